@@ -197,6 +197,7 @@ type ActiveMQArtemisSecurityStatus struct {
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:deprecatedversion:warning="broker.amq.io/v1alpha1 ActiveMQArtemisSecurity is deprecated, use broker.amq.io/v1beta1"
 
 // ActiveMQArtemisSecurity is the Schema for the activemqartemissecurities API
 type ActiveMQArtemisSecurity struct {
@@ -20,6 +20,7 @@ import (
 	"github.com/RHsyseng/operator-utils/pkg/olm"
 	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -37,6 +38,12 @@ type ActiveMQArtemisSpec struct {
 	// Password for standard broker user. It is required for connecting to the broker and the web console. If left empty, it will be generated.
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Admin Password",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:password"}
 	AdminPassword string `json:"adminPassword,omitempty"`
+	// Role granted to the admin user in artemis-roles.properties, it is also the default web console role. If left empty, admin is used.
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Admin Role",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	AdminRole string `json:"adminRole,omitempty"`
+	// Roles allowed to login to the web console. If left empty, only the admin role is allowed.
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Hawtio Roles"
+	HawtioRoles []string `json:"hawtioRoles,omitempty"`
 	// Specifies the deployment plan
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Deployment Plan"
 	DeploymentPlan DeploymentPlanType `json:"deploymentPlan,omitempty"`
@@ -58,15 +65,620 @@ type ActiveMQArtemisSpec struct {
 	// Specifies the address configurations
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Address Configurations"
 	AddressSettings AddressSettingsType `json:"addressSettings,omitempty"`
-	// Optional list of key=value properties that are applied to the broker configuration bean.
+	// Optional list of key=value properties that are applied to the broker configuration bean. A broker-N. key prefix applies the property only to the broker pod with ordinal N.
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Broker Properties"
 	BrokerProperties []string `json:"brokerProperties,omitempty"`
+	// Optional lists of key=value properties that are applied to the broker configuration bean of a single broker pod
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Ordinal Broker Properties"
+	OrdinalBrokerProperties []OrdinalBrokerPropertiesType `json:"ordinalBrokerProperties,omitempty"`
+	// Reference to a ConfigMap key holding a full or partial broker.xml. Elements of its core section replace the generated elements of the same name, brokerProperties still take precedence.
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Broker Xml ConfigMap"
+	BrokerXmlConfigMap *corev1.ConfigMapKeySelector `json:"brokerXmlConfigMap,omitempty"`
 	// Optional list of environment variables to apply to the container(s), not exclusive
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Environment Variables"
 	Env []corev1.EnvVar `json:"env,omitempty"`
 	// The ingress domain to expose the application. By default, on Kubernetes it is apps.artemiscloud.io and on OpenShift it is the Ingress Controller domain.
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Ingress Domain",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
 	IngressDomain string `json:"ingressDomain,omitempty"`
+	// The cert-manager issuer used for every acceptor with sslEnabled that has neither its own issuer nor an sslSecret
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Certificate Issuer"
+	CertificateIssuer *CertificateIssuerType `json:"certificateIssuer,omitempty"`
+	// What to do when the content of an ssl secret changes, RollingRestart restarts one broker at a time, Reload has acceptors reload their keystores in place and None (the default) leaves brokers alone
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="TLS Renewal",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	//+kubebuilder:validation:Enum=RollingRestart;Reload;None
+	TLSRenewal string `json:"tlsRenewal,omitempty"`
+	// Mutual TLS for the traffic between the brokers of the cluster, the operator has cert-manager issue a certificate every broker presents and trusts
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Cluster TLS"
+	ClusterTLS *ClusterTLSType `json:"clusterTLS,omitempty"`
+	// Specifies the proxy the broker uses for outbound http and https connections
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Proxy Configuration"
+	Proxy *ProxyType `json:"proxy,omitempty"`
+	// Specifies the log4j2 logging configuration of the broker, level changes are picked up by running brokers
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Logging Configuration"
+	Logging *LoggingType `json:"logging,omitempty"`
+	// Address prefixes reserved for internal use, only the listed roles may send to, consume from or create addresses and queues that start with them
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Reserved Address Prefixes"
+	ReservedAddressPrefixes *ReservedAddressPrefixesType `json:"reservedAddressPrefixes,omitempty"`
+	// Denies the send permission on every security match of the brokers while consumers keep receiving, for example to drain the brokers before a planned migration. Reported by the ReadOnly condition
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Read Only",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	ReadOnly bool `json:"readOnly,omitempty"`
+	// Maintain a <cr name>-topology ConfigMap with a versioned document of the brokers, their readiness, roles and endpoints, for clients to watch instead of the pods and services
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Publish Topology",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	PublishTopology bool `json:"publishTopology,omitempty"`
+	// Hold the brokers back until an ActiveMQArtemisSecurity applies to them, so that acceptors never open without the security configuration. A deployed StatefulSet is left unchanged while no security CR applies. Reported by the SecurityApplied condition
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Wait For Security",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	WaitForSecurity bool `json:"waitForSecurity,omitempty"`
+	// Customizes the headless service of the brokers, the service their pods get their DNS names from
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Headless Service"
+	HeadlessService *HeadlessServiceType `json:"headlessService,omitempty"`
+	// Bounds the age and the number of messages kept on matching addresses, for queues that fill up once their consumers are gone while the producers keep writing. Set through the address settings of the match, which take precedence over addressSettings
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Retention Policies"
+	RetentionPolicies []RetentionPolicyType `json:"retentionPolicies,omitempty"`
+	// The IP families of the services the operator generates, IPv4 and IPv6 in order of preference. The first family is also the one the brokers bind to and resolve host names to, IPv6 on an IPv6-only cluster. Defaults to the family of the cluster
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="IP Families"
+	IPFamilies []corev1.IPFamily `json:"ipFamilies,omitempty"`
+	// The IP family policy of the services the operator generates, one of SingleStack, PreferDualStack or RequireDualStack. Defaults to SingleStack
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="IP Family Policy",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	IPFamilyPolicy *corev1.IPFamilyPolicyType `json:"ipFamilyPolicy,omitempty"`
+	// Rotates the credentials the brokers of the cluster connect to each other with on a schedule, a rotation can also be started with the broker.amq.io/rotateClusterCredentialsAt annotation
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Cluster Credential Rotation"
+	ClusterCredentialRotation *CredentialRotationType `json:"clusterCredentialRotation,omitempty"`
+	// Rotates the admin user and password the operator generated on a schedule, a rotation can also be started with the broker.amq.io/rotateAdminCredentialsAt annotation. Not for an adminUser or adminPassword of the CR
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Admin Credential Rotation"
+	AdminCredentialRotation *CredentialRotationType `json:"adminCredentialRotation,omitempty"`
+	// Runs the brokers inside an Istio service mesh, the generated service ports are named after their protocol and the sidecar is started before the broker
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Service Mesh"
+	ServiceMesh *ServiceMeshType `json:"serviceMesh,omitempty"`
+	// Redelivery and dead letter defaults of every address. An Address CR can override them for its own address, entries of brokerProperties take precedence
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Redelivery"
+	Redelivery *RedeliveryDefaultsType `json:"redelivery,omitempty"`
+	// Takes the admin and cluster credentials and keystore passwords from an external secret store instead of secrets the operator generates
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Credentials Source"
+	CredentialsSource *CredentialsSourceType `json:"credentialsSource,omitempty"`
+	// Scales the brokers with the depth of their queues, the operator generates a KEDA ScaledObject that reads the metrics plugin from Prometheus and changes the deploymentPlan size through the scale subresource
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Autoscaling"
+	Autoscaling *AutoscalingType `json:"autoscaling,omitempty"`
+	// Pairs the brokers as a primary and a backup that replicates or shares its journal, the primary of a pair has an even ordinal and its backup the next one
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="High Availability"
+	HA *HAType `json:"ha,omitempty"`
+	// Spreads the brokers over the zones of the cluster and gives the clients of each zone a service of the brokers in their zone
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Zone Awareness"
+	ZoneAwareness *ZoneAwarenessType `json:"zoneAwareness,omitempty"`
+	// Federates addresses and queues from the brokers of other clusters, every broker pod gets the same federations
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Federations"
+	Federations []FederationType `json:"federations,omitempty"`
+	// AMQP connections from every broker pod to brokers of another site, each connection mirrors the messages, acknowledgements and queues of the broker
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="AMQP Connections"
+	AMQPConnections []AMQPConnectionType `json:"amqpConnections,omitempty"`
+	// Tunes the cluster connection the brokers of a clustered deployment connect to each other with
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Cluster Connection"
+	ClusterConnection *ClusterConnectionType `json:"clusterConnection,omitempty"`
+	// Joins the brokers into a cluster with the brokers of deployments in other kubernetes clusters, over a cluster connection with static connectors to their exposed addresses
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Cluster Mesh"
+	ClusterMesh *ClusterMeshType `json:"clusterMesh,omitempty"`
+}
+
+type ClusterMeshType struct {
+	// The acceptor the brokers of the other deployments connect to, it must be exposed per broker with publishExternalConnectors so each broker can advertise its external address to them
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Acceptor",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Acceptor string `json:"acceptor"`
+	// The urls of the exposed brokers of the other deployments, for example tcp://ex-aao-mesh-0-svc-rte-east.apps.example.com:443?sslEnabled=true, one is enough for a broker to learn about the rest of the mesh
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Members"
+	Members []string `json:"members"`
+	// Name of a secret with the user and password keys of the cluster user, every deployment of the mesh needs a secret with the same user and password
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Credentials Secret",xDescriptors={"urn:alm:descriptor:io.kubernetes:Secret"}
+	CredentialsSecret string `json:"credentialsSecret"`
+	// Name of a secret with a client.ts truststore and its trustStorePassword the brokers trust the certificates of the other deployments with, every deployment of the mesh needs a secret with the same name and content
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Trust Secret",xDescriptors={"urn:alm:descriptor:io.kubernetes:Secret"}
+	TrustSecret string `json:"trustSecret,omitempty"`
+}
+
+type ClusterConnectionType struct {
+	// How messages are spread over the brokers of the cluster, OFF keeps them on the broker they are sent to, STRICT spreads them whether or not the other brokers have consumers, ON_DEMAND only to brokers with consumers, and OFF_WITH_REDISTRIBUTION only redistributes messages to a broker with consumers when the local queue has none. Default ON_DEMAND
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Message Load Balancing",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:select:OFF","urn:alm:descriptor:com.tectonic.ui:select:STRICT","urn:alm:descriptor:com.tectonic.ui:select:ON_DEMAND","urn:alm:descriptor:com.tectonic.ui:select:OFF_WITH_REDISTRIBUTION"}
+	//+kubebuilder:validation:Enum=OFF;STRICT;ON_DEMAND;OFF_WITH_REDISTRIBUTION
+	MessageLoadBalancing MessageLoadBalancingType `json:"messageLoadBalancing,omitempty"`
+	// The number of brokers a message may be forwarded over, 0 keeps messages on the broker they are sent to. Default 1
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Max Hops",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	//+kubebuilder:validation:Minimum=0
+	MaxHops *int32 `json:"maxHops,omitempty"`
+	// The number of attempts to reconnect to another broker after the connection is lost, -1 for no limit. Default -1
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Reconnect Attempts",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	//+kubebuilder:validation:Minimum=-1
+	ReconnectAttempts *int32 `json:"reconnectAttempts,omitempty"`
+	// The milliseconds a broker waits for another broker to answer a packet before it fails the call. Default 30000
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Call Timeout",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	//+kubebuilder:validation:Minimum=1
+	CallTimeout *int64 `json:"callTimeout,omitempty"`
+	// The size in bytes of the window of messages forwarded to another broker before it acknowledges them, -1 for no limit. Default -1
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Producer Window Size",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	//+kubebuilder:validation:Minimum=-1
+	ProducerWindowSize *int32 `json:"producerWindowSize,omitempty"`
+}
+
+type MessageLoadBalancingType string
+
+const (
+	MessageLoadBalancingOff                   MessageLoadBalancingType = "OFF"
+	MessageLoadBalancingStrict                MessageLoadBalancingType = "STRICT"
+	MessageLoadBalancingOnDemand              MessageLoadBalancingType = "ON_DEMAND"
+	MessageLoadBalancingOffWithRedistribution MessageLoadBalancingType = "OFF_WITH_REDISTRIBUTION"
+)
+
+type AMQPConnectionType struct {
+	// The name of the broker connection on the brokers
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	//+kubebuilder:validation:Pattern=`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`
+	Name string `json:"name"`
+	// The url of the target broker, for example tcp://dr-broker-amqp-{ordinal}-svc.dr.example.com:5672?sslEnabled=true, {ordinal} is replaced by the ordinal of the broker pod so each broker connects to its own target
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Connector Url",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	//+kubebuilder:validation:Pattern=`^[a-zA-Z][a-zA-Z0-9+.-]*://.+`
+	ConnectorUrl string `json:"connectorUrl"`
+	// Name of a secret in the namespace of the CR with the user and password keys of the user the brokers connect to the target as
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Credentials Secret",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
+	// The milliseconds between two attempts to connect to the target. Default 5000
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Retry Interval",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	//+kubebuilder:validation:Minimum=1
+	RetryInterval *int64 `json:"retryInterval,omitempty"`
+	// The number of attempts to reconnect to the target after the connection is lost, -1 for no limit. Default -1
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Reconnect Attempts",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	//+kubebuilder:validation:Minimum=-1
+	ReconnectAttempts *int32 `json:"reconnectAttempts,omitempty"`
+	// What the connection mirrors to the target
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Mirror"
+	Mirror AMQPMirrorType `json:"mirror"`
+}
+
+type AMQPMirrorType struct {
+	// Whether acknowledgements are mirrored, so a message consumed here is removed on the target. Default true
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Message Acknowledgements",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	MessageAcknowledgements *bool `json:"messageAcknowledgements,omitempty"`
+	// Whether addresses and queues created here are created on the target. Default true
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Queue Creation",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	QueueCreation *bool `json:"queueCreation,omitempty"`
+	// Whether addresses and queues deleted here are deleted on the target. Default true
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Queue Removal",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	QueueRemoval *bool `json:"queueRemoval,omitempty"`
+	// Whether the messages waiting to be mirrored are kept in a durable queue, so they survive a restart of the broker. Default true
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Durable",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	Durable *bool `json:"durable,omitempty"`
+	// Whether a producer waits until the target has stored its message, instead of until the broker has. Default false
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Sync",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	Sync *bool `json:"sync,omitempty"`
+	// The addresses that are mirrored, an address prefix or a prefix starting with ! for addresses that are not, defaults to all addresses
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Address Filter"
+	AddressFilter []string `json:"addressFilter,omitempty"`
+}
+
+type FederationType struct {
+	// The name of the federation on the brokers
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	//+kubebuilder:validation:Pattern=`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`
+	Name string `json:"name"`
+	// The brokers the messages are federated from
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Upstreams"
+	Upstreams []FederationUpstreamType `json:"upstreams"`
+	// The addresses whose messages are federated, every consumer of a matching address gets the messages sent to the address on the upstreams
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Address Policies"
+	AddressPolicies []FederationAddressPolicyType `json:"addressPolicies,omitempty"`
+	// The queues whose messages are federated, a matching queue pulls messages from the upstreams while it has consumers and no local messages
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Queue Policies"
+	QueuePolicies []FederationQueuePolicyType `json:"queuePolicies,omitempty"`
+}
+
+type FederationUpstreamType struct {
+	// The name of the upstream on the brokers
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	//+kubebuilder:validation:Pattern=`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`
+	Name string `json:"name"`
+	// The urls of the connectors to the upstream brokers, for example tcp://cloud-broker.example.com:61616?sslEnabled=true, the brokers connect to the first one that is reachable
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Connector Urls"
+	ConnectorUrls []string `json:"connectorUrls"`
+	// Name of a secret in the namespace of the CR with the user and password keys of the user the brokers connect to the upstream as
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Credentials Secret",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
+	// The names of the address and queue policies of the federation the upstream applies, defaults to all of them
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Policies"
+	Policies []string `json:"policies,omitempty"`
+	// The milliseconds between two attempts to connect to the upstream. Default 500
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Retry Interval",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	//+kubebuilder:validation:Minimum=1
+	RetryInterval *int64 `json:"retryInterval,omitempty"`
+	// The number of attempts to reconnect to the upstream after the connection is lost, -1 for no limit. Default -1
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Reconnect Attempts",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	//+kubebuilder:validation:Minimum=-1
+	ReconnectAttempts *int32 `json:"reconnectAttempts,omitempty"`
+	// The milliseconds the brokers wait before they try again after the upstream refused them, Default 30000
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Circuit Breaker Timeout",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	//+kubebuilder:validation:Minimum=0
+	CircuitBreakerTimeout *int64 `json:"circuitBreakerTimeout,omitempty"`
+}
+
+type FederationAddressPolicyType struct {
+	// The name of the policy on the brokers
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	//+kubebuilder:validation:Pattern=`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`
+	Name string `json:"name"`
+	// The address matches the policy federates, for example orders.#
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Include"
+	Include []string `json:"include,omitempty"`
+	// The address matches the policy leaves out of the included ones
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Exclude"
+	Exclude []string `json:"exclude,omitempty"`
+	// The number of brokers a message may be federated over, 0 for no limit. Default 0
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Max Hops",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	//+kubebuilder:validation:Minimum=0
+	MaxHops *int32 `json:"maxHops,omitempty"`
+	// Whether the queue created on the upstream is deleted once the brokers disconnect from it. Default false
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Auto Delete",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	AutoDelete *bool `json:"autoDelete,omitempty"`
+	// Whether the bindings of diverts are federated like queue bindings. Default false
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enable Divert Bindings",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	EnableDivertBindings *bool `json:"enableDivertBindings,omitempty"`
+}
+
+type FederationQueuePolicyType struct {
+	// The name of the policy on the brokers
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	//+kubebuilder:validation:Pattern=`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`
+	Name string `json:"name"`
+	// The queues the policy federates
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Include"
+	Include []FederationQueueMatchType `json:"include,omitempty"`
+	// The queues the policy leaves out of the included ones
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Exclude"
+	Exclude []FederationQueueMatchType `json:"exclude,omitempty"`
+	// Whether the queues also pull from consumers that are themselves federated, set it when the upstream federates back. Default false
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Include Federated",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	IncludeFederated *bool `json:"includeFederated,omitempty"`
+	// Added to the priority of the federated consumer on the upstream so local consumers are served first. Default -1
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Priority Adjustment",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	PriorityAdjustment *int32 `json:"priorityAdjustment,omitempty"`
+}
+
+type FederationQueueMatchType struct {
+	// The address match of the queues, defaults to #
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Address Match",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	AddressMatch string `json:"addressMatch,omitempty"`
+	// The queue match, for example orders.#
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Queue Match",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	QueueMatch string `json:"queueMatch"`
+}
+
+type ZoneAwarenessType struct {
+	// Whether the brokers are spread over the zones and labeled with their zone
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enabled",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	Enabled bool `json:"enabled,omitempty"`
+	// The node label that holds the zone, defaults to topology.kubernetes.io/zone
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Topology Key",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	TopologyKey string `json:"topologyKey,omitempty"`
+	// What happens to a broker that would put one more broker in a zone than in another, DoNotSchedule keeps it pending and ScheduleAnyway places it in the zone with the fewest brokers that has room, defaults to ScheduleAnyway
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="When Unsatisfiable",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:select:DoNotSchedule","urn:alm:descriptor:com.tectonic.ui:select:ScheduleAnyway"}
+	//+kubebuilder:validation:Enum=DoNotSchedule;ScheduleAnyway
+	WhenUnsatisfiable corev1.UnsatisfiableConstraintAction `json:"whenUnsatisfiable,omitempty"`
+}
+
+type HAType struct {
+	// Whether the brokers are deployed as primary and backup pairs, the deploymentPlan size must be even
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enabled",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	Enabled bool `json:"enabled,omitempty"`
+	// How the backup of a pair gets the journal of its primary, Replication copies it over the network and SharedStore mounts the same ReadWriteMany volume in both brokers, defaults to Replication
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Policy",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:select:Replication","urn:alm:descriptor:com.tectonic.ui:select:SharedStore"}
+	Policy HAPolicy `json:"policy,omitempty"`
+	// The ReadWriteMany volume of the SharedStore policy
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Shared Store"
+	SharedStore *HASharedStoreType `json:"sharedStore,omitempty"`
+	// Whether a broker that loses its replication partner asks the other primaries of the cluster for a quorum before it becomes or stays live, defaults to false
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Vote On Replication Failure",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	VoteOnReplicationFailure *bool `json:"voteOnReplicationFailure,omitempty"`
+	// The number of votes a quorum needs, defaults to -1 which is a majority of the primaries of the cluster
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Quorum Size",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	QuorumSize *int32 `json:"quorumSize,omitempty"`
+	// The number of times a broker asks for a quorum before it gives up, defaults to 12
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Vote Retries",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	//+kubebuilder:validation:Minimum=0
+	VoteRetries *int32 `json:"voteRetries,omitempty"`
+	// The milliseconds between the times a broker asks for a quorum, defaults to 2000
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Vote Retry Wait",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	//+kubebuilder:validation:Minimum=0
+	VoteRetryWait *int64 `json:"voteRetryWait,omitempty"`
+	// Coordinates the primary and backup of each pair through ZooKeeper instead of a vote of the primaries of the cluster, so a single pair can't split brain
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="ZooKeeper"
+	ZooKeeper *HAZooKeeperType `json:"zooKeeper,omitempty"`
+}
+
+type HAZooKeeperType struct {
+	// The connect string of the ZooKeeper ensemble, for example zk-0.zk-hs:2181,zk-1.zk-hs:2181,zk-2.zk-hs:2181
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Connect String",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	ConnectString string `json:"connectString,omitempty"`
+	// The key of a secret in the namespace of the CR that holds the connect string
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Connect String Secret"
+	ConnectStringSecret *corev1.SecretKeySelector `json:"connectStringSecret,omitempty"`
+	// The key of a config map in the namespace of the CR that holds the connect string
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Connect String Config Map"
+	ConnectStringConfigMap *corev1.ConfigMapKeySelector `json:"connectStringConfigMap,omitempty"`
+	// An existing ZooKeeper service the brokers connect to, the client resolves every address of a headless service
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Service"
+	Service *HAZooKeeperServiceType `json:"service,omitempty"`
+	// The ZooKeeper node the coordination of the pairs is kept under, defaults to <namespace>-<name> of the CR so that several CRs can share an ensemble
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Namespace",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	//+kubebuilder:validation:Pattern=`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`
+	Namespace string `json:"namespace,omitempty"`
+	// The milliseconds after which ZooKeeper expires the session of a broker it lost contact with, the backup can only take over after that. Default 18000
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Session Timeout",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	//+kubebuilder:validation:Minimum=1
+	SessionTimeout *int32 `json:"sessionTimeout,omitempty"`
+	// The milliseconds a broker waits to connect to ZooKeeper. Default 8000
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Connection Timeout",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	//+kubebuilder:validation:Minimum=1
+	ConnectionTimeout *int32 `json:"connectionTimeout,omitempty"`
+}
+
+type HAZooKeeperServiceType struct {
+	// The name of the service
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Name string `json:"name"`
+	// The namespace of the service, defaults to the namespace of the CR
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Namespace",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Namespace string `json:"namespace,omitempty"`
+	// The client port of the service. Default 2181
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Port",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	//+kubebuilder:validation:Minimum=1
+	//+kubebuilder:validation:Maximum=65535
+	Port *int32 `json:"port,omitempty"`
+}
+
+//+kubebuilder:validation:Enum=Replication;SharedStore
+type HAPolicy string
+
+const (
+	HAPolicyReplication HAPolicy = "Replication"
+	HAPolicySharedStore HAPolicy = "SharedStore"
+)
+
+type HASharedStoreType struct {
+	// The storage class of the ReadWriteMany claim the operator creates, defaults to the storage class of the deploymentPlan
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Storage Class Name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	StorageClassName string `json:"storageClassName,omitempty"`
+	// The size of the claim, it holds the journals of all the pairs, defaults to the storage size of the deploymentPlan times the number of pairs
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Size",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Size string `json:"size,omitempty"`
+	// An existing ReadWriteMany claim to use rather than the one the operator creates
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Claim Name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	ClaimName string `json:"claimName,omitempty"`
+	// Whether the backup takes over when its primary shuts down normally rather than fails, defaults to true so that a restart of the primary doesn't interrupt the clients
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Failover On Shutdown",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	FailoverOnShutdown *bool `json:"failoverOnShutdown,omitempty"`
+	// The milliseconds a broker waits for the file lock of the journal before it gives up, defaults to waiting without a limit
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Lock Acquisition Timeout",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	//+kubebuilder:validation:Minimum=0
+	LockAcquisitionTimeout *int64 `json:"lockAcquisitionTimeout,omitempty"`
+}
+
+type AutoscalingType struct {
+	// Whether the operator generates the KEDA ScaledObject, it is removed when disabled
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enabled",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	Enabled bool `json:"enabled,omitempty"`
+	// The least number of brokers, defaults to 1
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Min Replicas",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:podCount"}
+	//+kubebuilder:validation:Minimum=1
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+	// The most number of brokers
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Max Replicas",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:podCount"}
+	//+kubebuilder:validation:Minimum=1
+	MaxReplicas int32 `json:"maxReplicas,omitempty"`
+	// The address of the Prometheus server that scrapes the metrics plugin of the brokers, for example http://prometheus-operated.monitoring:9090
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Prometheus Server Address",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	ServerAddress string `json:"serverAddress,omitempty"`
+	// The queues whose depth drives the number of brokers
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Queues"
+	Queues []AutoscalingQueueType `json:"queues,omitempty"`
+	// How often KEDA reads the depth of the queues in seconds, defaults to 30
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Polling Interval",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	//+kubebuilder:validation:Minimum=1
+	PollingInterval *int32 `json:"pollingInterval,omitempty"`
+	// How long a scale in waits for the depth to stay low, and the least time between removing two brokers, in seconds, defaults to 300. It gives the migration of the messages of a removed broker time to finish
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Scale In Period",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	//+kubebuilder:validation:Minimum=0
+	ScaleInPeriodSeconds *int32 `json:"scaleInPeriodSeconds,omitempty"`
+}
+
+type AutoscalingQueueType struct {
+	// The name of the queue
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Name string `json:"name"`
+	// How many messages the queue may hold per broker, a deeper queue adds brokers
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Target Messages Per Broker",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	//+kubebuilder:validation:Minimum=1
+	TargetMessagesPerBroker int64 `json:"targetMessagesPerBroker"`
+}
+
+type RedeliveryPolicyType struct {
+	// How many times a message is delivered before it is sent to the dead letter address, -1 for no limit. The broker defaults to 10
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Max Delivery Attempts",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	//+kubebuilder:validation:Minimum=-1
+	MaxDeliveryAttempts *int32 `json:"maxDeliveryAttempts,omitempty"`
+	// The time to wait before a message is redelivered, for example 5s. The broker defaults to redelivering right away
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Redelivery Delay",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	RedeliveryDelay string `json:"redeliveryDelay,omitempty"`
+	// The factor the redelivery delay grows by with each attempt, between 1 and 10, for example 2.0
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Redelivery Multiplier",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	//+kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?$`
+	RedeliveryMultiplier string `json:"redeliveryMultiplier,omitempty"`
+	// The longest the redelivery delay grows to, for example 5m. The broker defaults to ten times the redelivery delay
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Max Redelivery Delay",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	MaxRedeliveryDelay string `json:"maxRedeliveryDelay,omitempty"`
+	// The address messages are sent to once the delivery attempts are used up, they are dropped without one
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Dead Letter Address",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	DeadLetterAddress string `json:"deadLetterAddress,omitempty"`
+	// Whether the dead letter address, and a queue on it for each address, are created as messages are sent to them
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Auto Create Dead Letter Resources",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	AutoCreateDeadLetterResources *bool `json:"autoCreateDeadLetterResources,omitempty"`
+}
+
+type RedeliveryDefaultsType struct {
+	RedeliveryPolicyType `json:",inline"`
+	// Whether the delivery count of a message is persisted before it is delivered, so that a message that crashes the broker still reaches the dead letter address. Costs a journal write per delivery
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Persist Delivery Count",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	PersistDeliveryCount *bool `json:"persistDeliveryCount,omitempty"`
+}
+
+type ServiceMeshType struct {
+	// Whether the brokers run inside the mesh. The ports of the generated services get the tcp-, tls-, http- or https- prefix the mesh detects their protocol by and the broker pods are annotated for sidecar injection
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enabled",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	Enabled bool `json:"enabled,omitempty"`
+	// Whether the broker container waits for the sidecar proxy to be ready, defaults to true so that the brokers don't connect to each other before the proxy can carry the traffic
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Hold Application Until Proxy Starts",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	HoldApplicationUntilProxyStarts *bool `json:"holdApplicationUntilProxyStarts,omitempty"`
+}
+
+type CredentialRotationType struct {
+	// The time between two rotations, for example 720h. A rotation is due once this much time has passed since the last one completed, or since the CR was created
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Interval",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Interval string `json:"interval,omitempty"`
+}
+
+type CredentialsSourceType struct {
+	// The Vault agent injector writes the items into the pods, from the keys of a Vault secret
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Vault"
+	Vault *VaultCredentialsSourceType `json:"vault,omitempty"`
+	// The secrets store CSI driver mounts the items into the pods, they are the objects of a SecretProviderClass
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="CSI"
+	CSI *CSICredentialsSourceType `json:"csi,omitempty"`
+	// Takes the admin user and password from the adminUser and adminPassword items. The operator manages the brokers with the user of console.jolokia.credentialsSecret then
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Admin",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	Admin bool `json:"admin,omitempty"`
+	// Takes the cluster user and password from the clusterUser and clusterPassword items
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Cluster",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	Cluster bool `json:"cluster,omitempty"`
+	// The acceptors and connectors, or console, whose keystore and truststore passwords are taken from the <name>-keyStorePassword and <name>-trustStorePassword items
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Key Store Passwords"
+	KeyStorePasswords []string `json:"keyStorePasswords,omitempty"`
+	// A secret holding a copy of the items, such as a secretObjects entry of the SecretProviderClass or the destination of a VaultStaticSecret of the Vault Secrets Operator. The operator watches it to find out when the credentials rotate
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Synced Secret",xDescriptors={"urn:alm:descriptor:io.kubernetes:Secret"}
+	SyncedSecret string `json:"syncedSecret,omitempty"`
+	// What to do when the synced secret changes, RollingRestart restarts one broker at a time so that it reads the rotated credentials and None leaves brokers alone. Defaults to RollingRestart with a synced secret and to None without one
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Rotation",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	//+kubebuilder:validation:Enum=RollingRestart;None
+	Rotation string `json:"rotation,omitempty"`
+}
+
+type VaultCredentialsSourceType struct {
+	// The Vault role of the kubernetes auth method the pods log in with through their service account
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Role",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Role string `json:"role,omitempty"`
+	// The path of the Vault secret whose keys are the items, for example secret/data/brokers/ex-aao
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Path",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Path string `json:"path,omitempty"`
+}
+
+type CSICredentialsSourceType struct {
+	// The SecretProviderClass in the namespace of the broker, each item is an object of it mounted under its name or alias
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Secret Provider Class",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	SecretProviderClass string `json:"secretProviderClass,omitempty"`
+}
+
+type RetentionPolicyType struct {
+	// The address match the policy applies to, wildcards included, for example orders.#
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Match",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Match string `json:"match,omitempty"`
+	// The longest time a message is kept, for example 24h. Older messages are expired by the periodic expiry scan of the broker and go to the expiry address of the match, if there is one
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Max Age",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	MaxAge string `json:"maxAge,omitempty"`
+	// The most messages kept on each matching address, action decides what happens to the messages sent beyond it
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Max Messages",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	//+kubebuilder:validation:Minimum=1
+	MaxMessages *int64 `json:"maxMessages,omitempty"`
+	// What happens to messages sent to a full address, drop discards them silently, fail rejects them and page keeps them on disk. Defaults to drop
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Action",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Action RetentionAction `json:"action,omitempty"`
+}
+
+type HeadlessServiceType struct {
+	// Whether the DNS names of brokers that are not ready are published, defaults to true so that starting brokers find each other
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Publish Not Ready Addresses",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	PublishNotReadyAddresses *bool `json:"publishNotReadyAddresses,omitempty"`
+	// Ports added to the ones the operator publishes, for example a metrics or JGroups port
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Extra Ports"
+	Ports []corev1.ServicePort `json:"ports,omitempty"`
+	// Annotations of the headless service, for example for service discovery or scraping
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Annotations"
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type ReservedAddressPrefixesType struct {
+	// The reserved prefixes, each ending with the address delimiter, for example sys.
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Prefixes"
+	Prefixes []string `json:"prefixes,omitempty"`
+	// The roles granted access to the reserved addresses, defaults to the admin role
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Roles"
+	Roles []string `json:"roles,omitempty"`
+}
+
+type ProxyType struct {
+	// The proxy url for http connections, for example http://proxy.example.com:3128
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="HTTP Proxy",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	HttpProxy string `json:"httpProxy,omitempty"`
+	// The proxy url for https connections
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="HTTPS Proxy",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	HttpsProxy string `json:"httpsProxy,omitempty"`
+	// Comma separated list of hosts or domains that are connected to directly, a leading . matches all sub domains
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="No Proxy",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	NoProxy string `json:"noProxy,omitempty"`
+}
+
+type MetricsType struct {
+	// The name of the headless service port for scraping the metrics, defaults to metrics
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Port Name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	PortName string `json:"portName,omitempty"`
+	// The headless service port number for the metrics, defaults to 8162. It targets the console port of the broker
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Port",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	//+kubebuilder:validation:Minimum=1
+	//+kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port,omitempty"`
+	// Whether to export JVM memory metrics, enabled by the broker by default
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="JVM Memory",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	JvmMemory *bool `json:"jvmMemory,omitempty"`
+	// Whether to export JVM garbage collection metrics
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="JVM GC",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	JvmGc *bool `json:"jvmGc,omitempty"`
+	// Whether to export JVM thread metrics
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="JVM Threads",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	JvmThreads *bool `json:"jvmThreads,omitempty"`
+}
+
+type JVMType struct {
+	// The initial heap size, passed as -Xms
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Initial Heap Size",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	InitialHeapSize *resource.Quantity `json:"initialHeapSize,omitempty"`
+	// The maximum heap size, passed as -Xmx. It must be below the memory limit of the container
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Max Heap Size",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	MaxHeapSize *resource.Quantity `json:"maxHeapSize,omitempty"`
+	// The maximum metaspace size, passed as -XX:MaxMetaspaceSize
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Max Metaspace Size",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	MaxMetaspaceSize *resource.Quantity `json:"maxMetaspaceSize,omitempty"`
+	// The garbage collector to use, one of G1, Parallel, Serial, Shenandoah or Z
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Garbage Collector",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	GarbageCollector string `json:"garbageCollector,omitempty"`
+	// Additional JVM flags, each entry is a single flag such as -XX:+HeapDumpOnOutOfMemoryError
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Extra Args"
+	ExtraArgs []string `json:"extraArgs,omitempty"`
+}
+
+type LoggingType struct {
+	// The level of the root logger, for example INFO
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Root Level",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	RootLevel string `json:"rootLevel,omitempty"`
+	// Logger levels keyed by logger category, for example org.apache.activemq.artemis.core.server: DEBUG
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Levels"
+	Levels map[string]string `json:"levels,omitempty"`
+	// The layout pattern of the console appender
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Pattern",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Pattern string `json:"pattern,omitempty"`
+	// Reference to a ConfigMap key holding a log4j2 properties file used as the base configuration
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="ConfigMap"
+	ConfigMap *corev1.ConfigMapKeySelector `json:"configMap,omitempty"`
 }
 
 type AddressSettingsType struct {
@@ -294,6 +906,7 @@ type DeploymentPlanType struct {
 	InitImage string `json:"initImage,omitempty"`
 	// The number of broker pods to deploy
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Size",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:podCount"}
+	//+kubebuilder:default=1
 	Size *int32 `json:"size,omitempty"`
 	// If true require user password login credentials for broker protocol ports
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Require Login",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
@@ -301,18 +914,30 @@ type DeploymentPlanType struct {
 	// If true use persistent volume via persistent volume claim for journal storage
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Persistence Enabled",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
 	PersistenceEnabled bool `json:"persistenceEnabled,omitempty"`
+	// Run the brokers without persistence for throughput, messages are only held in memory and are lost with the pod. An emptyDir holds paged and large messages in place of a persistent volume claim and the default probes are relaxed
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Ephemeral"
+	Ephemeral *EphemeralType `json:"ephemeral,omitempty"`
 	// If aio use ASYNCIO, if nio use NIO for journal IO
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Journal Type",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
 	JournalType string `json:"journalType,omitempty"`
 	//If true migrate messages on scaledown
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Message Migration",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
 	MessageMigration *bool `json:"messageMigration,omitempty"`
+	// Overrides of the drainer pod that migrates the messages of a removed broker, for clusters whose admission policies reject the default one
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Drainer"
+	Drainer *DrainerType `json:"drainer,omitempty"`
 	// Specifies the minimum/maximum amount of compute resources required/allowed
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Resource Requirements",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:resourceRequirements"}
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+	// Specifies the JVM heap, metaspace and garbage collector settings of the broker
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="JVM Configuration"
+	JVM *JVMType `json:"jvm,omitempty"`
 	// Specifies the storage configurations
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Storage Configurations"
 	Storage StorageType `json:"storage,omitempty"`
+	// Specifies the persistence store configuration
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Persistence Configurations"
+	Persistence PersistenceType `json:"persistence,omitempty"`
 	// If true enable the Jolokia JVM Agent
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Jolokia Agent Enabled",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
 	JolokiaAgentEnabled bool `json:"jolokiaAgentEnabled,omitempty"`
@@ -337,6 +962,9 @@ type DeploymentPlanType struct {
 	// Whether or not to install the artemis metrics plugin
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enable Metrics Plugin",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
 	EnableMetricsPlugin *bool `json:"enableMetricsPlugin,omitempty"`
+	// Specifies the metrics plugin configuration, setting it enables the plugin
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Metrics Configuration"
+	Metrics *MetricsType `json:"metrics,omitempty"`
 	// Specifies the tolerations
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Tolerations"
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
@@ -349,15 +977,53 @@ type DeploymentPlanType struct {
 	// Specifies affinity configuration
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Affinity Configurations"
 	Affinity AffinityConfig `json:"affinity,omitempty"`
+	// Keeps the brokers apart, required puts every broker on its own node, preferred does so when the nodes allow it and zone puts every broker on its own node and spreads them over the zones when it can. The terms are added to the ones of affinity, which must not contradict them
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Anti Affinity Preset",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	AntiAffinityPreset AntiAffinityPreset `json:"antiAffinityPreset,omitempty"`
 	// Specifies the pod security context
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Pod Security Context"
 	PodSecurityContext *corev1.PodSecurityContext `json:"podSecurityContext,omitempty"`
 	// Custom annotations to be added to broker pod
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Annotations"
 	Annotations map[string]string `json:"annotations,omitempty"`
+	// Names of additional Multus networks to attach the broker pods to, a name can be qualified with its namespace as namespace/name
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Additional Networks"
+	AdditionalNetworks []string `json:"additionalNetworks,omitempty"`
 	// Specifies the pod disruption budget
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Pod Disruption Budget"
 	PodDisruptionBudget *policyv1.PodDisruptionBudgetSpec `json:"podDisruptionBudget,omitempty"`
+	// Specifies low priority placeholder pods that reserve capacity for broker pods, brokers preempt them so that the cluster autoscaler adds nodes before a scale up is blocked
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Capacity Placeholders"
+	CapacityPlaceholders *CapacityPlaceholdersType `json:"capacityPlaceholders,omitempty"`
+	// Samples the disk and address memory usage of each broker and sets the CapacityWarning condition when, at the current rate, the disk is predicted to fill or addresses to start paging within the horizon
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Capacity Forecast"
+	CapacityForecast *CapacityForecastType `json:"capacityForecast,omitempty"`
+	// What to do with changes the StatefulSet can't take in place, such as toggling persistence or changing the storage class. Block, the default, holds the StatefulSet as deployed and reports the change in the Recreated condition, Recreate deletes and recreates the StatefulSet keeping the broker data
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Immutable Fields Policy",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	//+kubebuilder:validation:Enum=Block;Recreate
+	ImmutableFieldsPolicy string `json:"immutableFieldsPolicy,omitempty"`
+}
+
+type CapacityPlaceholdersType struct {
+	// The number of placeholder pods, each requests the resources of a broker pod
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Replicas",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:podCount"}
+	//+kubebuilder:validation:Minimum=0
+	Replicas int32 `json:"replicas,omitempty"`
+	// The priority class of the placeholder pods, it must have a lower priority than the broker pods
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Priority Class Name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// The image of the placeholder pods, defaults to registry.k8s.io/pause:3.9
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Image",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Image string `json:"image,omitempty"`
+}
+
+type CapacityForecastType struct {
+	// How far ahead, in minutes, exhaustion is warned about, defaults to 360
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Horizon Minutes",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	HorizonMinutes *int32 `json:"horizonMinutes,omitempty"`
+	// How many minutes of samples the trend is computed over, defaults to 60
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Window Minutes",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	WindowMinutes *int32 `json:"windowMinutes,omitempty"`
 }
 
 // Affinity is a group of affinity scheduling rules.
@@ -401,6 +1067,161 @@ type StorageType struct {
 	// The storageClassName to be used in PVC
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Storage Class Name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
 	StorageClassName string `json:"storageClassName,omitempty"`
+	// The VolumeSnapshotClass used to carry the journal over when a recreate replaces the persistent volume claims, required to change the storage class or size with the Recreate policy
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Snapshot Class Name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	SnapshotClassName string `json:"snapshotClassName,omitempty"`
+}
+
+type PersistenceType struct {
+	// Specifies a database store, when set the journal is kept in the database rather than on a persistent volume and no persistent volume claim is created
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="JDBC Persistence"
+	JDBC *JDBCPersistenceType `json:"jdbc,omitempty"`
+}
+
+type OrdinalBrokerPropertiesType struct {
+	// The ordinal of the broker pod the properties apply to
+	//+kubebuilder:validation:Minimum=0
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Ordinal",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	Ordinal int32 `json:"ordinal"`
+	// The key=value properties of the broker pod
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Broker Properties"
+	BrokerProperties []string `json:"brokerProperties"`
+}
+
+type JDBCPersistenceType struct {
+	// The fully qualified class name of the JDBC driver
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Driver Class Name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	DriverClassName string `json:"driverClassName"`
+	// Image that contains the JDBC driver jars, they are copied by an init container into a directory that is added to the broker classpath
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Driver Image",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	DriverImage string `json:"driverImage,omitempty"`
+	// The directory in the driver image that holds the driver jars, default is /opt/jdbc
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Driver Path",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	DriverPath string `json:"driverPath,omitempty"`
+	// Reference to the secret key that holds the JDBC connection url
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Connection Url Secret"
+	ConnectionUrlSecret corev1.SecretKeySelector `json:"connectionUrlSecret"`
+	// Prefix applied to the names of the tables used by the broker
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Table Prefix",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	TablePrefix string `json:"tablePrefix,omitempty"`
+	// Properties of the pooled data source, for example maxTotal or minIdle
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Data Source Properties"
+	DataSourceProperties map[string]string `json:"dataSourceProperties,omitempty"`
+}
+
+type EphemeralType struct {
+	// The size limit of the emptyDir volume, the pod is evicted when it is exceeded. Unlimited when not set
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Size Limit",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	//+kubebuilder:validation:Pattern=`^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$`
+	SizeLimit string `json:"sizeLimit,omitempty"`
+	// Back the emptyDir volume with memory, its content counts against the memory limit of the broker container
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="In Memory",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	InMemory bool `json:"inMemory,omitempty"`
+}
+
+type DrainerType struct {
+	// The image of the drainer, defaults to the image of the broker. It needs the drain script of the broker image
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Image",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Image string `json:"image,omitempty"`
+	// The compute resources of the drainer container, defaults to the resources of the broker
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Resources",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:resourceRequirements"}
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// The security context of the drainer container
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Security Context"
+	SecurityContext *corev1.SecurityContext `json:"securityContext,omitempty"`
+	// The security context of the drainer pod
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Pod Security Context"
+	PodSecurityContext *corev1.PodSecurityContext `json:"podSecurityContext,omitempty"`
+	// The tolerations of the drainer pod, default to the tolerations of the broker pods
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Tolerations"
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// An existing service account the drainer pod runs as, it needs to list pods and get endpoints and namespaces. By default the drainer runs as the service account of the operator or as one the operator creates in the namespace of the broker
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Service Account Name",xDescriptors={"urn:alm:descriptor:io.kubernetes:ServiceAccount"}
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+}
+
+type ExposeMode string
+
+const (
+	ExposeModeRoute        ExposeMode = "route"
+	ExposeModeIngress      ExposeMode = "ingress"
+	ExposeModeLoadBalancer ExposeMode = "loadBalancer"
+	ExposeModeNodePort     ExposeMode = "nodePort"
+	// a route or an ingress per pod passing TLS through, told apart by the server name
+	ExposeModeSNI ExposeMode = "sni"
+)
+
+type ExternalDNSType struct {
+	// The host name of the record, {ordinal} is replaced by the ordinal of the broker pod and is required when each broker is exposed on its own
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Hostname",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Hostname string `json:"hostname,omitempty"`
+	// The TTL of the record in seconds, the external-dns default when not set
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="TTL",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	TTL int32 `json:"ttl,omitempty"`
+	// Further external-dns annotations, for example external-dns.alpha.kubernetes.io/target, {ordinal} in a value is replaced like in hostname
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Annotations"
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+//+kubebuilder:validation:Enum=required;preferred;zone
+type AntiAffinityPreset string
+
+const (
+	AntiAffinityPresetRequired  AntiAffinityPreset = "required"
+	AntiAffinityPresetPreferred AntiAffinityPreset = "preferred"
+	AntiAffinityPresetZone      AntiAffinityPreset = "zone"
+)
+
+//+kubebuilder:validation:Enum=drop;fail;page
+type RetentionAction string
+
+const (
+	RetentionActionDrop RetentionAction = "drop"
+	RetentionActionFail RetentionAction = "fail"
+	RetentionActionPage RetentionAction = "page"
+)
+
+type AcceptorPreset string
+
+const (
+	AcceptorPresetAMQP     AcceptorPreset = "amqp"
+	AcceptorPresetMQTT     AcceptorPreset = "mqtt"
+	AcceptorPresetSTOMP    AcceptorPreset = "stomp"
+	AcceptorPresetOpenWire AcceptorPreset = "openwire"
+	AcceptorPresetCore     AcceptorPreset = "core"
+	AcceptorPresetAll      AcceptorPreset = "all"
+)
+
+type ServiceSettingsType struct {
+	// ClientIP sends the connections of a client to the same pod, None by default
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Session Affinity",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	SessionAffinity corev1.ServiceAffinity `json:"sessionAffinity,omitempty"`
+	// Local only sends traffic from outside the cluster to brokers on the node it arrives at, which preserves the client source address. Only applies to the loadBalancer and nodePort expose modes, Cluster by default
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="External Traffic Policy",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	ExternalTrafficPolicy corev1.ServiceExternalTrafficPolicyType `json:"externalTrafficPolicy,omitempty"`
+	// Local only sends traffic from inside the cluster to brokers on the node of the client, Cluster by default
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Internal Traffic Policy",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	InternalTrafficPolicy *corev1.ServiceInternalTrafficPolicyType `json:"internalTrafficPolicy,omitempty"`
+}
+
+type RouteTermination string
+
+const (
+	RouteTerminationPassthrough RouteTermination = "passthrough"
+	RouteTerminationEdge        RouteTermination = "edge"
+	RouteTerminationReencrypt   RouteTermination = "reencrypt"
+)
+
+type RouteTLSType struct {
+	// Where the route terminates TLS, passthrough, edge or reencrypt. Passthrough and reencrypt need sslEnabled, edge sends plain traffic on to the pod. The router only handles HTTP with edge and reencrypt, so those suit the console and websocket acceptors
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Termination",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Termination RouteTermination `json:"termination,omitempty"`
+	// A Secret with the tls.crt, tls.key and optionally ca.crt the router serves with edge and reencrypt. The default certificate of the router, often a wildcard, is served when not set
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Certificate Secret",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	CertificateSecret string `json:"certificateSecret,omitempty"`
+	// With reencrypt, a Secret with the ca.crt the router verifies the broker certificate against. The service CA is used when not set
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Destination CA Secret",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	DestinationCASecret string `json:"destinationCASecret,omitempty"`
 }
 
 type AcceptorType struct {
@@ -413,6 +1234,9 @@ type AcceptorType struct {
 	// The protocols to enable for this acceptor
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Protocols",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
 	Protocols string `json:"protocols,omitempty"`
+	// Start from the usual settings of a protocol, one of amqp, mqtt, stomp, openwire, core or all. The preset fills in the protocols, the port and the protocol parameters, anything set on the acceptor takes precedence
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Preset",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Preset AcceptorPreset `json:"preset,omitempty"`
 	// Whether or not to enable SSL on this port
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="SSL Enabled",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
 	SSLEnabled bool `json:"sslEnabled,omitempty"`
@@ -443,6 +1267,39 @@ type AcceptorType struct {
 	// Whether or not to expose this acceptor
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Expose",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
 	Expose bool `json:"expose,omitempty"`
+	// How the acceptor is exposed, route, ingress, loadBalancer, nodePort or sni. Defaults to route on OpenShift and ingress otherwise. With sni every pod is reached through the one TLS endpoint of the router or ingress controller by its host name, the acceptor must have SSL enabled
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Expose Mode",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	ExposeMode *ExposeMode `json:"exposeMode,omitempty"`
+	// With the loadBalancer and nodePort modes, create a Service for each broker pod rather than one Service for the acceptor
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Expose Per Pod",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	ExposePerPod bool `json:"exposePerPod,omitempty"`
+	// With the nodePort mode, the fixed node port of the Service. With exposePerPod the pods get consecutive ports starting at it. Allocated by the cluster when not set
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Node Port",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	NodePort *int32 `json:"nodePort,omitempty"`
+	// Publish the external address of each broker pod as a connector named <acceptor name>-external-<ordinal> in the configuration of every broker, for example for the static connectors of a connection router that redirects clients from outside the cluster. Needs a route, an ingress or a loadBalancer Service per pod
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Publish External Connectors",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	PublishExternalConnectors bool `json:"publishExternalConnectors,omitempty"`
+	// Expect the PROXY protocol header on connections to this acceptor, so the broker sees the real client address rather than the one of the load balancer. The generated LoadBalancer Service or Ingress is annotated to send it, connections without the header are refused
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Proxy Protocol",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	ProxyProtocol bool `json:"proxyProtocol,omitempty"`
+	// The cert-manager issuer of the acceptor certificate, the operator creates the Certificate and wires its keystore in place of sslSecret content
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Certificate Issuer"
+	CertificateIssuer *CertificateIssuerType `json:"certificateIssuer,omitempty"`
+	// The ingress class of the generated Ingress, when not on OpenShift
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Ingress Class Name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	IngressClassName string `json:"ingressClassName,omitempty"`
+	// Annotations added to the generated Ingress, Route or LoadBalancer Service, for example ingress controller specific timeouts or cloud load balancer settings
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Expose Annotations"
+	ExposeAnnotations map[string]string `json:"exposeAnnotations,omitempty"`
+	// The DNS records external-dns publishes for the generated Ingress, Route or Service
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="External DNS"
+	ExternalDNS *ExternalDNSType `json:"externalDNS,omitempty"`
+	// How the generated Route terminates TLS, on OpenShift. Passthrough when SSL is enabled and none otherwise when not set
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Route TLS"
+	RouteTLS *RouteTLSType `json:"routeTLS,omitempty"`
+	// Session affinity and traffic policies of the Services generated for it
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Service Settings"
+	ServiceSettings *ServiceSettingsType `json:"serviceSettings,omitempty"`
 	// To indicate which kind of routing type to use.
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Anycast Prefix",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
 	AnycastPrefix string `json:"anycastPrefix,omitempty"`
@@ -464,6 +1321,15 @@ type AcceptorType struct {
 	// Whether to let the acceptor to bind to all interfaces
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Bind To All Interfaces",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
 	BindToAllInterfaces *bool `json:"bindToAllInterfaces,omitempty"`
+	// Name of the pod network interface the acceptor binds to, for example net1 for the first additional network. Takes precedence over bindToAllInterfaces
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Bind Interface",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	BindInterface string `json:"bindInterface,omitempty"`
+	// Comma separated list of SASL mechanisms offered to AMQP clients, for example GSSAPI,SCRAM-SHA-512
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="SASL Mechanisms",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	SASLMechanisms string `json:"saslMechanisms,omitempty"`
+	// The JAAS scope used for the SASL mechanisms, defaults to the first Kerberos login module of the applicable security CR for GSSAPI
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="SASL Login Config Scope",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	SASLLoginConfigScope string `json:"saslLoginConfigScope,omitempty"`
 	// Provider used for the keystore; "SUN", "SunJCE", etc. Default is null
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="KeyStore Provider",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
 	KeyStoreProvider string `json:"keyStoreProvider,omitempty"`
@@ -473,6 +1339,36 @@ type AcceptorType struct {
 	// Provider used for the truststore; "SUN", "SunJCE", etc. Default in broker is null
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="TrustStore Provider",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
 	TrustStoreProvider string `json:"trustStoreProvider,omitempty"`
+	// Additional transport parameters appended to the acceptor URL, for example handshake-timeout. They take precedence over the defaults the operator sets
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Params"
+	Params map[string]string `json:"params,omitempty"`
+}
+
+type CertificateIssuerType struct {
+	// Name of the cert-manager Issuer or ClusterIssuer
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Name string `json:"name"`
+	// Issuer or ClusterIssuer, defaults to Issuer
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Kind",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Kind string `json:"kind,omitempty"`
+	// The API group of the issuer, defaults to cert-manager.io
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Group",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Group string `json:"group,omitempty"`
+	// DNS names added to the certificate next to the broker pod and exposed host names
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="DNS Names",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	DNSNames []string `json:"dnsNames,omitempty"`
+}
+
+type ClusterTLSType struct {
+	// The cert-manager issuer of the broker certificate, when not set the operator creates a self signed CA for the cluster
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Certificate Issuer"
+	CertificateIssuer *CertificateIssuerType `json:"certificateIssuer,omitempty"`
+	// How long the broker certificate is valid, for example 2160h, defaults to the one of the issuer
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Duration",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Duration string `json:"duration,omitempty"`
+	// How long before it expires the broker certificate is renewed, for example 360h, defaults to a third of the duration
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Renew Before",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	RenewBefore string `json:"renewBefore,omitempty"`
 }
 
 type ConnectorType struct {
@@ -518,6 +1414,9 @@ type ConnectorType struct {
 	// Whether or not to expose this connector
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Expose",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
 	Expose bool `json:"expose,omitempty"`
+	// The cert-manager issuer of the connector certificate, the operator creates the Certificate and uses its keystore and truststore for the outbound connection
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Certificate Issuer"
+	CertificateIssuer *CertificateIssuerType `json:"certificateIssuer,omitempty"`
 	// Provider used for the keystore; "SUN", "SunJCE", etc. Default is null
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="KeyStore Provider",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
 	KeyStoreProvider string `json:"keyStoreProvider,omitempty"`
@@ -527,12 +1426,36 @@ type ConnectorType struct {
 	// Provider used for the truststore; "SUN", "SunJCE", etc. Default in broker is null
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="TrustStore Provider",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
 	TrustStoreProvider string `json:"trustStoreProvider,omitempty"`
+	// Additional transport parameters appended to the connector URL
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Params"
+	Params map[string]string `json:"params,omitempty"`
 }
 
 type ConsoleType struct {
 	// Whether or not to expose this port
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Expose",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
 	Expose bool `json:"expose,omitempty"`
+	// The ingress class of the generated Ingress, when not on OpenShift
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Ingress Class Name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	IngressClassName string `json:"ingressClassName,omitempty"`
+	// Annotations added to the generated Ingress or Route
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Expose Annotations"
+	ExposeAnnotations map[string]string `json:"exposeAnnotations,omitempty"`
+	// The DNS records external-dns publishes for the generated Ingress or Route
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="External DNS"
+	ExternalDNS *ExternalDNSType `json:"externalDNS,omitempty"`
+	// The host name of the generated Route or Ingress instead of the generated one, {ordinal} is replaced by the ordinal of the broker pod and is required when there is more than one
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Host",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Host string `json:"host,omitempty"`
+	// The path the generated Route or Ingress serves the console on, / by default. Not supported when TLS is passed through to the broker
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Path",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Path string `json:"path,omitempty"`
+	// How the generated Route terminates TLS, on OpenShift. Passthrough when SSL is enabled and none otherwise when not set
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Route TLS"
+	RouteTLS *RouteTLSType `json:"routeTLS,omitempty"`
+	// Session affinity and traffic policies of the Services generated for it
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Service Settings"
+	ServiceSettings *ServiceSettingsType `json:"serviceSettings,omitempty"`
 	// Whether or not to enable SSL on this port
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="SSL Enabled",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
 	SSLEnabled bool `json:"sslEnabled,omitempty"`
@@ -542,6 +1465,60 @@ type ConsoleType struct {
 	// If the embedded server requires client authentication
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Use Client Auth",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
 	UseClientAuth bool `json:"useClientAuth,omitempty"`
+	// Whether to disable the embedded web server, the operator then can't manage addresses or report the broker properties status through Jolokia
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Disabled",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	Disabled bool `json:"disabled,omitempty"`
+	// The host the embedded web server binds to, for example 0.0.0.0, defaults to the pod IP
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Bind Host",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	BindHost string `json:"bindHost,omitempty"`
+	// The container port of the embedded web server, defaults to 8161
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Port",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	Port int32 `json:"port,omitempty"`
+	// The time in seconds an idle console session is kept open
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Session Timeout Seconds",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	SessionTimeoutSeconds *int32 `json:"sessionTimeoutSeconds,omitempty"`
+	// Specifies the configuration of the Jolokia endpoint served on the console port, the operator manages brokers through it
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Jolokia Configuration"
+	Jolokia *JolokiaType `json:"jolokia,omitempty"`
+	// Signs users into the console with an OpenID Connect provider through an oauth2-proxy container in front of the console port
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Single Sign-On"
+	SSO *ConsoleSSOType `json:"sso,omitempty"`
+}
+
+type ConsoleSSOType struct {
+	// The issuer URL of the OpenID Connect provider, for example https://keycloak.example.com/realms/artemis
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Issuer URL",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	IssuerURL string `json:"issuerUrl"`
+	// The client ID registered with the provider for the console
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Client ID",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	ClientID string `json:"clientId"`
+	// The key of the secret holding the client secret registered with the provider
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Client Secret"
+	ClientSecret corev1.SecretKeySelector `json:"clientSecret"`
+	// The scopes requested from the provider, defaults to openid, profile and email
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Scopes"
+	Scopes []string `json:"scopes,omitempty"`
+	// The groups of the provider allowed to sign in, any authenticated user when not set
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Allowed Groups"
+	AllowedGroups []string `json:"allowedGroups,omitempty"`
+	// The oauth2-proxy image, defaults to the one the operator was built with
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Proxy Image",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	ProxyImage string `json:"proxyImage,omitempty"`
+}
+
+type JolokiaType struct {
+	// Whether to require https for the Jolokia endpoint, the console must have SSL enabled
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Force HTTPS",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	ForceHttps bool `json:"forceHttps,omitempty"`
+	// The origins allowed to make cross origin requests, for example *://console.example.com*, defaults to *://localhost*
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Allowed Origins"
+	AllowedOrigins []string `json:"allowedOrigins,omitempty"`
+	// Whether the Origin header of every request is checked against the allowed origins, defaults to true
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Strict Checking",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	StrictChecking *bool `json:"strictChecking,omitempty"`
+	// Name of a secret with the jolokiaUser and jolokiaPassword keys of a dedicated management user the operator uses instead of the admin user
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Credentials Secret",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
 }
 
 // ActiveMQArtemis App product upgrade flags, this is deprecated in v1beta1, specifying the Version is sufficient
@@ -577,6 +1554,10 @@ type ActiveMQArtemisStatus struct {
 	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Deployment Plan Size"
 	DeploymentPlanSize int32 `json:"deploymentPlanSize,omitempty"`
 
+	// The number of broker pods the StatefulSet runs, the current replicas of the scale subresource
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Replicas"
+	Replicas int32 `json:"replicas,omitempty"`
+
 	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Auto scale label selector"
 	ScaleLabelSelector string `json:"scaleLabelSelector,omitempty"`
 
@@ -589,6 +1570,208 @@ type ActiveMQArtemisStatus struct {
 
 	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Upgrade Status"
 	Upgrade UpgradeStatus `json:"upgrade,omitempty"`
+
+	// Exposed endpoints that resolve and accept connections from outside the cluster
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="External Endpoints"
+	ExternalEndpoints []ExternalEndpointStatus `json:"externalEndpoints,omitempty"`
+
+	// The value of the broker.amq.io/restartedAt annotation every broker pod has been restarted for
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Restarted At",xDescriptors="urn:alm:descriptor:text"
+	RestartedAt string `json:"restartedAt,omitempty"`
+
+	// The API version the CR was last applied with, deprecated versions are reported until the CR is applied with broker.amq.io/v1beta1
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Applied API Version",xDescriptors="urn:alm:descriptor:text"
+	AppliedAPIVersion string `json:"appliedAPIVersion,omitempty"`
+
+	// Whether the applied API version is deprecated
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Deprecated API Version",xDescriptors="urn:alm:descriptor:text"
+	DeprecatedAPIVersion bool `json:"deprecatedAPIVersion,omitempty"`
+
+	// The progress of the last rotation of the cluster credentials
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Cluster Credential Rotation"
+	ClusterCredentialRotation *CredentialRotationStatus `json:"clusterCredentialRotation,omitempty"`
+
+	// The progress of the last rotation of the generated admin credentials
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Admin Credential Rotation"
+	AdminCredentialRotation *CredentialRotationStatus `json:"adminCredentialRotation,omitempty"`
+
+	// The progress of the last canary rollout of the security configuration
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Security Canary"
+	SecurityCanary *SecurityCanaryStatus `json:"securityCanary,omitempty"`
+
+	// The security configuration last rendered into the pod template
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Security"
+	Security *SecurityRenderStatus `json:"security,omitempty"`
+
+	// The message migrations off the brokers removed by a scale down, copied from the ActiveMQArtemisScaledown CR
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Message Migration"
+	MessageMigration []ScaledownDrainStatus `json:"messageMigration,omitempty"`
+
+	// The primary and backup pairs of the brokers and which pod of each is live
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="High Availability Pairs"
+	HA []HAPairStatus `json:"ha,omitempty"`
+
+	// The brokers in each zone of the cluster, with zone awareness
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Zones"
+	Zones []ZoneStatus `json:"zones,omitempty"`
+
+	// The health of the mirror of each AMQP connection, read from the brokers
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="AMQP Connections"
+	AMQPConnections []AMQPConnectionStatus `json:"amqpConnections,omitempty"`
+}
+
+type AMQPConnectionStatus struct {
+	// The name of the connection
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Name",xDescriptors="urn:alm:descriptor:text"
+	Name string `json:"name"`
+
+	// The broker pods whose mirror is connected to its target
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Connected",xDescriptors="urn:alm:descriptor:text"
+	Connected []string `json:"connected,omitempty"`
+
+	// The broker pods whose mirror is not connected to its target
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Disconnected",xDescriptors="urn:alm:descriptor:text"
+	Disconnected []string `json:"disconnected,omitempty"`
+
+	// The messages the brokers have not mirrored to their targets yet
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Backlog",xDescriptors="urn:alm:descriptor:text"
+	Backlog int64 `json:"backlog,omitempty"`
+}
+
+type HAPairStatus struct {
+	// The group name that pairs the backup with its primary
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Group Name",xDescriptors="urn:alm:descriptor:text"
+	GroupName string `json:"groupName"`
+
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Primary",xDescriptors="urn:alm:descriptor:text"
+	Primary string `json:"primary,omitempty"`
+
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Backup",xDescriptors="urn:alm:descriptor:text"
+	Backup string `json:"backup,omitempty"`
+
+	// The pod of the pair that is ready and serves the clients, empty while neither is
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Live",xDescriptors="urn:alm:descriptor:text"
+	Live string `json:"live,omitempty"`
+
+	// What a rollout of the pair waits for
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Message",xDescriptors="urn:alm:descriptor:text"
+	Message string `json:"message,omitempty"`
+}
+
+type ZoneStatus struct {
+	// The zone label of the nodes
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Zone",xDescriptors="urn:alm:descriptor:text"
+	Zone string `json:"zone"`
+
+	// The broker pods placed in the zone
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Pods",xDescriptors="urn:alm:descriptor:text"
+	Pods []string `json:"pods,omitempty"`
+
+	// The services of the acceptors that select the ready brokers of the zone
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Services",xDescriptors="urn:alm:descriptor:text"
+	Services []string `json:"services,omitempty"`
+}
+
+type SecurityRenderStatus struct {
+	// The name of the ActiveMQArtemisSecurity CR
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Name",xDescriptors="urn:alm:descriptor:text"
+	Name string `json:"name,omitempty"`
+
+	// The generation of the ActiveMQArtemisSecurity CR in the pod template
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Generation",xDescriptors="urn:alm:descriptor:text"
+	Generation int64 `json:"generation,omitempty"`
+
+	// Why the last generation could not be rendered, the pod template keeps the previous one
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Error",xDescriptors="urn:alm:descriptor:text"
+	Error string `json:"error,omitempty"`
+}
+
+type SecurityCanaryStatus struct {
+	// One of Verifying, Promoted or Failed
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Phase",xDescriptors="urn:alm:descriptor:text"
+	Phase SecurityCanaryPhase `json:"phase,omitempty"`
+
+	// The broker pod that takes the change first
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Pod",xDescriptors="urn:alm:descriptor:text"
+	Pod string `json:"pod,omitempty"`
+
+	// The checksum of the security configuration being rolled out
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Checksum",xDescriptors="urn:alm:descriptor:text"
+	Checksum string `json:"checksum,omitempty"`
+
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Started At",xDescriptors="urn:alm:descriptor:text"
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// What the rollout waits for or why the login check failed
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Message",xDescriptors="urn:alm:descriptor:text"
+	Message string `json:"message,omitempty"`
+}
+
+type SecurityCanaryPhase string
+
+const (
+	// the canary pod restarts with the change and a login to it is checked, the other pods keep the previous configuration
+	SecurityCanaryVerifying SecurityCanaryPhase = "Verifying"
+	// the login check passed and the other pods take the change
+	SecurityCanaryPromoted SecurityCanaryPhase = "Promoted"
+	// the login check didn't pass in time, the other pods keep the previous configuration until the change is fixed
+	SecurityCanaryFailed SecurityCanaryPhase = "Failed"
+)
+
+type CredentialRotationStatus struct {
+	// One of Preparing, Rolling, Verifying, CleaningUp or Completed
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Phase",xDescriptors="urn:alm:descriptor:text"
+	Phase CredentialRotationPhase `json:"phase,omitempty"`
+
+	// What started the rotation, annotation or schedule
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Trigger",xDescriptors="urn:alm:descriptor:text"
+	Trigger string `json:"trigger,omitempty"`
+
+	// The last value of the broker.amq.io/rotateClusterCredentialsAt or broker.amq.io/rotateAdminCredentialsAt annotation a rotation was started for
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Requested",xDescriptors="urn:alm:descriptor:text"
+	Requested string `json:"requested,omitempty"`
+
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Started At",xDescriptors="urn:alm:descriptor:text"
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Completed At",xDescriptors="urn:alm:descriptor:text"
+	CompletedAt *metav1.Time `json:"completedAt,omitempty"`
+
+	// What the rotation waits for
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Message",xDescriptors="urn:alm:descriptor:text"
+	Message string `json:"message,omitempty"`
+}
+
+type CredentialRotationPhase string
+
+const (
+	// the brokers are taught the new credentials before any broker uses them
+	CredentialRotationPreparing CredentialRotationPhase = "Preparing"
+	// the brokers restart one at a time with the new credentials, each still accepts the previous ones
+	CredentialRotationRolling CredentialRotationPhase = "Rolling"
+	// the cluster is waited on to form again
+	CredentialRotationVerifying CredentialRotationPhase = "Verifying"
+	// the previous credentials are removed from the brokers
+	CredentialRotationCleaningUp CredentialRotationPhase = "CleaningUp"
+	CredentialRotationCompleted  CredentialRotationPhase = "Completed"
+)
+
+type ExternalEndpointStatus struct {
+	// Name of the Route or Ingress
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Name",xDescriptors="urn:alm:descriptor:text"
+	Name string `json:"name"`
+
+	// Host name clients connect to
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Host",xDescriptors="urn:alm:descriptor:text"
+	Host string `json:"host"`
+
+	// Port clients connect to
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Port",xDescriptors="urn:alm:descriptor:text"
+	Port int32 `json:"port"`
+
+	// Whether the endpoint completed a TLS handshake
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="TLS",xDescriptors="urn:alm:descriptor:text"
+	TLS bool `json:"tls,omitempty"`
 }
 
 type VersionStatus struct {
@@ -623,7 +1806,7 @@ type ExternalConfigStatus struct {
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
-//+kubebuilder:subresource:scale:specpath=.spec.deploymentPlan.size,statuspath=.status.deploymentPlanSize,selectorpath=.status.scaleLabelSelector
+//+kubebuilder:subresource:scale:specpath=.spec.deploymentPlan.size,statuspath=.status.replicas,selectorpath=.status.scaleLabelSelector
 //+kubebuilder:storageversion
 //+kubebuilder:resource:path=activemqartemises
 //+operator-sdk:csv:customresourcedefinitions:resources={{"Service", "v1"}}
@@ -671,9 +1854,83 @@ const (
 	ValidConditionImagePairRequiredReason    = "InitImageMustBePairedWithBrokerImage"
 	ValidConditionInvalidVersionReason       = "SpecVersionInvalid"
 
-	ValidConditionPDBNonNilSelectorReason   = "PodDisruptionBudgetNonNilSelector"
-	ValidConditionFailedReservedLabelReason = "ReservedLabelReference"
-	ValidConditionFailedExtraMountReason    = "InvalidExtraMount"
+	ValidConditionPDBNonNilSelectorReason        = "PodDisruptionBudgetNonNilSelector"
+	ValidConditionFailedReservedLabelReason      = "ReservedLabelReference"
+	ValidConditionFailedExtraMountReason         = "InvalidExtraMount"
+	ValidConditionFailedPersistenceReason        = "InvalidPersistence"
+	ValidConditionRoleNotGrantedReason           = "RoleNotGrantedBySecurity"
+	ValidConditionInvalidLoggingReason           = "InvalidLogging"
+	ValidConditionInvalidNetworkReason           = "InvalidNetwork"
+	ValidConditionInvalidJvmReason               = "InvalidJvmConfiguration"
+	ValidConditionInvalidMetricsReason           = "InvalidMetrics"
+	ValidConditionInvalidReservedPrefixReason    = "InvalidReservedAddressPrefix"
+	ValidConditionInvalidJolokiaReason           = "InvalidJolokiaConfiguration"
+	ValidConditionInvalidPlaceholdersReason      = "InvalidCapacityPlaceholders"
+	ValidConditionInvalidConsoleReason           = "InvalidConsole"
+	ValidConditionUnsupportedVersionReason       = "UnsupportedBrokerVersion"
+	ValidConditionInvalidExposureReason          = "InvalidExposure"
+	ValidConditionInvalidIssuerReason            = "InvalidCertificateIssuer"
+	ValidConditionInvalidTLSRenewalReason        = "InvalidTLSRenewal"
+	ValidConditionInvalidParamsReason            = "InvalidTransportParams"
+	ValidConditionInvalidRecreateReason          = "InvalidImmutableFieldsPolicy"
+	ValidConditionInvalidForecastReason          = "InvalidCapacityForecast"
+	ValidConditionInvalidRouteTLSReason          = "InvalidRouteTLS"
+	ValidConditionInvalidEphemeralReason         = "InvalidEphemeral"
+	ValidConditionInvalidPresetReason            = "InvalidAcceptorPreset"
+	ValidConditionInvalidClusterTLSReason        = "InvalidClusterTLS"
+	ValidConditionInvalidHeadlessServiceReason   = "InvalidHeadlessService"
+	ValidConditionInvalidAffinityReason          = "InvalidAffinity"
+	ValidConditionInvalidExternalDNSReason       = "InvalidExternalDNS"
+	ValidConditionInvalidRetentionPolicyReason   = "InvalidRetentionPolicy"
+	ValidConditionInvalidIPFamiliesReason        = "InvalidIPFamilies"
+	ValidConditionInvalidRotationReason          = "InvalidClusterCredentialRotation"
+	ValidConditionInvalidRedeliveryReason        = "InvalidRedelivery"
+	ValidConditionClientAuthRequiredReason       = "CertificateLoginWithoutClientAuth"
+	ValidConditionGssapiWithoutKerberosReason    = "GssapiWithoutKerberosLogin"
+	ValidConditionInvalidCredentialsSourceReason = "InvalidCredentialsSource"
+	ValidConditionInvalidAutoscalingReason       = "InvalidAutoscaling"
+	ValidConditionInvalidHAReason                = "InvalidHA"
+	ValidConditionInvalidFederationReason        = "InvalidFederation"
+	ValidConditionInvalidAMQPConnectionReason    = "InvalidAMQPConnection"
+	ValidConditionInvalidClusterConnectionReason = "InvalidClusterConnection"
+	ValidConditionInvalidClusterMeshReason       = "InvalidClusterMesh"
+
+	UnschedulableConditionType          = "Unschedulable"
+	UnschedulableConditionPendingReason = "PodUnschedulable"
+
+	NotReachableConditionType          = "NotReachable"
+	NotReachableConditionPendingReason = "EndpointCheckPending"
+	NotReachableConditionFailedReason  = "EndpointNotReachable"
+
+	ReadOnlyConditionType             = "ReadOnly"
+	ReadOnlyConditionBlockedReason    = "ProducersBlocked"
+	ReadOnlyConditionBlockingReason   = "BlockingProducers"
+
+	LoggingLevelsAppliedConditionType     = "LoggingLevelsApplied"
+	LoggingLevelsAppliedConditionReason   = "LevelsApplied"
+	LoggingLevelsApplyingConditionReason  = "ApplyingLevels"
+
+	SecurityAppliedConditionType          = "SecurityApplied"
+	SecurityAppliedConditionAppliedReason = "SecurityConfigApplied"
+	SecurityAppliedConditionWaitingReason = "WaitingForSecurity"
+
+	MirrorDisconnectedConditionType   = "MirrorDisconnected"
+	MirrorDisconnectedConditionReason = "MirrorNotConnected"
+
+	CapacityWarningConditionType         = "CapacityWarning"
+	CapacityWarningConditionDiskReason   = "DiskExhaustionPredicted"
+	CapacityWarningConditionPagingReason = "PagingPredicted"
+
+	ReconciledConditionType             = "Reconciled"
+	ReconciledConditionStepFailedReason = "ReconcileStepFailed"
+
+	RecreatedConditionType                   = "Recreated"
+	RecreatedConditionBlockedReason          = "ImmutableFieldsChanged"
+	RecreatedConditionSnapshotRequiredReason = "SnapshotClassRequired"
+	RecreatedConditionDeletingReason         = "DeletingStatefulSet"
+	RecreatedConditionSnapshottingReason     = "SnapshottingVolumes"
+	RecreatedConditionReplacingVolumesReason = "ReplacingVolumes"
+	RecreatedConditionSuccessReason          = "StatefulSetRecreated"
 
 	ReadyConditionType      = "Ready"
 	ReadyConditionReason    = "ResourceReady"
@@ -688,4 +1945,5 @@ const (
 	ConfigAppliedConditionUnknownReason                   = "UnableToRetrieveStatus"
 	ConfigAppliedConditionOutOfSyncReason                 = "OutOfSync"
 	ConfigAppliedConditionNoJolokiaClientsAvailableReason = "NoJolokiaClientsAvailable"
+	ConfigAppliedConditionConsoleDisabledReason           = "ConsoleDisabled"
 )
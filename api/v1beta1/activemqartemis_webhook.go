@@ -17,6 +17,14 @@ limitations under the License.
 package v1beta1
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -26,6 +34,173 @@ import (
 // log is for logging in this package.
 var activemqartemislog = logf.Log.WithName("activemqartemis-webhookv1beta1")
 
+// ValidateAntiAffinityPreset rejects an unknown preset and affinity that keeps the brokers together
+// where the preset keeps them apart
+func (r *ActiveMQArtemis) ValidateAntiAffinityPreset() error {
+	preset := r.Spec.DeploymentPlan.AntiAffinityPreset
+	var apart []string
+	switch preset {
+	case "":
+		return nil
+	case AntiAffinityPresetRequired, AntiAffinityPresetPreferred:
+		apart = []string{corev1.LabelHostname}
+	case AntiAffinityPresetZone:
+		apart = []string{corev1.LabelHostname, corev1.LabelTopologyZone, corev1.LabelFailureDomainBetaZone}
+	default:
+		return fmt.Errorf("deploymentPlan.antiAffinityPreset %q must be one of required, preferred or zone", preset)
+	}
+
+	if podAffinity := r.Spec.DeploymentPlan.Affinity.PodAffinity; podAffinity != nil {
+		terms := append([]corev1.PodAffinityTerm{}, podAffinity.RequiredDuringSchedulingIgnoredDuringExecution...)
+		for _, weighted := range podAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+			terms = append(terms, weighted.PodAffinityTerm)
+		}
+		for _, term := range terms {
+			if containsString(apart, term.TopologyKey) && r.selectsBrokers(term) {
+				return fmt.Errorf("deploymentPlan.affinity.podAffinity keeps the brokers together on %v, deploymentPlan.antiAffinityPreset %v keeps them apart", term.TopologyKey, preset)
+			}
+		}
+	}
+
+	size := int32(1)
+	if r.Spec.DeploymentPlan.Size != nil {
+		size = *r.Spec.DeploymentPlan.Size
+	}
+	if preset != AntiAffinityPresetPreferred && size > 1 && r.pinnedToOneValue(corev1.LabelHostname) {
+		return fmt.Errorf("the node selection of deploymentPlan pins the %d brokers to a single node, deploymentPlan.antiAffinityPreset %v needs a node per broker", size, preset)
+	}
+	if preset == AntiAffinityPresetZone && (r.pinnedToOneValue(corev1.LabelTopologyZone) || r.pinnedToOneValue(corev1.LabelFailureDomainBetaZone)) {
+		return fmt.Errorf("the node selection of deploymentPlan pins the brokers to a single zone, deploymentPlan.antiAffinityPreset zone spreads them over the zones")
+	}
+	return nil
+}
+
+// ValidateHAStoreChange rejects moving persistent brokers to or from a shared store, the journals
+// stay on the volumes the brokers mounted before
+func (r *ActiveMQArtemis) ValidateHAStoreChange(old *ActiveMQArtemis) error {
+	if !old.Spec.DeploymentPlan.PersistenceEnabled || !r.Spec.DeploymentPlan.PersistenceEnabled {
+		return nil
+	}
+	if r.onSharedStore() != old.onSharedStore() {
+		return fmt.Errorf("ha.policy can't move persistent brokers to or from SharedStore, their journals stay on the previous volumes. Deploy a new CR with the policy and move the messages to it")
+	}
+	return nil
+}
+
+func (r *ActiveMQArtemis) onSharedStore() bool {
+	return r.Spec.HA != nil && r.Spec.HA.Enabled && r.Spec.HA.Policy == HAPolicySharedStore
+}
+
+// selectsBrokers is true when the term selects pods with the labels the operator gives the brokers of the cr
+func (r *ActiveMQArtemis) selectsBrokers(term corev1.PodAffinityTerm) bool {
+	if term.LabelSelector == nil {
+		return false
+	}
+	if len(term.Namespaces) > 0 && !containsString(term.Namespaces, r.Namespace) {
+		return false
+	}
+	if len(term.Namespaces) == 0 && term.NamespaceSelector != nil {
+		return false
+	}
+	selector, err := metav1.LabelSelectorAsSelector(term.LabelSelector)
+	if err != nil {
+		return false
+	}
+	brokerLabels := labels.Set{"ActiveMQArtemis": r.Name, "application": r.Name + "-app"}
+	for key, value := range r.Spec.DeploymentPlan.Labels {
+		brokerLabels[key] = value
+	}
+	return selector.Matches(brokerLabels)
+}
+
+// pinnedToOneValue is true when the node selector or the required node affinity allow a single value of the label
+func (r *ActiveMQArtemis) pinnedToOneValue(key string) bool {
+	if _, found := r.Spec.DeploymentPlan.NodeSelector[key]; found {
+		return true
+	}
+	nodeAffinity := r.Spec.DeploymentPlan.Affinity.NodeAffinity
+	if nodeAffinity == nil || nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return false
+	}
+	values := map[string]bool{}
+	for _, term := range nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		pinned := false
+		for _, expression := range term.MatchExpressions {
+			if expression.Key == key && expression.Operator == corev1.NodeSelectorOpIn && len(expression.Values) == 1 {
+				values[expression.Values[0]] = true
+				pinned = true
+			}
+		}
+		if !pinned {
+			return false
+		}
+	}
+	return len(values) == 1
+}
+
+// bounds past which a redelivery setting is more likely a unit mistake than intended
+const (
+	maxRedeliveryAttemptsBound = 1000
+	maxRedeliveryDelayBound    = 24 * time.Hour
+	maxRedeliveryMultiplier    = 10.0
+)
+
+// ValidateRedelivery rejects redelivery defaults the brokers would take but that can't be what was meant
+func (r *ActiveMQArtemis) ValidateRedelivery() error {
+	if r.Spec.Redelivery == nil {
+		return nil
+	}
+	return r.Spec.Redelivery.RedeliveryPolicyType.Validate("redelivery")
+}
+
+// Validate checks the settings of the policy, field is the path of the policy in the CR
+func (p *RedeliveryPolicyType) Validate(field string) error {
+	if p == nil {
+		return nil
+	}
+	if attempts := p.MaxDeliveryAttempts; attempts != nil && *attempts != -1 && (*attempts < 1 || *attempts > maxRedeliveryAttemptsBound) {
+		return fmt.Errorf("%v.maxDeliveryAttempts %d must be -1 or between 1 and %d", field, *attempts, maxRedeliveryAttemptsBound)
+	}
+
+	var delay, maxDelay time.Duration
+	var err error
+	if p.RedeliveryDelay != "" {
+		if delay, err = time.ParseDuration(p.RedeliveryDelay); err != nil || delay < 0 || delay > maxRedeliveryDelayBound {
+			return fmt.Errorf("%v.redeliveryDelay %q must be a duration of at most %v, for example 5s", field, p.RedeliveryDelay, maxRedeliveryDelayBound)
+		}
+	}
+	if p.MaxRedeliveryDelay != "" {
+		if maxDelay, err = time.ParseDuration(p.MaxRedeliveryDelay); err != nil || maxDelay <= 0 || maxDelay > maxRedeliveryDelayBound {
+			return fmt.Errorf("%v.maxRedeliveryDelay %q must be a duration of at most %v, for example 5m", field, p.MaxRedeliveryDelay, maxRedeliveryDelayBound)
+		}
+		if maxDelay < delay {
+			return fmt.Errorf("%v.maxRedeliveryDelay %v is shorter than the redeliveryDelay %v", field, p.MaxRedeliveryDelay, p.RedeliveryDelay)
+		}
+	}
+	if p.RedeliveryMultiplier != "" {
+		multiplier, err := strconv.ParseFloat(p.RedeliveryMultiplier, 64)
+		if err != nil || multiplier < 1 || multiplier > maxRedeliveryMultiplier {
+			return fmt.Errorf("%v.redeliveryMultiplier %q must be a number between 1 and %v", field, p.RedeliveryMultiplier, maxRedeliveryMultiplier)
+		}
+		if multiplier > 1 && delay == 0 {
+			return fmt.Errorf("%v.redeliveryMultiplier %v has no effect without a redeliveryDelay", field, p.RedeliveryMultiplier)
+		}
+	}
+	if strings.ContainsAny(p.DeadLetterAddress, "#*") {
+		return fmt.Errorf("%v.deadLetterAddress %q can't be a wildcard", field, p.DeadLetterAddress)
+	}
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
 func (r *ActiveMQArtemis) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
@@ -54,16 +229,25 @@ var _ webhook.Validator = &ActiveMQArtemis{}
 func (r *ActiveMQArtemis) ValidateCreate() error {
 	activemqartemislog.V(1).Info("validate create", "name", r.Name)
 
-	// TODO(user): fill in your validation logic upon object creation.
-	return nil
+	if err := r.ValidateAntiAffinityPreset(); err != nil {
+		return err
+	}
+	return r.ValidateRedelivery()
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (r *ActiveMQArtemis) ValidateUpdate(old runtime.Object) error {
 	activemqartemislog.Info("validate update", "name", r.Name)
 
-	// TODO(user): fill in your validation logic upon object update.
-	return nil
+	if err := r.ValidateAntiAffinityPreset(); err != nil {
+		return err
+	}
+	if previous, ok := old.(*ActiveMQArtemis); ok {
+		if err := r.ValidateHAStoreChange(previous); err != nil {
+			return err
+		}
+	}
+	return r.ValidateRedelivery()
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
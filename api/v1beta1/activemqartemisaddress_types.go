@@ -40,6 +40,10 @@ type ActiveMQArtemisAddressSpec struct {
 	// Whether or not delete the queue from broker when CR is undeployed(default false)
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Remove From Broker On Delete",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
 	RemoveFromBrokerOnDelete bool `json:"removeFromBrokerOnDelete,omitempty"`
+	// What deleting the CR removes from the brokers. RemoveQueue removes the queue and keeps the address, RemoveQueueAndAddress also removes the address once no queue is bound to it and Orphan leaves the brokers as they are. Without it, removeFromBrokerOnDelete true is RemoveQueueAndAddress and false is Orphan
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Removal Policy",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	//+kubebuilder:validation:Enum=RemoveQueue;RemoveQueueAndAddress;Orphan
+	RemovalPolicy string `json:"removalPolicy,omitempty"`
 	// User name for creating the queue or address
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="User",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
 	User *string `json:"user,omitempty"`
@@ -49,9 +53,44 @@ type ActiveMQArtemisAddressSpec struct {
 	// Specify the queue configuration
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Queue Configuration"
 	QueueConfiguration *QueueConfigurationType `json:"queueConfiguration,omitempty"`
+	// The queues of the address, in place of queueName and queueConfiguration. A queue without a routing type takes the one of the address
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Queues"
+	Queues []AddressQueueType `json:"queues,omitempty"`
 	// Apply to the broker crs in the current namespace. A value of * or empty string means applying to all broker crs. Default apply to all broker crs
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Apply To Broker CR Names"
 	ApplyToCrNames []string `json:"applyToCrNames,omitempty"`
+	// Apply to the broker crs in the current namespace whose labels match, in addition to the ones applyToCrNames names. With a selector and no applyToCrNames only the matching broker crs get the address
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Apply To Broker CR Selector"
+	ApplyToCrSelector *metav1.LabelSelector `json:"applyToCrSelector,omitempty"`
+	// Redelivery and dead letter settings of the address, in place of the defaults of the brokers
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Redelivery"
+	Redelivery *RedeliveryPolicyType `json:"redelivery,omitempty"`
+	// Dead letter and expiry queues named after the address, created with it and set as its dead letter and expiry addresses
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Dead Letter And Expiry"
+	DeadLetterAndExpiry *DeadLetterAndExpiryType `json:"deadLetterAndExpiry,omitempty"`
+}
+
+type AddressQueueType struct {
+	// The Queue Name
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Name string `json:"name"`
+	// The configuration of the queue
+	QueueConfigurationType `json:",inline"`
+}
+
+type DeadLetterAndExpiryType struct {
+	// Whether a dead letter queue is created, messages that use up their delivery attempts are sent to it
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Dead Letter",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	DeadLetter bool `json:"deadLetter,omitempty"`
+	// What the name of the dead letter address and queue adds to the address name. Default .DLQ
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Dead Letter Suffix",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	DeadLetterSuffix string `json:"deadLetterSuffix,omitempty"`
+	// Whether an expiry queue is created, expired messages are sent to it
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Expiry",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	Expiry bool `json:"expiry,omitempty"`
+	// What the name of the expiry address and queue adds to the address name. Default .ExpiryQueue
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Expiry Suffix",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	ExpirySuffix string `json:"expirySuffix,omitempty"`
 }
 
 type QueueConfigurationType struct {
@@ -137,8 +176,32 @@ type QueueConfigurationType struct {
 
 // ActiveMQArtemisAddressStatus defines the observed state of ActiveMQArtemisAddress
 type ActiveMQArtemisAddressStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	// The generation of the spec last applied to the brokers
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Observed Generation"
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// What applying the observed generation changed on the brokers
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Changes"
+	Changes []string `json:"changes,omitempty"`
+	// The outcome of applying the address on each selected broker pod
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Pods"
+	Pods []AddressPodStatus `json:"pods,omitempty"`
+	// Current state of the address
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Conditions",xDescriptors="urn:alm:descriptor:io.kubernetes.conditions"
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// AddressPodStatus is the outcome of applying the address on a broker pod
+type AddressPodStatus struct {
+	// The name of the broker pod
+	PodName string `json:"podName"`
+	// Applied or Failed
+	State string `json:"state"`
+	// Why the address failed on the pod
+	Message string `json:"message,omitempty"`
+	// The generation of the spec last applied on the pod
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// When the state, the message or the observed generation of the pod last changed
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -170,3 +233,28 @@ func init() {
 
 func (r *ActiveMQArtemisAddress) Hub() {
 }
+
+const (
+	AddressAppliedConditionType = "Applied"
+	AddressAppliedSuccessReason = "AppliedOnAllPods"
+	AddressAppliedFailedReason  = "ApplyFailed"
+
+	AddressDeployedConditionType      = "Deployed"
+	AddressDeployedSuccessReason      = "DeployedOnAllPods"
+	AddressDeployedNoBrokersReason    = "NoBrokerPods"
+	AddressDeployedUnreachableReason  = "BrokerUnreachable"
+	AddressDeployedUnauthorizedReason = "JolokiaUnauthorized"
+	AddressDeployedApplyFailedReason  = "ApplyFailed"
+
+	AddressPodStateApplied = "Applied"
+	AddressPodStateFailed  = "Failed"
+
+	ValidConditionInvalidAddressReason = "InvalidAddress"
+
+	DefaultDeadLetterSuffix = ".DLQ"
+	DefaultExpirySuffix     = ".ExpiryQueue"
+
+	AddressRemovalPolicyRemoveQueue           = "RemoveQueue"
+	AddressRemovalPolicyRemoveQueueAndAddress = "RemoveQueueAndAddress"
+	AddressRemovalPolicyOrphan                = "Orphan"
+)
@@ -17,8 +17,16 @@ limitations under the License.
 package v1beta1
 
 import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
@@ -26,7 +34,332 @@ import (
 // log is for logging in this package.
 var activemqartemisaddresslog = logf.Log.WithName("activemqartemisaddress-webhookv1beta1")
 
+// the webhooks read the CRs of the namespace through the api server, a validation depends on the stored
+// CRs rather than on what a controller of this process has seen
+var webhookClient client.Reader
+
+// SetWebhookClient sets the reader the webhooks list the CRs of a namespace with
+func SetWebhookClient(reader client.Reader) {
+	webhookClient = reader
+}
+
+func (r *ActiveMQArtemisAddress) validateReservedPrefixes() error {
+	if webhookClient == nil {
+		return nil
+	}
+	brokers := &ActiveMQArtemisList{}
+	if err := webhookClient.List(context.TODO(), brokers, client.InNamespace(r.Namespace)); err != nil {
+		return fmt.Errorf("unable to list the brokers of namespace %v: %v", r.Namespace, err)
+	}
+	for _, broker := range brokers.Items {
+		if broker.Spec.ReservedAddressPrefixes == nil || !r.appliesTo(broker.Name, broker.Labels) {
+			continue
+		}
+		for _, prefix := range broker.Spec.ReservedAddressPrefixes.Prefixes {
+			if strings.HasPrefix(r.Spec.AddressName, prefix) || strings.TrimSuffix(prefix, ".") == r.Spec.AddressName {
+				return fmt.Errorf("addressName %v uses the prefix %v that is reserved by broker %v", r.Spec.AddressName, prefix, broker.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// Validate checks the fields of the spec on their own. The address controller checks them too, for CRs
+// that were admitted without the webhook
+func (s *ActiveMQArtemisAddressSpec) Validate() error {
+	if errs := s.validationErrors(); len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// validateChanges checks the fields of the spec an update changes, a value that is stored already and
+// that a later release of the operator rejects is left alone
+func (s *ActiveMQArtemisAddressSpec) validateChanges(old *ActiveMQArtemisAddressSpec) error {
+	stored := map[string]bool{}
+	for _, err := range old.validationErrors() {
+		stored[err.Error()] = true
+	}
+	for _, err := range s.validationErrors() {
+		if !stored[err.Error()] {
+			return err
+		}
+	}
+	return nil
+}
+
+// validationErrors has an error for each field of the spec the broker would refuse
+func (s *ActiveMQArtemisAddressSpec) validationErrors() []error {
+	errs := []error{}
+	add := func(err error) {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	add(validateAddressName("addressName", s.AddressName))
+	if s.QueueName != nil && *s.QueueName != "" {
+		add(validateAddressName("queueName", *s.QueueName))
+	} else if s.QueueConfiguration != nil && len(s.Queues) == 0 {
+		add(fmt.Errorf("queueConfiguration is set without a queueName"))
+	}
+	if len(s.Queues) > 0 && ((s.QueueName != nil && *s.QueueName != "") || s.QueueConfiguration != nil) {
+		add(fmt.Errorf("queues can't be set with queueName or queueConfiguration"))
+	}
+	names := map[string]bool{}
+	for i := range s.Queues {
+		field := fmt.Sprintf("queues[%d]", i)
+		add(validateAddressName(field+".name", s.Queues[i].Name))
+		if names[s.Queues[i].Name] {
+			add(fmt.Errorf("%v.name %v is already used by another queue of the address", field, s.Queues[i].Name))
+		}
+		names[s.Queues[i].Name] = true
+		add(s.Queues[i].QueueConfigurationType.Validate(field))
+	}
+	if s.RoutingType != nil && *s.RoutingType != "" {
+		if !strings.EqualFold(*s.RoutingType, "anycast") && !strings.EqualFold(*s.RoutingType, "multicast") {
+			add(fmt.Errorf("routingType %q must be anycast or multicast", *s.RoutingType))
+		} else if c := s.QueueConfiguration; c != nil && c.RoutingType != nil && *c.RoutingType != "" && !strings.EqualFold(*c.RoutingType, *s.RoutingType) {
+			add(fmt.Errorf("routingType %v and queueConfiguration.routingType %v differ", *s.RoutingType, *c.RoutingType))
+		}
+	}
+	add(validateApplyToCrSelector(s.ApplyToCrSelector))
+	add(s.QueueConfiguration.Validate("queueConfiguration"))
+	add(s.DeadLetterAndExpiry.Validate("deadLetterAndExpiry", s.Redelivery))
+	add(s.Redelivery.Validate("redelivery"))
+	return errs
+}
+
+// validateAddressName rejects the names the broker would take for something else. A wildcard would
+// match other addresses, :: separates the address from the queue in a fully qualified queue name and
+// quotes or backslashes break the requests of the management api
+func validateAddressName(field string, name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("%v can't be empty", field)
+	}
+	if strings.ContainsAny(name, "#*\"\\ \t\r\n") || strings.Contains(name, "::") {
+		return fmt.Errorf("%v %q can't have wildcards, quotes, backslashes, spaces or ::", field, name)
+	}
+	return nil
+}
+
+// validateConflicts rejects an address or queue that another Address CR of the namespace already
+// creates on the same brokers. A queue name is unique on a broker, and an address can't have a queue
+// of a routing type it doesn't take. The other CRs are listed from the api server, the controller
+// skips the invalid ones so they are skipped here too
+func (r *ActiveMQArtemisAddress) validateConflicts() error {
+	if webhookClient == nil {
+		return nil
+	}
+	addresses := &ActiveMQArtemisAddressList{}
+	if err := webhookClient.List(context.TODO(), addresses, client.InNamespace(r.Namespace)); err != nil {
+		return fmt.Errorf("unable to list the addresses of namespace %v: %v", r.Namespace, err)
+	}
+	queues := r.Spec.specQueues()
+	for i := range addresses.Items {
+		name, other := addresses.Items[i].ObjectMeta, addresses.Items[i].Spec
+		if name.Name == r.Name || name.DeletionTimestamp != nil || other.Validate() != nil || !applyToOverlaps(&r.Spec, &other) {
+			continue
+		}
+		otherQueues := other.specQueues()
+		sameAddress := r.Spec.AddressName == other.AddressName
+		if sameAddress && len(queues) == 0 && len(otherQueues) == 0 {
+			return fmt.Errorf("address %v is already created by Address CR %v", r.Spec.AddressName, name.Name)
+		}
+		for _, queue := range queues {
+			for _, otherQueue := range otherQueues {
+				if queue.name != otherQueue.name {
+					continue
+				}
+				if !sameAddress {
+					return fmt.Errorf("queue %v is on address %v in Address CR %v, a queue name is unique on a broker", queue.name, other.AddressName, name.Name)
+				}
+				return fmt.Errorf("queue %v on address %v is already created by Address CR %v", queue.name, r.Spec.AddressName, name.Name)
+			}
+		}
+		if !sameAddress {
+			continue
+		}
+		// an address created without a queue takes only its own routing type
+		if len(queues) == 0 {
+			for _, otherQueue := range otherQueues {
+				if otherQueue.routingType != routingTypeOf(&r.Spec) {
+					return fmt.Errorf("address %v is %v in Address CR %v, not %v", r.Spec.AddressName, otherQueue.routingType, name.Name, routingTypeOf(&r.Spec))
+				}
+			}
+		} else if len(otherQueues) == 0 {
+			for _, queue := range queues {
+				if queue.routingType != routingTypeOf(&other) {
+					return fmt.Errorf("address %v is %v in Address CR %v, not %v", r.Spec.AddressName, routingTypeOf(&other), name.Name, queue.routingType)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+type specQueue struct {
+	name        string
+	routingType string
+}
+
+// specQueues are the queues the spec creates on its address with their routing types, none for an
+// address without a queue
+func (s *ActiveMQArtemisAddressSpec) specQueues() []specQueue {
+	if s.QueueName != nil && *s.QueueName != "" {
+		return []specQueue{{name: *s.QueueName, routingType: routingTypeOf(s)}}
+	}
+	queues := []specQueue{}
+	for _, queue := range s.Queues {
+		routingType := routingTypeOf(s)
+		if queue.RoutingType != nil && *queue.RoutingType != "" {
+			routingType = strings.ToUpper(*queue.RoutingType)
+		}
+		queues = append(queues, specQueue{name: queue.Name, routingType: routingType})
+	}
+	return queues
+}
+
+// routingTypeOf is the routing type the address or queue is created with
+func routingTypeOf(s *ActiveMQArtemisAddressSpec) string {
+	if s.RoutingType != nil && *s.RoutingType != "" {
+		return strings.ToUpper(*s.RoutingType)
+	}
+	if c := s.QueueConfiguration; c != nil && c.RoutingType != nil && *c.RoutingType != "" {
+		return strings.ToUpper(*c.RoutingType)
+	}
+	return "MULTICAST"
+}
+
+// applyToOverlaps tells whether two Address CRs apply to a same broker. The controller alone matches
+// selectors against the brokers, so a CR with a selector is taken to apply elsewhere
+func applyToOverlaps(a *ActiveMQArtemisAddressSpec, b *ActiveMQArtemisAddressSpec) bool {
+	if a.ApplyToCrSelector != nil || b.ApplyToCrSelector != nil {
+		return false
+	}
+	if appliesToAll(a.ApplyToCrNames) || appliesToAll(b.ApplyToCrNames) {
+		return true
+	}
+	for _, name := range a.ApplyToCrNames {
+		for _, otherName := range b.ApplyToCrNames {
+			if name == otherName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func appliesToAll(names []string) bool {
+	if len(names) == 0 {
+		return true
+	}
+	for _, name := range names {
+		if name == "" || name == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the queue attributes against the values the broker takes, -1 means no limit
+func (c *QueueConfigurationType) Validate(field string) error {
+	if c == nil {
+		return nil
+	}
+	if c.RoutingType != nil && *c.RoutingType != "" && !strings.EqualFold(*c.RoutingType, "anycast") && !strings.EqualFold(*c.RoutingType, "multicast") {
+		return fmt.Errorf("%v.routingType %q must be anycast or multicast", field, *c.RoutingType)
+	}
+	for name, value := range map[string]*int64{
+		"maxConsumers":           int32Value(c.MaxConsumers),
+		"groupBuckets":           int32Value(c.GroupBuckets),
+		"ringSize":               c.RingSize,
+		"delayBeforeDispatch":    c.DelayBeforeDispatch,
+		"autoDeleteMessageCount": c.AutoDeleteMessageCount,
+	} {
+		if value != nil && *value < -1 {
+			return fmt.Errorf("%v.%v %d must be -1 or more", field, name, *value)
+		}
+	}
+	for name, value := range map[string]*int64{
+		"consumersBeforeDispatch": int32Value(c.ConsumersBeforeDispatch),
+		"autoDeleteDelay":         c.AutoDeleteDelay,
+	} {
+		if value != nil && *value < 0 {
+			return fmt.Errorf("%v.%v %d can't be negative", field, name, *value)
+		}
+	}
+	if c.LastValueKey != nil && *c.LastValueKey != "" && c.LastValue != nil && !*c.LastValue {
+		return fmt.Errorf("%v.lastValueKey %v is set on a queue that is not a last value queue", field, *c.LastValueKey)
+	}
+	if c.Temporary != nil && *c.Temporary && c.Durable != nil && *c.Durable {
+		return fmt.Errorf("%v.durable is set on a temporary queue, a temporary queue doesn't outlive the broker", field)
+	}
+	if c.AutoDelete != nil && !*c.AutoDelete && (c.AutoDeleteDelay != nil || c.AutoDeleteMessageCount != nil) {
+		return fmt.Errorf("%v.autoDeleteDelay and %v.autoDeleteMessageCount are set on a queue that is not auto deleted", field, field)
+	}
+	return nil
+}
+
+// Validate checks the suffixes make queue names of their own, and that the dead letter queue doesn't
+// compete with the dead letter address of the redelivery policy
+func (d *DeadLetterAndExpiryType) Validate(field string, redelivery *RedeliveryPolicyType) error {
+	if d == nil {
+		return nil
+	}
+	for name, suffix := range map[string]string{"deadLetterSuffix": d.DeadLetterSuffix, "expirySuffix": d.ExpirySuffix} {
+		if strings.ContainsAny(suffix, "#* ") {
+			return fmt.Errorf("%v.%v %q can't have wildcards or spaces", field, name, suffix)
+		}
+	}
+	deadLetterSuffix, expirySuffix := d.DeadLetterSuffix, d.ExpirySuffix
+	if deadLetterSuffix == "" {
+		deadLetterSuffix = DefaultDeadLetterSuffix
+	}
+	if expirySuffix == "" {
+		expirySuffix = DefaultExpirySuffix
+	}
+	if d.DeadLetter && d.Expiry && deadLetterSuffix == expirySuffix {
+		return fmt.Errorf("%v.deadLetterSuffix and %v.expirySuffix are both %q", field, field, deadLetterSuffix)
+	}
+	if d.DeadLetter && redelivery != nil && redelivery.DeadLetterAddress != "" {
+		return fmt.Errorf("%v.deadLetter creates the dead letter address, redelivery.deadLetterAddress %v can't be set with it", field, redelivery.DeadLetterAddress)
+	}
+	return nil
+}
+
+func int32Value(value *int32) *int64 {
+	if value == nil {
+		return nil
+	}
+	widened := int64(*value)
+	return &widened
+}
+
+func (r *ActiveMQArtemisAddress) appliesTo(brokerName string, brokerLabels map[string]string) bool {
+	if len(r.Spec.ApplyToCrNames) == 0 && r.Spec.ApplyToCrSelector == nil {
+		return true
+	}
+	for _, name := range r.Spec.ApplyToCrNames {
+		if name == "" || name == "*" || name == brokerName {
+			return true
+		}
+	}
+	if selector, err := metav1.LabelSelectorAsSelector(r.Spec.ApplyToCrSelector); err == nil {
+		return selector.Matches(labels.Set(brokerLabels))
+	}
+	return false
+}
+
+// validateApplyToCrSelector rejects a selector the controller can't match brokers with
+func validateApplyToCrSelector(selector *metav1.LabelSelector) error {
+	if _, err := metav1.LabelSelectorAsSelector(selector); err != nil {
+		return fmt.Errorf("applyToCrSelector: %v", err)
+	}
+	return nil
+}
+
 func (r *ActiveMQArtemisAddress) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	SetWebhookClient(mgr.GetAPIReader())
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
 		Complete()
@@ -54,15 +387,38 @@ var _ webhook.Validator = &ActiveMQArtemisAddress{}
 func (r *ActiveMQArtemisAddress) ValidateCreate() error {
 	activemqartemisaddresslog.V(1).Info("validate create", "name", r.Name)
 
-	// TODO(user): fill in your validation logic upon object creation.
-	return nil
+	if err := r.Spec.Validate(); err != nil {
+		return err
+	}
+	if err := r.validateReservedPrefixes(); err != nil {
+		return err
+	}
+	return r.validateConflicts()
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (r *ActiveMQArtemisAddress) ValidateUpdate(old runtime.Object) error {
 	activemqartemisaddresslog.V(1).Info("validate update", "name", r.Name)
 
-	// TODO(user): fill in your validation logic upon object update.
+	oldAddress, ok := old.(*ActiveMQArtemisAddress)
+	if !ok {
+		return fmt.Errorf("expected an ActiveMQArtemisAddress but got a %T", old)
+	}
+	if err := r.Spec.validateChanges(&oldAddress.Spec); err != nil {
+		return err
+	}
+	// the brokers and the other CRs are only checked against when the address or its queues change
+	sameAddress := r.Spec.AddressName == oldAddress.Spec.AddressName &&
+		reflect.DeepEqual(r.Spec.ApplyToCrNames, oldAddress.Spec.ApplyToCrNames) &&
+		reflect.DeepEqual(r.Spec.ApplyToCrSelector, oldAddress.Spec.ApplyToCrSelector)
+	if !sameAddress {
+		if err := r.validateReservedPrefixes(); err != nil {
+			return err
+		}
+	}
+	if !sameAddress || routingTypeOf(&r.Spec) != routingTypeOf(&oldAddress.Spec) || !reflect.DeepEqual(r.Spec.specQueues(), oldAddress.Spec.specQueues()) {
+		return r.validateConflicts()
+	}
 	return nil
 }
 
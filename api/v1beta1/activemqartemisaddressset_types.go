@@ -0,0 +1,107 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ActiveMQArtemisAddressSetSpec defines the desired state of ActiveMQArtemisAddressSet
+type ActiveMQArtemisAddressSetSpec struct {
+	// The ConfigMap in the namespace of the CR that holds the addresses. Each key holds a json or yaml list of entries with the addressName, queueName, routingType and queueConfiguration fields of an Address CR
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Config Map Name",xDescriptors={"urn:alm:descriptor:io.kubernetes:ConfigMap"}
+	//+kubebuilder:validation:MinLength=1
+	ConfigMapName string `json:"configMapName"`
+	// The keys of the ConfigMap to read the entries from, every key when empty
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Keys"
+	Keys []string `json:"keys,omitempty"`
+	// What deleting the CR, or dropping an entry from the ConfigMap, removes from the brokers. RemoveQueue, RemoveQueueAndAddress or Orphan as for an Address CR. Default Orphan
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Removal Policy",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	//+kubebuilder:validation:Enum=RemoveQueue;RemoveQueueAndAddress;Orphan
+	RemovalPolicy string `json:"removalPolicy,omitempty"`
+	// The number of management operations sent to a broker in one request. Default 100
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Batch Size",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	//+kubebuilder:validation:Minimum=1
+	BatchSize *int32 `json:"batchSize,omitempty"`
+	// Apply to the broker crs in the current namespace. A value of * or empty string means applying to all broker crs. Default apply to all broker crs
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Apply To Broker CR Names"
+	ApplyToCrNames []string `json:"applyToCrNames,omitempty"`
+	// Apply to the broker crs in the current namespace whose labels match, in addition to the ones applyToCrNames names. With a selector and no applyToCrNames only the matching broker crs get the addresses
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Apply To Broker CR Selector"
+	ApplyToCrSelector *metav1.LabelSelector `json:"applyToCrSelector,omitempty"`
+}
+
+// ActiveMQArtemisAddressSetStatus defines the observed state of ActiveMQArtemisAddressSet
+type ActiveMQArtemisAddressSetStatus struct {
+	// The generation of the spec applied on every pod
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Observed Generation"
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// The resource version of the ConfigMap applied on every pod
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Config Map Version"
+	ConfigMapVersion string `json:"configMapVersion,omitempty"`
+	// The number of entries applied
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Entries"
+	Entries int32 `json:"entries,omitempty"`
+	// The addresses and queues applied on every pod, an address or a queue as address::queue. An entry dropped from the ConfigMap is removed from the brokers as the removal policy says
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Applied"
+	Applied []string `json:"applied,omitempty"`
+	// The broker pods the addresses are on
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Applied Pods"
+	AppliedPods []string `json:"appliedPods,omitempty"`
+	// Current state of the address set
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Conditions",xDescriptors="urn:alm:descriptor:io.kubernetes.conditions"
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:storageversion
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Config Map",type=string,JSONPath=`.spec.configMapName`
+//+kubebuilder:printcolumn:name="Entries",type=integer,JSONPath=`.status.entries`
+//+kubebuilder:printcolumn:name="Applied",type=string,JSONPath=`.status.conditions[?(@.type=="Applied")].status`
+
+// Addresses and queues from a ConfigMap created in bulk on running brokers through the management api
+//+operator-sdk:csv:customresourcedefinitions:displayName="ActiveMQ Artemis Address Set"
+type ActiveMQArtemisAddressSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ActiveMQArtemisAddressSetSpec   `json:"spec,omitempty"`
+	Status ActiveMQArtemisAddressSetStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ActiveMQArtemisAddressSetList contains a list of ActiveMQArtemisAddressSet
+type ActiveMQArtemisAddressSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ActiveMQArtemisAddressSet `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ActiveMQArtemisAddressSet{}, &ActiveMQArtemisAddressSetList{})
+}
+
+const (
+	AddressSetAppliedConditionType   = "Applied"
+	AddressSetAppliedSuccessReason   = "AppliedOnAllPods"
+	AddressSetAppliedPendingReason   = "PodsPending"
+	AddressSetAppliedNoBrokersReason = "NoBrokerPods"
+
+	ValidConditionInvalidAddressSetReason = "InvalidAddressSet"
+)
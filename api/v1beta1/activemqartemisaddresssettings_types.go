@@ -0,0 +1,81 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ActiveMQArtemisAddressSettingsSpec defines the desired state of ActiveMQArtemisAddressSettings
+type ActiveMQArtemisAddressSettingsSpec struct {
+	// The address settings, each applies to the addresses its match selects
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Address Setting"
+	AddressSetting []AddressSettingType `json:"addressSetting,omitempty"`
+	// Apply to the broker crs in the current namespace. A value of * or empty string means applying to all broker crs. Default apply to all broker crs
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Apply To Broker CR Names"
+	ApplyToCrNames []string `json:"applyToCrNames,omitempty"`
+}
+
+// ActiveMQArtemisAddressSettingsStatus defines the observed state of ActiveMQArtemisAddressSettings
+type ActiveMQArtemisAddressSettingsStatus struct {
+	// The matches set on the brokers, a match removed from the spec is removed from the brokers
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Matches"
+	Matches []string `json:"matches,omitempty"`
+	// The broker pods the settings are set on
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Applied Pods"
+	AppliedPods []string `json:"appliedPods,omitempty"`
+	// Current state of the address settings
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Conditions",xDescriptors="urn:alm:descriptor:io.kubernetes.conditions"
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:storageversion
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Applied",type=string,JSONPath=`.status.conditions[?(@.type=="Applied")].status`
+
+// Address settings set on running brokers through the management api, without a restart
+//+operator-sdk:csv:customresourcedefinitions:displayName="ActiveMQ Artemis Address Settings"
+type ActiveMQArtemisAddressSettings struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ActiveMQArtemisAddressSettingsSpec   `json:"spec,omitempty"`
+	Status ActiveMQArtemisAddressSettingsStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ActiveMQArtemisAddressSettingsList contains a list of ActiveMQArtemisAddressSettings
+type ActiveMQArtemisAddressSettingsList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ActiveMQArtemisAddressSettings `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ActiveMQArtemisAddressSettings{}, &ActiveMQArtemisAddressSettingsList{})
+}
+
+const (
+	AddressSettingsAppliedConditionType   = "Applied"
+	AddressSettingsAppliedSuccessReason   = "AppliedOnAllPods"
+	AddressSettingsAppliedPendingReason   = "PodsPending"
+	AddressSettingsAppliedNoBrokersReason = "NoBrokerPods"
+
+	ValidConditionInvalidAddressSettingsReason = "InvalidAddressSettings"
+)
@@ -0,0 +1,144 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ActiveMQArtemisBridgeSpec defines the desired state of ActiveMQArtemisBridge
+type ActiveMQArtemisBridgeSpec struct {
+	// The name of the bridge on the brokers, defaults to the name of the CR
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Bridge Name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	BridgeName string `json:"bridgeName,omitempty"`
+	// The queue the bridge consumes the messages from, it must exist on the brokers
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Queue Name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	//+kubebuilder:validation:MinLength=1
+	QueueName string `json:"queueName"`
+	// The address the messages are sent to on the target, defaults to the address of the messages
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Forwarding Address",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	ForwardingAddress string `json:"forwardingAddress,omitempty"`
+	// A filter, only the messages that match it are forwarded
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Filter",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Filter string `json:"filter,omitempty"`
+	// The class name of a transformer applied to the forwarded messages, the class must be on the classpath of the brokers
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Transformer Class Name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	TransformerClassName string `json:"transformerClassName,omitempty"`
+	// The broker the messages are forwarded to
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Target"
+	Target BridgeTargetType `json:"target"`
+	// How the bridge connects and reconnects to the target
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Reconnect Policy"
+	ReconnectPolicy *BridgeReconnectPolicyType `json:"reconnectPolicy,omitempty"`
+	// The size in bytes of the window of messages sent to the target before it acknowledges them, -1 for no limit. Default 1048576
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Producer Window Size",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	//+kubebuilder:validation:Minimum=-1
+	ProducerWindowSize *int32 `json:"producerWindowSize,omitempty"`
+	// Whether the target is sent a duplicate id with each message, so a message sent again after a reconnect is dropped. Default true
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Use Duplicate Detection",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	UseDuplicateDetection *bool `json:"useDuplicateDetection,omitempty"`
+	// Apply to the broker crs in the current namespace. A value of * or empty string means applying to all broker crs. Default apply to all broker crs
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Apply To Broker CR Names"
+	ApplyToCrNames []string `json:"applyToCrNames,omitempty"`
+}
+
+type BridgeTargetType struct {
+	// The url of the connector to the target broker, for example tcp://other-broker-hdls-svc:61616
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Connector Url",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	//+kubebuilder:validation:Pattern=`^[a-zA-Z][a-zA-Z0-9+.-]*://.+`
+	ConnectorUrl string `json:"connectorUrl"`
+	// Name of a secret in the namespace of the CR with the user and password keys of the user the bridge connects to the target as
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Credentials Secret",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
+}
+
+type BridgeReconnectPolicyType struct {
+	// The milliseconds between two attempts to connect to the target. Default 2000
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Retry Interval",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	//+kubebuilder:validation:Minimum=1
+	RetryInterval *int64 `json:"retryInterval,omitempty"`
+	// The multiplier applied to the retry interval after each failed attempt, as a decimal string. Default 1.0
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Retry Interval Multiplier",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	//+kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?$`
+	RetryIntervalMultiplier string `json:"retryIntervalMultiplier,omitempty"`
+	// The number of attempts to make the first connection to the target, -1 for no limit. Default -1
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Initial Connect Attempts",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	//+kubebuilder:validation:Minimum=-1
+	InitialConnectAttempts *int32 `json:"initialConnectAttempts,omitempty"`
+	// The number of attempts to reconnect to the target after the connection is lost, -1 for no limit. Default -1
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Reconnect Attempts",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	//+kubebuilder:validation:Minimum=-1
+	ReconnectAttempts *int32 `json:"reconnectAttempts,omitempty"`
+}
+
+// ActiveMQArtemisBridgeStatus defines the observed state of ActiveMQArtemisBridge
+type ActiveMQArtemisBridgeStatus struct {
+	// The name of the bridge on the brokers
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Bridge Name"
+	BridgeName string `json:"bridgeName,omitempty"`
+	// The generation of the spec the bridge on every pod was created from
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Observed Generation"
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// The resource version of the credentials secret the bridge on every pod was created with
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Credentials Version"
+	CredentialsVersion string `json:"credentialsVersion,omitempty"`
+	// The broker pods the bridge is on
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Applied Pods"
+	AppliedPods []string `json:"appliedPods,omitempty"`
+	// Current state of the bridge
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Conditions",xDescriptors="urn:alm:descriptor:io.kubernetes.conditions"
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:storageversion
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Queue",type=string,JSONPath=`.spec.queueName`
+//+kubebuilder:printcolumn:name="Target",type=string,JSONPath=`.spec.target.connectorUrl`
+//+kubebuilder:printcolumn:name="Applied",type=string,JSONPath=`.status.conditions[?(@.type=="Applied")].status`
+
+// A core bridge created on running brokers through the management api
+//+operator-sdk:csv:customresourcedefinitions:displayName="ActiveMQ Artemis Bridge"
+type ActiveMQArtemisBridge struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ActiveMQArtemisBridgeSpec   `json:"spec,omitempty"`
+	Status ActiveMQArtemisBridgeStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ActiveMQArtemisBridgeList contains a list of ActiveMQArtemisBridge
+type ActiveMQArtemisBridgeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ActiveMQArtemisBridge `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ActiveMQArtemisBridge{}, &ActiveMQArtemisBridgeList{})
+}
+
+const (
+	BridgeAppliedConditionType   = "Applied"
+	BridgeAppliedSuccessReason   = "AppliedOnAllPods"
+	BridgeAppliedPendingReason   = "PodsPending"
+	BridgeAppliedNoBrokersReason = "NoBrokerPods"
+
+	ValidConditionInvalidBridgeReason = "InvalidBridge"
+)
@@ -0,0 +1,113 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ActiveMQArtemisDivertSpec defines the desired state of ActiveMQArtemisDivert
+type ActiveMQArtemisDivertSpec struct {
+	// The name of the divert on the brokers, defaults to the name of the CR
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Divert Name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	DivertName string `json:"divertName,omitempty"`
+	// The routing name of the divert, diverts with the same routing name on an address share the messages. Defaults to the divert name
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Routing Name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	RoutingName string `json:"routingName,omitempty"`
+	// The address the messages are diverted from
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Address",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	//+kubebuilder:validation:MinLength=1
+	Address string `json:"address"`
+	// The address the messages are diverted to
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Forwarding Address",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	//+kubebuilder:validation:MinLength=1
+	ForwardingAddress string `json:"forwardingAddress"`
+	// Whether the messages are only sent to the forwarding address, by default a copy is sent and the original goes to the address
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Exclusive",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	Exclusive bool `json:"exclusive,omitempty"`
+	// A filter, only the messages that match it are diverted
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Filter",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Filter string `json:"filter,omitempty"`
+	// The class name of a transformer applied to the diverted messages, the class must be on the classpath of the brokers
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Transformer Class Name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	TransformerClassName string `json:"transformerClassName,omitempty"`
+	// Properties passed to the transformer
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Transformer Properties"
+	TransformerProperties map[string]string `json:"transformerProperties,omitempty"`
+	// The routing type of the diverted messages, one of STRIP, PASS, ANYCAST or MULTICAST. Default STRIP
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Routing Type",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	//+kubebuilder:validation:Pattern=`^([sS][tT][rR][iI][pP]|[pP][aA][sS][sS]|[aA][nN][yY][cC][aA][sS][tT]|[mM][uU][lL][tT][iI][cC][aA][sS][tT])$`
+	RoutingType string `json:"routingType,omitempty"`
+	// Apply to the broker crs in the current namespace. A value of * or empty string means applying to all broker crs. Default apply to all broker crs
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Apply To Broker CR Names"
+	ApplyToCrNames []string `json:"applyToCrNames,omitempty"`
+}
+
+// ActiveMQArtemisDivertStatus defines the observed state of ActiveMQArtemisDivert
+type ActiveMQArtemisDivertStatus struct {
+	// The name of the divert on the brokers
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Divert Name"
+	DivertName string `json:"divertName,omitempty"`
+	// The generation of the spec the divert on every pod was created from
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Observed Generation"
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// The broker pods the divert is on
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Applied Pods"
+	AppliedPods []string `json:"appliedPods,omitempty"`
+	// Current state of the divert
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Conditions",xDescriptors="urn:alm:descriptor:io.kubernetes.conditions"
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:storageversion
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Address",type=string,JSONPath=`.spec.address`
+//+kubebuilder:printcolumn:name="Forwarding Address",type=string,JSONPath=`.spec.forwardingAddress`
+//+kubebuilder:printcolumn:name="Applied",type=string,JSONPath=`.status.conditions[?(@.type=="Applied")].status`
+
+// A divert created on running brokers through the management api
+//+operator-sdk:csv:customresourcedefinitions:displayName="ActiveMQ Artemis Divert"
+type ActiveMQArtemisDivert struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ActiveMQArtemisDivertSpec   `json:"spec,omitempty"`
+	Status ActiveMQArtemisDivertStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ActiveMQArtemisDivertList contains a list of ActiveMQArtemisDivert
+type ActiveMQArtemisDivertList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ActiveMQArtemisDivert `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ActiveMQArtemisDivert{}, &ActiveMQArtemisDivertList{})
+}
+
+const (
+	DivertAppliedConditionType   = "Applied"
+	DivertAppliedSuccessReason   = "AppliedOnAllPods"
+	DivertAppliedPendingReason   = "PodsPending"
+	DivertAppliedNoBrokersReason = "NoBrokerPods"
+
+	ValidConditionInvalidDivertReason = "InvalidDivert"
+)
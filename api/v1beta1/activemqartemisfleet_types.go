@@ -0,0 +1,120 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ActiveMQArtemisFleetSpec defines the desired state of ActiveMQArtemisFleet
+type ActiveMQArtemisFleetSpec struct {
+	// The number of ActiveMQArtemis resources stamped out from the template, named <fleet>-<ordinal>
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Size",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:podCount"}
+	Size int32 `json:"size,omitempty"`
+	// The ActiveMQArtemis resource every member of the fleet is created from
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Template"
+	Template ActiveMQArtemisFleetTemplate `json:"template,omitempty"`
+	// Changes applied on top of the template for individual members of the fleet
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Overrides"
+	Overrides []ActiveMQArtemisFleetOverride `json:"overrides,omitempty"`
+}
+
+type ActiveMQArtemisFleetTemplate struct {
+	// Labels added to every ActiveMQArtemis of the fleet
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Labels"
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations added to every ActiveMQArtemis of the fleet
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Annotations"
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// The spec of every ActiveMQArtemis of the fleet
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Spec"
+	Spec ActiveMQArtemisSpec `json:"spec,omitempty"`
+}
+
+type ActiveMQArtemisFleetOverride struct {
+	// The ordinal of the fleet member the override applies to
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Ordinal",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	Ordinal int32 `json:"ordinal"`
+	// Labels added to the member on top of the template labels
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Labels"
+	Labels map[string]string `json:"labels,omitempty"`
+	// A strategic merge patch applied to the template spec of the member
+	//+kubebuilder:pruning:PreserveUnknownFields
+	//+kubebuilder:validation:Schemaless
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Spec"
+	Spec *runtime.RawExtension `json:"spec,omitempty"`
+}
+
+// ActiveMQArtemisFleetStatus defines the observed state of ActiveMQArtemisFleet
+type ActiveMQArtemisFleetStatus struct {
+	// The readiness of each member of the fleet
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Brokers"
+	Brokers []ActiveMQArtemisFleetBrokerStatus `json:"brokers,omitempty"`
+	// The number of members of the fleet that are ready
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Ready Brokers"
+	ReadyBrokers int32 `json:"readyBrokers,omitempty"`
+	// Current state of the fleet
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Conditions",xDescriptors="urn:alm:descriptor:io.kubernetes.conditions"
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+type ActiveMQArtemisFleetBrokerStatus struct {
+	// The name of the ActiveMQArtemis
+	Name string `json:"name"`
+	// Whether the Ready condition of the ActiveMQArtemis is true
+	Ready bool `json:"ready"`
+	// The message of the Ready condition when it is not true
+	Message string `json:"message,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:storageversion
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:path=activemqartemisfleets
+//+kubebuilder:printcolumn:name="Size",type=integer,JSONPath=`.spec.size`
+//+kubebuilder:printcolumn:name="Ready",type=integer,JSONPath=`.status.readyBrokers`
+
+// A set of identical brokers stamped out from a template
+//+operator-sdk:csv:customresourcedefinitions:displayName="ActiveMQ Artemis Fleet"
+type ActiveMQArtemisFleet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ActiveMQArtemisFleetSpec   `json:"spec,omitempty"`
+	Status ActiveMQArtemisFleetStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ActiveMQArtemisFleetList contains a list of ActiveMQArtemisFleet
+type ActiveMQArtemisFleetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ActiveMQArtemisFleet `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ActiveMQArtemisFleet{}, &ActiveMQArtemisFleetList{})
+}
+
+const (
+	// the label linking a member to its fleet
+	FleetLabel = "ActiveMQArtemisFleet"
+
+	ValidConditionInvalidOverrideReason = "InvalidFleetOverride"
+)
@@ -0,0 +1,133 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ActiveMQArtemisQueueMigrationSpec defines the desired state of ActiveMQArtemisQueueMigration
+type ActiveMQArtemisQueueMigrationSpec struct {
+	// The address of the queue to migrate
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Address Name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	AddressName string `json:"addressName"`
+	// The name of the queue to migrate
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Queue Name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	QueueName string `json:"queueName"`
+	// The routing type of the queue, anycast or multicast, defaults to anycast
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Routing Type",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	RoutingType string `json:"routingType,omitempty"`
+	// The broker the messages are moved from
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Source"
+	Source QueueMigrationEndpointType `json:"source"`
+	// The broker the messages are moved to, the queue is created on it when it doesn't exist
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Target"
+	Target QueueMigrationEndpointType `json:"target"`
+	// The bytes a bridge may send before the target acknowledges them, lower values throttle the migration. Defaults to 1048576
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Producer Window Size",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	ProducerWindowSize *int32 `json:"producerWindowSize,omitempty"`
+	// Whether to remove the queue from the source once every message it held is accounted for on the target
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Remove Source Queue",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	RemoveSourceQueue bool `json:"removeSourceQueue,omitempty"`
+}
+
+type QueueMigrationEndpointType struct {
+	// The name of the ActiveMQArtemis in the namespace of the migration
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Broker Name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	BrokerName string `json:"brokerName"`
+	// The pod of the broker, for a source every pod is migrated when it is not set and for a target it defaults to 0
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Ordinal",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	Ordinal *int32 `json:"ordinal,omitempty"`
+}
+
+// ActiveMQArtemisQueueMigrationStatus defines the observed state of ActiveMQArtemisQueueMigration
+type ActiveMQArtemisQueueMigrationStatus struct {
+	// Pending, Migrating or Completed
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Phase"
+	Phase string `json:"phase,omitempty"`
+	// The progress of each source pod
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Sources"
+	Sources []QueueMigrationSourceStatus `json:"sources,omitempty"`
+	// The messages left in the queue on the source pods
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Messages Remaining"
+	MessagesRemaining int64 `json:"messagesRemaining,omitempty"`
+	// The messages the bridges have forwarded to the target
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Messages Moved"
+	MessagesMoved int64 `json:"messagesMoved,omitempty"`
+	// Current state of the migration
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Conditions",xDescriptors="urn:alm:descriptor:io.kubernetes.conditions"
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+type QueueMigrationSourceStatus struct {
+	// The name of the source pod
+	Pod string `json:"pod"`
+	// The messages in the queue when the bridge was created
+	InitialMessageCount int64 `json:"initialMessageCount,omitempty"`
+	// The messages in the queue when last checked
+	MessageCount int64 `json:"messageCount,omitempty"`
+	// The messages the bridge has forwarded to the target
+	MessagesAcknowledged int64 `json:"messagesAcknowledged,omitempty"`
+	// The messages forwarded by the bridges the source pod lost when it restarted
+	PreviousMessagesAcknowledged int64 `json:"previousMessagesAcknowledged,omitempty"`
+	// Whether the bridge to the target has been created
+	Bridged bool `json:"bridged,omitempty"`
+	// Whether the queue has drained and the bridge has been removed
+	Drained bool `json:"drained,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:storageversion
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+//+kubebuilder:printcolumn:name="Remaining",type=integer,JSONPath=`.status.messagesRemaining`
+//+kubebuilder:printcolumn:name="Moved",type=integer,JSONPath=`.status.messagesMoved`
+
+// Moves a queue and its messages from one broker to another
+//+operator-sdk:csv:customresourcedefinitions:displayName="ActiveMQ Artemis Queue Migration"
+type ActiveMQArtemisQueueMigration struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ActiveMQArtemisQueueMigrationSpec   `json:"spec,omitempty"`
+	Status ActiveMQArtemisQueueMigrationStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ActiveMQArtemisQueueMigrationList contains a list of ActiveMQArtemisQueueMigration
+type ActiveMQArtemisQueueMigrationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ActiveMQArtemisQueueMigration `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ActiveMQArtemisQueueMigration{}, &ActiveMQArtemisQueueMigrationList{})
+}
+
+const (
+	QueueMigrationPhasePending   = "Pending"
+	QueueMigrationPhaseMigrating = "Migrating"
+	QueueMigrationPhaseCompleted = "Completed"
+
+	ValidConditionInvalidMigrationReason = "InvalidQueueMigration"
+
+	VerifiedConditionType              = "Verified"
+	VerifiedConditionSuccessReason     = "AllMessagesMoved"
+	VerifiedConditionUnaccountedReason = "MessagesUnaccounted"
+)
@@ -35,14 +35,78 @@ type ActiveMQArtemisScaledownSpec struct {
 	// Specifies the minimum/maximum amount of compute resources required/allowed
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Resource Requirements",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:resourceRequirements"}
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+	// Overrides of the drainer pod, from the deploymentPlan of the broker CR
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Drainer"
+	Drainer *DrainerType `json:"drainer,omitempty"`
 }
 
 // ActiveMQArtemisScaledownStatus defines the observed state of ActiveMQArtemisScaledown
 type ActiveMQArtemisScaledownStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
+
+	// The message migrations off the removed brokers, the last one of every ordinal
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Drains"
+	Drains []ScaledownDrainStatus `json:"drains,omitempty"`
+}
+
+type ScaledownDrainStatus struct {
+	// The drainer pod, it takes the name of the removed broker pod and mounts its volumes
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Pod",xDescriptors="urn:alm:descriptor:text"
+	Pod string `json:"pod"`
+
+	// The broker pod the messages are migrated to
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Target Pod",xDescriptors="urn:alm:descriptor:text"
+	TargetPod string `json:"targetPod,omitempty"`
+
+	// One of Waiting, Draining, Retrying or Succeeded
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Phase",xDescriptors="urn:alm:descriptor:text"
+	Phase ScaledownDrainPhase `json:"phase,omitempty"`
+
+	// The number of drainer pods started for the ordinal
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Attempts",xDescriptors="urn:alm:descriptor:text"
+	Attempts int32 `json:"attempts,omitempty"`
+
+	// The messages still on the volumes of the removed broker, read from the drainer
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Messages Remaining",xDescriptors="urn:alm:descriptor:text"
+	MessagesRemaining *int64 `json:"messagesRemaining,omitempty"`
+
+	// The decrease of the size of the messages in the drainer since the operator first read it, paged messages included
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Bytes Moved",xDescriptors="urn:alm:descriptor:text"
+	BytesMoved *int64 `json:"bytesMoved,omitempty"`
+
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Started At",xDescriptors="urn:alm:descriptor:text"
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Completed At",xDescriptors="urn:alm:descriptor:text"
+	CompletedAt *metav1.Time `json:"completedAt,omitempty"`
+
+	// The time from the first attempt to the completion
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Duration",xDescriptors="urn:alm:descriptor:text"
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// When the drainer is started again after a failed attempt
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Next Attempt At",xDescriptors="urn:alm:descriptor:text"
+	NextAttemptAt *metav1.Time `json:"nextAttemptAt,omitempty"`
+
+	// What the drain waits for or why the last attempt failed
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Message",xDescriptors="urn:alm:descriptor:text"
+	Message string `json:"message,omitempty"`
 }
 
+type ScaledownDrainPhase string
+
+const (
+	// the target pod is not ready, the drainer is started once it is
+	ScaledownDrainWaiting ScaledownDrainPhase = "Waiting"
+	// the drainer pod runs and migrates the messages
+	ScaledownDrainDraining ScaledownDrainPhase = "Draining"
+	// the last attempt failed, the drainer is started again at nextAttemptAt
+	ScaledownDrainRetrying ScaledownDrainPhase = "Retrying"
+	// the messages were migrated and the volumes of the removed broker deleted
+	ScaledownDrainSucceeded ScaledownDrainPhase = "Succeeded"
+)
+
 //+kubebuilder:object:root=true
 //+kubebuilder:storageversion
 //+kubebuilder:subresource:status
@@ -17,6 +17,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -40,6 +41,28 @@ type ActiveMQArtemisSecuritySpec struct {
 	// Apply this security config to the broker crs in the current namespace. A value of * or empty string means applying to all broker crs. Default apply to all broker crs
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Apply to Broker CR Names"
 	ApplyToCrNames []string `json:"applyToCrNames,omitempty"`
+	// Apply this security config to the broker crs in the current namespace whose labels match, in addition to the ones applyToCrNames names. With a selector and no applyToCrNames only the matching broker crs get the config
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Apply to Broker CR Selector"
+	ApplyToCrSelector *metav1.LabelSelector `json:"applyToCrSelector,omitempty"`
+	// Rolls a change of the security configuration out to one broker pod first, the other pods take it once a login to that pod succeeds
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Canary"
+	Canary *SecurityCanaryType `json:"canary,omitempty"`
+	// The codec the brokers decode masked passwords with and whether the passwords of their configuration are masked
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Password Masking"
+	PasswordMasking *PasswordMaskingType `json:"passwordMasking,omitempty"`
+}
+
+type SecurityCanaryType struct {
+	// Whether a change of the security configuration is rolled out to the broker pod with the highest ordinal first
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enabled",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	Enabled bool `json:"enabled,omitempty"`
+	// Secret with the user and password the login check uses in its user and password keys, required when the canary is enabled. The check connects to an acceptor of the broker that offers AMQP as this user
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Credentials Secret",xDescriptors={"urn:alm:descriptor:io.kubernetes:Secret"}
+	CredentialsSecret *string `json:"credentialsSecret,omitempty"`
+	// The time (in seconds) the canary pod has to become ready and pass the login check, after that the rollout is reported as failed. Defaults to 300
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Timeout Seconds",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	//+kubebuilder:validation:Minimum=1
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
 }
 
 type LoginModulesType struct {
@@ -52,6 +75,152 @@ type LoginModulesType struct {
 	// Specifies the Keycloak login modules
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Keycloak Login Modules"
 	KeycloakLoginModules []KeycloakLoginModuleType `json:"keycloakLoginModules,omitempty"`
+	// Specifies the SCRAM login modules
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="SCRAM Login Modules"
+	ScramLoginModules []ScramLoginModuleType `json:"scramLoginModules,omitempty"`
+	// Specifies the Kerberos (GSSAPI) login modules
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Kerberos Login Modules"
+	KerberosLoginModules []KerberosLoginModuleType `json:"kerberosLoginModules,omitempty"`
+	// Specifies the certificate login modules, they authenticate clients of acceptors that require client authentication by the subject DN of their certificate
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Certificate Login Modules"
+	CertificateLoginModules []CertificateLoginModuleType `json:"certificateLoginModules,omitempty"`
+	// Specifies the LDAP login modules, they authenticate users against a directory server. A security domain takes one by referencing its name
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="LDAP Login Modules"
+	LdapLoginModules []LdapLoginModuleType `json:"ldapLoginModules,omitempty"`
+}
+
+type LdapLoginModuleType struct {
+	// Name of the LDAP login module, the security domains reference it by this name
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Name string `json:"name,omitempty"`
+	// URL of the directory server, for example ldaps://ldap.example.com:636
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Connection URL",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	ConnectionURL string `json:"connectionURL,omitempty"`
+	// DN the module binds with to search for users and roles, it binds anonymously when not set
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Connection Username",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	ConnectionUsername *string `json:"connectionUsername,omitempty"`
+	// Password of the connection username
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Connection Password",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:password"}
+	ConnectionPassword *string `json:"connectionPassword,omitempty"`
+	// Secret key holding the password of the connection username, it can't be set together with connectionPassword
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Connection Password Secret"
+	ConnectionPasswordSecret *corev1.SecretKeySelector `json:"connectionPasswordSecret,omitempty"`
+	// DN of the entry users are searched under, for example ou=users,dc=example,dc=com
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="User Base",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	UserBase string `json:"userBase,omitempty"`
+	// Filter of the user search where {0} is the user name, for example (uid={0})
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="User Search Matching",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	UserSearchMatching string `json:"userSearchMatching,omitempty"`
+	// Whether users are searched in the whole subtree of the user base instead of its direct entries
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="User Search Subtree",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	UserSearchSubtree bool `json:"userSearchSubtree,omitempty"`
+	// DN of the entry groups are searched under, users get no roles from the directory when not set
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Role Base",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	RoleBase string `json:"roleBase,omitempty"`
+	// Attribute of a group that holds the role name, for example cn
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Role Name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	RoleName string `json:"roleName,omitempty"`
+	// Filter of the group search where {0} is the DN and {1} the name of the user, for example (member={0})
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Role Search Matching",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	RoleSearchMatching string `json:"roleSearchMatching,omitempty"`
+	// Whether groups are searched in the whole subtree of the role base instead of its direct entries
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Role Search Subtree",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	RoleSearchSubtree bool `json:"roleSearchSubtree,omitempty"`
+	// Whether the roles of nested groups are expanded, the groups of a group are searched with expandRolesMatching until no new role is found
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Expand Roles",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	ExpandRoles bool `json:"expandRoles,omitempty"`
+	// Filter of the nested group search where {0} is the DN of a group, for example (member={0})
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Expand Roles Matching",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	ExpandRolesMatching string `json:"expandRolesMatching,omitempty"`
+	// How referrals of the directory server are handled, follow, ignore or throw. Defaults to ignore
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Referral",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:select:follow","urn:alm:descriptor:com.tectonic.ui:select:ignore","urn:alm:descriptor:com.tectonic.ui:select:throw"}
+	//+kubebuilder:validation:Enum=follow;ignore;throw
+	Referral *string `json:"referral,omitempty"`
+	// Whether connections to the directory server are pooled
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Connection Pool",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	ConnectionPool *bool `json:"connectionPool,omitempty"`
+	// The time (in ms) to wait for a connection to the directory server
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Connection Timeout",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	//+kubebuilder:validation:Minimum=1
+	ConnectionTimeout *int32 `json:"connectionTimeout,omitempty"`
+	// The time (in ms) to wait for a response of the directory server
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Read Timeout",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	//+kubebuilder:validation:Minimum=1
+	ReadTimeout *int32 `json:"readTimeout,omitempty"`
+	// Secret with the truststore LDAPS connections trust the directory server by in its client.ts key and the truststore password in its trustStorePassword key. It becomes the truststore of the broker JVM
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Trust Store Secret",xDescriptors={"urn:alm:descriptor:io.kubernetes:Secret"}
+	TrustStoreSecret *string `json:"trustStoreSecret,omitempty"`
+}
+
+type CertificateLoginModuleType struct {
+	// Name of the certificate login module
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Name string `json:"name,omitempty"`
+	// The users the subject DNs of client certificates map to
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Users"
+	Users []CertificateUserType `json:"users,omitempty"`
+	// Flag of the module in the broker domain, defaults to sufficient
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Flag",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Flag *string `json:"flag,omitempty"`
+}
+
+type CertificateUserType struct {
+	// User name a matching client certificate authenticates as
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Name string `json:"name,omitempty"`
+	// The subject DN of the client certificate, for example CN=orders,OU=services,O=example, or a regular expression between slashes
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Subject DN",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	SubjectDN string `json:"subjectDN,omitempty"`
+	// Roles of the user
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Roles"
+	Roles []string `json:"roles,omitempty"`
+}
+
+type ScramLoginModuleType struct {
+	// Name of the SCRAM login module, it is the JAAS scope an acceptor references in saslLoginConfigScope
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Name string `json:"name,omitempty"`
+	// Name of the secret holding the SCRAM credentials in a users.properties key and the roles in a roles.properties key
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Users Secret",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	UsersSecret string `json:"usersSecret,omitempty"`
+	// Flag of the SCRAM module in the broker domain, defaults to sufficient
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Flag",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Flag *string `json:"flag,omitempty"`
+}
+
+type KerberosLoginModuleType struct {
+	// Name of the Kerberos login module, it is the JAAS scope an acceptor references in saslLoginConfigScope
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Name string `json:"name,omitempty"`
+	// The service principal of the broker, for example amqp/broker.example.com@EXAMPLE.COM
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Principal",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Principal string `json:"principal,omitempty"`
+	// Secret key holding the keytab of the service principal
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Keytab Secret"
+	KeytabSecret corev1.SecretKeySelector `json:"keytabSecret,omitempty"`
+	// ConfigMap key holding the krb5.conf, the JVM defaults are used when not set
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Krb5 Conf ConfigMap"
+	Krb5ConfConfigMap *corev1.ConfigMapKeySelector `json:"krb5ConfConfigMap,omitempty"`
+	// Flag of the module in the broker domain that maps the kerberos peer principal to the broker user, defaults to sufficient
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Flag",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Flag *string `json:"flag,omitempty"`
+	// Debug option of the login module
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Debug",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	Debug bool `json:"debug,omitempty"`
+}
+
+const (
+	PasswordAlgorithmPlain  = "plain"
+	PasswordAlgorithmOneWay = "one-way"
+)
+
+type PasswordMaskingType struct {
+	// Whether the passwords of the broker configuration are masked, the broker decodes them with the codec
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Mask Password",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	MaskPassword *bool `json:"maskPassword,omitempty"`
+	// The codec class followed by its ;key=value parameters. It decodes the masked passwords of the broker and verifies the ENC() passwords of the properties login module users. The broker default codec when not set
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Password Codec",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	PasswordCodec *string `json:"passwordCodec,omitempty"`
 }
 
 type PropertiesLoginModuleType struct {
@@ -70,6 +239,13 @@ type UserType struct {
 	// Password to be defined in properties login module
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Password",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:password"}
 	Password *string `json:"password,omitempty"`
+	// Secret key holding the password of the user, it can't be set together with password
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Password Secret"
+	PasswordSecret *corev1.SecretKeySelector `json:"passwordSecret,omitempty"`
+	// How the password or the value of passwordSecret is encoded, plain by default or one-way when it is a hash of the broker default codec as printed by artemis mask --hash
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Password Algorithm",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	//+kubebuilder:validation:Enum=plain;one-way
+	PasswordAlgorithm *string `json:"passwordAlgorithm,omitempty"`
 	// Roles to be defined in properties login module
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Roles"
 	Roles []string `json:"roles,omitempty"`
@@ -85,8 +261,17 @@ type GuestLoginModuleType struct {
 	// The guest user role
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Guest Role",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
 	GuestRole *string `json:"guestRole,omitempty"`
+	// Whether the module lets clients in as the guest user, defaults to true. A disabled module is left out of the security domains that reference it
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enabled",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	Enabled *bool `json:"enabled,omitempty"`
 }
 
+// DefaultBrokerDomain is the name of the broker domain when the CR doesn't set one
+const DefaultBrokerDomain = "activemq"
+
+// DefaultGuestRole is the role of the guest user when a guest module doesn't set one
+const DefaultGuestRole = "guests"
+
 type KeycloakLoginModuleType struct {
 	// Name for KeycloakLoginModule
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
@@ -97,6 +282,18 @@ type KeycloakLoginModuleType struct {
 	// Specifies the Keycloak module configuration
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Keycloa Module Configuration"
 	Configuration KeycloakModuleConfigurationType `json:"configuration,omitempty"`
+	// Keycloak roles that get the permissions of broker roles, the roles of a token are the broker roles otherwise
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Role Mappings"
+	RoleMappings []KeycloakRoleMappingType `json:"roleMappings,omitempty"`
+}
+
+type KeycloakRoleMappingType struct {
+	// The realm or client role of the Keycloak token
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Keycloak Role",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	KeycloakRole string `json:"keycloakRole"`
+	// The broker roles of the security settings whose permissions the Keycloak role gets
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Broker Roles"
+	BrokerRoles []string `json:"brokerRoles"`
 }
 
 type KeycloakModuleConfigurationType struct {
@@ -121,6 +318,9 @@ type KeycloakModuleConfigurationType struct {
 	// Specify the credentials
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Credentials"
 	Credentials []KeyValueType `json:"credentials,omitempty"`
+	// Secret key holding the client secret of the resource, in place of a secret entry in credentials
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Credentials Secret"
+	CredentialsSecret *corev1.SecretKeySelector `json:"credentialsSecret,omitempty"`
 	// If to use resource role mappings
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Use Resource Role Mappings",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
 	UseResourceRoleMappings *bool `json:"useResourceRoleMappings,omitempty"`
@@ -238,7 +438,7 @@ type SecurityDomainsType struct {
 	// Specify the broker domain
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Broker Domain"
 	BrokerDomain BrokerDomainType `json:"brokerDomain,omitempty"`
-	// Specify the console domain
+	// Specify the console domain, the console logs users in with its login modules when it has a name other than the broker domain
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Console Domain"
 	ConsoleDomain BrokerDomainType `json:"consoleDomain,omitempty"`
 }
@@ -285,6 +485,9 @@ type BrokerSecuritySettingType struct {
 	Permissions []PermissionType `json:"permissions,omitempty"`
 }
 
+// BrokerPermissionTypes are the operation types of a security setting
+var BrokerPermissionTypes = []string{"send", "consume", "browse", "createAddress", "deleteAddress", "createDurableQueue", "deleteDurableQueue", "createNonDurableQueue", "deleteNonDurableQueue", "manage"}
+
 type PermissionType struct {
 	// The operation type of a security setting
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Operation Type",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
@@ -361,6 +564,24 @@ type AuthorisationConfigType struct {
 	// Specify the role accesses
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Role Accesses"
 	RoleAccess []RoleAccessType `json:"roleAccess,omitempty"`
+	// The MBean operations and attributes no one may use through Jolokia, whatever their roles
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Denied Entries"
+	DeniedList []DeniedListEntryType `json:"deniedList,omitempty"`
+}
+
+type DeniedListEntryType struct {
+	// The domain of the MBeans, for example org.apache.activemq.artemis
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Domain",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Domain string `json:"domain,omitempty"`
+	// The key properties of the MBean names, for example broker=*,component=addresses,*, all the MBeans of the domain when not set
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Key",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Key string `json:"key,omitempty"`
+	// The operations denied on the MBeans
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Operations"
+	Operations []string `json:"operations,omitempty"`
+	// The attributes denied on the MBeans
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Attributes"
+	Attributes []string `json:"attributes,omitempty"`
 }
 
 type AllowedListEntryType struct {
@@ -395,8 +616,38 @@ type RoleAccessType struct {
 
 // ActiveMQArtemisSecurityStatus defines the observed state of ActiveMQArtemisSecurity
 type ActiveMQArtemisSecurityStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	// The generation of the spec the broker statuses are computed from
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Observed Generation"
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// The brokers the configuration applies to and how far their pods picked it up
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Brokers"
+	Brokers []SecurityBrokerStatus `json:"brokers,omitempty"`
+}
+
+// SecurityBrokerStatus is how far the pods of a broker picked up the configuration
+type SecurityBrokerStatus struct {
+	// The name of the ActiveMQArtemis CR
+	Name string `json:"name"`
+	// The generation of this CR in the pod template of the broker, unset until one is rendered
+	Generation int64 `json:"generation,omitempty"`
+	// The broker pods that run with that generation
+	UpdatedPods []string `json:"updatedPods,omitempty"`
+	// The broker pods that still have to restart with it
+	PendingPods []string `json:"pendingPods,omitempty"`
+	// Why the broker doesn't pick up the current generation
+	Error string `json:"error,omitempty"`
+	// The permissions the broker applies for each match, with its default permissions, the reserved address prefixes and the securityRoles of its broker properties
+	EffectivePermissions []EffectivePermissionType `json:"effectivePermissions,omitempty"`
+}
+
+// EffectivePermissionType is what the addresses of a match permit once the entries of the match are merged
+type EffectivePermissionType struct {
+	// The address match pattern
+	Match string `json:"match"`
+	// The roles of each operation type, an operation type that is not listed is permitted to no role
+	Permissions []PermissionType `json:"permissions,omitempty"`
+	// The less specific matches whose permissions don't apply to the addresses of this match
+	Overrides []string `json:"overrides,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -17,6 +17,11 @@ limitations under the License.
 package v1beta1
 
 import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -54,15 +59,570 @@ var _ webhook.Validator = &ActiveMQArtemisSecurity{}
 func (r *ActiveMQArtemisSecurity) ValidateCreate() error {
 	activemqartemissecuritylog.V(1).Info("validate create", "name", r.Name)
 
-	// TODO(user): fill in your validation logic upon object creation.
-	return nil
+	if err := r.Spec.LoginModules.validatePropertiesLoginModules(); err != nil {
+		return err
+	}
+	if err := r.Spec.LoginModules.validateKeycloakLoginModules(); err != nil {
+		return err
+	}
+	if err := r.Spec.SecuritySettings.Management.validate(); err != nil {
+		return err
+	}
+	if err := r.Spec.validateBrokerSecuritySettings(); err != nil {
+		return err
+	}
+	if err := r.Spec.LoginModules.validateCertificateLoginModules(); err != nil {
+		return err
+	}
+	if err := r.Spec.LoginModules.validateLdapLoginModules(); err != nil {
+		return err
+	}
+	if err := r.Spec.validateKerberosLoginModules(); err != nil {
+		return err
+	}
+	if err := r.Spec.Canary.validate(); err != nil {
+		return err
+	}
+	if err := r.Spec.validateSecurityDomains(); err != nil {
+		return err
+	}
+	return validateApplyToCrSelector(r.Spec.ApplyToCrSelector)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (r *ActiveMQArtemisSecurity) ValidateUpdate(old runtime.Object) error {
 	activemqartemissecuritylog.V(1).Info("validate update", "name", r.Name)
 
-	// TODO(user): fill in your validation logic upon object update.
+	if err := r.Spec.LoginModules.validatePropertiesLoginModules(); err != nil {
+		return err
+	}
+	if err := r.Spec.LoginModules.validateKeycloakLoginModules(); err != nil {
+		return err
+	}
+	if err := r.Spec.SecuritySettings.Management.validate(); err != nil {
+		return err
+	}
+	// a setting the old spec already had stays valid, such as one stored before a role was removed
+	var oldSpec *ActiveMQArtemisSecuritySpec
+	if oldSecurity, ok := old.(*ActiveMQArtemisSecurity); ok {
+		oldSpec = &oldSecurity.Spec
+	}
+	if err := r.Spec.validateChangedBrokerSecuritySettings(oldSpec); err != nil {
+		return err
+	}
+	if err := r.Spec.LoginModules.validateCertificateLoginModules(); err != nil {
+		return err
+	}
+	if err := r.Spec.LoginModules.validateLdapLoginModules(); err != nil {
+		return err
+	}
+	if err := r.Spec.validateKerberosLoginModules(); err != nil {
+		return err
+	}
+	if err := r.Spec.Canary.validate(); err != nil {
+		return err
+	}
+	if err := r.Spec.validateSecurityDomains(); err != nil {
+		return err
+	}
+	return validateApplyToCrSelector(r.Spec.ApplyToCrSelector)
+}
+
+// the one-way algorithm of the broker default codec stores iterations:salt:hash, optionally within ENC()
+var oneWayPasswordHashRegex = regexp.MustCompile(`^(ENC\()?[0-9]+:[0-9a-fA-F]+:[0-9a-fA-F]+\)?$`)
+
+// validatePropertiesLoginModules checks a user takes its password from the CR or from a secret key,
+// and that a hashed password is given rather than generated
+func (l *LoginModulesType) validatePropertiesLoginModules() error {
+	for i, module := range l.PropertiesLoginModules {
+		for j, user := range module.Users {
+			field := fmt.Sprintf("loginModules.propertiesLoginModules[%d].users[%d]", i, j)
+			if user.PasswordAlgorithm != nil && *user.PasswordAlgorithm == PasswordAlgorithmOneWay {
+				if user.Password == nil && user.PasswordSecret == nil {
+					return fmt.Errorf("%v.passwordAlgorithm one-way needs a password or passwordSecret holding the hash", field)
+				}
+				if user.Password != nil && !oneWayPasswordHashRegex.MatchString(*user.Password) {
+					return fmt.Errorf("%v.password is not a one-way hash of the broker default codec", field)
+				}
+			}
+			if user.PasswordSecret == nil {
+				continue
+			}
+			if user.Password != nil {
+				return fmt.Errorf("%v.passwordSecret can't be set with a password", field)
+			}
+			if user.PasswordSecret.Name == "" || user.PasswordSecret.Key == "" {
+				return fmt.Errorf("%v.passwordSecret needs a name and a key", field)
+			}
+		}
+	}
+	return nil
+}
+
+// validateKeycloakLoginModules checks a module knows its realm and server, and that its client secret
+// and role mappings are complete
+func (l *LoginModulesType) validateKeycloakLoginModules() error {
+	for i, module := range l.KeycloakLoginModules {
+		field := fmt.Sprintf("loginModules.keycloakLoginModules[%d]", i)
+		if module.Name == "" {
+			return fmt.Errorf("%v.name can't be empty", field)
+		}
+		if module.ModuleType != nil && *module.ModuleType != "directAccess" && *module.ModuleType != "bearerToken" {
+			return fmt.Errorf("%v.moduleType %q must be directAccess or bearerToken", field, *module.ModuleType)
+		}
+		config := module.Configuration
+		if config.Realm == nil || *config.Realm == "" {
+			return fmt.Errorf("%v.configuration.realm can't be empty", field)
+		}
+		if config.AuthServerUrl == nil || *config.AuthServerUrl == "" {
+			return fmt.Errorf("%v.configuration.authServerUrl can't be empty", field)
+		}
+		if secret := config.CredentialsSecret; secret != nil {
+			if secret.Name == "" || secret.Key == "" {
+				return fmt.Errorf("%v.configuration.credentialsSecret needs a name and a key", field)
+			}
+			for _, credential := range config.Credentials {
+				if credential.Key == "secret" {
+					return fmt.Errorf("%v.configuration.credentialsSecret can't be set with a secret entry in credentials", field)
+				}
+			}
+		}
+		for j, mapping := range module.RoleMappings {
+			if mapping.KeycloakRole == "" || len(mapping.BrokerRoles) == 0 {
+				return fmt.Errorf("%v.roleMappings[%d] needs a keycloakRole and brokerRoles", field, j)
+			}
+		}
+	}
+	return nil
+}
+
+// validateCertificateLoginModules checks the users of a module can be written to the properties
+// files the broker reads, a user name is a key and roles are joined with commas
+func (l *LoginModulesType) validateCertificateLoginModules() error {
+	names := map[string]bool{}
+	for i, module := range l.CertificateLoginModules {
+		field := fmt.Sprintf("loginModules.certificateLoginModules[%d]", i)
+		if module.Name == "" || strings.ContainsAny(module.Name, "/ \t") {
+			return fmt.Errorf("%v.name %q must be a name without white space or /, it names the properties files of the module", field, module.Name)
+		}
+		if names[module.Name] {
+			return fmt.Errorf("%v.name %v is not unique", field, module.Name)
+		}
+		names[module.Name] = true
+		if module.Flag != nil {
+			switch *module.Flag {
+			case "required", "requisite", "sufficient", "optional":
+			default:
+				return fmt.Errorf("%v.flag %q must be required, requisite, sufficient or optional", field, *module.Flag)
+			}
+		}
+		if len(module.Users) == 0 {
+			return fmt.Errorf("%v.users can't be empty", field)
+		}
+		users := map[string]bool{}
+		for j, user := range module.Users {
+			if user.Name == "" || strings.ContainsAny(user.Name, "=:#! \t\\") {
+				return fmt.Errorf("%v.users[%d].name %q must be a name without white space, =, :, #, ! or \\", field, j, user.Name)
+			}
+			if users[user.Name] {
+				return fmt.Errorf("%v.users[%d].name %v is not unique", field, j, user.Name)
+			}
+			users[user.Name] = true
+			if user.SubjectDN == "" {
+				return fmt.Errorf("%v.users[%d].subjectDN can't be empty", field, j)
+			}
+			for _, role := range user.Roles {
+				if role == "" || strings.ContainsAny(role, ",=:#! \t\\") {
+					return fmt.Errorf("%v.users[%d].roles %q must be names without white space, commas, =, :, #, ! or \\", field, j, role)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validateLdapLoginModules checks a module can reach its directory server and find the users in it,
+// that it binds with at most one password, and that the modules share a single truststore
+func (l *LoginModulesType) validateLdapLoginModules() error {
+	names := map[string]bool{}
+	trustStoreSecret := ""
+	for _, module := range l.CertificateLoginModules {
+		names[module.Name] = true
+	}
+	for i, module := range l.LdapLoginModules {
+		field := fmt.Sprintf("loginModules.ldapLoginModules[%d]", i)
+		if module.Name == "" || strings.ContainsAny(module.Name, " \t") {
+			return fmt.Errorf("%v.name %q must be a name without white space", field, module.Name)
+		}
+		if names[module.Name] {
+			return fmt.Errorf("%v.name %v is not unique among the certificate and ldap login modules", field, module.Name)
+		}
+		names[module.Name] = true
+		if !strings.HasPrefix(module.ConnectionURL, "ldap://") && !strings.HasPrefix(module.ConnectionURL, "ldaps://") {
+			return fmt.Errorf("%v.connectionURL %q must be an ldap:// or ldaps:// URL", field, module.ConnectionURL)
+		}
+		if module.UserBase == "" || module.UserSearchMatching == "" {
+			return fmt.Errorf("%v needs a userBase and a userSearchMatching", field)
+		}
+		if secret := module.ConnectionPasswordSecret; secret != nil {
+			if module.ConnectionPassword != nil {
+				return fmt.Errorf("%v.connectionPasswordSecret can't be set with a connectionPassword", field)
+			}
+			if secret.Name == "" || secret.Key == "" {
+				return fmt.Errorf("%v.connectionPasswordSecret needs a name and a key", field)
+			}
+		}
+		if (module.ConnectionPassword != nil || module.ConnectionPasswordSecret != nil) && module.ConnectionUsername == nil {
+			return fmt.Errorf("%v.connectionUsername is needed to bind with a password", field)
+		}
+		if module.Referral != nil && *module.Referral != "follow" && *module.Referral != "ignore" && *module.Referral != "throw" {
+			return fmt.Errorf("%v.referral %q must be follow, ignore or throw", field, *module.Referral)
+		}
+		if (module.ConnectionTimeout != nil && *module.ConnectionTimeout <= 0) || (module.ReadTimeout != nil && *module.ReadTimeout <= 0) {
+			return fmt.Errorf("%v.connectionTimeout and readTimeout must be positive", field)
+		}
+		if module.ExpandRoles && (module.RoleBase == "" || module.ExpandRolesMatching == "") {
+			return fmt.Errorf("%v.expandRoles needs a roleBase and an expandRolesMatching", field)
+		}
+		if trustStore := module.TrustStoreSecret; trustStore != nil {
+			if !strings.HasPrefix(module.ConnectionURL, "ldaps://") {
+				return fmt.Errorf("%v.trustStoreSecret only applies to an ldaps:// connectionURL", field)
+			}
+			// the truststore becomes the one of the broker jvm, there is room for a single one
+			if trustStoreSecret != "" && trustStoreSecret != *trustStore {
+				return fmt.Errorf("%v.trustStoreSecret %v differs from the trustStoreSecret %v of another ldap login module", field, *trustStore, trustStoreSecret)
+			}
+			trustStoreSecret = *trustStore
+		}
+	}
+	return nil
+}
+
+// validateKerberosLoginModules checks a module has the principal and keytab the broker accepts GSSAPI
+// contexts with, and that its JAAS entry doesn't clash with another entry of the login.config
+func (s *ActiveMQArtemisSecuritySpec) validateKerberosLoginModules() error {
+	entries := map[string]bool{}
+	for _, domain := range []BrokerDomainType{s.SecurityDomains.BrokerDomain, s.SecurityDomains.ConsoleDomain} {
+		if domain.Name != nil {
+			entries[*domain.Name] = true
+		}
+	}
+	if s.SecurityDomains.BrokerDomain.Name == nil || *s.SecurityDomains.BrokerDomain.Name == "" {
+		entries[DefaultBrokerDomain] = true
+	}
+	for _, module := range s.LoginModules.ScramLoginModules {
+		entries[module.Name] = true
+	}
+	for i, module := range s.LoginModules.KerberosLoginModules {
+		field := fmt.Sprintf("loginModules.kerberosLoginModules[%d]", i)
+		if module.Name == "" || strings.ContainsAny(module.Name, "{}; \t") {
+			return fmt.Errorf("%v.name %q must be a name without white space, braces or ;, it names the JAAS entry of the module", field, module.Name)
+		}
+		if entries[module.Name] {
+			return fmt.Errorf("%v.name %v is already the name of a security domain or another sasl login module", field, module.Name)
+		}
+		entries[module.Name] = true
+		if module.Principal == "" {
+			return fmt.Errorf("%v.principal can't be empty", field)
+		}
+		if module.KeytabSecret.Name == "" || module.KeytabSecret.Key == "" {
+			return fmt.Errorf("%v.keytabSecret needs a name and a key", field)
+		}
+		if krb5 := module.Krb5ConfConfigMap; krb5 != nil && (krb5.Name == "" || krb5.Key == "") {
+			return fmt.Errorf("%v.krb5ConfConfigMap needs a name and a key", field)
+		}
+		if module.Flag != nil {
+			switch *module.Flag {
+			case "required", "requisite", "sufficient", "optional":
+			default:
+				return fmt.Errorf("%v.flag %q must be required, requisite, sufficient or optional", field, *module.Flag)
+			}
+		}
+	}
+	return nil
+}
+
+// validate checks the canary rollout can log in with the credentials it names and waits a positive time
+func (c *SecurityCanaryType) validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.Enabled && (c.CredentialsSecret == nil || *c.CredentialsSecret == "") {
+		return fmt.Errorf("canary.credentialsSecret is required, the login check connects to an acceptor as its user")
+	}
+	if c.TimeoutSeconds != nil && *c.TimeoutSeconds <= 0 {
+		return fmt.Errorf("canary.timeoutSeconds %d must be positive", *c.TimeoutSeconds)
+	}
+	return nil
+}
+
+// validateSecurityDomains checks the domains reference the certificate and ldap login modules with
+// a valid flag, that every ldap module is referenced, and that a console domain taking such a module
+// is a domain of its own. The operator renders these modules into the domains that reference them
+func (s *ActiveMQArtemisSecuritySpec) validateSecurityDomains() error {
+	operatorModules := map[string]bool{}
+	for _, module := range s.LoginModules.CertificateLoginModules {
+		operatorModules[module.Name] = true
+	}
+	for _, module := range s.LoginModules.LdapLoginModules {
+		operatorModules[module.Name] = true
+	}
+	referenced := map[string]bool{}
+	consoleReferences := false
+	for _, field := range []string{"securityDomains.brokerDomain", "securityDomains.consoleDomain"} {
+		domain := s.SecurityDomains.BrokerDomain
+		if field == "securityDomains.consoleDomain" {
+			domain = s.SecurityDomains.ConsoleDomain
+		}
+		for i, reference := range domain.LoginModules {
+			if reference.Name == nil || !operatorModules[*reference.Name] {
+				continue
+			}
+			referenced[*reference.Name] = true
+			if field == "securityDomains.consoleDomain" {
+				consoleReferences = true
+			}
+			if reference.Flag != nil {
+				switch *reference.Flag {
+				case "required", "requisite", "sufficient", "optional":
+				default:
+					return fmt.Errorf("%v.loginModules[%d].flag %q must be required, requisite, sufficient or optional", field, i, *reference.Flag)
+				}
+			}
+		}
+	}
+	for i, module := range s.LoginModules.LdapLoginModules {
+		if !referenced[module.Name] {
+			return fmt.Errorf("loginModules.ldapLoginModules[%d] %v is not referenced by the brokerDomain or the consoleDomain", i, module.Name)
+		}
+	}
+	if consoleReferences {
+		brokerDomain, consoleDomain := DefaultBrokerDomain, ""
+		if name := s.SecurityDomains.BrokerDomain.Name; name != nil && *name != "" {
+			brokerDomain = *name
+		}
+		if name := s.SecurityDomains.ConsoleDomain.Name; name != nil {
+			consoleDomain = *name
+		}
+		if consoleDomain == "" || consoleDomain == brokerDomain {
+			return fmt.Errorf("securityDomains.consoleDomain.name must be set and differ from the broker domain %v to take its own login modules", brokerDomain)
+		}
+	}
+	return nil
+}
+
+// validateBrokerSecuritySettings checks the matches use the broker wildcards, that two settings don't
+// match the same addresses, where the broker keeps only one of them, and that the roles are known when
+// the login modules of the CR define every role
+func (s *ActiveMQArtemisSecuritySpec) validateBrokerSecuritySettings() error {
+	return s.validateChangedBrokerSecuritySettings(nil)
+}
+
+// validateChangedBrokerSecuritySettings only checks the settings that are not in the old spec, with
+// every other setting for matches of the same addresses
+func (s *ActiveMQArtemisSecuritySpec) validateChangedBrokerSecuritySettings(old *ActiveMQArtemisSecuritySpec) error {
+	knownRoles, rolesDefined := s.definedRoles()
+	settings := s.SecuritySettings.Broker
+	for i, setting := range settings {
+		if old != nil && containsBrokerSecuritySetting(old.SecuritySettings.Broker, setting) {
+			continue
+		}
+		field := fmt.Sprintf("securitySettings.broker[%d]", i)
+		if err := validateSecurityMatch(setting.Match); err != nil {
+			return fmt.Errorf("%v.match %v", field, err)
+		}
+		for j := range settings {
+			if j == i || (old == nil && j > i) {
+				continue
+			}
+			if SecurityMatchCovers(settings[j].Match, setting.Match) && SecurityMatchCovers(setting.Match, settings[j].Match) {
+				return fmt.Errorf("%v.match %q matches the same addresses as securitySettings.broker[%d].match %q, merge their permissions", field, setting.Match, j, settings[j].Match)
+			}
+		}
+		for j, permission := range setting.Permissions {
+			path := fmt.Sprintf("%v.permissions[%d]", field, j)
+			if !containsPermissionType(permission.OperationType) {
+				return fmt.Errorf("%v.operationType %q must be one of %v", path, permission.OperationType, strings.Join(BrokerPermissionTypes, ", "))
+			}
+			for _, role := range permission.Roles {
+				if strings.TrimSpace(role) == "" || strings.Contains(role, ",") {
+					return fmt.Errorf("%v.roles %q must be non-empty names without commas", path, role)
+				}
+				if rolesDefined && !knownRoles[role] {
+					return fmt.Errorf("%v.roles %v is not a role of a user of the login modules", path, role)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func containsBrokerSecuritySetting(settings []BrokerSecuritySettingType, setting BrokerSecuritySettingType) bool {
+	for _, candidate := range settings {
+		if reflect.DeepEqual(candidate, setting) {
+			return true
+		}
+	}
+	return false
+}
+
+// definedRoles returns the roles of the properties, certificate and guest login modules, the roles
+// are only all known when there are no other login modules, ldap ones read theirs from the directory. The operator adds its own users with the
+// admin role, which a broker requires the management authorisation to grant
+func (s *ActiveMQArtemisSecuritySpec) definedRoles() (map[string]bool, bool) {
+	modules := s.LoginModules
+	if len(modules.KeycloakLoginModules) > 0 || len(modules.ScramLoginModules) > 0 || len(modules.KerberosLoginModules) > 0 || len(modules.LdapLoginModules) > 0 {
+		return nil, false
+	}
+	if len(modules.PropertiesLoginModules) == 0 && len(modules.CertificateLoginModules) == 0 && len(modules.GuestLoginModules) == 0 {
+		return nil, false
+	}
+	roles := map[string]bool{}
+	for _, module := range modules.PropertiesLoginModules {
+		for _, user := range module.Users {
+			for _, role := range user.Roles {
+				roles[role] = true
+			}
+		}
+	}
+	for _, module := range modules.CertificateLoginModules {
+		for _, user := range module.Users {
+			for _, role := range user.Roles {
+				roles[role] = true
+			}
+		}
+	}
+	// the settings of a disabled guest module stay valid so that it can be enabled again
+	for _, module := range modules.GuestLoginModules {
+		if module.GuestRole != nil {
+			roles[*module.GuestRole] = true
+		} else {
+			roles[DefaultGuestRole] = true
+		}
+	}
+	management := s.SecuritySettings.Management
+	for _, role := range management.HawtioRoles {
+		roles[role] = true
+	}
+	for _, access := range management.Authorisation.DefaultAccess {
+		for _, role := range access.Roles {
+			roles[role] = true
+		}
+	}
+	for _, roleAccess := range management.Authorisation.RoleAccess {
+		for _, access := range roleAccess.AccessList {
+			for _, role := range access.Roles {
+				roles[role] = true
+			}
+		}
+	}
+	return roles, true
+}
+
+func containsPermissionType(operationType string) bool {
+	for _, permissionType := range BrokerPermissionTypes {
+		if permissionType == operationType {
+			return true
+		}
+	}
+	return false
+}
+
+// the words of a match are separated by dots, # matches any number of words and * matches one word
+func validateSecurityMatch(match string) error {
+	if match == "" {
+		return fmt.Errorf("can't be empty")
+	}
+	for _, word := range strings.Split(match, ".") {
+		if word == "" {
+			return fmt.Errorf("%q has an empty word, the words are separated by single dots", match)
+		}
+		if word != "#" && word != "*" && strings.ContainsAny(word, "#*") {
+			return fmt.Errorf("%q has the wildcard of %q within a word, # and * must be words of their own", match, word)
+		}
+	}
+	return nil
+}
+
+// SecurityMatchCovers tells if every address the specific match matches is also matched by the general match
+func SecurityMatchCovers(general string, specific string) bool {
+	return securityWordsCover(strings.Split(general, "."), strings.Split(specific, "."))
+}
+
+func securityWordsCover(general []string, specific []string) bool {
+	if len(general) == 0 {
+		return len(specific) == 0
+	}
+	switch general[0] {
+	case "#":
+		// no word or one more word of the specific match, which may itself be any number of words
+		return securityWordsCover(general[1:], specific) || (len(specific) > 0 && securityWordsCover(general, specific[1:]))
+	case "*":
+		return len(specific) > 0 && specific[0] != "#" && securityWordsCover(general[1:], specific[1:])
+	default:
+		return len(specific) > 0 && specific[0] == general[0] && securityWordsCover(general[1:], specific[1:])
+	}
+}
+
+// the denied entries are written into the Jolokia policy through the shell
+const managementUnsafeChars = "\"'`$\\<>&"
+
+// validate checks the authorisation entries name what they match and who they grant it to
+func (m *ManagementSecuritySettingsType) validate() error {
+	field := "securitySettings.management"
+	for i, role := range m.HawtioRoles {
+		if role == "" {
+			return fmt.Errorf("%v.hawtioRoles[%d] can't be empty", field, i)
+		}
+	}
+	authorisation := m.Authorisation
+	validateAccess := func(path string, access DefaultAccessType) error {
+		if access.Method == nil || *access.Method == "" {
+			return fmt.Errorf("%v.method can't be empty", path)
+		}
+		if len(access.Roles) == 0 {
+			return fmt.Errorf("%v.roles can't be empty", path)
+		}
+		return nil
+	}
+	for i, entry := range authorisation.AllowedList {
+		if entry.Domain == nil || *entry.Domain == "" {
+			return fmt.Errorf("%v.authorisation.allowedList[%d].domain can't be empty", field, i)
+		}
+	}
+	for i, access := range authorisation.DefaultAccess {
+		if err := validateAccess(fmt.Sprintf("%v.authorisation.defaultAccess[%d]", field, i), access); err != nil {
+			return err
+		}
+	}
+	for i, roleAccess := range authorisation.RoleAccess {
+		path := fmt.Sprintf("%v.authorisation.roleAccess[%d]", field, i)
+		if roleAccess.Domain == nil || *roleAccess.Domain == "" {
+			return fmt.Errorf("%v.domain can't be empty", path)
+		}
+		for j, access := range roleAccess.AccessList {
+			if err := validateAccess(fmt.Sprintf("%v.accessList[%d]", path, j), access); err != nil {
+				return err
+			}
+		}
+	}
+	for i, denied := range authorisation.DeniedList {
+		path := fmt.Sprintf("%v.authorisation.deniedList[%d]", field, i)
+		if denied.Domain == "" {
+			return fmt.Errorf("%v.domain can't be empty", path)
+		}
+		if len(denied.Operations) == 0 && len(denied.Attributes) == 0 {
+			return fmt.Errorf("%v needs operations or attributes to deny", path)
+		}
+		for _, value := range append(append([]string{denied.Domain, denied.Key}, denied.Operations...), denied.Attributes...) {
+			if strings.ContainsAny(value, managementUnsafeChars) {
+				return fmt.Errorf("%v %q can't contain quotes, $, \\, <, > or &", path, value)
+			}
+		}
+		if strings.Contains(denied.Domain, ":") {
+			return fmt.Errorf("%v.domain %q can't contain :", path, denied.Domain)
+		}
+	}
 	return nil
 }
 
@@ -28,9 +28,142 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AMQPConnectionStatus) DeepCopyInto(out *AMQPConnectionStatus) {
+	*out = *in
+	if in.Connected != nil {
+		in, out := &in.Connected, &out.Connected
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Disconnected != nil {
+		in, out := &in.Disconnected, &out.Disconnected
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AMQPConnectionStatus.
+func (in *AMQPConnectionStatus) DeepCopy() *AMQPConnectionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AMQPConnectionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AMQPConnectionType) DeepCopyInto(out *AMQPConnectionType) {
+	*out = *in
+	if in.RetryInterval != nil {
+		in, out := &in.RetryInterval, &out.RetryInterval
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ReconnectAttempts != nil {
+		in, out := &in.ReconnectAttempts, &out.ReconnectAttempts
+		*out = new(int32)
+		**out = **in
+	}
+	in.Mirror.DeepCopyInto(&out.Mirror)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AMQPConnectionType.
+func (in *AMQPConnectionType) DeepCopy() *AMQPConnectionType {
+	if in == nil {
+		return nil
+	}
+	out := new(AMQPConnectionType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AMQPMirrorType) DeepCopyInto(out *AMQPMirrorType) {
+	*out = *in
+	if in.MessageAcknowledgements != nil {
+		in, out := &in.MessageAcknowledgements, &out.MessageAcknowledgements
+		*out = new(bool)
+		**out = **in
+	}
+	if in.QueueCreation != nil {
+		in, out := &in.QueueCreation, &out.QueueCreation
+		*out = new(bool)
+		**out = **in
+	}
+	if in.QueueRemoval != nil {
+		in, out := &in.QueueRemoval, &out.QueueRemoval
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Durable != nil {
+		in, out := &in.Durable, &out.Durable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Sync != nil {
+		in, out := &in.Sync, &out.Sync
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AddressFilter != nil {
+		in, out := &in.AddressFilter, &out.AddressFilter
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AMQPMirrorType.
+func (in *AMQPMirrorType) DeepCopy() *AMQPMirrorType {
+	if in == nil {
+		return nil
+	}
+	out := new(AMQPMirrorType)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AcceptorType) DeepCopyInto(out *AcceptorType) {
 	*out = *in
+	if in.ExposeMode != nil {
+		in, out := &in.ExposeMode, &out.ExposeMode
+		*out = new(ExposeMode)
+		**out = **in
+	}
+	if in.NodePort != nil {
+		in, out := &in.NodePort, &out.NodePort
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CertificateIssuer != nil {
+		in, out := &in.CertificateIssuer, &out.CertificateIssuer
+		*out = new(CertificateIssuerType)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExposeAnnotations != nil {
+		in, out := &in.ExposeAnnotations, &out.ExposeAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ExternalDNS != nil {
+		in, out := &in.ExternalDNS, &out.ExternalDNS
+		*out = new(ExternalDNSType)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RouteTLS != nil {
+		in, out := &in.RouteTLS, &out.RouteTLS
+		*out = new(RouteTLSType)
+		**out = **in
+	}
+	if in.ServiceSettings != nil {
+		in, out := &in.ServiceSettings, &out.ServiceSettings
+		*out = new(ServiceSettingsType)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.SupportAdvisory != nil {
 		in, out := &in.SupportAdvisory, &out.SupportAdvisory
 		*out = new(bool)
@@ -46,6 +179,13 @@ func (in *AcceptorType) DeepCopyInto(out *AcceptorType) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Params != nil {
+		in, out := &in.Params, &out.Params
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AcceptorType.
@@ -91,7 +231,7 @@ func (in *ActiveMQArtemisAddress) DeepCopyInto(out *ActiveMQArtemisAddress) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisAddress.
@@ -145,118 +285,26 @@ func (in *ActiveMQArtemisAddressList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ActiveMQArtemisAddressSpec) DeepCopyInto(out *ActiveMQArtemisAddressSpec) {
-	*out = *in
-	if in.QueueName != nil {
-		in, out := &in.QueueName, &out.QueueName
-		*out = new(string)
-		**out = **in
-	}
-	if in.RoutingType != nil {
-		in, out := &in.RoutingType, &out.RoutingType
-		*out = new(string)
-		**out = **in
-	}
-	if in.User != nil {
-		in, out := &in.User, &out.User
-		*out = new(string)
-		**out = **in
-	}
-	if in.Password != nil {
-		in, out := &in.Password, &out.Password
-		*out = new(string)
-		**out = **in
-	}
-	if in.QueueConfiguration != nil {
-		in, out := &in.QueueConfiguration, &out.QueueConfiguration
-		*out = new(QueueConfigurationType)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.ApplyToCrNames != nil {
-		in, out := &in.ApplyToCrNames, &out.ApplyToCrNames
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisAddressSpec.
-func (in *ActiveMQArtemisAddressSpec) DeepCopy() *ActiveMQArtemisAddressSpec {
-	if in == nil {
-		return nil
-	}
-	out := new(ActiveMQArtemisAddressSpec)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ActiveMQArtemisAddressStatus) DeepCopyInto(out *ActiveMQArtemisAddressStatus) {
-	*out = *in
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisAddressStatus.
-func (in *ActiveMQArtemisAddressStatus) DeepCopy() *ActiveMQArtemisAddressStatus {
-	if in == nil {
-		return nil
-	}
-	out := new(ActiveMQArtemisAddressStatus)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ActiveMQArtemisList) DeepCopyInto(out *ActiveMQArtemisList) {
-	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]ActiveMQArtemis, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisList.
-func (in *ActiveMQArtemisList) DeepCopy() *ActiveMQArtemisList {
-	if in == nil {
-		return nil
-	}
-	out := new(ActiveMQArtemisList)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ActiveMQArtemisList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ActiveMQArtemisScaledown) DeepCopyInto(out *ActiveMQArtemisScaledown) {
+func (in *ActiveMQArtemisAddressSet) DeepCopyInto(out *ActiveMQArtemisAddressSet) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisScaledown.
-func (in *ActiveMQArtemisScaledown) DeepCopy() *ActiveMQArtemisScaledown {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisAddressSet.
+func (in *ActiveMQArtemisAddressSet) DeepCopy() *ActiveMQArtemisAddressSet {
 	if in == nil {
 		return nil
 	}
-	out := new(ActiveMQArtemisScaledown)
+	out := new(ActiveMQArtemisAddressSet)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ActiveMQArtemisScaledown) DeepCopyObject() runtime.Object {
+func (in *ActiveMQArtemisAddressSet) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -264,31 +312,31 @@ func (in *ActiveMQArtemisScaledown) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ActiveMQArtemisScaledownList) DeepCopyInto(out *ActiveMQArtemisScaledownList) {
+func (in *ActiveMQArtemisAddressSetList) DeepCopyInto(out *ActiveMQArtemisAddressSetList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]ActiveMQArtemisScaledown, len(*in))
+		*out = make([]ActiveMQArtemisAddressSet, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisScaledownList.
-func (in *ActiveMQArtemisScaledownList) DeepCopy() *ActiveMQArtemisScaledownList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisAddressSetList.
+func (in *ActiveMQArtemisAddressSetList) DeepCopy() *ActiveMQArtemisAddressSetList {
 	if in == nil {
 		return nil
 	}
-	out := new(ActiveMQArtemisScaledownList)
+	out := new(ActiveMQArtemisAddressSetList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ActiveMQArtemisScaledownList) DeepCopyObject() runtime.Object {
+func (in *ActiveMQArtemisAddressSetList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -296,57 +344,93 @@ func (in *ActiveMQArtemisScaledownList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ActiveMQArtemisScaledownSpec) DeepCopyInto(out *ActiveMQArtemisScaledownSpec) {
+func (in *ActiveMQArtemisAddressSetSpec) DeepCopyInto(out *ActiveMQArtemisAddressSetSpec) {
 	*out = *in
-	in.Resources.DeepCopyInto(&out.Resources)
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.BatchSize != nil {
+		in, out := &in.BatchSize, &out.BatchSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ApplyToCrNames != nil {
+		in, out := &in.ApplyToCrNames, &out.ApplyToCrNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ApplyToCrSelector != nil {
+		in, out := &in.ApplyToCrSelector, &out.ApplyToCrSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisScaledownSpec.
-func (in *ActiveMQArtemisScaledownSpec) DeepCopy() *ActiveMQArtemisScaledownSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisAddressSetSpec.
+func (in *ActiveMQArtemisAddressSetSpec) DeepCopy() *ActiveMQArtemisAddressSetSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ActiveMQArtemisScaledownSpec)
+	out := new(ActiveMQArtemisAddressSetSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ActiveMQArtemisScaledownStatus) DeepCopyInto(out *ActiveMQArtemisScaledownStatus) {
+func (in *ActiveMQArtemisAddressSetStatus) DeepCopyInto(out *ActiveMQArtemisAddressSetStatus) {
 	*out = *in
+	if in.Applied != nil {
+		in, out := &in.Applied, &out.Applied
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AppliedPods != nil {
+		in, out := &in.AppliedPods, &out.AppliedPods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisScaledownStatus.
-func (in *ActiveMQArtemisScaledownStatus) DeepCopy() *ActiveMQArtemisScaledownStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisAddressSetStatus.
+func (in *ActiveMQArtemisAddressSetStatus) DeepCopy() *ActiveMQArtemisAddressSetStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ActiveMQArtemisScaledownStatus)
+	out := new(ActiveMQArtemisAddressSetStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ActiveMQArtemisSecurity) DeepCopyInto(out *ActiveMQArtemisSecurity) {
+func (in *ActiveMQArtemisAddressSettings) DeepCopyInto(out *ActiveMQArtemisAddressSettings) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisSecurity.
-func (in *ActiveMQArtemisSecurity) DeepCopy() *ActiveMQArtemisSecurity {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisAddressSettings.
+func (in *ActiveMQArtemisAddressSettings) DeepCopy() *ActiveMQArtemisAddressSettings {
 	if in == nil {
 		return nil
 	}
-	out := new(ActiveMQArtemisSecurity)
+	out := new(ActiveMQArtemisAddressSettings)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ActiveMQArtemisSecurity) DeepCopyObject() runtime.Object {
+func (in *ActiveMQArtemisAddressSettings) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -354,31 +438,31 @@ func (in *ActiveMQArtemisSecurity) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ActiveMQArtemisSecurityList) DeepCopyInto(out *ActiveMQArtemisSecurityList) {
+func (in *ActiveMQArtemisAddressSettingsList) DeepCopyInto(out *ActiveMQArtemisAddressSettingsList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]ActiveMQArtemisSecurity, len(*in))
+		*out = make([]ActiveMQArtemisAddressSettings, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisSecurityList.
-func (in *ActiveMQArtemisSecurityList) DeepCopy() *ActiveMQArtemisSecurityList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisAddressSettingsList.
+func (in *ActiveMQArtemisAddressSettingsList) DeepCopy() *ActiveMQArtemisAddressSettingsList {
 	if in == nil {
 		return nil
 	}
-	out := new(ActiveMQArtemisSecurityList)
+	out := new(ActiveMQArtemisAddressSettingsList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ActiveMQArtemisSecurityList) DeepCopyObject() runtime.Object {
+func (in *ActiveMQArtemisAddressSettingsList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -386,11 +470,15 @@ func (in *ActiveMQArtemisSecurityList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ActiveMQArtemisSecuritySpec) DeepCopyInto(out *ActiveMQArtemisSecuritySpec) {
+func (in *ActiveMQArtemisAddressSettingsSpec) DeepCopyInto(out *ActiveMQArtemisAddressSettingsSpec) {
 	*out = *in
-	in.LoginModules.DeepCopyInto(&out.LoginModules)
-	in.SecurityDomains.DeepCopyInto(&out.SecurityDomains)
-	in.SecuritySettings.DeepCopyInto(&out.SecuritySettings)
+	if in.AddressSetting != nil {
+		in, out := &in.AddressSetting, &out.AddressSetting
+		*out = make([]AddressSettingType, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.ApplyToCrNames != nil {
 		in, out := &in.ApplyToCrNames, &out.ApplyToCrNames
 		*out = make([]string, len(*in))
@@ -398,77 +486,130 @@ func (in *ActiveMQArtemisSecuritySpec) DeepCopyInto(out *ActiveMQArtemisSecurity
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisSecuritySpec.
-func (in *ActiveMQArtemisSecuritySpec) DeepCopy() *ActiveMQArtemisSecuritySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisAddressSettingsSpec.
+func (in *ActiveMQArtemisAddressSettingsSpec) DeepCopy() *ActiveMQArtemisAddressSettingsSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ActiveMQArtemisSecuritySpec)
+	out := new(ActiveMQArtemisAddressSettingsSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ActiveMQArtemisSecurityStatus) DeepCopyInto(out *ActiveMQArtemisSecurityStatus) {
+func (in *ActiveMQArtemisAddressSettingsStatus) DeepCopyInto(out *ActiveMQArtemisAddressSettingsStatus) {
 	*out = *in
-}
+	if in.Matches != nil {
+		in, out := &in.Matches, &out.Matches
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AppliedPods != nil {
+		in, out := &in.AppliedPods, &out.AppliedPods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisSecurityStatus.
-func (in *ActiveMQArtemisSecurityStatus) DeepCopy() *ActiveMQArtemisSecurityStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisAddressSettingsStatus.
+func (in *ActiveMQArtemisAddressSettingsStatus) DeepCopy() *ActiveMQArtemisAddressSettingsStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ActiveMQArtemisSecurityStatus)
+	out := new(ActiveMQArtemisAddressSettingsStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ActiveMQArtemisSpec) DeepCopyInto(out *ActiveMQArtemisSpec) {
+func (in *ActiveMQArtemisAddressSpec) DeepCopyInto(out *ActiveMQArtemisAddressSpec) {
 	*out = *in
-	in.DeploymentPlan.DeepCopyInto(&out.DeploymentPlan)
-	if in.Acceptors != nil {
-		in, out := &in.Acceptors, &out.Acceptors
-		*out = make([]AcceptorType, len(*in))
+	if in.QueueName != nil {
+		in, out := &in.QueueName, &out.QueueName
+		*out = new(string)
+		**out = **in
+	}
+	if in.RoutingType != nil {
+		in, out := &in.RoutingType, &out.RoutingType
+		*out = new(string)
+		**out = **in
+	}
+	if in.User != nil {
+		in, out := &in.User, &out.User
+		*out = new(string)
+		**out = **in
+	}
+	if in.Password != nil {
+		in, out := &in.Password, &out.Password
+		*out = new(string)
+		**out = **in
+	}
+	if in.QueueConfiguration != nil {
+		in, out := &in.QueueConfiguration, &out.QueueConfiguration
+		*out = new(QueueConfigurationType)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Queues != nil {
+		in, out := &in.Queues, &out.Queues
+		*out = make([]AddressQueueType, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.Connectors != nil {
-		in, out := &in.Connectors, &out.Connectors
-		*out = make([]ConnectorType, len(*in))
-		copy(*out, *in)
-	}
-	out.Console = in.Console
-	out.Upgrades = in.Upgrades
-	in.AddressSettings.DeepCopyInto(&out.AddressSettings)
-	if in.BrokerProperties != nil {
-		in, out := &in.BrokerProperties, &out.BrokerProperties
+	if in.ApplyToCrNames != nil {
+		in, out := &in.ApplyToCrNames, &out.ApplyToCrNames
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.Env != nil {
-		in, out := &in.Env, &out.Env
-		*out = make([]v1.EnvVar, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.ApplyToCrSelector != nil {
+		in, out := &in.ApplyToCrSelector, &out.ApplyToCrSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Redelivery != nil {
+		in, out := &in.Redelivery, &out.Redelivery
+		*out = new(RedeliveryPolicyType)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeadLetterAndExpiry != nil {
+		in, out := &in.DeadLetterAndExpiry, &out.DeadLetterAndExpiry
+		*out = new(DeadLetterAndExpiryType)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisSpec.
-func (in *ActiveMQArtemisSpec) DeepCopy() *ActiveMQArtemisSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisAddressSpec.
+func (in *ActiveMQArtemisAddressSpec) DeepCopy() *ActiveMQArtemisAddressSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ActiveMQArtemisSpec)
+	out := new(ActiveMQArtemisAddressSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ActiveMQArtemisStatus) DeepCopyInto(out *ActiveMQArtemisStatus) {
+func (in *ActiveMQArtemisAddressStatus) DeepCopyInto(out *ActiveMQArtemisAddressStatus) {
 	*out = *in
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Pods != nil {
+		in, out := &in.Pods, &out.Pods
+		*out = make([]AddressPodStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]metav1.Condition, len(*in))
@@ -476,832 +617,2980 @@ func (in *ActiveMQArtemisStatus) DeepCopyInto(out *ActiveMQArtemisStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	in.PodStatus.DeepCopyInto(&out.PodStatus)
-	if in.ExternalConfigs != nil {
-		in, out := &in.ExternalConfigs, &out.ExternalConfigs
-		*out = make([]ExternalConfigStatus, len(*in))
-		copy(*out, *in)
-	}
-	out.Version = in.Version
-	out.Upgrade = in.Upgrade
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisStatus.
-func (in *ActiveMQArtemisStatus) DeepCopy() *ActiveMQArtemisStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisAddressStatus.
+func (in *ActiveMQArtemisAddressStatus) DeepCopy() *ActiveMQArtemisAddressStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ActiveMQArtemisStatus)
+	out := new(ActiveMQArtemisAddressStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ActiveMQArtemisUpgrades) DeepCopyInto(out *ActiveMQArtemisUpgrades) {
+func (in *ActiveMQArtemisBridge) DeepCopyInto(out *ActiveMQArtemisBridge) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisUpgrades.
-func (in *ActiveMQArtemisUpgrades) DeepCopy() *ActiveMQArtemisUpgrades {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisBridge.
+func (in *ActiveMQArtemisBridge) DeepCopy() *ActiveMQArtemisBridge {
 	if in == nil {
 		return nil
 	}
-	out := new(ActiveMQArtemisUpgrades)
+	out := new(ActiveMQArtemisBridge)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ActiveMQArtemisBridge) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AddressSettingType) DeepCopyInto(out *AddressSettingType) {
+func (in *ActiveMQArtemisBridgeList) DeepCopyInto(out *ActiveMQArtemisBridgeList) {
 	*out = *in
-	if in.DeadLetterAddress != nil {
-		in, out := &in.DeadLetterAddress, &out.DeadLetterAddress
-		*out = new(string)
-		**out = **in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ActiveMQArtemisBridge, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.AutoCreateDeadLetterResources != nil {
-		in, out := &in.AutoCreateDeadLetterResources, &out.AutoCreateDeadLetterResources
-		*out = new(bool)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisBridgeList.
+func (in *ActiveMQArtemisBridgeList) DeepCopy() *ActiveMQArtemisBridgeList {
+	if in == nil {
+		return nil
 	}
-	if in.DeadLetterQueuePrefix != nil {
-		in, out := &in.DeadLetterQueuePrefix, &out.DeadLetterQueuePrefix
-		*out = new(string)
-		**out = **in
+	out := new(ActiveMQArtemisBridgeList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ActiveMQArtemisBridgeList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
-	if in.DeadLetterQueueSuffix != nil {
-		in, out := &in.DeadLetterQueueSuffix, &out.DeadLetterQueueSuffix
-		*out = new(string)
-		**out = **in
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActiveMQArtemisBridgeSpec) DeepCopyInto(out *ActiveMQArtemisBridgeSpec) {
+	*out = *in
+	out.Target = in.Target
+	if in.ReconnectPolicy != nil {
+		in, out := &in.ReconnectPolicy, &out.ReconnectPolicy
+		*out = new(BridgeReconnectPolicyType)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.ExpiryAddress != nil {
-		in, out := &in.ExpiryAddress, &out.ExpiryAddress
-		*out = new(string)
+	if in.ProducerWindowSize != nil {
+		in, out := &in.ProducerWindowSize, &out.ProducerWindowSize
+		*out = new(int32)
 		**out = **in
 	}
-	if in.AutoCreateExpiryResources != nil {
-		in, out := &in.AutoCreateExpiryResources, &out.AutoCreateExpiryResources
+	if in.UseDuplicateDetection != nil {
+		in, out := &in.UseDuplicateDetection, &out.UseDuplicateDetection
 		*out = new(bool)
 		**out = **in
 	}
-	if in.ExpiryQueuePrefix != nil {
-		in, out := &in.ExpiryQueuePrefix, &out.ExpiryQueuePrefix
-		*out = new(string)
-		**out = **in
+	if in.ApplyToCrNames != nil {
+		in, out := &in.ApplyToCrNames, &out.ApplyToCrNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.ExpiryQueueSuffix != nil {
-		in, out := &in.ExpiryQueueSuffix, &out.ExpiryQueueSuffix
-		*out = new(string)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisBridgeSpec.
+func (in *ActiveMQArtemisBridgeSpec) DeepCopy() *ActiveMQArtemisBridgeSpec {
+	if in == nil {
+		return nil
 	}
-	if in.ExpiryDelay != nil {
-		in, out := &in.ExpiryDelay, &out.ExpiryDelay
-		*out = new(int32)
-		**out = **in
+	out := new(ActiveMQArtemisBridgeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActiveMQArtemisBridgeStatus) DeepCopyInto(out *ActiveMQArtemisBridgeStatus) {
+	*out = *in
+	if in.AppliedPods != nil {
+		in, out := &in.AppliedPods, &out.AppliedPods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.MinExpiryDelay != nil {
-		in, out := &in.MinExpiryDelay, &out.MinExpiryDelay
-		*out = new(int32)
-		**out = **in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.MaxExpiryDelay != nil {
-		in, out := &in.MaxExpiryDelay, &out.MaxExpiryDelay
-		*out = new(int32)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisBridgeStatus.
+func (in *ActiveMQArtemisBridgeStatus) DeepCopy() *ActiveMQArtemisBridgeStatus {
+	if in == nil {
+		return nil
 	}
-	if in.RedeliveryDelay != nil {
-		in, out := &in.RedeliveryDelay, &out.RedeliveryDelay
-		*out = new(int32)
-		**out = **in
+	out := new(ActiveMQArtemisBridgeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActiveMQArtemisDivert) DeepCopyInto(out *ActiveMQArtemisDivert) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisDivert.
+func (in *ActiveMQArtemisDivert) DeepCopy() *ActiveMQArtemisDivert {
+	if in == nil {
+		return nil
 	}
-	if in.MaxRedeliveryDelay != nil {
-		in, out := &in.MaxRedeliveryDelay, &out.MaxRedeliveryDelay
-		*out = new(int32)
-		**out = **in
+	out := new(ActiveMQArtemisDivert)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ActiveMQArtemisDivert) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
-	if in.MaxDeliveryAttempts != nil {
-		in, out := &in.MaxDeliveryAttempts, &out.MaxDeliveryAttempts
-		*out = new(int32)
-		**out = **in
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActiveMQArtemisDivertList) DeepCopyInto(out *ActiveMQArtemisDivertList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ActiveMQArtemisDivert, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.MaxSizeBytes != nil {
-		in, out := &in.MaxSizeBytes, &out.MaxSizeBytes
-		*out = new(string)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisDivertList.
+func (in *ActiveMQArtemisDivertList) DeepCopy() *ActiveMQArtemisDivertList {
+	if in == nil {
+		return nil
+	}
+	out := new(ActiveMQArtemisDivertList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ActiveMQArtemisDivertList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActiveMQArtemisDivertSpec) DeepCopyInto(out *ActiveMQArtemisDivertSpec) {
+	*out = *in
+	if in.TransformerProperties != nil {
+		in, out := &in.TransformerProperties, &out.TransformerProperties
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ApplyToCrNames != nil {
+		in, out := &in.ApplyToCrNames, &out.ApplyToCrNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisDivertSpec.
+func (in *ActiveMQArtemisDivertSpec) DeepCopy() *ActiveMQArtemisDivertSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ActiveMQArtemisDivertSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActiveMQArtemisDivertStatus) DeepCopyInto(out *ActiveMQArtemisDivertStatus) {
+	*out = *in
+	if in.AppliedPods != nil {
+		in, out := &in.AppliedPods, &out.AppliedPods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisDivertStatus.
+func (in *ActiveMQArtemisDivertStatus) DeepCopy() *ActiveMQArtemisDivertStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ActiveMQArtemisDivertStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActiveMQArtemisFleet) DeepCopyInto(out *ActiveMQArtemisFleet) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisFleet.
+func (in *ActiveMQArtemisFleet) DeepCopy() *ActiveMQArtemisFleet {
+	if in == nil {
+		return nil
+	}
+	out := new(ActiveMQArtemisFleet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ActiveMQArtemisFleet) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActiveMQArtemisFleetBrokerStatus) DeepCopyInto(out *ActiveMQArtemisFleetBrokerStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisFleetBrokerStatus.
+func (in *ActiveMQArtemisFleetBrokerStatus) DeepCopy() *ActiveMQArtemisFleetBrokerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ActiveMQArtemisFleetBrokerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActiveMQArtemisFleetList) DeepCopyInto(out *ActiveMQArtemisFleetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ActiveMQArtemisFleet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisFleetList.
+func (in *ActiveMQArtemisFleetList) DeepCopy() *ActiveMQArtemisFleetList {
+	if in == nil {
+		return nil
+	}
+	out := new(ActiveMQArtemisFleetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ActiveMQArtemisFleetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActiveMQArtemisFleetOverride) DeepCopyInto(out *ActiveMQArtemisFleetOverride) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Spec != nil {
+		in, out := &in.Spec, &out.Spec
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisFleetOverride.
+func (in *ActiveMQArtemisFleetOverride) DeepCopy() *ActiveMQArtemisFleetOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(ActiveMQArtemisFleetOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActiveMQArtemisFleetSpec) DeepCopyInto(out *ActiveMQArtemisFleetSpec) {
+	*out = *in
+	in.Template.DeepCopyInto(&out.Template)
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]ActiveMQArtemisFleetOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisFleetSpec.
+func (in *ActiveMQArtemisFleetSpec) DeepCopy() *ActiveMQArtemisFleetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ActiveMQArtemisFleetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActiveMQArtemisFleetStatus) DeepCopyInto(out *ActiveMQArtemisFleetStatus) {
+	*out = *in
+	if in.Brokers != nil {
+		in, out := &in.Brokers, &out.Brokers
+		*out = make([]ActiveMQArtemisFleetBrokerStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisFleetStatus.
+func (in *ActiveMQArtemisFleetStatus) DeepCopy() *ActiveMQArtemisFleetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ActiveMQArtemisFleetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActiveMQArtemisFleetTemplate) DeepCopyInto(out *ActiveMQArtemisFleetTemplate) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisFleetTemplate.
+func (in *ActiveMQArtemisFleetTemplate) DeepCopy() *ActiveMQArtemisFleetTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ActiveMQArtemisFleetTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActiveMQArtemisList) DeepCopyInto(out *ActiveMQArtemisList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ActiveMQArtemis, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisList.
+func (in *ActiveMQArtemisList) DeepCopy() *ActiveMQArtemisList {
+	if in == nil {
+		return nil
+	}
+	out := new(ActiveMQArtemisList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ActiveMQArtemisList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActiveMQArtemisQueueMigration) DeepCopyInto(out *ActiveMQArtemisQueueMigration) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisQueueMigration.
+func (in *ActiveMQArtemisQueueMigration) DeepCopy() *ActiveMQArtemisQueueMigration {
+	if in == nil {
+		return nil
+	}
+	out := new(ActiveMQArtemisQueueMigration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ActiveMQArtemisQueueMigration) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActiveMQArtemisQueueMigrationList) DeepCopyInto(out *ActiveMQArtemisQueueMigrationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ActiveMQArtemisQueueMigration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisQueueMigrationList.
+func (in *ActiveMQArtemisQueueMigrationList) DeepCopy() *ActiveMQArtemisQueueMigrationList {
+	if in == nil {
+		return nil
+	}
+	out := new(ActiveMQArtemisQueueMigrationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ActiveMQArtemisQueueMigrationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActiveMQArtemisQueueMigrationSpec) DeepCopyInto(out *ActiveMQArtemisQueueMigrationSpec) {
+	*out = *in
+	in.Source.DeepCopyInto(&out.Source)
+	in.Target.DeepCopyInto(&out.Target)
+	if in.ProducerWindowSize != nil {
+		in, out := &in.ProducerWindowSize, &out.ProducerWindowSize
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisQueueMigrationSpec.
+func (in *ActiveMQArtemisQueueMigrationSpec) DeepCopy() *ActiveMQArtemisQueueMigrationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ActiveMQArtemisQueueMigrationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActiveMQArtemisQueueMigrationStatus) DeepCopyInto(out *ActiveMQArtemisQueueMigrationStatus) {
+	*out = *in
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = make([]QueueMigrationSourceStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisQueueMigrationStatus.
+func (in *ActiveMQArtemisQueueMigrationStatus) DeepCopy() *ActiveMQArtemisQueueMigrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ActiveMQArtemisQueueMigrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActiveMQArtemisScaledown) DeepCopyInto(out *ActiveMQArtemisScaledown) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisScaledown.
+func (in *ActiveMQArtemisScaledown) DeepCopy() *ActiveMQArtemisScaledown {
+	if in == nil {
+		return nil
+	}
+	out := new(ActiveMQArtemisScaledown)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ActiveMQArtemisScaledown) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActiveMQArtemisScaledownList) DeepCopyInto(out *ActiveMQArtemisScaledownList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ActiveMQArtemisScaledown, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisScaledownList.
+func (in *ActiveMQArtemisScaledownList) DeepCopy() *ActiveMQArtemisScaledownList {
+	if in == nil {
+		return nil
+	}
+	out := new(ActiveMQArtemisScaledownList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ActiveMQArtemisScaledownList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActiveMQArtemisScaledownSpec) DeepCopyInto(out *ActiveMQArtemisScaledownSpec) {
+	*out = *in
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.Drainer != nil {
+		in, out := &in.Drainer, &out.Drainer
+		*out = new(DrainerType)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisScaledownSpec.
+func (in *ActiveMQArtemisScaledownSpec) DeepCopy() *ActiveMQArtemisScaledownSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ActiveMQArtemisScaledownSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActiveMQArtemisScaledownStatus) DeepCopyInto(out *ActiveMQArtemisScaledownStatus) {
+	*out = *in
+	if in.Drains != nil {
+		in, out := &in.Drains, &out.Drains
+		*out = make([]ScaledownDrainStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisScaledownStatus.
+func (in *ActiveMQArtemisScaledownStatus) DeepCopy() *ActiveMQArtemisScaledownStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ActiveMQArtemisScaledownStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActiveMQArtemisSecurity) DeepCopyInto(out *ActiveMQArtemisSecurity) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisSecurity.
+func (in *ActiveMQArtemisSecurity) DeepCopy() *ActiveMQArtemisSecurity {
+	if in == nil {
+		return nil
+	}
+	out := new(ActiveMQArtemisSecurity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ActiveMQArtemisSecurity) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActiveMQArtemisSecurityList) DeepCopyInto(out *ActiveMQArtemisSecurityList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ActiveMQArtemisSecurity, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisSecurityList.
+func (in *ActiveMQArtemisSecurityList) DeepCopy() *ActiveMQArtemisSecurityList {
+	if in == nil {
+		return nil
+	}
+	out := new(ActiveMQArtemisSecurityList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ActiveMQArtemisSecurityList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActiveMQArtemisSecuritySpec) DeepCopyInto(out *ActiveMQArtemisSecuritySpec) {
+	*out = *in
+	in.LoginModules.DeepCopyInto(&out.LoginModules)
+	in.SecurityDomains.DeepCopyInto(&out.SecurityDomains)
+	in.SecuritySettings.DeepCopyInto(&out.SecuritySettings)
+	if in.ApplyToCrNames != nil {
+		in, out := &in.ApplyToCrNames, &out.ApplyToCrNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ApplyToCrSelector != nil {
+		in, out := &in.ApplyToCrSelector, &out.ApplyToCrSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(SecurityCanaryType)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PasswordMasking != nil {
+		in, out := &in.PasswordMasking, &out.PasswordMasking
+		*out = new(PasswordMaskingType)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisSecuritySpec.
+func (in *ActiveMQArtemisSecuritySpec) DeepCopy() *ActiveMQArtemisSecuritySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ActiveMQArtemisSecuritySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActiveMQArtemisSecurityStatus) DeepCopyInto(out *ActiveMQArtemisSecurityStatus) {
+	*out = *in
+	if in.Brokers != nil {
+		in, out := &in.Brokers, &out.Brokers
+		*out = make([]SecurityBrokerStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisSecurityStatus.
+func (in *ActiveMQArtemisSecurityStatus) DeepCopy() *ActiveMQArtemisSecurityStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ActiveMQArtemisSecurityStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActiveMQArtemisSpec) DeepCopyInto(out *ActiveMQArtemisSpec) {
+	*out = *in
+	if in.HawtioRoles != nil {
+		in, out := &in.HawtioRoles, &out.HawtioRoles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.DeploymentPlan.DeepCopyInto(&out.DeploymentPlan)
+	if in.Acceptors != nil {
+		in, out := &in.Acceptors, &out.Acceptors
+		*out = make([]AcceptorType, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Connectors != nil {
+		in, out := &in.Connectors, &out.Connectors
+		*out = make([]ConnectorType, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Console.DeepCopyInto(&out.Console)
+	out.Upgrades = in.Upgrades
+	in.AddressSettings.DeepCopyInto(&out.AddressSettings)
+	if in.BrokerProperties != nil {
+		in, out := &in.BrokerProperties, &out.BrokerProperties
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.OrdinalBrokerProperties != nil {
+		in, out := &in.OrdinalBrokerProperties, &out.OrdinalBrokerProperties
+		*out = make([]OrdinalBrokerPropertiesType, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.BrokerXmlConfigMap != nil {
+		in, out := &in.BrokerXmlConfigMap, &out.BrokerXmlConfigMap
+		*out = new(v1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CertificateIssuer != nil {
+		in, out := &in.CertificateIssuer, &out.CertificateIssuer
+		*out = new(CertificateIssuerType)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClusterTLS != nil {
+		in, out := &in.ClusterTLS, &out.ClusterTLS
+		*out = new(ClusterTLSType)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(ProxyType)
+		**out = **in
+	}
+	if in.Logging != nil {
+		in, out := &in.Logging, &out.Logging
+		*out = new(LoggingType)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReservedAddressPrefixes != nil {
+		in, out := &in.ReservedAddressPrefixes, &out.ReservedAddressPrefixes
+		*out = new(ReservedAddressPrefixesType)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HeadlessService != nil {
+		in, out := &in.HeadlessService, &out.HeadlessService
+		*out = new(HeadlessServiceType)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RetentionPolicies != nil {
+		in, out := &in.RetentionPolicies, &out.RetentionPolicies
+		*out = make([]RetentionPolicyType, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.IPFamilies != nil {
+		in, out := &in.IPFamilies, &out.IPFamilies
+		*out = make([]v1.IPFamily, len(*in))
+		copy(*out, *in)
+	}
+	if in.IPFamilyPolicy != nil {
+		in, out := &in.IPFamilyPolicy, &out.IPFamilyPolicy
+		*out = new(v1.IPFamilyPolicyType)
+		**out = **in
+	}
+	if in.ClusterCredentialRotation != nil {
+		in, out := &in.ClusterCredentialRotation, &out.ClusterCredentialRotation
+		*out = new(CredentialRotationType)
+		**out = **in
+	}
+	if in.AdminCredentialRotation != nil {
+		in, out := &in.AdminCredentialRotation, &out.AdminCredentialRotation
+		*out = new(CredentialRotationType)
+		**out = **in
+	}
+	if in.ServiceMesh != nil {
+		in, out := &in.ServiceMesh, &out.ServiceMesh
+		*out = new(ServiceMeshType)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Redelivery != nil {
+		in, out := &in.Redelivery, &out.Redelivery
+		*out = new(RedeliveryDefaultsType)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CredentialsSource != nil {
+		in, out := &in.CredentialsSource, &out.CredentialsSource
+		*out = new(CredentialsSourceType)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(AutoscalingType)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HA != nil {
+		in, out := &in.HA, &out.HA
+		*out = new(HAType)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ZoneAwareness != nil {
+		in, out := &in.ZoneAwareness, &out.ZoneAwareness
+		*out = new(ZoneAwarenessType)
+		**out = **in
+	}
+	if in.Federations != nil {
+		in, out := &in.Federations, &out.Federations
+		*out = make([]FederationType, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AMQPConnections != nil {
+		in, out := &in.AMQPConnections, &out.AMQPConnections
+		*out = make([]AMQPConnectionType, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ClusterConnection != nil {
+		in, out := &in.ClusterConnection, &out.ClusterConnection
+		*out = new(ClusterConnectionType)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClusterMesh != nil {
+		in, out := &in.ClusterMesh, &out.ClusterMesh
+		*out = new(ClusterMeshType)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisSpec.
+func (in *ActiveMQArtemisSpec) DeepCopy() *ActiveMQArtemisSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ActiveMQArtemisSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActiveMQArtemisStatus) DeepCopyInto(out *ActiveMQArtemisStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.PodStatus.DeepCopyInto(&out.PodStatus)
+	if in.ExternalConfigs != nil {
+		in, out := &in.ExternalConfigs, &out.ExternalConfigs
+		*out = make([]ExternalConfigStatus, len(*in))
+		copy(*out, *in)
+	}
+	out.Version = in.Version
+	out.Upgrade = in.Upgrade
+	if in.ExternalEndpoints != nil {
+		in, out := &in.ExternalEndpoints, &out.ExternalEndpoints
+		*out = make([]ExternalEndpointStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClusterCredentialRotation != nil {
+		in, out := &in.ClusterCredentialRotation, &out.ClusterCredentialRotation
+		*out = new(CredentialRotationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdminCredentialRotation != nil {
+		in, out := &in.AdminCredentialRotation, &out.AdminCredentialRotation
+		*out = new(CredentialRotationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityCanary != nil {
+		in, out := &in.SecurityCanary, &out.SecurityCanary
+		*out = new(SecurityCanaryStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Security != nil {
+		in, out := &in.Security, &out.Security
+		*out = new(SecurityRenderStatus)
+		**out = **in
+	}
+	if in.MessageMigration != nil {
+		in, out := &in.MessageMigration, &out.MessageMigration
+		*out = make([]ScaledownDrainStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.HA != nil {
+		in, out := &in.HA, &out.HA
+		*out = make([]HAPairStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]ZoneStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AMQPConnections != nil {
+		in, out := &in.AMQPConnections, &out.AMQPConnections
+		*out = make([]AMQPConnectionStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisStatus.
+func (in *ActiveMQArtemisStatus) DeepCopy() *ActiveMQArtemisStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ActiveMQArtemisStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActiveMQArtemisUpgrades) DeepCopyInto(out *ActiveMQArtemisUpgrades) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisUpgrades.
+func (in *ActiveMQArtemisUpgrades) DeepCopy() *ActiveMQArtemisUpgrades {
+	if in == nil {
+		return nil
+	}
+	out := new(ActiveMQArtemisUpgrades)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AddressPodStatus) DeepCopyInto(out *AddressPodStatus) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddressPodStatus.
+func (in *AddressPodStatus) DeepCopy() *AddressPodStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AddressPodStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AddressQueueType) DeepCopyInto(out *AddressQueueType) {
+	*out = *in
+	in.QueueConfigurationType.DeepCopyInto(&out.QueueConfigurationType)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddressQueueType.
+func (in *AddressQueueType) DeepCopy() *AddressQueueType {
+	if in == nil {
+		return nil
+	}
+	out := new(AddressQueueType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AddressSettingType) DeepCopyInto(out *AddressSettingType) {
+	*out = *in
+	if in.DeadLetterAddress != nil {
+		in, out := &in.DeadLetterAddress, &out.DeadLetterAddress
+		*out = new(string)
+		**out = **in
+	}
+	if in.AutoCreateDeadLetterResources != nil {
+		in, out := &in.AutoCreateDeadLetterResources, &out.AutoCreateDeadLetterResources
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DeadLetterQueuePrefix != nil {
+		in, out := &in.DeadLetterQueuePrefix, &out.DeadLetterQueuePrefix
+		*out = new(string)
+		**out = **in
+	}
+	if in.DeadLetterQueueSuffix != nil {
+		in, out := &in.DeadLetterQueueSuffix, &out.DeadLetterQueueSuffix
+		*out = new(string)
+		**out = **in
+	}
+	if in.ExpiryAddress != nil {
+		in, out := &in.ExpiryAddress, &out.ExpiryAddress
+		*out = new(string)
+		**out = **in
+	}
+	if in.AutoCreateExpiryResources != nil {
+		in, out := &in.AutoCreateExpiryResources, &out.AutoCreateExpiryResources
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ExpiryQueuePrefix != nil {
+		in, out := &in.ExpiryQueuePrefix, &out.ExpiryQueuePrefix
+		*out = new(string)
+		**out = **in
+	}
+	if in.ExpiryQueueSuffix != nil {
+		in, out := &in.ExpiryQueueSuffix, &out.ExpiryQueueSuffix
+		*out = new(string)
+		**out = **in
+	}
+	if in.ExpiryDelay != nil {
+		in, out := &in.ExpiryDelay, &out.ExpiryDelay
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MinExpiryDelay != nil {
+		in, out := &in.MinExpiryDelay, &out.MinExpiryDelay
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxExpiryDelay != nil {
+		in, out := &in.MaxExpiryDelay, &out.MaxExpiryDelay
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RedeliveryDelay != nil {
+		in, out := &in.RedeliveryDelay, &out.RedeliveryDelay
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxRedeliveryDelay != nil {
+		in, out := &in.MaxRedeliveryDelay, &out.MaxRedeliveryDelay
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxDeliveryAttempts != nil {
+		in, out := &in.MaxDeliveryAttempts, &out.MaxDeliveryAttempts
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxSizeBytes != nil {
+		in, out := &in.MaxSizeBytes, &out.MaxSizeBytes
+		*out = new(string)
+		**out = **in
+	}
+	if in.MaxSizeBytesRejectThreshold != nil {
+		in, out := &in.MaxSizeBytesRejectThreshold, &out.MaxSizeBytesRejectThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PageSizeBytes != nil {
+		in, out := &in.PageSizeBytes, &out.PageSizeBytes
+		*out = new(string)
+		**out = **in
+	}
+	if in.PageMaxCacheSize != nil {
+		in, out := &in.PageMaxCacheSize, &out.PageMaxCacheSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AddressFullPolicy != nil {
+		in, out := &in.AddressFullPolicy, &out.AddressFullPolicy
+		*out = new(string)
+		**out = **in
+	}
+	if in.MessageCounterHistoryDayLimit != nil {
+		in, out := &in.MessageCounterHistoryDayLimit, &out.MessageCounterHistoryDayLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.LastValueQueue != nil {
+		in, out := &in.LastValueQueue, &out.LastValueQueue
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DefaultLastValueQueue != nil {
+		in, out := &in.DefaultLastValueQueue, &out.DefaultLastValueQueue
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DefaultLastValueKey != nil {
+		in, out := &in.DefaultLastValueKey, &out.DefaultLastValueKey
+		*out = new(string)
+		**out = **in
+	}
+	if in.DefaultNonDestructive != nil {
+		in, out := &in.DefaultNonDestructive, &out.DefaultNonDestructive
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DefaultExclusiveQueue != nil {
+		in, out := &in.DefaultExclusiveQueue, &out.DefaultExclusiveQueue
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DefaultGroupRebalance != nil {
+		in, out := &in.DefaultGroupRebalance, &out.DefaultGroupRebalance
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DefaultGroupRebalancePauseDispatch != nil {
+		in, out := &in.DefaultGroupRebalancePauseDispatch, &out.DefaultGroupRebalancePauseDispatch
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DefaultGroupBuckets != nil {
+		in, out := &in.DefaultGroupBuckets, &out.DefaultGroupBuckets
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DefaultGroupFirstKey != nil {
+		in, out := &in.DefaultGroupFirstKey, &out.DefaultGroupFirstKey
+		*out = new(string)
+		**out = **in
+	}
+	if in.DefaultConsumersBeforeDispatch != nil {
+		in, out := &in.DefaultConsumersBeforeDispatch, &out.DefaultConsumersBeforeDispatch
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DefaultDelayBeforeDispatch != nil {
+		in, out := &in.DefaultDelayBeforeDispatch, &out.DefaultDelayBeforeDispatch
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RedistributionDelay != nil {
+		in, out := &in.RedistributionDelay, &out.RedistributionDelay
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SendToDlaOnNoRoute != nil {
+		in, out := &in.SendToDlaOnNoRoute, &out.SendToDlaOnNoRoute
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SlowConsumerThreshold != nil {
+		in, out := &in.SlowConsumerThreshold, &out.SlowConsumerThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SlowConsumerPolicy != nil {
+		in, out := &in.SlowConsumerPolicy, &out.SlowConsumerPolicy
+		*out = new(string)
+		**out = **in
+	}
+	if in.SlowConsumerCheckPeriod != nil {
+		in, out := &in.SlowConsumerCheckPeriod, &out.SlowConsumerCheckPeriod
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AutoCreateJmsQueues != nil {
+		in, out := &in.AutoCreateJmsQueues, &out.AutoCreateJmsQueues
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AutoDeleteJmsQueues != nil {
+		in, out := &in.AutoDeleteJmsQueues, &out.AutoDeleteJmsQueues
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AutoCreateJmsTopics != nil {
+		in, out := &in.AutoCreateJmsTopics, &out.AutoCreateJmsTopics
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AutoDeleteJmsTopics != nil {
+		in, out := &in.AutoDeleteJmsTopics, &out.AutoDeleteJmsTopics
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AutoCreateQueues != nil {
+		in, out := &in.AutoCreateQueues, &out.AutoCreateQueues
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AutoDeleteQueues != nil {
+		in, out := &in.AutoDeleteQueues, &out.AutoDeleteQueues
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AutoDeleteCreatedQueues != nil {
+		in, out := &in.AutoDeleteCreatedQueues, &out.AutoDeleteCreatedQueues
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AutoDeleteQueuesDelay != nil {
+		in, out := &in.AutoDeleteQueuesDelay, &out.AutoDeleteQueuesDelay
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AutoDeleteQueuesMessageCount != nil {
+		in, out := &in.AutoDeleteQueuesMessageCount, &out.AutoDeleteQueuesMessageCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ConfigDeleteQueues != nil {
+		in, out := &in.ConfigDeleteQueues, &out.ConfigDeleteQueues
+		*out = new(string)
+		**out = **in
+	}
+	if in.AutoCreateAddresses != nil {
+		in, out := &in.AutoCreateAddresses, &out.AutoCreateAddresses
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AutoDeleteAddresses != nil {
+		in, out := &in.AutoDeleteAddresses, &out.AutoDeleteAddresses
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AutoDeleteAddressesDelay != nil {
+		in, out := &in.AutoDeleteAddressesDelay, &out.AutoDeleteAddressesDelay
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ConfigDeleteAddresses != nil {
+		in, out := &in.ConfigDeleteAddresses, &out.ConfigDeleteAddresses
+		*out = new(string)
+		**out = **in
+	}
+	if in.ManagementBrowsePageSize != nil {
+		in, out := &in.ManagementBrowsePageSize, &out.ManagementBrowsePageSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DefaultPurgeOnNoConsumers != nil {
+		in, out := &in.DefaultPurgeOnNoConsumers, &out.DefaultPurgeOnNoConsumers
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DefaultMaxConsumers != nil {
+		in, out := &in.DefaultMaxConsumers, &out.DefaultMaxConsumers
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DefaultQueueRoutingType != nil {
+		in, out := &in.DefaultQueueRoutingType, &out.DefaultQueueRoutingType
+		*out = new(string)
+		**out = **in
+	}
+	if in.DefaultAddressRoutingType != nil {
+		in, out := &in.DefaultAddressRoutingType, &out.DefaultAddressRoutingType
+		*out = new(string)
+		**out = **in
+	}
+	if in.DefaultConsumerWindowSize != nil {
+		in, out := &in.DefaultConsumerWindowSize, &out.DefaultConsumerWindowSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DefaultRingSize != nil {
+		in, out := &in.DefaultRingSize, &out.DefaultRingSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RetroactiveMessageCount != nil {
+		in, out := &in.RetroactiveMessageCount, &out.RetroactiveMessageCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.EnableMetrics != nil {
+		in, out := &in.EnableMetrics, &out.EnableMetrics
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ManagementMessageAttributeSizeLimit != nil {
+		in, out := &in.ManagementMessageAttributeSizeLimit, &out.ManagementMessageAttributeSizeLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SlowConsumerThresholdMeasurementUnit != nil {
+		in, out := &in.SlowConsumerThresholdMeasurementUnit, &out.SlowConsumerThresholdMeasurementUnit
+		*out = new(string)
+		**out = **in
+	}
+	if in.EnableIngressTimestamp != nil {
+		in, out := &in.EnableIngressTimestamp, &out.EnableIngressTimestamp
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ConfigDeleteDiverts != nil {
+		in, out := &in.ConfigDeleteDiverts, &out.ConfigDeleteDiverts
+		*out = new(string)
+		**out = **in
+	}
+	if in.MaxSizeMessages != nil {
+		in, out := &in.MaxSizeMessages, &out.MaxSizeMessages
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddressSettingType.
+func (in *AddressSettingType) DeepCopy() *AddressSettingType {
+	if in == nil {
+		return nil
+	}
+	out := new(AddressSettingType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AddressSettingsType) DeepCopyInto(out *AddressSettingsType) {
+	*out = *in
+	if in.ApplyRule != nil {
+		in, out := &in.ApplyRule, &out.ApplyRule
+		*out = new(string)
+		**out = **in
+	}
+	if in.AddressSetting != nil {
+		in, out := &in.AddressSetting, &out.AddressSetting
+		*out = make([]AddressSettingType, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddressSettingsType.
+func (in *AddressSettingsType) DeepCopy() *AddressSettingsType {
+	if in == nil {
+		return nil
+	}
+	out := new(AddressSettingsType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AffinityConfig) DeepCopyInto(out *AffinityConfig) {
+	*out = *in
+	if in.NodeAffinity != nil {
+		in, out := &in.NodeAffinity, &out.NodeAffinity
+		*out = new(v1.NodeAffinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodAffinity != nil {
+		in, out := &in.PodAffinity, &out.PodAffinity
+		*out = new(v1.PodAffinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodAntiAffinity != nil {
+		in, out := &in.PodAntiAffinity, &out.PodAntiAffinity
+		*out = new(v1.PodAntiAffinity)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AffinityConfig.
+func (in *AffinityConfig) DeepCopy() *AffinityConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AffinityConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AllowedListEntryType) DeepCopyInto(out *AllowedListEntryType) {
+	*out = *in
+	if in.Domain != nil {
+		in, out := &in.Domain, &out.Domain
+		*out = new(string)
+		**out = **in
+	}
+	if in.Key != nil {
+		in, out := &in.Key, &out.Key
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AllowedListEntryType.
+func (in *AllowedListEntryType) DeepCopy() *AllowedListEntryType {
+	if in == nil {
+		return nil
+	}
+	out := new(AllowedListEntryType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthorisationConfigType) DeepCopyInto(out *AuthorisationConfigType) {
+	*out = *in
+	if in.AllowedList != nil {
+		in, out := &in.AllowedList, &out.AllowedList
+		*out = make([]AllowedListEntryType, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DefaultAccess != nil {
+		in, out := &in.DefaultAccess, &out.DefaultAccess
+		*out = make([]DefaultAccessType, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RoleAccess != nil {
+		in, out := &in.RoleAccess, &out.RoleAccess
+		*out = make([]RoleAccessType, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DeniedList != nil {
+		in, out := &in.DeniedList, &out.DeniedList
+		*out = make([]DeniedListEntryType, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthorisationConfigType.
+func (in *AuthorisationConfigType) DeepCopy() *AuthorisationConfigType {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthorisationConfigType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoscalingQueueType) DeepCopyInto(out *AutoscalingQueueType) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalingQueueType.
+func (in *AutoscalingQueueType) DeepCopy() *AutoscalingQueueType {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoscalingQueueType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoscalingType) DeepCopyInto(out *AutoscalingType) {
+	*out = *in
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Queues != nil {
+		in, out := &in.Queues, &out.Queues
+		*out = make([]AutoscalingQueueType, len(*in))
+		copy(*out, *in)
+	}
+	if in.PollingInterval != nil {
+		in, out := &in.PollingInterval, &out.PollingInterval
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ScaleInPeriodSeconds != nil {
+		in, out := &in.ScaleInPeriodSeconds, &out.ScaleInPeriodSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalingType.
+func (in *AutoscalingType) DeepCopy() *AutoscalingType {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoscalingType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BridgeReconnectPolicyType) DeepCopyInto(out *BridgeReconnectPolicyType) {
+	*out = *in
+	if in.RetryInterval != nil {
+		in, out := &in.RetryInterval, &out.RetryInterval
+		*out = new(int64)
+		**out = **in
+	}
+	if in.InitialConnectAttempts != nil {
+		in, out := &in.InitialConnectAttempts, &out.InitialConnectAttempts
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ReconnectAttempts != nil {
+		in, out := &in.ReconnectAttempts, &out.ReconnectAttempts
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BridgeReconnectPolicyType.
+func (in *BridgeReconnectPolicyType) DeepCopy() *BridgeReconnectPolicyType {
+	if in == nil {
+		return nil
+	}
+	out := new(BridgeReconnectPolicyType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BridgeTargetType) DeepCopyInto(out *BridgeTargetType) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BridgeTargetType.
+func (in *BridgeTargetType) DeepCopy() *BridgeTargetType {
+	if in == nil {
+		return nil
+	}
+	out := new(BridgeTargetType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrokerDomainType) DeepCopyInto(out *BrokerDomainType) {
+	*out = *in
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+	if in.LoginModules != nil {
+		in, out := &in.LoginModules, &out.LoginModules
+		*out = make([]LoginModuleReferenceType, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerDomainType.
+func (in *BrokerDomainType) DeepCopy() *BrokerDomainType {
+	if in == nil {
+		return nil
+	}
+	out := new(BrokerDomainType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrokerSecuritySettingType) DeepCopyInto(out *BrokerSecuritySettingType) {
+	*out = *in
+	if in.Permissions != nil {
+		in, out := &in.Permissions, &out.Permissions
+		*out = make([]PermissionType, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerSecuritySettingType.
+func (in *BrokerSecuritySettingType) DeepCopy() *BrokerSecuritySettingType {
+	if in == nil {
+		return nil
+	}
+	out := new(BrokerSecuritySettingType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CSICredentialsSourceType) DeepCopyInto(out *CSICredentialsSourceType) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CSICredentialsSourceType.
+func (in *CSICredentialsSourceType) DeepCopy() *CSICredentialsSourceType {
+	if in == nil {
+		return nil
 	}
-	if in.MaxSizeBytesRejectThreshold != nil {
-		in, out := &in.MaxSizeBytesRejectThreshold, &out.MaxSizeBytesRejectThreshold
+	out := new(CSICredentialsSourceType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CapacityForecastType) DeepCopyInto(out *CapacityForecastType) {
+	*out = *in
+	if in.HorizonMinutes != nil {
+		in, out := &in.HorizonMinutes, &out.HorizonMinutes
 		*out = new(int32)
 		**out = **in
 	}
-	if in.PageSizeBytes != nil {
-		in, out := &in.PageSizeBytes, &out.PageSizeBytes
+	if in.WindowMinutes != nil {
+		in, out := &in.WindowMinutes, &out.WindowMinutes
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CapacityForecastType.
+func (in *CapacityForecastType) DeepCopy() *CapacityForecastType {
+	if in == nil {
+		return nil
+	}
+	out := new(CapacityForecastType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CapacityPlaceholdersType) DeepCopyInto(out *CapacityPlaceholdersType) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CapacityPlaceholdersType.
+func (in *CapacityPlaceholdersType) DeepCopy() *CapacityPlaceholdersType {
+	if in == nil {
+		return nil
+	}
+	out := new(CapacityPlaceholdersType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateIssuerType) DeepCopyInto(out *CertificateIssuerType) {
+	*out = *in
+	if in.DNSNames != nil {
+		in, out := &in.DNSNames, &out.DNSNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateIssuerType.
+func (in *CertificateIssuerType) DeepCopy() *CertificateIssuerType {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateIssuerType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateLoginModuleType) DeepCopyInto(out *CertificateLoginModuleType) {
+	*out = *in
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]CertificateUserType, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Flag != nil {
+		in, out := &in.Flag, &out.Flag
 		*out = new(string)
 		**out = **in
 	}
-	if in.PageMaxCacheSize != nil {
-		in, out := &in.PageMaxCacheSize, &out.PageMaxCacheSize
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateLoginModuleType.
+func (in *CertificateLoginModuleType) DeepCopy() *CertificateLoginModuleType {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateLoginModuleType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateUserType) DeepCopyInto(out *CertificateUserType) {
+	*out = *in
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateUserType.
+func (in *CertificateUserType) DeepCopy() *CertificateUserType {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateUserType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterConnectionType) DeepCopyInto(out *ClusterConnectionType) {
+	*out = *in
+	if in.MaxHops != nil {
+		in, out := &in.MaxHops, &out.MaxHops
 		*out = new(int32)
 		**out = **in
 	}
-	if in.AddressFullPolicy != nil {
-		in, out := &in.AddressFullPolicy, &out.AddressFullPolicy
+	if in.ReconnectAttempts != nil {
+		in, out := &in.ReconnectAttempts, &out.ReconnectAttempts
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CallTimeout != nil {
+		in, out := &in.CallTimeout, &out.CallTimeout
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ProducerWindowSize != nil {
+		in, out := &in.ProducerWindowSize, &out.ProducerWindowSize
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterConnectionType.
+func (in *ClusterConnectionType) DeepCopy() *ClusterConnectionType {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterConnectionType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterMeshType) DeepCopyInto(out *ClusterMeshType) {
+	*out = *in
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterMeshType.
+func (in *ClusterMeshType) DeepCopy() *ClusterMeshType {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterMeshType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTLSType) DeepCopyInto(out *ClusterTLSType) {
+	*out = *in
+	if in.CertificateIssuer != nil {
+		in, out := &in.CertificateIssuer, &out.CertificateIssuer
+		*out = new(CertificateIssuerType)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTLSType.
+func (in *ClusterTLSType) DeepCopy() *ClusterTLSType {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTLSType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectorConfigType) DeepCopyInto(out *ConnectorConfigType) {
+	*out = *in
+	if in.Host != nil {
+		in, out := &in.Host, &out.Host
 		*out = new(string)
 		**out = **in
 	}
-	if in.MessageCounterHistoryDayLimit != nil {
-		in, out := &in.MessageCounterHistoryDayLimit, &out.MessageCounterHistoryDayLimit
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
 		*out = new(int32)
 		**out = **in
 	}
-	if in.LastValueQueue != nil {
-		in, out := &in.LastValueQueue, &out.LastValueQueue
-		*out = new(bool)
+	if in.RmiRegistryPort != nil {
+		in, out := &in.RmiRegistryPort, &out.RmiRegistryPort
+		*out = new(int32)
 		**out = **in
 	}
-	if in.DefaultLastValueQueue != nil {
-		in, out := &in.DefaultLastValueQueue, &out.DefaultLastValueQueue
+	if in.JmxRealm != nil {
+		in, out := &in.JmxRealm, &out.JmxRealm
+		*out = new(string)
+		**out = **in
+	}
+	if in.ObjectName != nil {
+		in, out := &in.ObjectName, &out.ObjectName
+		*out = new(string)
+		**out = **in
+	}
+	if in.AuthenticatorType != nil {
+		in, out := &in.AuthenticatorType, &out.AuthenticatorType
+		*out = new(string)
+		**out = **in
+	}
+	if in.Secured != nil {
+		in, out := &in.Secured, &out.Secured
 		*out = new(bool)
 		**out = **in
 	}
-	if in.DefaultLastValueKey != nil {
-		in, out := &in.DefaultLastValueKey, &out.DefaultLastValueKey
-		*out = new(string)
-		**out = **in
+	if in.KeyStoreProvider != nil {
+		in, out := &in.KeyStoreProvider, &out.KeyStoreProvider
+		*out = new(string)
+		**out = **in
+	}
+	if in.KeyStorePath != nil {
+		in, out := &in.KeyStorePath, &out.KeyStorePath
+		*out = new(string)
+		**out = **in
+	}
+	if in.KeyStorePassword != nil {
+		in, out := &in.KeyStorePassword, &out.KeyStorePassword
+		*out = new(string)
+		**out = **in
+	}
+	if in.TrustStoreProvider != nil {
+		in, out := &in.TrustStoreProvider, &out.TrustStoreProvider
+		*out = new(string)
+		**out = **in
+	}
+	if in.TrustStorePath != nil {
+		in, out := &in.TrustStorePath, &out.TrustStorePath
+		*out = new(string)
+		**out = **in
+	}
+	if in.TrustStorePassword != nil {
+		in, out := &in.TrustStorePassword, &out.TrustStorePassword
+		*out = new(string)
+		**out = **in
+	}
+	if in.PasswordCodec != nil {
+		in, out := &in.PasswordCodec, &out.PasswordCodec
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectorConfigType.
+func (in *ConnectorConfigType) DeepCopy() *ConnectorConfigType {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectorConfigType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectorType) DeepCopyInto(out *ConnectorType) {
+	*out = *in
+	if in.CertificateIssuer != nil {
+		in, out := &in.CertificateIssuer, &out.CertificateIssuer
+		*out = new(CertificateIssuerType)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Params != nil {
+		in, out := &in.Params, &out.Params
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectorType.
+func (in *ConnectorType) DeepCopy() *ConnectorType {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectorType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConsoleSSOType) DeepCopyInto(out *ConsoleSSOType) {
+	*out = *in
+	in.ClientSecret.DeepCopyInto(&out.ClientSecret)
+	if in.Scopes != nil {
+		in, out := &in.Scopes, &out.Scopes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedGroups != nil {
+		in, out := &in.AllowedGroups, &out.AllowedGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConsoleSSOType.
+func (in *ConsoleSSOType) DeepCopy() *ConsoleSSOType {
+	if in == nil {
+		return nil
+	}
+	out := new(ConsoleSSOType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConsoleType) DeepCopyInto(out *ConsoleType) {
+	*out = *in
+	if in.ExposeAnnotations != nil {
+		in, out := &in.ExposeAnnotations, &out.ExposeAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ExternalDNS != nil {
+		in, out := &in.ExternalDNS, &out.ExternalDNS
+		*out = new(ExternalDNSType)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.DefaultNonDestructive != nil {
-		in, out := &in.DefaultNonDestructive, &out.DefaultNonDestructive
-		*out = new(bool)
+	if in.RouteTLS != nil {
+		in, out := &in.RouteTLS, &out.RouteTLS
+		*out = new(RouteTLSType)
 		**out = **in
 	}
-	if in.DefaultExclusiveQueue != nil {
-		in, out := &in.DefaultExclusiveQueue, &out.DefaultExclusiveQueue
-		*out = new(bool)
-		**out = **in
+	if in.ServiceSettings != nil {
+		in, out := &in.ServiceSettings, &out.ServiceSettings
+		*out = new(ServiceSettingsType)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.DefaultGroupRebalance != nil {
-		in, out := &in.DefaultGroupRebalance, &out.DefaultGroupRebalance
-		*out = new(bool)
+	if in.SessionTimeoutSeconds != nil {
+		in, out := &in.SessionTimeoutSeconds, &out.SessionTimeoutSeconds
+		*out = new(int32)
 		**out = **in
 	}
-	if in.DefaultGroupRebalancePauseDispatch != nil {
-		in, out := &in.DefaultGroupRebalancePauseDispatch, &out.DefaultGroupRebalancePauseDispatch
-		*out = new(bool)
-		**out = **in
+	if in.Jolokia != nil {
+		in, out := &in.Jolokia, &out.Jolokia
+		*out = new(JolokiaType)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.DefaultGroupBuckets != nil {
-		in, out := &in.DefaultGroupBuckets, &out.DefaultGroupBuckets
-		*out = new(int32)
-		**out = **in
+	if in.SSO != nil {
+		in, out := &in.SSO, &out.SSO
+		*out = new(ConsoleSSOType)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.DefaultGroupFirstKey != nil {
-		in, out := &in.DefaultGroupFirstKey, &out.DefaultGroupFirstKey
-		*out = new(string)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConsoleType.
+func (in *ConsoleType) DeepCopy() *ConsoleType {
+	if in == nil {
+		return nil
 	}
-	if in.DefaultConsumersBeforeDispatch != nil {
-		in, out := &in.DefaultConsumersBeforeDispatch, &out.DefaultConsumersBeforeDispatch
-		*out = new(int32)
-		**out = **in
+	out := new(ConsoleType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CredentialRotationStatus) DeepCopyInto(out *CredentialRotationStatus) {
+	*out = *in
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
 	}
-	if in.DefaultDelayBeforeDispatch != nil {
-		in, out := &in.DefaultDelayBeforeDispatch, &out.DefaultDelayBeforeDispatch
-		*out = new(int32)
-		**out = **in
+	if in.CompletedAt != nil {
+		in, out := &in.CompletedAt, &out.CompletedAt
+		*out = (*in).DeepCopy()
 	}
-	if in.RedistributionDelay != nil {
-		in, out := &in.RedistributionDelay, &out.RedistributionDelay
-		*out = new(int32)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialRotationStatus.
+func (in *CredentialRotationStatus) DeepCopy() *CredentialRotationStatus {
+	if in == nil {
+		return nil
 	}
-	if in.SendToDlaOnNoRoute != nil {
-		in, out := &in.SendToDlaOnNoRoute, &out.SendToDlaOnNoRoute
-		*out = new(bool)
-		**out = **in
+	out := new(CredentialRotationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CredentialRotationType) DeepCopyInto(out *CredentialRotationType) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialRotationType.
+func (in *CredentialRotationType) DeepCopy() *CredentialRotationType {
+	if in == nil {
+		return nil
 	}
-	if in.SlowConsumerThreshold != nil {
-		in, out := &in.SlowConsumerThreshold, &out.SlowConsumerThreshold
-		*out = new(int32)
+	out := new(CredentialRotationType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CredentialsSourceType) DeepCopyInto(out *CredentialsSourceType) {
+	*out = *in
+	if in.Vault != nil {
+		in, out := &in.Vault, &out.Vault
+		*out = new(VaultCredentialsSourceType)
 		**out = **in
 	}
-	if in.SlowConsumerPolicy != nil {
-		in, out := &in.SlowConsumerPolicy, &out.SlowConsumerPolicy
-		*out = new(string)
+	if in.CSI != nil {
+		in, out := &in.CSI, &out.CSI
+		*out = new(CSICredentialsSourceType)
 		**out = **in
 	}
-	if in.SlowConsumerCheckPeriod != nil {
-		in, out := &in.SlowConsumerCheckPeriod, &out.SlowConsumerCheckPeriod
-		*out = new(int32)
-		**out = **in
+	if in.KeyStorePasswords != nil {
+		in, out := &in.KeyStorePasswords, &out.KeyStorePasswords
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.AutoCreateJmsQueues != nil {
-		in, out := &in.AutoCreateJmsQueues, &out.AutoCreateJmsQueues
-		*out = new(bool)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialsSourceType.
+func (in *CredentialsSourceType) DeepCopy() *CredentialsSourceType {
+	if in == nil {
+		return nil
 	}
-	if in.AutoDeleteJmsQueues != nil {
-		in, out := &in.AutoDeleteJmsQueues, &out.AutoDeleteJmsQueues
-		*out = new(bool)
-		**out = **in
+	out := new(CredentialsSourceType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeadLetterAndExpiryType) DeepCopyInto(out *DeadLetterAndExpiryType) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeadLetterAndExpiryType.
+func (in *DeadLetterAndExpiryType) DeepCopy() *DeadLetterAndExpiryType {
+	if in == nil {
+		return nil
 	}
-	if in.AutoCreateJmsTopics != nil {
-		in, out := &in.AutoCreateJmsTopics, &out.AutoCreateJmsTopics
-		*out = new(bool)
+	out := new(DeadLetterAndExpiryType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DefaultAccessType) DeepCopyInto(out *DefaultAccessType) {
+	*out = *in
+	if in.Method != nil {
+		in, out := &in.Method, &out.Method
+		*out = new(string)
 		**out = **in
 	}
-	if in.AutoDeleteJmsTopics != nil {
-		in, out := &in.AutoDeleteJmsTopics, &out.AutoDeleteJmsTopics
-		*out = new(bool)
-		**out = **in
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.AutoCreateQueues != nil {
-		in, out := &in.AutoCreateQueues, &out.AutoCreateQueues
-		*out = new(bool)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefaultAccessType.
+func (in *DefaultAccessType) DeepCopy() *DefaultAccessType {
+	if in == nil {
+		return nil
 	}
-	if in.AutoDeleteQueues != nil {
-		in, out := &in.AutoDeleteQueues, &out.AutoDeleteQueues
-		*out = new(bool)
-		**out = **in
+	out := new(DefaultAccessType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeniedListEntryType) DeepCopyInto(out *DeniedListEntryType) {
+	*out = *in
+	if in.Operations != nil {
+		in, out := &in.Operations, &out.Operations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.AutoDeleteCreatedQueues != nil {
-		in, out := &in.AutoDeleteCreatedQueues, &out.AutoDeleteCreatedQueues
-		*out = new(bool)
-		**out = **in
+	if in.Attributes != nil {
+		in, out := &in.Attributes, &out.Attributes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.AutoDeleteQueuesDelay != nil {
-		in, out := &in.AutoDeleteQueuesDelay, &out.AutoDeleteQueuesDelay
-		*out = new(int32)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeniedListEntryType.
+func (in *DeniedListEntryType) DeepCopy() *DeniedListEntryType {
+	if in == nil {
+		return nil
 	}
-	if in.AutoDeleteQueuesMessageCount != nil {
-		in, out := &in.AutoDeleteQueuesMessageCount, &out.AutoDeleteQueuesMessageCount
+	out := new(DeniedListEntryType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeploymentPlanType) DeepCopyInto(out *DeploymentPlanType) {
+	*out = *in
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
 		*out = new(int32)
 		**out = **in
 	}
-	if in.ConfigDeleteQueues != nil {
-		in, out := &in.ConfigDeleteQueues, &out.ConfigDeleteQueues
-		*out = new(string)
+	if in.Ephemeral != nil {
+		in, out := &in.Ephemeral, &out.Ephemeral
+		*out = new(EphemeralType)
 		**out = **in
 	}
-	if in.AutoCreateAddresses != nil {
-		in, out := &in.AutoCreateAddresses, &out.AutoCreateAddresses
+	if in.MessageMigration != nil {
+		in, out := &in.MessageMigration, &out.MessageMigration
 		*out = new(bool)
 		**out = **in
 	}
-	if in.AutoDeleteAddresses != nil {
-		in, out := &in.AutoDeleteAddresses, &out.AutoDeleteAddresses
-		*out = new(bool)
-		**out = **in
+	if in.Drainer != nil {
+		in, out := &in.Drainer, &out.Drainer
+		*out = new(DrainerType)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.AutoDeleteAddressesDelay != nil {
-		in, out := &in.AutoDeleteAddressesDelay, &out.AutoDeleteAddressesDelay
-		*out = new(int32)
-		**out = **in
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.JVM != nil {
+		in, out := &in.JVM, &out.JVM
+		*out = new(JVMType)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.ConfigDeleteAddresses != nil {
-		in, out := &in.ConfigDeleteAddresses, &out.ConfigDeleteAddresses
-		*out = new(string)
+	out.Storage = in.Storage
+	in.Persistence.DeepCopyInto(&out.Persistence)
+	in.ExtraMounts.DeepCopyInto(&out.ExtraMounts)
+	if in.Clustered != nil {
+		in, out := &in.Clustered, &out.Clustered
+		*out = new(bool)
 		**out = **in
 	}
-	if in.ManagementBrowsePageSize != nil {
-		in, out := &in.ManagementBrowsePageSize, &out.ManagementBrowsePageSize
-		*out = new(int32)
-		**out = **in
+	in.PodSecurity.DeepCopyInto(&out.PodSecurity)
+	if in.LivenessProbe != nil {
+		in, out := &in.LivenessProbe, &out.LivenessProbe
+		*out = new(v1.Probe)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.DefaultPurgeOnNoConsumers != nil {
-		in, out := &in.DefaultPurgeOnNoConsumers, &out.DefaultPurgeOnNoConsumers
+	if in.ReadinessProbe != nil {
+		in, out := &in.ReadinessProbe, &out.ReadinessProbe
+		*out = new(v1.Probe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EnableMetricsPlugin != nil {
+		in, out := &in.EnableMetricsPlugin, &out.EnableMetricsPlugin
 		*out = new(bool)
 		**out = **in
 	}
-	if in.DefaultMaxConsumers != nil {
-		in, out := &in.DefaultMaxConsumers, &out.DefaultMaxConsumers
-		*out = new(int32)
-		**out = **in
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = new(MetricsType)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
-	if in.DefaultQueueRoutingType != nil {
-		in, out := &in.DefaultQueueRoutingType, &out.DefaultQueueRoutingType
-		*out = new(string)
-		**out = **in
+	in.Affinity.DeepCopyInto(&out.Affinity)
+	if in.PodSecurityContext != nil {
+		in, out := &in.PodSecurityContext, &out.PodSecurityContext
+		*out = new(v1.PodSecurityContext)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.DefaultAddressRoutingType != nil {
-		in, out := &in.DefaultAddressRoutingType, &out.DefaultAddressRoutingType
-		*out = new(string)
-		**out = **in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
-	if in.DefaultConsumerWindowSize != nil {
-		in, out := &in.DefaultConsumerWindowSize, &out.DefaultConsumerWindowSize
-		*out = new(int32)
-		**out = **in
+	if in.AdditionalNetworks != nil {
+		in, out := &in.AdditionalNetworks, &out.AdditionalNetworks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.DefaultRingSize != nil {
-		in, out := &in.DefaultRingSize, &out.DefaultRingSize
-		*out = new(int32)
-		**out = **in
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(policyv1.PodDisruptionBudgetSpec)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.RetroactiveMessageCount != nil {
-		in, out := &in.RetroactiveMessageCount, &out.RetroactiveMessageCount
-		*out = new(int32)
+	if in.CapacityPlaceholders != nil {
+		in, out := &in.CapacityPlaceholders, &out.CapacityPlaceholders
+		*out = new(CapacityPlaceholdersType)
 		**out = **in
 	}
-	if in.EnableMetrics != nil {
-		in, out := &in.EnableMetrics, &out.EnableMetrics
-		*out = new(bool)
-		**out = **in
+	if in.CapacityForecast != nil {
+		in, out := &in.CapacityForecast, &out.CapacityForecast
+		*out = new(CapacityForecastType)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.ManagementMessageAttributeSizeLimit != nil {
-		in, out := &in.ManagementMessageAttributeSizeLimit, &out.ManagementMessageAttributeSizeLimit
-		*out = new(int32)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeploymentPlanType.
+func (in *DeploymentPlanType) DeepCopy() *DeploymentPlanType {
+	if in == nil {
+		return nil
 	}
-	if in.SlowConsumerThresholdMeasurementUnit != nil {
-		in, out := &in.SlowConsumerThresholdMeasurementUnit, &out.SlowConsumerThresholdMeasurementUnit
-		*out = new(string)
-		**out = **in
+	out := new(DeploymentPlanType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DrainerType) DeepCopyInto(out *DrainerType) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.EnableIngressTimestamp != nil {
-		in, out := &in.EnableIngressTimestamp, &out.EnableIngressTimestamp
-		*out = new(bool)
-		**out = **in
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(v1.SecurityContext)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.ConfigDeleteDiverts != nil {
-		in, out := &in.ConfigDeleteDiverts, &out.ConfigDeleteDiverts
-		*out = new(string)
-		**out = **in
+	if in.PodSecurityContext != nil {
+		in, out := &in.PodSecurityContext, &out.PodSecurityContext
+		*out = new(v1.PodSecurityContext)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.MaxSizeMessages != nil {
-		in, out := &in.MaxSizeMessages, &out.MaxSizeMessages
-		*out = new(int64)
-		**out = **in
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddressSettingType.
-func (in *AddressSettingType) DeepCopy() *AddressSettingType {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrainerType.
+func (in *DrainerType) DeepCopy() *DrainerType {
 	if in == nil {
 		return nil
 	}
-	out := new(AddressSettingType)
+	out := new(DrainerType)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AddressSettingsType) DeepCopyInto(out *AddressSettingsType) {
+func (in *EffectivePermissionType) DeepCopyInto(out *EffectivePermissionType) {
 	*out = *in
-	if in.ApplyRule != nil {
-		in, out := &in.ApplyRule, &out.ApplyRule
-		*out = new(string)
-		**out = **in
-	}
-	if in.AddressSetting != nil {
-		in, out := &in.AddressSetting, &out.AddressSetting
-		*out = make([]AddressSettingType, len(*in))
+	if in.Permissions != nil {
+		in, out := &in.Permissions, &out.Permissions
+		*out = make([]PermissionType, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddressSettingsType.
-func (in *AddressSettingsType) DeepCopy() *AddressSettingsType {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EffectivePermissionType.
+func (in *EffectivePermissionType) DeepCopy() *EffectivePermissionType {
 	if in == nil {
 		return nil
 	}
-	out := new(AddressSettingsType)
+	out := new(EffectivePermissionType)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AffinityConfig) DeepCopyInto(out *AffinityConfig) {
+func (in *EphemeralType) DeepCopyInto(out *EphemeralType) {
 	*out = *in
-	if in.NodeAffinity != nil {
-		in, out := &in.NodeAffinity, &out.NodeAffinity
-		*out = new(v1.NodeAffinity)
-		(*in).DeepCopyInto(*out)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EphemeralType.
+func (in *EphemeralType) DeepCopy() *EphemeralType {
+	if in == nil {
+		return nil
 	}
-	if in.PodAffinity != nil {
-		in, out := &in.PodAffinity, &out.PodAffinity
-		*out = new(v1.PodAffinity)
-		(*in).DeepCopyInto(*out)
+	out := new(EphemeralType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalConfigStatus) DeepCopyInto(out *ExternalConfigStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalConfigStatus.
+func (in *ExternalConfigStatus) DeepCopy() *ExternalConfigStatus {
+	if in == nil {
+		return nil
 	}
-	if in.PodAntiAffinity != nil {
-		in, out := &in.PodAntiAffinity, &out.PodAntiAffinity
-		*out = new(v1.PodAntiAffinity)
-		(*in).DeepCopyInto(*out)
+	out := new(ExternalConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalDNSType) DeepCopyInto(out *ExternalDNSType) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AffinityConfig.
-func (in *AffinityConfig) DeepCopy() *AffinityConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalDNSType.
+func (in *ExternalDNSType) DeepCopy() *ExternalDNSType {
 	if in == nil {
 		return nil
 	}
-	out := new(AffinityConfig)
+	out := new(ExternalDNSType)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AllowedListEntryType) DeepCopyInto(out *AllowedListEntryType) {
+func (in *ExternalEndpointStatus) DeepCopyInto(out *ExternalEndpointStatus) {
 	*out = *in
-	if in.Domain != nil {
-		in, out := &in.Domain, &out.Domain
-		*out = new(string)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalEndpointStatus.
+func (in *ExternalEndpointStatus) DeepCopy() *ExternalEndpointStatus {
+	if in == nil {
+		return nil
 	}
-	if in.Key != nil {
-		in, out := &in.Key, &out.Key
-		*out = new(string)
-		**out = **in
+	out := new(ExternalEndpointStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExtraMountsType) DeepCopyInto(out *ExtraMountsType) {
+	*out = *in
+	if in.ConfigMaps != nil {
+		in, out := &in.ConfigMaps, &out.ConfigMaps
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Secrets != nil {
+		in, out := &in.Secrets, &out.Secrets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AllowedListEntryType.
-func (in *AllowedListEntryType) DeepCopy() *AllowedListEntryType {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtraMountsType.
+func (in *ExtraMountsType) DeepCopy() *ExtraMountsType {
 	if in == nil {
 		return nil
 	}
-	out := new(AllowedListEntryType)
+	out := new(ExtraMountsType)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AuthorisationConfigType) DeepCopyInto(out *AuthorisationConfigType) {
+func (in *FederationAddressPolicyType) DeepCopyInto(out *FederationAddressPolicyType) {
 	*out = *in
-	if in.AllowedList != nil {
-		in, out := &in.AllowedList, &out.AllowedList
-		*out = make([]AllowedListEntryType, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Include != nil {
+		in, out := &in.Include, &out.Include
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.DefaultAccess != nil {
-		in, out := &in.DefaultAccess, &out.DefaultAccess
-		*out = make([]DefaultAccessType, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Exclude != nil {
+		in, out := &in.Exclude, &out.Exclude
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.RoleAccess != nil {
-		in, out := &in.RoleAccess, &out.RoleAccess
-		*out = make([]RoleAccessType, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.MaxHops != nil {
+		in, out := &in.MaxHops, &out.MaxHops
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AutoDelete != nil {
+		in, out := &in.AutoDelete, &out.AutoDelete
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EnableDivertBindings != nil {
+		in, out := &in.EnableDivertBindings, &out.EnableDivertBindings
+		*out = new(bool)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthorisationConfigType.
-func (in *AuthorisationConfigType) DeepCopy() *AuthorisationConfigType {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederationAddressPolicyType.
+func (in *FederationAddressPolicyType) DeepCopy() *FederationAddressPolicyType {
 	if in == nil {
 		return nil
 	}
-	out := new(AuthorisationConfigType)
+	out := new(FederationAddressPolicyType)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *BrokerDomainType) DeepCopyInto(out *BrokerDomainType) {
+func (in *FederationQueueMatchType) DeepCopyInto(out *FederationQueueMatchType) {
 	*out = *in
-	if in.Name != nil {
-		in, out := &in.Name, &out.Name
-		*out = new(string)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederationQueueMatchType.
+func (in *FederationQueueMatchType) DeepCopy() *FederationQueueMatchType {
+	if in == nil {
+		return nil
+	}
+	out := new(FederationQueueMatchType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FederationQueuePolicyType) DeepCopyInto(out *FederationQueuePolicyType) {
+	*out = *in
+	if in.Include != nil {
+		in, out := &in.Include, &out.Include
+		*out = make([]FederationQueueMatchType, len(*in))
+		copy(*out, *in)
+	}
+	if in.Exclude != nil {
+		in, out := &in.Exclude, &out.Exclude
+		*out = make([]FederationQueueMatchType, len(*in))
+		copy(*out, *in)
+	}
+	if in.IncludeFederated != nil {
+		in, out := &in.IncludeFederated, &out.IncludeFederated
+		*out = new(bool)
 		**out = **in
 	}
-	if in.LoginModules != nil {
-		in, out := &in.LoginModules, &out.LoginModules
-		*out = make([]LoginModuleReferenceType, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.PriorityAdjustment != nil {
+		in, out := &in.PriorityAdjustment, &out.PriorityAdjustment
+		*out = new(int32)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerDomainType.
-func (in *BrokerDomainType) DeepCopy() *BrokerDomainType {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederationQueuePolicyType.
+func (in *FederationQueuePolicyType) DeepCopy() *FederationQueuePolicyType {
 	if in == nil {
 		return nil
 	}
-	out := new(BrokerDomainType)
+	out := new(FederationQueuePolicyType)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *BrokerSecuritySettingType) DeepCopyInto(out *BrokerSecuritySettingType) {
+func (in *FederationType) DeepCopyInto(out *FederationType) {
 	*out = *in
-	if in.Permissions != nil {
-		in, out := &in.Permissions, &out.Permissions
-		*out = make([]PermissionType, len(*in))
+	if in.Upstreams != nil {
+		in, out := &in.Upstreams, &out.Upstreams
+		*out = make([]FederationUpstreamType, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AddressPolicies != nil {
+		in, out := &in.AddressPolicies, &out.AddressPolicies
+		*out = make([]FederationAddressPolicyType, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.QueuePolicies != nil {
+		in, out := &in.QueuePolicies, &out.QueuePolicies
+		*out = make([]FederationQueuePolicyType, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerSecuritySettingType.
-func (in *BrokerSecuritySettingType) DeepCopy() *BrokerSecuritySettingType {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederationType.
+func (in *FederationType) DeepCopy() *FederationType {
 	if in == nil {
 		return nil
 	}
-	out := new(BrokerSecuritySettingType)
+	out := new(FederationType)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ConnectorConfigType) DeepCopyInto(out *ConnectorConfigType) {
+func (in *FederationUpstreamType) DeepCopyInto(out *FederationUpstreamType) {
 	*out = *in
-	if in.Host != nil {
-		in, out := &in.Host, &out.Host
-		*out = new(string)
-		**out = **in
-	}
-	if in.Port != nil {
-		in, out := &in.Port, &out.Port
-		*out = new(int32)
-		**out = **in
-	}
-	if in.RmiRegistryPort != nil {
-		in, out := &in.RmiRegistryPort, &out.RmiRegistryPort
-		*out = new(int32)
-		**out = **in
-	}
-	if in.JmxRealm != nil {
-		in, out := &in.JmxRealm, &out.JmxRealm
-		*out = new(string)
-		**out = **in
-	}
-	if in.ObjectName != nil {
-		in, out := &in.ObjectName, &out.ObjectName
-		*out = new(string)
-		**out = **in
-	}
-	if in.AuthenticatorType != nil {
-		in, out := &in.AuthenticatorType, &out.AuthenticatorType
-		*out = new(string)
-		**out = **in
+	if in.ConnectorUrls != nil {
+		in, out := &in.ConnectorUrls, &out.ConnectorUrls
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.Secured != nil {
-		in, out := &in.Secured, &out.Secured
-		*out = new(bool)
-		**out = **in
+	if in.Policies != nil {
+		in, out := &in.Policies, &out.Policies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.KeyStoreProvider != nil {
-		in, out := &in.KeyStoreProvider, &out.KeyStoreProvider
-		*out = new(string)
+	if in.RetryInterval != nil {
+		in, out := &in.RetryInterval, &out.RetryInterval
+		*out = new(int64)
 		**out = **in
 	}
-	if in.KeyStorePath != nil {
-		in, out := &in.KeyStorePath, &out.KeyStorePath
-		*out = new(string)
+	if in.ReconnectAttempts != nil {
+		in, out := &in.ReconnectAttempts, &out.ReconnectAttempts
+		*out = new(int32)
 		**out = **in
 	}
-	if in.KeyStorePassword != nil {
-		in, out := &in.KeyStorePassword, &out.KeyStorePassword
-		*out = new(string)
+	if in.CircuitBreakerTimeout != nil {
+		in, out := &in.CircuitBreakerTimeout, &out.CircuitBreakerTimeout
+		*out = new(int64)
 		**out = **in
 	}
-	if in.TrustStoreProvider != nil {
-		in, out := &in.TrustStoreProvider, &out.TrustStoreProvider
-		*out = new(string)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederationUpstreamType.
+func (in *FederationUpstreamType) DeepCopy() *FederationUpstreamType {
+	if in == nil {
+		return nil
 	}
-	if in.TrustStorePath != nil {
-		in, out := &in.TrustStorePath, &out.TrustStorePath
+	out := new(FederationUpstreamType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GuestLoginModuleType) DeepCopyInto(out *GuestLoginModuleType) {
+	*out = *in
+	if in.GuestUser != nil {
+		in, out := &in.GuestUser, &out.GuestUser
 		*out = new(string)
 		**out = **in
 	}
-	if in.TrustStorePassword != nil {
-		in, out := &in.TrustStorePassword, &out.TrustStorePassword
+	if in.GuestRole != nil {
+		in, out := &in.GuestRole, &out.GuestRole
 		*out = new(string)
 		**out = **in
 	}
-	if in.PasswordCodec != nil {
-		in, out := &in.PasswordCodec, &out.PasswordCodec
-		*out = new(string)
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectorConfigType.
-func (in *ConnectorConfigType) DeepCopy() *ConnectorConfigType {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GuestLoginModuleType.
+func (in *GuestLoginModuleType) DeepCopy() *GuestLoginModuleType {
 	if in == nil {
 		return nil
 	}
-	out := new(ConnectorConfigType)
+	out := new(GuestLoginModuleType)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ConnectorType) DeepCopyInto(out *ConnectorType) {
+func (in *HAPairStatus) DeepCopyInto(out *HAPairStatus) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectorType.
-func (in *ConnectorType) DeepCopy() *ConnectorType {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HAPairStatus.
+func (in *HAPairStatus) DeepCopy() *HAPairStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ConnectorType)
+	out := new(HAPairStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ConsoleType) DeepCopyInto(out *ConsoleType) {
+func (in *HASharedStoreType) DeepCopyInto(out *HASharedStoreType) {
 	*out = *in
+	if in.FailoverOnShutdown != nil {
+		in, out := &in.FailoverOnShutdown, &out.FailoverOnShutdown
+		*out = new(bool)
+		**out = **in
+	}
+	if in.LockAcquisitionTimeout != nil {
+		in, out := &in.LockAcquisitionTimeout, &out.LockAcquisitionTimeout
+		*out = new(int64)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConsoleType.
-func (in *ConsoleType) DeepCopy() *ConsoleType {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HASharedStoreType.
+func (in *HASharedStoreType) DeepCopy() *HASharedStoreType {
 	if in == nil {
 		return nil
 	}
-	out := new(ConsoleType)
+	out := new(HASharedStoreType)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DefaultAccessType) DeepCopyInto(out *DefaultAccessType) {
+func (in *HAType) DeepCopyInto(out *HAType) {
 	*out = *in
-	if in.Method != nil {
-		in, out := &in.Method, &out.Method
-		*out = new(string)
+	if in.SharedStore != nil {
+		in, out := &in.SharedStore, &out.SharedStore
+		*out = new(HASharedStoreType)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VoteOnReplicationFailure != nil {
+		in, out := &in.VoteOnReplicationFailure, &out.VoteOnReplicationFailure
+		*out = new(bool)
 		**out = **in
 	}
-	if in.Roles != nil {
-		in, out := &in.Roles, &out.Roles
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.QuorumSize != nil {
+		in, out := &in.QuorumSize, &out.QuorumSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.VoteRetries != nil {
+		in, out := &in.VoteRetries, &out.VoteRetries
+		*out = new(int32)
+		**out = **in
+	}
+	if in.VoteRetryWait != nil {
+		in, out := &in.VoteRetryWait, &out.VoteRetryWait
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ZooKeeper != nil {
+		in, out := &in.ZooKeeper, &out.ZooKeeper
+		*out = new(HAZooKeeperType)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefaultAccessType.
-func (in *DefaultAccessType) DeepCopy() *DefaultAccessType {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HAType.
+func (in *HAType) DeepCopy() *HAType {
 	if in == nil {
 		return nil
 	}
-	out := new(DefaultAccessType)
+	out := new(HAType)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DeploymentPlanType) DeepCopyInto(out *DeploymentPlanType) {
+func (in *HAZooKeeperServiceType) DeepCopyInto(out *HAZooKeeperServiceType) {
 	*out = *in
-	if in.Size != nil {
-		in, out := &in.Size, &out.Size
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
 		*out = new(int32)
 		**out = **in
 	}
-	if in.MessageMigration != nil {
-		in, out := &in.MessageMigration, &out.MessageMigration
-		*out = new(bool)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HAZooKeeperServiceType.
+func (in *HAZooKeeperServiceType) DeepCopy() *HAZooKeeperServiceType {
+	if in == nil {
+		return nil
 	}
-	in.Resources.DeepCopyInto(&out.Resources)
-	out.Storage = in.Storage
-	in.ExtraMounts.DeepCopyInto(&out.ExtraMounts)
-	if in.Clustered != nil {
-		in, out := &in.Clustered, &out.Clustered
-		*out = new(bool)
-		**out = **in
+	out := new(HAZooKeeperServiceType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HAZooKeeperType) DeepCopyInto(out *HAZooKeeperType) {
+	*out = *in
+	if in.ConnectStringSecret != nil {
+		in, out := &in.ConnectStringSecret, &out.ConnectStringSecret
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
 	}
-	in.PodSecurity.DeepCopyInto(&out.PodSecurity)
-	if in.LivenessProbe != nil {
-		in, out := &in.LivenessProbe, &out.LivenessProbe
-		*out = new(v1.Probe)
+	if in.ConnectStringConfigMap != nil {
+		in, out := &in.ConnectStringConfigMap, &out.ConnectStringConfigMap
+		*out = new(v1.ConfigMapKeySelector)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.ReadinessProbe != nil {
-		in, out := &in.ReadinessProbe, &out.ReadinessProbe
-		*out = new(v1.Probe)
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(HAZooKeeperServiceType)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.EnableMetricsPlugin != nil {
-		in, out := &in.EnableMetricsPlugin, &out.EnableMetricsPlugin
+	if in.SessionTimeout != nil {
+		in, out := &in.SessionTimeout, &out.SessionTimeout
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ConnectionTimeout != nil {
+		in, out := &in.ConnectionTimeout, &out.ConnectionTimeout
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HAZooKeeperType.
+func (in *HAZooKeeperType) DeepCopy() *HAZooKeeperType {
+	if in == nil {
+		return nil
+	}
+	out := new(HAZooKeeperType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HeadlessServiceType) DeepCopyInto(out *HeadlessServiceType) {
+	*out = *in
+	if in.PublishNotReadyAddresses != nil {
+		in, out := &in.PublishNotReadyAddresses, &out.PublishNotReadyAddresses
 		*out = new(bool)
 		**out = **in
 	}
-	if in.Tolerations != nil {
-		in, out := &in.Tolerations, &out.Tolerations
-		*out = make([]v1.Toleration, len(*in))
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]v1.ServicePort, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.Labels != nil {
-		in, out := &in.Labels, &out.Labels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.NodeSelector != nil {
-		in, out := &in.NodeSelector, &out.NodeSelector
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
 		*out = make(map[string]string, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
 		}
 	}
-	in.Affinity.DeepCopyInto(&out.Affinity)
-	if in.PodSecurityContext != nil {
-		in, out := &in.PodSecurityContext, &out.PodSecurityContext
-		*out = new(v1.PodSecurityContext)
-		(*in).DeepCopyInto(*out)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HeadlessServiceType.
+func (in *HeadlessServiceType) DeepCopy() *HeadlessServiceType {
+	if in == nil {
+		return nil
 	}
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
+	out := new(HeadlessServiceType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JDBCPersistenceType) DeepCopyInto(out *JDBCPersistenceType) {
+	*out = *in
+	in.ConnectionUrlSecret.DeepCopyInto(&out.ConnectionUrlSecret)
+	if in.DataSourceProperties != nil {
+		in, out := &in.DataSourceProperties, &out.DataSourceProperties
 		*out = make(map[string]string, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
 		}
 	}
-	if in.PodDisruptionBudget != nil {
-		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
-		*out = new(policyv1.PodDisruptionBudgetSpec)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeploymentPlanType.
-func (in *DeploymentPlanType) DeepCopy() *DeploymentPlanType {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JDBCPersistenceType.
+func (in *JDBCPersistenceType) DeepCopy() *JDBCPersistenceType {
 	if in == nil {
 		return nil
 	}
-	out := new(DeploymentPlanType)
+	out := new(JDBCPersistenceType)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ExternalConfigStatus) DeepCopyInto(out *ExternalConfigStatus) {
+func (in *JVMType) DeepCopyInto(out *JVMType) {
 	*out = *in
+	if in.InitialHeapSize != nil {
+		in, out := &in.InitialHeapSize, &out.InitialHeapSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MaxHeapSize != nil {
+		in, out := &in.MaxHeapSize, &out.MaxHeapSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MaxMetaspaceSize != nil {
+		in, out := &in.MaxMetaspaceSize, &out.MaxMetaspaceSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.ExtraArgs != nil {
+		in, out := &in.ExtraArgs, &out.ExtraArgs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalConfigStatus.
-func (in *ExternalConfigStatus) DeepCopy() *ExternalConfigStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JVMType.
+func (in *JVMType) DeepCopy() *JVMType {
 	if in == nil {
 		return nil
 	}
-	out := new(ExternalConfigStatus)
+	out := new(JVMType)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ExtraMountsType) DeepCopyInto(out *ExtraMountsType) {
+func (in *JolokiaType) DeepCopyInto(out *JolokiaType) {
 	*out = *in
-	if in.ConfigMaps != nil {
-		in, out := &in.ConfigMaps, &out.ConfigMaps
+	if in.AllowedOrigins != nil {
+		in, out := &in.AllowedOrigins, &out.AllowedOrigins
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.Secrets != nil {
-		in, out := &in.Secrets, &out.Secrets
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.StrictChecking != nil {
+		in, out := &in.StrictChecking, &out.StrictChecking
+		*out = new(bool)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtraMountsType.
-func (in *ExtraMountsType) DeepCopy() *ExtraMountsType {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JolokiaType.
+func (in *JolokiaType) DeepCopy() *JolokiaType {
 	if in == nil {
 		return nil
 	}
-	out := new(ExtraMountsType)
+	out := new(JolokiaType)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GuestLoginModuleType) DeepCopyInto(out *GuestLoginModuleType) {
-	*out = *in
-	if in.GuestUser != nil {
-		in, out := &in.GuestUser, &out.GuestUser
-		*out = new(string)
-		**out = **in
+func (in *KerberosLoginModuleType) DeepCopyInto(out *KerberosLoginModuleType) {
+	*out = *in
+	in.KeytabSecret.DeepCopyInto(&out.KeytabSecret)
+	if in.Krb5ConfConfigMap != nil {
+		in, out := &in.Krb5ConfConfigMap, &out.Krb5ConfConfigMap
+		*out = new(v1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.GuestRole != nil {
-		in, out := &in.GuestRole, &out.GuestRole
+	if in.Flag != nil {
+		in, out := &in.Flag, &out.Flag
 		*out = new(string)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GuestLoginModuleType.
-func (in *GuestLoginModuleType) DeepCopy() *GuestLoginModuleType {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KerberosLoginModuleType.
+func (in *KerberosLoginModuleType) DeepCopy() *KerberosLoginModuleType {
 	if in == nil {
 		return nil
 	}
-	out := new(GuestLoginModuleType)
+	out := new(KerberosLoginModuleType)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -1335,6 +3624,13 @@ func (in *KeycloakLoginModuleType) DeepCopyInto(out *KeycloakLoginModuleType) {
 		**out = **in
 	}
 	in.Configuration.DeepCopyInto(&out.Configuration)
+	if in.RoleMappings != nil {
+		in, out := &in.RoleMappings, &out.RoleMappings
+		*out = make([]KeycloakRoleMappingType, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakLoginModuleType.
@@ -1387,6 +3683,11 @@ func (in *KeycloakModuleConfigurationType) DeepCopyInto(out *KeycloakModuleConfi
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.CredentialsSecret != nil {
+		in, out := &in.CredentialsSecret, &out.CredentialsSecret
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.UseResourceRoleMappings != nil {
 		in, out := &in.UseResourceRoleMappings, &out.UseResourceRoleMappings
 		*out = new(bool)
@@ -1571,6 +3872,108 @@ func (in *KeycloakModuleConfigurationType) DeepCopy() *KeycloakModuleConfigurati
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakRoleMappingType) DeepCopyInto(out *KeycloakRoleMappingType) {
+	*out = *in
+	if in.BrokerRoles != nil {
+		in, out := &in.BrokerRoles, &out.BrokerRoles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakRoleMappingType.
+func (in *KeycloakRoleMappingType) DeepCopy() *KeycloakRoleMappingType {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakRoleMappingType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LdapLoginModuleType) DeepCopyInto(out *LdapLoginModuleType) {
+	*out = *in
+	if in.ConnectionUsername != nil {
+		in, out := &in.ConnectionUsername, &out.ConnectionUsername
+		*out = new(string)
+		**out = **in
+	}
+	if in.ConnectionPassword != nil {
+		in, out := &in.ConnectionPassword, &out.ConnectionPassword
+		*out = new(string)
+		**out = **in
+	}
+	if in.ConnectionPasswordSecret != nil {
+		in, out := &in.ConnectionPasswordSecret, &out.ConnectionPasswordSecret
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Referral != nil {
+		in, out := &in.Referral, &out.Referral
+		*out = new(string)
+		**out = **in
+	}
+	if in.ConnectionPool != nil {
+		in, out := &in.ConnectionPool, &out.ConnectionPool
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ConnectionTimeout != nil {
+		in, out := &in.ConnectionTimeout, &out.ConnectionTimeout
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ReadTimeout != nil {
+		in, out := &in.ReadTimeout, &out.ReadTimeout
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TrustStoreSecret != nil {
+		in, out := &in.TrustStoreSecret, &out.TrustStoreSecret
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LdapLoginModuleType.
+func (in *LdapLoginModuleType) DeepCopy() *LdapLoginModuleType {
+	if in == nil {
+		return nil
+	}
+	out := new(LdapLoginModuleType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoggingType) DeepCopyInto(out *LoggingType) {
+	*out = *in
+	if in.Levels != nil {
+		in, out := &in.Levels, &out.Levels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(v1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoggingType.
+func (in *LoggingType) DeepCopy() *LoggingType {
+	if in == nil {
+		return nil
+	}
+	out := new(LoggingType)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LoginModuleReferenceType) DeepCopyInto(out *LoginModuleReferenceType) {
 	*out = *in
@@ -1630,6 +4033,34 @@ func (in *LoginModulesType) DeepCopyInto(out *LoginModulesType) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ScramLoginModules != nil {
+		in, out := &in.ScramLoginModules, &out.ScramLoginModules
+		*out = make([]ScramLoginModuleType, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.KerberosLoginModules != nil {
+		in, out := &in.KerberosLoginModules, &out.KerberosLoginModules
+		*out = make([]KerberosLoginModuleType, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CertificateLoginModules != nil {
+		in, out := &in.CertificateLoginModules, &out.CertificateLoginModules
+		*out = make([]CertificateLoginModuleType, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LdapLoginModules != nil {
+		in, out := &in.LdapLoginModules, &out.LdapLoginModules
+		*out = make([]LdapLoginModuleType, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoginModulesType.
@@ -1664,6 +4095,81 @@ func (in *ManagementSecuritySettingsType) DeepCopy() *ManagementSecuritySettings
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricsType) DeepCopyInto(out *MetricsType) {
+	*out = *in
+	if in.JvmMemory != nil {
+		in, out := &in.JvmMemory, &out.JvmMemory
+		*out = new(bool)
+		**out = **in
+	}
+	if in.JvmGc != nil {
+		in, out := &in.JvmGc, &out.JvmGc
+		*out = new(bool)
+		**out = **in
+	}
+	if in.JvmThreads != nil {
+		in, out := &in.JvmThreads, &out.JvmThreads
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsType.
+func (in *MetricsType) DeepCopy() *MetricsType {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricsType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrdinalBrokerPropertiesType) DeepCopyInto(out *OrdinalBrokerPropertiesType) {
+	*out = *in
+	if in.BrokerProperties != nil {
+		in, out := &in.BrokerProperties, &out.BrokerProperties
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrdinalBrokerPropertiesType.
+func (in *OrdinalBrokerPropertiesType) DeepCopy() *OrdinalBrokerPropertiesType {
+	if in == nil {
+		return nil
+	}
+	out := new(OrdinalBrokerPropertiesType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PasswordMaskingType) DeepCopyInto(out *PasswordMaskingType) {
+	*out = *in
+	if in.MaskPassword != nil {
+		in, out := &in.MaskPassword, &out.MaskPassword
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PasswordCodec != nil {
+		in, out := &in.PasswordCodec, &out.PasswordCodec
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PasswordMaskingType.
+func (in *PasswordMaskingType) DeepCopy() *PasswordMaskingType {
+	if in == nil {
+		return nil
+	}
+	out := new(PasswordMaskingType)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PermissionType) DeepCopyInto(out *PermissionType) {
 	*out = *in
@@ -1684,6 +4190,26 @@ func (in *PermissionType) DeepCopy() *PermissionType {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PersistenceType) DeepCopyInto(out *PersistenceType) {
+	*out = *in
+	if in.JDBC != nil {
+		in, out := &in.JDBC, &out.JDBC
+		*out = new(JDBCPersistenceType)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersistenceType.
+func (in *PersistenceType) DeepCopy() *PersistenceType {
+	if in == nil {
+		return nil
+	}
+	out := new(PersistenceType)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PodSecurityType) DeepCopyInto(out *PodSecurityType) {
 	*out = *in
@@ -1731,6 +4257,21 @@ func (in *PropertiesLoginModuleType) DeepCopy() *PropertiesLoginModuleType {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxyType) DeepCopyInto(out *ProxyType) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyType.
+func (in *ProxyType) DeepCopy() *ProxyType {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxyType)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *QueueConfigurationType) DeepCopyInto(out *QueueConfigurationType) {
 	*out = *in
@@ -1824,54 +4365,180 @@ func (in *QueueConfigurationType) DeepCopyInto(out *QueueConfigurationType) {
 		*out = new(int64)
 		**out = **in
 	}
-	if in.ConsumerPriority != nil {
-		in, out := &in.ConsumerPriority, &out.ConsumerPriority
+	if in.ConsumerPriority != nil {
+		in, out := &in.ConsumerPriority, &out.ConsumerPriority
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AutoDelete != nil {
+		in, out := &in.AutoDelete, &out.AutoDelete
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AutoDeleteDelay != nil {
+		in, out := &in.AutoDeleteDelay, &out.AutoDeleteDelay
+		*out = new(int64)
+		**out = **in
+	}
+	if in.AutoDeleteMessageCount != nil {
+		in, out := &in.AutoDeleteMessageCount, &out.AutoDeleteMessageCount
+		*out = new(int64)
+		**out = **in
+	}
+	if in.RingSize != nil {
+		in, out := &in.RingSize, &out.RingSize
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ConfigurationManaged != nil {
+		in, out := &in.ConfigurationManaged, &out.ConfigurationManaged
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Temporary != nil {
+		in, out := &in.Temporary, &out.Temporary
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AutoCreateAddress != nil {
+		in, out := &in.AutoCreateAddress, &out.AutoCreateAddress
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueueConfigurationType.
+func (in *QueueConfigurationType) DeepCopy() *QueueConfigurationType {
+	if in == nil {
+		return nil
+	}
+	out := new(QueueConfigurationType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueueMigrationEndpointType) DeepCopyInto(out *QueueMigrationEndpointType) {
+	*out = *in
+	if in.Ordinal != nil {
+		in, out := &in.Ordinal, &out.Ordinal
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueueMigrationEndpointType.
+func (in *QueueMigrationEndpointType) DeepCopy() *QueueMigrationEndpointType {
+	if in == nil {
+		return nil
+	}
+	out := new(QueueMigrationEndpointType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueueMigrationSourceStatus) DeepCopyInto(out *QueueMigrationSourceStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueueMigrationSourceStatus.
+func (in *QueueMigrationSourceStatus) DeepCopy() *QueueMigrationSourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(QueueMigrationSourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedeliveryDefaultsType) DeepCopyInto(out *RedeliveryDefaultsType) {
+	*out = *in
+	in.RedeliveryPolicyType.DeepCopyInto(&out.RedeliveryPolicyType)
+	if in.PersistDeliveryCount != nil {
+		in, out := &in.PersistDeliveryCount, &out.PersistDeliveryCount
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedeliveryDefaultsType.
+func (in *RedeliveryDefaultsType) DeepCopy() *RedeliveryDefaultsType {
+	if in == nil {
+		return nil
+	}
+	out := new(RedeliveryDefaultsType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedeliveryPolicyType) DeepCopyInto(out *RedeliveryPolicyType) {
+	*out = *in
+	if in.MaxDeliveryAttempts != nil {
+		in, out := &in.MaxDeliveryAttempts, &out.MaxDeliveryAttempts
 		*out = new(int32)
 		**out = **in
 	}
-	if in.AutoDelete != nil {
-		in, out := &in.AutoDelete, &out.AutoDelete
+	if in.AutoCreateDeadLetterResources != nil {
+		in, out := &in.AutoCreateDeadLetterResources, &out.AutoCreateDeadLetterResources
 		*out = new(bool)
 		**out = **in
 	}
-	if in.AutoDeleteDelay != nil {
-		in, out := &in.AutoDeleteDelay, &out.AutoDeleteDelay
-		*out = new(int64)
-		**out = **in
-	}
-	if in.AutoDeleteMessageCount != nil {
-		in, out := &in.AutoDeleteMessageCount, &out.AutoDeleteMessageCount
-		*out = new(int64)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedeliveryPolicyType.
+func (in *RedeliveryPolicyType) DeepCopy() *RedeliveryPolicyType {
+	if in == nil {
+		return nil
 	}
-	if in.RingSize != nil {
-		in, out := &in.RingSize, &out.RingSize
-		*out = new(int64)
-		**out = **in
+	out := new(RedeliveryPolicyType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReservedAddressPrefixesType) DeepCopyInto(out *ReservedAddressPrefixesType) {
+	*out = *in
+	if in.Prefixes != nil {
+		in, out := &in.Prefixes, &out.Prefixes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.ConfigurationManaged != nil {
-		in, out := &in.ConfigurationManaged, &out.ConfigurationManaged
-		*out = new(bool)
-		**out = **in
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.Temporary != nil {
-		in, out := &in.Temporary, &out.Temporary
-		*out = new(bool)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReservedAddressPrefixesType.
+func (in *ReservedAddressPrefixesType) DeepCopy() *ReservedAddressPrefixesType {
+	if in == nil {
+		return nil
 	}
-	if in.AutoCreateAddress != nil {
-		in, out := &in.AutoCreateAddress, &out.AutoCreateAddress
-		*out = new(bool)
+	out := new(ReservedAddressPrefixesType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetentionPolicyType) DeepCopyInto(out *RetentionPolicyType) {
+	*out = *in
+	if in.MaxMessages != nil {
+		in, out := &in.MaxMessages, &out.MaxMessages
+		*out = new(int64)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueueConfigurationType.
-func (in *QueueConfigurationType) DeepCopy() *QueueConfigurationType {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetentionPolicyType.
+func (in *RetentionPolicyType) DeepCopy() *RetentionPolicyType {
 	if in == nil {
 		return nil
 	}
-	out := new(QueueConfigurationType)
+	out := new(RetentionPolicyType)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -1908,6 +4575,159 @@ func (in *RoleAccessType) DeepCopy() *RoleAccessType {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RouteTLSType) DeepCopyInto(out *RouteTLSType) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteTLSType.
+func (in *RouteTLSType) DeepCopy() *RouteTLSType {
+	if in == nil {
+		return nil
+	}
+	out := new(RouteTLSType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScaledownDrainStatus) DeepCopyInto(out *ScaledownDrainStatus) {
+	*out = *in
+	if in.MessagesRemaining != nil {
+		in, out := &in.MessagesRemaining, &out.MessagesRemaining
+		*out = new(int64)
+		**out = **in
+	}
+	if in.BytesMoved != nil {
+		in, out := &in.BytesMoved, &out.BytesMoved
+		*out = new(int64)
+		**out = **in
+	}
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletedAt != nil {
+		in, out := &in.CompletedAt, &out.CompletedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.NextAttemptAt != nil {
+		in, out := &in.NextAttemptAt, &out.NextAttemptAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScaledownDrainStatus.
+func (in *ScaledownDrainStatus) DeepCopy() *ScaledownDrainStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ScaledownDrainStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScramLoginModuleType) DeepCopyInto(out *ScramLoginModuleType) {
+	*out = *in
+	if in.Flag != nil {
+		in, out := &in.Flag, &out.Flag
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScramLoginModuleType.
+func (in *ScramLoginModuleType) DeepCopy() *ScramLoginModuleType {
+	if in == nil {
+		return nil
+	}
+	out := new(ScramLoginModuleType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityBrokerStatus) DeepCopyInto(out *SecurityBrokerStatus) {
+	*out = *in
+	if in.UpdatedPods != nil {
+		in, out := &in.UpdatedPods, &out.UpdatedPods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PendingPods != nil {
+		in, out := &in.PendingPods, &out.PendingPods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.EffectivePermissions != nil {
+		in, out := &in.EffectivePermissions, &out.EffectivePermissions
+		*out = make([]EffectivePermissionType, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityBrokerStatus.
+func (in *SecurityBrokerStatus) DeepCopy() *SecurityBrokerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityBrokerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityCanaryStatus) DeepCopyInto(out *SecurityCanaryStatus) {
+	*out = *in
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityCanaryStatus.
+func (in *SecurityCanaryStatus) DeepCopy() *SecurityCanaryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityCanaryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityCanaryType) DeepCopyInto(out *SecurityCanaryType) {
+	*out = *in
+	if in.CredentialsSecret != nil {
+		in, out := &in.CredentialsSecret, &out.CredentialsSecret
+		*out = new(string)
+		**out = **in
+	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityCanaryType.
+func (in *SecurityCanaryType) DeepCopy() *SecurityCanaryType {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityCanaryType)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecurityDomainsType) DeepCopyInto(out *SecurityDomainsType) {
 	*out = *in
@@ -1925,6 +4745,21 @@ func (in *SecurityDomainsType) DeepCopy() *SecurityDomainsType {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityRenderStatus) DeepCopyInto(out *SecurityRenderStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityRenderStatus.
+func (in *SecurityRenderStatus) DeepCopy() *SecurityRenderStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityRenderStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecuritySettingsType) DeepCopyInto(out *SecuritySettingsType) {
 	*out = *in
@@ -1948,6 +4783,46 @@ func (in *SecuritySettingsType) DeepCopy() *SecuritySettingsType {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceMeshType) DeepCopyInto(out *ServiceMeshType) {
+	*out = *in
+	if in.HoldApplicationUntilProxyStarts != nil {
+		in, out := &in.HoldApplicationUntilProxyStarts, &out.HoldApplicationUntilProxyStarts
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceMeshType.
+func (in *ServiceMeshType) DeepCopy() *ServiceMeshType {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceMeshType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceSettingsType) DeepCopyInto(out *ServiceSettingsType) {
+	*out = *in
+	if in.InternalTrafficPolicy != nil {
+		in, out := &in.InternalTrafficPolicy, &out.InternalTrafficPolicy
+		*out = new(v1.ServiceInternalTrafficPolicyType)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceSettingsType.
+func (in *ServiceSettingsType) DeepCopy() *ServiceSettingsType {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceSettingsType)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageType) DeepCopyInto(out *StorageType) {
 	*out = *in
@@ -1986,6 +4861,16 @@ func (in *UserType) DeepCopyInto(out *UserType) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.PasswordSecret != nil {
+		in, out := &in.PasswordSecret, &out.PasswordSecret
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PasswordAlgorithm != nil {
+		in, out := &in.PasswordAlgorithm, &out.PasswordAlgorithm
+		*out = new(string)
+		**out = **in
+	}
 	if in.Roles != nil {
 		in, out := &in.Roles, &out.Roles
 		*out = make([]string, len(*in))
@@ -2003,6 +4888,21 @@ func (in *UserType) DeepCopy() *UserType {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultCredentialsSourceType) DeepCopyInto(out *VaultCredentialsSourceType) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultCredentialsSourceType.
+func (in *VaultCredentialsSourceType) DeepCopy() *VaultCredentialsSourceType {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultCredentialsSourceType)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VersionStatus) DeepCopyInto(out *VersionStatus) {
 	*out = *in
@@ -2017,3 +4917,43 @@ func (in *VersionStatus) DeepCopy() *VersionStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ZoneAwarenessType) DeepCopyInto(out *ZoneAwarenessType) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZoneAwarenessType.
+func (in *ZoneAwarenessType) DeepCopy() *ZoneAwarenessType {
+	if in == nil {
+		return nil
+	}
+	out := new(ZoneAwarenessType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ZoneStatus) DeepCopyInto(out *ZoneStatus) {
+	*out = *in
+	if in.Pods != nil {
+		in, out := &in.Pods, &out.Pods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZoneStatus.
+func (in *ZoneStatus) DeepCopy() *ZoneStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ZoneStatus)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -40,6 +40,7 @@ type ActiveMQArtemisScaledownStatus struct {
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:deprecatedversion:warning="broker.amq.io/v2alpha1 ActiveMQArtemisScaledown is deprecated, use broker.amq.io/v1beta1"
 
 // ActiveMQArtemisScaledown is the Schema for the activemqartemisscaledowns API
 type ActiveMQArtemisScaledown struct {
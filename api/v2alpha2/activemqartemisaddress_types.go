@@ -42,6 +42,7 @@ type ActiveMQArtemisAddressStatus struct {
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:deprecatedversion:warning="broker.amq.io/v2alpha2 ActiveMQArtemisAddress is deprecated, use broker.amq.io/v1beta1"
 
 // ActiveMQArtemisAddress is the Schema for the activemqartemisaddresses API
 type ActiveMQArtemisAddress struct {
@@ -188,6 +188,7 @@ type ActiveMQArtemisStatus struct {
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:deprecatedversion:warning="broker.amq.io/v2alpha3 ActiveMQArtemis is deprecated, use broker.amq.io/v1beta1"
 //+kubebuilder:resource:path=activemqartemises
 
 // ActiveMQArtemis is the Schema for the activemqartemises API
@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+)
+
+type acceptorPreset struct {
+	protocols string
+	port      int32
+	params    map[string]string
+}
+
+// the ports are the ones of the acceptors in the broker.xml that ships with the broker
+var acceptorPresets = map[brokerv1beta1.AcceptorPreset]acceptorPreset{
+	brokerv1beta1.AcceptorPresetAMQP: {
+		protocols: "AMQP",
+		port:      5672,
+		params: map[string]string{
+			"amqpCredits":            "1000",
+			"amqpLowCredits":         "300",
+			"amqpDuplicateDetection": "true",
+		},
+	},
+	brokerv1beta1.AcceptorPresetMQTT: {
+		protocols: "MQTT",
+		port:      1883,
+	},
+	brokerv1beta1.AcceptorPresetSTOMP: {
+		protocols: "STOMP",
+		port:      61613,
+		params: map[string]string{
+			"stompEnableMessageId": "true",
+		},
+	},
+	brokerv1beta1.AcceptorPresetOpenWire: {
+		protocols: "OPENWIRE",
+		port:      61617,
+	},
+	brokerv1beta1.AcceptorPresetCore: {
+		protocols: "CORE",
+		port:      61616,
+	},
+	brokerv1beta1.AcceptorPresetAll: {
+		protocols: "AMQP,CORE,HORNETQ,MQTT,OPENWIRE,STOMP",
+		port:      61616,
+	},
+}
+
+// acceptorsWithPorts returns the acceptors with their presets applied and the ports the broker
+// configuration assigns to the ones that leave the port unset
+func acceptorsWithPorts(customResource *brokerv1beta1.ActiveMQArtemis) []brokerv1beta1.AcceptorType {
+	var portIncrement int32 = 10
+	var currentPortIncrement int32 = 0
+	acceptors := []brokerv1beta1.AcceptorType{}
+	for _, acceptor := range customResource.Spec.Acceptors {
+		acceptor = applyAcceptorPreset(acceptor)
+		if acceptor.Port == 0 {
+			acceptor.Port = 61626 + currentPortIncrement
+			currentPortIncrement += portIncrement
+		}
+		acceptors = append(acceptors, acceptor)
+	}
+	return acceptors
+}
+
+// the port the acceptor listens on once its preset is applied, 0 when the operator picks one
+func acceptorPort(acceptor brokerv1beta1.AcceptorType) int32 {
+	if acceptor.Port == 0 {
+		if preset, found := acceptorPresets[acceptor.Preset]; found {
+			return preset.port
+		}
+	}
+	return acceptor.Port
+}
+
+// fills in what the acceptor leaves unset from its preset, params of the acceptor win over the preset ones
+func applyAcceptorPreset(acceptor brokerv1beta1.AcceptorType) brokerv1beta1.AcceptorType {
+	preset, found := acceptorPresets[acceptor.Preset]
+	if !found {
+		return acceptor
+	}
+
+	acceptor.Port = acceptorPort(acceptor)
+	if acceptor.Protocols == "" {
+		acceptor.Protocols = preset.protocols
+	}
+	if len(preset.params) > 0 {
+		params := make(map[string]string, len(preset.params)+len(acceptor.Params))
+		for key, value := range preset.params {
+			params[key] = value
+		}
+		for key, value := range acceptor.Params {
+			params[key] = value
+		}
+		acceptor.Params = params
+	}
+	return acceptor
+}
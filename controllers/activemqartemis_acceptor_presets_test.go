@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	"strings"
+	"testing"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/common"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAcceptorPresets(t *testing.T) {
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: "test"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			DeploymentPlan: brokerv1beta1.DeploymentPlanType{Size: common.Int32ToPtr(1)},
+			Acceptors: []brokerv1beta1.AcceptorType{
+				{Name: "amqp", Preset: brokerv1beta1.AcceptorPresetAMQP, Params: map[string]string{"amqpCredits": "500"}},
+				{Name: "mqtt", Preset: brokerv1beta1.AcceptorPresetMQTT, Port: 8883},
+				{Name: "stomp", Preset: brokerv1beta1.AcceptorPresetSTOMP, Protocols: "STOMP,CORE"},
+			},
+		},
+	}
+	namer := MakeNamers(cr)
+	assert.Nil(t, validateAcceptorPresets(cr))
+
+	acceptors := generateAcceptorsString(cr, *namer, fake.NewClientBuilder().Build(), nil)
+	assert.Contains(t, acceptors, ":5672?protocols=AMQP;amqpCredits=500;amqpDuplicateDetection=true;amqpLowCredits=300;")
+	assert.NotContains(t, strings.Split(acceptors, "<\\/acceptor>")[0], "amqpCredits=1000")
+	assert.Contains(t, acceptors, ":8883?protocols=MQTT;")
+	assert.Contains(t, acceptors, ":61613?protocols=STOMP,CORE;stompEnableMessageId=true;")
+	// the port is kept for the services
+	assert.Equal(t, int32(5672), cr.Spec.Acceptors[0].Port)
+	// the params of the CR aren't touched
+	assert.Equal(t, map[string]string{"amqpCredits": "500"}, cr.Spec.Acceptors[0].Params)
+
+	cr.Spec.Acceptors = []brokerv1beta1.AcceptorType{{Name: "core", Preset: brokerv1beta1.AcceptorPresetCore}}
+	cr.Spec.Console.Port = 61616
+	assert.NotNil(t, validateConsole(cr))
+
+	cr.Spec.Acceptors[0].Preset = "xmpp"
+	condition := validateAcceptorPresets(cr)
+	assert.NotNil(t, condition)
+	assert.Equal(t, brokerv1beta1.ValidConditionInvalidPresetReason, condition.Reason)
+}
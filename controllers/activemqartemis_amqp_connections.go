@@ -0,0 +1,250 @@
+package controllers
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	ss "github.com/artemiscloud/activemq-artemis-operator/pkg/resources/statefulsets"
+	jc "github.com/artemiscloud/activemq-artemis-operator/pkg/utils/jolokia_client"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// the broker stores the messages waiting to be mirrored in a queue named after the connection
+	mirrorQueuePrefix = "$ACTIVEMQ_ARTEMIS_MIRROR_"
+
+	amqpConnectionOrdinalPlaceholder = "{ordinal}"
+
+	// the mirror queues are read at most this often, not on every status update
+	mirrorHealthSampleInterval = 30 * time.Second
+)
+
+// mirrorSampler has the time the mirrors of each broker were last read
+type mirrorSampler struct {
+	sync.Mutex
+	sampled map[types.NamespacedName]time.Time
+}
+
+var mirrorSamples = newMirrorSampler()
+
+func newMirrorSampler() *mirrorSampler {
+	return &mirrorSampler{sampled: map[types.NamespacedName]time.Time{}}
+}
+
+// due is true when the mirrors of the broker were last read at least the sample interval before now,
+// they then count as read at now
+func (s *mirrorSampler) due(broker types.NamespacedName, now time.Time) bool {
+	s.Lock()
+	defer s.Unlock()
+
+	if last, found := s.sampled[broker]; found && now.Sub(last) < mirrorHealthSampleInterval {
+		return false
+	}
+	s.sampled[broker] = now
+	return true
+}
+
+func (s *mirrorSampler) forget(broker types.NamespacedName) {
+	s.Lock()
+	defer s.Unlock()
+
+	delete(s.sampled, broker)
+}
+
+// mirrorHealth is a sample of the mirror of a connection on a broker pod
+type mirrorHealth struct {
+	connected bool
+	backlog   int64
+}
+
+// amqpConnectionProperties renders the connections into broker properties, a url with the ordinal
+// placeholder is written per pod so each broker connects to the target of the same ordinal
+func amqpConnectionProperties(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client) []string {
+	props := []string{}
+	for _, connection := range customResource.Spec.AMQPConnections {
+		prefix := "AMQPConnections." + connection.Name + "."
+
+		if strings.Contains(connection.ConnectorUrl, amqpConnectionOrdinalPlaceholder) {
+			for i := int32(0); i < getDeploymentSize(customResource); i++ {
+				ordinal := strconv.Itoa(int(i))
+				props = append(props, OrdinalPrefix+ordinal+OrdinalPrefixSep+prefix+"uri="+strings.ReplaceAll(connection.ConnectorUrl, amqpConnectionOrdinalPlaceholder, ordinal))
+			}
+		} else {
+			props = append(props, prefix+"uri="+connection.ConnectorUrl)
+		}
+		if connection.CredentialsSecret != "" {
+			user, password, err := getConnectionCredentials(customResource, connection.CredentialsSecret, client)
+			if err != nil {
+				clog.Error(err, "unable to resolve amqp connection credentials", "secret", connection.CredentialsSecret)
+			} else {
+				props = append(props, prefix+"user="+user, prefix+"password="+password)
+			}
+		}
+		if connection.RetryInterval != nil {
+			props = append(props, fmt.Sprintf("%sretryInterval=%d", prefix, *connection.RetryInterval))
+		}
+		if connection.ReconnectAttempts != nil {
+			props = append(props, fmt.Sprintf("%sreconnectAttempts=%d", prefix, *connection.ReconnectAttempts))
+		}
+
+		mirror := connection.Mirror
+		mirrorPrefix := prefix + "connectionElements.mirror."
+		props = append(props, mirrorPrefix+"type=MIRROR")
+		for _, setting := range []struct {
+			name  string
+			value *bool
+		}{
+			{"messageAcknowledgements", mirror.MessageAcknowledgements},
+			{"queueCreation", mirror.QueueCreation},
+			{"queueRemoval", mirror.QueueRemoval},
+			{"durable", mirror.Durable},
+			{"sync", mirror.Sync},
+		} {
+			if setting.value != nil {
+				props = append(props, fmt.Sprintf("%s%s=%t", mirrorPrefix, setting.name, *setting.value))
+			}
+		}
+		if len(mirror.AddressFilter) > 0 {
+			props = append(props, mirrorPrefix+"addressFilter="+strings.Join(mirror.AddressFilter, ","))
+		}
+	}
+	return props
+}
+
+func amqpConnectionSecretNames(customResource *brokerv1beta1.ActiveMQArtemis) []string {
+	names := []string{}
+	for _, connection := range customResource.Spec.AMQPConnections {
+		if connection.CredentialsSecret != "" {
+			names = append(names, connection.CredentialsSecret)
+		}
+	}
+	return names
+}
+
+func validateAMQPConnections(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, scheme *runtime.Scheme) (*metav1.Condition, bool) {
+	invalid := func(message string, args ...interface{}) *metav1.Condition {
+		return &metav1.Condition{
+			Type:    brokerv1beta1.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  brokerv1beta1.ValidConditionInvalidAMQPConnectionReason,
+			Message: fmt.Sprintf(message, args...),
+		}
+	}
+
+	names := map[string]bool{}
+	for _, connection := range customResource.Spec.AMQPConnections {
+		if names[connection.Name] {
+			return invalid(".Spec.AMQPConnections has more than one connection named %v", connection.Name), false
+		}
+		names[connection.Name] = true
+
+		parsed, err := url.Parse(strings.ReplaceAll(connection.ConnectorUrl, amqpConnectionOrdinalPlaceholder, "0"))
+		if err != nil {
+			return invalid(".Spec.AMQPConnections %v connectorUrl %q is not valid, %v", connection.Name, connection.ConnectorUrl, err), false
+		}
+		if parsed.Hostname() == "" {
+			return invalid(".Spec.AMQPConnections %v connectorUrl %q has no host", connection.Name, connection.ConnectorUrl), false
+		}
+		for _, filter := range connection.Mirror.AddressFilter {
+			if strings.TrimPrefix(filter, "!") == "" || strings.Contains(filter, ",") {
+				return invalid(".Spec.AMQPConnections %v mirror addressFilter entry %q must be an address prefix, optionally starting with !", connection.Name, filter), false
+			}
+		}
+
+		if connection.CredentialsSecret != "" {
+			path := fmt.Sprintf(".Spec.AMQPConnections %v CredentialsSecret", connection.Name)
+			if condition := validateConnectionCredentials(customResource, connection.CredentialsSecret, path, client, scheme); condition != nil {
+				return condition, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// readMirrorHealth reads the mirror queue of every connection on every reachable broker pod, the
+// mirror consumes the queue while it is connected to its target
+func readMirrorHealth(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, namer Namers) map[string]map[string]mirrorHealth {
+	resource := types.NamespacedName{Name: customResource.Name, Namespace: customResource.Namespace}
+	health := map[string]map[string]mirrorHealth{}
+	for _, jk := range jc.GetBrokers(resource, ss.GetDeployedStatefulSetNames(client, []types.NamespacedName{resource}), client) {
+		pod := namer.SsNameBuilder.Name() + "-" + jk.Ordinal
+		for _, connection := range customResource.Spec.AMQPConnections {
+			queue := mirrorQueuePrefix + connection.Name
+			consumers, err := jk.Artemis.GetQueueConsumerCount(queue, "anycast", queue)
+			if err != nil {
+				clog.V(1).Info("unable to read mirror queue consumers", "connection", connection.Name, "pod", pod, "error", err.Error())
+				continue
+			}
+			backlog, err := jk.Artemis.GetQueueMessageCount(queue, "anycast", queue)
+			if err != nil {
+				clog.V(1).Info("unable to read mirror queue messages", "connection", connection.Name, "pod", pod, "error", err.Error())
+				continue
+			}
+			if health[connection.Name] == nil {
+				health[connection.Name] = map[string]mirrorHealth{}
+			}
+			health[connection.Name][pod] = mirrorHealth{connected: consumers > 0, backlog: backlog}
+		}
+	}
+	return health
+}
+
+// updateAMQPConnectionStatus lists the pods whose mirror is connected and the ones whose mirror isn't,
+// the MirrorDisconnected condition is only present while one isn't as a false condition would hold back Ready.
+// Between two samples the status is left as it was
+func updateAMQPConnectionStatus(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, readHealth func() map[string]map[string]mirrorHealth, sampled *mirrorSampler, now time.Time) {
+	broker := types.NamespacedName{Namespace: customResource.Namespace, Name: customResource.Name}
+	if len(customResource.Spec.AMQPConnections) == 0 {
+		sampled.forget(broker)
+		customResource.Status.AMQPConnections = nil
+		meta.RemoveStatusCondition(&customResource.Status.Conditions, brokerv1beta1.MirrorDisconnectedConditionType)
+		return
+	}
+	if !sampled.due(broker, now) {
+		return
+	}
+
+	health := readHealth()
+	statuses := []brokerv1beta1.AMQPConnectionStatus{}
+	disconnected := []string{}
+	for _, connection := range customResource.Spec.AMQPConnections {
+		status := brokerv1beta1.AMQPConnectionStatus{Name: connection.Name}
+		for i := int32(0); i < getDeploymentSize(customResource); i++ {
+			pod := namer.SsNameBuilder.Name() + "-" + strconv.Itoa(int(i))
+			sample, found := health[connection.Name][pod]
+			if !found {
+				continue
+			}
+			if sample.connected {
+				status.Connected = append(status.Connected, pod)
+			} else {
+				status.Disconnected = append(status.Disconnected, pod)
+				disconnected = append(disconnected, connection.Name+" on "+pod)
+			}
+			status.Backlog += sample.backlog
+		}
+		statuses = append(statuses, status)
+	}
+	customResource.Status.AMQPConnections = statuses
+
+	if len(disconnected) == 0 {
+		meta.RemoveStatusCondition(&customResource.Status.Conditions, brokerv1beta1.MirrorDisconnectedConditionType)
+		return
+	}
+	meta.SetStatusCondition(&customResource.Status.Conditions, metav1.Condition{
+		Type:               brokerv1beta1.MirrorDisconnectedConditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             brokerv1beta1.MirrorDisconnectedConditionReason,
+		Message:            "the mirror is not connected to its target for " + strings.Join(disconnected, ", "),
+		ObservedGeneration: customResource.Generation,
+	})
+}
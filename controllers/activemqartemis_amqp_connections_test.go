@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/common"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+)
+
+func TestMirrorAMQPConnections(t *testing.T) {
+	sync := true
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "site", Namespace: "site-ns"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			DeploymentPlan: brokerv1beta1.DeploymentPlanType{Size: common.Int32ToPtr(2)},
+			AMQPConnections: []brokerv1beta1.AMQPConnectionType{{
+				Name:              "dr",
+				ConnectorUrl:      "tcp://dr-amqp-{ordinal}-svc.dr.example.com:5672?sslEnabled=true",
+				CredentialsSecret: "dr-credentials",
+				ReconnectAttempts: common.Int32ToPtr(-1),
+				Mirror:            brokerv1beta1.AMQPMirrorType{Sync: &sync, AddressFilter: []string{"orders", "!orders.tmp"}},
+			}},
+		},
+	}
+	namer := MakeNamers(cr)
+
+	testScheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(testScheme))
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "dr-credentials", Namespace: cr.Namespace},
+		Data:       map[string][]byte{"user": []byte("mirror"), "password": []byte("secret")},
+	}
+
+	condition, retry := validateAMQPConnections(cr, fake.NewClientBuilder().WithScheme(testScheme).Build(), testScheme)
+	if assert.NotNil(t, condition) {
+		assert.Equal(t, brokerv1beta1.ValidConditionMissingResourcesReason, condition.Reason)
+		assert.True(t, retry)
+	}
+
+	c := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(secret).Build()
+	condition, _ = validateAMQPConnections(cr, c, testScheme)
+	assert.Nil(t, condition)
+	for _, invalid := range []func(*brokerv1beta1.ActiveMQArtemis){
+		func(c *brokerv1beta1.ActiveMQArtemis) {
+			c.Spec.AMQPConnections = append(c.Spec.AMQPConnections, c.Spec.AMQPConnections[0])
+		},
+		func(c *brokerv1beta1.ActiveMQArtemis) { c.Spec.AMQPConnections[0].ConnectorUrl = "tcp://:5672" },
+		func(c *brokerv1beta1.ActiveMQArtemis) { c.Spec.AMQPConnections[0].Mirror.AddressFilter = []string{"!"} },
+	} {
+		invalidCr := cr.DeepCopy()
+		invalid(invalidCr)
+		condition, _ := validateAMQPConnections(invalidCr, c, testScheme)
+		if assert.NotNil(t, condition) {
+			assert.Equal(t, brokerv1beta1.ValidConditionInvalidAMQPConnectionReason, condition.Reason)
+		}
+	}
+
+	// each broker connects to the target of its own ordinal
+	props := amqpConnectionProperties(cr, c)
+	assert.Contains(t, props, "broker-0.AMQPConnections.dr.uri=tcp://dr-amqp-0-svc.dr.example.com:5672?sslEnabled=true")
+	assert.Contains(t, props, "broker-1.AMQPConnections.dr.uri=tcp://dr-amqp-1-svc.dr.example.com:5672?sslEnabled=true")
+	assert.Contains(t, props, "AMQPConnections.dr.user=mirror")
+	assert.Contains(t, props, "AMQPConnections.dr.password=secret")
+	assert.Contains(t, props, "AMQPConnections.dr.reconnectAttempts=-1")
+	assert.Contains(t, props, "AMQPConnections.dr.connectionElements.mirror.type=MIRROR")
+	assert.Contains(t, props, "AMQPConnections.dr.connectionElements.mirror.sync=true")
+	assert.Contains(t, props, "AMQPConnections.dr.connectionElements.mirror.addressFilter=orders,!orders.tmp")
+	assert.NotContains(t, props, "AMQPConnections.dr.connectionElements.mirror.durable=true")
+	data := brokerPropertiesData(props)
+	assert.Contains(t, data["broker-1."+BrokerPropertiesName], "AMQPConnections.dr.uri=tcp://dr-amqp-1-svc")
+	assert.NotContains(t, data[BrokerPropertiesName], "AMQPConnections.dr.uri")
+
+	assert.Equal(t, []string{"dr-credentials"}, amqpConnectionSecretNames(cr))
+
+	health := map[string]map[string]mirrorHealth{"dr": {
+		"site-ss-0": {connected: true, backlog: 3},
+		"site-ss-1": {connected: false, backlog: 40},
+	}}
+	sampled := newMirrorSampler()
+	now := time.Now()
+	updateAMQPConnectionStatus(cr, *namer, func() map[string]map[string]mirrorHealth { return health }, sampled, now)
+	assert.Equal(t, []brokerv1beta1.AMQPConnectionStatus{{Name: "dr", Connected: []string{"site-ss-0"}, Disconnected: []string{"site-ss-1"}, Backlog: 43}}, cr.Status.AMQPConnections)
+	disconnected := meta.FindStatusCondition(cr.Status.Conditions, brokerv1beta1.MirrorDisconnectedConditionType)
+	if assert.NotNil(t, disconnected) {
+		assert.Equal(t, metav1.ConditionTrue, disconnected.Status)
+		assert.Contains(t, disconnected.Message, "dr on site-ss-1")
+	}
+
+	// the brokers are not asked again before the interval passed
+	updateAMQPConnectionStatus(cr, *namer, func() map[string]map[string]mirrorHealth {
+		t.Fatal("read within the sample interval")
+		return nil
+	}, sampled, now.Add(mirrorHealthSampleInterval/2))
+	assert.NotNil(t, meta.FindStatusCondition(cr.Status.Conditions, brokerv1beta1.MirrorDisconnectedConditionType))
+
+	health["dr"]["site-ss-1"] = mirrorHealth{connected: true}
+	updateAMQPConnectionStatus(cr, *namer, func() map[string]map[string]mirrorHealth { return health }, sampled, now.Add(mirrorHealthSampleInterval))
+	assert.Nil(t, meta.FindStatusCondition(cr.Status.Conditions, brokerv1beta1.MirrorDisconnectedConditionType))
+
+	cr.Spec.AMQPConnections = nil
+	updateAMQPConnectionStatus(cr, *namer, func() map[string]map[string]mirrorHealth {
+		t.Fatal("no connection to read")
+		return nil
+	}, sampled, now.Add(2*mirrorHealthSampleInterval))
+	assert.Nil(t, cr.Status.AMQPConnections)
+
+	// a broker is forgotten on its own, not with the ones whose name it prefixes
+	other := types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name + "-bar"}
+	assert.True(t, sampled.due(other, now))
+	sampled.forget(types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name})
+	assert.False(t, sampled.due(other, now))
+}
@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"sync"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// one series per cr, with the API version the cr was last applied with
+var appliedAPIVersionInfo = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "activemq_artemis_cr_applied_api_version_info",
+	Help: "The API version an ActiveMQArtemis CR was last applied with, deprecated is true until it is applied with broker.amq.io/v1beta1",
+}, []string{"namespace", "name", "api_version", "deprecated"})
+
+var appliedAPIVersionLabels = struct {
+	sync.Mutex
+	labels map[types.NamespacedName]prometheus.Labels
+}{labels: map[types.NamespacedName]prometheus.Labels{}}
+
+func init() {
+	metrics.Registry.MustRegister(appliedAPIVersionInfo)
+}
+
+// appliedAPIVersion is the API version of the most recent change to the cr that is not a status
+// update, the API server records it in the managed fields whatever version the cr is stored as
+func appliedAPIVersion(cr *brokerv1beta1.ActiveMQArtemis) string {
+	var applied *metav1.ManagedFieldsEntry
+	for i := range cr.ManagedFields {
+		entry := &cr.ManagedFields[i]
+		if entry.Subresource == "status" || entry.Time == nil {
+			continue
+		}
+		if applied == nil || !entry.Time.Before(applied.Time) {
+			applied = entry
+		}
+	}
+	if applied == nil {
+		return ""
+	}
+	return applied.APIVersion
+}
+
+func isDeprecatedAPIVersion(apiVersion string) bool {
+	return apiVersion != "" && apiVersion != brokerv1beta1.GroupVersion.String()
+}
+
+func updateAPIVersionStatus(cr *brokerv1beta1.ActiveMQArtemis) {
+	apiVersion := appliedAPIVersion(cr)
+	if apiVersion == "" {
+		return
+	}
+	deprecated := isDeprecatedAPIVersion(apiVersion)
+	if deprecated && apiVersion != cr.Status.AppliedAPIVersion {
+		ctrl.Log.WithValues("ActiveMQArtemis Name", cr.Name).Info("CR applied with a deprecated API version, apply it with "+brokerv1beta1.GroupVersion.String(), "apiVersion", apiVersion)
+	}
+	cr.Status.AppliedAPIVersion = apiVersion
+	cr.Status.DeprecatedAPIVersion = deprecated
+
+	setAppliedAPIVersionMetric(types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name}, apiVersion, deprecated)
+}
+
+func setAppliedAPIVersionMetric(name types.NamespacedName, apiVersion string, deprecated bool) {
+	labels := prometheus.Labels{
+		"namespace":   name.Namespace,
+		"name":        name.Name,
+		"api_version": apiVersion,
+		"deprecated":  "false",
+	}
+	if deprecated {
+		labels["deprecated"] = "true"
+	}
+
+	appliedAPIVersionLabels.Lock()
+	defer appliedAPIVersionLabels.Unlock()
+
+	if previous, found := appliedAPIVersionLabels.labels[name]; found && previous["api_version"] != apiVersion {
+		appliedAPIVersionInfo.Delete(previous)
+	}
+	appliedAPIVersionLabels.labels[name] = labels
+	appliedAPIVersionInfo.With(labels).Set(1)
+}
+
+func deleteAppliedAPIVersionMetric(name types.NamespacedName) {
+	appliedAPIVersionLabels.Lock()
+	defer appliedAPIVersionLabels.Unlock()
+
+	if previous, found := appliedAPIVersionLabels.labels[name]; found {
+		appliedAPIVersionInfo.Delete(previous)
+		delete(appliedAPIVersionLabels.labels, name)
+	}
+}
@@ -0,0 +1,49 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestAppliedAPIVersionStatus(t *testing.T) {
+	earlier := metav1.NewTime(time.Date(2022, 1, 1, 10, 0, 0, 0, time.UTC))
+	later := metav1.NewTime(earlier.Add(time.Hour))
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "broker",
+			Namespace: "old",
+			ManagedFields: []metav1.ManagedFieldsEntry{
+				{Manager: "kubectl", Operation: metav1.ManagedFieldsOperationUpdate, APIVersion: "broker.amq.io/v2alpha5", Time: &earlier},
+				{Manager: "operator", Operation: metav1.ManagedFieldsOperationUpdate, APIVersion: "broker.amq.io/v1beta1", Time: &later, Subresource: "status"},
+			},
+		},
+	}
+	name := types.NamespacedName{Name: "broker", Namespace: "old"}
+	series := func(apiVersion string, deprecated string) float64 {
+		return testutil.ToFloat64(appliedAPIVersionInfo.With(prometheus.Labels{"namespace": "old", "name": "broker", "api_version": apiVersion, "deprecated": deprecated}))
+	}
+
+	// status updates of the operator don't count as applying the cr
+	updateAPIVersionStatus(cr)
+	assert.Equal(t, "broker.amq.io/v2alpha5", cr.Status.AppliedAPIVersion)
+	assert.True(t, cr.Status.DeprecatedAPIVersion)
+	assert.Equal(t, float64(1), series("broker.amq.io/v2alpha5", "true"))
+
+	cr.ManagedFields = append(cr.ManagedFields, metav1.ManagedFieldsEntry{Manager: "kubectl", Operation: metav1.ManagedFieldsOperationApply, APIVersion: "broker.amq.io/v1beta1", Time: &later})
+	updateAPIVersionStatus(cr)
+	assert.Equal(t, "broker.amq.io/v1beta1", cr.Status.AppliedAPIVersion)
+	assert.False(t, cr.Status.DeprecatedAPIVersion)
+	assert.Equal(t, 1, testutil.CollectAndCount(appliedAPIVersionInfo))
+	assert.Equal(t, float64(1), series("broker.amq.io/v1beta1", "false"))
+
+	deleteAppliedAPIVersionMetric(name)
+	assert.Equal(t, 0, testutil.CollectAndCount(appliedAPIVersionInfo))
+}
@@ -0,0 +1,233 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/resources"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	scaledObjectAPIVersion             = "keda.sh/v1alpha1"
+	scaledObjectKind                   = "ScaledObject"
+	defaultAutoscalingMinReplicas      = 1
+	defaultAutoscalingPollingInterval  = 30
+	defaultAutoscalingScaleInPeriodSec = 300
+
+	// KEDA holds the scale target at this count and stops evaluating the triggers while it is set
+	scaledObjectPausedReplicasAnnotation = "autoscaling.keda.sh/paused-replicas"
+)
+
+func scaledObjectName(customResource *brokerv1beta1.ActiveMQArtemis) string {
+	return customResource.Name + "-autoscaling"
+}
+
+func isAutoscalingEnabled(customResource *brokerv1beta1.ActiveMQArtemis) bool {
+	return customResource.Spec.Autoscaling != nil && customResource.Spec.Autoscaling.Enabled
+}
+
+// the queue depth comes from the artemis_message_count metric of the metrics plugin, summed over
+// the broker pods of the cr
+func queueDepthQuery(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, queue string) string {
+	return fmt.Sprintf(`sum(artemis_message_count{namespace="%v",pod=~"%v-[0-9]+",queue="%v"})`, customResource.Namespace, namer.SsNameBuilder.Name(), queue)
+}
+
+// a drain runs until the drainer pod has migrated the messages of a removed broker, another scale
+// in before that would remove the target of the drain
+func isMessageMigrationDraining(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client) bool {
+	scaledown := &brokerv1beta1.ActiveMQArtemisScaledown{}
+	err := client.Get(context.TODO(), types.NamespacedName{Name: customResource.Name, Namespace: customResource.Namespace}, scaledown)
+	if err != nil || !metav1.IsControlledBy(scaledown, customResource) {
+		return false
+	}
+	for _, drain := range scaledown.Status.Drains {
+		if drain.Phase != brokerv1beta1.ScaledownDrainSucceeded {
+			return true
+		}
+	}
+	return false
+}
+
+// newScaledObject targets the scale subresource of the cr rather than the StatefulSet, KEDA changes
+// the deploymentPlan size and the operator rolls it out like any other change of the size. A scale
+// in removes one broker per period so that the messages of a broker are migrated before the next
+// one goes
+func newScaledObject(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, draining bool) *unstructured.Unstructured {
+	autoscaling := customResource.Spec.Autoscaling
+
+	minReplicas := int64(defaultAutoscalingMinReplicas)
+	if autoscaling.MinReplicas != nil {
+		minReplicas = int64(*autoscaling.MinReplicas)
+	}
+	pollingInterval := int64(defaultAutoscalingPollingInterval)
+	if autoscaling.PollingInterval != nil {
+		pollingInterval = int64(*autoscaling.PollingInterval)
+	}
+	scaleInPeriod := int64(defaultAutoscalingScaleInPeriodSec)
+	if autoscaling.ScaleInPeriodSeconds != nil {
+		scaleInPeriod = int64(*autoscaling.ScaleInPeriodSeconds)
+	}
+
+	triggers := []interface{}{}
+	for _, queue := range autoscaling.Queues {
+		triggers = append(triggers, map[string]interface{}{
+			"type": "prometheus",
+			"metadata": map[string]interface{}{
+				"serverAddress": autoscaling.ServerAddress,
+				"query":         queueDepthQuery(customResource, namer, queue.Name),
+				"threshold":     strconv.FormatInt(queue.TargetMessagesPerBroker, 10),
+			},
+		})
+	}
+
+	scaledObject := &unstructured.Unstructured{}
+	scaledObject.SetAPIVersion(scaledObjectAPIVersion)
+	scaledObject.SetKind(scaledObjectKind)
+	scaledObject.SetName(scaledObjectName(customResource))
+	scaledObject.SetNamespace(customResource.Namespace)
+	scaledObject.SetLabels(namer.LabelBuilder.Labels())
+	if draining {
+		scaledObject.SetAnnotations(map[string]string{
+			scaledObjectPausedReplicasAnnotation: strconv.FormatInt(int64(getDeploymentSize(customResource)), 10),
+		})
+	}
+	scaledObject.Object["spec"] = map[string]interface{}{
+		"scaleTargetRef": map[string]interface{}{
+			"apiVersion": brokerv1beta1.GroupVersion.String(),
+			"kind":       "ActiveMQArtemis",
+			"name":       customResource.Name,
+		},
+		"pollingInterval": pollingInterval,
+		"minReplicaCount": minReplicas,
+		"maxReplicaCount": int64(autoscaling.MaxReplicas),
+		"advanced": map[string]interface{}{
+			"horizontalPodAutoscalerConfig": map[string]interface{}{
+				"behavior": map[string]interface{}{
+					"scaleDown": map[string]interface{}{
+						"stabilizationWindowSeconds": scaleInPeriod,
+						"policies": []interface{}{
+							map[string]interface{}{
+								"type":          "Pods",
+								"value":         int64(1),
+								"periodSeconds": scaleInPeriod,
+							},
+						},
+					},
+				},
+			},
+		},
+		"triggers": triggers,
+	}
+	return scaledObject
+}
+
+// the KEDA types are not part of the scheme, the ScaledObject is applied directly like the
+// certificates. Without KEDA installed there is no ScaledObject to remove
+func (reconciler *ActiveMQArtemisReconcilerImpl) applyAutoscaling(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, client rtclient.Client, scheme *runtime.Scheme) {
+	existing := &unstructured.Unstructured{}
+	existing.SetAPIVersion(scaledObjectAPIVersion)
+	existing.SetKind(scaledObjectKind)
+	err := client.Get(context.TODO(), types.NamespacedName{Name: scaledObjectName(customResource), Namespace: customResource.Namespace}, existing)
+	if meta.IsNoMatchError(err) && !isAutoscalingEnabled(customResource) {
+		return
+	}
+
+	if !isAutoscalingEnabled(customResource) {
+		if err == nil && metav1.IsControlledBy(existing, customResource) {
+			if err = resources.Delete(client, existing); err != nil && !k8serrors.IsNotFound(err) {
+				clog.Error(err, "unable to delete scaled object", "name", existing.GetName())
+			}
+		}
+		return
+	}
+
+	desired := newScaledObject(customResource, namer, isMessageMigrationDraining(customResource, client))
+	if k8serrors.IsNotFound(err) {
+		if err = resources.Create(customResource, client, scheme, desired); err != nil {
+			clog.Error(err, "unable to create scaled object", "name", desired.GetName())
+		}
+		return
+	}
+	if err != nil {
+		clog.Error(err, "unable to retrieve scaled object, is KEDA installed?", "name", desired.GetName())
+		return
+	}
+	pausedReplicas, paused := desired.GetAnnotations()[scaledObjectPausedReplicasAnnotation]
+	existingPausedReplicas, existingPaused := existing.GetAnnotations()[scaledObjectPausedReplicasAnnotation]
+	if equality.Semantic.DeepEqual(existing.Object["spec"], desired.Object["spec"]) && paused == existingPaused && pausedReplicas == existingPausedReplicas {
+		return
+	}
+	existing.Object["spec"] = desired.Object["spec"]
+	annotations := existing.GetAnnotations()
+	if paused {
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[scaledObjectPausedReplicasAnnotation] = pausedReplicas
+	} else {
+		delete(annotations, scaledObjectPausedReplicasAnnotation)
+	}
+	existing.SetAnnotations(annotations)
+	if err = resources.Update(client, existing); err != nil {
+		clog.Error(err, "unable to update scaled object", "name", existing.GetName())
+	}
+}
+
+func validateAutoscaling(customResource *brokerv1beta1.ActiveMQArtemis) *metav1.Condition {
+	autoscaling := customResource.Spec.Autoscaling
+	if !autoscaling.Enabled {
+		return nil
+	}
+	invalid := func(message string) *metav1.Condition {
+		return &metav1.Condition{
+			Type:    brokerv1beta1.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  brokerv1beta1.ValidConditionInvalidAutoscalingReason,
+			Message: ".Spec.Autoscaling " + message,
+		}
+	}
+
+	minReplicas := int32(defaultAutoscalingMinReplicas)
+	if autoscaling.MinReplicas != nil {
+		minReplicas = *autoscaling.MinReplicas
+	}
+	if autoscaling.MaxReplicas < minReplicas {
+		return invalid(fmt.Sprintf("maxReplicas %d must not be below minReplicas %d", autoscaling.MaxReplicas, minReplicas))
+	}
+	if autoscaling.ServerAddress == "" {
+		return invalid("serverAddress is required, KEDA reads the depth of the queues from Prometheus")
+	}
+	if len(autoscaling.Queues) == 0 {
+		return invalid("needs at least one queue")
+	}
+	seen := map[string]bool{}
+	for _, queue := range autoscaling.Queues {
+		if queue.Name == "" {
+			return invalid("queue name is required")
+		}
+		if seen[queue.Name] {
+			return invalid(fmt.Sprintf("lists queue %v twice", queue.Name))
+		}
+		seen[queue.Name] = true
+		if queue.TargetMessagesPerBroker < 1 {
+			return invalid(fmt.Sprintf("targetMessagesPerBroker of queue %v must be at least 1", queue.Name))
+		}
+	}
+	if !isMetricsPluginEnabled(customResource) {
+		return invalid("needs the metrics plugin, set .Spec.DeploymentPlan.Metrics or .Spec.DeploymentPlan.EnableMetricsPlugin")
+	}
+	if migration := customResource.Spec.DeploymentPlan.MessageMigration; requiresPersistentVolume(customResource) && (migration == nil || !*migration) {
+		return invalid("with persistence needs .Spec.DeploymentPlan.MessageMigration, a scale in would leave the messages of the removed brokers on their volumes")
+	}
+	return nil
+}
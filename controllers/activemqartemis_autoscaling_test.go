@@ -0,0 +1,132 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/common"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestKedaAutoscaling(t *testing.T) {
+	migrate := true
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: "test", UID: "broker-uid"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			DeploymentPlan: brokerv1beta1.DeploymentPlanType{
+				PersistenceEnabled: true,
+				MessageMigration:   &migrate,
+				Metrics:            &brokerv1beta1.MetricsType{},
+			},
+			Autoscaling: &brokerv1beta1.AutoscalingType{
+				Enabled:              true,
+				MinReplicas:          common.Int32ToPtr(2),
+				MaxReplicas:          5,
+				ServerAddress:        "http://prometheus.monitoring:9090",
+				Queues:               []brokerv1beta1.AutoscalingQueueType{{Name: "orders", TargetMessagesPerBroker: 1000}},
+				ScaleInPeriodSeconds: common.Int32ToPtr(600),
+			},
+		},
+	}
+	namer := MakeNamers(cr)
+	fakeClient := fake.NewClientBuilder().WithScheme(newTestScheme(t)).Build()
+	assert.Nil(t, validateAutoscaling(cr))
+
+	reconciler := &ActiveMQArtemisReconcilerImpl{}
+	reconciler.applyAutoscaling(cr, *namer, fakeClient, nil)
+	get := func() (*unstructured.Unstructured, error) {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(scaledObjectAPIVersion)
+		obj.SetKind(scaledObjectKind)
+		return obj, fakeClient.Get(context.TODO(), types.NamespacedName{Name: "broker-autoscaling", Namespace: "test"}, obj)
+	}
+	scaledObject, err := get()
+	assert.NoError(t, err)
+
+	// KEDA drives the size of the cr through its scale subresource, not the statefulset
+	kind, _, _ := unstructured.NestedString(scaledObject.Object, "spec", "scaleTargetRef", "kind")
+	assert.Equal(t, "ActiveMQArtemis", kind)
+	apiVersion, _, _ := unstructured.NestedString(scaledObject.Object, "spec", "scaleTargetRef", "apiVersion")
+	assert.Equal(t, "broker.amq.io/v1beta1", apiVersion)
+	minReplicas, _, _ := unstructured.NestedInt64(scaledObject.Object, "spec", "minReplicaCount")
+	assert.Equal(t, int64(2), minReplicas)
+	maxReplicas, _, _ := unstructured.NestedInt64(scaledObject.Object, "spec", "maxReplicaCount")
+	assert.Equal(t, int64(5), maxReplicas)
+	triggers, _, _ := unstructured.NestedSlice(scaledObject.Object, "spec", "triggers")
+	assert.Len(t, triggers, 1)
+	trigger := triggers[0].(map[string]interface{})
+	assert.Equal(t, "prometheus", trigger["type"])
+	metadata := trigger["metadata"].(map[string]interface{})
+	assert.Equal(t, `sum(artemis_message_count{namespace="test",pod=~"broker-ss-[0-9]+",queue="orders"})`, metadata["query"])
+	assert.Equal(t, "1000", metadata["threshold"])
+
+	// one broker at a time leaves so that its messages are migrated before the next one goes
+	scaleDown, _, _ := unstructured.NestedMap(scaledObject.Object, "spec", "advanced", "horizontalPodAutoscalerConfig", "behavior", "scaleDown")
+	assert.Equal(t, int64(600), scaleDown["stabilizationWindowSeconds"])
+	policy := scaleDown["policies"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "Pods", policy["type"])
+	assert.Equal(t, int64(1), policy["value"])
+
+	cr.Spec.Autoscaling.Queues[0].TargetMessagesPerBroker = 500
+	reconciler.applyAutoscaling(cr, *namer, fakeClient, nil)
+	scaledObject, _ = get()
+	triggers, _, _ = unstructured.NestedSlice(scaledObject.Object, "spec", "triggers")
+	assert.Equal(t, "500", triggers[0].(map[string]interface{})["metadata"].(map[string]interface{})["threshold"])
+
+	// KEDA holds the size while the messages of a removed broker are migrated
+	cr.Spec.DeploymentPlan.Size = common.Int32ToPtr(3)
+	isController := true
+	scaledown := &brokerv1beta1.ActiveMQArtemisScaledown{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "broker",
+			Namespace:       "test",
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: brokerv1beta1.GroupVersion.String(), Kind: "ActiveMQArtemis", Name: "broker", UID: "broker-uid", Controller: &isController}},
+		},
+		Status: brokerv1beta1.ActiveMQArtemisScaledownStatus{
+			Drains: []brokerv1beta1.ScaledownDrainStatus{{Pod: "broker-ss-3", TargetPod: "broker-ss-0", Phase: brokerv1beta1.ScaledownDrainDraining}},
+		},
+	}
+	assert.NoError(t, fakeClient.Create(context.TODO(), scaledown))
+	reconciler.applyAutoscaling(cr, *namer, fakeClient, nil)
+	scaledObject, _ = get()
+	assert.Equal(t, "3", scaledObject.GetAnnotations()["autoscaling.keda.sh/paused-replicas"])
+
+	scaledown.Status.Drains[0].Phase = brokerv1beta1.ScaledownDrainSucceeded
+	assert.NoError(t, fakeClient.Update(context.TODO(), scaledown))
+	reconciler.applyAutoscaling(cr, *namer, fakeClient, nil)
+	scaledObject, _ = get()
+	assert.NotContains(t, scaledObject.GetAnnotations(), "autoscaling.keda.sh/paused-replicas")
+
+	cr.Spec.Autoscaling.Enabled = false
+	reconciler.applyAutoscaling(cr, *namer, fakeClient, nil)
+	_, err = get()
+	assert.True(t, k8serrors.IsNotFound(err))
+	assert.Nil(t, validateAutoscaling(cr))
+	cr.Spec.Autoscaling.Enabled = true
+
+	for _, invalid := range []func(*brokerv1beta1.ActiveMQArtemis){
+		func(c *brokerv1beta1.ActiveMQArtemis) { c.Spec.Autoscaling.MaxReplicas = 1 },
+		func(c *brokerv1beta1.ActiveMQArtemis) { c.Spec.Autoscaling.ServerAddress = "" },
+		func(c *brokerv1beta1.ActiveMQArtemis) { c.Spec.Autoscaling.Queues = nil },
+		func(c *brokerv1beta1.ActiveMQArtemis) {
+			c.Spec.Autoscaling.Queues = append(c.Spec.Autoscaling.Queues, c.Spec.Autoscaling.Queues[0])
+		},
+		func(c *brokerv1beta1.ActiveMQArtemis) { c.Spec.Autoscaling.Queues[0].TargetMessagesPerBroker = 0 },
+		func(c *brokerv1beta1.ActiveMQArtemis) { c.Spec.DeploymentPlan.Metrics = nil },
+		func(c *brokerv1beta1.ActiveMQArtemis) { c.Spec.DeploymentPlan.MessageMigration = nil },
+	} {
+		invalidCR := cr.DeepCopy()
+		invalid(invalidCR)
+		condition := validateAutoscaling(invalidCR)
+		if assert.NotNil(t, condition) {
+			assert.Equal(t, brokerv1beta1.ValidConditionInvalidAutoscalingReason, condition.Reason)
+		}
+	}
+}
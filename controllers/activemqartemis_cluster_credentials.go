@@ -0,0 +1,397 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/resources/secrets"
+	ss "github.com/artemiscloud/activemq-artemis-operator/pkg/resources/statefulsets"
+	mgmt "github.com/artemiscloud/activemq-artemis-operator/pkg/utils/artemis"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/jolokia"
+	jc "github.com/artemiscloud/activemq-artemis-operator/pkg/utils/jolokia_client"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/random"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// a new value on the CR starts a rotation of the cluster credentials
+	RotateClusterCredentialsAnnotation = "broker.amq.io/rotateClusterCredentialsAt"
+	// a new value on the CR starts a rotation of the generated admin credentials
+	RotateAdminCredentialsAnnotation = "broker.amq.io/rotateAdminCredentialsAt"
+	// the start of the rotation the pod template was rolled for
+	clusterCredentialsRotatedAtAnnotation = "broker.amq.io/clusterCredentialsRotatedAt"
+	adminCredentialsRotatedAtAnnotation   = "broker.amq.io/adminCredentialsRotatedAt"
+
+	clusterCredentialsRotationSuffix = "-cluster-credentials-rotation"
+	adminCredentialsRotationSuffix   = "-admin-credentials-rotation"
+	rotationStartedAtKey             = "startedAt"
+	nextUserKey                      = "nextUser"
+	nextPasswordKey                  = "nextPassword"
+	previousUserKey                  = "previousUser"
+	previousPasswordKey              = "previousPassword"
+
+	clusterCredentialRotationTriggerAnnotation = "annotation"
+	clusterCredentialRotationTriggerSchedule   = "schedule"
+)
+
+// credentialRotation is a user and password pair of the credentials secret the operator rotates
+type credentialRotation struct {
+	name                string
+	annotation          string
+	rotatedAtAnnotation string
+	secretSuffix        string
+	userKey             string
+	passwordKey         string
+	// the cluster credentials are only in use once the brokers formed the cluster with them
+	verifyTopology bool
+	// only credentials the operator generated are rotated
+	generated func(*brokerv1beta1.ActiveMQArtemis) bool
+	schedule  func(*brokerv1beta1.ActiveMQArtemis) *brokerv1beta1.CredentialRotationType
+	status    func(*brokerv1beta1.ActiveMQArtemis) **brokerv1beta1.CredentialRotationStatus
+}
+
+var clusterCredentialRotation = &credentialRotation{
+	name:                "cluster",
+	annotation:          RotateClusterCredentialsAnnotation,
+	rotatedAtAnnotation: clusterCredentialsRotatedAtAnnotation,
+	secretSuffix:        clusterCredentialsRotationSuffix,
+	userKey:             "AMQ_CLUSTER_USER",
+	passwordKey:         "AMQ_CLUSTER_PASSWORD",
+	verifyTopology:      true,
+	generated: func(customResource *brokerv1beta1.ActiveMQArtemis) bool {
+		return !credentialsSourceSkipsEnvVar(customResource, "AMQ_CLUSTER_USER")
+	},
+	schedule: func(customResource *brokerv1beta1.ActiveMQArtemis) *brokerv1beta1.CredentialRotationType {
+		return customResource.Spec.ClusterCredentialRotation
+	},
+	status: func(customResource *brokerv1beta1.ActiveMQArtemis) **brokerv1beta1.CredentialRotationStatus {
+		return &customResource.Status.ClusterCredentialRotation
+	},
+}
+
+// the operator and the console log in with the admin user unless a jolokia user is configured, so
+// the brokers accept both users until every broker restarted with the new one
+var adminCredentialRotation = &credentialRotation{
+	name:                "admin",
+	annotation:          RotateAdminCredentialsAnnotation,
+	rotatedAtAnnotation: adminCredentialsRotatedAtAnnotation,
+	secretSuffix:        adminCredentialsRotationSuffix,
+	userKey:             "AMQ_USER",
+	passwordKey:         "AMQ_PASSWORD",
+	generated: func(customResource *brokerv1beta1.ActiveMQArtemis) bool {
+		return customResource.Spec.AdminUser == "" && customResource.Spec.AdminPassword == "" &&
+			!credentialsSourceSkipsEnvVar(customResource, "AMQ_USER")
+	},
+	schedule: func(customResource *brokerv1beta1.ActiveMQArtemis) *brokerv1beta1.CredentialRotationType {
+		return customResource.Spec.AdminCredentialRotation
+	},
+	status: func(customResource *brokerv1beta1.ActiveMQArtemis) **brokerv1beta1.CredentialRotationStatus {
+		return &customResource.Status.AdminCredentialRotation
+	},
+}
+
+var credentialRotations = []*credentialRotation{clusterCredentialRotation, adminCredentialRotation}
+
+// the management operations a rotation needs from a broker, a broker only takes a connection
+// with other credentials than its own cluster credentials from a user of its login module
+type clusterUserManager interface {
+	AddUser(userName string, password string, roles string) (*jolokia.ResponseData, error)
+	RemoveUser(userName string) (*jolokia.ResponseData, error)
+	GetClusterTopologySize() (int, error)
+}
+
+type rotationBroker struct {
+	manager clusterUserManager
+	// whether the pod runs with the new cluster credentials
+	rotated bool
+}
+
+func (credentials *credentialRotation) secretName(customResource *brokerv1beta1.ActiveMQArtemis) string {
+	return customResource.Name + credentials.secretSuffix
+}
+
+func rotationStamp(rotation *brokerv1beta1.CredentialRotationStatus) string {
+	return rotation.StartedAt.UTC().Format(time.RFC3339)
+}
+
+func rotationInProgress(rotation *brokerv1beta1.CredentialRotationStatus) bool {
+	return rotation != nil && rotation.Phase != brokerv1beta1.CredentialRotationCompleted
+}
+
+// the pod template keeps the start of the last rotation, it only changes once the brokers
+// accept the new credentials
+func (credentials *credentialRotation) rotatedAt(customResource *brokerv1beta1.ActiveMQArtemis, deployed *appsv1.StatefulSet) string {
+	rotation := *credentials.status(customResource)
+	if rotation != nil && rotation.StartedAt != nil && rotation.Phase != brokerv1beta1.CredentialRotationPreparing {
+		return rotationStamp(rotation)
+	}
+	if deployed != nil {
+		return deployed.Spec.Template.Annotations[credentials.rotatedAtAnnotation]
+	}
+	return ""
+}
+
+// a rotation is due when the annotation holds a value no rotation was started for, or when the
+// interval has passed since the last rotation completed
+func (credentials *credentialRotation) trigger(customResource *brokerv1beta1.ActiveMQArtemis, now time.Time) string {
+	if !credentials.generated(customResource) {
+		return ""
+	}
+	rotation := *credentials.status(customResource)
+	requested := customResource.Annotations[credentials.annotation]
+	if requested != "" && (rotation == nil || requested != rotation.Requested) {
+		return clusterCredentialRotationTriggerAnnotation
+	}
+	schedule := credentials.schedule(customResource)
+	if schedule == nil || schedule.Interval == "" {
+		return ""
+	}
+	interval, err := time.ParseDuration(schedule.Interval)
+	if err != nil {
+		return ""
+	}
+	since := customResource.CreationTimestamp.Time
+	if rotation != nil && rotation.CompletedAt != nil {
+		since = rotation.CompletedAt.Time
+	}
+	if !now.Before(since.Add(interval)) {
+		return clusterCredentialRotationTriggerSchedule
+	}
+	return ""
+}
+
+// rotateCredentials moves a rotation on by at most one phase per reconcile. Until the new
+// credentials are in use it returns empty values, after that the new credentials, which replace
+// the ones of the credentials secret
+func (reconciler *ActiveMQArtemisReconcilerImpl) rotateCredentials(credentials *credentialRotation, customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, client rtclient.Client, brokers func() map[string]rotationBroker, now time.Time) (string, string) {
+
+	status := credentials.status(customResource)
+	rotation := *status
+	if !rotationInProgress(rotation) {
+		trigger := credentials.trigger(customResource, now)
+		if trigger == "" {
+			return "", ""
+		}
+		// the brokers roll for one rotation at a time, a due one starts once the other completed
+		for _, other := range credentialRotations {
+			if other != credentials && rotationInProgress(*other.status(customResource)) {
+				return "", ""
+			}
+		}
+		rotation = &brokerv1beta1.CredentialRotationStatus{
+			Phase:     brokerv1beta1.CredentialRotationPreparing,
+			Trigger:   trigger,
+			Requested: customResource.Annotations[credentials.annotation],
+			StartedAt: &metav1.Time{Time: now},
+		}
+		if previous := *status; trigger == clusterCredentialRotationTriggerSchedule && previous != nil {
+			rotation.Requested = previous.Requested
+		}
+		*status = rotation
+		ctrl.Log.WithValues("ActiveMQArtemis Name", customResource.Name).Info("Rotating the "+credentials.name+" credentials", "trigger", trigger)
+	}
+
+	secret, err := reconciler.credentialsRotationSecret(credentials, customResource, namer, client, rotationStamp(rotation))
+	if err != nil {
+		rotation.Message = err.Error()
+		return "", ""
+	}
+	// once completed the secret is no longer tracked, which deletes the previous credentials
+	defer func() {
+		if rotation.Phase != brokerv1beta1.CredentialRotationCompleted {
+			reconciler.trackDesired(secret)
+		}
+	}()
+	nextUser, nextPassword := secretValue(secret, nextUserKey), secretValue(secret, nextPasswordKey)
+	previousUser, previousPassword := secretValue(secret, previousUserKey), secretValue(secret, previousPasswordKey)
+
+	deployed, _ := reconciler.getFromDeployed(reflect.TypeOf(appsv1.StatefulSet{}), namer.SsNameBuilder.Name()).(*appsv1.StatefulSet)
+	replicas := 0
+	if deployed != nil && deployed.Spec.Replicas != nil {
+		replicas = int(*deployed.Spec.Replicas)
+	}
+	role := getAdminRole(customResource)
+
+	switch rotation.Phase {
+	case brokerv1beta1.CredentialRotationPreparing:
+		// every broker takes the new credentials before any broker connects with them
+		current := brokers()
+		if len(current) < replicas {
+			rotation.Message = fmt.Sprintf("waiting for %d of %d brokers to be reachable", replicas-len(current), replicas)
+			return "", ""
+		}
+		if failed := forEachRotationBroker(current, func(broker rotationBroker) error {
+			return addClusterUser(broker.manager, nextUser, nextPassword, role)
+		}); len(failed) > 0 {
+			rotation.Message = "unable to add the new " + credentials.name + " user to " + strings.Join(failed, ", ")
+			return "", ""
+		}
+		rotation.Phase = brokerv1beta1.CredentialRotationRolling
+		rotation.Message = ""
+
+	case brokerv1beta1.CredentialRotationRolling:
+		// restarted brokers connect with the new credentials and take the previous ones from the others
+		current := brokers()
+		failed := forEachRotationBroker(current, func(broker rotationBroker) error {
+			if broker.rotated {
+				return addClusterUser(broker.manager, previousUser, previousPassword, role)
+			}
+			return addClusterUser(broker.manager, nextUser, nextPassword, role)
+		})
+		rotated := 0
+		for _, broker := range current {
+			if broker.rotated {
+				rotated++
+			}
+		}
+		if len(failed) > 0 {
+			rotation.Message = "unable to add the " + credentials.name + " users to " + strings.Join(failed, ", ")
+		} else if rotated < replicas || deployed == nil || !isStatefulSetSettled(deployed) {
+			rotation.Message = fmt.Sprintf("%d of %d brokers restarted with the new credentials", rotated, replicas)
+		} else {
+			rotation.Phase = brokerv1beta1.CredentialRotationVerifying
+			rotation.Message = ""
+		}
+
+	case brokerv1beta1.CredentialRotationVerifying:
+		if credentials.verifyTopology && isClustered(customResource) && replicas > 1 {
+			current := brokers()
+			pods := sortedRotationBrokers(current)
+			for _, pod := range pods {
+				size, err := current[pod].manager.GetClusterTopologySize()
+				if err != nil || size < replicas {
+					rotation.Message = fmt.Sprintf("waiting for the cluster to form again, %v sees %d of %d brokers", pod, size, replicas)
+					return nextUser, nextPassword
+				}
+			}
+			if len(pods) < replicas {
+				rotation.Message = fmt.Sprintf("waiting for %d of %d brokers to be reachable", replicas-len(pods), replicas)
+				return nextUser, nextPassword
+			}
+		}
+		rotation.Phase = brokerv1beta1.CredentialRotationCleaningUp
+		rotation.Message = ""
+
+	case brokerv1beta1.CredentialRotationCleaningUp:
+		if failed := forEachRotationBroker(brokers(), func(broker rotationBroker) error {
+			return removeClusterUser(broker.manager, previousUser)
+		}); len(failed) > 0 {
+			rotation.Message = "unable to remove the previous " + credentials.name + " user from " + strings.Join(failed, ", ")
+			return nextUser, nextPassword
+		}
+		rotation.Phase = brokerv1beta1.CredentialRotationCompleted
+		rotation.CompletedAt = &metav1.Time{Time: now}
+		rotation.Message = ""
+	}
+
+	if rotation.Phase == brokerv1beta1.CredentialRotationPreparing {
+		return "", ""
+	}
+	return nextUser, nextPassword
+}
+
+// the secret of a rotation holds the credentials it rotates to and from, it is made again when a
+// rotation finds the one of an earlier rotation
+func (reconciler *ActiveMQArtemisReconcilerImpl) credentialsRotationSecret(rotation *credentialRotation, customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, client rtclient.Client, stamp string) (*corev1.Secret, error) {
+	name := rotation.secretName(customResource)
+	var deployed *corev1.Secret
+	if obj := reconciler.cloneOfDeployed(reflect.TypeOf(corev1.Secret{}), name); obj != nil {
+		deployed = obj.(*corev1.Secret)
+		if secretValue(deployed, rotationStartedAtKey) == stamp {
+			return deployed, nil
+		}
+	}
+
+	credentials := &corev1.Secret{}
+	credentialsName := types.NamespacedName{Name: namer.SecretsCredentialsNameBuilder.Name(), Namespace: customResource.Namespace}
+	if err := client.Get(context.TODO(), credentialsName, credentials); err != nil {
+		return nil, fmt.Errorf("unable to read the %v credentials from %v: %v", rotation.name, credentialsName.Name, err)
+	}
+
+	secret := secrets.NewSecret(types.NamespacedName{Name: name, Namespace: customResource.Namespace}, name, map[string]string{
+		rotationStartedAtKey: stamp,
+		nextUserKey:          random.GenerateRandomString(8),
+		nextPasswordKey:      random.GenerateRandomString(16),
+		previousUserKey:      secretValue(credentials, rotation.userKey),
+		previousPasswordKey:  secretValue(credentials, rotation.passwordKey),
+	}, namer.LabelBuilder.Labels())
+	if deployed != nil {
+		deployed.StringData = secret.StringData
+		deployed.Data = nil
+		secret = deployed
+	}
+	return secret, nil
+}
+
+func secretValue(secret *corev1.Secret, key string) string {
+	if value, found := secret.StringData[key]; found {
+		return value
+	}
+	return string(secret.Data[key])
+}
+
+func addClusterUser(manager clusterUserManager, userName string, password string, role string) error {
+	data, err := manager.AddUser(userName, password, role)
+	if err != nil && !mgmt.IsUserExistsError(data) {
+		return err
+	}
+	return nil
+}
+
+func removeClusterUser(manager clusterUserManager, userName string) error {
+	data, err := manager.RemoveUser(userName)
+	if err != nil && !mgmt.IsUserNotFoundError(data) {
+		return err
+	}
+	return nil
+}
+
+func sortedRotationBrokers(brokers map[string]rotationBroker) []string {
+	pods := make([]string, 0, len(brokers))
+	for pod := range brokers {
+		pods = append(pods, pod)
+	}
+	sort.Strings(pods)
+	return pods
+}
+
+func forEachRotationBroker(brokers map[string]rotationBroker, apply func(rotationBroker) error) []string {
+	failed := []string{}
+	for _, pod := range sortedRotationBrokers(brokers) {
+		if err := apply(brokers[pod]); err != nil {
+			failed = append(failed, fmt.Sprintf("%v: %v", pod, err))
+		}
+	}
+	return failed
+}
+
+// a pod runs with the new credentials once it was created from the pod template of the rotation
+func rotationBrokers(credentials *credentialRotation, customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, namer Namers, deployed *appsv1.StatefulSet) map[string]rotationBroker {
+	resource := types.NamespacedName{Name: customResource.Name, Namespace: customResource.Namespace}
+	rotation := *credentials.status(customResource)
+	templateRotated := deployed != nil && rotation != nil && rotation.StartedAt != nil &&
+		deployed.Status.ObservedGeneration == deployed.Generation &&
+		deployed.Spec.Template.Annotations[credentials.rotatedAtAnnotation] == rotationStamp(rotation)
+
+	brokers := map[string]rotationBroker{}
+	for _, jk := range jc.GetBrokers(resource, ss.GetDeployedStatefulSetNames(client, []types.NamespacedName{resource}), client) {
+		name := namer.SsNameBuilder.Name() + "-" + jk.Ordinal
+		rotated := false
+		pod := &corev1.Pod{}
+		if templateRotated && client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: customResource.Namespace}, pod) == nil {
+			rotated = pod.Labels[appsv1.ControllerRevisionHashLabelKey] == deployed.Status.UpdateRevision
+		}
+		brokers[name] = rotationBroker{manager: jk.Artemis, rotated: rotated}
+	}
+	return brokers
+}
@@ -0,0 +1,228 @@
+package controllers
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/jolokia"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type fakeClusterUserManager struct {
+	users        map[string]string
+	topologySize int
+}
+
+func (m *fakeClusterUserManager) AddUser(userName string, password string, roles string) (*jolokia.ResponseData, error) {
+	if _, found := m.users[userName]; found {
+		data := &jolokia.ResponseData{Status: 500, Error: "javax.management.MBeanException : AMQ229223: User " + userName + " already exists"}
+		return data, errors.New(data.Error)
+	}
+	m.users[userName] = password
+	return &jolokia.ResponseData{Status: 200}, nil
+}
+
+func (m *fakeClusterUserManager) RemoveUser(userName string) (*jolokia.ResponseData, error) {
+	delete(m.users, userName)
+	return &jolokia.ResponseData{Status: 200}, nil
+}
+
+func (m *fakeClusterUserManager) GetClusterTopologySize() (int, error) {
+	return m.topologySize, nil
+}
+
+func TestClusterCredentialRotation(t *testing.T) {
+	size := int32(2)
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "broker",
+			Namespace:         "rotation",
+			CreationTimestamp: metav1.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			Annotations:       map[string]string{RotateClusterCredentialsAnnotation: "2026-01-02"},
+		},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			DeploymentPlan:            brokerv1beta1.DeploymentPlanType{Size: &size},
+			ClusterCredentialRotation: &brokerv1beta1.CredentialRotationType{Interval: "720h"},
+		},
+	}
+	assert.Nil(t, validateClusterCredentialRotation(cr))
+	namer := MakeNamers(cr)
+	credentials := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: namer.SecretsCredentialsNameBuilder.Name(), Namespace: cr.Namespace},
+		Data:       map[string][]byte{"AMQ_CLUSTER_USER": []byte("old"), "AMQ_CLUSTER_PASSWORD": []byte("oldpass")},
+	}
+	fakeClient := fake.NewClientBuilder().WithObjects(credentials).Build()
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: namer.SsNameBuilder.Name(), Namespace: cr.Namespace},
+		Spec:       appsv1.StatefulSetSpec{Replicas: &size},
+		Status:     appsv1.StatefulSetStatus{ReadyReplicas: 2, UpdatedReplicas: 2},
+	}
+
+	brokers := map[string]rotationBroker{
+		"broker-ss-0": {manager: &fakeClusterUserManager{users: map[string]string{}}},
+		"broker-ss-1": {manager: &fakeClusterUserManager{users: map[string]string{}}},
+	}
+	now := metav1.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC).Time
+	var deployedSecret *v1.Secret
+	reconcile := func() (string, string, *v1.Secret) {
+		reconciler := &ActiveMQArtemisReconcilerImpl{deployed: map[reflect.Type][]client.Object{
+			reflect.TypeOf(appsv1.StatefulSet{}): {statefulSet},
+		}}
+		if deployedSecret != nil {
+			reconciler.deployed[reflect.TypeOf(v1.Secret{})] = []client.Object{deployedSecret}
+		}
+		user, password := reconciler.rotateCredentials(clusterCredentialRotation, cr, *namer, fakeClient, func() map[string]rotationBroker { return brokers }, now)
+		deployedSecret = nil
+		for _, obj := range reconciler.requestedResources {
+			deployedSecret = obj.(*v1.Secret)
+		}
+		return user, password, deployedSecret
+	}
+
+	// the brokers learn the new credentials before they are used
+	user, _, secret := reconcile()
+	assert.Equal(t, brokerv1beta1.CredentialRotationRolling, cr.Status.ClusterCredentialRotation.Phase)
+	assert.Equal(t, "2026-01-02", cr.Status.ClusterCredentialRotation.Requested)
+	nextUser := secretValue(secret, nextUserKey)
+	assert.Equal(t, nextUser, user)
+	assert.Equal(t, "old", secretValue(secret, previousUserKey))
+	for _, broker := range brokers {
+		assert.Contains(t, broker.manager.(*fakeClusterUserManager).users, nextUser)
+	}
+	assert.Equal(t, "", clusterCredentialRotation.rotatedAt(&brokerv1beta1.ActiveMQArtemis{}, statefulSet))
+	assert.Equal(t, "2026-01-02T00:00:00Z", clusterCredentialRotation.rotatedAt(cr, statefulSet))
+
+	// the brokers restart with them, each restarted one takes the previous ones
+	brokers["broker-ss-1"] = rotationBroker{manager: &fakeClusterUserManager{users: map[string]string{}}, rotated: true}
+	statefulSet.Status.UpdatedReplicas = 1
+	user, password, _ := reconcile()
+	assert.Equal(t, nextUser, user)
+	assert.NotEmpty(t, password)
+	assert.Equal(t, brokerv1beta1.CredentialRotationRolling, cr.Status.ClusterCredentialRotation.Phase)
+	assert.Equal(t, "oldpass", brokers["broker-ss-1"].manager.(*fakeClusterUserManager).users["old"])
+
+	brokers["broker-ss-0"] = rotationBroker{manager: &fakeClusterUserManager{users: map[string]string{}, topologySize: 1}, rotated: true}
+	brokers["broker-ss-1"].manager.(*fakeClusterUserManager).topologySize = 2
+	statefulSet.Status.UpdatedReplicas = 2
+	reconcile()
+	assert.Equal(t, brokerv1beta1.CredentialRotationVerifying, cr.Status.ClusterCredentialRotation.Phase)
+
+	// the previous credentials stay until the cluster has formed again
+	reconcile()
+	assert.Equal(t, brokerv1beta1.CredentialRotationVerifying, cr.Status.ClusterCredentialRotation.Phase)
+	assert.Contains(t, cr.Status.ClusterCredentialRotation.Message, "broker-ss-0 sees 1 of 2")
+
+	brokers["broker-ss-0"].manager.(*fakeClusterUserManager).topologySize = 2
+	reconcile()
+	assert.Equal(t, brokerv1beta1.CredentialRotationCleaningUp, cr.Status.ClusterCredentialRotation.Phase)
+	user, _, secret = reconcile()
+	assert.Equal(t, nextUser, user)
+	assert.Nil(t, secret)
+	assert.Equal(t, brokerv1beta1.CredentialRotationCompleted, cr.Status.ClusterCredentialRotation.Phase)
+	for _, broker := range brokers {
+		assert.NotContains(t, broker.manager.(*fakeClusterUserManager).users, "old")
+	}
+
+	// nothing more until the interval has passed or the annotation changes
+	user, _, secret = reconcile()
+	assert.Equal(t, "", user)
+	assert.Nil(t, secret)
+	now = now.Add(721 * time.Hour)
+	assert.Equal(t, clusterCredentialRotationTriggerSchedule, clusterCredentialRotation.trigger(cr, now))
+	cr.Annotations[RotateClusterCredentialsAnnotation] = "again"
+	assert.Equal(t, clusterCredentialRotationTriggerAnnotation, clusterCredentialRotation.trigger(cr, now))
+
+	cr.Spec.ClusterCredentialRotation.Interval = "10m"
+	condition := validateClusterCredentialRotation(cr)
+	assert.NotNil(t, condition)
+	assert.Equal(t, brokerv1beta1.ValidConditionInvalidRotationReason, condition.Reason)
+}
+
+func TestGeneratedAdminCredentialRotation(t *testing.T) {
+	size := int32(2)
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "broker",
+			Namespace:         "rotation",
+			CreationTimestamp: metav1.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			Annotations:       map[string]string{RotateAdminCredentialsAnnotation: "2026-01-02"},
+		},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			DeploymentPlan:          brokerv1beta1.DeploymentPlanType{Size: &size},
+			AdminCredentialRotation: &brokerv1beta1.CredentialRotationType{Interval: "2160h"},
+		},
+	}
+	assert.Nil(t, validateAdminCredentialRotation(cr))
+	namer := MakeNamers(cr)
+	credentials := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: namer.SecretsCredentialsNameBuilder.Name(), Namespace: cr.Namespace},
+		Data:       map[string][]byte{"AMQ_USER": []byte("admin"), "AMQ_PASSWORD": []byte("adminpass")},
+	}
+	fakeClient := fake.NewClientBuilder().WithObjects(credentials).Build()
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: namer.SsNameBuilder.Name(), Namespace: cr.Namespace},
+		Spec:       appsv1.StatefulSetSpec{Replicas: &size},
+		Status:     appsv1.StatefulSetStatus{ReadyReplicas: 2, UpdatedReplicas: 2},
+	}
+	brokers := map[string]rotationBroker{
+		"broker-ss-0": {manager: &fakeClusterUserManager{users: map[string]string{}}},
+		"broker-ss-1": {manager: &fakeClusterUserManager{users: map[string]string{}}},
+	}
+	now := metav1.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC).Time
+	reconciler := &ActiveMQArtemisReconcilerImpl{deployed: map[reflect.Type][]client.Object{
+		reflect.TypeOf(appsv1.StatefulSet{}): {statefulSet},
+	}}
+	rotate := func() (string, string) {
+		return reconciler.rotateCredentials(adminCredentialRotation, cr, *namer, fakeClient, func() map[string]rotationBroker { return brokers }, now)
+	}
+
+	// every broker takes the new admin user before the secret changes
+	user, _ := rotate()
+	assert.Equal(t, brokerv1beta1.CredentialRotationRolling, cr.Status.AdminCredentialRotation.Phase)
+	assert.Nil(t, cr.Status.ClusterCredentialRotation)
+	secret := reconciler.requestedResources[0].(*v1.Secret)
+	assert.Equal(t, "broker-admin-credentials-rotation", secret.Name)
+	assert.Equal(t, "admin", secretValue(secret, previousUserKey))
+	assert.Equal(t, secretValue(secret, nextUserKey), user)
+	for _, broker := range brokers {
+		assert.Contains(t, broker.manager.(*fakeClusterUserManager).users, user)
+	}
+	assert.Equal(t, "2026-01-02T00:00:00Z", adminCredentialRotation.rotatedAt(cr, statefulSet))
+
+	// a due cluster rotation waits for the brokers to have rolled for the admin one
+	cr.Annotations[RotateClusterCredentialsAnnotation] = "2026-01-02"
+	clusterUser, _ := reconciler.rotateCredentials(clusterCredentialRotation, cr, *namer, fakeClient, func() map[string]rotationBroker { return brokers }, now)
+	assert.Equal(t, "", clusterUser)
+	assert.Nil(t, cr.Status.ClusterCredentialRotation)
+
+	// no topology to wait for once the brokers restarted
+	for pod := range brokers {
+		brokers[pod] = rotationBroker{manager: brokers[pod].manager, rotated: true}
+	}
+	rotate()
+	assert.Equal(t, brokerv1beta1.CredentialRotationVerifying, cr.Status.AdminCredentialRotation.Phase)
+	rotate()
+	assert.Equal(t, brokerv1beta1.CredentialRotationCleaningUp, cr.Status.AdminCredentialRotation.Phase)
+	rotate()
+	assert.Equal(t, brokerv1beta1.CredentialRotationCompleted, cr.Status.AdminCredentialRotation.Phase)
+	for _, broker := range brokers {
+		assert.NotContains(t, broker.manager.(*fakeClusterUserManager).users, "admin")
+	}
+
+	// credentials of the CR are left alone
+	cr.Spec.AdminPassword = "mine"
+	cr.Annotations[RotateAdminCredentialsAnnotation] = "again"
+	assert.Equal(t, "", adminCredentialRotation.trigger(cr, now))
+	condition := validateAdminCredentialRotation(cr)
+	assert.NotNil(t, condition)
+	assert.Equal(t, brokerv1beta1.ValidConditionInvalidRotationReason, condition.Reason)
+}
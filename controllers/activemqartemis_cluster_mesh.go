@@ -0,0 +1,267 @@
+package controllers
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/resources/environments"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// the prefix of the connectors to the members
+	clusterMeshConnectorPrefix = "mesh"
+
+	clusterMeshTrustStoreKey         = "client.ts"
+	clusterMeshTrustStorePasswordKey = "trustStorePassword"
+
+	clusterMeshChecksumEnvVarName = "CLUSTER_MESH_CHECKSUM"
+)
+
+// replaces the discovery group of a cluster connection with static connectors,
+// usage: cluster-mesh.py <broker.xml> <cluster connection> <connector>...
+var clusterMeshScript = `import sys
+from xml.dom import minidom
+
+doc = minidom.parse(sys.argv[1])
+connections = [c for c in doc.getElementsByTagName('cluster-connection') if c.getAttribute('name') == sys.argv[2]]
+if not connections:
+    sys.exit('no cluster connection ' + sys.argv[2] + ' in ' + sys.argv[1])
+connection = connections[0]
+
+static = doc.createElement('static-connectors')
+for name in sys.argv[3:]:
+    ref = doc.createElement('connector-ref')
+    ref.appendChild(doc.createTextNode(name))
+    static.appendChild(ref)
+
+replaced = connection.getElementsByTagName('discovery-group-ref') + connection.getElementsByTagName('static-connectors')
+if replaced:
+    connection.replaceChild(static, replaced[0])
+    for old in replaced[1:]:
+        connection.removeChild(old)
+else:
+    connection.appendChild(static)
+
+with open(sys.argv[1], 'w') as out:
+    out.write(doc.toxml())
+`
+
+func clusterMeshMemberConnectorName(i int) string {
+	return fmt.Sprintf("%s-member-%d", clusterMeshConnectorPrefix, i)
+}
+
+func clusterMeshAcceptor(customResource *brokerv1beta1.ActiveMQArtemis) (brokerv1beta1.AcceptorType, bool) {
+	for _, acceptor := range customResource.Spec.Acceptors {
+		if acceptor.Name == customResource.Spec.ClusterMesh.Acceptor {
+			return acceptor, true
+		}
+	}
+	return brokerv1beta1.AcceptorType{}, false
+}
+
+// the trust secret is mounted like an extra mount, at a path that only depends on its name so the
+// path in a connector a broker advertises is valid in the pods of the other deployments too
+func clusterMeshTrustStoreParams(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client) []string {
+	trustSecret := customResource.Spec.ClusterMesh.TrustSecret
+	if trustSecret == "" {
+		return nil
+	}
+	if client == nil {
+		clog.Error(fmt.Errorf("no client to retrieve secret %v", trustSecret), "unable to resolve cluster mesh trust store password")
+		return nil
+	}
+	secret := &corev1.Secret{}
+	if err := client.Get(context.TODO(), types.NamespacedName{Name: trustSecret, Namespace: customResource.Namespace}, secret); err != nil {
+		clog.Error(err, "unable to resolve cluster mesh trust store password", "secret", trustSecret)
+		return nil
+	}
+	return []string{
+		"sslEnabled=true",
+		"trustStorePath=" + secretPathBase + trustSecret + "/" + clusterMeshTrustStoreKey,
+		"trustStorePassword=" + strings.TrimSpace(string(secret.Data[clusterMeshTrustStorePasswordKey])),
+	}
+}
+
+// clusterMeshReady is true once the external address of every broker is known. Until then the
+// mesh is held, a broker that joined it would advertise a connector the other deployments can't reach
+func (reconciler *ActiveMQArtemisReconcilerImpl) clusterMeshReady(customResource *brokerv1beta1.ActiveMQArtemis) (brokerv1beta1.AcceptorType, bool) {
+	if customResource.Spec.ClusterMesh == nil || !isClustered(customResource) {
+		return brokerv1beta1.AcceptorType{}, false
+	}
+	acceptor, found := clusterMeshAcceptor(customResource)
+	if !found {
+		return acceptor, false
+	}
+	for i := int32(0); i < getDeploymentSize(customResource); i++ {
+		if host, _, _, _ := reconciler.externalAddress(customResource, acceptor, i); host == "" {
+			clog.V(1).Info("holding the cluster mesh until the external address of every broker is known", "acceptor", acceptor.Name, "ordinal", i)
+			return acceptor, false
+		}
+	}
+	return acceptor, true
+}
+
+// clusterMeshConnectors are the static connectors of the cluster connection, the members and the
+// external connectors of the brokers of the deployment itself, so the brokers of a deployment still
+// find each other while no member is reachable
+func clusterMeshConnectors(customResource *brokerv1beta1.ActiveMQArtemis, acceptor brokerv1beta1.AcceptorType) []string {
+	connectors := []string{}
+	for i := range customResource.Spec.ClusterMesh.Members {
+		connectors = append(connectors, clusterMeshMemberConnectorName(i))
+	}
+	for i := int32(0); i < getDeploymentSize(customResource); i++ {
+		connectors = append(connectors, fmt.Sprintf("%s-external-%d", acceptor.Name, i))
+	}
+	return connectors
+}
+
+// clusterMeshProperties points the cluster connection of the broker image at the other deployments.
+// The init container replaces its discovery group with static connectors, and each broker advertises
+// the external connector of its own ordinal so the other deployments can connect back to it
+func (reconciler *ActiveMQArtemisReconcilerImpl) clusterMeshProperties(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client) []string {
+	acceptor, ready := reconciler.clusterMeshReady(customResource)
+	if !ready {
+		return nil
+	}
+	trustParams := clusterMeshTrustStoreParams(customResource, client)
+
+	props := []string{}
+	for i, member := range customResource.Spec.ClusterMesh.Members {
+		connectorName := clusterMeshMemberConnectorName(i)
+		connector, err := connectorUrlProperties(connectorName, member)
+		if err != nil {
+			clog.Error(err, "unable to parse cluster mesh member url", "member", member)
+			continue
+		}
+		props = append(props, connector...)
+		for _, param := range trustParams {
+			if !strings.Contains(member, strings.SplitN(param, "=", 2)[0]+"=") {
+				props = append(props, "connectorConfigurations."+connectorName+".params."+param)
+			}
+		}
+	}
+
+	for i := int32(0); i < getDeploymentSize(customResource); i++ {
+		external := fmt.Sprintf("%s-external-%d", acceptor.Name, i)
+		props = append(props, fmt.Sprintf("%s%d%sclusterConfigurations.%s.connectorName=%s", OrdinalPrefix, i, OrdinalPrefixSep, clusterConnectionName, external))
+		for _, param := range trustParams {
+			props = append(props, "connectorConfigurations."+external+".params."+param)
+		}
+	}
+	return props
+}
+
+// clusterMeshCmd has the init container swap the discovery group of the cluster connection for the
+// static connectors. The cluster connection is only read on start, a change of the connectors or of
+// the external addresses rolls the pods through the checksum in the init container env
+func (reconciler *ActiveMQArtemisReconcilerImpl) clusterMeshCmd(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, podSpec *corev1.PodSpec) string {
+	acceptor, ready := reconciler.clusterMeshReady(customResource)
+	if !ready {
+		return ""
+	}
+	checksum := corev1.EnvVar{
+		Name:  clusterMeshChecksumEnvVarName,
+		Value: hex.EncodeToString(alder32Of(append(reconciler.externalConnectorProperties(customResource), reconciler.clusterMeshProperties(customResource, client)...))),
+	}
+	environments.Create(podSpec.InitContainers, &checksum)
+	return "python3 " + initScriptPath(clusterMeshScriptName) + " " + brokerConfigRoot + "/etc/broker.xml " + clusterConnectionName + " " + strings.Join(clusterMeshConnectors(customResource, acceptor), " ")
+}
+
+// applyClusterMeshCredentials replaces the generated cluster user, the deployments of the mesh
+// authenticate to each other with the same one
+func applyClusterMeshCredentials(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, envVars map[string]ValueInfo) {
+	mesh := customResource.Spec.ClusterMesh
+	if mesh == nil || mesh.CredentialsSecret == "" {
+		return
+	}
+	user, password, err := getConnectionCredentials(customResource, mesh.CredentialsSecret, client)
+	if err != nil {
+		clog.Error(err, "unable to resolve cluster mesh credentials", "secret", mesh.CredentialsSecret)
+		return
+	}
+	envVars["AMQ_CLUSTER_USER"] = ValueInfo{Value: user}
+	envVars["AMQ_CLUSTER_PASSWORD"] = ValueInfo{Value: password}
+}
+
+func clusterMeshSecretNames(customResource *brokerv1beta1.ActiveMQArtemis) []string {
+	mesh := customResource.Spec.ClusterMesh
+	if mesh == nil {
+		return nil
+	}
+	names := []string{}
+	for _, name := range []string{mesh.CredentialsSecret, mesh.TrustSecret} {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func validateClusterMesh(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, scheme *runtime.Scheme) (*metav1.Condition, bool) {
+	mesh := customResource.Spec.ClusterMesh
+	invalid := func(message string, args ...interface{}) *metav1.Condition {
+		return &metav1.Condition{
+			Type:    brokerv1beta1.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  brokerv1beta1.ValidConditionInvalidClusterMeshReason,
+			Message: ".Spec.ClusterMesh " + fmt.Sprintf(message, args...),
+		}
+	}
+
+	if !isClustered(customResource) {
+		return invalid("needs a clustered deployment"), false
+	}
+	acceptor, found := clusterMeshAcceptor(customResource)
+	if !found {
+		return invalid("acceptor %v is not in .Spec.Acceptors", mesh.Acceptor), false
+	}
+	if !acceptor.Expose || !acceptor.PublishExternalConnectors {
+		return invalid("acceptor %v needs expose and publishExternalConnectors, each broker advertises its external address to the other deployments", mesh.Acceptor), false
+	}
+	if len(mesh.Members) == 0 {
+		return invalid("has no members"), false
+	}
+	for _, member := range mesh.Members {
+		if _, err := connectorUrlProperties("", member); err != nil {
+			return invalid("member %q is not a valid url, %v", member, err), false
+		}
+	}
+	if mesh.CredentialsSecret == "" {
+		return invalid("needs a credentialsSecret, the deployments of the mesh share the cluster user"), false
+	}
+	if customResource.Spec.ClusterCredentialRotation != nil {
+		return invalid("can't be combined with .Spec.ClusterCredentialRotation, the cluster user comes from the credentialsSecret"), false
+	}
+	if source := customResource.Spec.CredentialsSource; source != nil && source.Cluster {
+		return invalid("can't be combined with .Spec.CredentialsSource.Cluster, the cluster user comes from the credentialsSecret"), false
+	}
+
+	if condition := validateConnectionCredentials(customResource, mesh.CredentialsSecret, ".Spec.ClusterMesh.CredentialsSecret", client, scheme); condition != nil {
+		return condition, true
+	}
+	if mesh.TrustSecret != "" {
+		secret := corev1.Secret{}
+		if !retrieveResource(mesh.TrustSecret, customResource.Namespace, &secret, client, scheme) {
+			return &metav1.Condition{
+				Type:    brokerv1beta1.ValidConditionType,
+				Status:  metav1.ConditionFalse,
+				Reason:  brokerv1beta1.ValidConditionMissingResourcesReason,
+				Message: fmt.Sprintf(".Spec.ClusterMesh.TrustSecret %v is not found", mesh.TrustSecret),
+			}, true
+		}
+		for _, key := range []string{clusterMeshTrustStoreKey, clusterMeshTrustStorePasswordKey} {
+			if condition := AssertSecretContainsKey(secret, key, ".Spec.ClusterMesh.TrustSecret is set but"); condition != nil {
+				return condition, true
+			}
+		}
+	}
+	return nil, false
+}
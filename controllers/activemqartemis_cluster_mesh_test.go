@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/resources/environments"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/common"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+)
+
+func TestClusterMesh(t *testing.T) {
+	ingress := brokerv1beta1.ExposeModeIngress
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "west", Namespace: "west-ns"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			DeploymentPlan: brokerv1beta1.DeploymentPlanType{Size: common.Int32ToPtr(2)},
+			Acceptors: []brokerv1beta1.AcceptorType{{
+				Name:                      "mesh",
+				Port:                      61617,
+				SSLEnabled:                true,
+				Expose:                    true,
+				ExposeMode:                &ingress,
+				PublishExternalConnectors: true,
+			}},
+			ClusterMesh: &brokerv1beta1.ClusterMeshType{
+				Acceptor:          "mesh",
+				Members:           []string{"tcp://east-mesh-0-svc-ing.apps.east.example.com:443?sslEnabled=true&sniHost=east-mesh-0-svc-ing.apps.east.example.com"},
+				CredentialsSecret: "mesh-credentials",
+				TrustSecret:       "mesh-trust",
+			},
+		},
+	}
+
+	testScheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(testScheme))
+	credentials := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "mesh-credentials", Namespace: cr.Namespace},
+		Data:       map[string][]byte{"user": []byte("cluster"), "password": []byte("shared")},
+	}
+	trust := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "mesh-trust", Namespace: cr.Namespace},
+		Data:       map[string][]byte{"client.ts": []byte("ts"), "trustStorePassword": []byte("changeit")},
+	}
+
+	condition, retry := validateClusterMesh(cr, fake.NewClientBuilder().WithScheme(testScheme).WithObjects(credentials).Build(), testScheme)
+	if assert.NotNil(t, condition) {
+		assert.Equal(t, brokerv1beta1.ValidConditionMissingResourcesReason, condition.Reason)
+		assert.True(t, retry)
+	}
+
+	c := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(credentials, trust).Build()
+	condition, _ = validateClusterMesh(cr, c, testScheme)
+	assert.Nil(t, condition)
+	for _, invalid := range []func(*brokerv1beta1.ActiveMQArtemis){
+		func(c *brokerv1beta1.ActiveMQArtemis) {
+			clustered := false
+			c.Spec.DeploymentPlan.Clustered = &clustered
+		},
+		func(c *brokerv1beta1.ActiveMQArtemis) { c.Spec.ClusterMesh.Acceptor = "missing" },
+		func(c *brokerv1beta1.ActiveMQArtemis) { c.Spec.Acceptors[0].PublishExternalConnectors = false },
+		func(c *brokerv1beta1.ActiveMQArtemis) { c.Spec.ClusterMesh.Members = nil },
+		func(c *brokerv1beta1.ActiveMQArtemis) { c.Spec.ClusterMesh.CredentialsSecret = "" },
+		func(c *brokerv1beta1.ActiveMQArtemis) {
+			c.Spec.ClusterCredentialRotation = &brokerv1beta1.CredentialRotationType{}
+		},
+	} {
+		invalidCr := cr.DeepCopy()
+		invalid(invalidCr)
+		condition, _ := validateClusterMesh(invalidCr, c, testScheme)
+		if assert.NotNil(t, condition) {
+			assert.Equal(t, brokerv1beta1.ValidConditionInvalidClusterMeshReason, condition.Reason)
+		}
+	}
+
+	ingress0 := &netv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: "west-mesh-0-svc-ing", Namespace: cr.Namespace},
+		Spec: netv1.IngressSpec{
+			Rules: []netv1.IngressRule{{Host: "west-mesh-0-svc-ing.apps.west.example.com"}},
+			TLS:   []netv1.IngressTLS{{Hosts: []string{"west-mesh-0-svc-ing.apps.west.example.com"}}},
+		},
+	}
+	ingress1 := ingress0.DeepCopy()
+	ingress1.Name = "west-mesh-1-svc-ing"
+	ingress1.Spec.Rules[0].Host = "west-mesh-1-svc-ing.apps.west.example.com"
+	ingress1.Spec.TLS[0].Hosts = []string{ingress1.Spec.Rules[0].Host}
+
+	// the mesh is held while the external address of pod 1 isn't known
+	reconciler := &ActiveMQArtemisReconcilerImpl{
+		deployed: map[reflect.Type][]client.Object{reflect.TypeOf(netv1.Ingress{}): {ingress0}},
+	}
+	podSpec := &v1.PodSpec{InitContainers: []v1.Container{{Name: "init"}}}
+	assert.Empty(t, reconciler.clusterMeshProperties(cr, c))
+	assert.Empty(t, reconciler.clusterMeshCmd(cr, c, podSpec))
+	assert.Empty(t, podSpec.InitContainers[0].Env)
+
+	reconciler.deployed[reflect.TypeOf(netv1.Ingress{})] = []client.Object{ingress0, ingress1}
+	props := reconciler.clusterMeshProperties(cr, c)
+	assert.Contains(t, props, "connectorConfigurations.mesh-member-0.params.host=east-mesh-0-svc-ing.apps.east.example.com")
+	assert.Contains(t, props, "connectorConfigurations.mesh-member-0.params.sslEnabled=true")
+	assert.Contains(t, props, "connectorConfigurations.mesh-member-0.params.trustStorePath=/amq/extra/secrets/mesh-trust/client.ts")
+	assert.Contains(t, props, "connectorConfigurations.mesh-member-0.params.trustStorePassword=changeit")
+	assert.Contains(t, props, "broker-0.clusterConfigurations.my-cluster.connectorName=mesh-external-0")
+	assert.Contains(t, props, "broker-1.clusterConfigurations.my-cluster.connectorName=mesh-external-1")
+	assert.Contains(t, props, "connectorConfigurations.mesh-external-0.params.trustStorePath=/amq/extra/secrets/mesh-trust/client.ts")
+	// no cluster connection next to the one of the image
+	assert.NotContains(t, strings.Join(props, "\n"), "clusterConfigurations.mesh.")
+	// the url already enables ssl
+	assert.Equal(t, 1, strings.Count(strings.Join(props, "\n"), "mesh-member-0.params.sslEnabled"))
+
+	// the init container swaps the discovery group of my-cluster for the members and the brokers of the deployment
+	assert.Equal(t, "python3 /amq/init/scripts/cluster-mesh.py /amq/init/config/etc/broker.xml my-cluster mesh-member-0 mesh-external-0 mesh-external-1", reconciler.clusterMeshCmd(cr, c, podSpec))
+	checksum := environments.Retrieve(podSpec.InitContainers, clusterMeshChecksumEnvVarName)
+	if assert.NotNil(t, checksum) {
+		// a new address of a broker rolls the pods
+		ingress1.Spec.Rules[0].Host = "west-mesh-1-svc-ing.apps.west2.example.com"
+		moved := &v1.PodSpec{InitContainers: []v1.Container{{Name: "init"}}}
+		reconciler.clusterMeshCmd(cr, c, moved)
+		assert.NotEqual(t, checksum.Value, environments.Retrieve(moved.InitContainers, clusterMeshChecksumEnvVarName).Value)
+	}
+
+	envVars := map[string]ValueInfo{"AMQ_CLUSTER_USER": {Value: "generated", AutoGen: true}}
+	applyClusterMeshCredentials(cr, c, envVars)
+	assert.Equal(t, ValueInfo{Value: "cluster"}, envVars["AMQ_CLUSTER_USER"])
+	assert.Equal(t, ValueInfo{Value: "shared"}, envVars["AMQ_CLUSTER_PASSWORD"])
+
+	assert.Equal(t, []string{"mesh-credentials", "mesh-trust"}, clusterMeshSecretNames(cr))
+}
@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"strconv"
+	"time"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// the acceptor the brokers of the cluster connect to with a client certificate. It is kept apart from
+	// the one on 61616 that the drainer and the bridges of the operator connect to without a certificate
+	clusterTLSName = "cluster-tls"
+	clusterTLSPort = 61618
+	// the connector of the cluster connection in the broker.xml of the broker image
+	clusterConnectorName = "artemis"
+	// the cluster connection in the broker.xml of the broker image
+	clusterConnectionName = "my-cluster"
+)
+
+func clusterTLSSecretName(customResource *brokerv1beta1.ActiveMQArtemis) string {
+	return customResource.Name + "-" + clusterTLSName + "-secret"
+}
+
+func clusterTLSPasswordSecretName(customResource *brokerv1beta1.ActiveMQArtemis) string {
+	return customResource.Name + "-" + clusterTLSName + keyStorePasswordNameSuffix
+}
+
+func clusterTLSIssuerName(customResource *brokerv1beta1.ActiveMQArtemis) string {
+	return customResource.Name + "-" + clusterTLSName + "-ca"
+}
+
+func newIssuer(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, name string, spec map[string]interface{}) *unstructured.Unstructured {
+	issuer := &unstructured.Unstructured{}
+	issuer.SetAPIVersion(certificateAPIVersion)
+	issuer.SetKind(defaultCertificateIssuer)
+	issuer.SetName(name)
+	issuer.SetNamespace(customResource.Namespace)
+	issuer.SetLabels(namer.LabelBuilder.Labels())
+	issuer.Object["spec"] = spec
+	return issuer
+}
+
+// applyClusterCertificate has cert-manager issue the certificate every broker presents to the others
+// and trusts them with. Without an issuer of the cr the chain is a self signed issuer, a CA certificate
+// and a CA issuer of the cr, cert-manager renews the broker certificate before it expires
+func (reconciler *ActiveMQArtemisReconcilerImpl) applyClusterCertificate(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, client rtclient.Client, scheme *runtime.Scheme) string {
+	clusterTLS := customResource.Spec.ClusterTLS
+
+	issuer := clusterTLS.CertificateIssuer
+	if issuer == nil {
+		selfSignedName := customResource.Name + "-" + clusterTLSName + "-selfsigned"
+		caSecretName := customResource.Name + "-" + clusterTLSName + "-ca-secret"
+		reconciler.applyCertificate(customResource, client, scheme, newIssuer(customResource, namer, selfSignedName, map[string]interface{}{
+			"selfSigned": map[string]interface{}{},
+		}))
+
+		ca := &unstructured.Unstructured{}
+		ca.SetAPIVersion(certificateAPIVersion)
+		ca.SetKind("Certificate")
+		ca.SetName(customResource.Name + "-" + clusterTLSName + "-ca-cert")
+		ca.SetNamespace(customResource.Namespace)
+		ca.SetLabels(namer.LabelBuilder.Labels())
+		ca.Object["spec"] = map[string]interface{}{
+			"isCA":       true,
+			"commonName": customResource.Name + "-" + clusterTLSName + "-ca",
+			"secretName": caSecretName,
+			"issuerRef": map[string]interface{}{
+				"name":  selfSignedName,
+				"kind":  defaultCertificateIssuer,
+				"group": "cert-manager.io",
+			},
+		}
+		reconciler.applyCertificate(customResource, client, scheme, ca)
+
+		reconciler.applyCertificate(customResource, client, scheme, newIssuer(customResource, namer, clusterTLSIssuerName(customResource), map[string]interface{}{
+			"ca": map[string]interface{}{
+				"secretName": caSecretName,
+			},
+		}))
+		issuer = &brokerv1beta1.CertificateIssuerType{Name: clusterTLSIssuerName(customResource)}
+	}
+
+	password := reconciler.issuedKeyStorePassword(customResource, namer, clusterTLSPasswordSecretName(customResource))
+	certificate := newCertificate(customResource, namer, clusterTLSName, issuer, clusterTLSSecretName(customResource), clusterTLSPasswordSecretName(customResource), nil)
+	spec := certificate.Object["spec"].(map[string]interface{})
+	spec["usages"] = []interface{}{"digital signature", "key encipherment", "server auth", "client auth"}
+	if clusterTLS.Duration != "" {
+		spec["duration"] = clusterTLS.Duration
+	}
+	if clusterTLS.RenewBefore != "" {
+		spec["renewBefore"] = clusterTLS.RenewBefore
+	}
+	reconciler.applyCertificate(customResource, client, scheme, certificate)
+	return password
+}
+
+// clusterTLSProperties has the cluster connector present the broker certificate to the cluster tls
+// acceptor, which is configured in the acceptors of broker.xml
+func (reconciler *ActiveMQArtemisReconcilerImpl) clusterTLSProperties(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers) []string {
+	if customResource.Spec.ClusterTLS == nil {
+		return nil
+	}
+	password := reconciler.issuedKeyStorePassword(customResource, namer, clusterTLSPasswordSecretName(customResource))
+	path := "/etc/" + clusterTLSSecretName(customResource) + "-volume/"
+	prefix := "connectorConfigurations." + clusterConnectorName + ".params."
+	return []string{
+		prefix + "port=" + strconv.Itoa(clusterTLSPort),
+		prefix + "sslEnabled=true",
+		prefix + "keyStorePath=" + path + "keystore.p12",
+		prefix + "keyStorePassword=" + password,
+		prefix + "keyStoreType=PKCS12",
+		prefix + "trustStorePath=" + path + "truststore.p12",
+		prefix + "trustStorePassword=" + password,
+		prefix + "trustStoreType=PKCS12",
+	}
+}
+
+func parseCertificateDuration(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, true
+	}
+	duration, err := time.ParseDuration(value)
+	return duration, err == nil && duration > 0
+}
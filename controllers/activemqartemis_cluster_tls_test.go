@@ -0,0 +1,93 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/common"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestClusterTLS(t *testing.T) {
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: "test"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			DeploymentPlan: brokerv1beta1.DeploymentPlanType{Size: common.Int32ToPtr(2)},
+			ClusterTLS:     &brokerv1beta1.ClusterTLSType{Duration: "2160h", RenewBefore: "360h"},
+			Acceptors:      []brokerv1beta1.AcceptorType{{Name: "amqp", Port: 5672}},
+		},
+	}
+	namer := MakeNamers(cr)
+	fakeClient := fake.NewClientBuilder().Build()
+	assert.Nil(t, validateClusterTLS(cr))
+
+	// the statefulset asks for the password first
+	reconciler := &ActiveMQArtemisReconcilerImpl{}
+	props := reconciler.clusterTLSProperties(cr, *namer)
+	passwords := reconciler.applyAcceptorCertificates(cr, *namer, fakeClient, nil)
+	password := passwords[clusterTLSName]
+	assert.NotEmpty(t, password)
+	assert.Len(t, reconciler.requestedResources, 1)
+	assert.Contains(t, props, "connectorConfigurations.artemis.params.keyStorePassword="+password)
+	assert.Contains(t, props, "connectorConfigurations.artemis.params.port=61618")
+	assert.Contains(t, props, "connectorConfigurations.artemis.params.trustStorePath=/etc/broker-cluster-tls-secret-volume/truststore.p12")
+
+	get := func(kind string, name string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(certificateAPIVersion)
+		obj.SetKind(kind)
+		assert.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: "test"}, obj))
+		return obj
+	}
+	get("Issuer", "broker-cluster-tls-selfsigned")
+	isCA, _, _ := unstructured.NestedBool(get("Certificate", "broker-cluster-tls-ca-cert").Object, "spec", "isCA")
+	assert.True(t, isCA)
+	caSecret, _, _ := unstructured.NestedString(get("Issuer", "broker-cluster-tls-ca").Object, "spec", "ca", "secretName")
+	assert.Equal(t, "broker-cluster-tls-ca-secret", caSecret)
+
+	certificate := get("Certificate", "broker-cluster-tls-cert")
+	issuerName, _, _ := unstructured.NestedString(certificate.Object, "spec", "issuerRef", "name")
+	assert.Equal(t, "broker-cluster-tls-ca", issuerName)
+	renewBefore, _, _ := unstructured.NestedString(certificate.Object, "spec", "renewBefore")
+	assert.Equal(t, "360h", renewBefore)
+	usages, _, _ := unstructured.NestedStringSlice(certificate.Object, "spec", "usages")
+	assert.Contains(t, usages, "client auth")
+
+	acceptors := generateAcceptorsString(cr, *namer, fakeClient, passwords)
+	assert.Contains(t, acceptors, "<acceptor name=\"cluster-tls\">tcp:\\/\\/ACCEPTOR_IP:61618?protocols=CORE;sslEnabled=true;keyStorePath=\\/etc\\/broker-cluster-tls-secret-volume\\/keystore.p12")
+	assert.Contains(t, acceptors, ";needClientAuth=true;")
+	// the drainer and the bridges of the operator connect to 61616 without a client certificate
+	assert.Contains(t, acceptors, "<acceptor name=\"scaleDown\">tcp:\\/\\/ACCEPTOR_IP:61616?protocols=CORE;tcpSendBufferSize")
+	assert.Contains(t, tlsSecretNames(cr, *namer, false), "broker-cluster-tls-secret")
+
+	mounted := false
+	for _, mount := range MakeVolumeMounts(cr, *namer) {
+		mounted = mounted || mount.Name == "broker-cluster-tls-secret-volume"
+	}
+	assert.True(t, mounted)
+
+	// an issuer of the cr replaces the self signed CA
+	cr.Spec.ClusterTLS.CertificateIssuer = &brokerv1beta1.CertificateIssuerType{Name: "corporate", Kind: "ClusterIssuer"}
+	reconciler.applyAcceptorCertificates(cr, *namer, fakeClient, nil)
+	issuerName, _, _ = unstructured.NestedString(get("Certificate", "broker-cluster-tls-cert").Object, "spec", "issuerRef", "name")
+	assert.Equal(t, "corporate", issuerName)
+
+	cr.Spec.ClusterTLS.RenewBefore = "3000h"
+	assert.NotNil(t, validateClusterTLS(cr))
+	cr.Spec.ClusterTLS.RenewBefore = ""
+	cr.Spec.Acceptors = append(cr.Spec.Acceptors, brokerv1beta1.AcceptorType{Name: "all", Preset: brokerv1beta1.AcceptorPresetAll})
+	assert.Nil(t, validateClusterTLS(cr))
+	cr.Spec.Acceptors[1].Port = 61618
+	assert.NotNil(t, validateClusterTLS(cr))
+	cr.Spec.Acceptors = cr.Spec.Acceptors[:1]
+	cr.Spec.DeploymentPlan.Clustered = new(bool)
+	condition := validateClusterTLS(cr)
+	assert.NotNil(t, condition)
+	assert.Equal(t, brokerv1beta1.ValidConditionInvalidClusterTLSReason, condition.Reason)
+}
@@ -0,0 +1,107 @@
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// the keys of the netty secret the acceptors and connectors reach the brokers with
+var acceptorsAndConnectorsKeys = []string{"AMQ_ACCEPTORS", "AMQ_CONNECTORS"}
+
+// AppliedBrokerConfiguration reads the broker properties, acceptors and connectors the brokers of the
+// cr are configured with, keyed like the data of the secrets that hold them
+func AppliedBrokerConfiguration(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client) (map[string]string, error) {
+	configuration := map[string]string{}
+	if customResource.UID == "" {
+		return configuration, nil
+	}
+
+	deployed, err := getDeployedResources(customResource, client)
+	if err != nil {
+		return nil, err
+	}
+	propsName := getConfigAppliedConfigMapName(customResource).Name
+	for _, obj := range deployed[reflect.TypeOf(corev1.Secret{})] {
+		if obj.GetName() == propsName {
+			copySecretData(obj.(*corev1.Secret), configuration, nil)
+		}
+	}
+	for _, obj := range deployed[reflect.TypeOf(corev1.ConfigMap{})] {
+		if strings.HasPrefix(obj.GetName(), propsName+"-") {
+			for key, value := range obj.(*corev1.ConfigMap).Data {
+				configuration[key] = value
+			}
+		}
+	}
+
+	netty := &corev1.Secret{}
+	nettyName := types.NamespacedName{Namespace: customResource.Namespace, Name: MakeNamers(customResource).SecretsNettyNameBuilder.Name()}
+	if err := client.Get(context.TODO(), nettyName, netty); err != nil {
+		if apierrors.IsNotFound(err) {
+			return configuration, nil
+		}
+		return nil, err
+	}
+	copySecretData(netty, configuration, acceptorsAndConnectorsKeys)
+	return configuration, nil
+}
+
+// PendingBrokerConfiguration renders what a reconcile of the cr would configure the brokers with, it
+// only reads from the cluster. The passwords of issued keystores are left out
+func PendingBrokerConfiguration(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client) (map[string]string, error) {
+	customResource = customResource.DeepCopy()
+	namer := MakeNamers(customResource)
+	reconciler := &ActiveMQArtemisReconcilerImpl{}
+	if customResource.UID != "" {
+		deployed, err := getDeployedResources(customResource, client)
+		if err != nil {
+			return nil, err
+		}
+		reconciler.deployed = deployed
+	}
+
+	configuration := map[string]string{}
+	_, _, data := reconciler.addResourceForBrokerProperties(customResource, *namer, client)
+	for key, value := range data {
+		configuration[key] = value
+	}
+
+	acceptorPasswords := map[string]string{}
+	for _, acceptor := range customResource.Spec.Acceptors {
+		if getAcceptorCertificateIssuer(customResource, acceptor) != nil {
+			acceptorPasswords[acceptor.Name] = ""
+		}
+	}
+	if customResource.Spec.ClusterTLS != nil {
+		acceptorPasswords[clusterTLSName] = ""
+	}
+	connectorPasswords := map[string]string{}
+	for _, connector := range customResource.Spec.Connectors {
+		if connector.SSLEnabled && connector.CertificateIssuer != nil {
+			connectorPasswords[connector.Name] = ""
+		}
+	}
+	configuration["AMQ_ACCEPTORS"] = generateAcceptorsString(customResource, *namer, client, acceptorPasswords)
+	configuration["AMQ_CONNECTORS"] = generateConnectorsString(customResource, *namer, client, connectorPasswords)
+	return configuration, nil
+}
+
+func copySecretData(secret *corev1.Secret, configuration map[string]string, keys []string) {
+	for key, value := range secret.Data {
+		if keys == nil || containsString(keys, key) {
+			configuration[key] = string(value)
+		}
+	}
+	for key, value := range secret.StringData {
+		if keys == nil || containsString(keys, key) {
+			configuration[key] = value
+		}
+	}
+}
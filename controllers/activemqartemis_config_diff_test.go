@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"testing"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestBrokerConfigurationDiff(t *testing.T) {
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		TypeMeta:   metav1.TypeMeta{APIVersion: brokerv1beta1.GroupVersion.String(), Kind: "ActiveMQArtemis"},
+		ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: "review", UID: "broker-uid"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			BrokerProperties: []string{"globalMaxSize=512m"},
+			Acceptors:        []brokerv1beta1.AcceptorType{{Name: "amqp", Port: 5672, Protocols: "AMQP"}},
+		},
+	}
+	owner := []metav1.OwnerReference{{APIVersion: brokerv1beta1.GroupVersion.String(), Kind: "ActiveMQArtemis", Name: "broker", UID: "broker-uid"}}
+	props := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "broker-props", Namespace: "review", OwnerReferences: owner},
+		Data:       map[string][]byte{BrokerPropertiesName: []byte("# generated by crd\n#\nglobalMaxSize=256m\n")},
+	}
+	netty := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "broker-netty-secret", Namespace: "review", OwnerReferences: owner},
+		Data: map[string][]byte{
+			"AMQ_ACCEPTORS":  []byte(generateAcceptorsString(cr.DeepCopy(), *MakeNamers(cr), nil, nil)),
+			"AMQ_CONNECTORS": []byte(""),
+			"AMQ_CLUSTER":    []byte("ignored"),
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithObjects(props, netty).Build()
+
+	applied, err := AppliedBrokerConfiguration(cr, fakeClient)
+	assert.NoError(t, err)
+	assert.Contains(t, applied[BrokerPropertiesName], "globalMaxSize=256m")
+	assert.Contains(t, applied["AMQ_ACCEPTORS"], "<acceptor name=\"amqp\">")
+	assert.NotContains(t, applied, "AMQ_CLUSTER")
+
+	pending, err := PendingBrokerConfiguration(cr, fakeClient)
+	assert.NoError(t, err)
+	assert.Contains(t, pending[BrokerPropertiesName], "globalMaxSize=512m")
+	assert.Equal(t, applied["AMQ_ACCEPTORS"], pending["AMQ_ACCEPTORS"])
+	assert.Empty(t, pending["AMQ_CONNECTORS"])
+
+	// a cr that is not deployed yet has nothing applied
+	cr.UID = ""
+	applied, err = AppliedBrokerConfiguration(cr, fakeClient)
+	assert.NoError(t, err)
+	assert.Empty(t, applied)
+}
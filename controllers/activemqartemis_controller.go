@@ -19,38 +19,71 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"reflect"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+	rtcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/artemiscloud/activemq-artemis-operator/pkg/resources"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/forecast"
 	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/namer"
 	"github.com/pkg/errors"
 
 	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
 	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/common"
 	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/selectors"
+	"github.com/artemiscloud/activemq-artemis-operator/version"
 )
 
-var clog = ctrl.Log.WithName("controller_v1beta1activemqartemis")
+// the broker controller reconciles one request at a time, the lines of its package logger carry the
+// values of the reconcile in progress
+var clogCorrelation = &reconcileCorrelation{}
+var clog = correlatedLogger(ctrl.Log.WithName("controller_v1beta1activemqartemis"), clogCorrelation)
+
+// the watch handlers and the config handlers the security controller calls run beside the reconcile
+var hlog = ctrl.Log.WithName("controller_v1beta1activemqartemis")
 
 var namespaceToConfigHandler = make(map[types.NamespacedName]common.ActiveMQArtemisConfigHandler)
 
+// the security controller adds and removes the handlers while the broker reconciles and the watch
+// handlers of both controllers read them
+var namespaceToConfigHandlerLock sync.RWMutex
+
+// configHandlers is a copy of the handlers, IsApplicableFor can read from the cluster and runs without the lock
+func configHandlers() map[types.NamespacedName]common.ActiveMQArtemisConfigHandler {
+	namespaceToConfigHandlerLock.RLock()
+	defer namespaceToConfigHandlerLock.RUnlock()
+	handlers := make(map[types.NamespacedName]common.ActiveMQArtemisConfigHandler, len(namespaceToConfigHandler))
+	for name, handler := range namespaceToConfigHandler {
+		handlers[name] = handler
+	}
+	return handlers
+}
+
 func GetBrokerConfigHandler(brokerNamespacedName types.NamespacedName) (handler common.ActiveMQArtemisConfigHandler) {
-	for _, handler := range namespaceToConfigHandler {
+	for _, handler := range configHandlers() {
 		if handler.IsApplicableFor(brokerNamespacedName) {
 			return handler
 		}
@@ -69,7 +102,7 @@ func (r *ActiveMQArtemisReconciler) UpdatePodForSecurity(securityHandlerNamespac
 			candidate.Name = artemis.Name
 			candidate.Namespace = artemis.Namespace
 			if handler.IsApplicableFor(candidate) {
-				clog.V(1).Info("force reconcile for security", "handler", securityHandlerNamespacedName, "CR", candidate)
+				hlog.V(1).Info("force reconcile for security", "handler", securityHandlerNamespacedName, "CR", candidate)
 				r.events <- event.GenericEvent{Object: &existingCrs.Items[index]}
 			}
 		}
@@ -78,23 +111,27 @@ func (r *ActiveMQArtemisReconciler) UpdatePodForSecurity(securityHandlerNamespac
 }
 
 func (r *ActiveMQArtemisReconciler) RemoveBrokerConfigHandler(namespacedName types.NamespacedName) {
-	clog.V(1).Info("Removing config handler", "name", namespacedName)
+	hlog.V(1).Info("Removing config handler", "name", namespacedName)
+	namespaceToConfigHandlerLock.Lock()
 	oldHandler, ok := namespaceToConfigHandler[namespacedName]
+	delete(namespaceToConfigHandler, namespacedName)
+	namespaceToConfigHandlerLock.Unlock()
 	if ok {
-		delete(namespaceToConfigHandler, namespacedName)
-		clog.V(2).Info("Handler removed", "name", namespacedName)
+		hlog.V(2).Info("Handler removed", "name", namespacedName)
 		r.UpdatePodForSecurity(namespacedName, oldHandler)
 	}
 }
 
 func (r *ActiveMQArtemisReconciler) AddBrokerConfigHandler(namespacedName types.NamespacedName, handler common.ActiveMQArtemisConfigHandler, toReconcile bool) error {
+	namespaceToConfigHandlerLock.Lock()
 	if _, ok := namespaceToConfigHandler[namespacedName]; ok {
-		clog.V(2).Info("There is an old config handler, it'll be replaced")
+		hlog.V(2).Info("There is an old config handler, it'll be replaced")
 	}
 	namespaceToConfigHandler[namespacedName] = handler
-	clog.V(2).Info("A new config handler has been added", "handler", handler)
+	namespaceToConfigHandlerLock.Unlock()
+	hlog.V(2).Info("A new config handler has been added", "handler", handler)
 	if toReconcile {
-		clog.V(1).Info("Updating broker security")
+		hlog.V(1).Info("Updating broker security")
 		return r.UpdatePodForSecurity(namespacedName, handler)
 	}
 	return nil
@@ -103,8 +140,10 @@ func (r *ActiveMQArtemisReconciler) AddBrokerConfigHandler(namespacedName types.
 // ActiveMQArtemisReconciler reconciles a ActiveMQArtemis object
 type ActiveMQArtemisReconciler struct {
 	rtclient.Client
-	Scheme *runtime.Scheme
-	events chan event.GenericEvent
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	events   chan event.GenericEvent
+	resync   *resyncLane
 }
 
 //run 'make manifests' after changing the following rbac markers
@@ -122,6 +161,10 @@ type ActiveMQArtemisReconciler struct {
 //+kubebuilder:rbac:groups=apps,namespace=activemq-artemis-operator,resources=deployments/finalizers,verbs=update
 //+kubebuilder:rbac:groups=rbac.authorization.k8s.io,namespace=activemq-artemis-operator,resources=roles;rolebindings,verbs=create;get;delete
 //+kubebuilder:rbac:groups=policy,namespace=activemq-artemis-operator,resources=poddisruptionbudgets,verbs=create;get;delete
+//+kubebuilder:rbac:groups=cert-manager.io,namespace=activemq-artemis-operator,resources=certificates,verbs=get;create;update;delete
+//+kubebuilder:rbac:groups=snapshot.storage.k8s.io,namespace=activemq-artemis-operator,resources=volumesnapshots,verbs=get;list;create;delete
+//+kubebuilder:rbac:groups="",resources=nodes,verbs=get
+//+kubebuilder:rbac:groups=keda.sh,namespace=activemq-artemis-operator,resources=scaledobjects,verbs=get;create;update;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -133,7 +176,12 @@ type ActiveMQArtemisReconciler struct {
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.10.0/pkg/reconcile
 func (r *ActiveMQArtemisReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
-	reqLogger := ctrl.Log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name, "Reconciling", "ActiveMQArtemis")
+	reqLogger := ctrl.LoggerFrom(ctx)
+	defer clogCorrelation.begin(ctx, "activemqartemis", request)()
+
+	if r.resync != nil {
+		r.resync.reconciling(request.NamespacedName)
+	}
 
 	customResource := &brokerv1beta1.ActiveMQArtemis{}
 
@@ -143,142 +191,1531 @@ func (r *ActiveMQArtemisReconciler) Reconcile(ctx context.Context, request ctrl.
 	// When deleting before creation reconcile won't be called
 	err := r.Get(context.TODO(), request.NamespacedName, customResource)
 
-	if err != nil {
-		if apierrors.IsNotFound(err) {
-			reqLogger.V(1).Info("ActiveMQArtemis Controller Reconcile encountered a IsNotFound, for request NamespacedName " + request.NamespacedName.String())
-			return ctrl.Result{}, nil
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			reqLogger.V(1).Info("ActiveMQArtemis Controller Reconcile encountered a IsNotFound, for request NamespacedName " + request.NamespacedName.String())
+			deleteAppliedAPIVersionMetric(request.NamespacedName)
+			forecast.GetTrends().Forget(request.Namespace + "/" + request.Name + "/")
+			mirrorSamples.forget(request.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+		reqLogger.Error(err, "unable to retrieve the ActiveMQArtemis", "request", request)
+		return ctrl.Result{}, err
+	}
+
+	namer := MakeNamers(customResource)
+	reconciler := ActiveMQArtemisReconcilerImpl{}
+
+	result := ctrl.Result{}
+	var valid = true
+
+	if valid, result = validate(customResource, r.Client, r.Scheme, *namer); valid {
+
+		reconciler.Process(customResource, *namer, r.Client, r.Scheme)
+
+		result = UpdateBrokerPropertiesStatus(customResource, r.Client, r.Scheme)
+	} else if condition := meta.FindStatusCondition(customResource.Status.Conditions, brokerv1beta1.ValidConditionType); condition != nil && condition.Status == metav1.ConditionFalse {
+		recordEvent(ctx, r.Recorder, customResource, corev1.EventTypeWarning, condition.Reason, condition.Message)
+	}
+
+	capacityWarning := meta.FindStatusCondition(customResource.Status.Conditions, brokerv1beta1.CapacityWarningConditionType)
+	if capacityWarning != nil {
+		capacityWarning = capacityWarning.DeepCopy()
+	}
+
+	UpdateStatus(customResource, r.Client, request.NamespacedName, *namer)
+
+	recordCapacityWarning(ctx, r.Recorder, customResource, capacityWarning)
+
+	err = UpdateCRStatus(customResource, r.Client, request.NamespacedName)
+
+	if err != nil {
+		if apierrors.IsConflict(err) {
+			reqLogger.V(1).Info("unable to update ActiveMQArtemis status", "Request Namespace", request.Namespace, "Request Name", request.Name, "error", err)
+			err = nil // we don't want the controller event loop reporting this as an error
+		} else {
+			reqLogger.V(1).Error(err, "unable to update ActiveMQArtemis status", "Request Namespace", request.Namespace, "Request Name", request.Name)
+		}
+		return ctrl.Result{RequeueAfter: common.GetReconcileResyncPeriod()}, err
+	}
+
+	if result.IsZero() {
+		reqLogger.Info("resource successfully reconciled")
+		resync := false
+		if hasExtraMounts(customResource) {
+			reqLogger.V(1).Info("resource has extraMounts, requeuing for periodic sync")
+			resync = true
+		}
+		if hasExternalConnectors(customResource) {
+			reqLogger.V(1).Info("resource publishes external connectors, requeuing to follow the exposed addresses")
+			resync = true
+		}
+		if meta.IsStatusConditionTrue(customResource.Status.Conditions, brokerv1beta1.NotReachableConditionType) {
+			reqLogger.V(1).Info("external endpoints not reachable yet, requeuing")
+			resync = true
+		}
+		if customResource.Spec.DeploymentPlan.CapacityForecast != nil {
+			reqLogger.V(1).Info("capacity forecast enabled, requeuing to sample usage")
+			resync = true
+		}
+		if !isSecurityGateOpen(customResource) {
+			reqLogger.V(1).Info("waiting for a security cr, requeuing")
+			resync = true
+		}
+		if isRecreateInProgress(customResource) {
+			reqLogger.V(1).Info("statefulset recreate in progress, requeuing")
+			resync = true
+		}
+		if isReconcileStepFailed(customResource) {
+			reqLogger.V(1).Info("a reconcile step failed, requeuing")
+			resync = true
+		}
+		if resync {
+			result = r.requeueForResync(request, common.GetReconcileResyncPeriod())
+		}
+	} else {
+		reqLogger.V(1).Info("requeue resource")
+	}
+	return result, err
+}
+
+// brokerValidation is one check of a broker cr. A check either needs nothing but the cr or retrieves
+// resources from the cluster, the ones that retrieve can ask for a retry while a resource is missing
+type brokerValidation struct {
+	// when set the check only runs when the cr enables what it checks
+	enabled  func(*brokerv1beta1.ActiveMQArtemis) bool
+	check    func(*brokerv1beta1.ActiveMQArtemis) *metav1.Condition
+	retrieve func(*brokerv1beta1.ActiveMQArtemis, rtclient.Client, *runtime.Scheme) (*metav1.Condition, bool)
+	// the check depends on the other custom resources the operator follows, there are none without a cluster
+	followsCRs bool
+}
+
+// brokerValidations are the checks of validate and ValidatePolicies in the order they run, the first
+// condition that isn't true is reported
+var brokerValidations = []brokerValidation{
+	{retrieve: validateExtraMounts},
+	{check: validateBrokerVersion},
+	{
+		enabled: func(cr *brokerv1beta1.ActiveMQArtemis) bool { return cr.Spec.DeploymentPlan.PodDisruptionBudget != nil },
+		check:   validatePodDisruption,
+	},
+	{retrieve: func(cr *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, scheme *runtime.Scheme) (*metav1.Condition, bool) {
+		return validateSSLEnabledSecrets(cr, client, scheme, *MakeNamers(cr))
+	}},
+	{
+		enabled:  func(cr *brokerv1beta1.ActiveMQArtemis) bool { return cr.Spec.BrokerXmlConfigMap != nil },
+		retrieve: validateBrokerXmlConfigMap,
+	},
+	{
+		check:      validateRolesGrantedBySecurity,
+		followsCRs: true,
+	},
+	{
+		check:      validateCertificateClientAuth,
+		followsCRs: true,
+	},
+	{retrieve: validateGssapiAcceptors},
+	{
+		enabled:  func(cr *brokerv1beta1.ActiveMQArtemis) bool { return cr.Spec.DeploymentPlan.Persistence.JDBC != nil },
+		retrieve: validateJdbcPersistence,
+	},
+	{
+		enabled:  func(cr *brokerv1beta1.ActiveMQArtemis) bool { return cr.Spec.Logging != nil },
+		retrieve: validateLogging,
+	},
+	{check: validateBindInterfaces},
+	{
+		enabled: func(cr *brokerv1beta1.ActiveMQArtemis) bool { return cr.Spec.DeploymentPlan.JVM != nil },
+		check:   validateJvm,
+	},
+	{
+		enabled: func(cr *brokerv1beta1.ActiveMQArtemis) bool { return cr.Spec.DeploymentPlan.Metrics != nil },
+		check:   validateMetrics,
+	},
+	{
+		enabled: func(cr *brokerv1beta1.ActiveMQArtemis) bool { return cr.Spec.DeploymentPlan.AntiAffinityPreset != "" },
+		check:   validateAntiAffinityPreset,
+	},
+	{
+		enabled: func(cr *brokerv1beta1.ActiveMQArtemis) bool { return cr.Spec.HeadlessService != nil },
+		check:   validateHeadlessService,
+	},
+	{retrieve: validateSaslResources},
+	{
+		enabled: func(cr *brokerv1beta1.ActiveMQArtemis) bool {
+			return cr.Spec.DeploymentPlan.CapacityPlaceholders != nil
+		},
+		check: validateCapacityPlaceholders,
+	},
+	{
+		enabled: func(cr *brokerv1beta1.ActiveMQArtemis) bool { return cr.Spec.DeploymentPlan.CapacityForecast != nil },
+		check:   validateCapacityForecast,
+	},
+	{check: validateConsole},
+	{
+		enabled:  consoleSSOEnabled,
+		retrieve: validateConsoleSSOResources,
+	},
+	{
+		enabled: func(cr *brokerv1beta1.ActiveMQArtemis) bool { return cr.Spec.CredentialsSource != nil },
+		check:   validateCredentialsSource,
+	},
+	{check: validateExposure},
+	{retrieve: validateRouteTLS},
+	{
+		enabled: func(cr *brokerv1beta1.ActiveMQArtemis) bool { return cr.Spec.DeploymentPlan.Ephemeral != nil },
+		check:   validateEphemeral,
+	},
+	{check: validateAcceptorPresets},
+	{check: validateTransportParams},
+	{
+		enabled: func(cr *brokerv1beta1.ActiveMQArtemis) bool {
+			return cr.Spec.DeploymentPlan.ImmutableFieldsPolicy != ""
+		},
+		check: validateImmutableFieldsPolicy,
+	},
+	{
+		enabled: func(cr *brokerv1beta1.ActiveMQArtemis) bool { return cr.Spec.TLSRenewal != "" },
+		check:   validateTLSRenewal,
+	},
+	{check: validateExternalDNS},
+	{check: validateCertificateIssuers},
+	{
+		enabled: func(cr *brokerv1beta1.ActiveMQArtemis) bool { return cr.Spec.ClusterTLS != nil },
+		check:   validateClusterTLS,
+	},
+	{
+		enabled:  func(cr *brokerv1beta1.ActiveMQArtemis) bool { return cr.Spec.Console.Jolokia != nil },
+		retrieve: validateJolokia,
+	},
+	{
+		enabled: func(cr *brokerv1beta1.ActiveMQArtemis) bool { return cr.Spec.ReservedAddressPrefixes != nil },
+		check:   validateReservedAddressPrefixes,
+	},
+	{
+		enabled: func(cr *brokerv1beta1.ActiveMQArtemis) bool { return len(cr.Spec.RetentionPolicies) > 0 },
+		check:   validateRetentionPolicies,
+	},
+	{
+		enabled: func(cr *brokerv1beta1.ActiveMQArtemis) bool { return cr.Spec.ClusterCredentialRotation != nil },
+		check:   validateClusterCredentialRotation,
+	},
+	{
+		enabled: func(cr *brokerv1beta1.ActiveMQArtemis) bool { return cr.Spec.AdminCredentialRotation != nil },
+		check:   validateAdminCredentialRotation,
+	},
+	{check: validateIPFamilies},
+	{
+		enabled: func(cr *brokerv1beta1.ActiveMQArtemis) bool { return cr.Spec.Redelivery != nil },
+		check:   validateRedelivery,
+	},
+	{
+		enabled: func(cr *brokerv1beta1.ActiveMQArtemis) bool { return cr.Spec.ClusterConnection != nil },
+		check:   validateClusterConnection,
+	},
+	{
+		enabled: func(cr *brokerv1beta1.ActiveMQArtemis) bool { return cr.Spec.Autoscaling != nil },
+		check:   validateAutoscaling,
+	},
+	{
+		enabled: func(cr *brokerv1beta1.ActiveMQArtemis) bool { return cr.Spec.HA != nil },
+		check:   validateHA,
+	},
+	{
+		enabled:  func(cr *brokerv1beta1.ActiveMQArtemis) bool { return isHAEnabled(cr) && cr.Spec.HA.ZooKeeper != nil },
+		retrieve: validateHAZooKeeper,
+	},
+	{
+		enabled:  func(cr *brokerv1beta1.ActiveMQArtemis) bool { return requiresPersistentVolume(cr) },
+		retrieve: validateHAStoreChange,
+	},
+	{
+		enabled:  func(cr *brokerv1beta1.ActiveMQArtemis) bool { return len(cr.Spec.Federations) > 0 },
+		retrieve: validateFederations,
+	},
+	{
+		enabled:  func(cr *brokerv1beta1.ActiveMQArtemis) bool { return len(cr.Spec.AMQPConnections) > 0 },
+		retrieve: validateAMQPConnections,
+	},
+	{
+		enabled:  func(cr *brokerv1beta1.ActiveMQArtemis) bool { return cr.Spec.ClusterMesh != nil },
+		retrieve: validateClusterMesh,
+	},
+}
+
+func validate(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, scheme *runtime.Scheme, namer Namers) (bool, ctrl.Result) {
+	// Do additional validation here
+	validationCondition := metav1.Condition{
+		Type:   brokerv1beta1.ValidConditionType,
+		Status: metav1.ConditionTrue,
+		Reason: brokerv1beta1.ValidConditionSuccessReason,
+	}
+
+	var retry bool
+	for _, validation := range brokerValidations {
+		if validationCondition.Status != metav1.ConditionTrue {
+			break
+		}
+		if validation.enabled != nil && !validation.enabled(customResource) {
+			continue
+		}
+		var condition *metav1.Condition
+		if validation.retrieve != nil {
+			condition, retry = validation.retrieve(customResource, client, scheme)
+		} else {
+			condition = validation.check(customResource)
+		}
+		if condition != nil {
+			validationCondition = *condition
+		}
+	}
+
+	validationCondition.ObservedGeneration = customResource.Generation
+	meta.SetStatusCondition(&customResource.Status.Conditions, validationCondition)
+
+	if retry {
+		return validationCondition.Status == metav1.ConditionTrue, ctrl.Result{Requeue: retry, RequeueAfter: common.GetReconcileResyncPeriod()}
+	} else {
+		return validationCondition.Status == metav1.ConditionTrue, ctrl.Result{}
+	}
+}
+
+// ValidatePolicies runs the checks of validate that don't need to read anything from the cluster,
+// it returns the first condition that isn't true
+func ValidatePolicies(customResource *brokerv1beta1.ActiveMQArtemis) *metav1.Condition {
+	for _, validation := range brokerValidations {
+		if validation.check == nil || validation.followsCRs {
+			continue
+		}
+		if validation.enabled != nil && !validation.enabled(customResource) {
+			continue
+		}
+		if condition := validation.check(customResource); condition != nil && condition.Status != metav1.ConditionTrue {
+			return condition
+		}
+	}
+	return nil
+}
+
+func validateSSLEnabledSecrets(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, scheme *runtime.Scheme, namer Namers) (*metav1.Condition, bool) {
+
+	var retry = true
+	if customResource.Spec.Console.SSLEnabled {
+
+		secretName := namer.SecretsConsoleNameBuilder.Name()
+
+		secret := corev1.Secret{}
+		found := retrieveResource(secretName, customResource.Namespace, &secret, client, scheme)
+		if !found {
+			return &metav1.Condition{
+				Type:    brokerv1beta1.ValidConditionType,
+				Status:  metav1.ConditionFalse,
+				Reason:  brokerv1beta1.ValidConditionMissingResourcesReason,
+				Message: fmt.Sprintf(".Spec.Console.SSLEnabled is true but required secret %v is not found", secretName),
+			}, retry
+		}
+
+		contextMessage := ".Spec.Console.SSLEnabled is true but required"
+		for _, key := range []string{
+			"keyStorePassword",
+			"trustStorePassword",
+		} {
+			Condition := AssertSecretContainsKey(secret, key, contextMessage)
+			if Condition != nil {
+				return Condition, retry
+			}
+		}
+
+		Condition := AssertSecretContainsOneOf(secret, []string{
+			"keyStorePath",
+			"broker.ks"}, contextMessage)
+		if Condition != nil {
+			return Condition, retry
+		}
+
+		Condition = AssertSecretContainsOneOf(secret, []string{
+			"trustStorePath",
+			"client.ts"}, contextMessage)
+		if Condition != nil {
+			return Condition, retry
+		}
+	}
+	return nil, false
+}
+
+func getApplicableSecurityCR(customResource *brokerv1beta1.ActiveMQArtemis) *brokerv1beta1.ActiveMQArtemisSecurity {
+	handler, ok := GetBrokerConfigHandler(types.NamespacedName{Name: customResource.Name, Namespace: customResource.Namespace}).(*ActiveMQArtemisSecurityConfigHandler)
+	if !ok || handler == nil {
+		return nil
+	}
+	return handler.SecurityCR
+}
+
+// the certificate login modules only see the certificates of clients an acceptor asks for one
+func validateCertificateClientAuth(customResource *brokerv1beta1.ActiveMQArtemis) *metav1.Condition {
+	securityCR := getApplicableSecurityCR(customResource)
+	if securityCR == nil || len(securityCR.Spec.LoginModules.CertificateLoginModules) == 0 {
+		return nil
+	}
+	for _, acceptor := range customResource.Spec.Acceptors {
+		if acceptor.SSLEnabled && (acceptor.NeedClientAuth || acceptor.WantClientAuth) {
+			return nil
+		}
+	}
+	return &metav1.Condition{
+		Type:    brokerv1beta1.ValidConditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  brokerv1beta1.ValidConditionClientAuthRequiredReason,
+		Message: fmt.Sprintf("security cr %v has certificate login modules but no acceptor has sslEnabled with needClientAuth or wantClientAuth", securityCR.Name),
+	}
+}
+
+// an acceptor offering GSSAPI without its own saslLoginConfigScope takes the first kerberos module of
+// the applicable security cr, without one the broker can't accept the GSSAPI contexts of its clients.
+// Until the security controller registered the handler of the security cr, as after a restart of the
+// operator, the cr itself tells there is a kerberos module
+func validateGssapiAcceptors(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, scheme *runtime.Scheme) (*metav1.Condition, bool) {
+	for _, acceptor := range customResource.Spec.Acceptors {
+		if !strings.Contains(strings.ToUpper(acceptor.SASLMechanisms), "GSSAPI") || saslLoginConfigScope(customResource, acceptor) != "" {
+			continue
+		}
+		if unregisteredKerberosSecurityCR(customResource, client) {
+			return nil, true
+		}
+		return &metav1.Condition{
+			Type:    brokerv1beta1.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  brokerv1beta1.ValidConditionGssapiWithoutKerberosReason,
+			Message: fmt.Sprintf("acceptor %v offers GSSAPI but has no saslLoginConfigScope and no applicable security cr has a kerberos login module", acceptor.Name),
+		}, false
+	}
+	return nil, false
+}
+
+// unregisteredKerberosSecurityCR tells if a security cr with a kerberos login module applies to the
+// broker while it has no handler yet
+func unregisteredKerberosSecurityCR(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client) bool {
+	securityCRs := &brokerv1beta1.ActiveMQArtemisSecurityList{}
+	if err := client.List(context.TODO(), securityCRs, rtclient.InNamespace(customResource.Namespace)); err != nil {
+		clog.V(1).Info("unable to list the security crs of the broker", "error", err)
+		return false
+	}
+	for _, securityCR := range securityCRs.Items {
+		if len(securityCR.Spec.LoginModules.KerberosLoginModules) > 0 &&
+			appliesToBrokerCR(securityCR.Spec.ApplyToCrNames, securityCR.Spec.ApplyToCrSelector, customResource) {
+			return true
+		}
+	}
+	return false
+}
+
+// when an applicable security cr restricts management access, the roles the operator wires in
+// for the admin user and the web console must be part of what it grants
+func validateRolesGrantedBySecurity(customResource *brokerv1beta1.ActiveMQArtemis) *metav1.Condition {
+	securityCR := getApplicableSecurityCR(customResource)
+	if securityCR == nil {
+		return nil
+	}
+	management := securityCR.Spec.SecuritySettings.Management
+
+	if len(management.HawtioRoles) > 0 {
+		for _, role := range getHawtioRoles(customResource) {
+			if !containsString(management.HawtioRoles, role) {
+				return &metav1.Condition{
+					Type:    brokerv1beta1.ValidConditionType,
+					Status:  metav1.ConditionFalse,
+					Reason:  brokerv1beta1.ValidConditionRoleNotGrantedReason,
+					Message: fmt.Sprintf("web console role %v is not in securitySettings.management.hawtioRoles of security cr %v", role, securityCR.Name),
+				}
+			}
+		}
+	}
+
+	grantedRoles := []string{}
+	for _, access := range management.Authorisation.DefaultAccess {
+		grantedRoles = append(grantedRoles, access.Roles...)
+	}
+	for _, roleAccess := range management.Authorisation.RoleAccess {
+		for _, access := range roleAccess.AccessList {
+			grantedRoles = append(grantedRoles, access.Roles...)
+		}
+	}
+	adminRole := getAdminRole(customResource)
+	if len(grantedRoles) > 0 && !containsString(grantedRoles, adminRole) {
+		return &metav1.Condition{
+			Type:    brokerv1beta1.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  brokerv1beta1.ValidConditionRoleNotGrantedReason,
+			Message: fmt.Sprintf("admin role %v is not granted by securitySettings.management.authorisation of security cr %v", adminRole, securityCR.Name),
+		}
+	}
+	return nil
+}
+
+func validateBrokerXmlConfigMap(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, scheme *runtime.Scheme) (*metav1.Condition, bool) {
+
+	brokerXml := customResource.Spec.BrokerXmlConfigMap
+	configMap := corev1.ConfigMap{}
+	found := retrieveResource(brokerXml.Name, customResource.Namespace, &configMap, client, scheme)
+	if !found {
+		return &metav1.Condition{
+			Type:    brokerv1beta1.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  brokerv1beta1.ValidConditionMissingResourcesReason,
+			Message: fmt.Sprintf(".Spec.BrokerXmlConfigMap missing required configMap %v", brokerXml.Name),
+		}, true
+	}
+	if Condition := AssertConfigMapContainsKey(configMap, brokerXml.Key, ".Spec.BrokerXmlConfigMap"); Condition != nil {
+		return Condition, true
+	}
+	return nil, false
+}
+
+func validateLogging(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, scheme *runtime.Scheme) (*metav1.Condition, bool) {
+
+	logging := customResource.Spec.Logging
+	if _, _, found := getConfigExtraMount(customResource, loggingConfigSuffix); found {
+		return &metav1.Condition{
+			Type:    brokerv1beta1.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  brokerv1beta1.ValidConditionInvalidLoggingReason,
+			Message: fmt.Sprintf(".Spec.Logging can not be combined with an extraMounts entry with suffix %v", loggingConfigSuffix),
+		}, false
+	}
+
+	levels := map[string]string{}
+	if logging.RootLevel != "" {
+		levels["rootLevel"] = logging.RootLevel
+	}
+	for category, level := range logging.Levels {
+		levels["levels."+category] = level
+	}
+	for _, key := range sortedKeys(levels) {
+		if !isValidLogLevel(levels[key]) {
+			return &metav1.Condition{
+				Type:    brokerv1beta1.ValidConditionType,
+				Status:  metav1.ConditionFalse,
+				Reason:  brokerv1beta1.ValidConditionInvalidLoggingReason,
+				Message: fmt.Sprintf(".Spec.Logging.%v has invalid level %v, expected one of %v", key, levels[key], log4j2Levels),
+			}, false
+		}
+	}
+
+	if logging.ConfigMap != nil {
+		configMap := corev1.ConfigMap{}
+		found := retrieveResource(logging.ConfigMap.Name, customResource.Namespace, &configMap, client, scheme)
+		if !found {
+			return &metav1.Condition{
+				Type:    brokerv1beta1.ValidConditionType,
+				Status:  metav1.ConditionFalse,
+				Reason:  brokerv1beta1.ValidConditionMissingResourcesReason,
+				Message: fmt.Sprintf(".Spec.Logging.ConfigMap missing required configMap %v", logging.ConfigMap.Name),
+			}, true
+		}
+		if Condition := AssertConfigMapContainsKey(configMap, logging.ConfigMap.Key, ".Spec.Logging.ConfigMap"); Condition != nil {
+			return Condition, true
+		}
+	}
+	return nil, false
+}
+
+// the sasl login modules of an applicable security cr are mounted into the broker pods, the client
+// secrets of its keycloak login modules are read into the login config
+func validateSaslResources(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, scheme *runtime.Scheme) (*metav1.Condition, bool) {
+	securityCR := getApplicableSecurityCR(customResource)
+	if securityCR == nil {
+		return nil, false
+	}
+	secretNames, configMapNames, _ := saslMountsAndArgs(securityCR)
+	for _, name := range secretNames {
+		if !retrieveResource(name, customResource.Namespace, &corev1.Secret{}, client, scheme) {
+			return &metav1.Condition{
+				Type:    brokerv1beta1.ValidConditionType,
+				Status:  metav1.ConditionFalse,
+				Reason:  brokerv1beta1.ValidConditionMissingResourcesReason,
+				Message: fmt.Sprintf("security cr %v login modules missing required secret %v", securityCR.Name, name),
+			}, true
+		}
+	}
+	for _, name := range configMapNames {
+		if !retrieveResource(name, customResource.Namespace, &corev1.ConfigMap{}, client, scheme) {
+			return &metav1.Condition{
+				Type:    brokerv1beta1.ValidConditionType,
+				Status:  metav1.ConditionFalse,
+				Reason:  brokerv1beta1.ValidConditionMissingResourcesReason,
+				Message: fmt.Sprintf("security cr %v login modules missing required configMap %v", securityCR.Name, name),
+			}, true
+		}
+	}
+	for _, selector := range securitySecretReferences(securityCR) {
+		secret := corev1.Secret{}
+		if !retrieveResource(selector.Name, customResource.Namespace, &secret, client, scheme) {
+			return &metav1.Condition{
+				Type:    brokerv1beta1.ValidConditionType,
+				Status:  metav1.ConditionFalse,
+				Reason:  brokerv1beta1.ValidConditionMissingResourcesReason,
+				Message: fmt.Sprintf("security cr %v login modules missing required secret %v", securityCR.Name, selector.Name),
+			}, true
+		}
+		if condition := AssertSecretContainsKey(secret, selector.Key, fmt.Sprintf("security cr %v login modules", securityCR.Name)); condition != nil {
+			return condition, true
+		}
+	}
+	return nil, false
+}
+
+func validateJvm(customResource *brokerv1beta1.ActiveMQArtemis) *metav1.Condition {
+
+	jvm := customResource.Spec.DeploymentPlan.JVM
+	invalid := func(message string) *metav1.Condition {
+		return &metav1.Condition{
+			Type:    brokerv1beta1.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  brokerv1beta1.ValidConditionInvalidJvmReason,
+			Message: ".Spec.DeploymentPlan.JVM " + message,
+		}
+	}
+
+	if jvm.GarbageCollector != "" {
+		if _, found := garbageCollectorFlags[jvm.GarbageCollector]; !found {
+			return invalid(fmt.Sprintf("garbageCollector %v is not one of %v", jvm.GarbageCollector, sortedKeys(garbageCollectorFlags)))
+		}
+	}
+
+	for _, size := range []struct {
+		name     string
+		quantity *resource.Quantity
+	}{{"initialHeapSize", jvm.InitialHeapSize}, {"maxHeapSize", jvm.MaxHeapSize}, {"maxMetaspaceSize", jvm.MaxMetaspaceSize}} {
+		if size.quantity != nil && size.quantity.Value() < 1024*1024 {
+			return invalid(fmt.Sprintf("%v %v must be at least 1Mi", size.name, size.quantity.String()))
+		}
+	}
+
+	if jvm.InitialHeapSize != nil && jvm.MaxHeapSize != nil && jvm.InitialHeapSize.Cmp(*jvm.MaxHeapSize) > 0 {
+		return invalid(fmt.Sprintf("initialHeapSize %v is above maxHeapSize %v", jvm.InitialHeapSize.String(), jvm.MaxHeapSize.String()))
+	}
+
+	if memoryLimit, found := customResource.Spec.DeploymentPlan.Resources.Limits[corev1.ResourceMemory]; found {
+		for _, size := range []struct {
+			name     string
+			quantity *resource.Quantity
+		}{{"initialHeapSize", jvm.InitialHeapSize}, {"maxHeapSize", jvm.MaxHeapSize}} {
+			if size.quantity != nil && size.quantity.Cmp(memoryLimit) >= 0 {
+				return invalid(fmt.Sprintf("%v %v must be below the container memory limit %v", size.name, size.quantity.String(), memoryLimit.String()))
+			}
+		}
+	}
+
+	for _, arg := range jvm.ExtraArgs {
+		if !strings.HasPrefix(arg, "-") || strings.ContainsAny(arg, " \t") {
+			return invalid(fmt.Sprintf("extraArgs entry %q must be a single flag starting with -", arg))
+		}
+		for _, conflict := range []struct {
+			prefix string
+			set    bool
+		}{
+			{"-Xms", jvm.InitialHeapSize != nil},
+			{"-Xmx", jvm.MaxHeapSize != nil},
+			{"-XX:MaxMetaspaceSize=", jvm.MaxMetaspaceSize != nil},
+			{"-XX:+Use", jvm.GarbageCollector != "" && strings.HasSuffix(arg, "GC")},
+		} {
+			if conflict.set && strings.HasPrefix(arg, conflict.prefix) {
+				return invalid(fmt.Sprintf("extraArgs entry %v conflicts with the structured fields", arg))
+			}
+		}
+	}
+	return nil
+}
+
+func validateMetrics(customResource *brokerv1beta1.ActiveMQArtemis) *metav1.Condition {
+
+	metrics := customResource.Spec.DeploymentPlan.Metrics
+	if metrics.PortName != "" {
+		if errs := validation.IsValidPortName(metrics.PortName); len(errs) > 0 {
+			return &metav1.Condition{
+				Type:    brokerv1beta1.ValidConditionType,
+				Status:  metav1.ConditionFalse,
+				Reason:  brokerv1beta1.ValidConditionInvalidMetricsReason,
+				Message: fmt.Sprintf(".Spec.DeploymentPlan.Metrics.PortName %v is invalid: %v", metrics.PortName, strings.Join(errs, ", ")),
+			}
+		}
+	}
+	if metrics.Port != 0 {
+		if errs := validation.IsValidPortNum(int(metrics.Port)); len(errs) > 0 {
+			return &metav1.Condition{
+				Type:    brokerv1beta1.ValidConditionType,
+				Status:  metav1.ConditionFalse,
+				Reason:  brokerv1beta1.ValidConditionInvalidMetricsReason,
+				Message: fmt.Sprintf(".Spec.DeploymentPlan.Metrics.Port %v is invalid: %v", metrics.Port, strings.Join(errs, ", ")),
+			}
+		}
+	}
+
+	ports := headlessServicePorts(customResource)
+	metricsPort := (*ports)[len(*ports)-1]
+	for _, port := range (*ports)[:len(*ports)-1] {
+		if port.Name == metricsPort.Name || port.Port == metricsPort.Port {
+			return &metav1.Condition{
+				Type:    brokerv1beta1.ValidConditionType,
+				Status:  metav1.ConditionFalse,
+				Reason:  brokerv1beta1.ValidConditionInvalidMetricsReason,
+				Message: fmt.Sprintf(".Spec.DeploymentPlan.Metrics port %v/%v clashes with the headless service port %v/%v", metricsPort.Name, metricsPort.Port, port.Name, port.Port),
+			}
+		}
+	}
+	return nil
+}
+
+// validateAntiAffinityPreset reports what the webhook rejects, for when the webhook is not deployed
+func validateAntiAffinityPreset(customResource *brokerv1beta1.ActiveMQArtemis) *metav1.Condition {
+	if err := customResource.ValidateAntiAffinityPreset(); err != nil {
+		return &metav1.Condition{
+			Type:    brokerv1beta1.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  brokerv1beta1.ValidConditionInvalidAffinityReason,
+			Message: err.Error(),
+		}
+	}
+	return nil
+}
+
+// validateHeadlessService checks the extra ports of the headless service against each other and the
+// ports the operator publishes
+func validateHeadlessService(customResource *brokerv1beta1.ActiveMQArtemis) *metav1.Condition {
+
+	invalid := func(message string) *metav1.Condition {
+		return &metav1.Condition{
+			Type:    brokerv1beta1.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  brokerv1beta1.ValidConditionInvalidHeadlessServiceReason,
+			Message: message,
+		}
+	}
+
+	protocol := func(port corev1.ServicePort) corev1.Protocol {
+		if port.Protocol == "" {
+			return corev1.ProtocolTCP
+		}
+		return port.Protocol
+	}
+
+	published := *headlessServicePorts(customResource)
+	for i, port := range customResource.Spec.HeadlessService.Ports {
+		if errs := validation.IsValidPortName(port.Name); len(errs) > 0 {
+			return invalid(fmt.Sprintf(".Spec.HeadlessService.Ports[%d].Name %q is invalid: %v", i, port.Name, strings.Join(errs, ", ")))
+		}
+		if errs := validation.IsValidPortNum(int(port.Port)); len(errs) > 0 {
+			return invalid(fmt.Sprintf(".Spec.HeadlessService.Ports[%d].Port %v is invalid: %v", i, port.Port, strings.Join(errs, ", ")))
+		}
+		for _, other := range published {
+			if port.Name == other.Name || (port.Port == other.Port && protocol(port) == protocol(other)) {
+				return invalid(fmt.Sprintf(".Spec.HeadlessService.Ports[%d] %v/%v clashes with the headless service port %v/%v", i, port.Name, port.Port, other.Name, other.Port))
+			}
+		}
+		published = append(published, port)
+	}
+	return nil
+}
+
+func validateCapacityPlaceholders(customResource *brokerv1beta1.ActiveMQArtemis) *metav1.Condition {
+
+	placeholders := customResource.Spec.DeploymentPlan.CapacityPlaceholders
+	var message string
+	if placeholders.Replicas < 0 {
+		message = fmt.Sprintf(".Spec.DeploymentPlan.CapacityPlaceholders.Replicas %d must not be negative", placeholders.Replicas)
+	} else if placeholders.PriorityClassName == "" {
+		message = ".Spec.DeploymentPlan.CapacityPlaceholders.PriorityClassName is required, placeholders must have a lower priority than the brokers"
+	} else if len(customResource.Spec.DeploymentPlan.Resources.Requests) == 0 {
+		message = ".Spec.DeploymentPlan.CapacityPlaceholders needs .Spec.DeploymentPlan.Resources.Requests to know the capacity of a broker pod"
+	}
+	if message != "" {
+		return &metav1.Condition{
+			Type:    brokerv1beta1.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  brokerv1beta1.ValidConditionInvalidPlaceholdersReason,
+			Message: message,
+		}
+	}
+	return nil
+}
+
+func validateCapacityForecast(customResource *brokerv1beta1.ActiveMQArtemis) *metav1.Condition {
+
+	capacityForecast := customResource.Spec.DeploymentPlan.CapacityForecast
+	var message string
+	if capacityForecast.HorizonMinutes != nil && *capacityForecast.HorizonMinutes <= 0 {
+		message = fmt.Sprintf(".Spec.DeploymentPlan.CapacityForecast.HorizonMinutes %d must be positive", *capacityForecast.HorizonMinutes)
+	} else if capacityForecast.WindowMinutes != nil && *capacityForecast.WindowMinutes <= 0 {
+		message = fmt.Sprintf(".Spec.DeploymentPlan.CapacityForecast.WindowMinutes %d must be positive", *capacityForecast.WindowMinutes)
+	}
+	if message != "" {
+		return &metav1.Condition{
+			Type:    brokerv1beta1.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  brokerv1beta1.ValidConditionInvalidForecastReason,
+			Message: message,
+		}
+	}
+	return nil
+}
+
+func validateConsole(customResource *brokerv1beta1.ActiveMQArtemis) *metav1.Condition {
+
+	console := customResource.Spec.Console
+	var message string
+	if console.Disabled && (console.Expose || console.SSLEnabled || console.Jolokia != nil || console.SSO != nil) {
+		message = ".Spec.Console.Disabled is true but the console is configured with expose, sslEnabled, jolokia or sso"
+	} else if console.Port != 0 && len(validation.IsValidPortNum(int(console.Port))) > 0 {
+		message = fmt.Sprintf(".Spec.Console.Port %d is not a valid port", console.Port)
+	} else if console.BindHost != "" && !consoleBindHostRegex.MatchString(console.BindHost) {
+		message = fmt.Sprintf(".Spec.Console.BindHost %q is not a valid host name or address", console.BindHost)
+	} else if console.SessionTimeoutSeconds != nil && *console.SessionTimeoutSeconds <= 0 {
+		message = fmt.Sprintf(".Spec.Console.SessionTimeoutSeconds %d must be positive", *console.SessionTimeoutSeconds)
+	} else if console.Host != "" && len(validation.IsDNS1123Subdomain(hostForOrdinal(console.Host, 0))) > 0 {
+		message = fmt.Sprintf(".Spec.Console.Host %q is not a valid host name", console.Host)
+	} else if console.Host != "" && getDeploymentSize(customResource) > 1 && !strings.Contains(console.Host, hostOrdinal) {
+		message = fmt.Sprintf(".Spec.Console.Host %q must contain %v to tell the brokers apart", console.Host, hostOrdinal)
+	} else if console.Path != "" && !strings.HasPrefix(console.Path, "/") {
+		message = fmt.Sprintf(".Spec.Console.Path %q must start with /", console.Path)
+	} else if console.Path != "" && console.SSLEnabled && (console.RouteTLS == nil || console.RouteTLS.Termination == "" || console.RouteTLS.Termination == brokerv1beta1.RouteTerminationPassthrough) {
+		message = ".Spec.Console.Path can't be used when TLS is passed through to the broker"
+	}
+	if message == "" && console.SSO != nil {
+		message = validateConsoleSSO(console)
+	}
+	if message == "" && console.Port != 0 {
+		for _, acceptor := range customResource.Spec.Acceptors {
+			if acceptorPort(acceptor) == console.Port {
+				message = fmt.Sprintf(".Spec.Console.Port %d clashes with the port of acceptor %v", console.Port, acceptor.Name)
+			}
+		}
+	}
+	if message != "" {
+		return &metav1.Condition{
+			Type:    brokerv1beta1.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  brokerv1beta1.ValidConditionInvalidConsoleReason,
+			Message: message,
+		}
+	}
+	return nil
+}
+
+func validateConsoleSSO(console brokerv1beta1.ConsoleType) string {
+	sso := console.SSO
+	if issuer, err := url.Parse(sso.IssuerURL); err != nil || issuer.Scheme != "https" || issuer.Host == "" {
+		return fmt.Sprintf(".Spec.Console.SSO.IssuerURL %q must be an https URL", sso.IssuerURL)
+	}
+	if sso.ClientID == "" {
+		return ".Spec.Console.SSO.ClientID is required"
+	}
+	if sso.ClientSecret.Name == "" || sso.ClientSecret.Key == "" {
+		return ".Spec.Console.SSO.ClientSecret needs the name and key of the secret holding the client secret"
+	}
+	if console.RouteTLS != nil && console.RouteTLS.Termination == brokerv1beta1.RouteTerminationPassthrough {
+		return ".Spec.Console.SSO can't be used when TLS is passed through to the broker, the route must terminate TLS in front of the proxy"
+	}
+	if console.BindHost != "" {
+		return ".Spec.Console.SSO binds the console to the loopback address of the pod, .Spec.Console.BindHost can't be set"
+	}
+	for _, scope := range sso.Scopes {
+		if scope == "" || strings.ContainsAny(scope, " \t") {
+			return fmt.Sprintf(".Spec.Console.SSO.Scopes %q must be single scopes", scope)
+		}
+	}
+	return ""
+}
+
+// the client secret is read by the sign-on proxy of every broker pod, a missing secret or key
+// leaves the proxies unable to start
+func validateConsoleSSOResources(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, scheme *runtime.Scheme) (*metav1.Condition, bool) {
+	selector := customResource.Spec.Console.SSO.ClientSecret
+	secret := corev1.Secret{}
+	if !retrieveResource(selector.Name, customResource.Namespace, &secret, client, scheme) {
+		return &metav1.Condition{
+			Type:    brokerv1beta1.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  brokerv1beta1.ValidConditionMissingResourcesReason,
+			Message: fmt.Sprintf(".Spec.Console.SSO missing required secret %v", selector.Name),
+		}, true
+	}
+	if condition := AssertSecretContainsKey(secret, selector.Key, ".Spec.Console.SSO client"); condition != nil {
+		return condition, true
+	}
+	return nil, false
+}
+
+func validateExposure(customResource *brokerv1beta1.ActiveMQArtemis) *metav1.Condition {
+
+	var message string
+	checkServiceSettings := func(path string, settings *brokerv1beta1.ServiceSettingsType, external bool) {
+		if settings == nil || message != "" {
+			return
+		}
+		switch settings.SessionAffinity {
+		case "", corev1.ServiceAffinityNone, corev1.ServiceAffinityClientIP:
+		default:
+			message = fmt.Sprintf("%v.ServiceSettings.SessionAffinity %q must be None or ClientIP", path, settings.SessionAffinity)
+			return
+		}
+		switch settings.ExternalTrafficPolicy {
+		case "":
+		case corev1.ServiceExternalTrafficPolicyTypeCluster, corev1.ServiceExternalTrafficPolicyTypeLocal:
+			if !external {
+				message = fmt.Sprintf("%v.ServiceSettings.ExternalTrafficPolicy only applies to the loadBalancer and nodePort expose modes", path)
+				return
+			}
+		default:
+			message = fmt.Sprintf("%v.ServiceSettings.ExternalTrafficPolicy %q must be Cluster or Local", path, settings.ExternalTrafficPolicy)
+			return
+		}
+		if policy := settings.InternalTrafficPolicy; policy != nil && *policy != corev1.ServiceInternalTrafficPolicyCluster && *policy != corev1.ServiceInternalTrafficPolicyLocal {
+			message = fmt.Sprintf("%v.ServiceSettings.InternalTrafficPolicy %q must be Cluster or Local", path, *policy)
+		}
+	}
+	check := func(path string, ingressClassName string, exposeAnnotations map[string]string) {
+		if message != "" {
+			return
+		}
+		if ingressClassName != "" {
+			if errs := validation.IsDNS1123Subdomain(ingressClassName); len(errs) > 0 {
+				message = fmt.Sprintf("%v.IngressClassName %q is invalid: %v", path, ingressClassName, strings.Join(errs, ", "))
+				return
+			}
+		}
+		for key := range exposeAnnotations {
+			if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+				message = fmt.Sprintf("%v.ExposeAnnotations key %q is invalid: %v", path, key, strings.Join(errs, ", "))
+				return
+			}
+			if key == exposeAnnotationsKey {
+				message = fmt.Sprintf("%v.ExposeAnnotations key %q is reserved for the operator", path, key)
+				return
+			}
+		}
+	}
+
+	for _, acceptor := range customResource.Spec.Acceptors {
+		check(".Spec.Acceptors."+acceptor.Name, acceptor.IngressClassName, acceptor.ExposeAnnotations)
+		checkServiceSettings(".Spec.Acceptors."+acceptor.Name, acceptor.ServiceSettings, acceptor.Expose && isServiceExposeMode(acceptorExposeMode(acceptor)))
+		switch acceptorExposeMode(acceptor) {
+		case "", brokerv1beta1.ExposeModeRoute, brokerv1beta1.ExposeModeIngress, brokerv1beta1.ExposeModeLoadBalancer, brokerv1beta1.ExposeModeNodePort:
+		case brokerv1beta1.ExposeModeSNI:
+			if !acceptor.SSLEnabled && message == "" {
+				message = fmt.Sprintf(".Spec.Acceptors.%v.ExposeMode sni needs sslEnabled, the server name is read from the TLS handshake", acceptor.Name)
+			}
+		default:
+			if message == "" {
+				message = fmt.Sprintf(".Spec.Acceptors.%v.ExposeMode %q must be one of route, ingress, loadBalancer, nodePort or sni", acceptor.Name, *acceptor.ExposeMode)
+			}
+		}
+		if acceptor.NodePort != nil && message == "" {
+			last := *acceptor.NodePort
+			if acceptor.ExposePerPod {
+				last += getDeploymentSize(customResource) - 1
+			}
+			if acceptorExposeMode(acceptor) != brokerv1beta1.ExposeModeNodePort {
+				message = fmt.Sprintf(".Spec.Acceptors.%v.NodePort only applies to the nodePort expose mode", acceptor.Name)
+			} else if *acceptor.NodePort < 1 || last > 65535 {
+				message = fmt.Sprintf(".Spec.Acceptors.%v.NodePort range %d-%d is not a valid port range", acceptor.Name, *acceptor.NodePort, last)
+			}
+		}
+		if acceptor.PublishExternalConnectors && message == "" {
+			mode := acceptorExposeMode(acceptor)
+			if !acceptor.Expose {
+				message = fmt.Sprintf(".Spec.Acceptors.%v.PublishExternalConnectors needs the acceptor to be exposed", acceptor.Name)
+			} else if mode == brokerv1beta1.ExposeModeNodePort {
+				message = fmt.Sprintf(".Spec.Acceptors.%v.PublishExternalConnectors is not supported with the nodePort expose mode, the address of a node is not known", acceptor.Name)
+			} else if mode == brokerv1beta1.ExposeModeLoadBalancer && !acceptor.ExposePerPod {
+				message = fmt.Sprintf(".Spec.Acceptors.%v.PublishExternalConnectors needs exposePerPod with the loadBalancer expose mode", acceptor.Name)
+			}
+		}
+		if acceptor.ProxyProtocol && message == "" {
+			if !acceptor.Expose {
+				message = fmt.Sprintf(".Spec.Acceptors.%v.ProxyProtocol needs the acceptor to be exposed", acceptor.Name)
+			} else if mode := platformExposeMode(acceptorExposeMode(acceptor)); mode != brokerv1beta1.ExposeModeLoadBalancer && mode != brokerv1beta1.ExposeModeIngress {
+				message = fmt.Sprintf(".Spec.Acceptors.%v.ProxyProtocol needs the loadBalancer or ingress expose mode, nothing in front of a %v sends the header", acceptor.Name, mode)
+			}
+		}
+	}
+	check(".Spec.Console", customResource.Spec.Console.IngressClassName, customResource.Spec.Console.ExposeAnnotations)
+	checkServiceSettings(".Spec.Console", customResource.Spec.Console.ServiceSettings, false)
+
+	if message != "" {
+		return &metav1.Condition{
+			Type:    brokerv1beta1.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  brokerv1beta1.ValidConditionInvalidExposureReason,
+			Message: message,
+		}
+	}
+	return nil
+}
+
+// validateExternalDNS checks the records external-dns is asked to publish, each broker exposed on its
+// own needs a host name of its own
+func validateExternalDNS(customResource *brokerv1beta1.ActiveMQArtemis) *metav1.Condition {
+
+	var message string
+	check := func(path string, externalDNS *brokerv1beta1.ExternalDNSType, exposed bool, perBroker bool) {
+		if message != "" || externalDNS == nil {
+			return
+		}
+		hostname := externalDNS.Hostname
+		var errs []string
+		if hostname != "" {
+			errs = validation.IsDNS1123Subdomain(hostForOrdinal(hostname, 0))
+		}
+		if !exposed {
+			message = fmt.Sprintf("%v.ExternalDNS needs %v to be exposed", path, path)
+		} else if len(errs) > 0 {
+			message = fmt.Sprintf("%v.ExternalDNS.Hostname %q is not a valid DNS name: %v", path, hostname, strings.Join(errs, ", "))
+		} else if hostname != "" && perBroker && getDeploymentSize(customResource) > 1 && !strings.Contains(hostname, hostOrdinal) {
+			message = fmt.Sprintf("%v.ExternalDNS.Hostname %q must contain %v, each broker is exposed on its own", path, hostname, hostOrdinal)
+		} else if hostname != "" && !perBroker && strings.Contains(hostname, hostOrdinal) {
+			message = fmt.Sprintf("%v.ExternalDNS.Hostname %q can't contain %v, the brokers are exposed together", path, hostname, hostOrdinal)
+		} else if externalDNS.TTL < 0 {
+			message = fmt.Sprintf("%v.ExternalDNS.TTL %d must not be negative", path, externalDNS.TTL)
+		}
+		for key := range externalDNS.Annotations {
+			if message == "" && (!strings.HasPrefix(key, externalDNSAnnotationPrefix) || len(validation.IsQualifiedName(key)) > 0) {
+				message = fmt.Sprintf("%v.ExternalDNS.Annotations key %q must be a valid annotation starting with %v", path, key, externalDNSAnnotationPrefix)
+			}
+		}
+	}
+
+	for _, acceptor := range customResource.Spec.Acceptors {
+		perBroker := !isServiceExposeMode(acceptorExposeMode(acceptor)) || acceptor.ExposePerPod
+		check(".Spec.Acceptors."+acceptor.Name, acceptor.ExternalDNS, acceptor.Expose, perBroker)
+	}
+	check(".Spec.Console", customResource.Spec.Console.ExternalDNS, customResource.Spec.Console.Expose, true)
+
+	if message != "" {
+		return &metav1.Condition{
+			Type:    brokerv1beta1.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  brokerv1beta1.ValidConditionInvalidExternalDNSReason,
+			Message: message,
+		}
+	}
+	return nil
+}
+
+func validateEphemeral(customResource *brokerv1beta1.ActiveMQArtemis) *metav1.Condition {
+
+	var message string
+	ephemeral := customResource.Spec.DeploymentPlan.Ephemeral
+	if customResource.Spec.DeploymentPlan.PersistenceEnabled {
+		message = ".Spec.DeploymentPlan.Ephemeral can't be combined with persistenceEnabled"
+	} else if isJdbcPersistence(customResource) {
+		message = ".Spec.DeploymentPlan.Ephemeral can't be combined with JDBC persistence"
+	} else if ephemeral.SizeLimit != "" {
+		if quantity, err := resource.ParseQuantity(ephemeral.SizeLimit); err != nil {
+			message = fmt.Sprintf(".Spec.DeploymentPlan.Ephemeral.SizeLimit %q is not a valid quantity: %v", ephemeral.SizeLimit, err)
+		} else if quantity.Sign() <= 0 {
+			message = fmt.Sprintf(".Spec.DeploymentPlan.Ephemeral.SizeLimit %q must be positive", ephemeral.SizeLimit)
+		}
+	}
+
+	if message != "" {
+		return &metav1.Condition{
+			Type:    brokerv1beta1.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  brokerv1beta1.ValidConditionInvalidEphemeralReason,
+			Message: message,
+		}
+	}
+	return nil
+}
+
+func validateRouteTLS(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, scheme *runtime.Scheme) (*metav1.Condition, bool) {
+
+	var message string
+	var missing string
+	check := func(path string, routeTLS *brokerv1beta1.RouteTLSType, sslEnabled bool) {
+		if routeTLS == nil || message != "" || missing != "" {
+			return
+		}
+		switch routeTLS.Termination {
+		case "", brokerv1beta1.RouteTerminationPassthrough:
+			if routeTLS.CertificateSecret != "" || routeTLS.DestinationCASecret != "" {
+				message = fmt.Sprintf("%v.RouteTLS secrets only apply to the edge and reencrypt terminations, with passthrough the broker serves its own certificate", path)
+			} else if routeTLS.Termination != "" && !sslEnabled {
+				message = fmt.Sprintf("%v.RouteTLS termination passthrough needs sslEnabled", path)
+			}
+		case brokerv1beta1.RouteTerminationEdge:
+			if sslEnabled {
+				message = fmt.Sprintf("%v.RouteTLS termination edge sends plain traffic on to the pod, it can't be used with sslEnabled", path)
+			} else if routeTLS.DestinationCASecret != "" {
+				message = fmt.Sprintf("%v.RouteTLS destinationCASecret only applies to the reencrypt termination", path)
+			}
+		case brokerv1beta1.RouteTerminationReencrypt:
+			if !sslEnabled {
+				message = fmt.Sprintf("%v.RouteTLS termination reencrypt needs sslEnabled", path)
+			}
+		default:
+			message = fmt.Sprintf("%v.RouteTLS termination %q must be one of passthrough, edge or reencrypt", path, routeTLS.Termination)
+		}
+		if message != "" {
+			return
+		}
+
+		secret := corev1.Secret{}
+		if routeTLS.CertificateSecret != "" {
+			if !retrieveResource(routeTLS.CertificateSecret, customResource.Namespace, &secret, client, scheme) {
+				missing = fmt.Sprintf("%v.RouteTLS missing required secret %v", path, routeTLS.CertificateSecret)
+			} else if len(secret.Data[corev1.TLSCertKey]) == 0 || len(secret.Data[corev1.TLSPrivateKeyKey]) == 0 {
+				missing = fmt.Sprintf("%v.RouteTLS secret %v must contain %v and %v", path, routeTLS.CertificateSecret, corev1.TLSCertKey, corev1.TLSPrivateKeyKey)
+			}
+		}
+		if routeTLS.DestinationCASecret != "" && missing == "" {
+			if !retrieveResource(routeTLS.DestinationCASecret, customResource.Namespace, &secret, client, scheme) {
+				missing = fmt.Sprintf("%v.RouteTLS missing required secret %v", path, routeTLS.DestinationCASecret)
+			} else if len(secret.Data[routeCAKey]) == 0 {
+				missing = fmt.Sprintf("%v.RouteTLS secret %v must contain %v", path, routeTLS.DestinationCASecret, routeCAKey)
+			}
+		}
+	}
+
+	for _, acceptor := range customResource.Spec.Acceptors {
+		check(".Spec.Acceptors."+acceptor.Name, acceptor.RouteTLS, acceptor.SSLEnabled)
+	}
+	check(".Spec.Console", customResource.Spec.Console.RouteTLS, customResource.Spec.Console.SSLEnabled)
+
+	if message != "" {
+		return &metav1.Condition{
+			Type:    brokerv1beta1.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  brokerv1beta1.ValidConditionInvalidRouteTLSReason,
+			Message: message,
+		}, false
+	}
+	if missing != "" {
+		// the secret may yet be created
+		return &metav1.Condition{
+			Type:    brokerv1beta1.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  brokerv1beta1.ValidConditionMissingResourcesReason,
+			Message: missing,
+		}, true
+	}
+	return nil, false
+}
+
+func validateAcceptorPresets(customResource *brokerv1beta1.ActiveMQArtemis) *metav1.Condition {
+	for _, acceptor := range customResource.Spec.Acceptors {
+		if _, found := acceptorPresets[acceptor.Preset]; acceptor.Preset != "" && !found {
+			return &metav1.Condition{
+				Type:    brokerv1beta1.ValidConditionType,
+				Status:  metav1.ConditionFalse,
+				Reason:  brokerv1beta1.ValidConditionInvalidPresetReason,
+				Message: fmt.Sprintf(".Spec.Acceptors.%v.Preset %q must be one of amqp, mqtt, stomp, openwire, core or all", acceptor.Name, acceptor.Preset),
+			}
+		}
+	}
+	return nil
+}
+
+var transportParamKeyRegex = regexp.MustCompile(`^[a-zA-Z0-9._-]+$`)
+
+// params end up in the acceptor and connector urls of broker.xml, so anything that would end the url or the element is refused
+func validateTransportParams(customResource *brokerv1beta1.ActiveMQArtemis) *metav1.Condition {
+
+	var message string
+	check := func(path string, params map[string]string) {
+		for key, value := range params {
+			if message != "" {
+				return
+			}
+			if !transportParamKeyRegex.MatchString(key) {
+				message = fmt.Sprintf("%v.Params key %q is invalid, it must consist of letters, digits, '.', '_' or '-'", path, key)
+			} else if strings.ContainsAny(value, ";&<>\"\\\n") {
+				message = fmt.Sprintf("%v.Params value of %q must not contain any of ;&<>\"\\ or a line break", path, key)
+			}
+		}
+	}
+
+	for _, acceptor := range customResource.Spec.Acceptors {
+		check(".Spec.Acceptors."+acceptor.Name, acceptor.Params)
+	}
+	for _, connector := range customResource.Spec.Connectors {
+		check(".Spec.Connectors."+connector.Name, connector.Params)
+	}
+
+	if message != "" {
+		return &metav1.Condition{
+			Type:    brokerv1beta1.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  brokerv1beta1.ValidConditionInvalidParamsReason,
+			Message: message,
+		}
+	}
+	return nil
+}
+
+func validateTLSRenewal(customResource *brokerv1beta1.ActiveMQArtemis) *metav1.Condition {
+	switch customResource.Spec.TLSRenewal {
+	case TLSRenewalRollingRestart, TLSRenewalReload, TLSRenewalNone:
+		return nil
+	}
+	return &metav1.Condition{
+		Type:    brokerv1beta1.ValidConditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  brokerv1beta1.ValidConditionInvalidTLSRenewalReason,
+		Message: fmt.Sprintf(".Spec.TLSRenewal %q must be one of %v, %v or %v", customResource.Spec.TLSRenewal, TLSRenewalRollingRestart, TLSRenewalReload, TLSRenewalNone),
+	}
+}
+
+func validateImmutableFieldsPolicy(customResource *brokerv1beta1.ActiveMQArtemis) *metav1.Condition {
+	switch customResource.Spec.DeploymentPlan.ImmutableFieldsPolicy {
+	case ImmutableFieldsPolicyBlock, ImmutableFieldsPolicyRecreate:
+		return nil
+	}
+	return &metav1.Condition{
+		Type:    brokerv1beta1.ValidConditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  brokerv1beta1.ValidConditionInvalidRecreateReason,
+		Message: fmt.Sprintf(".Spec.DeploymentPlan.ImmutableFieldsPolicy %q must be one of %v or %v", customResource.Spec.DeploymentPlan.ImmutableFieldsPolicy, ImmutableFieldsPolicyBlock, ImmutableFieldsPolicyRecreate),
+	}
+}
+
+func validateCertificateIssuers(customResource *brokerv1beta1.ActiveMQArtemis) *metav1.Condition {
+
+	var message string
+	check := func(path string, issuer *brokerv1beta1.CertificateIssuerType) {
+		if message != "" || issuer == nil {
+			return
+		}
+		if issuer.Name == "" {
+			message = path + ".Name is required"
+		} else if issuer.Kind != "" && issuer.Kind != "Issuer" && issuer.Kind != "ClusterIssuer" {
+			message = fmt.Sprintf("%v.Kind %q must be Issuer or ClusterIssuer", path, issuer.Kind)
+		}
+	}
+
+	check(".Spec.CertificateIssuer", customResource.Spec.CertificateIssuer)
+	if customResource.Spec.ClusterTLS != nil {
+		check(".Spec.ClusterTLS.CertificateIssuer", customResource.Spec.ClusterTLS.CertificateIssuer)
+	}
+	for _, acceptor := range customResource.Spec.Acceptors {
+		if acceptor.CertificateIssuer != nil && !acceptor.SSLEnabled && message == "" {
+			message = fmt.Sprintf(".Spec.Acceptors.%v.CertificateIssuer is set but sslEnabled is false", acceptor.Name)
+		}
+		check(".Spec.Acceptors."+acceptor.Name+".CertificateIssuer", acceptor.CertificateIssuer)
+	}
+	for _, connector := range customResource.Spec.Connectors {
+		if connector.CertificateIssuer != nil && !connector.SSLEnabled && message == "" {
+			message = fmt.Sprintf(".Spec.Connectors.%v.CertificateIssuer is set but sslEnabled is false", connector.Name)
+		}
+		check(".Spec.Connectors."+connector.Name+".CertificateIssuer", connector.CertificateIssuer)
+	}
+
+	// an issued certificate and its secret are named after the cr and the acceptor or connector, two
+	// of them with the same name would take each other's Certificate or keystores
+	issued := map[string]string{}
+	claim := func(path string, name string, sslSecret string) {
+		secretName := customResource.Name + "-" + name + "-secret"
+		if sslSecret != "" {
+			secretName = sslSecret
+		}
+		for _, key := range []string{"certificate " + customResource.Name + "-" + name + "-cert", "secret " + secretName} {
+			if other, found := issued[key]; found && message == "" {
+				message = fmt.Sprintf("%v is issued the %v of %v", path, key, other)
+			}
+			issued[key] = path
+		}
+	}
+	if customResource.Spec.ClusterTLS != nil {
+		claim(".Spec.ClusterTLS", clusterTLSName, "")
+	}
+	for _, acceptor := range customResource.Spec.Acceptors {
+		if getAcceptorCertificateIssuer(customResource, acceptor) != nil {
+			claim(".Spec.Acceptors."+acceptor.Name, acceptor.Name, acceptor.SSLSecret)
+		}
+	}
+	for _, connector := range customResource.Spec.Connectors {
+		if connector.SSLEnabled && connector.CertificateIssuer != nil {
+			claim(".Spec.Connectors."+connector.Name, connector.Name, connector.SSLSecret)
+		}
+	}
+
+	if message != "" {
+		return &metav1.Condition{
+			Type:    brokerv1beta1.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  brokerv1beta1.ValidConditionInvalidIssuerReason,
+			Message: message,
+		}
+	}
+	return nil
+}
+
+// the brokers of the cluster connect to the acceptor the operator generates on the cluster tls port,
+// that is the one that requires the client certificate
+func validateClusterTLS(customResource *brokerv1beta1.ActiveMQArtemis) *metav1.Condition {
+
+	clusterTLS := customResource.Spec.ClusterTLS
+	duration, validDuration := parseCertificateDuration(clusterTLS.Duration)
+	renewBefore, validRenewBefore := parseCertificateDuration(clusterTLS.RenewBefore)
+
+	var message string
+	if !isClustered(customResource) {
+		message = ".Spec.ClusterTLS is set but .Spec.DeploymentPlan.Clustered is false"
+	} else if !validDuration {
+		message = fmt.Sprintf(".Spec.ClusterTLS.Duration %q is not a valid duration", clusterTLS.Duration)
+	} else if !validRenewBefore {
+		message = fmt.Sprintf(".Spec.ClusterTLS.RenewBefore %q is not a valid duration", clusterTLS.RenewBefore)
+	} else if duration > 0 && renewBefore >= duration {
+		message = fmt.Sprintf(".Spec.ClusterTLS.RenewBefore %v must be shorter than the duration %v", clusterTLS.RenewBefore, clusterTLS.Duration)
+	}
+	for _, acceptor := range customResource.Spec.Acceptors {
+		if message == "" && acceptorPort(acceptor) == clusterTLSPort {
+			message = fmt.Sprintf(".Spec.Acceptors.%v uses port %d, which carries the cluster traffic with .Spec.ClusterTLS", acceptor.Name, clusterTLSPort)
+		} else if message == "" && acceptor.Name == clusterTLSName {
+			message = fmt.Sprintf(".Spec.Acceptors name %v is reserved with .Spec.ClusterTLS", clusterTLSName)
+		}
+	}
+	for _, connector := range customResource.Spec.Connectors {
+		if message == "" && connector.Name == clusterTLSName {
+			message = fmt.Sprintf(".Spec.Connectors name %v is reserved with .Spec.ClusterTLS", clusterTLSName)
 		}
-		reqLogger.Error(err, "unable to retrieve the ActiveMQArtemis", "request", request)
-		return ctrl.Result{}, err
 	}
 
-	namer := MakeNamers(customResource)
-	reconciler := ActiveMQArtemisReconcilerImpl{}
+	if message != "" {
+		return &metav1.Condition{
+			Type:    brokerv1beta1.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  brokerv1beta1.ValidConditionInvalidClusterTLSReason,
+			Message: message,
+		}
+	}
+	return nil
+}
 
-	result := ctrl.Result{}
-	var valid = true
+var consoleBindHostRegex = regexp.MustCompile(`^[a-zA-Z0-9.:-]+$`)
 
-	if valid, result = validate(customResource, r.Client, r.Scheme, *namer); valid {
+var jolokiaOriginRegex = regexp.MustCompile(`^[a-zA-Z0-9*.:/_\[\]-]+$`)
 
-		reconciler.Process(customResource, *namer, r.Client, r.Scheme)
+func validateJolokia(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, scheme *runtime.Scheme) (*metav1.Condition, bool) {
 
-		result = UpdateBrokerPropertiesStatus(customResource, r.Client, r.Scheme)
+	jolokia := customResource.Spec.Console.Jolokia
+	if jolokia.ForceHttps && !customResource.Spec.Console.SSLEnabled {
+		return &metav1.Condition{
+			Type:    brokerv1beta1.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  brokerv1beta1.ValidConditionInvalidJolokiaReason,
+			Message: ".Spec.Console.Jolokia.ForceHttps is true but .Spec.Console.SSLEnabled is false",
+		}, false
 	}
+	for _, origin := range jolokia.AllowedOrigins {
+		if !jolokiaOriginRegex.MatchString(origin) {
+			return &metav1.Condition{
+				Type:    brokerv1beta1.ValidConditionType,
+				Status:  metav1.ConditionFalse,
+				Reason:  brokerv1beta1.ValidConditionInvalidJolokiaReason,
+				Message: fmt.Sprintf(".Spec.Console.Jolokia.AllowedOrigins entry %q is not a valid origin pattern", origin),
+			}, false
+		}
+	}
+	if jolokia.CredentialsSecret != "" {
+		secret := corev1.Secret{}
+		found := retrieveResource(jolokia.CredentialsSecret, customResource.Namespace, &secret, client, scheme)
+		if !found {
+			return &metav1.Condition{
+				Type:    brokerv1beta1.ValidConditionType,
+				Status:  metav1.ConditionFalse,
+				Reason:  brokerv1beta1.ValidConditionMissingResourcesReason,
+				Message: fmt.Sprintf(".Spec.Console.Jolokia.CredentialsSecret %v is not found", jolokia.CredentialsSecret),
+			}, true
+		}
+		contextMessage := ".Spec.Console.Jolokia.CredentialsSecret is set but"
+		for _, key := range []string{"jolokiaUser", "jolokiaPassword"} {
+			if condition := AssertSecretContainsKey(secret, key, contextMessage); condition != nil {
+				return condition, true
+			}
+		}
+	}
+	return nil, false
+}
 
-	UpdateStatus(customResource, r.Client, request.NamespacedName, *namer)
-
-	err = UpdateCRStatus(customResource, r.Client, request.NamespacedName)
+func validateReservedAddressPrefixes(customResource *brokerv1beta1.ActiveMQArtemis) *metav1.Condition {
 
-	if err != nil {
-		if apierrors.IsConflict(err) {
-			reqLogger.V(1).Info("unable to update ActiveMQArtemis status", "Request Namespace", request.Namespace, "Request Name", request.Name, "error", err)
-			err = nil // we don't want the controller event loop reporting this as an error
-		} else {
-			reqLogger.V(1).Error(err, "unable to update ActiveMQArtemis status", "Request Namespace", request.Namespace, "Request Name", request.Name)
+	reserved := customResource.Spec.ReservedAddressPrefixes
+	for _, prefix := range reserved.Prefixes {
+		if !strings.HasSuffix(prefix, ".") || len(prefix) == 1 || strings.ContainsAny(prefix, "#* ") {
+			return &metav1.Condition{
+				Type:    brokerv1beta1.ValidConditionType,
+				Status:  metav1.ConditionFalse,
+				Reason:  brokerv1beta1.ValidConditionInvalidReservedPrefixReason,
+				Message: fmt.Sprintf(".Spec.ReservedAddressPrefixes.Prefixes entry %q must end with the address delimiter . and must not contain wildcards", prefix),
+			}
 		}
-		return ctrl.Result{RequeueAfter: common.GetReconcileResyncPeriod()}, err
 	}
-
-	if result.IsZero() {
-		reqLogger.Info("resource successfully reconciled")
-		if hasExtraMounts(customResource) {
-			reqLogger.V(1).Info("resource has extraMounts, requeuing for periodic sync")
-			result = ctrl.Result{RequeueAfter: common.GetReconcileResyncPeriod()}
+	for _, role := range reserved.Roles {
+		if role == "" || strings.ContainsAny(role, ".= ") {
+			return &metav1.Condition{
+				Type:    brokerv1beta1.ValidConditionType,
+				Status:  metav1.ConditionFalse,
+				Reason:  brokerv1beta1.ValidConditionInvalidReservedPrefixReason,
+				Message: fmt.Sprintf(".Spec.ReservedAddressPrefixes.Roles entry %q is not a valid role name", role),
+			}
 		}
-	} else {
-		reqLogger.V(1).Info("requeue resource")
 	}
-	return result, err
+	return nil
 }
 
-func validate(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, scheme *runtime.Scheme, namer Namers) (bool, ctrl.Result) {
-	// Do additional validation here
-	validationCondition := metav1.Condition{
-		Type:   brokerv1beta1.ValidConditionType,
-		Status: metav1.ConditionTrue,
-		Reason: brokerv1beta1.ValidConditionSuccessReason,
-	}
+func validateClusterCredentialRotation(customResource *brokerv1beta1.ActiveMQArtemis) *metav1.Condition {
+	return validateCredentialRotationInterval(".Spec.ClusterCredentialRotation", customResource.Spec.ClusterCredentialRotation.Interval)
+}
 
-	condition, retry := validateExtraMounts(customResource, client, scheme)
-	if condition != nil {
-		validationCondition = *condition
+func validateAdminCredentialRotation(customResource *brokerv1beta1.ActiveMQArtemis) *metav1.Condition {
+	if !adminCredentialRotation.generated(customResource) {
+		return &metav1.Condition{
+			Type:    brokerv1beta1.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  brokerv1beta1.ValidConditionInvalidRotationReason,
+			Message: ".Spec.AdminCredentialRotation only rotates generated admin credentials, it can't be set with .Spec.AdminUser, .Spec.AdminPassword or .Spec.CredentialsSource.Admin",
+		}
 	}
+	return validateCredentialRotationInterval(".Spec.AdminCredentialRotation", customResource.Spec.AdminCredentialRotation.Interval)
+}
 
-	if validationCondition.Status == metav1.ConditionTrue {
-		condition := validateBrokerVersion(customResource)
-		if condition != nil {
-			validationCondition = *condition
+func validateCredentialRotationInterval(field string, interval string) *metav1.Condition {
+	if interval == "" {
+		return nil
+	}
+	if duration, err := time.ParseDuration(interval); err != nil || duration < time.Hour {
+		return &metav1.Condition{
+			Type:    brokerv1beta1.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  brokerv1beta1.ValidConditionInvalidRotationReason,
+			Message: fmt.Sprintf("%v.Interval %q must be a duration of at least 1h, for example 720h", field, interval),
 		}
 	}
+	return nil
+}
 
-	if validationCondition.Status == metav1.ConditionTrue && customResource.Spec.DeploymentPlan.PodDisruptionBudget != nil {
-		condition := validatePodDisruption(customResource)
-		if condition != nil {
-			validationCondition = *condition
+func validateRedelivery(customResource *brokerv1beta1.ActiveMQArtemis) *metav1.Condition {
+	if err := customResource.ValidateRedelivery(); err != nil {
+		return &metav1.Condition{
+			Type:    brokerv1beta1.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  brokerv1beta1.ValidConditionInvalidRedeliveryReason,
+			Message: err.Error(),
 		}
 	}
+	return nil
+}
 
-	if validationCondition.Status == metav1.ConditionTrue {
-		condition, retry = validateSSLEnabledSecrets(customResource, client, scheme, namer)
-		if condition != nil {
-			validationCondition = *condition
+// the cluster connection only exists in the broker.xml of a clustered deployment
+func validateClusterConnection(customResource *brokerv1beta1.ActiveMQArtemis) *metav1.Condition {
+	if !isClustered(customResource) {
+		return &metav1.Condition{
+			Type:    brokerv1beta1.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  brokerv1beta1.ValidConditionInvalidClusterConnectionReason,
+			Message: ".Spec.ClusterConnection is set but .Spec.DeploymentPlan.Clustered is false",
 		}
 	}
+	return nil
+}
 
-	validationCondition.ObservedGeneration = customResource.Generation
-	meta.SetStatusCondition(&customResource.Status.Conditions, validationCondition)
-
-	if retry {
-		return validationCondition.Status == metav1.ConditionTrue, ctrl.Result{Requeue: retry, RequeueAfter: common.GetReconcileResyncPeriod()}
-	} else {
-		return validationCondition.Status == metav1.ConditionTrue, ctrl.Result{}
+func validateIPFamilies(customResource *brokerv1beta1.ActiveMQArtemis) *metav1.Condition {
+	families := customResource.Spec.IPFamilies
+	message := ""
+	for i, family := range families {
+		if family != corev1.IPv4Protocol && family != corev1.IPv6Protocol {
+			message = fmt.Sprintf(".Spec.IPFamilies entry %q is not one of IPv4 or IPv6", family)
+		} else if i > 0 && families[0] == family {
+			message = fmt.Sprintf(".Spec.IPFamilies lists %v twice", family)
+		}
+	}
+	if message == "" && len(families) > 2 {
+		message = ".Spec.IPFamilies lists more than two families"
+	}
+	if policy := customResource.Spec.IPFamilyPolicy; message == "" && policy != nil {
+		switch *policy {
+		case corev1.IPFamilyPolicySingleStack:
+			if len(families) > 1 {
+				message = ".Spec.IPFamilies lists two families but .Spec.IPFamilyPolicy is SingleStack"
+			}
+		case corev1.IPFamilyPolicyPreferDualStack, corev1.IPFamilyPolicyRequireDualStack:
+		default:
+			message = fmt.Sprintf(".Spec.IPFamilyPolicy %q is not one of SingleStack, PreferDualStack or RequireDualStack", *policy)
+		}
+	} else if message == "" && len(families) > 1 {
+		message = ".Spec.IPFamilies lists two families, which needs .Spec.IPFamilyPolicy PreferDualStack or RequireDualStack"
 	}
+	if message != "" {
+		return &metav1.Condition{
+			Type:    brokerv1beta1.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  brokerv1beta1.ValidConditionInvalidIPFamiliesReason,
+			Message: message,
+		}
+	}
+	return nil
 }
 
-func validateSSLEnabledSecrets(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, scheme *runtime.Scheme, namer Namers) (*metav1.Condition, bool) {
-
-	var retry = true
-	if customResource.Spec.Console.SSLEnabled {
-
-		secretName := namer.SecretsConsoleNameBuilder.Name()
-
-		secret := corev1.Secret{}
-		found := retrieveResource(secretName, customResource.Namespace, &secret, client, scheme)
-		if !found {
+func validateRetentionPolicies(customResource *brokerv1beta1.ActiveMQArtemis) *metav1.Condition {
+	matches := map[string]bool{}
+	for i, policy := range customResource.Spec.RetentionPolicies {
+		path := fmt.Sprintf(".Spec.RetentionPolicies[%v]", i)
+		message := ""
+		if policy.Match == "" || strings.ContainsAny(policy.Match, "\"= ") {
+			message = fmt.Sprintf("%v.Match %q is not a valid address match", path, policy.Match)
+		} else if matches[policy.Match] {
+			message = fmt.Sprintf("%v.Match %q is already used by another retention policy", path, policy.Match)
+		} else if policy.MaxAge == "" && policy.MaxMessages == nil {
+			message = fmt.Sprintf("%v needs maxAge or maxMessages", path)
+		} else if policy.MaxMessages != nil && *policy.MaxMessages <= 0 {
+			message = fmt.Sprintf("%v.MaxMessages must be greater than 0", path)
+		} else if policy.Action != "" && policy.MaxMessages == nil {
+			message = fmt.Sprintf("%v.Action applies once maxMessages is reached, which is not set", path)
+		} else if policy.Action != "" && policy.Action != brokerv1beta1.RetentionActionDrop && policy.Action != brokerv1beta1.RetentionActionFail && policy.Action != brokerv1beta1.RetentionActionPage {
+			message = fmt.Sprintf("%v.Action %q is not one of drop, fail or page", path, policy.Action)
+		}
+		if message == "" && policy.MaxAge != "" {
+			if maxAge, err := time.ParseDuration(policy.MaxAge); err != nil || maxAge < time.Second {
+				message = fmt.Sprintf("%v.MaxAge %q must be a duration of at least 1s, for example 24h", path, policy.MaxAge)
+			}
+		}
+		if message != "" {
 			return &metav1.Condition{
 				Type:    brokerv1beta1.ValidConditionType,
 				Status:  metav1.ConditionFalse,
-				Reason:  brokerv1beta1.ValidConditionMissingResourcesReason,
-				Message: fmt.Sprintf(".Spec.Console.SSLEnabled is true but required secret %v is not found", secretName),
-			}, retry
-		}
-
-		contextMessage := ".Spec.Console.SSLEnabled is true but required"
-		for _, key := range []string{
-			"keyStorePassword",
-			"trustStorePassword",
-		} {
-			Condition := AssertSecretContainsKey(secret, key, contextMessage)
-			if Condition != nil {
-				return Condition, retry
+				Reason:  brokerv1beta1.ValidConditionInvalidRetentionPolicyReason,
+				Message: message,
 			}
 		}
+		matches[policy.Match] = true
+	}
+	return nil
+}
 
-		Condition := AssertSecretContainsOneOf(secret, []string{
-			"keyStorePath",
-			"broker.ks"}, contextMessage)
-		if Condition != nil {
-			return Condition, retry
+var interfaceNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_.-]{1,15}$`)
+
+func validateBindInterfaces(customResource *brokerv1beta1.ActiveMQArtemis) *metav1.Condition {
+	for _, acceptor := range customResource.Spec.Acceptors {
+		if acceptor.BindInterface != "" && !interfaceNameRegex.MatchString(acceptor.BindInterface) {
+			return &metav1.Condition{
+				Type:    brokerv1beta1.ValidConditionType,
+				Status:  metav1.ConditionFalse,
+				Reason:  brokerv1beta1.ValidConditionInvalidNetworkReason,
+				Message: fmt.Sprintf(".Spec.Acceptors %v has invalid bindInterface %v, expected a network interface name", acceptor.Name, acceptor.BindInterface),
+			}
 		}
+	}
+	return nil
+}
 
-		Condition = AssertSecretContainsOneOf(secret, []string{
-			"trustStorePath",
-			"client.ts"}, contextMessage)
-		if Condition != nil {
-			return Condition, retry
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
 		}
 	}
-	return nil, false
+	return false
 }
 
 func validatePodDisruption(customResource *brokerv1beta1.ActiveMQArtemis) *metav1.Condition {
@@ -322,11 +1759,46 @@ func validateBrokerVersion(customResource *brokerv1beta1.ActiveMQArtemis) *metav
 			Reason:  brokerv1beta1.ValidConditionImagePairRequiredReason,
 			Message: common.ImageDependentPairMessage,
 		}
+	} else if isLockedDown(customResource.Spec.DeploymentPlan.Image) {
+		return validateImageCompatibility(customResource)
 	}
 
 	return nil
 }
 
+// pinned images bypass the version resolution, their tags are checked against the supported versions
+func validateImageCompatibility(customResource *brokerv1beta1.ActiveMQArtemis) *metav1.Condition {
+	var message string
+	imageVersion, imageKnown := version.ImageVersion(customResource.Spec.DeploymentPlan.Image)
+	initImageVersion, initImageKnown := version.ImageVersion(customResource.Spec.DeploymentPlan.InitImage)
+	if imageKnown && !version.IsCompatibleVersion(imageVersion) {
+		message = fmt.Sprintf("broker image version %v is not supported by this operator, supported versions are %v", imageVersion, strings.Join(version.SupportedActiveMQArtemisVersions, ", "))
+	} else if initImageKnown && !version.IsCompatibleVersion(initImageVersion) {
+		message = fmt.Sprintf("init image version %v is not supported by this operator, supported versions are %v", initImageVersion, strings.Join(version.SupportedActiveMQArtemisVersions, ", "))
+	} else if imageKnown && initImageKnown && !imageVersion.Equals(initImageVersion) {
+		message = fmt.Sprintf("broker image version %v does not match init image version %v", imageVersion, initImageVersion)
+	}
+	if message == "" {
+		return nil
+	}
+
+	if common.GetVersionCompatibilityPolicy() == common.VersionCompatibilityPolicyEnforce {
+		return &metav1.Condition{
+			Type:    brokerv1beta1.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  brokerv1beta1.ValidConditionUnsupportedVersionReason,
+			Message: message,
+		}
+	}
+	// warn only, the broker is still deployed
+	return &metav1.Condition{
+		Type:    brokerv1beta1.ValidConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  brokerv1beta1.ValidConditionUnsupportedVersionReason,
+		Message: message,
+	}
+}
+
 func validateExtraMounts(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, scheme *runtime.Scheme) (*metav1.Condition, bool) {
 
 	instanceCounts := map[string]int{}
@@ -552,12 +2024,24 @@ func NewReconcileActiveMQArtemis(c rtclient.Client, s *runtime.Scheme) ActiveMQA
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *ActiveMQArtemisReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.resync = newResyncLane(common.GetReconcileResyncInterval(), r.releaseResync)
+	if err := indexSecretNames(mgr.GetFieldIndexer()); err != nil {
+		return err
+	}
 	builder := ctrl.NewControllerManagedBy(mgr).
-		For(&brokerv1beta1.ActiveMQArtemis{}).
+		For(&brokerv1beta1.ActiveMQArtemis{}, builder.WithPredicates(r.resync.predicates())).
+		WithOptions(rtcontroller.Options{MaxConcurrentReconciles: 1}).
 		Owns(&appsv1.StatefulSet{}).
-		Owns(&corev1.Pod{})
+		Owns(&corev1.Pod{}).
+		Owns(&brokerv1beta1.ActiveMQArtemisScaledown{}, builder.WithPredicates(drainPhasePredicate())).
+		Watches(&source.Kind{Type: &corev1.Pod{}}, handler.EnqueueRequestsFromMapFunc(r.zoneAwareBrokerOfPod), builder.WithPredicates(podScheduledPredicate())).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(r.brokersUsingSecret)).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, handler.EnqueueRequestsFromMapFunc(r.brokersUsingConfigMap)).
+		Watches(&source.Kind{Type: &brokerv1beta1.ActiveMQArtemisAddressSettings{}}, handler.EnqueueRequestsFromMapFunc(r.brokersRenderingCR)).
+		Watches(&source.Kind{Type: &brokerv1beta1.ActiveMQArtemisDivert{}}, handler.EnqueueRequestsFromMapFunc(r.brokersRenderingCR)).
+		Watches(&source.Kind{Type: &brokerv1beta1.ActiveMQArtemisBridge{}}, handler.EnqueueRequestsFromMapFunc(r.brokersRenderingCR))
 	var err error
-	controller, err := builder.Build(r)
+	controller, err := builder.Build(withCorrelation("activemqartemis", r))
 	if err == nil {
 		r.events = make(chan event.GenericEvent)
 		err = controller.Watch(
@@ -565,9 +2049,236 @@ func (r *ActiveMQArtemisReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			&handler.EnqueueRequestForObject{},
 		)
 	}
+	if err == nil {
+		err = mgr.Add(r.resync)
+	}
 	return err
 }
 
+// the broker pods are owned by the StatefulSet, a pod that gets a node has the broker of a zone
+// aware CR label it with its zone
+func podScheduledPredicate() predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			pod, isPod := e.Object.(*corev1.Pod)
+			return isPod && pod.Spec.NodeName != ""
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			old, isOldPod := e.ObjectOld.(*corev1.Pod)
+			new, isNewPod := e.ObjectNew.(*corev1.Pod)
+			return isOldPod && isNewPod && old.Spec.NodeName != new.Spec.NodeName
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+func (r *ActiveMQArtemisReconciler) zoneAwareBrokerOfPod(pod rtclient.Object) []ctrl.Request {
+	requests := []ctrl.Request{}
+	name, found := pod.GetLabels()[selectors.LabelResourceKey]
+	if !found {
+		return requests
+	}
+	if _, labeled := pod.GetLabels()[ZoneLabel]; labeled {
+		return requests
+	}
+	broker := &brokerv1beta1.ActiveMQArtemis{}
+	if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: pod.GetNamespace()}, broker); err != nil {
+		hlog.V(1).Info("unable to get the broker of pod", "pod", pod.GetName(), "error", err)
+		return requests
+	}
+	if isZoneAware(broker) {
+		requests = append(requests, ctrl.Request{NamespacedName: types.NamespacedName{Name: broker.Name, Namespace: broker.Namespace}})
+	}
+	return requests
+}
+
+// brokerSecretNames are the secrets a broker reads whose content the operator renders into the pod
+// template, the broker properties or the credentials secret. The ssl secrets only count when their
+// renewal rolls the brokers
+func brokerSecretNames(broker *brokerv1beta1.ActiveMQArtemis) []string {
+	names := []string{}
+	if getTLSRenewal(broker) != TLSRenewalNone {
+		names = append(names, tlsSecretNames(broker, *MakeNamers(broker), true)...)
+	}
+	// the synced copy of an external credentials source changes when the source rotates the credentials
+	if broker.Spec.CredentialsSource != nil && broker.Spec.CredentialsSource.SyncedSecret != "" {
+		names = append(names, broker.Spec.CredentialsSource.SyncedSecret)
+	}
+	names = append(names, federationSecretNames(broker)...)
+	names = append(names, amqpConnectionSecretNames(broker)...)
+	names = append(names, clusterMeshSecretNames(broker)...)
+	names = append(names, routeTLSSecretNames(broker)...)
+	if isJdbcPersistence(broker) {
+		names = append(names, broker.Spec.DeploymentPlan.Persistence.JDBC.ConnectionUrlSecret.Name)
+	}
+	return names
+}
+
+// the secrets a security cr reads, the keys are checksummed into the init container env of the
+// brokers it applies to
+func securitySecretNames(securityCR *brokerv1beta1.ActiveMQArtemisSecurity) []string {
+	names := []string{}
+	for _, selector := range securitySecretReferences(securityCR) {
+		names = append(names, selector.Name)
+	}
+	return names
+}
+
+func bridgeSecretNames(bridge *brokerv1beta1.ActiveMQArtemisBridge) []string {
+	if bridge.Spec.Target.CredentialsSecret == "" {
+		return nil
+	}
+	return []string{bridge.Spec.Target.CredentialsSecret}
+}
+
+// the field of the cache index on the names of the secrets a cr reads
+const secretNamesField = "secretNames"
+
+// indexSecretNames has the cache index the brokers, security crs and bridges by the secrets they
+// read, a changed secret is then mapped without going through every cr of its namespace. A security
+// cr is indexed itself rather than through the brokers it applies to, so that a change of the
+// secrets it reads is seen as soon as the cache has it
+func indexSecretNames(indexer rtclient.FieldIndexer) error {
+	if err := indexer.IndexField(context.TODO(), &brokerv1beta1.ActiveMQArtemis{}, secretNamesField, func(obj rtclient.Object) []string {
+		return brokerSecretNames(obj.(*brokerv1beta1.ActiveMQArtemis))
+	}); err != nil {
+		return err
+	}
+	if err := indexer.IndexField(context.TODO(), &brokerv1beta1.ActiveMQArtemisSecurity{}, secretNamesField, func(obj rtclient.Object) []string {
+		return securitySecretNames(obj.(*brokerv1beta1.ActiveMQArtemisSecurity))
+	}); err != nil {
+		return err
+	}
+	return indexer.IndexField(context.TODO(), &brokerv1beta1.ActiveMQArtemisBridge{}, secretNamesField, func(obj rtclient.Object) []string {
+		return bridgeSecretNames(obj.(*brokerv1beta1.ActiveMQArtemisBridge))
+	})
+}
+
+// the certificates of the routes are copied into them, a renewed certificate changes the route
+func routeTLSSecretNames(broker *brokerv1beta1.ActiveMQArtemis) []string {
+	names := []string{}
+	routeTLSs := []*brokerv1beta1.RouteTLSType{broker.Spec.Console.RouteTLS}
+	for _, acceptor := range broker.Spec.Acceptors {
+		routeTLSs = append(routeTLSs, acceptor.RouteTLS)
+	}
+	for _, routeTLS := range routeTLSs {
+		if routeTLS == nil {
+			continue
+		}
+		if routeTLS.CertificateSecret != "" {
+			names = append(names, routeTLS.CertificateSecret)
+		}
+		if routeTLS.DestinationCASecret != "" {
+			names = append(names, routeTLS.DestinationCASecret)
+		}
+	}
+	return names
+}
+
+// brokersUsingSecret maps a changed secret to the brokers of its namespace that read it, directly,
+// through the security cr that applies to them or through a bridge they render
+func (r *ActiveMQArtemisReconciler) brokersUsingSecret(secret rtclient.Object) []ctrl.Request {
+	requests := []ctrl.Request{}
+	name := secret.GetName()
+	usingSecret := []rtclient.ListOption{rtclient.InNamespace(secret.GetNamespace()), rtclient.MatchingFields{secretNamesField: name}}
+	brokers := map[types.NamespacedName]bool{}
+
+	brokerList := &brokerv1beta1.ActiveMQArtemisList{}
+	if err := r.Client.List(context.TODO(), brokerList, usingSecret...); err != nil {
+		hlog.V(1).Info("unable to list brokers for secret", "secret", name, "error", err)
+	}
+	for _, broker := range brokerList.Items {
+		brokers[types.NamespacedName{Name: broker.Name, Namespace: broker.Namespace}] = true
+	}
+
+	securityList := &brokerv1beta1.ActiveMQArtemisSecurityList{}
+	if err := r.Client.List(context.TODO(), securityList, usingSecret...); err != nil {
+		hlog.V(1).Info("unable to list security crs for secret", "secret", name, "error", err)
+	}
+	securityCRs := map[string]bool{}
+	for _, securityCR := range securityList.Items {
+		securityCRs[securityCR.Name] = true
+	}
+	if len(securityCRs) > 0 {
+		all := &brokerv1beta1.ActiveMQArtemisList{}
+		if err := r.Client.List(context.TODO(), all, rtclient.InNamespace(secret.GetNamespace())); err != nil {
+			hlog.V(1).Info("unable to list brokers for secret", "secret", name, "error", err)
+		}
+		for i := range all.Items {
+			if securityCR := getApplicableSecurityCR(&all.Items[i]); securityCR != nil && securityCRs[securityCR.Name] {
+				brokers[types.NamespacedName{Name: all.Items[i].Name, Namespace: all.Items[i].Namespace}] = true
+			}
+		}
+	}
+
+	bridgeList := &brokerv1beta1.ActiveMQArtemisBridgeList{}
+	if err := r.Client.List(context.TODO(), bridgeList, usingSecret...); err != nil {
+		hlog.V(1).Info("unable to list bridges for secret", "secret", name, "error", err)
+	}
+	for i := range bridgeList.Items {
+		for _, request := range r.brokersRenderingCR(&bridgeList.Items[i]) {
+			brokers[request.NamespacedName] = true
+		}
+	}
+
+	for broker := range brokers {
+		requests = append(requests, ctrl.Request{NamespacedName: broker})
+	}
+	sort.Slice(requests, func(i, j int) bool { return requests[i].Name < requests[j].Name })
+	return requests
+}
+
+func (r *ActiveMQArtemisReconciler) brokersUsingConfigMap(configMap rtclient.Object) []ctrl.Request {
+	requests := []ctrl.Request{}
+
+	brokers := &brokerv1beta1.ActiveMQArtemisList{}
+	if err := r.Client.List(context.TODO(), brokers, rtclient.InNamespace(configMap.GetNamespace())); err != nil {
+		hlog.V(1).Info("unable to list brokers for configmap", "configmap", configMap.GetName(), "error", err)
+		return requests
+	}
+	for _, broker := range brokers.Items {
+		if (broker.Spec.Logging != nil && broker.Spec.Logging.ConfigMap != nil && broker.Spec.Logging.ConfigMap.Name == configMap.GetName()) ||
+			(broker.Spec.BrokerXmlConfigMap != nil && broker.Spec.BrokerXmlConfigMap.Name == configMap.GetName()) {
+			requests = append(requests, ctrl.Request{NamespacedName: types.NamespacedName{Name: broker.Name, Namespace: broker.Namespace}})
+		}
+	}
+	return requests
+}
+
+// brokersRenderingCR are the brokers whose broker properties render the address settings, the
+// divert or the bridge of a CR
+func (r *ActiveMQArtemisReconciler) brokersRenderingCR(obj rtclient.Object) []ctrl.Request {
+	requests := []ctrl.Request{}
+	var applyToCrNames []string
+	switch cr := obj.(type) {
+	case *brokerv1beta1.ActiveMQArtemisAddressSettings:
+		applyToCrNames = cr.Spec.ApplyToCrNames
+	case *brokerv1beta1.ActiveMQArtemisDivert:
+		applyToCrNames = cr.Spec.ApplyToCrNames
+	case *brokerv1beta1.ActiveMQArtemisBridge:
+		applyToCrNames = cr.Spec.ApplyToCrNames
+	default:
+		return requests
+	}
+
+	brokers := &brokerv1beta1.ActiveMQArtemisList{}
+	if err := r.Client.List(context.TODO(), brokers, rtclient.InNamespace(obj.GetNamespace())); err != nil {
+		hlog.V(1).Info("unable to list brokers for cr", "cr", obj.GetName(), "error", err)
+		return requests
+	}
+	for _, broker := range brokers.Items {
+		if appliesToBroker(applyToCrNames, broker.Name) {
+			requests = append(requests, ctrl.Request{NamespacedName: types.NamespacedName{Name: broker.Name, Namespace: broker.Namespace}})
+		}
+	}
+	return requests
+}
+
 func UpdateCRStatus(desired *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, namespacedName types.NamespacedName) error {
 
 	common.SetReadyCondition(&desired.Status.Conditions)
@@ -581,11 +2292,19 @@ func UpdateCRStatus(desired *brokerv1beta1.ActiveMQArtemis, client rtclient.Clie
 	}
 
 	if current.Status.DeploymentPlanSize != desired.Status.DeploymentPlanSize ||
+		current.Status.Replicas != desired.Status.Replicas ||
 		current.Status.ScaleLabelSelector != desired.Status.ScaleLabelSelector ||
 		!reflect.DeepEqual(current.Status.Version, desired.Status.Version) ||
 		len(desired.Status.ExternalConfigs) != len(current.Status.ExternalConfigs) ||
 		externalConfigsModified(desired, current) ||
 		!reflect.DeepEqual(current.Status.PodStatus, desired.Status.PodStatus) ||
+		!reflect.DeepEqual(current.Status.ExternalEndpoints, desired.Status.ExternalEndpoints) ||
+		!reflect.DeepEqual(current.Status.SecurityCanary, desired.Status.SecurityCanary) ||
+		!reflect.DeepEqual(current.Status.Security, desired.Status.Security) ||
+		!reflect.DeepEqual(current.Status.MessageMigration, desired.Status.MessageMigration) ||
+		!reflect.DeepEqual(current.Status.HA, desired.Status.HA) ||
+		!reflect.DeepEqual(current.Status.Zones, desired.Status.Zones) ||
+		!reflect.DeepEqual(current.Status.AMQPConnections, desired.Status.AMQPConnections) ||
 		len(current.Status.Conditions) != len(desired.Status.Conditions) ||
 		conditionsModified(desired, current) {
 
@@ -0,0 +1,289 @@
+package controllers
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/resources/environments"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	credentialsSourceVolumeName     = "credentials-source"
+	credentialsSourceCSIMountPath   = "/amq/credentials"
+	credentialsSourceVaultPath      = "/vault/secrets"
+	credentialsSourceCSIDriver      = "secrets-store.csi.k8s.io"
+	credentialsSourcePlaceholder    = "credentials-source."
+	credentialsSourceConsoleName    = "console"
+	credentialsSourceRollingRestart = "RollingRestart"
+	credentialsSourceNoRotation     = "None"
+
+	credentialsSourceCheckSumEnvVarName = "CREDENTIALS_SOURCE_CHECKSUM"
+
+	vaultAnnotationPrefix = "vault.hashicorp.com/"
+)
+
+// the env vars launch.sh creates the instance with, by item of the source
+var credentialsSourceEnvVars = []struct{ item, envVar string }{
+	{"adminUser", "AMQ_USER"},
+	{"adminPassword", "AMQ_PASSWORD"},
+	{"clusterUser", "AMQ_CLUSTER_USER"},
+	{"clusterPassword", "AMQ_CLUSTER_PASSWORD"},
+}
+
+func credentialsSourceSelectsItem(source *brokerv1beta1.CredentialsSourceType, item string) bool {
+	if strings.HasPrefix(item, "admin") {
+		return source.Admin
+	}
+	return source.Cluster
+}
+
+// credentialsSourceItems are the files the source writes into the pods, in a stable order
+func credentialsSourceItems(customResource *brokerv1beta1.ActiveMQArtemis) []string {
+	source := customResource.Spec.CredentialsSource
+	if source == nil {
+		return nil
+	}
+	items := []string{}
+	for _, env := range credentialsSourceEnvVars {
+		if credentialsSourceSelectsItem(source, env.item) {
+			items = append(items, env.item)
+		}
+	}
+	for _, name := range source.KeyStorePasswords {
+		items = append(items, name+"-keyStorePassword", name+"-trustStorePassword")
+	}
+	return items
+}
+
+func credentialsSourceDir(customResource *brokerv1beta1.ActiveMQArtemis) string {
+	if customResource.Spec.CredentialsSource.Vault != nil {
+		return credentialsSourceVaultPath
+	}
+	return credentialsSourceCSIMountPath
+}
+
+// credentialsSourceSkipsEnvVar tells if an env var of the credentials secret comes from the source instead
+func credentialsSourceSkipsEnvVar(customResource *brokerv1beta1.ActiveMQArtemis, envVar string) bool {
+	source := customResource.Spec.CredentialsSource
+	if source == nil {
+		return false
+	}
+	for _, env := range credentialsSourceEnvVars {
+		if env.envVar == envVar {
+			return credentialsSourceSelectsItem(source, env.item)
+		}
+	}
+	return false
+}
+
+// credentialsSourceKeyStorePasswords returns the placeholders the init container replaces with the
+// keystore and truststore passwords of an acceptor, connector or the console
+func credentialsSourceKeyStorePasswords(customResource *brokerv1beta1.ActiveMQArtemis, name string) (string, string, bool) {
+	source := customResource.Spec.CredentialsSource
+	if source == nil || !containsString(source.KeyStorePasswords, name) {
+		return "", "", false
+	}
+	return credentialsSourcePlaceholder + name + "-keyStorePassword", credentialsSourcePlaceholder + name + "-trustStorePassword", true
+}
+
+// credentialsSourceExportCmd has launch.sh create the instance with the users of the source, cat
+// fails the init container when an item is missing
+func credentialsSourceExportCmd(customResource *brokerv1beta1.ActiveMQArtemis) string {
+	source := customResource.Spec.CredentialsSource
+	if source == nil || (!source.Admin && !source.Cluster) {
+		return ""
+	}
+	dir := credentialsSourceDir(customResource)
+	files := []string{}
+	exports := []string{}
+	for _, env := range credentialsSourceEnvVars {
+		if credentialsSourceSelectsItem(source, env.item) {
+			files = append(files, dir+"/"+env.item)
+			exports = append(exports, env.envVar+"=\"$(cat "+dir+"/"+env.item+")\"")
+		}
+	}
+	return "cat " + strings.Join(files, " ") + " > /dev/null && export " + strings.Join(exports, " ")
+}
+
+// replaces the keystore password placeholders of the broker and console configuration with the items,
+// usage: credentials-source.py <source dir> <item>...
+var credentialsSourceScript = `import os, sys
+from xml.sax.saxutils import escape
+
+source, items = sys.argv[1], sys.argv[2:]
+for path in ['` + brokerConfigRoot + `/etc/broker.xml', '` + brokerConfigRoot + `/etc/bootstrap.xml']:
+    if not os.path.exists(path):
+        continue
+    with open(path) as f:
+        content = f.read()
+    for item in items:
+        with open(os.path.join(source, item)) as f:
+            value = f.read().rstrip('\n')
+        content = content.replace('` + credentialsSourcePlaceholder + `' + item, escape(value, {chr(34): '&quot;'}))
+    with open(path, 'w') as f:
+        f.write(content)
+`
+
+func credentialsSourceKeyStoreCmd(customResource *brokerv1beta1.ActiveMQArtemis) string {
+	source := customResource.Spec.CredentialsSource
+	if source == nil || len(source.KeyStorePasswords) == 0 {
+		return ""
+	}
+	items := []string{}
+	for _, item := range credentialsSourceItems(customResource) {
+		if strings.HasSuffix(item, "StorePassword") {
+			items = append(items, item)
+		}
+	}
+	return "python3 " + initScriptPath(credentialsSourceScriptName) + " " +
+		credentialsSourceDir(customResource) + " " + strings.Join(items, " ")
+}
+
+// addVaultAnnotations has the Vault agent injector write every item before the init container runs,
+// the template reads the key of a kv version 2 secret and of a version 1 secret
+func addVaultAnnotations(customResource *brokerv1beta1.ActiveMQArtemis, annotations map[string]string) {
+	vault := customResource.Spec.CredentialsSource.Vault
+	annotations[vaultAnnotationPrefix+"agent-inject"] = "true"
+	annotations[vaultAnnotationPrefix+"agent-init-first"] = "true"
+	// the broker reads the items when it starts, a rotation restarts it
+	annotations[vaultAnnotationPrefix+"agent-pre-populate-only"] = "true"
+	annotations[vaultAnnotationPrefix+"role"] = vault.Role
+	for _, item := range credentialsSourceItems(customResource) {
+		annotations[vaultAnnotationPrefix+"agent-inject-secret-"+item] = vault.Path
+		annotations[vaultAnnotationPrefix+"agent-inject-template-"+item] = fmt.Sprintf(
+			`{{- with secret %q -}}{{- if .Data.data -}}{{ index .Data.data %q }}{{- else -}}{{ index .Data %q }}{{- end -}}{{- end -}}`,
+			vault.Path, item, item)
+	}
+}
+
+// the csi driver mounts the objects of the secret provider class for the init container
+func credentialsSourceVolume(customResource *brokerv1beta1.ActiveMQArtemis) (*corev1.Volume, *corev1.VolumeMount) {
+	source := customResource.Spec.CredentialsSource
+	if source == nil || source.CSI == nil {
+		return nil, nil
+	}
+	readOnly := true
+	return &corev1.Volume{
+		Name: credentialsSourceVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			CSI: &corev1.CSIVolumeSource{
+				Driver:           credentialsSourceCSIDriver,
+				ReadOnly:         &readOnly,
+				VolumeAttributes: map[string]string{"secretProviderClass": source.CSI.SecretProviderClass},
+			},
+		},
+	}, &corev1.VolumeMount{
+		Name:      credentialsSourceVolumeName,
+		MountPath: credentialsSourceCSIMountPath,
+		ReadOnly:  true,
+	}
+}
+
+// only the synced secret tells the operator about a rotation, neither the vault agent nor the csi
+// driver write one on their own, so without it the default is None
+func getCredentialsSourceRotation(customResource *brokerv1beta1.ActiveMQArtemis) string {
+	source := customResource.Spec.CredentialsSource
+	if source.Rotation == "" {
+		if source.SyncedSecret == "" {
+			return credentialsSourceNoRotation
+		}
+		return credentialsSourceRollingRestart
+	}
+	return source.Rotation
+}
+
+// the items only reach the broker when it starts, a checksum of their copy in the synced secret has
+// the statefulset roll the brokers one at a time when the source rotates them
+func trackCredentialsSourceCheckSumInEnvVar(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, containers []corev1.Container) {
+	source := customResource.Spec.CredentialsSource
+	if source == nil || getCredentialsSourceRotation(customResource) == credentialsSourceNoRotation {
+		environments.Delete(containers, credentialsSourceCheckSumEnvVarName)
+		return
+	}
+	secret := &corev1.Secret{}
+	if err := client.Get(context.TODO(), types.NamespacedName{Name: source.SyncedSecret, Namespace: customResource.Namespace}, secret); err != nil {
+		// the csi driver only creates it once a pod mounts the volume, the vault secrets operator once it synced the path
+		ctrl.Log.V(1).Info("synced secret of the credentials source not found", "secret", source.SyncedSecret, "error", err.Error())
+		environments.Delete(containers, credentialsSourceCheckSumEnvVarName)
+		return
+	}
+	entries := []string{}
+	for _, item := range credentialsSourceItems(customResource) {
+		entries = append(entries, item, string(secret.Data[item]))
+	}
+	checkSumEnvVar := &corev1.EnvVar{
+		Name:  credentialsSourceCheckSumEnvVarName,
+		Value: hex.EncodeToString(alder32Of(entries)),
+	}
+	if environments.Retrieve(containers, credentialsSourceCheckSumEnvVarName) == nil {
+		environments.Create(containers, checkSumEnvVar)
+	} else {
+		environments.Update(containers, checkSumEnvVar)
+	}
+}
+
+func validateCredentialsSource(customResource *brokerv1beta1.ActiveMQArtemis) *metav1.Condition {
+	source := customResource.Spec.CredentialsSource
+	message := ""
+	switch {
+	case (source.Vault == nil) == (source.CSI == nil):
+		message = ".Spec.CredentialsSource needs exactly one of vault or csi"
+	case source.Vault != nil && (source.Vault.Role == "" || source.Vault.Path == ""):
+		message = ".Spec.CredentialsSource.Vault needs a role and a path"
+	case source.CSI != nil && source.CSI.SecretProviderClass == "":
+		message = ".Spec.CredentialsSource.CSI needs a secretProviderClass"
+	case source.Rotation == credentialsSourceRollingRestart && source.SyncedSecret == "":
+		message = ".Spec.CredentialsSource.Rotation RollingRestart needs .Spec.CredentialsSource.SyncedSecret, the operator can't see the items of the source change without a copy of them"
+	case len(credentialsSourceItems(customResource)) == 0:
+		message = ".Spec.CredentialsSource takes no credentials, set admin, cluster or keyStorePasswords"
+	case source.Admin && (customResource.Spec.Console.Jolokia == nil || customResource.Spec.Console.Jolokia.CredentialsSecret == ""):
+		message = ".Spec.CredentialsSource.Admin needs .Spec.Console.Jolokia.CredentialsSecret, the operator can't read the admin credentials from the source"
+	case source.Cluster && customResource.Spec.ClusterCredentialRotation != nil:
+		message = ".Spec.CredentialsSource.Cluster can't be set with .Spec.ClusterCredentialRotation, the source rotates the cluster credentials"
+	case source.Cluster && customResource.Spec.DeploymentPlan.MessageMigration != nil && *customResource.Spec.DeploymentPlan.MessageMigration:
+		message = ".Spec.CredentialsSource.Cluster can't be set with .Spec.DeploymentPlan.MessageMigration, the drainer connects with the generated cluster credentials"
+	}
+	for _, name := range source.KeyStorePasswords {
+		if message != "" {
+			break
+		}
+		if !credentialsSourceKeyStoreNameValid(customResource, name) {
+			message = fmt.Sprintf(".Spec.CredentialsSource.KeyStorePasswords %q must be console or an acceptor or connector with sslEnabled and without a certificateIssuer", name)
+		}
+	}
+	if message == "" {
+		return nil
+	}
+	return &metav1.Condition{
+		Type:    brokerv1beta1.ValidConditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  brokerv1beta1.ValidConditionInvalidCredentialsSourceReason,
+		Message: message,
+	}
+}
+
+// cert-manager protects the keystores it issues with a password the operator generates
+func credentialsSourceKeyStoreNameValid(customResource *brokerv1beta1.ActiveMQArtemis, name string) bool {
+	if name == credentialsSourceConsoleName {
+		return customResource.Spec.Console.SSLEnabled
+	}
+	for _, acceptor := range customResource.Spec.Acceptors {
+		if acceptor.Name == name {
+			return acceptor.SSLEnabled && getAcceptorCertificateIssuer(customResource, acceptor) == nil
+		}
+	}
+	for _, connector := range customResource.Spec.Connectors {
+		if connector.Name == name {
+			return connector.SSLEnabled && connector.CertificateIssuer == nil
+		}
+	}
+	return false
+}
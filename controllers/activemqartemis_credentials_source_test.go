@@ -0,0 +1,125 @@
+package controllers
+
+import (
+	"strings"
+	"testing"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/resources/environments"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCredentialsSource(t *testing.T) {
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: "test"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			Console: brokerv1beta1.ConsoleType{
+				Jolokia: &brokerv1beta1.JolokiaType{CredentialsSecret: "operator-mgmt"},
+			},
+			Acceptors: []brokerv1beta1.AcceptorType{{Name: "amqps", Port: 5671, SSLEnabled: true}},
+			CredentialsSource: &brokerv1beta1.CredentialsSourceType{
+				Vault:             &brokerv1beta1.VaultCredentialsSourceType{Role: "broker", Path: "secret/data/broker"},
+				Admin:             true,
+				Cluster:           true,
+				KeyStorePasswords: []string{"amqps"},
+				SyncedSecret:      "broker-synced",
+			},
+		},
+	}
+	namer := MakeNamers(cr)
+	assert.Nil(t, validateCredentialsSource(cr))
+
+	// the vault agent writes every item before the init container runs
+	annotations := podAnnotations(cr)
+	assert.Equal(t, "broker", annotations["vault.hashicorp.com/role"])
+	assert.Equal(t, "true", annotations["vault.hashicorp.com/agent-pre-populate-only"])
+	assert.Equal(t, "secret/data/broker", annotations["vault.hashicorp.com/agent-inject-secret-clusterPassword"])
+	assert.Contains(t, annotations["vault.hashicorp.com/agent-inject-template-amqps-trustStorePassword"], `index .Data.data "amqps-trustStorePassword"`)
+
+	// the users are exported before launch.sh creates the instance, the keystore passwords replaced after
+	reconciler := &ActiveMQArtemisReconcilerImpl{}
+	newSpec, err := reconciler.NewPodTemplateSpecForCR(cr, *namer, &v1.PodTemplateSpec{}, fake.NewClientBuilder().Build())
+	assert.NoError(t, err)
+	initArgs := newSpec.Spec.InitContainers[0].Args[1]
+	exportAt := strings.Index(initArgs, `export AMQ_USER="$(cat /vault/secrets/adminUser)"`)
+	launchAt := strings.Index(initArgs, "/opt/amq/bin/launch.sh")
+	replaceAt := strings.Index(initArgs, "python3 /amq/init/scripts/credentials-source.py /vault/secrets amqps-keyStorePassword amqps-trustStorePassword")
+	assert.True(t, exportAt >= 0 && exportAt < launchAt && launchAt < replaceAt)
+	assert.Contains(t, initArgs, `AMQ_CLUSTER_PASSWORD="$(cat /vault/secrets/clusterPassword)"`)
+
+	acceptors := generateAcceptorsString(cr, *namer, fake.NewClientBuilder().Build(), nil)
+	assert.Contains(t, acceptors, "keyStorePassword=credentials-source.amqps-keyStorePassword;")
+	assert.Contains(t, acceptors, "trustStorePassword=credentials-source.amqps-trustStorePassword")
+	assert.True(t, credentialsSourceSkipsEnvVar(cr, "AMQ_CLUSTER_USER"))
+	assert.False(t, credentialsSourceSkipsEnvVar(cr, "AMQ_ROLE"))
+
+	// a csi volume is mounted for the init container only
+	cr.Spec.CredentialsSource.Vault = nil
+	cr.Spec.CredentialsSource.CSI = &brokerv1beta1.CSICredentialsSourceType{SecretProviderClass: "broker-credentials"}
+	assert.Nil(t, validateCredentialsSource(cr))
+	assert.NotContains(t, podAnnotations(cr), "vault.hashicorp.com/role")
+	newSpec, err = reconciler.NewPodTemplateSpecForCR(cr, *namer, &v1.PodTemplateSpec{}, fake.NewClientBuilder().Build())
+	assert.NoError(t, err)
+	var volume *v1.Volume
+	for i := range newSpec.Spec.Volumes {
+		if newSpec.Spec.Volumes[i].Name == "credentials-source" {
+			volume = &newSpec.Spec.Volumes[i]
+		}
+	}
+	assert.NotNil(t, volume)
+	assert.Equal(t, "broker-credentials", volume.CSI.VolumeAttributes["secretProviderClass"])
+	mounted := false
+	for _, mount := range newSpec.Spec.InitContainers[0].VolumeMounts {
+		mounted = mounted || (mount.Name == "credentials-source" && mount.MountPath == "/amq/credentials")
+	}
+	assert.True(t, mounted)
+	assert.Contains(t, newSpec.Spec.InitContainers[0].Args[1], `export AMQ_USER="$(cat /amq/credentials/adminUser)"`)
+
+	// a rotation in the synced secret rolls the brokers
+	synced := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "broker-synced", Namespace: "test"},
+		Data:       map[string][]byte{"clusterPassword": []byte("first")},
+	}
+	containers := []v1.Container{{Name: "broker"}}
+	trackCredentialsSourceCheckSumInEnvVar(cr, fake.NewClientBuilder().WithObjects(synced).Build(), containers)
+	first := environments.Retrieve(containers, "CREDENTIALS_SOURCE_CHECKSUM")
+	assert.NotNil(t, first)
+	firstValue := first.Value
+	synced.Data["clusterPassword"] = []byte("second")
+	trackCredentialsSourceCheckSumInEnvVar(cr, fake.NewClientBuilder().WithObjects(synced).Build(), containers)
+	assert.NotEqual(t, firstValue, environments.Retrieve(containers, "CREDENTIALS_SOURCE_CHECKSUM").Value)
+	cr.Spec.CredentialsSource.Rotation = "None"
+	trackCredentialsSourceCheckSumInEnvVar(cr, fake.NewClientBuilder().WithObjects(synced).Build(), containers)
+	assert.Nil(t, environments.Retrieve(containers, "CREDENTIALS_SOURCE_CHECKSUM"))
+
+	// the vault secrets operator keeps the copy of a vault source, without one nothing tells of a rotation
+	cr.Spec.CredentialsSource.CSI = nil
+	cr.Spec.CredentialsSource.Vault = &brokerv1beta1.VaultCredentialsSourceType{Role: "broker", Path: "secret/data/broker"}
+	cr.Spec.CredentialsSource.Rotation = ""
+	trackCredentialsSourceCheckSumInEnvVar(cr, fake.NewClientBuilder().WithObjects(synced).Build(), containers)
+	assert.NotNil(t, environments.Retrieve(containers, "CREDENTIALS_SOURCE_CHECKSUM"))
+	assert.True(t, containsString(brokerSecretNames(cr), "broker-synced"))
+	cr.Spec.CredentialsSource.SyncedSecret = ""
+	assert.Equal(t, "None", getCredentialsSourceRotation(cr))
+	assert.Nil(t, validateCredentialsSource(cr))
+	cr.Spec.CredentialsSource.Rotation = "RollingRestart"
+	assert.NotNil(t, validateCredentialsSource(cr))
+	cr.Spec.CredentialsSource.Rotation = ""
+	cr.Spec.CredentialsSource.Vault = nil
+	cr.Spec.CredentialsSource.CSI = &brokerv1beta1.CSICredentialsSourceType{SecretProviderClass: "broker-credentials"}
+
+	cr.Spec.CredentialsSource.KeyStorePasswords = []string{"console"}
+	assert.NotNil(t, validateCredentialsSource(cr))
+	cr.Spec.CredentialsSource.KeyStorePasswords = nil
+	cr.Spec.Console.Jolokia = nil
+	assert.NotNil(t, validateCredentialsSource(cr))
+	cr.Spec.CredentialsSource.Admin = false
+	cr.Spec.CredentialsSource.Vault = &brokerv1beta1.VaultCredentialsSourceType{Role: "broker", Path: "secret/data/broker"}
+	condition := validateCredentialsSource(cr)
+	assert.NotNil(t, condition)
+	assert.Equal(t, brokerv1beta1.ValidConditionInvalidCredentialsSourceReason, condition.Reason)
+}
@@ -0,0 +1,289 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const defaultConnectorPort = "61616"
+
+func federationConnectorName(federation brokerv1beta1.FederationType, upstream brokerv1beta1.FederationUpstreamType, index int) string {
+	return fmt.Sprintf("federation-%s-%s-%d", federation.Name, upstream.Name, index)
+}
+
+// federationProperties renders the federations into broker properties, the brokers read the
+// properties secret of every pod so each pod federates from the upstreams on its own
+func federationProperties(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client) []string {
+	props := []string{}
+	for _, federation := range customResource.Spec.Federations {
+		prefix := "federationConfigurations." + federation.Name + "."
+
+		for _, upstream := range federation.Upstreams {
+			connectors := []string{}
+			for i, connectorUrl := range upstream.ConnectorUrls {
+				connector, err := connectorUrlProperties(federationConnectorName(federation, upstream, i), connectorUrl)
+				if err != nil {
+					clog.Error(err, "unable to parse federation connector url", "federation", federation.Name, "upstream", upstream.Name)
+					continue
+				}
+				props = append(props, connector...)
+				connectors = append(connectors, federationConnectorName(federation, upstream, i))
+			}
+
+			upstreamPrefix := prefix + "upstreamConfigurations." + upstream.Name + "."
+			props = append(props, upstreamPrefix+"connectionConfiguration.staticConnectors="+strings.Join(connectors, ","))
+			if upstream.CredentialsSecret != "" {
+				user, password, err := getConnectionCredentials(customResource, upstream.CredentialsSecret, client)
+				if err != nil {
+					clog.Error(err, "unable to resolve federation upstream credentials", "secret", upstream.CredentialsSecret)
+				} else {
+					props = append(props,
+						upstreamPrefix+"connectionConfiguration.username="+user,
+						upstreamPrefix+"connectionConfiguration.password="+password)
+				}
+			}
+			if upstream.RetryInterval != nil {
+				props = append(props, fmt.Sprintf("%sconnectionConfiguration.retryInterval=%d", upstreamPrefix, *upstream.RetryInterval))
+			}
+			if upstream.ReconnectAttempts != nil {
+				props = append(props, fmt.Sprintf("%sconnectionConfiguration.reconnectAttempts=%d", upstreamPrefix, *upstream.ReconnectAttempts))
+			}
+			if upstream.CircuitBreakerTimeout != nil {
+				props = append(props, fmt.Sprintf("%sconnectionConfiguration.circuitBreakerTimeout=%d", upstreamPrefix, *upstream.CircuitBreakerTimeout))
+			}
+			props = append(props, upstreamPrefix+"policyRefs="+strings.Join(federationUpstreamPolicies(federation, upstream), ","))
+		}
+
+		for _, policy := range federation.AddressPolicies {
+			policyPrefix := prefix + "addressPolicies." + policy.Name + "."
+			for i, match := range policy.Include {
+				props = append(props, fmt.Sprintf("%sincludes.include-%d.addressMatch=%s", policyPrefix, i, match))
+			}
+			for i, match := range policy.Exclude {
+				props = append(props, fmt.Sprintf("%sexcludes.exclude-%d.addressMatch=%s", policyPrefix, i, match))
+			}
+			if policy.MaxHops != nil {
+				props = append(props, fmt.Sprintf("%smaxHops=%d", policyPrefix, *policy.MaxHops))
+			}
+			if policy.AutoDelete != nil {
+				props = append(props, fmt.Sprintf("%sautoDelete=%t", policyPrefix, *policy.AutoDelete))
+			}
+			if policy.EnableDivertBindings != nil {
+				props = append(props, fmt.Sprintf("%senableDivertBindings=%t", policyPrefix, *policy.EnableDivertBindings))
+			}
+		}
+
+		for _, policy := range federation.QueuePolicies {
+			policyPrefix := prefix + "queuePolicies." + policy.Name + "."
+			props = append(props, federationQueueMatchProperties(policyPrefix+"includes.include-", policy.Include)...)
+			props = append(props, federationQueueMatchProperties(policyPrefix+"excludes.exclude-", policy.Exclude)...)
+			if policy.IncludeFederated != nil {
+				props = append(props, fmt.Sprintf("%sincludeFederated=%t", policyPrefix, *policy.IncludeFederated))
+			}
+			if policy.PriorityAdjustment != nil {
+				props = append(props, fmt.Sprintf("%spriorityAdjustment=%d", policyPrefix, *policy.PriorityAdjustment))
+			}
+		}
+	}
+	return props
+}
+
+func federationQueueMatchProperties(prefix string, matches []brokerv1beta1.FederationQueueMatchType) []string {
+	props := []string{}
+	for i, match := range matches {
+		addressMatch := match.AddressMatch
+		if addressMatch == "" {
+			addressMatch = "#"
+		}
+		props = append(props,
+			fmt.Sprintf("%s%d.addressMatch=%s", prefix, i, addressMatch),
+			fmt.Sprintf("%s%d.queueMatch=%s", prefix, i, match.QueueMatch))
+	}
+	return props
+}
+
+// the query of the url becomes the params of the connector, like the query of a connector in broker.xml
+func connectorUrlProperties(name string, connectorUrl string) ([]string, error) {
+	parsed, err := url.Parse(connectorUrl)
+	if err != nil {
+		return nil, err
+	}
+	if parsed.Hostname() == "" {
+		return nil, fmt.Errorf("connector url %v has no host", connectorUrl)
+	}
+	port := parsed.Port()
+	if port == "" {
+		port = defaultConnectorPort
+	}
+
+	prefix := "connectorConfigurations." + name + "."
+	props := []string{
+		prefix + "factoryClassName=org.apache.activemq.artemis.core.remoting.impl.netty.NettyConnectorFactory",
+		prefix + "params.host=" + parsed.Hostname(),
+		prefix + "params.port=" + port,
+	}
+	query := parsed.Query()
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		props = append(props, prefix+"params."+key+"="+query.Get(key))
+	}
+	return props, nil
+}
+
+func federationUpstreamPolicies(federation brokerv1beta1.FederationType, upstream brokerv1beta1.FederationUpstreamType) []string {
+	if len(upstream.Policies) > 0 {
+		return upstream.Policies
+	}
+	policies := []string{}
+	for _, policy := range federation.AddressPolicies {
+		policies = append(policies, policy.Name)
+	}
+	for _, policy := range federation.QueuePolicies {
+		policies = append(policies, policy.Name)
+	}
+	return policies
+}
+
+// getConnectionCredentials reads the user and password keys of the secret a connection to another broker authenticates with
+func getConnectionCredentials(customResource *brokerv1beta1.ActiveMQArtemis, secretName string, client rtclient.Client) (string, string, error) {
+	if client == nil {
+		return "", "", fmt.Errorf("no client to retrieve secret %v", secretName)
+	}
+
+	secret := &corev1.Secret{}
+	if err := client.Get(context.TODO(), types.NamespacedName{Name: secretName, Namespace: customResource.Namespace}, secret); err != nil {
+		return "", "", err
+	}
+	credentials := []string{}
+	for _, key := range []string{"user", "password"} {
+		value, found := secret.Data[key]
+		if !found {
+			return "", "", fmt.Errorf("secret %v has no key %v", secretName, key)
+		}
+		credentials = append(credentials, strings.TrimSpace(string(value)))
+	}
+	return credentials[0], credentials[1], nil
+}
+
+func federationSecretNames(customResource *brokerv1beta1.ActiveMQArtemis) []string {
+	names := []string{}
+	for _, federation := range customResource.Spec.Federations {
+		for _, upstream := range federation.Upstreams {
+			if upstream.CredentialsSecret != "" {
+				names = append(names, upstream.CredentialsSecret)
+			}
+		}
+	}
+	return names
+}
+
+func invalidFederation(message string, args ...interface{}) *metav1.Condition {
+	return &metav1.Condition{
+		Type:    brokerv1beta1.ValidConditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  brokerv1beta1.ValidConditionInvalidFederationReason,
+		Message: fmt.Sprintf(message, args...),
+	}
+}
+
+// validateFederations checks the names the properties are keyed by and the policies the upstreams
+// refer to, a missing credentials secret is retried as it may be created after the CR
+func validateFederations(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, scheme *runtime.Scheme) (*metav1.Condition, bool) {
+	federations := map[string]bool{}
+	for _, federation := range customResource.Spec.Federations {
+		if federations[federation.Name] {
+			return invalidFederation(".Spec.Federations has more than one federation named %v", federation.Name), false
+		}
+		federations[federation.Name] = true
+
+		if len(federation.Upstreams) == 0 {
+			return invalidFederation(".Spec.Federations %v has no upstreams", federation.Name), false
+		}
+		if len(federation.AddressPolicies) == 0 && len(federation.QueuePolicies) == 0 {
+			return invalidFederation(".Spec.Federations %v has no address or queue policies", federation.Name), false
+		}
+
+		policies := map[string]bool{}
+		for _, policy := range federation.AddressPolicies {
+			if policies[policy.Name] {
+				return invalidFederation(".Spec.Federations %v has more than one policy named %v", federation.Name, policy.Name), false
+			}
+			policies[policy.Name] = true
+			if len(policy.Include) == 0 {
+				return invalidFederation(".Spec.Federations %v address policy %v includes no addresses", federation.Name, policy.Name), false
+			}
+		}
+		for _, policy := range federation.QueuePolicies {
+			if policies[policy.Name] {
+				return invalidFederation(".Spec.Federations %v has more than one policy named %v", federation.Name, policy.Name), false
+			}
+			policies[policy.Name] = true
+			if len(policy.Include) == 0 {
+				return invalidFederation(".Spec.Federations %v queue policy %v includes no queues", federation.Name, policy.Name), false
+			}
+		}
+
+		upstreams := map[string]bool{}
+		for _, upstream := range federation.Upstreams {
+			if upstreams[upstream.Name] {
+				return invalidFederation(".Spec.Federations %v has more than one upstream named %v", federation.Name, upstream.Name), false
+			}
+			upstreams[upstream.Name] = true
+
+			if len(upstream.ConnectorUrls) == 0 {
+				return invalidFederation(".Spec.Federations %v upstream %v has no connector urls", federation.Name, upstream.Name), false
+			}
+			for _, connectorUrl := range upstream.ConnectorUrls {
+				if _, err := connectorUrlProperties("", connectorUrl); err != nil {
+					return invalidFederation(".Spec.Federations %v upstream %v connector url %q is not valid, %v", federation.Name, upstream.Name, connectorUrl, err), false
+				}
+			}
+			for _, policy := range upstream.Policies {
+				if !policies[policy] {
+					return invalidFederation(".Spec.Federations %v upstream %v refers to policy %v that the federation doesn't have", federation.Name, upstream.Name, policy), false
+				}
+			}
+
+			if upstream.CredentialsSecret != "" {
+				path := fmt.Sprintf(".Spec.Federations %v upstream %v CredentialsSecret", federation.Name, upstream.Name)
+				if condition := validateConnectionCredentials(customResource, upstream.CredentialsSecret, path, client, scheme); condition != nil {
+					return condition, true
+				}
+			}
+		}
+	}
+	return nil, false
+}
+
+// validateConnectionCredentials checks that the credentials secret exists and has the user and password keys
+func validateConnectionCredentials(customResource *brokerv1beta1.ActiveMQArtemis, secretName string, path string, client rtclient.Client, scheme *runtime.Scheme) *metav1.Condition {
+	secret := corev1.Secret{}
+	if !retrieveResource(secretName, customResource.Namespace, &secret, client, scheme) {
+		return &metav1.Condition{
+			Type:    brokerv1beta1.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  brokerv1beta1.ValidConditionMissingResourcesReason,
+			Message: fmt.Sprintf("%s %v is not found", path, secretName),
+		}
+	}
+	for _, key := range []string{"user", "password"} {
+		if condition := AssertSecretContainsKey(secret, key, path+" is set but"); condition != nil {
+			return condition
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"testing"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/common"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+)
+
+func TestFederations(t *testing.T) {
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "edge", Namespace: "edge-ns"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			Federations: []brokerv1beta1.FederationType{{
+				Name: "cloud",
+				Upstreams: []brokerv1beta1.FederationUpstreamType{{
+					Name:              "eu",
+					ConnectorUrls:     []string{"tcp://cloud.example.com:443?sslEnabled=true&sniHost=cloud.example.com", "tcp://cloud-backup.example.com"},
+					CredentialsSecret: "cloud-credentials",
+					RetryInterval:     &[]int64{1000}[0],
+				}},
+				AddressPolicies: []brokerv1beta1.FederationAddressPolicyType{{Name: "events", Include: []string{"events.#"}, MaxHops: common.Int32ToPtr(1)}},
+				QueuePolicies: []brokerv1beta1.FederationQueuePolicyType{{
+					Name:    "orders",
+					Include: []brokerv1beta1.FederationQueueMatchType{{QueueMatch: "orders.#"}},
+					Exclude: []brokerv1beta1.FederationQueueMatchType{{AddressMatch: "orders", QueueMatch: "orders.local"}},
+				}},
+			}},
+		},
+	}
+
+	testScheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(testScheme))
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "cloud-credentials", Namespace: cr.Namespace},
+		Data:       map[string][]byte{"user": []byte("edge\n"), "password": []byte("secret")},
+	}
+
+	condition, retry := validateFederations(cr, fake.NewClientBuilder().WithScheme(testScheme).Build(), testScheme)
+	if assert.NotNil(t, condition) {
+		assert.Equal(t, brokerv1beta1.ValidConditionMissingResourcesReason, condition.Reason)
+		assert.True(t, retry)
+	}
+
+	c := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(secret).Build()
+	condition, _ = validateFederations(cr, c, testScheme)
+	assert.Nil(t, condition)
+	for _, invalid := range []func(*brokerv1beta1.ActiveMQArtemis){
+		func(c *brokerv1beta1.ActiveMQArtemis) {
+			c.Spec.Federations = append(c.Spec.Federations, c.Spec.Federations[0])
+		},
+		func(c *brokerv1beta1.ActiveMQArtemis) { c.Spec.Federations[0].Upstreams = nil },
+		func(c *brokerv1beta1.ActiveMQArtemis) {
+			c.Spec.Federations[0].AddressPolicies = nil
+			c.Spec.Federations[0].QueuePolicies = nil
+		},
+		func(c *brokerv1beta1.ActiveMQArtemis) { c.Spec.Federations[0].QueuePolicies[0].Name = "events" },
+		func(c *brokerv1beta1.ActiveMQArtemis) {
+			c.Spec.Federations[0].Upstreams[0].ConnectorUrls = []string{"tcp://:61616"}
+		},
+		func(c *brokerv1beta1.ActiveMQArtemis) {
+			c.Spec.Federations[0].Upstreams[0].Policies = []string{"missing"}
+		},
+	} {
+		invalidCr := cr.DeepCopy()
+		invalid(invalidCr)
+		condition, _ := validateFederations(invalidCr, c, testScheme)
+		if assert.NotNil(t, condition) {
+			assert.Equal(t, brokerv1beta1.ValidConditionInvalidFederationReason, condition.Reason)
+		}
+	}
+
+	props := federationProperties(cr, c)
+	assert.Contains(t, props, "connectorConfigurations.federation-cloud-eu-0.params.host=cloud.example.com")
+	assert.Contains(t, props, "connectorConfigurations.federation-cloud-eu-0.params.port=443")
+	assert.Contains(t, props, "connectorConfigurations.federation-cloud-eu-0.params.sslEnabled=true")
+	assert.Contains(t, props, "connectorConfigurations.federation-cloud-eu-1.params.port=61616")
+	assert.Contains(t, props, "federationConfigurations.cloud.upstreamConfigurations.eu.connectionConfiguration.staticConnectors=federation-cloud-eu-0,federation-cloud-eu-1")
+	assert.Contains(t, props, "federationConfigurations.cloud.upstreamConfigurations.eu.connectionConfiguration.username=edge")
+	assert.Contains(t, props, "federationConfigurations.cloud.upstreamConfigurations.eu.connectionConfiguration.password=secret")
+	assert.Contains(t, props, "federationConfigurations.cloud.upstreamConfigurations.eu.connectionConfiguration.retryInterval=1000")
+	assert.Contains(t, props, "federationConfigurations.cloud.upstreamConfigurations.eu.policyRefs=events,orders")
+	assert.Contains(t, props, "federationConfigurations.cloud.addressPolicies.events.includes.include-0.addressMatch=events.#")
+	assert.Contains(t, props, "federationConfigurations.cloud.addressPolicies.events.maxHops=1")
+	assert.Contains(t, props, "federationConfigurations.cloud.queuePolicies.orders.includes.include-0.addressMatch=#")
+	assert.Contains(t, props, "federationConfigurations.cloud.queuePolicies.orders.includes.include-0.queueMatch=orders.#")
+	assert.Contains(t, props, "federationConfigurations.cloud.queuePolicies.orders.excludes.exclude-0.addressMatch=orders")
+
+	assert.Equal(t, []string{"cloud-credentials"}, federationSecretNames(cr))
+}
@@ -0,0 +1,160 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	ss "github.com/artemiscloud/activemq-artemis-operator/pkg/resources/statefulsets"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/forecast"
+	jc "github.com/artemiscloud/activemq-artemis-operator/pkg/utils/jolokia_client"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	defaultForecastHorizonMinutes = 360
+	defaultForecastWindowMinutes  = 60
+
+	// the broker blocks producers once the disk store usage passes maxDiskUsage
+	defaultMaxDiskUsage = 90
+	// addresses page once their memory reaches the global max size
+	pagingMemoryUsage = 100
+
+	// the brokers are asked for their usage at most this often, not on every status update
+	forecastSampleInterval = time.Minute
+)
+
+// capacityUsage is a sample of a broker pod, both in percent
+type capacityUsage struct {
+	disk          float64
+	addressMemory float64
+}
+
+func readCapacityUsage(cr *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, namer Namers) map[string]capacityUsage {
+	resource := types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace}
+	usage := map[string]capacityUsage{}
+	for _, jk := range jc.GetBrokers(resource, ss.GetDeployedStatefulSetNames(client, []types.NamespacedName{resource}), client) {
+		disk, err := jk.Artemis.GetDiskStoreUsage()
+		if err != nil {
+			clog.V(1).Info("unable to read disk store usage", "IP", jk.IP, "Ordinal", jk.Ordinal, "error", err.Error())
+			continue
+		}
+		addressMemory, err := jk.Artemis.GetAddressMemoryUsagePercentage()
+		if err != nil {
+			clog.V(1).Info("unable to read address memory usage", "IP", jk.IP, "Ordinal", jk.Ordinal, "error", err.Error())
+			continue
+		}
+		usage[namer.SsNameBuilder.Name()+"-"+jk.Ordinal] = capacityUsage{disk: disk * 100, addressMemory: addressMemory}
+	}
+	return usage
+}
+
+// maxDiskUsage is the limit set through brokerProperties, -1 disables the check so the disk itself is the limit
+func maxDiskUsage(cr *brokerv1beta1.ActiveMQArtemis) float64 {
+	limit := float64(defaultMaxDiskUsage)
+	for _, property := range cr.Spec.BrokerProperties {
+		keyValue := strings.SplitN(property, "=", 2)
+		if len(keyValue) != 2 || strings.TrimSpace(keyValue[0]) != "maxDiskUsage" {
+			continue
+		}
+		if parsed, err := strconv.ParseFloat(strings.TrimSpace(keyValue[1]), 64); err == nil {
+			limit = parsed
+		}
+	}
+	if limit < 0 || limit > 100 {
+		return 100
+	}
+	return limit
+}
+
+// only present while exhaustion is predicted within the horizon, a false condition would hold back Ready
+func updateCapacityForecast(cr *brokerv1beta1.ActiveMQArtemis, readUsage func() map[string]capacityUsage, trends *forecast.Trends, now time.Time) {
+
+	prefix := cr.Namespace + "/" + cr.Name + "/"
+	capacityForecast := cr.Spec.DeploymentPlan.CapacityForecast
+	if capacityForecast == nil {
+		trends.Forget(prefix)
+		meta.RemoveStatusCondition(&cr.Status.Conditions, brokerv1beta1.CapacityWarningConditionType)
+		return
+	}
+
+	horizon := time.Duration(defaultForecastHorizonMinutes) * time.Minute
+	if capacityForecast.HorizonMinutes != nil {
+		horizon = time.Duration(*capacityForecast.HorizonMinutes) * time.Minute
+	}
+	window := time.Duration(defaultForecastWindowMinutes) * time.Minute
+	if capacityForecast.WindowMinutes != nil {
+		window = time.Duration(*capacityForecast.WindowMinutes) * time.Minute
+	}
+
+	if !trends.Due(prefix, now, forecastSampleInterval) {
+		return
+	}
+	// the pods that are gone are no longer sampled
+	trends.Expire(prefix, now, window)
+
+	usage := readUsage()
+	pods := make([]string, 0, len(usage))
+	for pod := range usage {
+		pods = append(pods, pod)
+	}
+	sort.Strings(pods)
+
+	reason := ""
+	warnings := []string{}
+	for _, pod := range pods {
+		trends.Record(prefix+pod+"/disk", now, usage[pod].disk, window)
+		trends.Record(prefix+pod+"/memory", now, usage[pod].addressMemory, window)
+
+		if estimate, rising := trends.TimeToReach(prefix+pod+"/disk", maxDiskUsage(cr)); rising && estimate <= horizon {
+			reason = brokerv1beta1.CapacityWarningConditionDiskReason
+			warnings = append(warnings, fmt.Sprintf("disk of %v full in %v at current rate", pod, formatEstimate(estimate)))
+		}
+		if estimate, rising := trends.TimeToReach(prefix+pod+"/memory", pagingMemoryUsage); rising && estimate <= horizon {
+			if reason == "" {
+				reason = brokerv1beta1.CapacityWarningConditionPagingReason
+			}
+			warnings = append(warnings, fmt.Sprintf("addresses of %v paging in %v at current rate", pod, formatEstimate(estimate)))
+		}
+	}
+
+	if len(warnings) > 0 {
+		meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+			Type:    brokerv1beta1.CapacityWarningConditionType,
+			Status:  metav1.ConditionTrue,
+			Reason:  reason,
+			Message: strings.Join(warnings, ", "),
+		})
+	} else {
+		meta.RemoveStatusCondition(&cr.Status.Conditions, brokerv1beta1.CapacityWarningConditionType)
+	}
+}
+
+// recordCapacityWarning raises an event when a warning appears or its cause changes, the estimate
+// in the message moves with every sample so it doesn't raise one
+func recordCapacityWarning(ctx context.Context, recorder record.EventRecorder, cr *brokerv1beta1.ActiveMQArtemis, previous *metav1.Condition) {
+	warning := meta.FindStatusCondition(cr.Status.Conditions, brokerv1beta1.CapacityWarningConditionType)
+	if warning == nil || recorder == nil {
+		return
+	}
+	if previous != nil && previous.Reason == warning.Reason {
+		return
+	}
+	recordEvent(ctx, recorder, cr, corev1.EventTypeWarning, warning.Reason, warning.Message)
+}
+
+func formatEstimate(estimate time.Duration) string {
+	if estimate <= 0 {
+		return "now"
+	}
+	return "~" + strings.TrimSuffix(estimate.Round(time.Minute).String(), "0s")
+}
@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/common"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/forecast"
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestCapacityForecast(t *testing.T) {
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: "test"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			BrokerProperties: []string{"maxDiskUsage=80"},
+			DeploymentPlan: brokerv1beta1.DeploymentPlanType{
+				CapacityForecast: &brokerv1beta1.CapacityForecastType{HorizonMinutes: common.Int32ToPtr(120)},
+			},
+		},
+	}
+	assert.Equal(t, float64(80), maxDiskUsage(cr))
+
+	trends := forecast.NewTrends()
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	// the disk of pod 0 grows a percent every 10 minutes, addresses stay flat
+	sample := func(minute int, disk float64) {
+		updateCapacityForecast(cr, func() map[string]capacityUsage {
+			return map[string]capacityUsage{
+				"broker-ss-0": {disk: disk, addressMemory: 20},
+				"broker-ss-1": {disk: 10, addressMemory: 20},
+			}
+		}, trends, start.Add(time.Duration(minute)*time.Minute))
+	}
+
+	// too far off to warn about, 30% left at 10 minutes a percent is 5 hours
+	for minute := 0; minute <= 40; minute += 10 {
+		sample(minute, 46+float64(minute)/10)
+	}
+	assert.Nil(t, meta.FindStatusCondition(cr.Status.Conditions, brokerv1beta1.CapacityWarningConditionType))
+
+	// now within the horizon
+	recorder := record.NewFakeRecorder(10)
+	for minute := 50; minute <= 90; minute += 10 {
+		previous := meta.FindStatusCondition(cr.Status.Conditions, brokerv1beta1.CapacityWarningConditionType)
+		if previous != nil {
+			previous = previous.DeepCopy()
+		}
+		sample(minute, 66+float64(minute)/10)
+		recordCapacityWarning(context.TODO(), recorder, cr, previous)
+	}
+	condition := meta.FindStatusCondition(cr.Status.Conditions, brokerv1beta1.CapacityWarningConditionType)
+	assert.NotNil(t, condition)
+	// one event when the warning appears
+	assert.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "Warning "+brokerv1beta1.CapacityWarningConditionDiskReason)
+	assert.Equal(t, brokerv1beta1.CapacityWarningConditionDiskReason, condition.Reason)
+	assert.Contains(t, condition.Message, "disk of broker-ss-0 full in ~")
+	assert.NotContains(t, condition.Message, "broker-ss-1")
+
+	// the brokers are not asked again within the sample interval
+	reads := 0
+	updateCapacityForecast(cr, func() map[string]capacityUsage {
+		reads++
+		return nil
+	}, trends, start.Add(90*time.Minute+30*time.Second))
+	assert.Equal(t, 0, reads)
+	assert.NotNil(t, meta.FindStatusCondition(cr.Status.Conditions, brokerv1beta1.CapacityWarningConditionType))
+
+	// a warning is not a failure, Ready isn't held back
+	common.SetReadyCondition(&cr.Status.Conditions)
+	assert.True(t, meta.IsStatusConditionTrue(cr.Status.Conditions, brokerv1beta1.ReadyConditionType))
+
+	// disabling the forecast clears the warning
+	cr.Spec.DeploymentPlan.CapacityForecast = nil
+	sample(100, 80)
+	assert.Nil(t, meta.FindStatusCondition(cr.Status.Conditions, brokerv1beta1.CapacityWarningConditionType))
+
+	cr.Spec.DeploymentPlan.CapacityForecast = &brokerv1beta1.CapacityForecastType{WindowMinutes: common.Int32ToPtr(0)}
+	assert.NotNil(t, validateCapacityForecast(cr))
+}
@@ -0,0 +1,549 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/resources/persistentvolumeclaims"
+	ss "github.com/artemiscloud/activemq-artemis-operator/pkg/resources/statefulsets"
+	jc "github.com/artemiscloud/activemq-artemis-operator/pkg/utils/jolokia_client"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	haGroupNamePrefix = "pair-"
+
+	haZooKeeperManagerClass        = "org.apache.activemq.artemis.quorum.zookeeper.CuratorDistributedPrimitiveManager"
+	haZooKeeperConnectStringEnvVar = "HA_ZOOKEEPER_CONNECT_STRING"
+	defaultZooKeeperPort           = int32(2181)
+)
+
+func isHAEnabled(customResource *brokerv1beta1.ActiveMQArtemis) bool {
+	return customResource.Spec.HA != nil && customResource.Spec.HA.Enabled
+}
+
+// with a shared store the backup reads the journal of its primary from the same ReadWriteMany volume
+func isHASharedStore(customResource *brokerv1beta1.ActiveMQArtemis) bool {
+	return isHAEnabled(customResource) && customResource.Spec.HA.Policy == brokerv1beta1.HAPolicySharedStore
+}
+
+func haSharedStoreClaimName(customResource *brokerv1beta1.ActiveMQArtemis) string {
+	if sharedStore := customResource.Spec.HA.SharedStore; sharedStore != nil && sharedStore.ClaimName != "" {
+		return sharedStore.ClaimName
+	}
+	return customResource.Name + "-shared-store"
+}
+
+func haGroupName(pair int32) string {
+	return haGroupNamePrefix + strconv.Itoa(int(pair))
+}
+
+// haPolicyXml is the ha-policy of the primary or the backup of a pair, the init container sets the
+// GROUP variable from the ordinal of the pod. The operator restarts the pods of a pair in an order
+// that relies on the primary checking for a live backup and the backup failing back
+func haPolicyXml(customResource *brokerv1beta1.ActiveMQArtemis, primary bool) string {
+	if isHASharedStore(customResource) {
+		return haSharedStoreXml(customResource, primary)
+	}
+	ha := customResource.Spec.HA
+	if ha.ZooKeeper != nil {
+		return haZooKeeperXml(customResource, primary)
+	}
+	vote := ""
+	if ha.VoteOnReplicationFailure != nil {
+		vote += "<vote-on-replication-failure>" + strconv.FormatBool(*ha.VoteOnReplicationFailure) + "</vote-on-replication-failure>"
+	}
+	if ha.QuorumSize != nil {
+		vote += "<quorum-size>" + strconv.Itoa(int(*ha.QuorumSize)) + "</quorum-size>"
+	}
+	if ha.VoteRetries != nil {
+		vote += "<vote-retries>" + strconv.Itoa(int(*ha.VoteRetries)) + "</vote-retries>"
+	}
+	if ha.VoteRetryWait != nil {
+		vote += "<vote-retry-wait>" + strconv.FormatInt(*ha.VoteRetryWait, 10) + "</vote-retry-wait>"
+	}
+
+	if primary {
+		return "<core><ha-policy><replication><master><group-name>${GROUP}</group-name><check-for-live-server>true</check-for-live-server>" +
+			vote + "</master></replication></ha-policy></core>"
+	}
+	return "<core><ha-policy><replication><slave><group-name>${GROUP}</group-name><allow-failback>true</allow-failback><restart-backup>true</restart-backup>" +
+		vote + "</slave></replication></ha-policy></core>"
+}
+
+// every pair keeps its journal in its own directory of the shared volume, the broker holding the file
+// lock of the journal directory is the live one
+func haSharedStoreXml(customResource *brokerv1beta1.ActiveMQArtemis, primary bool) string {
+	failoverOnShutdown := true
+	lock := ""
+	if sharedStore := customResource.Spec.HA.SharedStore; sharedStore != nil {
+		if sharedStore.FailoverOnShutdown != nil {
+			failoverOnShutdown = *sharedStore.FailoverOnShutdown
+		}
+		if sharedStore.LockAcquisitionTimeout != nil {
+			lock = "<journal-lock-acquisition-timeout>" + strconv.FormatInt(*sharedStore.LockAcquisitionTimeout, 10) + "</journal-lock-acquisition-timeout>"
+		}
+	}
+	dir := "/opt/" + customResource.Name + "/data/${GROUP}"
+	store := "<paging-directory>" + dir + "/paging</paging-directory><bindings-directory>" + dir + "/bindings</bindings-directory>" +
+		"<journal-directory>" + dir + "/journal</journal-directory><large-messages-directory>" + dir + "/large-messages</large-messages-directory>" + lock
+	failover := "<failover-on-shutdown>" + strconv.FormatBool(failoverOnShutdown) + "</failover-on-shutdown>"
+
+	if primary {
+		return "<core>" + store + "<ha-policy><shared-store><master>" + failover + "</master></shared-store></ha-policy></core>"
+	}
+	return "<core>" + store + "<ha-policy><shared-store><slave><allow-failback>true</allow-failback>" + failover + "</slave></shared-store></ha-policy></core>"
+}
+
+// with ZooKeeper the pair holds a lock in the ensemble instead of asking the other primaries of the
+// cluster for a vote, so a backup that loses its primary only takes over once the session of the
+// primary expired
+func haZooKeeperXml(customResource *brokerv1beta1.ActiveMQArtemis, primary bool) string {
+	zooKeeper := customResource.Spec.HA.ZooKeeper
+	properties := "<property key='connect-string' value='${" + haZooKeeperConnectStringEnvVar + "}'/>" +
+		"<property key='namespace' value='" + haZooKeeperNamespace(customResource) + "'/>"
+	if zooKeeper.SessionTimeout != nil {
+		properties += "<property key='session-ms' value='" + strconv.Itoa(int(*zooKeeper.SessionTimeout)) + "'/>"
+	}
+	if zooKeeper.ConnectionTimeout != nil {
+		properties += "<property key='connection-ms' value='" + strconv.Itoa(int(*zooKeeper.ConnectionTimeout)) + "'/>"
+	}
+	manager := "<manager><class-name>" + haZooKeeperManagerClass + "</class-name><properties>" + properties + "</properties></manager>"
+
+	if primary {
+		return "<core><ha-policy><replication><primary>" + manager +
+			"<group-name>${GROUP}</group-name><coordination-id>${GROUP}</coordination-id></primary></replication></ha-policy></core>"
+	}
+	return "<core><ha-policy><replication><backup>" + manager +
+		"<group-name>${GROUP}</group-name><allow-failback>true</allow-failback></backup></replication></ha-policy></core>"
+}
+
+func haZooKeeperNamespace(customResource *brokerv1beta1.ActiveMQArtemis) string {
+	if namespace := customResource.Spec.HA.ZooKeeper.Namespace; namespace != "" {
+		return namespace
+	}
+	return customResource.Namespace + "-" + customResource.Name
+}
+
+// haZooKeeperConnectString is the env var of the init container the ha-policy reads the connect
+// string from, whatever its source, so that it never becomes part of the init command
+func haZooKeeperConnectString(customResource *brokerv1beta1.ActiveMQArtemis) *corev1.EnvVar {
+	if !isHAEnabled(customResource) || customResource.Spec.HA.ZooKeeper == nil {
+		return nil
+	}
+	zooKeeper := customResource.Spec.HA.ZooKeeper
+	envVar := &corev1.EnvVar{Name: haZooKeeperConnectStringEnvVar, Value: zooKeeper.ConnectString}
+	switch {
+	case zooKeeper.ConnectStringSecret != nil:
+		envVar.Value = ""
+		envVar.ValueFrom = &corev1.EnvVarSource{SecretKeyRef: zooKeeper.ConnectStringSecret}
+	case zooKeeper.ConnectStringConfigMap != nil:
+		envVar.Value = ""
+		envVar.ValueFrom = &corev1.EnvVarSource{ConfigMapKeyRef: zooKeeper.ConnectStringConfigMap}
+	case zooKeeper.Service != nil:
+		envVar.Value = haZooKeeperServiceAddress(customResource)
+	}
+	return envVar
+}
+
+func haZooKeeperServiceAddress(customResource *brokerv1beta1.ActiveMQArtemis) string {
+	service := customResource.Spec.HA.ZooKeeper.Service
+	namespace := service.Namespace
+	if namespace == "" {
+		namespace = customResource.Namespace
+	}
+	port := defaultZooKeeperPort
+	if service.Port != nil {
+		port = *service.Port
+	}
+	return fmt.Sprintf("%s.%s.svc:%d", service.Name, namespace, port)
+}
+
+// the pods share a template, the init container picks the ha-policy of the pod from the parity of its
+// ordinal, expands the group of the pair and merges it into the generated broker.xml
+func haPolicyCmd(customResource *brokerv1beta1.ActiveMQArtemis, initCfgRootDir string) string {
+	if !isHAEnabled(customResource) {
+		return ""
+	}
+	policy := initCfgRootDir + "/ha-policy.xml"
+	return "ORDINAL=${HOSTNAME##*-} && export GROUP=" + haGroupNamePrefix + "$((ORDINAL / 2))" +
+		" && if [ $((ORDINAL % 2)) -eq 0 ]; then HA_POLICY=" + initScriptPath(haPrimaryPolicyFileName) +
+		"; else HA_POLICY=" + initScriptPath(haBackupPolicyFileName) + "; fi" +
+		" && python3 " + initScriptPath(expandEnvScriptName) + " $HA_POLICY " + policy +
+		" && python3 " + initScriptPath(brokerXmlMergeScriptName) + " " + brokerConfigRoot + "/etc/broker.xml " + policy
+}
+
+// a backup doesn't open its acceptors and never becomes ready, so the StatefulSet neither waits for
+// the pods to be ready nor rolls them, the operator deletes them in the order of applyHARollout.
+// Without ha a previously paired StatefulSet is rolled by kube again
+func configureHAStatefulSet(customResource *brokerv1beta1.ActiveMQArtemis, desired *appsv1.StatefulSet) {
+	if isHAEnabled(customResource) {
+		desired.Spec.PodManagementPolicy = appsv1.ParallelPodManagement
+		desired.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{Type: appsv1.OnDeleteStatefulSetStrategyType}
+		return
+	}
+	if desired.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType {
+		desired.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{Type: appsv1.RollingUpdateStatefulSetStrategyType}
+	}
+}
+
+// haSharedStoreClaim is the ReadWriteMany claim that holds the journals of all the pairs, it defaults
+// to the storage of the deploymentPlan for each pair
+func haSharedStoreClaim(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers) *corev1.PersistentVolumeClaim {
+	storage := customResource.Spec.DeploymentPlan.Storage
+	size := resource.MustParse("2Gi")
+	if storage.Size != "" {
+		size = resource.MustParse(storage.Size)
+	}
+	size = *resource.NewQuantity(size.Value()*int64(getDeploymentSize(customResource)/2), size.Format)
+	storageClassName := storage.StorageClassName
+
+	if sharedStore := customResource.Spec.HA.SharedStore; sharedStore != nil {
+		if sharedStore.Size != "" {
+			size = resource.MustParse(sharedStore.Size)
+		}
+		if sharedStore.StorageClassName != "" {
+			storageClassName = sharedStore.StorageClassName
+		}
+	}
+
+	claim := persistentvolumeclaims.NewPersistentVolumeClaimWithCapacityAndStorageClassName(
+		types.NamespacedName{Name: haSharedStoreClaimName(customResource), Namespace: customResource.Namespace}, size.String(), namer.LabelBuilder.Labels(), storageClassName)
+	claim.Spec.AccessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany}
+	return claim
+}
+
+// the claim is not owned by the cr, like the claims of the StatefulSet it outlives the cr so that the
+// journals are not lost with it. A claim named in the cr is left to its owner, and a larger size only
+// ever grows the claim
+func (reconciler *ActiveMQArtemisReconcilerImpl) applyHASharedStore(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, client rtclient.Client) {
+	if sharedStore := customResource.Spec.HA.SharedStore; sharedStore != nil && sharedStore.ClaimName != "" {
+		return
+	}
+	desired := haSharedStoreClaim(customResource, namer)
+	existing := &corev1.PersistentVolumeClaim{}
+	err := client.Get(context.TODO(), types.NamespacedName{Name: desired.Name, Namespace: desired.Namespace}, existing)
+	if k8serrors.IsNotFound(err) {
+		if err = client.Create(context.TODO(), desired); err != nil {
+			clog.Error(err, "unable to create shared store claim", "name", desired.Name)
+		}
+		return
+	}
+	if err != nil {
+		clog.Error(err, "unable to retrieve shared store claim", "name", desired.Name)
+		return
+	}
+	desiredSize := desired.Spec.Resources.Requests[corev1.ResourceStorage]
+	existingSize := existing.Spec.Resources.Requests[corev1.ResourceStorage]
+	if desiredSize.Cmp(existingSize) <= 0 {
+		return
+	}
+	if existing.Spec.Resources.Requests == nil {
+		existing.Spec.Resources.Requests = corev1.ResourceList{}
+	}
+	existing.Spec.Resources.Requests[corev1.ResourceStorage] = desiredSize
+	if err = client.Update(context.TODO(), existing); err != nil {
+		clog.Error(err, "unable to grow shared store claim", "name", existing.Name)
+	}
+}
+
+// haReplicaSyncCheck reads from a live broker pod whether its backup is in sync
+type haReplicaSyncCheck func(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, pod string) (bool, error)
+
+// applyHARollout restarts one pod at a time for a change of the pod template, pair by pair from the
+// highest ordinal. The member of a pair that isn't live goes first. Once it is back and, with
+// replication, in sync, the live member goes and the other one takes over. A primary fails back once
+// it is restarted. The highest pair is the canary of a change of the security configuration, the
+// other pairs wait until a login to its live broker succeeds
+func (reconciler *ActiveMQArtemisReconcilerImpl) applyHARollout(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, client rtclient.Client, replicaSync haReplicaSyncCheck, loginCheck securityCanaryLoginCheck, now time.Time) {
+	for i := range customResource.Status.HA {
+		customResource.Status.HA[i].Message = ""
+	}
+	if !isHAEnabled(customResource) {
+		return
+	}
+	deployed, _ := reconciler.getFromDeployed(reflect.TypeOf(appsv1.StatefulSet{}), namer.SsNameBuilder.Name()).(*appsv1.StatefulSet)
+	if deployed == nil || deployed.Spec.Replicas == nil || deployed.Status.ObservedGeneration != deployed.Generation || deployed.Status.UpdateRevision == "" {
+		return
+	}
+	log := ctrl.Log.WithValues("ActiveMQArtemis Name", customResource.Name)
+
+	getPod := func(name string) *corev1.Pod {
+		pod := &corev1.Pod{}
+		if err := client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: customResource.Namespace}, pod); err != nil {
+			return nil
+		}
+		return pod
+	}
+	outdated := func(pod *corev1.Pod) bool {
+		return pod != nil && pod.Labels[appsv1.ControllerRevisionHashLabelKey] != deployed.Status.UpdateRevision
+	}
+	restart := func(pod *corev1.Pod) bool {
+		if err := client.Delete(context.TODO(), pod); err != nil && !k8serrors.IsNotFound(err) {
+			log.Error(err, "unable to restart pod of ha pair", "pod", pod.Name)
+			return false
+		}
+		log.Info("Restarted pod of ha pair", "pod", pod.Name)
+		return true
+	}
+
+	canaryPair := *deployed.Spec.Replicas/2 - 1
+	for pair := canaryPair; pair >= 0; pair-- {
+		primaryName := deployed.Name + "-" + strconv.Itoa(int(2*pair))
+		backupName := deployed.Name + "-" + strconv.Itoa(int(2*pair+1))
+		primary, backup := getPod(primaryName), getPod(backupName)
+
+		// a backup opens its acceptors only when it takes over, the ready member is the live one
+		var live, standby *corev1.Pod
+		liveRole, standbyRole, standbyName := "primary", "backup", backupName
+		if primary != nil && isPodReady(primary) {
+			live, standby = primary, backup
+		} else if backup != nil && isPodReady(backup) {
+			live, standby = backup, primary
+			liveRole, standbyRole, standbyName = "backup", "primary", primaryName
+		}
+
+		message := ""
+		switch {
+		case live == nil:
+			// nothing of the pair serves, a pod of the previous revision may be what keeps it down
+			for _, pod := range []*corev1.Pod{primary, backup} {
+				if outdated(pod) {
+					restart(pod)
+				}
+			}
+			message = fmt.Sprintf("waiting for primary %v or backup %v to be live", primaryName, backupName)
+		case outdated(standby):
+			if restart(standby) {
+				message = fmt.Sprintf("restarting %v %v", standbyRole, standbyName)
+			}
+		case standby == nil:
+			message = fmt.Sprintf("waiting for %v %v to start while %v %v is live", standbyRole, standbyName, liveRole, live.Name)
+		case outdated(live) && !isHASharedStore(customResource):
+			if synced, err := replicaSync(customResource, client, live.Name); err != nil || !synced {
+				message = fmt.Sprintf("waiting for %v %v to be in sync with %v %v before the %v restarts", standbyRole, standbyName, liveRole, live.Name, liveRole)
+			} else if restart(live) {
+				message = haTakeOverMessage(liveRole, live.Name, standbyName)
+			}
+		case outdated(live):
+			// the other member reads the journal from the shared store as soon as it holds the lock
+			if restart(live) {
+				message = haTakeOverMessage(liveRole, live.Name, standbyName)
+			}
+		case pair == canaryPair && pair > 0:
+			message = haSecurityCanaryMessage(customResource, client, deployed, live.Name, loginCheck, now)
+		}
+		if message != "" {
+			setHAPairMessage(customResource, pair, message)
+			return
+		}
+	}
+}
+
+func haTakeOverMessage(liveRole string, live string, standby string) string {
+	if liveRole == "primary" {
+		return fmt.Sprintf("restarting primary %v, backup %v takes over until it fails back", live, standby)
+	}
+	return fmt.Sprintf("restarting backup %v, primary %v takes over", live, standby)
+}
+
+func setHAPairMessage(customResource *brokerv1beta1.ActiveMQArtemis, pair int32, message string) {
+	group := haGroupName(pair)
+	for i := range customResource.Status.HA {
+		if customResource.Status.HA[i].GroupName == group {
+			customResource.Status.HA[i].Message = message
+			return
+		}
+	}
+	customResource.Status.HA = append(customResource.Status.HA, brokerv1beta1.HAPairStatus{GroupName: group, Message: message})
+}
+
+// updateHAStatus lists the pairs of the deploymentPlan size, the ready pod of a pair is the live one
+// as a backup opens its acceptors only when it takes over
+func updateHAStatus(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, namer Namers) {
+	if !isHAEnabled(customResource) {
+		customResource.Status.HA = nil
+		return
+	}
+	messages := map[string]string{}
+	for _, pair := range customResource.Status.HA {
+		messages[pair.GroupName] = pair.Message
+	}
+
+	pairs := []brokerv1beta1.HAPairStatus{}
+	for pair := int32(0); pair < getDeploymentSize(customResource)/2; pair++ {
+		status := brokerv1beta1.HAPairStatus{
+			GroupName: haGroupName(pair),
+			Primary:   namer.SsNameBuilder.Name() + "-" + strconv.Itoa(int(2*pair)),
+			Backup:    namer.SsNameBuilder.Name() + "-" + strconv.Itoa(int(2*pair+1)),
+		}
+		for _, name := range []string{status.Primary, status.Backup} {
+			pod := &corev1.Pod{}
+			if err := client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: customResource.Namespace}, pod); err == nil && isPodReady(pod) {
+				status.Live = name
+				break
+			}
+		}
+		status.Message = messages[status.GroupName]
+		pairs = append(pairs, status)
+	}
+	customResource.Status.HA = pairs
+}
+
+// checkHAReplicaSync reads the ReplicaSync attribute of the live broker through jolokia
+func checkHAReplicaSync(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, pod string) (bool, error) {
+	resource := types.NamespacedName{Name: customResource.Name, Namespace: customResource.Namespace}
+	for _, jk := range jc.GetBrokers(resource, ss.GetDeployedStatefulSetNames(client, []types.NamespacedName{resource}), client) {
+		if jk.PodName == pod {
+			return jk.Artemis.IsReplicaSync()
+		}
+	}
+	return false, fmt.Errorf("pod %v is not reachable", pod)
+}
+
+func validateHA(customResource *brokerv1beta1.ActiveMQArtemis) *metav1.Condition {
+	if !isHAEnabled(customResource) {
+		return nil
+	}
+	invalid := func(message string) *metav1.Condition {
+		return &metav1.Condition{
+			Type:    brokerv1beta1.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  brokerv1beta1.ValidConditionInvalidHAReason,
+			Message: ".Spec.HA " + message,
+		}
+	}
+
+	if size := getDeploymentSize(customResource); size < 2 || size%2 != 0 {
+		return invalid(fmt.Sprintf("needs an even deploymentPlan size of at least 2 for primary and backup pairs, not %d", size))
+	}
+	if !isClustered(customResource) {
+		return invalid("needs a clustered deployment, the backup finds its primary through the cluster connection")
+	}
+	if !requiresPersistentVolume(customResource) {
+		return invalid("needs .Spec.DeploymentPlan.PersistenceEnabled with a file journal, the backup replicates the journal of its primary")
+	}
+	if isAutoscalingEnabled(customResource) {
+		return invalid("can't be combined with .Spec.Autoscaling, the autoscaler doesn't keep the size even")
+	}
+	ha := customResource.Spec.HA
+	if ha.Policy != brokerv1beta1.HAPolicySharedStore {
+		if ha.SharedStore != nil {
+			return invalid("sharedStore needs the SharedStore policy")
+		}
+		if zooKeeper := ha.ZooKeeper; zooKeeper != nil {
+			sources := 0
+			for _, set := range []bool{zooKeeper.ConnectString != "", zooKeeper.ConnectStringSecret != nil, zooKeeper.ConnectStringConfigMap != nil, zooKeeper.Service != nil} {
+				if set {
+					sources++
+				}
+			}
+			if sources != 1 {
+				return invalid("zooKeeper needs exactly one of connectString, connectStringSecret, connectStringConfigMap or service")
+			}
+			if ha.VoteOnReplicationFailure != nil || ha.QuorumSize != nil || ha.VoteRetries != nil || ha.VoteRetryWait != nil {
+				return invalid("vote settings can't be combined with zooKeeper, the pairs don't vote")
+			}
+		}
+		return nil
+	}
+	if ha.ZooKeeper != nil {
+		return invalid("zooKeeper only applies to the Replication policy, the file lock of the shared store decides which broker is live")
+	}
+	if ha.VoteOnReplicationFailure != nil || ha.QuorumSize != nil || ha.VoteRetries != nil || ha.VoteRetryWait != nil {
+		return invalid("vote settings only apply to the Replication policy")
+	}
+	if ha.SharedStore != nil && ha.SharedStore.Size != "" {
+		if _, err := resource.ParseQuantity(ha.SharedStore.Size); err != nil {
+			return invalid(fmt.Sprintf("sharedStore size %v is not a quantity", ha.SharedStore.Size))
+		}
+	}
+	return nil
+}
+
+// validateHAStoreChange refuses to move deployed brokers between the claims of their pods and a shared
+// store. The StatefulSet would be recreated without the journals of the brokers, they stay behind on
+// the volumes the brokers no longer mount
+func validateHAStoreChange(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, scheme *runtime.Scheme) (*metav1.Condition, bool) {
+	deployed := &appsv1.StatefulSet{}
+	if !retrieveResource(MakeNamers(customResource).SsNameBuilder.Name(), customResource.Namespace, deployed, client, scheme) {
+		return nil, false
+	}
+	onSharedStore := false
+	if len(deployed.Spec.VolumeClaimTemplates) == 0 {
+		// the claim of the pods is named after the cr, the template of the StatefulSet provides it
+		for _, volume := range deployed.Spec.Template.Spec.Volumes {
+			if volume.Name == customResource.Name && volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.ClaimName != customResource.Name {
+				onSharedStore = true
+			}
+		}
+	} else if isHASharedStore(customResource) {
+		return &metav1.Condition{
+			Type:    brokerv1beta1.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  brokerv1beta1.ValidConditionInvalidHAReason,
+			Message: ".Spec.HA policy SharedStore can't be applied to brokers that keep their journals on the claims of their pods, deploy a new CR with the policy and move the messages to it",
+		}, false
+	}
+	if onSharedStore && !isHASharedStore(customResource) {
+		return &metav1.Condition{
+			Type:    brokerv1beta1.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  brokerv1beta1.ValidConditionInvalidHAReason,
+			Message: ".Spec.HA policy SharedStore can't be removed from brokers that keep their journals on the shared store, deploy a new CR and move the messages to it",
+		}, false
+	}
+	return nil, false
+}
+
+// validateHAZooKeeper checks the sources of the connect string, the secret, the config map or the
+// existing service may be created after the CR
+func validateHAZooKeeper(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, scheme *runtime.Scheme) (*metav1.Condition, bool) {
+	zooKeeper := customResource.Spec.HA.ZooKeeper
+	missing := func(message string, args ...interface{}) *metav1.Condition {
+		return &metav1.Condition{
+			Type:    brokerv1beta1.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  brokerv1beta1.ValidConditionMissingResourcesReason,
+			Message: ".Spec.HA.ZooKeeper." + fmt.Sprintf(message, args...),
+		}
+	}
+
+	if selector := zooKeeper.ConnectStringSecret; selector != nil {
+		secret := corev1.Secret{}
+		if !retrieveResource(selector.Name, customResource.Namespace, &secret, client, scheme) {
+			return missing("ConnectStringSecret %v is not found", selector.Name), true
+		}
+		if condition := AssertSecretContainsKey(secret, selector.Key, ".Spec.HA.ZooKeeper.ConnectStringSecret is set but"); condition != nil {
+			return condition, true
+		}
+	}
+	if selector := zooKeeper.ConnectStringConfigMap; selector != nil {
+		configMap := corev1.ConfigMap{}
+		if !retrieveResource(selector.Name, customResource.Namespace, &configMap, client, scheme) {
+			return missing("ConnectStringConfigMap %v is not found", selector.Name), true
+		}
+		if condition := AssertConfigMapContainsKey(configMap, selector.Key, ".Spec.HA.ZooKeeper.ConnectStringConfigMap"); condition != nil {
+			return condition, true
+		}
+	}
+	// the operator may not watch the namespace of a service elsewhere, that one is left to the brokers
+	if service := zooKeeper.Service; service != nil && (service.Namespace == "" || service.Namespace == customResource.Namespace) {
+		if !retrieveResource(service.Name, customResource.Namespace, &corev1.Service{}, client, scheme) {
+			return missing("Service %v is not found", service.Name), true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,444 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/common"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+)
+
+func TestHAPairs(t *testing.T) {
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "ha", Namespace: "ha-ns"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			DeploymentPlan: brokerv1beta1.DeploymentPlanType{Size: common.Int32ToPtr(4), PersistenceEnabled: true},
+			HA:             &brokerv1beta1.HAType{Enabled: true, QuorumSize: common.Int32ToPtr(2)},
+		},
+	}
+	namer := MakeNamers(cr)
+	assert.Nil(t, validateHA(cr))
+	for _, invalid := range []func(*brokerv1beta1.ActiveMQArtemis){
+		func(c *brokerv1beta1.ActiveMQArtemis) { c.Spec.DeploymentPlan.Size = common.Int32ToPtr(3) },
+		func(c *brokerv1beta1.ActiveMQArtemis) { c.Spec.DeploymentPlan.PersistenceEnabled = false },
+		func(c *brokerv1beta1.ActiveMQArtemis) {
+			clustered := false
+			c.Spec.DeploymentPlan.Clustered = &clustered
+		},
+		func(c *brokerv1beta1.ActiveMQArtemis) {
+			c.Spec.Autoscaling = &brokerv1beta1.AutoscalingType{Enabled: true}
+		},
+	} {
+		c := cr.DeepCopy()
+		invalid(c)
+		condition := validateHA(c)
+		if assert.NotNil(t, condition) {
+			assert.Equal(t, brokerv1beta1.ValidConditionInvalidHAReason, condition.Reason)
+		}
+	}
+
+	// the parity of the ordinal picks the policy, the pair shares its group name
+	cmd := haPolicyCmd(cr, "/amq/init/config")
+	scripts := initScriptsData(cr, []string{cmd})
+	policies := scripts[haPrimaryPolicyFileName] + scripts[haBackupPolicyFileName]
+	assert.Contains(t, scripts, brokerXmlMergeScriptName)
+	assert.Contains(t, cmd, "GROUP=pair-$((ORDINAL / 2))")
+	assert.Contains(t, policies, "<master><group-name>${GROUP}</group-name><check-for-live-server>true</check-for-live-server><quorum-size>2</quorum-size></master>")
+	assert.Contains(t, policies, "<slave><group-name>${GROUP}</group-name><allow-failback>true</allow-failback>")
+
+	podSpec := &v1.PodSpec{}
+	configureAntiAffinityPreset(podSpec, cr)
+	assert.Nil(t, podSpec.Affinity, "replicated pairs keep the affinity they were deployed with")
+
+	desired := &appsv1.StatefulSet{}
+	configureHAStatefulSet(cr, desired)
+	assert.Equal(t, appsv1.ParallelPodManagement, desired.Spec.PodManagementPolicy)
+	assert.Equal(t, appsv1.OnDeleteStatefulSetStrategyType, desired.Spec.UpdateStrategy.Type)
+
+	replicas := int32(4)
+	deployed := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "ha-ss", Namespace: cr.Namespace, Generation: 3},
+		Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+		Status:     appsv1.StatefulSetStatus{ObservedGeneration: 3, UpdateRevision: "ha-ss-new"},
+	}
+	pod := func(ordinal int, revision string, ready bool) *v1.Pod {
+		status := v1.ConditionFalse
+		if ready {
+			status = v1.ConditionTrue
+		}
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "ha-ss-" + strconv.Itoa(ordinal), Namespace: cr.Namespace, Labels: map[string]string{appsv1.ControllerRevisionHashLabelKey: revision}},
+			Status:     v1.PodStatus{Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: status}}},
+		}
+	}
+	testScheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(testScheme))
+	c := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(
+		pod(0, "ha-ss-old", true), pod(1, "ha-ss-old", false), pod(2, "ha-ss-new", true), pod(3, "ha-ss-old", false)).Build()
+	synced := false
+	syncedPods := []string{}
+	replicaSync := func(customResource *brokerv1beta1.ActiveMQArtemis, c client.Client, pod string) (bool, error) {
+		syncedPods = append(syncedPods, pod)
+		return synced, nil
+	}
+	reconciler := &ActiveMQArtemisReconcilerImpl{deployed: map[reflect.Type][]client.Object{reflect.TypeOf(appsv1.StatefulSet{}): {deployed}}}
+	rollout := func() {
+		reconciler.applyHARollout(cr, *namer, c, replicaSync, nil, time.Now())
+	}
+	exists := func(name string) bool {
+		return c.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: cr.Namespace}, &v1.Pod{}) == nil
+	}
+	message := func(group string) string {
+		for _, pair := range cr.Status.HA {
+			if pair.GroupName == group {
+				return pair.Message
+			}
+		}
+		return ""
+	}
+
+	updateHAStatus(cr, c, *namer)
+	assert.Equal(t, []brokerv1beta1.HAPairStatus{
+		{GroupName: "pair-0", Primary: "ha-ss-0", Backup: "ha-ss-1", Live: "ha-ss-0"},
+		{GroupName: "pair-1", Primary: "ha-ss-2", Backup: "ha-ss-3", Live: "ha-ss-2"},
+	}, cr.Status.HA)
+
+	// the backups restart first, from the highest pair, while their primaries are live
+	rollout()
+	assert.False(t, exists("ha-ss-3"))
+	assert.Equal(t, "restarting backup ha-ss-3", message("pair-1"))
+	rollout()
+	assert.Equal(t, "waiting for backup ha-ss-3 to start while primary ha-ss-2 is live", message("pair-1"))
+	assert.NoError(t, c.Create(context.TODO(), pod(3, "ha-ss-new", false)))
+	rollout()
+	assert.False(t, exists("ha-ss-1"))
+	assert.Equal(t, "", message("pair-1"))
+	assert.Equal(t, "restarting backup ha-ss-1", message("pair-0"))
+
+	// the primary waits for its new backup to be in sync
+	assert.NoError(t, c.Create(context.TODO(), pod(1, "ha-ss-new", false)))
+	rollout()
+	assert.True(t, exists("ha-ss-0"))
+	assert.Contains(t, message("pair-0"), "waiting for backup ha-ss-1 to be in sync with primary ha-ss-0")
+
+	synced = true
+	rollout()
+	assert.False(t, exists("ha-ss-0"))
+	assert.Contains(t, message("pair-0"), "restarting primary ha-ss-0")
+	assert.Equal(t, []string{"ha-ss-0", "ha-ss-0"}, syncedPods)
+
+	// the backup is live until it fails back to the restarted primary
+	backup := &v1.Pod{}
+	assert.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "ha-ss-1", Namespace: cr.Namespace}, backup))
+	backup.Status.Conditions[0].Status = v1.ConditionTrue
+	assert.NoError(t, c.Update(context.TODO(), backup))
+	rollout()
+	assert.Equal(t, "waiting for primary ha-ss-0 to start while backup ha-ss-1 is live", message("pair-0"))
+	updateHAStatus(cr, c, *namer)
+	assert.Equal(t, "ha-ss-1", cr.Status.HA[0].Live)
+	assert.Equal(t, "waiting for primary ha-ss-0 to start while backup ha-ss-1 is live", cr.Status.HA[0].Message)
+
+	// a live backup of the previous revision restarts once the primary is in sync with it
+	assert.NoError(t, c.Create(context.TODO(), pod(0, "ha-ss-new", false)))
+	backup.Labels[appsv1.ControllerRevisionHashLabelKey] = "ha-ss-old"
+	assert.NoError(t, c.Update(context.TODO(), backup))
+	syncedPods = nil
+	rollout()
+	assert.Equal(t, []string{"ha-ss-1"}, syncedPods)
+	assert.False(t, exists("ha-ss-1"))
+	assert.Equal(t, "restarting backup ha-ss-1, primary ha-ss-0 takes over", message("pair-0"))
+
+	// without a live broker there is nothing to keep serving
+	assert.NoError(t, c.Create(context.TODO(), pod(1, "ha-ss-old", false)))
+	rollout()
+	assert.False(t, exists("ha-ss-1"))
+	assert.True(t, exists("ha-ss-0"))
+	assert.Equal(t, "waiting for primary ha-ss-0 or backup ha-ss-1 to be live", message("pair-0"))
+
+	cr.Spec.HA.Enabled = false
+	updateHAStatus(cr, c, *namer)
+	assert.Nil(t, cr.Status.HA)
+	configureHAStatefulSet(cr, desired)
+	assert.Equal(t, appsv1.RollingUpdateStatefulSetStrategyType, desired.Spec.UpdateStrategy.Type)
+}
+
+func TestHASecurityCanary(t *testing.T) {
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "ha", Namespace: "ha-canary-ns"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			DeploymentPlan: brokerv1beta1.DeploymentPlanType{Size: common.Int32ToPtr(4), PersistenceEnabled: true},
+			HA:             &brokerv1beta1.HAType{Enabled: true},
+		},
+	}
+	namer := MakeNamers(cr)
+	securityName := types.NamespacedName{Name: "ha-sec", Namespace: cr.Namespace}
+	canaryLogin := "canary-login"
+	securityCR := &brokerv1beta1.ActiveMQArtemisSecurity{
+		ObjectMeta: metav1.ObjectMeta{Name: securityName.Name, Namespace: securityName.Namespace},
+		Spec: brokerv1beta1.ActiveMQArtemisSecuritySpec{
+			Canary: &brokerv1beta1.SecurityCanaryType{Enabled: true, CredentialsSecret: &canaryLogin},
+		},
+	}
+	namespaceToConfigHandler[securityName] = &ActiveMQArtemisSecurityConfigHandler{SecurityCR: securityCR, NamespacedName: securityName}
+	defer delete(namespaceToConfigHandler, securityName)
+
+	replicas := int32(4)
+	statefulSet := func(checksum string) *appsv1.StatefulSet {
+		return &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "ha-ss", Namespace: cr.Namespace, Generation: 2},
+			Spec: appsv1.StatefulSetSpec{
+				Replicas: &replicas,
+				Template: v1.PodTemplateSpec{Spec: v1.PodSpec{InitContainers: []v1.Container{{
+					Name: "ha-container-init",
+					Env:  []v1.EnvVar{{Name: securityConfigChecksumEnvVarName, Value: checksum}},
+				}}}},
+			},
+			Status: appsv1.StatefulSetStatus{ObservedGeneration: 2, UpdateRevision: "ha-ss-new"},
+		}
+	}
+	pod := func(ordinal int, revision string, ready bool) *v1.Pod {
+		status := v1.ConditionFalse
+		if ready {
+			status = v1.ConditionTrue
+		}
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "ha-ss-" + strconv.Itoa(ordinal), Namespace: cr.Namespace, Labels: map[string]string{appsv1.ControllerRevisionHashLabelKey: revision}},
+			Status:     v1.PodStatus{Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: status}}},
+		}
+	}
+	testScheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(testScheme))
+	c := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(
+		pod(0, "ha-ss-old", true), pod(1, "ha-ss-old", false), pod(2, "ha-ss-new", false), pod(3, "ha-ss-new", true)).Build()
+	var loginErr error
+	checkLogin := func(customResource *brokerv1beta1.ActiveMQArtemis, c client.Client, pod string, canary *brokerv1beta1.SecurityCanaryType) error {
+		assert.Equal(t, "ha-ss-3", pod, "the live broker of the canary pair")
+		return loginErr
+	}
+	exists := func(name string) bool {
+		return c.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: cr.Namespace}, &v1.Pod{}) == nil
+	}
+	start := time.Now()
+
+	// the change starts out at the highest pair
+	reconciler := &ActiveMQArtemisReconcilerImpl{deployed: map[reflect.Type][]client.Object{reflect.TypeOf(appsv1.StatefulSet{}): {statefulSet("previous")}}}
+	reconciler.startHASecurityCanary(cr, statefulSet("changed"), start)
+	assert.Equal(t, brokerv1beta1.SecurityCanaryVerifying, cr.Status.SecurityCanary.Phase)
+	assert.Equal(t, "ha-ss-2", cr.Status.SecurityCanary.Pod)
+
+	// the other pairs wait for the login to the live broker of the canary pair
+	reconciler = &ActiveMQArtemisReconcilerImpl{deployed: map[reflect.Type][]client.Object{reflect.TypeOf(appsv1.StatefulSet{}): {statefulSet("changed")}}}
+	loginErr = errors.New("amqp: SASL PLAIN auth failed")
+	reconciler.applyHARollout(cr, *namer, c, nil, checkLogin, start.Add(time.Minute))
+	assert.True(t, exists("ha-ss-1"))
+	assert.Equal(t, "ha-ss-3", cr.Status.SecurityCanary.Pod)
+	assert.Contains(t, cr.Status.SecurityCanary.Message, "the login check failed")
+	assert.Equal(t, "the change is held back from the other pairs until the security canary ha-ss-3 lets the login in", cr.Status.HA[0].Message)
+
+	reconciler.applyHARollout(cr, *namer, c, nil, checkLogin, start.Add(10*time.Minute))
+	assert.Equal(t, brokerv1beta1.SecurityCanaryFailed, cr.Status.SecurityCanary.Phase)
+	assert.True(t, exists("ha-ss-1"))
+
+	loginErr = nil
+	reconciler.applyHARollout(cr, *namer, c, nil, checkLogin, start.Add(11*time.Minute))
+	assert.Equal(t, brokerv1beta1.SecurityCanaryPromoted, cr.Status.SecurityCanary.Phase)
+	assert.False(t, exists("ha-ss-1"))
+}
+
+func TestHASharedStore(t *testing.T) {
+	lockTimeout := int64(30000)
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "shared", Namespace: "shared-ns"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			DeploymentPlan: brokerv1beta1.DeploymentPlanType{
+				Size:               common.Int32ToPtr(4),
+				PersistenceEnabled: true,
+				Storage:            brokerv1beta1.StorageType{Size: "5Gi", StorageClassName: "block"},
+			},
+			HA: &brokerv1beta1.HAType{
+				Enabled:     true,
+				Policy:      brokerv1beta1.HAPolicySharedStore,
+				SharedStore: &brokerv1beta1.HASharedStoreType{StorageClassName: "nfs", LockAcquisitionTimeout: &lockTimeout},
+			},
+		},
+	}
+	namer := MakeNamers(cr)
+	assert.Nil(t, validateHA(cr))
+	withVotes := cr.DeepCopy()
+	withVotes.Spec.HA.QuorumSize = common.Int32ToPtr(2)
+	assert.Equal(t, brokerv1beta1.ValidConditionInvalidHAReason, validateHA(withVotes).Reason)
+	replication := cr.DeepCopy()
+	replication.Spec.HA.Policy = brokerv1beta1.HAPolicyReplication
+	assert.NotNil(t, validateHA(replication), "sharedStore needs the SharedStore policy")
+
+	// each pair keeps its journal in its own directory of the shared volume
+	cmd := haPolicyCmd(cr, "/amq/init/config")
+	scripts := initScriptsData(cr, []string{cmd})
+	policies := scripts[haPrimaryPolicyFileName] + scripts[haBackupPolicyFileName]
+	assert.Contains(t, scripts, brokerXmlMergeScriptName)
+	assert.Contains(t, policies, "<journal-directory>/opt/shared/data/${GROUP}/journal</journal-directory>")
+	assert.Contains(t, policies, "<journal-lock-acquisition-timeout>30000</journal-lock-acquisition-timeout>")
+	assert.Contains(t, policies, "<shared-store><master><failover-on-shutdown>true</failover-on-shutdown></master></shared-store>")
+	assert.Contains(t, policies, "<shared-store><slave><allow-failback>true</allow-failback><failover-on-shutdown>true</failover-on-shutdown></slave></shared-store>")
+	assert.NotContains(t, policies, "check-for-live-server")
+
+	volumes := MakeVolumes(cr, *namer)
+	assert.Equal(t, "shared", volumes[0].Name)
+	assert.Equal(t, "shared-shared-store", volumes[0].PersistentVolumeClaim.ClaimName)
+
+	podSpec := &v1.PodSpec{}
+	configureAntiAffinityPreset(podSpec, cr)
+	assert.Len(t, podSpec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution, 1, "the pairs are kept apart without a preset")
+
+	testScheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(testScheme))
+
+	// the journals of deployed brokers don't move between the claims of the pods and the shared store
+	withClaims := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "shared-ss", Namespace: cr.Namespace}}
+	withClaims.Spec.VolumeClaimTemplates = []v1.PersistentVolumeClaim{{ObjectMeta: metav1.ObjectMeta{Name: "shared"}}}
+	withClaims.Spec.Template.Spec.Volumes = []v1.Volume{{Name: "shared", VolumeSource: v1.VolumeSource{PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "shared"}}}}
+	condition, retry := validateHAStoreChange(cr, fake.NewClientBuilder().WithScheme(testScheme).WithObjects(withClaims).Build(), testScheme)
+	assert.False(t, retry)
+	if assert.NotNil(t, condition) {
+		assert.Equal(t, brokerv1beta1.ValidConditionInvalidHAReason, condition.Reason)
+	}
+	onSharedStore := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "shared-ss", Namespace: cr.Namespace}}
+	onSharedStore.Spec.Template.Spec.Volumes = MakeVolumes(cr, *namer)
+	sharedStoreClient := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(onSharedStore).Build()
+	condition, _ = validateHAStoreChange(cr, sharedStoreClient, testScheme)
+	assert.Nil(t, condition)
+	condition, _ = validateHAStoreChange(replication, sharedStoreClient, testScheme)
+	assert.NotNil(t, condition, "the journals are on the shared store")
+	condition, _ = validateHAStoreChange(cr, fake.NewClientBuilder().WithScheme(testScheme).Build(), testScheme)
+	assert.Nil(t, condition, "nothing is deployed yet")
+	replication.Spec.HA.SharedStore = nil
+	assert.Error(t, cr.ValidateUpdate(replication))
+	assert.Error(t, replication.ValidateUpdate(cr))
+	assert.NoError(t, cr.ValidateUpdate(cr.DeepCopy()))
+
+	c := fake.NewClientBuilder().WithScheme(testScheme).Build()
+	reconciler := &ActiveMQArtemisReconcilerImpl{}
+	claim := func() *v1.PersistentVolumeClaim {
+		claim := &v1.PersistentVolumeClaim{}
+		assert.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "shared-shared-store", Namespace: cr.Namespace}, claim))
+		return claim
+	}
+	reconciler.applyHASharedStore(cr, *namer, c)
+	created := claim()
+	assert.Empty(t, created.OwnerReferences, "the journals outlive the cr")
+	assert.Equal(t, []v1.PersistentVolumeAccessMode{v1.ReadWriteMany}, created.Spec.AccessModes)
+	assert.Equal(t, "nfs", *created.Spec.StorageClassName)
+	assert.Equal(t, "10Gi", created.Spec.Resources.Requests.Storage().String(), "the storage of each pair")
+
+	cr.Spec.DeploymentPlan.Size = common.Int32ToPtr(6)
+	reconciler.applyHASharedStore(cr, *namer, c)
+	assert.Equal(t, "15Gi", claim().Spec.Resources.Requests.Storage().String())
+	cr.Spec.DeploymentPlan.Size = common.Int32ToPtr(2)
+	reconciler.applyHASharedStore(cr, *namer, c)
+	assert.Equal(t, "15Gi", claim().Spec.Resources.Requests.Storage().String(), "a claim never shrinks")
+
+	// the primary restarts without a replica sync once its backup is up
+	replicas := int32(2)
+	deployed := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "shared-ss", Namespace: cr.Namespace},
+		Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+		Status:     appsv1.StatefulSetStatus{UpdateRevision: "shared-ss-new"},
+	}
+	primary := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "shared-ss-0", Namespace: cr.Namespace, Labels: map[string]string{appsv1.ControllerRevisionHashLabelKey: "shared-ss-old"}},
+		Status:     v1.PodStatus{Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}}},
+	}
+	backup := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "shared-ss-1", Namespace: cr.Namespace, Labels: map[string]string{appsv1.ControllerRevisionHashLabelKey: "shared-ss-new"}},
+	}
+	c = fake.NewClientBuilder().WithScheme(testScheme).WithObjects(primary, backup).Build()
+	reconciler.deployed = map[reflect.Type][]client.Object{reflect.TypeOf(appsv1.StatefulSet{}): {deployed}}
+	reconciler.applyHARollout(cr, *namer, c, func(*brokerv1beta1.ActiveMQArtemis, client.Client, string) (bool, error) {
+		t.Fatal("a shared store has no replica to sync")
+		return false, nil
+	}, nil, time.Now())
+	assert.True(t, k8serrors.IsNotFound(c.Get(context.TODO(), types.NamespacedName{Name: "shared-ss-0", Namespace: cr.Namespace}, &v1.Pod{})))
+	assert.Equal(t, "restarting primary shared-ss-0, backup shared-ss-1 takes over until it fails back", cr.Status.HA[0].Message)
+}
+
+func TestHAZooKeeperQuorum(t *testing.T) {
+	sessionTimeout := int32(12000)
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "zk", Namespace: "zk-ns"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			DeploymentPlan: brokerv1beta1.DeploymentPlanType{Size: common.Int32ToPtr(2), PersistenceEnabled: true},
+			HA: &brokerv1beta1.HAType{
+				Enabled: true,
+				ZooKeeper: &brokerv1beta1.HAZooKeeperType{
+					ConnectStringSecret: &v1.SecretKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "zk-connect"}, Key: "connect"},
+					SessionTimeout:      &sessionTimeout,
+				},
+			},
+		},
+	}
+	assert.Nil(t, validateHA(cr))
+	for _, invalid := range []func(*brokerv1beta1.ActiveMQArtemis){
+		func(c *brokerv1beta1.ActiveMQArtemis) { c.Spec.HA.ZooKeeper.ConnectString = "zk:2181" },
+		func(c *brokerv1beta1.ActiveMQArtemis) { c.Spec.HA.ZooKeeper.ConnectStringSecret = nil },
+		func(c *brokerv1beta1.ActiveMQArtemis) { c.Spec.HA.QuorumSize = common.Int32ToPtr(2) },
+		func(c *brokerv1beta1.ActiveMQArtemis) { c.Spec.HA.Policy = brokerv1beta1.HAPolicySharedStore },
+	} {
+		c := cr.DeepCopy()
+		invalid(c)
+		condition := validateHA(c)
+		if assert.NotNil(t, condition) {
+			assert.Equal(t, brokerv1beta1.ValidConditionInvalidHAReason, condition.Reason)
+		}
+	}
+
+	testScheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(testScheme))
+	condition, retry := validateHAZooKeeper(cr, fake.NewClientBuilder().WithScheme(testScheme).Build(), testScheme)
+	if assert.NotNil(t, condition) {
+		assert.Equal(t, brokerv1beta1.ValidConditionMissingResourcesReason, condition.Reason)
+		assert.True(t, retry, "the secret may be created after the cr")
+	}
+	secret := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "zk-connect", Namespace: "zk-ns"}, Data: map[string][]byte{"connect": []byte("zk-0:2181,zk-1:2181,zk-2:2181")}}
+	condition, _ = validateHAZooKeeper(cr, fake.NewClientBuilder().WithScheme(testScheme).WithObjects(secret).Build(), testScheme)
+	assert.Nil(t, condition)
+
+	// the pair holds a lock in the ensemble, the connect string only reaches the xml through the env of the init container
+	cmd := haPolicyCmd(cr, "/amq/init/config")
+	scripts := initScriptsData(cr, []string{cmd})
+	policies := scripts[haPrimaryPolicyFileName] + scripts[haBackupPolicyFileName]
+	assert.Contains(t, scripts, brokerXmlMergeScriptName)
+	assert.Contains(t, policies, "<class-name>org.apache.activemq.artemis.quorum.zookeeper.CuratorDistributedPrimitiveManager</class-name>")
+	assert.Contains(t, policies, "<property key='connect-string' value='${HA_ZOOKEEPER_CONNECT_STRING}'/><property key='namespace' value='zk-ns-zk'/><property key='session-ms' value='12000'/>")
+	assert.Contains(t, policies, "<group-name>${GROUP}</group-name><coordination-id>${GROUP}</coordination-id></primary>")
+	assert.Contains(t, policies, "<group-name>${GROUP}</group-name><allow-failback>true</allow-failback></backup>")
+	assert.NotContains(t, policies, "<master>")
+	assert.Equal(t, "zk-connect", haZooKeeperConnectString(cr).ValueFrom.SecretKeyRef.Name)
+
+	existing := cr.DeepCopy()
+	existing.Spec.HA.ZooKeeper.ConnectStringSecret = nil
+	existing.Spec.HA.ZooKeeper.Service = &brokerv1beta1.HAZooKeeperServiceType{Name: "zookeeper", Namespace: "infra"}
+	assert.Nil(t, validateHA(existing))
+	assert.Equal(t, "zookeeper.infra.svc:2181", haZooKeeperConnectString(existing).Value)
+	existing.Spec.HA.ZooKeeper.Service.Namespace = ""
+	condition, _ = validateHAZooKeeper(existing, fake.NewClientBuilder().WithScheme(testScheme).Build(), testScheme)
+	assert.NotNil(t, condition, "a service in the namespace of the cr is checked")
+
+	withoutHA := cr.DeepCopy()
+	withoutHA.Spec.HA.Enabled = false
+	assert.Nil(t, haZooKeeperConnectString(withoutHA))
+}
@@ -0,0 +1,141 @@
+package controllers
+
+import (
+	"encoding/hex"
+	"reflect"
+	"strings"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/resources/configmaps"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/resources/environments"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/resources/volumes"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// the scripts and files of the init commands are mounted from a configmap rather than written by
+// the shell, their content never goes through its quoting and expansion
+const (
+	initScriptsDir                = "/amq/init/scripts"
+	initScriptsSuffix             = "-init-scripts"
+	initScriptsVolumeName         = "init-scripts"
+	initScriptsChecksumEnvVarName = "INIT_SCRIPTS_CHECKSUM"
+	brokerXmlMergeScriptName      = "merge-broker-xml.py"
+	bindInterfacesScriptName      = "bind-interfaces.py"
+	consoleBootstrapScriptName    = "console-bootstrap.py"
+	credentialsSourceScriptName   = "credentials-source.py"
+	saslLoginConfigScriptName     = "sasl-login-config.py"
+	expandEnvScriptName           = "expand-env.py"
+	securitySecretsScriptName     = "security-secrets.py"
+	propertiesCodecScriptName     = "properties-codec.py"
+	clusterMeshScriptName         = "cluster-mesh.py"
+	haPrimaryPolicyFileName       = "ha-primary.xml"
+	haBackupPolicyFileName        = "ha-backup.xml"
+	jolokiaAccessFileName         = "jolokia-access.xml"
+)
+
+// expands the ${VAR} references of a file with the environment of the init container,
+// usage: expand-env.py <file> <expanded file>
+var expandEnvScript = `import os, sys
+
+with open(sys.argv[1]) as f:
+    content = os.path.expandvars(f.read())
+with open(sys.argv[2], 'w') as out:
+    out.write(content)
+`
+
+// replaces the secret:// references of the security config with the keys mounted from the secrets,
+// a secret+enc:// reference is a one-way hash the broker checks passwords against with the default codec,
+// usage: security-secrets.py <file>
+var securitySecretsScript = `import json, re, sys
+
+def resolve(match):
+    with open(match.group(2)) as f:
+        value = f.read().strip()
+    if match.group(1) and not value.startswith('ENC('):
+        value = 'ENC(' + value + ')'
+    return json.dumps(value)
+
+with open(sys.argv[1]) as f:
+    content = f.read()
+with open(sys.argv[1], 'w') as out:
+    out.write(re.sub(r"'?secret(\+enc)?://(/[^\s']+)'?", resolve, content))
+`
+
+var initScripts = map[string]string{
+	brokerXmlMergeScriptName:    brokerXmlMergeScript,
+	bindInterfacesScriptName:    bindInterfacesScript,
+	consoleBootstrapScriptName:  consoleBootstrapScript,
+	credentialsSourceScriptName: credentialsSourceScript,
+	saslLoginConfigScriptName:   saslLoginConfigScript,
+	expandEnvScriptName:         expandEnvScript,
+	securitySecretsScriptName:   securitySecretsScript,
+	propertiesCodecScriptName:   propertiesCodecScript,
+	clusterMeshScriptName:       clusterMeshScript,
+}
+
+func initScriptPath(name string) string {
+	return initScriptsDir + "/" + name
+}
+
+func getInitScriptsResourceName(customResource *brokerv1beta1.ActiveMQArtemis) string {
+	return customResource.Name + initScriptsSuffix
+}
+
+// initScriptsData holds the scripts the init commands run and the files the CR renders for them
+func initScriptsData(customResource *brokerv1beta1.ActiveMQArtemis, initCmds []string) map[string]string {
+	data := map[string]string{}
+	for _, cmd := range initCmds {
+		for name, script := range initScripts {
+			if strings.Contains(cmd, initScriptPath(name)) {
+				data[name] = script
+			}
+		}
+	}
+	if isHAEnabled(customResource) {
+		data[haPrimaryPolicyFileName] = haPolicyXml(customResource, true)
+		data[haBackupPolicyFileName] = haPolicyXml(customResource, false)
+	}
+	if policy := jolokiaAccessPolicy(customResource); policy != "" {
+		data[jolokiaAccessFileName] = policy
+	}
+	return data
+}
+
+// the configmap is only mounted when the init commands need it, a change of its content rolls the
+// pods through the checksum in the init container env
+func (reconciler *ActiveMQArtemisReconcilerImpl) addResourceForInitScripts(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, initCmds []string, podSpec *corev1.PodSpec) {
+	data := initScriptsData(customResource, initCmds)
+	if len(data) == 0 {
+		return
+	}
+
+	name := getInitScriptsResourceName(customResource)
+	var desired *corev1.ConfigMap
+	if obj := reconciler.cloneOfDeployed(reflect.TypeOf(corev1.ConfigMap{}), name); obj != nil {
+		desired = obj.(*corev1.ConfigMap)
+	} else {
+		desired = configmaps.MakeConfigMap(customResource.Namespace, name, nil)
+		desired.Labels = namer.LabelBuilder.Labels()
+	}
+	desired.Data = data
+	reconciler.trackDesired(desired)
+
+	volume := volumes.MakeVolumeForConfigMap(name)
+	volume.Name = initScriptsVolumeName
+	podSpec.Volumes = append(podSpec.Volumes, volume)
+	podSpec.InitContainers[0].VolumeMounts = append(podSpec.InitContainers[0].VolumeMounts, volumes.MakeVolumeMountForCfg(initScriptsVolumeName, initScriptsDir, true))
+
+	checksum := corev1.EnvVar{
+		Name:  initScriptsChecksumEnvVarName,
+		Value: hex.EncodeToString(alder32Of(sortedValues(data))),
+	}
+	environments.Create(podSpec.InitContainers, &checksum)
+}
+
+func sortedValues(data map[string]string) []string {
+	values := []string{}
+	for _, key := range sortedKeys(data) {
+		values = append(values, key, data[key])
+	}
+	return values
+}
@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"testing"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/resources/environments"
+	"github.com/stretchr/testify/assert"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestInitScriptsConfigMap(t *testing.T) {
+	reconciler := &ActiveMQArtemisReconcilerImpl{}
+
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: "test"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			BrokerXmlConfigMap: &v1.ConfigMapKeySelector{
+				LocalObjectReference: v1.LocalObjectReference{Name: "my-broker-xml"},
+				Key:                  "broker.xml",
+			},
+		},
+	}
+
+	newSpec, err := reconciler.NewPodTemplateSpecForCR(cr, Namers{}, &v1.PodTemplateSpec{}, nil)
+	assert.NoError(t, err)
+
+	var scripts *v1.ConfigMap
+	for _, obj := range reconciler.requestedResources {
+		if configMap, ok := obj.(*v1.ConfigMap); ok && configMap.Name == "broker-init-scripts" {
+			scripts = configMap
+		}
+	}
+	assert.NotNil(t, scripts)
+	assert.Equal(t, brokerXmlMergeScript, scripts.Data[brokerXmlMergeScriptName])
+	// only the scripts the init commands run are shipped
+	assert.NotContains(t, scripts.Data, bindInterfacesScriptName)
+
+	mounted := false
+	for _, mount := range newSpec.Spec.InitContainers[0].VolumeMounts {
+		if mount.Name == initScriptsVolumeName && mount.MountPath == initScriptsDir && mount.ReadOnly {
+			mounted = true
+		}
+	}
+	assert.True(t, mounted)
+	assert.NotNil(t, environments.Retrieve(newSpec.Spec.InitContainers, initScriptsChecksumEnvVarName))
+
+	// a change of a script rolls the pods
+	checksum := environments.Retrieve(newSpec.Spec.InitContainers, initScriptsChecksumEnvVarName).Value
+	cr.Spec.Console.Jolokia = &brokerv1beta1.JolokiaType{AllowedOrigins: []string{"*://console.example.com*"}}
+	reconciler = &ActiveMQArtemisReconcilerImpl{}
+	newSpec, err = reconciler.NewPodTemplateSpecForCR(cr, Namers{}, &v1.PodTemplateSpec{}, nil)
+	assert.NoError(t, err)
+	assert.NotEqual(t, checksum, environments.Retrieve(newSpec.Spec.InitContainers, initScriptsChecksumEnvVarName).Value)
+
+	plain := &brokerv1beta1.ActiveMQArtemis{ObjectMeta: metav1.ObjectMeta{Name: "plain", Namespace: "test"}}
+	reconciler = &ActiveMQArtemisReconcilerImpl{}
+	newSpec, err = reconciler.NewPodTemplateSpecForCR(plain, Namers{}, &v1.PodTemplateSpec{}, nil)
+	assert.NoError(t, err)
+	for _, volume := range newSpec.Spec.Volumes {
+		assert.NotEqual(t, initScriptsVolumeName, volume.Name)
+	}
+	assert.Nil(t, environments.Retrieve(newSpec.Spec.InitContainers, initScriptsChecksumEnvVarName))
+}
@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	"reflect"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// the jvm resolves host names to ipv4 addresses first unless told otherwise, which leaves a dual-stack
+// broker advertising ipv4 on a cluster whose services prefer ipv6
+const preferIPv6JavaArg = "-Djava.net.preferIPv6Addresses=true"
+
+func isIPv6Primary(customResource *brokerv1beta1.ActiveMQArtemis) bool {
+	return len(customResource.Spec.IPFamilies) > 0 && customResource.Spec.IPFamilies[0] == corev1.IPv6Protocol
+}
+
+// the address an acceptor with bindToAllInterfaces listens on, the ipv6 wildcard accepts ipv4 too on a
+// dual-stack pod
+func wildcardBindAddress(customResource *brokerv1beta1.ActiveMQArtemis) string {
+	if isIPv6Primary(customResource) {
+		return "[::]"
+	}
+	return "0.0.0.0"
+}
+
+// the families of a service are immutable, so they are only set when the service is created. A
+// service that exists keeps the ones it was created with, the API server defaulted them when the CR
+// had none
+func (reconciler *ActiveMQArtemisReconcilerImpl) configureIPFamilies(customResource *brokerv1beta1.ActiveMQArtemis, service *corev1.Service) {
+	if obj := reconciler.getFromDeployed(reflect.TypeOf(corev1.Service{}), service.Name); obj != nil {
+		deployed := obj.(*corev1.Service)
+		service.Spec.IPFamilies = append([]corev1.IPFamily(nil), deployed.Spec.IPFamilies...)
+		service.Spec.IPFamilyPolicy = deployed.Spec.IPFamilyPolicy
+		return
+	}
+	if len(customResource.Spec.IPFamilies) > 0 {
+		service.Spec.IPFamilies = append([]corev1.IPFamily{}, customResource.Spec.IPFamilies...)
+	}
+	if customResource.Spec.IPFamilyPolicy != nil {
+		policy := *customResource.Spec.IPFamilyPolicy
+		service.Spec.IPFamilyPolicy = &policy
+	}
+}
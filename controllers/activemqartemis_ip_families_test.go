@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"reflect"
+	"testing"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIPFamilies(t *testing.T) {
+	bindToAll := true
+	requireDualStack := v1.IPFamilyPolicyRequireDualStack
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: "ipv6"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			IPFamilies:     []v1.IPFamily{v1.IPv6Protocol, v1.IPv4Protocol},
+			IPFamilyPolicy: &requireDualStack,
+			Acceptors: []brokerv1beta1.AcceptorType{
+				{Name: "all", Port: 61617, BindToAllInterfaces: &bindToAll},
+				{Name: "data", Port: 61618, BindInterface: "net1"},
+			},
+			Console: brokerv1beta1.ConsoleType{Expose: true},
+		},
+	}
+	assert.Nil(t, validateIPFamilies(cr))
+
+	acceptors := generateAcceptorsString(cr, Namers{}, k8sClient, nil)
+	assert.Contains(t, acceptors, "tcp:\\/\\/[::]:61617?")
+
+	reconciler := &ActiveMQArtemisReconcilerImpl{}
+	newSpec, err := reconciler.NewPodTemplateSpecForCR(cr, Namers{}, &v1.PodTemplateSpec{}, k8sClient)
+	assert.NoError(t, err)
+	assert.Contains(t, newSpec.Spec.Containers[0].Env, v1.EnvVar{Name: "JAVA_ARGS_APPEND", Value: preferIPv6JavaArg})
+	assert.Contains(t, newSpec.Spec.InitContainers[0].Args[1], "python3 /amq/init/scripts/bind-interfaces.py -6 /amq/init/config/etc/broker.xml net1")
+
+	reconciler.configureConsoleExposure(cr, *MakeNamers(cr), fake.NewClientBuilder().Build(), nil)
+	services := 0
+	for _, obj := range reconciler.requestedResources {
+		if service, ok := obj.(*v1.Service); ok {
+			services++
+			assert.Equal(t, []v1.IPFamily{v1.IPv6Protocol, v1.IPv4Protocol}, service.Spec.IPFamilies)
+			assert.Equal(t, v1.IPFamilyPolicyRequireDualStack, *service.Spec.IPFamilyPolicy)
+		}
+	}
+	assert.Equal(t, 1, services)
+
+	// a service that exists keeps the families it was created with
+	singleStack := v1.IPFamilyPolicySingleStack
+	deployed := &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "broker-wconsj-0-svc", Namespace: "ipv6"}}
+	deployed.Spec.IPFamilies = []v1.IPFamily{v1.IPv4Protocol}
+	deployed.Spec.IPFamilyPolicy = &singleStack
+	reconciler = &ActiveMQArtemisReconcilerImpl{deployed: map[reflect.Type][]rtclient.Object{}}
+	reconciler.addToDeployed(reflect.TypeOf(v1.Service{}), deployed)
+	reconciler.configureConsoleExposure(cr, *MakeNamers(cr), fake.NewClientBuilder().Build(), nil)
+	for _, obj := range reconciler.requestedResources {
+		if service, ok := obj.(*v1.Service); ok {
+			assert.Equal(t, []v1.IPFamily{v1.IPv4Protocol}, service.Spec.IPFamilies)
+			assert.Equal(t, v1.IPFamilyPolicySingleStack, *service.Spec.IPFamilyPolicy)
+		}
+	}
+
+	// without families the brokers bind and resolve like before
+	cr.Spec.IPFamilies = nil
+	assert.Contains(t, generateAcceptorsString(cr, Namers{}, k8sClient, nil), "tcp:\\/\\/0.0.0.0:61617?")
+
+	for _, invalid := range []struct {
+		families []v1.IPFamily
+		policy   v1.IPFamilyPolicyType
+	}{
+		{[]v1.IPFamily{v1.IPv4Protocol, v1.IPv6Protocol}, v1.IPFamilyPolicySingleStack},
+		{[]v1.IPFamily{v1.IPv6Protocol, v1.IPv6Protocol}, v1.IPFamilyPolicyPreferDualStack},
+		{[]v1.IPFamily{"IPv5"}, ""},
+		{[]v1.IPFamily{v1.IPv4Protocol}, "DualStack"},
+		{[]v1.IPFamily{v1.IPv4Protocol, v1.IPv6Protocol}, ""},
+	} {
+		cr.Spec.IPFamilies = invalid.families
+		cr.Spec.IPFamilyPolicy = nil
+		if invalid.policy != "" {
+			policy := invalid.policy
+			cr.Spec.IPFamilyPolicy = &policy
+		}
+		condition := validateIPFamilies(cr)
+		assert.NotNil(t, condition, invalid)
+		assert.Equal(t, brokerv1beta1.ValidConditionInvalidIPFamiliesReason, condition.Reason)
+	}
+}
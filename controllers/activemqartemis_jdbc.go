@@ -0,0 +1,155 @@
+package controllers
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/resources/volumes"
+)
+
+// where a jdbc driver image is expected to hold its jars
+var defaultJdbcDriverPath = "/opt/jdbc"
+
+// where the jdbc driver jars are copied to, it is added to the broker classpath with artemis.extra.libs
+var jdbcDriverLibDir = brokerConfigRoot + "/lib"
+
+const jdbcConnectionChecksumEnvVarName = "JDBC_CONNECTION_CHECKSUM"
+
+var jdbcTableNames = []struct {
+	property    string
+	defaultName string
+}{
+	{"bindingsTableName", "BINDINGS"},
+	{"messageTableName", "MESSAGES"},
+	{"largeMessageTableName", "LARGE_MESSAGES"},
+	{"pageStoreTableName", "PAGE_STORE"},
+	{"nodeManagerStoreTableName", "NODE_MANAGER_STORE"},
+}
+
+func isJdbcPersistence(customResource *brokerv1beta1.ActiveMQArtemis) bool {
+	return customResource.Spec.DeploymentPlan.Persistence.JDBC != nil
+}
+
+func jdbcStoreProperties(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client) []string {
+	if !isJdbcPersistence(customResource) {
+		return nil
+	}
+	jdbc := customResource.Spec.DeploymentPlan.Persistence.JDBC
+
+	props := []string{
+		"storeConfiguration=DATABASE",
+		"storeConfiguration.jdbcDriverClassName=" + jdbc.DriverClassName,
+	}
+
+	connectionUrl, err := getJdbcConnectionUrl(customResource, client)
+	if err != nil {
+		clog.Error(err, "unable to resolve jdbc connection url", "secret", jdbc.ConnectionUrlSecret.Name)
+	} else {
+		props = append(props, "storeConfiguration.jdbcConnectionUrl="+connectionUrl)
+	}
+
+	if jdbc.TablePrefix != "" {
+		for _, table := range jdbcTableNames {
+			props = append(props, fmt.Sprintf("storeConfiguration.%s=%s%s", table.property, jdbc.TablePrefix, table.defaultName))
+		}
+	}
+
+	for _, key := range sortedKeys(jdbc.DataSourceProperties) {
+		props = append(props, fmt.Sprintf("storeConfiguration.dataSourceProperties.%s=%s", key, jdbc.DataSourceProperties[key]))
+	}
+	return props
+}
+
+func getJdbcConnectionUrl(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client) (string, error) {
+	secretKey := customResource.Spec.DeploymentPlan.Persistence.JDBC.ConnectionUrlSecret
+	if client == nil {
+		return "", fmt.Errorf("no client to retrieve secret %v", secretKey.Name)
+	}
+
+	secret := &corev1.Secret{}
+	if err := client.Get(context.TODO(), types.NamespacedName{Name: secretKey.Name, Namespace: customResource.Namespace}, secret); err != nil {
+		return "", err
+	}
+	value, found := secret.Data[secretKey.Key]
+	if !found {
+		return "", fmt.Errorf("secret %v has no key %v", secretKey.Name, secretKey.Key)
+	}
+	return strings.TrimSpace(string(value)), nil
+}
+
+func jdbcConnectionChecksum(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client) string {
+	connectionUrl, err := getJdbcConnectionUrl(customResource, client)
+	if err != nil {
+		clog.V(1).Info("unable to resolve jdbc connection url", "error", err)
+		return ""
+	}
+	return hex.EncodeToString(alder32Of([]string{connectionUrl}))
+}
+
+func makeJdbcDriverInitContainer(customResource *brokerv1beta1.ActiveMQArtemis, cfgVolumeName string) *corev1.Container {
+	jdbc := customResource.Spec.DeploymentPlan.Persistence.JDBC
+	if jdbc == nil || jdbc.DriverImage == "" {
+		return nil
+	}
+
+	driverPath := jdbc.DriverPath
+	if driverPath == "" {
+		driverPath = defaultJdbcDriverPath
+	}
+	libDir := jdbcDriverLibDir
+
+	return &corev1.Container{
+		Name:            customResource.Name + "-jdbc-driver",
+		Image:           jdbc.DriverImage,
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Command:         []string{"/bin/sh", "-c"},
+		Args:            []string{"mkdir -p " + libDir + " && cp -r " + driverPath + "/. " + libDir + "/"},
+		Resources:       customResource.Spec.DeploymentPlan.Resources,
+		VolumeMounts: []corev1.VolumeMount{
+			volumes.MakeRwVolumeMountForCfg(cfgVolumeName, brokerConfigRoot),
+		},
+	}
+}
+
+func validateJdbcPersistence(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, scheme *runtime.Scheme) (*metav1.Condition, bool) {
+
+	jdbc := customResource.Spec.DeploymentPlan.Persistence.JDBC
+	if jdbc.DriverClassName == "" || jdbc.ConnectionUrlSecret.Name == "" || jdbc.ConnectionUrlSecret.Key == "" {
+		return &metav1.Condition{
+			Type:    brokerv1beta1.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  brokerv1beta1.ValidConditionFailedPersistenceReason,
+			Message: ".Spec.DeploymentPlan.Persistence.JDBC requires a driverClassName and a connectionUrlSecret name and key",
+		}, false
+	}
+
+	secret := corev1.Secret{}
+	found := retrieveResource(jdbc.ConnectionUrlSecret.Name, customResource.Namespace, &secret, client, scheme)
+	if !found {
+		return &metav1.Condition{
+			Type:    brokerv1beta1.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  brokerv1beta1.ValidConditionMissingResourcesReason,
+			Message: fmt.Sprintf(".Spec.DeploymentPlan.Persistence.JDBC missing required secret %v", jdbc.ConnectionUrlSecret.Name),
+		}, true
+	}
+
+	if _, present := secret.Data[jdbc.ConnectionUrlSecret.Key]; !present {
+		return &metav1.Condition{
+			Type:    brokerv1beta1.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  brokerv1beta1.ValidConditionFailedPersistenceReason,
+			Message: fmt.Sprintf(".Spec.DeploymentPlan.Persistence.JDBC secret %v must have key %v", secret.Name, jdbc.ConnectionUrlSecret.Key),
+		}, true
+	}
+	return nil, false
+}
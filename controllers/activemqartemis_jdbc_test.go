@@ -0,0 +1,131 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/resources/environments"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestJdbcStoreProperties(t *testing.T) {
+	urlSecret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "jdbc-url",
+			Namespace: "test",
+		},
+		Data: map[string][]byte{
+			"url": []byte("jdbc:postgresql://db:5432/artemis\n"),
+		},
+	}
+	client := fake.NewClientBuilder().WithObjects(urlSecret).Build()
+
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "jdbc",
+			Namespace: "test",
+		},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			DeploymentPlan: brokerv1beta1.DeploymentPlanType{
+				PersistenceEnabled: true,
+				Persistence: brokerv1beta1.PersistenceType{
+					JDBC: &brokerv1beta1.JDBCPersistenceType{
+						DriverClassName: "org.postgresql.Driver",
+						ConnectionUrlSecret: v1.SecretKeySelector{
+							LocalObjectReference: v1.LocalObjectReference{Name: "jdbc-url"},
+							Key:                  "url",
+						},
+						TablePrefix:          "B1_",
+						DataSourceProperties: map[string]string{"maxTotal": "10"},
+					},
+				},
+			},
+		},
+	}
+
+	props := jdbcStoreProperties(cr, client)
+	assert.Contains(t, props, "storeConfiguration=DATABASE")
+	assert.Contains(t, props, "storeConfiguration.jdbcDriverClassName=org.postgresql.Driver")
+	assert.Contains(t, props, "storeConfiguration.jdbcConnectionUrl=jdbc:postgresql://db:5432/artemis")
+	assert.Contains(t, props, "storeConfiguration.messageTableName=B1_MESSAGES")
+	assert.Contains(t, props, "storeConfiguration.dataSourceProperties.maxTotal=10")
+
+	assert.False(t, requiresPersistentVolume(cr))
+	assert.Empty(t, MakeVolumes(cr, Namers{}))
+
+	cr.Spec.DeploymentPlan.Persistence.JDBC = nil
+	assert.Nil(t, jdbcStoreProperties(cr, client))
+	assert.True(t, requiresPersistentVolume(cr))
+}
+
+func TestNewPodTemplateSpecForCR_AddsJdbcDriverInitContainer(t *testing.T) {
+	reconciler := &ActiveMQArtemisReconcilerImpl{}
+
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "jdbc",
+		},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			DeploymentPlan: brokerv1beta1.DeploymentPlanType{
+				Persistence: brokerv1beta1.PersistenceType{
+					JDBC: &brokerv1beta1.JDBCPersistenceType{
+						DriverClassName: "org.postgresql.Driver",
+						DriverImage:     "quay.io/example/postgresql-driver:latest",
+					},
+				},
+			},
+		},
+	}
+
+	newSpec, err := reconciler.NewPodTemplateSpecForCR(cr, Namers{}, &v1.PodTemplateSpec{}, nil)
+
+	assert.NoError(t, err)
+	assert.Len(t, newSpec.Spec.InitContainers, 2)
+	driverContainer := newSpec.Spec.InitContainers[1]
+	assert.Equal(t, "quay.io/example/postgresql-driver:latest", driverContainer.Image)
+	assert.Equal(t, []string{"mkdir -p /amq/init/config/lib && cp -r /opt/jdbc/. /amq/init/config/lib/"}, driverContainer.Args)
+	javaArgs := environments.Retrieve(newSpec.Spec.Containers, "JAVA_ARGS_APPEND")
+	assert.NotNil(t, javaArgs)
+	assert.Contains(t, javaArgs.Value, "-Dartemis.extra.libs=/amq/init/config/lib")
+}
+
+func TestJdbcConnectionChecksum(t *testing.T) {
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "jdbc", Namespace: "test"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			DeploymentPlan: brokerv1beta1.DeploymentPlanType{
+				Persistence: brokerv1beta1.PersistenceType{
+					JDBC: &brokerv1beta1.JDBCPersistenceType{
+						DriverClassName:     "org.postgresql.Driver",
+						ConnectionUrlSecret: v1.SecretKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "db"}, Key: "url"},
+					},
+				},
+			},
+		},
+	}
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "test"},
+		Data:       map[string][]byte{"url": []byte("jdbc:postgresql://db:5432/artemis?user=a&password=one")},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithObjects(cr, secret).Build()
+
+	reconciler := &ActiveMQArtemisReconcilerImpl{}
+	newSpec, err := reconciler.NewPodTemplateSpecForCR(cr, Namers{}, &v1.PodTemplateSpec{}, fakeClient)
+	assert.NoError(t, err)
+	checksum := environments.Retrieve(newSpec.Spec.InitContainers, jdbcConnectionChecksumEnvVarName)
+	assert.NotNil(t, checksum)
+	assert.NotEmpty(t, checksum.Value)
+
+	// a rotated password changes the pod template
+	secret.Data["url"] = []byte("jdbc:postgresql://db:5432/artemis?user=a&password=two")
+	assert.NoError(t, fakeClient.Update(context.TODO(), secret))
+	assert.NotEqual(t, checksum.Value, jdbcConnectionChecksum(cr, fakeClient))
+
+	r := &ActiveMQArtemisReconciler{Client: withSecretNamesIndex(t, fakeClient)}
+	assert.Len(t, r.brokersUsingSecret(secret), 1)
+}
@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	ss "github.com/artemiscloud/activemq-artemis-operator/pkg/resources/statefulsets"
+	jc "github.com/artemiscloud/activemq-artemis-operator/pkg/utils/jolokia_client"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// the management operation live logging levels need from a broker
+type loggerLevelSetter interface {
+	SetLoggerLevel(category string, level string) (bool, error)
+}
+
+func loggerLevelSetters(cr *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, namer Namers) map[string]loggerLevelSetter {
+	resource := types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace}
+	setters := map[string]loggerLevelSetter{}
+	for _, jk := range jc.GetBrokers(resource, ss.GetDeployedStatefulSetNames(client, []types.NamespacedName{resource}), client) {
+		setters[namer.SsNameBuilder.Name()+"-"+jk.Ordinal] = jk.Artemis
+	}
+	return setters
+}
+
+// the logging secret reaches the pods once the kubelet syncs it and log4j2 rereads it, the levels
+// of the configured loggers are changed through the management api right away. They are pushed
+// once per generation, a pod that starts later reads them from the file
+func updateLoggingLevels(cr *brokerv1beta1.ActiveMQArtemis, brokers func() map[string]loggerLevelSetter) {
+
+	if cr.Spec.Logging == nil || len(cr.Spec.Logging.Levels) == 0 {
+		meta.RemoveStatusCondition(&cr.Status.Conditions, brokerv1beta1.LoggingLevelsAppliedConditionType)
+		return
+	}
+
+	applied := meta.FindStatusCondition(cr.Status.Conditions, brokerv1beta1.LoggingLevelsAppliedConditionType)
+	if applied != nil && applied.Status == metav1.ConditionTrue && applied.ObservedGeneration == cr.Generation {
+		return
+	}
+
+	setters := brokers()
+	pods := make([]string, 0, len(setters))
+	for pod := range setters {
+		pods = append(pods, pod)
+	}
+	sort.Strings(pods)
+
+	failed := []string{}
+	for _, pod := range pods {
+		for _, category := range sortedKeys(cr.Spec.Logging.Levels) {
+			// a logger that is not configured yet comes with the reload of the file
+			if _, err := setters[pod].SetLoggerLevel(category, strings.ToUpper(cr.Spec.Logging.Levels[category])); err != nil {
+				failed = append(failed, fmt.Sprintf("%v %v: %v", pod, category, err))
+			}
+		}
+	}
+
+	// unknown rather than false, the levels still come with the reload of the file
+	if len(failed) == 0 {
+		meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+			Type:               brokerv1beta1.LoggingLevelsAppliedConditionType,
+			Status:             metav1.ConditionTrue,
+			Reason:             brokerv1beta1.LoggingLevelsAppliedConditionReason,
+			Message:            fmt.Sprintf("logger levels applied to %d brokers", len(pods)),
+			ObservedGeneration: cr.Generation,
+		})
+	} else {
+		meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+			Type:               brokerv1beta1.LoggingLevelsAppliedConditionType,
+			Status:             metav1.ConditionUnknown,
+			Reason:             brokerv1beta1.LoggingLevelsApplyingConditionReason,
+			Message:            "unable to set " + strings.Join(failed, ", "),
+			ObservedGeneration: cr.Generation,
+		})
+	}
+}
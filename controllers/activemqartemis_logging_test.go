@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"errors"
+	"testing"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type fakeLoggerLevelSetter struct {
+	levels map[string]string
+	fail   bool
+}
+
+func (s *fakeLoggerLevelSetter) SetLoggerLevel(category string, level string) (bool, error) {
+	if s.fail {
+		return false, errors.New("unreachable")
+	}
+	s.levels[category] = level
+	return true, nil
+}
+
+func TestLoggingLevels(t *testing.T) {
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "logging", Namespace: "test", Generation: 1},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			Logging: &brokerv1beta1.LoggingType{Levels: map[string]string{"org.apache.activemq.artemis.core.server": "debug"}},
+		},
+	}
+	setter := &fakeLoggerLevelSetter{levels: map[string]string{}}
+	calls := 0
+	brokers := func() map[string]loggerLevelSetter {
+		calls++
+		return map[string]loggerLevelSetter{"logging-ss-0": setter}
+	}
+
+	updateLoggingLevels(cr, brokers)
+	assert.Equal(t, "DEBUG", setter.levels["org.apache.activemq.artemis.core.server"])
+	condition := meta.FindStatusCondition(cr.Status.Conditions, brokerv1beta1.LoggingLevelsAppliedConditionType)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, int64(1), condition.ObservedGeneration)
+
+	// the brokers are only asked again when the spec changes
+	updateLoggingLevels(cr, brokers)
+	assert.Equal(t, 1, calls)
+
+	cr.Generation = 2
+	cr.Spec.Logging.Levels["org.apache.activemq.artemis.core.server"] = "info"
+	setter.fail = true
+	updateLoggingLevels(cr, brokers)
+	condition = meta.FindStatusCondition(cr.Status.Conditions, brokerv1beta1.LoggingLevelsAppliedConditionType)
+	assert.Equal(t, metav1.ConditionUnknown, condition.Status)
+	assert.Equal(t, brokerv1beta1.LoggingLevelsApplyingConditionReason, condition.Reason)
+
+	// a failure is retried on the next reconcile
+	setter.fail = false
+	updateLoggingLevels(cr, brokers)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, "INFO", setter.levels["org.apache.activemq.artemis.core.server"])
+
+	cr.Spec.Logging = nil
+	updateLoggingLevels(cr, brokers)
+	assert.Nil(t, meta.FindStatusCondition(cr.Status.Conditions, brokerv1beta1.LoggingLevelsAppliedConditionType))
+}
+
+func TestBrokersUsingConfigMap(t *testing.T) {
+	logging := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "logging", Namespace: "test"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			Logging: &brokerv1beta1.LoggingType{ConfigMap: &v1.ConfigMapKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "base-logging"}, Key: "log4j2.properties"}},
+		},
+	}
+	other := &brokerv1beta1.ActiveMQArtemis{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "test"}}
+	r := &ActiveMQArtemisReconciler{Client: fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithObjects(logging, other).Build()}
+
+	requests := r.brokersUsingConfigMap(&v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "base-logging", Namespace: "test"}})
+	assert.Len(t, requests, 1)
+	assert.Equal(t, "logging", requests[0].Name)
+	assert.Empty(t, r.brokersUsingConfigMap(&v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "unrelated", Namespace: "test"}}))
+}
@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	"context"
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+)
+
+// the drain controller updates the progress of a running drain on the scaledown cr every few seconds,
+// only a drain that changes phase concerns the broker, it pauses autoscaling and shows the drains
+func drainPhasePredicate() predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if !isStatusOnlyUpdate(e.ObjectOld, e.ObjectNew) {
+				return true
+			}
+			old, isOldScaledown := e.ObjectOld.(*brokerv1beta1.ActiveMQArtemisScaledown)
+			new, isNewScaledown := e.ObjectNew.(*brokerv1beta1.ActiveMQArtemisScaledown)
+			return !isOldScaledown || !isNewScaledown || !reflect.DeepEqual(drainPhases(old), drainPhases(new))
+		},
+	}
+}
+
+func drainPhases(scaledown *brokerv1beta1.ActiveMQArtemisScaledown) map[string]brokerv1beta1.ScaledownDrainPhase {
+	phases := map[string]brokerv1beta1.ScaledownDrainPhase{}
+	for _, drain := range scaledown.Status.Drains {
+		phases[drain.Pod] = drain.Phase
+	}
+	return phases
+}
+
+// the drain controller reports on the scaledown cr, the broker cr shows the same drains
+func updateMessageMigrationStatus(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client) {
+	scaledown := &brokerv1beta1.ActiveMQArtemisScaledown{}
+	err := client.Get(context.TODO(), types.NamespacedName{Name: customResource.Name, Namespace: customResource.Namespace}, scaledown)
+	if err != nil || !metav1.IsControlledBy(scaledown, customResource) {
+		customResource.Status.MessageMigration = nil
+		return
+	}
+	customResource.Status.MessageMigration = scaledown.Status.Drains
+}
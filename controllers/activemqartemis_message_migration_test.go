@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"testing"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestUpdateMessageMigrationStatus(t *testing.T) {
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "drained", Namespace: "drain-ns", UID: "drained-uid"},
+		Status: brokerv1beta1.ActiveMQArtemisStatus{
+			MessageMigration: []brokerv1beta1.ScaledownDrainStatus{{Pod: "drained-ss-2", Phase: brokerv1beta1.ScaledownDrainSucceeded}},
+		},
+	}
+	isController := true
+	remaining := int64(12)
+	scaledown := &brokerv1beta1.ActiveMQArtemisScaledown{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "drained",
+			Namespace:       "drain-ns",
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: brokerv1beta1.GroupVersion.String(), Kind: "ActiveMQArtemis", Name: "drained", UID: "drained-uid", Controller: &isController}},
+		},
+		Status: brokerv1beta1.ActiveMQArtemisScaledownStatus{
+			Drains: []brokerv1beta1.ScaledownDrainStatus{{Pod: "drained-ss-1", TargetPod: "drained-ss-0", Phase: brokerv1beta1.ScaledownDrainDraining, Attempts: 1, MessagesRemaining: &remaining}},
+		},
+	}
+	testScheme := newTestScheme(t)
+
+	updateMessageMigrationStatus(cr, fake.NewClientBuilder().WithScheme(testScheme).WithObjects(scaledown).Build())
+	assert.Equal(t, scaledown.Status.Drains, cr.Status.MessageMigration)
+
+	updateMessageMigrationStatus(cr, fake.NewClientBuilder().WithScheme(testScheme).Build())
+	assert.Nil(t, cr.Status.MessageMigration, "without message migration there is no scaledown cr")
+}
+
+func TestDrainPhasePredicate(t *testing.T) {
+	remaining := int64(100)
+	old := &brokerv1beta1.ActiveMQArtemisScaledown{
+		ObjectMeta: metav1.ObjectMeta{Name: "drained", Namespace: "drain-ns", Generation: 1},
+		Status: brokerv1beta1.ActiveMQArtemisScaledownStatus{
+			Drains: []brokerv1beta1.ScaledownDrainStatus{{Pod: "drained-ss-1", Phase: brokerv1beta1.ScaledownDrainDraining, MessagesRemaining: &remaining}},
+		},
+	}
+	predicate := drainPhasePredicate()
+
+	progressed := old.DeepCopy()
+	fewer := int64(40)
+	progressed.Status.Drains[0].MessagesRemaining = &fewer
+	assert.False(t, predicate.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: progressed}), "the progress of a drain doesn't reconcile the broker")
+
+	succeeded := progressed.DeepCopy()
+	succeeded.Status.Drains[0].Phase = brokerv1beta1.ScaledownDrainSucceeded
+	assert.True(t, predicate.Update(event.UpdateEvent{ObjectOld: progressed, ObjectNew: succeeded}))
+
+	started := old.DeepCopy()
+	started.Status.Drains = append(started.Status.Drains, brokerv1beta1.ScaledownDrainStatus{Pod: "drained-ss-2", Phase: brokerv1beta1.ScaledownDrainWaiting})
+	assert.True(t, predicate.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: started}))
+
+	changed := old.DeepCopy()
+	changed.Generation = 2
+	assert.True(t, predicate.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: changed}))
+}
@@ -0,0 +1,107 @@
+package controllers
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// securityRoles."match".role.permission=value or securityRoles.match.role.permission=value
+var securityRolesProperty = regexp.MustCompile(`^\s*securityRoles\.(?:"([^"]+)"|([^.]+))\.([^.=\s]+)\.[^.=\s]+\s*=`)
+
+func isReadOnlyInEffect(cr *brokerv1beta1.ActiveMQArtemis) bool {
+	return meta.FindStatusCondition(cr.Status.Conditions, brokerv1beta1.ReadOnlyConditionType) != nil
+}
+
+// readOnlySecurityRoles are the roles of each security match the broker has, the catch-all of the
+// broker.xml of the image, the reserved prefixes, the applicable security cr and brokerProperties
+func readOnlySecurityRoles(customResource *brokerv1beta1.ActiveMQArtemis) map[string][]string {
+	roles := map[string][]string{}
+	add := func(match string, role string) {
+		if !containsString(roles[match], role) {
+			roles[match] = append(roles[match], role)
+		}
+	}
+
+	add("#", getAdminRole(customResource))
+	if customResource.Spec.ReservedAddressPrefixes != nil {
+		for _, prefix := range reservedAddressPrefixes(customResource) {
+			for _, role := range reservedAddressRoles(customResource) {
+				add(prefix+"#", role)
+			}
+		}
+	}
+	if securityCR := getApplicableSecurityCR(customResource); securityCR != nil {
+		for _, setting := range securityCR.Spec.SecuritySettings.Broker {
+			for _, permission := range setting.Permissions {
+				for _, role := range permission.Roles {
+					add(setting.Match, role)
+				}
+			}
+		}
+	}
+	for _, property := range customResource.Spec.BrokerProperties {
+		if parts := securityRolesProperty.FindStringSubmatch(property); parts != nil {
+			match := parts[1]
+			if match == "" {
+				match = parts[2]
+			}
+			add(match, parts[3])
+		}
+	}
+	return roles
+}
+
+// read only takes the send permission away from every role of every security match, the addresses
+// created later are covered by the same matches. Only the existing matches are changed, a new match
+// would take the other permissions of the less specific ones away. They come after brokerProperties
+// so that those can't grant send again
+func readOnlyProperties(customResource *brokerv1beta1.ActiveMQArtemis) []string {
+	if !customResource.Spec.ReadOnly {
+		return nil
+	}
+	roles := readOnlySecurityRoles(customResource)
+	matches := make([]string, 0, len(roles))
+	for match := range roles {
+		matches = append(matches, match)
+	}
+	sort.Strings(matches)
+	props := []string{}
+	for _, match := range matches {
+		sorted := append([]string{}, roles[match]...)
+		sort.Strings(sorted)
+		for _, role := range sorted {
+			props = append(props, fmt.Sprintf("securityRoles.\"%v\".%v.send=false", match, role))
+		}
+	}
+	return props
+}
+
+// the mode is in effect once the brokers have applied the broker properties that deny send
+func updateReadOnlyMode(cr *brokerv1beta1.ActiveMQArtemis) {
+
+	if !cr.Spec.ReadOnly {
+		meta.RemoveStatusCondition(&cr.Status.Conditions, brokerv1beta1.ReadOnlyConditionType)
+		return
+	}
+
+	if meta.IsStatusConditionTrue(cr.Status.Conditions, brokerv1beta1.ConfigAppliedConditionType) {
+		meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+			Type:    brokerv1beta1.ReadOnlyConditionType,
+			Status:  metav1.ConditionTrue,
+			Reason:  brokerv1beta1.ReadOnlyConditionBlockedReason,
+			Message: fmt.Sprintf("send denied on %d security matches", len(readOnlySecurityRoles(cr))),
+		})
+	} else {
+		meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+			Type:    brokerv1beta1.ReadOnlyConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  brokerv1beta1.ReadOnlyConditionBlockingReason,
+			Message: "waiting for the brokers to apply the broker properties that deny send",
+		})
+	}
+}
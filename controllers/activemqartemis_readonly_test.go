@@ -0,0 +1,53 @@
+package controllers
+
+import (
+	"testing"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestReadOnlyMode(t *testing.T) {
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: "test"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			ReadOnly: true,
+			BrokerProperties: []string{
+				`securityRoles."orders.#".producers.send=true`,
+				"securityRoles.audit#.auditors.consume=true",
+			},
+			ReservedAddressPrefixes: &brokerv1beta1.ReservedAddressPrefixesType{Prefixes: []string{"sys."}, Roles: []string{"ops"}},
+		},
+	}
+
+	// send is taken away on the existing matches only, after brokerProperties so they can't grant it again
+	props := readOnlyProperties(cr)
+	assert.Equal(t, []string{
+		`securityRoles."#".admin.send=false`,
+		`securityRoles."audit#".auditors.send=false`,
+		`securityRoles."orders.#".producers.send=false`,
+		`securityRoles."sys.#".ops.send=false`,
+	}, props)
+
+	// in effect once the brokers applied the properties, until then Ready is held back
+	updateReadOnlyMode(cr)
+	condition := meta.FindStatusCondition(cr.Status.Conditions, brokerv1beta1.ReadOnlyConditionType)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, brokerv1beta1.ReadOnlyConditionBlockingReason, condition.Reason)
+
+	meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{Type: brokerv1beta1.ConfigAppliedConditionType, Status: metav1.ConditionTrue, Reason: brokerv1beta1.ConfigAppliedConditionSynchedReason})
+	updateReadOnlyMode(cr)
+	condition = meta.FindStatusCondition(cr.Status.Conditions, brokerv1beta1.ReadOnlyConditionType)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, brokerv1beta1.ReadOnlyConditionBlockedReason, condition.Reason)
+	assert.True(t, isReadOnlyInEffect(cr))
+
+	// turning it off drops the properties and the condition
+	cr.Spec.ReadOnly = false
+	assert.Empty(t, readOnlyProperties(cr))
+	updateReadOnlyMode(cr)
+	assert.Nil(t, meta.FindStatusCondition(cr.Status.Conditions, brokerv1beta1.ReadOnlyConditionType))
+}
@@ -0,0 +1,145 @@
+package controllers
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// names of the built in steps, in the order Process runs them
+const (
+	DeploymentPlanStepName         = "deploymentPlan"
+	CredentialsStepName            = "credentials"
+	AcceptorsAndConnectorsStepName = "acceptorsAndConnectors"
+	ConsoleStepName                = "console"
+	ZonesStepName                  = "zones"
+	TopologyStepName               = "topology"
+)
+
+// ReconcileStepContext is what a step gets to work with. The desired StatefulSet
+// is mutated in place, any other resource the step wants deployed is handed to
+// TrackDesired. Nothing is applied to the cluster until all steps have run.
+type ReconcileStepContext struct {
+	CustomResource *brokerv1beta1.ActiveMQArtemis
+	Namer          Namers
+	Client         rtclient.Client
+	Scheme         *runtime.Scheme
+	StatefulSet    *appsv1.StatefulSet
+
+	reconciler *ActiveMQArtemisReconcilerImpl
+}
+
+func (c *ReconcileStepContext) TrackDesired(desired rtclient.Object) {
+	c.reconciler.trackDesired(desired)
+}
+
+// CloneOfDeployed returns a copy of a resource owned by the CR as currently deployed, or nil
+func (c *ReconcileStepContext) CloneOfDeployed(kind reflect.Type, name string) rtclient.Object {
+	return c.reconciler.cloneOfDeployed(kind, name)
+}
+
+// ReconcileStep is a stage of Process. A step that returns an error stops the
+// remaining steps and leaves the deployed resources untouched for this reconcile.
+type ReconcileStep struct {
+	Name  string
+	Apply func(ctx *ReconcileStepContext) error
+}
+
+var reconcileStepsLock sync.RWMutex
+
+var reconcileSteps = []ReconcileStep{
+	{Name: DeploymentPlanStepName, Apply: func(ctx *ReconcileStepContext) error {
+		return ctx.reconciler.ProcessDeploymentPlan(ctx.CustomResource, ctx.Namer, ctx.Client, ctx.Scheme, ctx.StatefulSet)
+	}},
+	{Name: CredentialsStepName, Apply: func(ctx *ReconcileStepContext) error {
+		ctx.reconciler.ProcessCredentials(ctx.CustomResource, ctx.Namer, ctx.Client, ctx.Scheme, ctx.StatefulSet)
+		return nil
+	}},
+	{Name: AcceptorsAndConnectorsStepName, Apply: func(ctx *ReconcileStepContext) error {
+		ctx.reconciler.ProcessAcceptorsAndConnectors(ctx.CustomResource, ctx.Namer, ctx.Client, ctx.Scheme, ctx.StatefulSet)
+		return nil
+	}},
+	{Name: ConsoleStepName, Apply: func(ctx *ReconcileStepContext) error {
+		ctx.reconciler.ProcessConsole(ctx.CustomResource, ctx.Namer, ctx.Client, ctx.Scheme, ctx.StatefulSet)
+		return nil
+	}},
+	{Name: ZonesStepName, Apply: func(ctx *ReconcileStepContext) error {
+		ctx.reconciler.ProcessZones(ctx.CustomResource, ctx.Namer, ctx.Client)
+		return nil
+	}},
+	{Name: TopologyStepName, Apply: func(ctx *ReconcileStepContext) error {
+		ctx.reconciler.ProcessTopology(ctx.CustomResource, ctx.Namer, ctx.Client)
+		return nil
+	}},
+}
+
+// RegisterReconcileStep inserts a step right after the named one, an empty name appends it
+// after all others. It is meant to be called before the manager starts, for example from
+// the main of a downstream build.
+func RegisterReconcileStep(after string, step ReconcileStep) error {
+	reconcileStepsLock.Lock()
+	defer reconcileStepsLock.Unlock()
+
+	if step.Name == "" || step.Apply == nil {
+		return fmt.Errorf("a reconcile step needs a name and an apply function")
+	}
+	position := len(reconcileSteps)
+	found := after == ""
+	for i, existing := range reconcileSteps {
+		if existing.Name == step.Name {
+			return fmt.Errorf("reconcile step %v is already registered", step.Name)
+		}
+		if existing.Name == after {
+			position = i + 1
+			found = true
+		}
+	}
+	if !found {
+		return fmt.Errorf("reconcile step %v is not registered", after)
+	}
+
+	reconcileSteps = append(reconcileSteps, ReconcileStep{})
+	copy(reconcileSteps[position+1:], reconcileSteps[position:])
+	reconcileSteps[position] = step
+	return nil
+}
+
+// applyReconcileSteps runs the steps in order. The error of a failed step stays on the Reconciled
+// condition until a reconcile gets through all of them, the CR is requeued meanwhile
+func applyReconcileSteps(ctx *ReconcileStepContext, steps []ReconcileStep) error {
+	for _, step := range steps {
+		if err := step.Apply(ctx); err != nil {
+			setReconcileStepFailed(ctx.CustomResource, step.Name, err)
+			return err
+		}
+	}
+	meta.RemoveStatusCondition(&ctx.CustomResource.Status.Conditions, brokerv1beta1.ReconciledConditionType)
+	return nil
+}
+
+func setReconcileStepFailed(customResource *brokerv1beta1.ActiveMQArtemis, step string, err error) {
+	meta.SetStatusCondition(&customResource.Status.Conditions, metav1.Condition{
+		Type:    brokerv1beta1.ReconciledConditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  brokerv1beta1.ReconciledConditionStepFailedReason,
+		Message: fmt.Sprintf("step %v failed: %v", step, err),
+	})
+}
+
+func isReconcileStepFailed(customResource *brokerv1beta1.ActiveMQArtemis) bool {
+	return meta.IsStatusConditionFalse(customResource.Status.Conditions, brokerv1beta1.ReconciledConditionType)
+}
+
+func getReconcileSteps() []ReconcileStep {
+	reconcileStepsLock.RLock()
+	defer reconcileStepsLock.RUnlock()
+
+	return append([]ReconcileStep{}, reconcileSteps...)
+}
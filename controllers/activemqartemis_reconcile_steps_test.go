@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"errors"
+	"testing"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+func TestRegisterReconcileStep(t *testing.T) {
+	saved := getReconcileSteps()
+	defer func() { reconcileSteps = saved }()
+
+	sidecar := ReconcileStep{Name: "sidecar", Apply: func(ctx *ReconcileStepContext) error {
+		ctx.StatefulSet.Spec.Template.Spec.Containers = append(ctx.StatefulSet.Spec.Template.Spec.Containers, v1.Container{Name: "sidecar"})
+		return nil
+	}}
+	assert.NoError(t, RegisterReconcileStep(CredentialsStepName, sidecar))
+	assert.NoError(t, RegisterReconcileStep("", ReconcileStep{Name: "last", Apply: sidecar.Apply}))
+
+	assert.Error(t, RegisterReconcileStep("", sidecar))
+	assert.Error(t, RegisterReconcileStep("unknown", ReconcileStep{Name: "other", Apply: sidecar.Apply}))
+	assert.Error(t, RegisterReconcileStep("", ReconcileStep{Name: "no-apply"}))
+
+	names := []string{}
+	for _, step := range getReconcileSteps() {
+		names = append(names, step.Name)
+	}
+	assert.Equal(t, []string{DeploymentPlanStepName, CredentialsStepName, "sidecar", AcceptorsAndConnectorsStepName, ConsoleStepName, ZonesStepName, TopologyStepName, "last"}, names)
+
+	ctx := &ReconcileStepContext{StatefulSet: &appsv1.StatefulSet{}, reconciler: &ActiveMQArtemisReconcilerImpl{}}
+	assert.NoError(t, getReconcileSteps()[2].Apply(ctx))
+	assert.Equal(t, "sidecar", ctx.StatefulSet.Spec.Template.Spec.Containers[0].Name)
+}
+
+func TestReconcileStepFailure(t *testing.T) {
+	cr := &brokerv1beta1.ActiveMQArtemis{}
+	ctx := &ReconcileStepContext{CustomResource: cr, StatefulSet: &appsv1.StatefulSet{}, reconciler: &ActiveMQArtemisReconcilerImpl{}}
+
+	applied := []string{}
+	step := func(name string, err error) ReconcileStep {
+		return ReconcileStep{Name: name, Apply: func(ctx *ReconcileStepContext) error {
+			applied = append(applied, name)
+			return err
+		}}
+	}
+
+	err := applyReconcileSteps(ctx, []ReconcileStep{step("first", nil), step("failing", errors.New("no certificate")), step("skipped", nil)})
+	assert.Error(t, err)
+	assert.Equal(t, []string{"first", "failing"}, applied)
+	condition := meta.FindStatusCondition(cr.Status.Conditions, brokerv1beta1.ReconciledConditionType)
+	assert.NotNil(t, condition)
+	assert.Equal(t, brokerv1beta1.ReconciledConditionStepFailedReason, condition.Reason)
+	assert.Equal(t, "step failing failed: no certificate", condition.Message)
+	assert.True(t, isReconcileStepFailed(cr))
+
+	// the condition goes once all the steps apply
+	assert.NoError(t, applyReconcileSteps(ctx, []ReconcileStep{step("first", nil)}))
+	assert.Nil(t, meta.FindStatusCondition(cr.Status.Conditions, brokerv1beta1.ReconciledConditionType))
+	assert.False(t, isReconcileStepFailed(cr))
+}
@@ -8,8 +8,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"hash/adler32"
+	"net/url"
 	osruntime "runtime"
 	"sort"
+	"time"
 	"unicode"
 
 	"github.com/blang/semver/v4"
@@ -17,6 +19,7 @@ import (
 	"github.com/RHsyseng/operator-utils/pkg/olm"
 	"github.com/RHsyseng/operator-utils/pkg/resource/compare"
 	"github.com/RHsyseng/operator-utils/pkg/resource/read"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/draincontroller"
 	"github.com/artemiscloud/activemq-artemis-operator/pkg/resources"
 	"github.com/artemiscloud/activemq-artemis-operator/pkg/resources/containers"
 	"github.com/artemiscloud/activemq-artemis-operator/pkg/resources/ingresses"
@@ -29,8 +32,10 @@ import (
 	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/channels"
 	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/common"
 	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/cr2jinja2"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/envelope"
 	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/jolokia_client"
 	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/namer"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/random"
 	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/selectors"
 	"github.com/artemiscloud/activemq-artemis-operator/version"
 	"github.com/go-logr/logr"
@@ -38,6 +43,9 @@ import (
 	"k8s.io/apimachinery/pkg/api/equality"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -48,6 +56,8 @@ import (
 	"github.com/artemiscloud/activemq-artemis-operator/pkg/resources/environments"
 	svc "github.com/artemiscloud/activemq-artemis-operator/pkg/resources/services"
 	"github.com/artemiscloud/activemq-artemis-operator/pkg/resources/volumes"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/endpoints"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/forecast"
 
 	"reflect"
 
@@ -76,6 +86,15 @@ const (
 	TCPLivenessPort                  = 8161
 	jaasConfigSuffix                 = "-jaas-config"
 	loggingConfigSuffix              = "-logging-config"
+	multusNetworksAnnotation         = "k8s.v1.cni.cncf.io/networks"
+	exposeAnnotationsKey             = "broker.amq.io/expose-annotations"
+	routeCAKey                       = "ca.crt"
+	RestartedAtAnnotation            = "broker.amq.io/restartedAt"
+	defaultMetricsPortName           = "metrics"
+	defaultMetricsServicePort        = 8162
+
+	ephemeralProbePeriodSeconds       = 20
+	ephemeralLivenessFailureThreshold = 6
 
 	cfgMapPathBase = "/amq/extra/configmaps/"
 	secretPathBase = "/amq/extra/secrets/"
@@ -86,6 +105,7 @@ const (
 	JaasConfigKey         = "login.config"
 	LoggingConfigKey      = "logging.properties"
 	DefaultDeploymentSize = int32(1)
+	DefaultAdminRole      = "admin"
 )
 
 var defaultMessageMigration bool = true
@@ -97,13 +117,98 @@ var initHelperScript = "/opt/amq-broker/script/default.sh"
 var brokerConfigRoot = "/amq/init/config"
 var configCmd = "/opt/amq/bin/launch.sh"
 
+// merges the core elements of a user supplied broker.xml into the generated one,
+// usage: merge-broker-xml.py <generated broker.xml> <user broker.xml>
+var brokerXmlMergeScript = `import sys
+from xml.dom import minidom
+
+def core(doc):
+    cores = doc.getElementsByTagName('core')
+    if not cores:
+        sys.exit('no core element in ' + doc.documentURI)
+    return cores[0]
+
+def elements(node):
+    return [c for c in node.childNodes if c.nodeType == c.ELEMENT_NODE]
+
+generated = minidom.parse(sys.argv[1])
+generated.documentURI = sys.argv[1]
+user = minidom.parse(sys.argv[2])
+user.documentURI = sys.argv[2]
+
+target = core(generated)
+for child in elements(core(user)):
+    imported = generated.importNode(child, True)
+    existing = [e for e in elements(target) if e.tagName == child.tagName]
+    if existing:
+        target.replaceChild(imported, existing[0])
+    else:
+        target.appendChild(imported)
+
+with open(sys.argv[1], 'w') as out:
+    out.write(generated.toxml())
+`
+
+// acceptors bound to an interface carry this placeholder followed by the interface name
+// in place of the host until the init container knows the interface address
+const bindInterfacePlaceholder = "ACCEPTOR_IF_"
+
+// replaces the interface placeholders of the acceptors with the ipv4 address of the interface, or with
+// its global ipv6 address given -6, usage: bind-interfaces.py [-6] <broker.xml> <interface>...
+var bindInterfacesScript = `import fcntl, socket, struct, sys
+
+SIOCGIFADDR = 0x8915
+
+def address(ifname):
+    s = socket.socket(socket.AF_INET, socket.SOCK_DGRAM)
+    try:
+        return socket.inet_ntoa(fcntl.ioctl(s.fileno(), SIOCGIFADDR, struct.pack('256s', ifname[:15].encode()))[20:24])
+    except OSError as e:
+        sys.exit('no ipv4 address for interface ' + ifname + ': ' + str(e))
+
+def address6(ifname):
+    with open('/proc/net/if_inet6') as f:
+        for line in f:
+            fields = line.split()
+            if fields[5] == ifname and fields[3] == '00':
+                return '[' + socket.inet_ntop(socket.AF_INET6, bytes.fromhex(fields[0])) + ']'
+    sys.exit('no global ipv6 address for interface ' + ifname)
+
+args = sys.argv[1:]
+ipv6 = args[0] == '-6'
+if ipv6:
+    args = args[1:]
+with open(args[0]) as f:
+    xml = f.read()
+for ifname in args[1:]:
+    xml = xml.replace('` + bindInterfacePlaceholder + `' + ifname + ':', (address6(ifname) if ipv6 else address(ifname)) + ':')
+with open(args[0], 'w') as out:
+    out.write(xml)
+`
+
+var consoleBootstrapScript = `import re, sys
+
+bootstrap, host, port, disabled = sys.argv[1:5]
+with open(bootstrap) as f:
+    xml = f.read()
+if disabled == 'true':
+    xml = re.sub(r'\s*<web\b.*?</web>', '', xml, flags=re.S)
+else:
+    def rebind(m):
+        return m.group(1) + (m.group(2) if host == '-' else host) + ':' + (m.group(3) if port == '-' else port)
+    xml = re.sub(r'((?:bind|uri)=.https?://)(\[[^\]]*\]|[^:/]+):([0-9]+)', rebind, xml)
+with open(bootstrap, 'w') as out:
+    out.write(xml)
+`
+
 // default ApplyRule for address-settings
 var defApplyRule string = "merge_all"
 var yacfgProfileVersion = version.YacfgProfileVersionFromFullVersion[version.LatestVersion]
 
 type ActiveMQArtemisReconcilerImpl struct {
-	requestedResources []rtclient.Object
-	deployed           map[reflect.Type][]rtclient.Object
+	requestedResources  []rtclient.Object
+	deployed            map[reflect.Type][]rtclient.Object
+	appliedCertificates map[string]bool
 }
 
 type ValueInfo struct {
@@ -137,22 +242,46 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) Process(customResource *brokerv
 	desiredStatefulSet, err := reconciler.ProcessStatefulSet(customResource, namer, client, log)
 	if err != nil {
 		log.Error(err, "Error processing stafulset")
+		setReconcileStepFailed(customResource, "statefulSet", err)
 		return
 	}
 
-	reconciler.ProcessDeploymentPlan(customResource, namer, client, scheme, desiredStatefulSet)
-
-	reconciler.ProcessCredentials(customResource, namer, client, scheme, desiredStatefulSet)
-
-	reconciler.ProcessAcceptorsAndConnectors(customResource, namer, client, scheme, desiredStatefulSet)
-
-	reconciler.ProcessConsole(customResource, namer, client, scheme, desiredStatefulSet)
+	stepContext := &ReconcileStepContext{
+		CustomResource: customResource,
+		Namer:          namer,
+		Client:         client,
+		Scheme:         scheme,
+		StatefulSet:    desiredStatefulSet,
+		reconciler:     reconciler,
+	}
+	if err := applyReconcileSteps(stepContext, getReconcileSteps()); err != nil {
+		log.Error(err, "Error processing reconcile steps")
+		reconciler.requestedResources = nil
+		return
+	}
 
 	// mods to env var values sourced from secrets are not detected by process resources
 	// track updates in trigger env var that has a total checksum
 	trackSecretCheckSumInEnvVar(reconciler.requestedResources, desiredStatefulSet.Spec.Template.Spec.Containers)
 
-	reconciler.trackDesired(desiredStatefulSet)
+	trackTLSSecretsCheckSumInEnvVar(customResource, namer, client, desiredStatefulSet.Spec.Template.Spec.Containers)
+
+	trackCredentialsSourceCheckSumInEnvVar(customResource, client, desiredStatefulSet.Spec.Template.Spec.Containers)
+
+	configureHAStatefulSet(customResource, desiredStatefulSet)
+
+	if !isSecurityGateOpen(customResource) || securityRenderFailed(customResource) {
+		reconciler.holdStatefulSetForSecurity(desiredStatefulSet)
+	} else if reconciler.processImmutableFields(customResource, namer, client, desiredStatefulSet) {
+		if isHAEnabled(customResource) {
+			// the pairs are restarted by the operator, a partition has no effect on an OnDelete StatefulSet
+			reconciler.startHASecurityCanary(customResource, desiredStatefulSet, time.Now())
+			reconciler.applyHARollout(customResource, namer, client, checkHAReplicaSync, checkSecurityCanaryLogin, time.Now())
+		} else {
+			reconciler.applySecurityCanary(customResource, client, desiredStatefulSet, checkSecurityCanaryLogin, time.Now())
+		}
+		reconciler.trackDesired(desiredStatefulSet)
+	}
 
 	// this should apply any deltas/updates
 	reconciler.ProcessResources(customResource, client, scheme)
@@ -186,6 +315,101 @@ func trackSecretCheckSumInEnvVar(requestedResources []rtclient.Object, container
 	environments.TrackSecretCheckSumInRollCount(hex.EncodeToString(digest.Sum(nil)), container)
 }
 
+const (
+	TLSRenewalRollingRestart = "RollingRestart"
+	TLSRenewalReload         = "Reload"
+	TLSRenewalNone           = "None"
+
+	tlsSecretsCheckSumEnvVarName = "TLS_SECRETS_CHECKSUM"
+)
+
+func getTLSRenewal(customResource *brokerv1beta1.ActiveMQArtemis) string {
+	if customResource.Spec.TLSRenewal == "" {
+		return TLSRenewalNone
+	}
+	return customResource.Spec.TLSRenewal
+}
+
+// the names of the secrets holding keystores the broker reads, acceptor keystores are
+// left out when they are reloaded in place
+func tlsSecretNames(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, includeAcceptors bool) []string {
+	names := []string{}
+	if includeAcceptors {
+		for _, acceptor := range customResource.Spec.Acceptors {
+			if acceptor.SSLEnabled {
+				secretName := customResource.Name + "-" + acceptor.Name + "-secret"
+				if acceptor.SSLSecret != "" {
+					secretName = acceptor.SSLSecret
+				}
+				names = append(names, secretName)
+			}
+		}
+	}
+	for _, connector := range customResource.Spec.Connectors {
+		if connector.SSLEnabled {
+			secretName := customResource.Name + "-" + connector.Name + "-secret"
+			if connector.SSLSecret != "" {
+				secretName = connector.SSLSecret
+			}
+			names = append(names, secretName)
+		}
+	}
+	// the cluster connector can't reload its keystore
+	if customResource.Spec.ClusterTLS != nil {
+		names = append(names, clusterTLSSecretName(customResource))
+	}
+	if customResource.Spec.Console.SSLEnabled {
+		secretName := namer.SecretsConsoleNameBuilder.Name()
+		if customResource.Spec.Console.SSLSecret != "" {
+			secretName = customResource.Spec.Console.SSLSecret
+		}
+		names = append(names, secretName)
+	}
+	return names
+}
+
+// renewed certificates only reach the broker on restart, a checksum of the ssl secrets in the pod
+// template has the statefulset roll the brokers one at a time when any of them changes
+func trackTLSSecretsCheckSumInEnvVar(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, client rtclient.Client, containers []corev1.Container) {
+
+	renewal := getTLSRenewal(customResource)
+	if renewal == TLSRenewalNone {
+		environments.Delete(containers, tlsSecretsCheckSumEnvVarName)
+		return
+	}
+
+	names := tlsSecretNames(customResource, namer, renewal != TLSRenewalReload)
+	sort.Strings(names)
+
+	digest := adler32.New()
+	found := false
+	for _, name := range names {
+		secret := &corev1.Secret{}
+		if err := client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: customResource.Namespace}, secret); err != nil {
+			continue
+		}
+		found = true
+		digest.Write([]byte(name))
+		for _, k := range sortedKeysStringKeyByteValue(secret.Data) {
+			digest.Write([]byte(k))
+			digest.Write(secret.Data[k])
+		}
+	}
+	if !found {
+		environments.Delete(containers, tlsSecretsCheckSumEnvVarName)
+		return
+	}
+	checkSumEnvVar := &corev1.EnvVar{
+		Name:  tlsSecretsCheckSumEnvVarName,
+		Value: hex.EncodeToString(digest.Sum(nil)),
+	}
+	if environments.Retrieve(containers, tlsSecretsCheckSumEnvVarName) == nil {
+		environments.Create(containers, checkSumEnvVar)
+	} else {
+		environments.Update(containers, checkSumEnvVar)
+	}
+}
+
 func (reconciler *ActiveMQArtemisReconcilerImpl) cloneOfDeployed(kind reflect.Type, name string) rtclient.Object {
 	obj := reconciler.getFromDeployed(kind, name)
 	if obj != nil {
@@ -231,9 +455,15 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) ProcessStatefulSet(customResour
 	}
 
 	labels := namer.LabelBuilder.Labels()
-	headlessServiceDefinition := svc.NewHeadlessServiceForCR2(client, namer.SvcHeadlessNameBuilder.Name(), ssNamespacedName.Namespace, serviceports.GetDefaultPorts(), labels)
+	headlessServiceDefinition := svc.NewHeadlessServiceForCR2(client, namer.SvcHeadlessNameBuilder.Name(), ssNamespacedName.Namespace, headlessServicePorts(customResource), labels)
+	customizeHeadlessService(customResource, headlessServiceDefinition)
+	reconciler.configureIPFamilies(customResource, headlessServiceDefinition)
 	if isClustered(customResource) {
 		pingServiceDefinition := svc.NewPingServiceDefinitionForCR2(client, namer.SvcPingNameBuilder.Name(), ssNamespacedName.Namespace, labels, labels)
+		if isServiceMeshEnabled(customResource) {
+			pingServiceDefinition.Spec.Ports[0].Name = meshPortName(customResource, "ping", meshProtocolTCP)
+		}
+		reconciler.configureIPFamilies(customResource, pingServiceDefinition)
 		reconciler.trackDesired(pingServiceDefinition)
 	}
 	reconciler.trackDesired(headlessServiceDefinition)
@@ -241,6 +471,180 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) ProcessStatefulSet(customResour
 	return currentStatefulSet, nil
 }
 
+func headlessServicePorts(customResource *brokerv1beta1.ActiveMQArtemis) *[]corev1.ServicePort {
+	ports := serviceports.GetDefaultPorts()
+	for i := range *ports {
+		if (*ports)[i].Name == "console-jolokia" {
+			(*ports)[i].TargetPort = intstr.FromInt(int(consoleServiceTargetPort(customResource)))
+		}
+	}
+	if metrics := customResource.Spec.DeploymentPlan.Metrics; metrics != nil {
+		metricsPort := corev1.ServicePort{
+			Name:       defaultMetricsPortName,
+			Protocol:   "TCP",
+			Port:       defaultMetricsServicePort,
+			TargetPort: intstr.FromInt(int(consoleServiceTargetPort(customResource))),
+		}
+		if metrics.PortName != "" {
+			metricsPort.Name = metrics.PortName
+		}
+		if metrics.Port != 0 {
+			metricsPort.Port = metrics.Port
+		}
+		*ports = append(*ports, metricsPort)
+	}
+	for i := range *ports {
+		protocol := meshConsoleProtocol(customResource)
+		if (*ports)[i].Name == "all" {
+			protocol = meshProtocolTCP
+		}
+		(*ports)[i].Name = meshPortName(customResource, (*ports)[i].Name, protocol)
+	}
+	return ports
+}
+
+// customizeHeadlessService adds the ports and annotations of the cr to the headless service, a port
+// without a target port targets the same port of the broker container
+func customizeHeadlessService(customResource *brokerv1beta1.ActiveMQArtemis, service *corev1.Service) {
+	clearExposeAnnotations(service)
+	headless := customResource.Spec.HeadlessService
+	if headless == nil {
+		return
+	}
+	if headless.PublishNotReadyAddresses != nil {
+		service.Spec.PublishNotReadyAddresses = *headless.PublishNotReadyAddresses
+	}
+	for _, port := range headless.Ports {
+		if port.Protocol == "" {
+			port.Protocol = corev1.ProtocolTCP
+		}
+		if port.TargetPort.Type == intstr.Int && port.TargetPort.IntVal == 0 {
+			port.TargetPort = intstr.FromInt(int(port.Port))
+		}
+		service.Spec.Ports = append(service.Spec.Ports, port)
+	}
+	setExposeAnnotations(service, headless.Annotations)
+}
+
+func isMetricsPluginEnabled(customResource *brokerv1beta1.ActiveMQArtemis) bool {
+	if customResource.Spec.DeploymentPlan.Metrics != nil {
+		return true
+	}
+	return customResource.Spec.DeploymentPlan.EnableMetricsPlugin != nil && *customResource.Spec.DeploymentPlan.EnableMetricsPlugin
+}
+
+func metricsProperties(customResource *brokerv1beta1.ActiveMQArtemis) []string {
+	metrics := customResource.Spec.DeploymentPlan.Metrics
+	if metrics == nil {
+		return nil
+	}
+	props := []string{}
+	for _, option := range []struct {
+		property string
+		value    *bool
+	}{{"jvmMemory", metrics.JvmMemory}, {"jvmGc", metrics.JvmGc}, {"jvmThread", metrics.JvmThreads}} {
+		if option.value != nil {
+			props = append(props, fmt.Sprintf("metricsConfiguration.%v=%v", option.property, *option.value))
+		}
+	}
+	return props
+}
+
+func reservedAddressPrefixes(customResource *brokerv1beta1.ActiveMQArtemis) []string {
+	if customResource.Spec.ReservedAddressPrefixes == nil {
+		return nil
+	}
+	return customResource.Spec.ReservedAddressPrefixes.Prefixes
+}
+
+func reservedAddressRoles(customResource *brokerv1beta1.ActiveMQArtemis) []string {
+	if len(customResource.Spec.ReservedAddressPrefixes.Roles) > 0 {
+		return customResource.Spec.ReservedAddressPrefixes.Roles
+	}
+	return []string{getAdminRole(customResource)}
+}
+
+// a security-setting match on prefix# replaces the less specific matches, roles that are not listed get no permissions
+func reservedAddressPrefixProperties(customResource *brokerv1beta1.ActiveMQArtemis) []string {
+	props := []string{}
+	for _, prefix := range reservedAddressPrefixes(customResource) {
+		for _, role := range reservedAddressRoles(customResource) {
+			for _, permission := range brokerv1beta1.BrokerPermissionTypes {
+				props = append(props, fmt.Sprintf("securityRoles.\"%v#\".%v.%v=true", prefix, role, permission))
+			}
+		}
+	}
+	return props
+}
+
+// a message older than the max age expires whatever expiration it was sent with, the address full
+// policy only applies once the address holds max messages
+func retentionPolicyProperties(customResource *brokerv1beta1.ActiveMQArtemis) []string {
+	props := []string{}
+	for _, policy := range customResource.Spec.RetentionPolicies {
+		prefix := fmt.Sprintf("addressSettings.\"%v\".", policy.Match)
+		if policy.MaxAge != "" {
+			if maxAge, err := time.ParseDuration(policy.MaxAge); err == nil {
+				props = append(props,
+					fmt.Sprintf("%vexpiryDelay=%v", prefix, maxAge.Milliseconds()),
+					fmt.Sprintf("%vmaxExpiryDelay=%v", prefix, maxAge.Milliseconds()))
+			}
+		}
+		if policy.MaxMessages != nil {
+			action := policy.Action
+			if action == "" {
+				action = brokerv1beta1.RetentionActionDrop
+			}
+			props = append(props,
+				fmt.Sprintf("%vmaxSizeMessages=%v", prefix, *policy.MaxMessages),
+				fmt.Sprintf("%vaddressFullMessagePolicy=%v", prefix, strings.ToUpper(string(action))))
+		}
+	}
+	return props
+}
+
+// the redelivery defaults go to the catch-all address settings, which the settings of a more specific
+// match, like the ones an Address CR adds, take precedence over
+func redeliveryProperties(customResource *brokerv1beta1.ActiveMQArtemis) []string {
+	redelivery := customResource.Spec.Redelivery
+	if redelivery == nil {
+		return nil
+	}
+	props := []string{}
+	if redelivery.PersistDeliveryCount != nil {
+		props = append(props, fmt.Sprintf("persistDeliveryCountBeforeDelivery=%v", *redelivery.PersistDeliveryCount))
+	}
+	for key, value := range redeliverySettings(&redelivery.RedeliveryPolicyType) {
+		props = append(props, fmt.Sprintf("addressSettings.\"#\".%v=%v", key, value))
+	}
+	sort.Strings(props)
+	return props
+}
+
+// redeliverySettings maps a policy to the address settings it sets, invalid values are left out
+func redeliverySettings(policy *brokerv1beta1.RedeliveryPolicyType) map[string]interface{} {
+	settings := map[string]interface{}{}
+	if policy.MaxDeliveryAttempts != nil {
+		settings["maxDeliveryAttempts"] = *policy.MaxDeliveryAttempts
+	}
+	if delay, err := time.ParseDuration(policy.RedeliveryDelay); err == nil {
+		settings["redeliveryDelay"] = delay.Milliseconds()
+	}
+	if multiplier, err := strconv.ParseFloat(policy.RedeliveryMultiplier, 64); err == nil {
+		settings["redeliveryMultiplier"] = multiplier
+	}
+	if maxDelay, err := time.ParseDuration(policy.MaxRedeliveryDelay); err == nil {
+		settings["maxRedeliveryDelay"] = maxDelay.Milliseconds()
+	}
+	if policy.DeadLetterAddress != "" {
+		settings["deadLetterAddress"] = policy.DeadLetterAddress
+	}
+	if policy.AutoCreateDeadLetterResources != nil {
+		settings["autoCreateDeadLetterResources"] = *policy.AutoCreateDeadLetterResources
+	}
+	return settings
+}
+
 func isClustered(customResource *brokerv1beta1.ActiveMQArtemis) bool {
 	if customResource.Spec.DeploymentPlan.Clustered != nil {
 		return *customResource.Spec.DeploymentPlan.Clustered
@@ -248,6 +652,33 @@ func isClustered(customResource *brokerv1beta1.ActiveMQArtemis) bool {
 	return true
 }
 
+// clusterConnectionProperties tunes the cluster connection of the broker.xml of the image by its name,
+// the rest of the cluster connection is left as the image configures it
+func clusterConnectionProperties(customResource *brokerv1beta1.ActiveMQArtemis) []string {
+	clusterConnection := customResource.Spec.ClusterConnection
+	if clusterConnection == nil || !isClustered(customResource) {
+		return nil
+	}
+	prefix := "clusterConfigurations." + clusterConnectionName + "."
+	props := []string{}
+	if clusterConnection.MessageLoadBalancing != "" {
+		props = append(props, prefix+"messageLoadBalancingType="+string(clusterConnection.MessageLoadBalancing))
+	}
+	if clusterConnection.MaxHops != nil {
+		props = append(props, fmt.Sprintf("%smaxHops=%d", prefix, *clusterConnection.MaxHops))
+	}
+	if clusterConnection.ReconnectAttempts != nil {
+		props = append(props, fmt.Sprintf("%sreconnectAttempts=%d", prefix, *clusterConnection.ReconnectAttempts))
+	}
+	if clusterConnection.CallTimeout != nil {
+		props = append(props, fmt.Sprintf("%scallTimeout=%d", prefix, *clusterConnection.CallTimeout))
+	}
+	if clusterConnection.ProducerWindowSize != nil {
+		props = append(props, fmt.Sprintf("%sproducerWindowSize=%d", prefix, *clusterConnection.ProducerWindowSize))
+	}
+	return props
+}
+
 func (reconciler *ActiveMQArtemisReconcilerImpl) ProcessCredentials(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, client rtclient.Client, scheme *runtime.Scheme, currentStatefulSet *appsv1.StatefulSet) {
 
 	var log = ctrl.Log.WithName("controller_v1beta1activemqartemis")
@@ -312,11 +743,38 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) ProcessCredentials(customResour
 		Value:   environments.GLOBAL_AMQ_CLUSTER_PASSWORD,
 		AutoGen: true,
 	}
+	applyClusterMeshCredentials(customResource, client, envVars)
+
+	deployed, _ := reconciler.getFromDeployed(reflect.TypeOf(appsv1.StatefulSet{}), namer.SsNameBuilder.Name()).(*appsv1.StatefulSet)
+	for _, rotation := range credentialRotations {
+		user, password := reconciler.rotateCredentials(rotation, customResource, namer, client, func() map[string]rotationBroker {
+			return rotationBrokers(rotation, customResource, client, namer, deployed)
+		}, time.Now())
+		if user != "" {
+			envVars[rotation.userKey] = ValueInfo{Value: user}
+			envVars[rotation.passwordKey] = ValueInfo{Value: password}
+		}
+		// the credentials come from the secret, a new value in the pod template rolls the brokers onto them
+		if rotatedAt := rotation.rotatedAt(customResource, deployed); rotatedAt != "" {
+			annotations := make(map[string]string, len(currentStatefulSet.Spec.Template.Annotations)+1)
+			for key, value := range currentStatefulSet.Spec.Template.Annotations {
+				annotations[key] = value
+			}
+			annotations[rotation.rotatedAtAnnotation] = rotatedAt
+			currentStatefulSet.Spec.Template.Annotations = annotations
+		}
+	}
+	// the init container exports the credentials taken from an external store, see credentialsSourceExportCmd
+	for envVar := range envVars {
+		if credentialsSourceSkipsEnvVar(customResource, envVar) {
+			delete(envVars, envVar)
+		}
+	}
 
 	reconciler.sourceEnvVarFromSecret(customResource, namer, currentStatefulSet, &envVars, secretName, client, scheme)
 }
 
-func (reconciler *ActiveMQArtemisReconcilerImpl) ProcessDeploymentPlan(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, client rtclient.Client, scheme *runtime.Scheme, currentStatefulSet *appsv1.StatefulSet) {
+func (reconciler *ActiveMQArtemisReconcilerImpl) ProcessDeploymentPlan(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, client rtclient.Client, scheme *runtime.Scheme, currentStatefulSet *appsv1.StatefulSet) error {
 
 	deploymentPlan := &customResource.Spec.DeploymentPlan
 
@@ -326,11 +784,300 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) ProcessDeploymentPlan(customRes
 	currentStatefulSet.Spec.Replicas = &replicas
 
 	clog.Info("Now sync Message migration", "for cr", customResource.Name)
-	syncMessageMigration(customResource, namer, client, scheme)
+	// a scale down without its drainer strands the messages of the removed brokers, nothing is
+	// applied until the drainer can be set up
+	if err := syncMessageMigration(customResource, namer, client, scheme); err != nil {
+		return err
+	}
 
 	if customResource.Spec.DeploymentPlan.PodDisruptionBudget != nil {
 		reconciler.applyPodDisruptionBudget(customResource, client, currentStatefulSet)
 	}
+
+	if customResource.Spec.DeploymentPlan.CapacityPlaceholders != nil {
+		reconciler.applyCapacityPlaceholders(customResource)
+	}
+
+	reconciler.applyAutoscaling(customResource, namer, client, scheme)
+
+	if isHASharedStore(customResource) {
+		reconciler.applyHASharedStore(customResource, namer, client)
+	}
+	return nil
+}
+
+const (
+	certificateAPIVersion      = "cert-manager.io/v1"
+	certificateKeyStoreKey     = "password"
+	defaultCertificateIssuer   = "Issuer"
+	keyStorePasswordNameSuffix = "-keystore-password"
+)
+
+func getAcceptorCertificateIssuer(customResource *brokerv1beta1.ActiveMQArtemis, acceptor brokerv1beta1.AcceptorType) *brokerv1beta1.CertificateIssuerType {
+	if !acceptor.SSLEnabled {
+		return nil
+	}
+	if acceptor.CertificateIssuer != nil {
+		return acceptor.CertificateIssuer
+	}
+	// an explicit sslSecret holds keystores made by hand, the cr level issuer must not replace them
+	if acceptor.SSLSecret == "" {
+		return customResource.Spec.CertificateIssuer
+	}
+	return nil
+}
+
+// for each acceptor backed by an issuer a Certificate is kept in line with the cr and the password
+// protecting its pkcs12 stores is tracked in a secret, the passwords are returned by acceptor name
+func (reconciler *ActiveMQArtemisReconcilerImpl) applyAcceptorCertificates(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, client rtclient.Client, scheme *runtime.Scheme) map[string]string {
+
+	passwords := map[string]string{}
+	for _, acceptor := range customResource.Spec.Acceptors {
+		issuer := getAcceptorCertificateIssuer(customResource, acceptor)
+		if issuer == nil {
+			continue
+		}
+		passwords[acceptor.Name] = reconciler.applyIssuedCertificate(customResource, namer, client, scheme, acceptor.Name, acceptor.SSLSecret, issuer, acceptorExposedHosts(customResource, acceptor))
+	}
+	if customResource.Spec.ClusterTLS != nil {
+		passwords[clusterTLSName] = reconciler.applyClusterCertificate(customResource, namer, client, scheme)
+	}
+	return passwords
+}
+
+// connectors only get a certificate from their own issuer, it identifies the broker to the remote end
+func (reconciler *ActiveMQArtemisReconcilerImpl) applyConnectorCertificates(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, client rtclient.Client, scheme *runtime.Scheme) map[string]string {
+
+	passwords := map[string]string{}
+	for _, connector := range customResource.Spec.Connectors {
+		if !connector.SSLEnabled || connector.CertificateIssuer == nil {
+			continue
+		}
+		passwords[connector.Name] = reconciler.applyIssuedCertificate(customResource, namer, client, scheme, connector.Name, connector.SSLSecret, connector.CertificateIssuer, nil)
+	}
+	return passwords
+}
+
+func (reconciler *ActiveMQArtemisReconcilerImpl) applyIssuedCertificate(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, client rtclient.Client, scheme *runtime.Scheme, name string, sslSecret string, issuer *brokerv1beta1.CertificateIssuerType, exposedHosts []string) string {
+
+	secretName := customResource.Name + "-" + name + "-secret"
+	if sslSecret != "" {
+		secretName = sslSecret
+	}
+	passwordSecretName := customResource.Name + "-" + name + keyStorePasswordNameSuffix
+	password := reconciler.issuedKeyStorePassword(customResource, namer, passwordSecretName)
+
+	certificate := newCertificate(customResource, namer, name, issuer, secretName, passwordSecretName, exposedHosts)
+	reconciler.applyCertificate(customResource, client, scheme, certificate)
+	return password
+}
+
+// the password of the keystores cert-manager writes, the secret holding it is tracked once per
+// reconcile whichever of the statefulset or the acceptors asks for it first
+func (reconciler *ActiveMQArtemisReconcilerImpl) issuedKeyStorePassword(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, passwordSecretName string) string {
+	for _, obj := range reconciler.requestedResources {
+		if secret, ok := obj.(*corev1.Secret); ok && secret.Name == passwordSecretName {
+			return secret.StringData[certificateKeyStoreKey]
+		}
+	}
+
+	password := ""
+	if obj := reconciler.cloneOfDeployed(reflect.TypeOf(corev1.Secret{}), passwordSecretName); obj != nil {
+		password = string(obj.(*corev1.Secret).Data[certificateKeyStoreKey])
+	}
+	if password == "" {
+		password = random.GenerateRandomString(16)
+	}
+	namespacedName := types.NamespacedName{Name: customResource.Name, Namespace: customResource.Namespace}
+	reconciler.trackDesired(secrets.NewSecret(namespacedName, passwordSecretName, map[string]string{certificateKeyStoreKey: password}, namer.LabelBuilder.Labels()))
+	return password
+}
+
+func acceptorExposedHosts(customResource *brokerv1beta1.ActiveMQArtemis, acceptor brokerv1beta1.AcceptorType) []string {
+	hosts := []string{}
+	if !acceptor.Expose {
+		return hosts
+	}
+	domain := customResource.Spec.IngressDomain
+	isOpenshift, _ := environments.DetectOpenshift()
+	if domain == "" && !isOpenshift {
+		domain = ingresses.DefaultIngressDomain
+	}
+	if domain != "" {
+		suffix := "-rte"
+		if !isOpenshift {
+			suffix = "-ing"
+		}
+		for i := int32(0); i < getDeploymentSize(customResource); i++ {
+			hosts = append(hosts, customResource.Name+"-"+acceptor.Name+"-"+strconv.Itoa(int(i))+"-svc"+suffix+"."+domain)
+		}
+	}
+	return hosts
+}
+
+func newCertificate(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, name string, issuer *brokerv1beta1.CertificateIssuerType, secretName string, passwordSecretName string, exposedHosts []string) *unstructured.Unstructured {
+
+	headless := namer.SvcHeadlessNameBuilder.Name()
+	dnsNames := []string{
+		"*." + headless + "." + customResource.Namespace + ".svc",
+		"*." + headless + "." + customResource.Namespace + ".svc.cluster.local",
+	}
+	dnsNames = append(dnsNames, exposedHosts...)
+	dnsNames = append(dnsNames, issuer.DNSNames...)
+
+	kind := issuer.Kind
+	if kind == "" {
+		kind = defaultCertificateIssuer
+	}
+	group := issuer.Group
+	if group == "" {
+		group = "cert-manager.io"
+	}
+
+	certificate := &unstructured.Unstructured{}
+	certificate.SetAPIVersion(certificateAPIVersion)
+	certificate.SetKind("Certificate")
+	certificate.SetName(customResource.Name + "-" + name + "-cert")
+	certificate.SetNamespace(customResource.Namespace)
+	certificate.SetLabels(namer.LabelBuilder.Labels())
+	certificate.Object["spec"] = map[string]interface{}{
+		"secretName": secretName,
+		"dnsNames":   toInterfaceSlice(dnsNames),
+		"issuerRef": map[string]interface{}{
+			"name":  issuer.Name,
+			"kind":  kind,
+			"group": group,
+		},
+		"keystores": map[string]interface{}{
+			"pkcs12": map[string]interface{}{
+				"create": true,
+				"passwordSecretRef": map[string]interface{}{
+					"name": passwordSecretName,
+					"key":  certificateKeyStoreKey,
+				},
+			},
+		},
+	}
+	return certificate
+}
+
+func toInterfaceSlice(values []string) []interface{} {
+	result := make([]interface{}, len(values))
+	for i, value := range values {
+		result[i] = value
+	}
+	return result
+}
+
+// the cert-manager types are not part of the scheme, the Certificate is applied directly
+// rather than through the deployed resources, it goes away with the cr or with its acceptor
+func (reconciler *ActiveMQArtemisReconcilerImpl) applyCertificate(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, scheme *runtime.Scheme, certificate *unstructured.Unstructured) {
+	if reconciler.appliedCertificates == nil {
+		reconciler.appliedCertificates = map[string]bool{}
+	}
+	reconciler.appliedCertificates[certificate.GetKind()+"/"+certificate.GetName()] = true
+
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(certificate.GroupVersionKind())
+	err := client.Get(context.TODO(), types.NamespacedName{Name: certificate.GetName(), Namespace: certificate.GetNamespace()}, existing)
+	if k8serrors.IsNotFound(err) {
+		if err = resources.Create(customResource, client, scheme, certificate); err != nil {
+			clog.Error(err, "unable to create certificate", "name", certificate.GetName())
+		}
+		return
+	}
+	if err != nil {
+		clog.Error(err, "unable to retrieve certificate", "name", certificate.GetName())
+		return
+	}
+	if equality.Semantic.DeepEqual(existing.Object["spec"], certificate.Object["spec"]) {
+		return
+	}
+	existing.Object["spec"] = certificate.Object["spec"]
+	if err = resources.Update(client, existing); err != nil {
+		clog.Error(err, "unable to update certificate", "name", certificate.GetName())
+	}
+}
+
+// deleteStaleCertificates removes the Certificates and Issuers of the cr that this reconcile didn't
+// apply, their acceptor, connector or cluster tls was removed or no longer uses an issuer
+func (reconciler *ActiveMQArtemisReconcilerImpl) deleteStaleCertificates(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, client rtclient.Client) {
+	for _, kind := range []string{"Certificate", defaultCertificateIssuer} {
+		list := &unstructured.UnstructuredList{}
+		list.SetAPIVersion(certificateAPIVersion)
+		list.SetKind(kind + "List")
+		if err := client.List(context.TODO(), list, rtclient.InNamespace(customResource.Namespace), rtclient.MatchingLabels(namer.LabelBuilder.Labels())); err != nil {
+			if !meta.IsNoMatchError(err) {
+				clog.Error(err, "unable to list certificates", "kind", kind)
+			}
+			continue
+		}
+		for index := range list.Items {
+			existing := &list.Items[index]
+			if reconciler.appliedCertificates[kind+"/"+existing.GetName()] || !metav1.IsControlledBy(existing, customResource) {
+				continue
+			}
+			if err := resources.Delete(client, existing); err != nil && !k8serrors.IsNotFound(err) {
+				clog.Error(err, "unable to delete certificate", "kind", kind, "name", existing.GetName())
+			}
+		}
+	}
+}
+
+const defaultPlaceholderImage = "registry.k8s.io/pause:3.9"
+
+func getCapacityPlaceholderName(customResource *brokerv1beta1.ActiveMQArtemis) string {
+	return customResource.Name + "-capacity-placeholder"
+}
+
+// the placeholder pods request what a broker pod requests, with a lower priority they are
+// preempted by a pending broker and the cluster autoscaler reacts to the placeholders left pending
+func (reconciler *ActiveMQArtemisReconcilerImpl) applyCapacityPlaceholders(customResource *brokerv1beta1.ActiveMQArtemis) {
+	placeholders := customResource.Spec.DeploymentPlan.CapacityPlaceholders
+
+	image := placeholders.Image
+	if image == "" {
+		image = defaultPlaceholderImage
+	}
+	// must not match the broker pod selectors of the services and the pod disruption budget
+	labels := map[string]string{"ActiveMQArtemisCapacityPlaceholder": customResource.Name}
+	replicas := placeholders.Replicas
+	gracePeriod := int64(0)
+
+	podSpec := corev1.PodSpec{
+		PriorityClassName:             placeholders.PriorityClassName,
+		TerminationGracePeriodSeconds: &gracePeriod,
+		NodeSelector:                  customResource.Spec.DeploymentPlan.NodeSelector,
+		Tolerations:                   customResource.Spec.DeploymentPlan.Tolerations,
+		Containers: []corev1.Container{{
+			Name:      "placeholder",
+			Image:     image,
+			Resources: customResource.Spec.DeploymentPlan.Resources,
+		}},
+	}
+	configureAffinity(&podSpec, &customResource.Spec.DeploymentPlan.Affinity)
+
+	deployment := appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getCapacityPlaceholderName(customResource),
+			Namespace: customResource.Namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec:       podSpec,
+			},
+		},
+	}
+
+	reconciler.trackDesired(&deployment)
 }
 
 func (reconciler *ActiveMQArtemisReconcilerImpl) applyPodDisruptionBudget(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, currentStatefulSet *appsv1.StatefulSet) {
@@ -361,8 +1108,12 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) applyPodDisruptionBudget(custom
 
 func (reconciler *ActiveMQArtemisReconcilerImpl) ProcessAcceptorsAndConnectors(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, client rtclient.Client, scheme *runtime.Scheme, currentStatefulSet *appsv1.StatefulSet) {
 
-	acceptorEntry := generateAcceptorsString(customResource, namer, client)
-	connectorEntry := generateConnectorsString(customResource, namer, client)
+	keyStorePasswords := reconciler.applyAcceptorCertificates(customResource, namer, client, scheme)
+	connectorKeyStorePasswords := reconciler.applyConnectorCertificates(customResource, namer, client, scheme)
+	reconciler.deleteStaleCertificates(customResource, namer, client)
+
+	acceptorEntry := generateAcceptorsString(customResource, namer, client, keyStorePasswords)
+	connectorEntry := generateConnectorsString(customResource, namer, client, connectorKeyStorePasswords)
 
 	reconciler.configureAcceptorsExposure(customResource, namer, client, scheme)
 	reconciler.configureConnectorsExposure(customResource, namer, client, scheme)
@@ -385,23 +1136,31 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) ProcessAcceptorsAndConnectors(c
 
 func (reconciler *ActiveMQArtemisReconcilerImpl) ProcessConsole(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, client rtclient.Client, scheme *runtime.Scheme, currentStatefulSet *appsv1.StatefulSet) {
 
-	reconciler.configureConsoleExposure(customResource, namer, client, scheme)
-	if !customResource.Spec.Console.SSLEnabled {
+	if customResource.Spec.Console.Disabled {
 		return
 	}
 
-	secretName := namer.SecretsConsoleNameBuilder.Name()
+	reconciler.configureConsoleExposure(customResource, namer, client, scheme)
+	if customResource.Spec.Console.SSLEnabled {
+		secretName := namer.SecretsConsoleNameBuilder.Name()
 
-	envVars := map[string]ValueInfo{"AMQ_CONSOLE_ARGS": {
-		Value:    generateConsoleSSLFlags(customResource, namer, client, secretName),
-		AutoGen:  true,
-		Internal: true,
-	}}
+		envVars := map[string]ValueInfo{"AMQ_CONSOLE_ARGS": {
+			Value:    generateConsoleSSLFlags(customResource, namer, client, secretName),
+			AutoGen:  true,
+			Internal: true,
+		}}
 
-	reconciler.sourceEnvVarFromSecret(customResource, namer, currentStatefulSet, &envVars, secretName, client, scheme)
+		reconciler.sourceEnvVarFromSecret(customResource, namer, currentStatefulSet, &envVars, secretName, client, scheme)
+	}
+
+	// added last so that the broker credentials and console args sourced above stay out of the proxy
+	if proxy := makeConsoleSSOProxyContainer(customResource); proxy != nil {
+		reconciler.applyConsoleSSOCookieSecret(customResource, namer)
+		currentStatefulSet.Spec.Template.Spec.Containers = append(currentStatefulSet.Spec.Template.Spec.Containers, *proxy)
+	}
 }
 
-func syncMessageMigration(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, client rtclient.Client, scheme *runtime.Scheme) {
+func syncMessageMigration(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, client rtclient.Client, scheme *runtime.Scheme) error {
 
 	var err error = nil
 	var retrieveError error = nil
@@ -421,6 +1180,17 @@ func syncMessageMigration(customResource *brokerv1beta1.ActiveMQArtemis, namer N
 	ssNames["SERVICE_ACCOUNT"] = os.Getenv("SERVICE_ACCOUNT")
 	ssNames["SERVICE_ACCOUNT_NAME"] = os.Getenv("SERVICE_ACCOUNT")
 	ssNames["AMQ_CREDENTIALS_SECRET_NAME"] = namer.SecretsCredentialsNameBuilder.Name()
+	ssNames[draincontroller.AnnotationHAReplication] = strconv.FormatBool(isHAEnabled(customResource))
+
+	keyRing, err := envelope.LoadKeyRing(client)
+	if err != nil {
+		return fmt.Errorf("unable to load the operator encryption keys for message migration: %v", err)
+	}
+	if keyRing != nil {
+		if err = sealCredentialAnnotations(keyRing, ssNames); err != nil {
+			return fmt.Errorf("unable to seal the cluster credentials for message migration: %v", err)
+		}
+	}
 
 	scaledown := &brokerv1beta1.ActiveMQArtemisScaledown{
 		TypeMeta: metav1.TypeMeta{
@@ -436,9 +1206,12 @@ func syncMessageMigration(customResource *brokerv1beta1.ActiveMQArtemis, namer N
 		Spec: brokerv1beta1.ActiveMQArtemisScaledownSpec{
 			LocalOnly: isLocalOnly(),
 			Resources: customResource.Spec.DeploymentPlan.Resources,
+			Drainer:   customResource.Spec.DeploymentPlan.Drainer,
 		},
 		Status: brokerv1beta1.ActiveMQArtemisScaledownStatus{},
 	}
+	drainerResources := scaledown.Spec.Resources
+	drainer := scaledown.Spec.Drainer
 
 	if nil == customResource.Spec.DeploymentPlan.MessageMigration {
 		customResource.Spec.DeploymentPlan.MessageMigration = &defaultMessageMigration
@@ -447,9 +1220,17 @@ func syncMessageMigration(customResource *brokerv1beta1.ActiveMQArtemis, namer N
 	clustered := isClustered(customResource)
 
 	if *customResource.Spec.DeploymentPlan.MessageMigration && clustered {
-		if !customResource.Spec.DeploymentPlan.PersistenceEnabled {
-			clog.Info("Won't set up scaledown for non persistent deployment")
-			return
+		if !requiresPersistentVolume(customResource) {
+			clog.Info("Won't set up scaledown for deployment without persistent volumes")
+			return nil
+		}
+		if isHASharedStore(customResource) {
+			// the journal of a removed pair stays in its directory of the shared volume, there is nothing to drain
+			clog.Info("Won't set up scaledown for deployment with a shared store")
+			if err = resources.Retrieve(namespacedName, client, scaledown); err == nil {
+				resources.Delete(client, scaledown)
+			}
+			return nil
 		}
 		clog.Info("we need scaledown for this cr", "crName", customResource.Name, "scheme", scheme)
 		if err = resources.Retrieve(namespacedName, client, scaledown); err != nil {
@@ -460,6 +1241,38 @@ func syncMessageMigration(customResource *brokerv1beta1.ActiveMQArtemis, namer N
 			} else {
 				clog.Error(retrieveError, "we have error retrieving drainer", "drainer", scaledown, "scheme", scheme)
 			}
+		} else {
+			// the changes of the annotations and the spec go out in a single update
+			updated := false
+			if scaledown.Annotations == nil {
+				scaledown.Annotations = map[string]string{}
+			}
+			if scaledown.Annotations[draincontroller.AnnotationHAReplication] != ssNames[draincontroller.AnnotationHAReplication] {
+				// the drainer leaves the backups of replicated pairs alone
+				scaledown.Annotations[draincontroller.AnnotationHAReplication] = ssNames[draincontroller.AnnotationHAReplication]
+				updated = true
+			}
+			if keyRing != nil && credentialAnnotationsNeedSealing(keyRing, scaledown.Annotations) {
+				// the active key was rotated or sealing was turned on after the drainer cr was created
+				if err = sealCredentialAnnotations(keyRing, scaledown.Annotations); err != nil {
+					return fmt.Errorf("unable to reseal the cluster credentials of drainer %v: %v", scaledown.Name, err)
+				}
+				updated = true
+			}
+			// the drain controller builds the next drainer pod from the updated cr
+			if !reflect.DeepEqual(scaledown.Spec.Resources, drainerResources) {
+				scaledown.Spec.Resources = drainerResources
+				updated = true
+			}
+			if !reflect.DeepEqual(scaledown.Spec.Drainer, drainer) {
+				scaledown.Spec.Drainer = drainer
+				updated = true
+			}
+			if updated {
+				if err = resources.Update(client, scaledown); err != nil {
+					return fmt.Errorf("unable to update drainer %v: %v", scaledown.Name, err)
+				}
+			}
 		}
 	} else {
 		if err = resources.Retrieve(namespacedName, client, scaledown); err == nil {
@@ -468,6 +1281,33 @@ func syncMessageMigration(customResource *brokerv1beta1.ActiveMQArtemis, namer N
 			resources.Delete(client, scaledown)
 		}
 	}
+	return nil
+}
+
+var credentialAnnotations = []string{"CLUSTERUSER", "CLUSTERPASS"}
+
+func credentialAnnotationsNeedSealing(keyRing *envelope.KeyRing, annotations map[string]string) bool {
+	for _, key := range credentialAnnotations {
+		if value, found := annotations[key]; found && keyRing.NeedsSealing(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func sealCredentialAnnotations(keyRing *envelope.KeyRing, annotations map[string]string) error {
+	for _, key := range credentialAnnotations {
+		value, found := annotations[key]
+		if !found {
+			continue
+		}
+		sealed, err := keyRing.Reseal(key, value)
+		if err != nil {
+			return err
+		}
+		annotations[key] = sealed
+	}
+	return nil
 }
 
 func isLocalOnly() bool {
@@ -605,7 +1445,7 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) sourceEnvVarFromSecret(customRe
 
 }
 
-func generateAcceptorsString(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, client rtclient.Client) string {
+func generateAcceptorsString(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, client rtclient.Client, keyStorePasswords map[string]string) string {
 
 	// TODO: Optimize for the single broker configuration
 	ensureCOREOn61616Exists := true // as clustered is no longer an option but true by default
@@ -618,6 +1458,8 @@ func generateAcceptorsString(customResource *brokerv1beta1.ActiveMQArtemis, name
 	var port61616InUse bool = false
 	var i uint32 = 0
 	for _, acceptor := range customResource.Spec.Acceptors {
+		acceptor = applyAcceptorPreset(acceptor)
+		customResource.Spec.Acceptors[i].Port = acceptor.Port
 		if acceptor.Port == 0 {
 			acceptor.Port = 61626 + currentPortIncrement
 			currentPortIncrement += portIncrement
@@ -628,8 +1470,10 @@ func generateAcceptorsString(customResource *brokerv1beta1.ActiveMQArtemis, name
 			acceptor.Protocols = "AMQP,CORE,HORNETQ,MQTT,OPENWIRE,STOMP"
 		}
 		bindAddress := "ACCEPTOR_IP"
-		if acceptor.BindToAllInterfaces != nil && *acceptor.BindToAllInterfaces {
-			bindAddress = "0.0.0.0"
+		if acceptor.BindInterface != "" {
+			bindAddress = bindInterfacePlaceholder + acceptor.BindInterface
+		} else if acceptor.BindToAllInterfaces != nil && *acceptor.BindToAllInterfaces {
+			bindAddress = wildcardBindAddress(customResource)
 		}
 		acceptorEntry = acceptorEntry + "<acceptor name=\"" + acceptor.Name + "\">"
 		acceptorEntry = acceptorEntry + "tcp:" + "\\/\\/" + bindAddress + ":"
@@ -649,7 +1493,17 @@ func generateAcceptorsString(customResource *brokerv1beta1.ActiveMQArtemis, name
 			if acceptor.SSLSecret != "" {
 				secretName = acceptor.SSLSecret
 			}
-			acceptorEntry = acceptorEntry + ";" + generateAcceptorConnectorSSLArguments(customResource, namer, client, secretName)
+			if password, issued := keyStorePasswords[acceptor.Name]; issued {
+				acceptorEntry = acceptorEntry + ";" + generateIssuedCertificateSSLArguments(secretName, password)
+			} else {
+				acceptorEntry = acceptorEntry + ";" + generateAcceptorConnectorSSLArguments(customResource, namer, client, acceptor.Name, secretName)
+			}
+			if getTLSRenewal(customResource) == TLSRenewalReload {
+				acceptorEntry = acceptorEntry + ";" + "sslAutoReload=true"
+			}
+			if acceptorExposeMode(acceptor) == brokerv1beta1.ExposeModeSNI && acceptor.SNIHost == "" {
+				acceptor.SNIHost = sniHostPattern(customResource, acceptor)
+			}
 			sslOptionalArguments := generateAcceptorSSLOptionalArguments(acceptor)
 			if sslOptionalArguments != "" {
 				acceptorEntry = acceptorEntry + ";" + sslOptionalArguments
@@ -675,7 +1529,19 @@ func generateAcceptorsString(customResource *brokerv1beta1.ActiveMQArtemis, name
 		if acceptor.SuppressInternalManagementObjects != nil {
 			acceptorEntry = acceptorEntry + ";" + "suppressInternalManagementObjects=" + strconv.FormatBool(*acceptor.SuppressInternalManagementObjects)
 		}
-		acceptorEntry = acceptorEntry + ";" + defaultArgs
+		if acceptor.SASLMechanisms != "" {
+			acceptorEntry = acceptorEntry + ";" + "saslMechanisms=" + acceptor.SASLMechanisms
+			if scope := saslLoginConfigScope(customResource, acceptor); scope != "" {
+				acceptorEntry = acceptorEntry + ";" + "saslLoginConfigScope=" + scope
+			}
+		}
+		if acceptor.ProxyProtocol {
+			acceptorEntry = acceptorEntry + ";" + "proxyProtocolEnabled=true"
+		}
+		acceptorEntry = acceptorEntry + transportParamsString(acceptor.Params)
+		if args := withoutOverriddenArgs(defaultArgs, acceptor.Params); args != "" {
+			acceptorEntry = acceptorEntry + ";" + args
+		}
 
 		acceptorEntry = acceptorEntry + "<\\/acceptor>"
 
@@ -689,96 +1555,551 @@ func generateAcceptorsString(customResource *brokerv1beta1.ActiveMQArtemis, name
 		acceptorEntry = acceptorEntry + fmt.Sprintf("%d", 61616)
 		acceptorEntry = acceptorEntry + "?protocols=" + "CORE"
 		acceptorEntry = acceptorEntry + ";" + defaultArgs
-		// TODO: SSL
 		acceptorEntry = acceptorEntry + "<\\/acceptor>"
 	}
-
-	return acceptorEntry
+	if password, found := keyStorePasswords[clusterTLSName]; found {
+		acceptorEntry = acceptorEntry + "<acceptor name=\"" + clusterTLSName + "\">"
+		acceptorEntry = acceptorEntry + "tcp:" + "\\/\\/" + "ACCEPTOR_IP:"
+		acceptorEntry = acceptorEntry + fmt.Sprintf("%d", clusterTLSPort)
+		acceptorEntry = acceptorEntry + "?protocols=" + "CORE"
+		acceptorEntry = acceptorEntry + ";" + generateIssuedCertificateSSLArguments(clusterTLSSecretName(customResource), password) + ";needClientAuth=true"
+		if getTLSRenewal(customResource) == TLSRenewalReload {
+			acceptorEntry = acceptorEntry + ";" + "sslAutoReload=true"
+		}
+		acceptorEntry = acceptorEntry + ";" + defaultArgs
+		acceptorEntry = acceptorEntry + "<\\/acceptor>"
+	}
+
+	return acceptorEntry
+}
+
+// params are sorted so the generated url, and with it the statefulset, stays stable between reconciles
+func transportParamsString(params map[string]string) string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	paramsString := ""
+	for _, key := range keys {
+		paramsString = paramsString + ";" + key + "=" + strings.Replace(params[key], "/", "\\/", -1)
+	}
+	return paramsString
+}
+
+func withoutOverriddenArgs(args string, params map[string]string) string {
+	kept := []string{}
+	for _, arg := range strings.Split(args, ";") {
+		if _, overridden := params[strings.SplitN(arg, "=", 2)[0]]; !overridden {
+			kept = append(kept, arg)
+		}
+	}
+	return strings.Join(kept, ";")
+}
+
+// an explicit scope wins, otherwise GSSAPI is wired to the first kerberos module of the applicable security cr
+func saslLoginConfigScope(customResource *brokerv1beta1.ActiveMQArtemis, acceptor brokerv1beta1.AcceptorType) string {
+	if acceptor.SASLLoginConfigScope != "" {
+		return acceptor.SASLLoginConfigScope
+	}
+	if !strings.Contains(strings.ToUpper(acceptor.SASLMechanisms), "GSSAPI") {
+		return ""
+	}
+	if securityCR := getApplicableSecurityCR(customResource); securityCR != nil && len(securityCR.Spec.LoginModules.KerberosLoginModules) > 0 {
+		return securityCR.Spec.LoginModules.KerberosLoginModules[0].Name
+	}
+	return ""
+}
+
+func generateConnectorsString(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, client rtclient.Client, keyStorePasswords map[string]string) string {
+
+	connectorEntry := ""
+	connectors := customResource.Spec.Connectors
+	for _, connector := range connectors {
+		if connector.Type == "" {
+			connector.Type = "tcp"
+		}
+		connectorEntry = connectorEntry + "<connector name=\"" + connector.Name + "\">"
+		connectorEntry = connectorEntry + strings.ToLower(connector.Type) + ":\\/\\/" + strings.ToLower(connector.Host) + ":"
+		connectorEntry = connectorEntry + fmt.Sprintf("%d", connector.Port)
+
+		if connector.SSLEnabled {
+			secretName := customResource.Name + "-" + connector.Name + "-secret"
+			if connector.SSLSecret != "" {
+				secretName = connector.SSLSecret
+			}
+			if password, issued := keyStorePasswords[connector.Name]; issued {
+				connectorEntry = connectorEntry + "?" + generateIssuedCertificateSSLArguments(secretName, password)
+			} else {
+				connectorEntry = connectorEntry + "?" + generateAcceptorConnectorSSLArguments(customResource, namer, client, connector.Name, secretName)
+			}
+			sslOptionalArguments := generateConnectorSSLOptionalArguments(connector)
+			if sslOptionalArguments != "" {
+				connectorEntry = connectorEntry + ";" + sslOptionalArguments
+			}
+		}
+		if params := transportParamsString(connector.Params); params != "" {
+			if !connector.SSLEnabled {
+				params = "?" + strings.TrimPrefix(params, ";")
+			}
+			connectorEntry = connectorEntry + params
+		}
+		connectorEntry = connectorEntry + "<\\/connector>"
+	}
+
+	return connectorEntry
+}
+
+func (reconciler *ActiveMQArtemisReconcilerImpl) configureAcceptorsExposure(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, client rtclient.Client, scheme *runtime.Scheme) {
+	originalLabels := namer.LabelBuilder.Labels()
+	namespacedName := types.NamespacedName{
+		Name:      customResource.Name,
+		Namespace: customResource.Namespace,
+	}
+	for _, acceptor := range customResource.Spec.Acceptors {
+		if acceptor.Expose && isServiceExposeMode(acceptorExposeMode(acceptor)) && !acceptor.ExposePerPod {
+			reconciler.trackExposedService(customResource, namer, client, acceptor, acceptor.Name, originalLabels, 0)
+		}
+	}
+	deploymentSize := getDeploymentSize(customResource)
+	for i := int32(0); i < deploymentSize; i++ {
+		ordinalString := strconv.Itoa(int(i))
+		var serviceRoutelabels = make(map[string]string)
+		for k, v := range originalLabels {
+			serviceRoutelabels[k] = v
+		}
+		serviceRoutelabels["statefulset.kubernetes.io/pod-name"] = namer.SsNameBuilder.Name() + "-" + ordinalString
+
+		for _, acceptor := range customResource.Spec.Acceptors {
+			serviceDefinition := svc.NewServiceDefinitionForCR("", client, namespacedName, acceptor.Name+"-"+ordinalString, acceptor.Port, serviceRoutelabels, namer.LabelBuilder.Labels())
+			applyServiceSettings(serviceDefinition, acceptor.ServiceSettings)
+			reconciler.configureIPFamilies(customResource, serviceDefinition)
+			nameMeshPorts(customResource, serviceDefinition, meshTransportProtocol(acceptor.SSLEnabled))
+
+			reconciler.checkExistingService(customResource, serviceDefinition, client)
+			reconciler.trackDesired(serviceDefinition)
+
+			if acceptor.Expose {
+
+				targetPortName := acceptor.Name + "-" + ordinalString
+				targetServiceName := customResource.Name + "-" + targetPortName + "-svc"
+				routePortName := meshPortName(customResource, targetPortName, meshTransportProtocol(acceptor.SSLEnabled))
+
+				switch acceptorExposeMode(acceptor) {
+				case brokerv1beta1.ExposeModeLoadBalancer, brokerv1beta1.ExposeModeNodePort:
+					if acceptor.ExposePerPod {
+						reconciler.trackExposedService(customResource, namer, client, acceptor, targetPortName, serviceRoutelabels, i)
+					}
+				case brokerv1beta1.ExposeModeRoute:
+					reconciler.trackDesired(reconciler.routeDefinitionForCR(namespacedName, serviceRoutelabels, targetServiceName, routePortName, acceptor.SSLEnabled, customResource.Spec.IngressDomain, withExternalDNS(acceptorExposeAnnotations(acceptor), acceptor.ExternalDNS, i), routeTLSConfig(customResource, client, acceptor.RouteTLS)))
+				case brokerv1beta1.ExposeModeIngress:
+					reconciler.trackDesired(reconciler.ingressDefinitionForCR(namespacedName, serviceRoutelabels, targetServiceName, routePortName, acceptor.SSLEnabled, customResource.Spec.IngressDomain, acceptor.IngressClassName, withExternalDNS(acceptorExposeAnnotations(acceptor), acceptor.ExternalDNS, i)))
+				default:
+					exposureDefinition := reconciler.ExposureDefinitionForCR(namespacedName, serviceRoutelabels, targetServiceName, routePortName, acceptor.SSLEnabled, customResource.Spec.IngressDomain, acceptor.IngressClassName, withExternalDNS(acceptorExposeAnnotations(acceptor), acceptor.ExternalDNS, i), routeTLSConfig(customResource, client, acceptor.RouteTLS))
+					reconciler.trackDesired(exposureDefinition)
+				}
+			}
+		}
+	}
+}
+
+// applyServiceSettings sets the fields the api server would otherwise default, so that removing a
+// setting from the CR reverts it. The desired service starts from the deployed one, so a policy the
+// cluster has defaulted is only reset when it is there
+func applyServiceSettings(service *corev1.Service, settings *brokerv1beta1.ServiceSettingsType) {
+	if settings == nil {
+		settings = &brokerv1beta1.ServiceSettingsType{}
+	}
+
+	service.Spec.SessionAffinity = corev1.ServiceAffinityNone
+	if settings.SessionAffinity != "" {
+		service.Spec.SessionAffinity = settings.SessionAffinity
+	}
+	if service.Spec.SessionAffinity == corev1.ServiceAffinityNone {
+		service.Spec.SessionAffinityConfig = nil
+	}
+
+	// only valid for services that take traffic from outside the cluster
+	if service.Spec.Type == corev1.ServiceTypeLoadBalancer || service.Spec.Type == corev1.ServiceTypeNodePort {
+		service.Spec.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyTypeCluster
+		if settings.ExternalTrafficPolicy != "" {
+			service.Spec.ExternalTrafficPolicy = settings.ExternalTrafficPolicy
+		}
+	} else {
+		service.Spec.ExternalTrafficPolicy = ""
+	}
+
+	if settings.InternalTrafficPolicy != nil {
+		policy := *settings.InternalTrafficPolicy
+		service.Spec.InternalTrafficPolicy = &policy
+	} else if service.Spec.InternalTrafficPolicy != nil {
+		policy := corev1.ServiceInternalTrafficPolicyCluster
+		service.Spec.InternalTrafficPolicy = &policy
+	}
+}
+
+// the api server allocates node ports, desired ports without them would be seen as a change on every reconcile
+func (reconciler *ActiveMQArtemisReconcilerImpl) keepAllocatedNodePorts(desired *corev1.Service) {
+	obj := reconciler.getFromDeployed(reflect.TypeOf(corev1.Service{}), desired.Name)
+	if obj == nil {
+		return
+	}
+	for _, deployedPort := range obj.(*corev1.Service).Spec.Ports {
+		for i := range desired.Spec.Ports {
+			if desired.Spec.Ports[i].Name == deployedPort.Name && desired.Spec.Ports[i].NodePort == 0 {
+				desired.Spec.Ports[i].NodePort = deployedPort.NodePort
+			}
+		}
+	}
+}
+
+func acceptorExposeMode(acceptor brokerv1beta1.AcceptorType) brokerv1beta1.ExposeMode {
+	if acceptor.ExposeMode == nil {
+		return ""
+	}
+	return *acceptor.ExposeMode
+}
+
+// platformExposeMode resolves the modes that leave the choice between a route and an ingress to the platform
+func platformExposeMode(mode brokerv1beta1.ExposeMode) brokerv1beta1.ExposeMode {
+	if mode != "" && mode != brokerv1beta1.ExposeModeSNI {
+		return mode
+	}
+	if isOpenshift, err := environments.DetectOpenshift(); isOpenshift && err == nil {
+		return brokerv1beta1.ExposeModeRoute
+	}
+	return brokerv1beta1.ExposeModeIngress
+}
+
+// sniHostPattern matches the host names of the per pod routes or ingresses of an acceptor, a
+// route without a domain gets its host from the router, which appends the namespace
+func sniHostPattern(customResource *brokerv1beta1.ActiveMQArtemis, acceptor brokerv1beta1.AcceptorType) string {
+	prefix := strings.ReplaceAll(customResource.Name+"-"+acceptor.Name, ".", "[.]") + "-[0-9]+-svc"
+	if platformExposeMode(acceptorExposeMode(acceptor)) == brokerv1beta1.ExposeModeRoute {
+		return prefix + "-rte[.-].+"
+	}
+	return prefix + "-ing[.].+"
+}
+
+// the modes that expose an acceptor through a Service of its own rather than a Route or an Ingress
+func isServiceExposeMode(mode brokerv1beta1.ExposeMode) bool {
+	return mode == brokerv1beta1.ExposeModeLoadBalancer || mode == brokerv1beta1.ExposeModeNodePort
+}
+
+func exposedServiceName(customResource *brokerv1beta1.ActiveMQArtemis, portName string, mode brokerv1beta1.ExposeMode) string {
+	if mode == brokerv1beta1.ExposeModeNodePort {
+		return customResource.Name + "-" + portName + "-np-svc"
+	}
+	return customResource.Name + "-" + portName + "-lb-svc"
+}
+
+// trackExposedService exposes an acceptor through a LoadBalancer or NodePort Service, selecting
+// every broker pod or a single one depending on the selector labels
+func (reconciler *ActiveMQArtemisReconcilerImpl) trackExposedService(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, client rtclient.Client, acceptor brokerv1beta1.AcceptorType, portName string, selectorLabels map[string]string, ordinal int32) {
+	mode := acceptorExposeMode(acceptor)
+	namespacedName := types.NamespacedName{Name: customResource.Name, Namespace: customResource.Namespace}
+	serviceDefinition := svc.NewServiceDefinitionForCR(exposedServiceName(customResource, portName, mode), client, namespacedName, portName, acceptor.Port, selectorLabels, namer.LabelBuilder.Labels())
+	if mode == brokerv1beta1.ExposeModeNodePort {
+		serviceDefinition.Spec.Type = corev1.ServiceTypeNodePort
+		if acceptor.NodePort != nil {
+			serviceDefinition.Spec.Ports[0].NodePort = *acceptor.NodePort + ordinal
+		}
+	} else {
+		serviceDefinition.Spec.Type = corev1.ServiceTypeLoadBalancer
+	}
+	nameMeshPorts(customResource, serviceDefinition, meshTransportProtocol(acceptor.SSLEnabled))
+	reconciler.keepAllocatedNodePorts(serviceDefinition)
+	applyServiceSettings(serviceDefinition, acceptor.ServiceSettings)
+	reconciler.configureIPFamilies(customResource, serviceDefinition)
+	// only ready brokers are sent traffic from outside the cluster
+	serviceDefinition.Spec.PublishNotReadyAddresses = false
+	clearExposeAnnotations(serviceDefinition)
+	exposeAnnotations := withExternalDNS(acceptorExposeAnnotations(acceptor), acceptor.ExternalDNS, ordinal)
+	if acceptor.ExposePerPod {
+		setExposeAnnotations(serviceDefinition, exposeAnnotations)
+	} else {
+		setExposeAnnotations(serviceDefinition, withZoneRouting(customResource, exposeAnnotations))
+	}
+
+	reconciler.checkExistingService(customResource, serviceDefinition, client)
+	reconciler.trackDesired(serviceDefinition)
+}
+
+func hasExternalConnectors(customResource *brokerv1beta1.ActiveMQArtemis) bool {
+	for _, acceptor := range customResource.Spec.Acceptors {
+		if acceptor.Expose && acceptor.PublishExternalConnectors {
+			return true
+		}
+	}
+	return false
+}
+
+// cluster aware clients are handed the pod dns names, which don't resolve outside the cluster, so
+// the exposed address of every pod is published as a connector for connection routers to redirect to
+func (reconciler *ActiveMQArtemisReconcilerImpl) externalConnectorProperties(customResource *brokerv1beta1.ActiveMQArtemis) []string {
+	props := []string{}
+	for _, acceptor := range customResource.Spec.Acceptors {
+		if !acceptor.Expose || !acceptor.PublishExternalConnectors {
+			continue
+		}
+		for i := int32(0); i < getDeploymentSize(customResource); i++ {
+			// addresses assigned by the cluster are published on a later reconcile
+			host, port, tls, sni := reconciler.externalAddress(customResource, acceptor, i)
+			if host == "" {
+				continue
+			}
+			prefix := fmt.Sprintf("connectorConfigurations.%s-external-%d.", acceptor.Name, i)
+			props = append(props,
+				prefix+"factoryClassName=org.apache.activemq.artemis.core.remoting.impl.netty.NettyConnectorFactory",
+				prefix+"params.host="+host,
+				fmt.Sprintf("%sparams.port=%d", prefix, port))
+			if tls {
+				props = append(props, prefix+"params.sslEnabled=true")
+			}
+			if sni {
+				props = append(props, prefix+"params.sniHost="+host)
+			}
+		}
+	}
+	return props
 }
 
-func generateConnectorsString(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, client rtclient.Client) string {
+// externalAddress reads the address a pod is exposed at from the deployed route, ingress or
+// service, sni is set when the address is shared with other pods and told apart by the host name
+func (reconciler *ActiveMQArtemisReconcilerImpl) externalAddress(customResource *brokerv1beta1.ActiveMQArtemis, acceptor brokerv1beta1.AcceptorType, ordinal int32) (host string, port int32, tls bool, sni bool) {
+	portName := acceptor.Name + "-" + strconv.Itoa(int(ordinal))
+	targetServiceName := customResource.Name + "-" + portName + "-svc"
 
-	connectorEntry := ""
-	connectors := customResource.Spec.Connectors
-	for _, connector := range connectors {
-		if connector.Type == "" {
-			connector.Type = "tcp"
-		}
-		connectorEntry = connectorEntry + "<connector name=\"" + connector.Name + "\">"
-		connectorEntry = connectorEntry + strings.ToLower(connector.Type) + ":\\/\\/" + strings.ToLower(connector.Host) + ":"
-		connectorEntry = connectorEntry + fmt.Sprintf("%d", connector.Port)
+	mode := platformExposeMode(acceptorExposeMode(acceptor))
 
-		if connector.SSLEnabled {
-			secretName := customResource.Name + "-" + connector.Name + "-secret"
-			if connector.SSLSecret != "" {
-				secretName = connector.SSLSecret
+	switch mode {
+	case brokerv1beta1.ExposeModeRoute:
+		if obj := reconciler.getFromDeployed(reflect.TypeOf(routev1.Route{}), targetServiceName+"-rte"); obj != nil {
+			route := obj.(*routev1.Route)
+			if route.Spec.TLS != nil {
+				return route.Spec.Host, 443, true, true
 			}
-			connectorEntry = connectorEntry + ";" + generateAcceptorConnectorSSLArguments(customResource, namer, client, secretName)
-			sslOptionalArguments := generateConnectorSSLOptionalArguments(connector)
-			if sslOptionalArguments != "" {
-				connectorEntry = connectorEntry + ";" + sslOptionalArguments
+			return route.Spec.Host, 80, false, false
+		}
+	case brokerv1beta1.ExposeModeIngress:
+		if obj := reconciler.getFromDeployed(reflect.TypeOf(netv1.Ingress{}), targetServiceName+"-ing"); obj != nil {
+			ingress := obj.(*netv1.Ingress)
+			if len(ingress.Spec.Rules) > 0 {
+				if len(ingress.Spec.TLS) > 0 {
+					return ingress.Spec.Rules[0].Host, 443, true, true
+				}
+				return ingress.Spec.Rules[0].Host, 80, false, false
+			}
+		}
+	case brokerv1beta1.ExposeModeLoadBalancer:
+		if obj := reconciler.getFromDeployed(reflect.TypeOf(corev1.Service{}), exposedServiceName(customResource, portName, mode)); obj != nil {
+			service := obj.(*corev1.Service)
+			if len(service.Spec.Ports) == 0 {
+				break
+			}
+			for _, ingress := range service.Status.LoadBalancer.Ingress {
+				if ingress.Hostname != "" {
+					return ingress.Hostname, service.Spec.Ports[0].Port, acceptor.SSLEnabled, false
+				}
+				if ingress.IP != "" {
+					return ingress.IP, service.Spec.Ports[0].Port, acceptor.SSLEnabled, false
+				}
 			}
 		}
-		connectorEntry = connectorEntry + "<\\/connector>"
 	}
+	return "", 0, false, false
+}
 
-	return connectorEntry
+func (reconciler *ActiveMQArtemisReconcilerImpl) ExposureDefinitionForCR(namespacedName types.NamespacedName, labels map[string]string, targetServiceName string, targetPortName string, passthroughTLS bool, domain string, ingressClassName string, exposeAnnotations map[string]string, routeTLS *routev1.TLSConfig) rtclient.Object {
+
+	if isOpenshift, err := environments.DetectOpenshift(); isOpenshift && err == nil {
+		return reconciler.routeDefinitionForCR(namespacedName, labels, targetServiceName, targetPortName, passthroughTLS, domain, exposeAnnotations, routeTLS)
+	} else {
+		return reconciler.ingressDefinitionForCR(namespacedName, labels, targetServiceName, targetPortName, passthroughTLS, domain, ingressClassName, exposeAnnotations)
+	}
 }
 
-func (reconciler *ActiveMQArtemisReconcilerImpl) configureAcceptorsExposure(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, client rtclient.Client, scheme *runtime.Scheme) {
-	originalLabels := namer.LabelBuilder.Labels()
-	namespacedName := types.NamespacedName{
-		Name:      customResource.Name,
-		Namespace: customResource.Namespace,
+func (reconciler *ActiveMQArtemisReconcilerImpl) routeDefinitionForCR(namespacedName types.NamespacedName, labels map[string]string, targetServiceName string, targetPortName string, passthroughTLS bool, domain string, exposeAnnotations map[string]string, routeTLS *routev1.TLSConfig) rtclient.Object {
+	clog.Info("creating route for "+targetPortName, "service", targetServiceName)
+
+	var existing *routev1.Route = nil
+	obj := reconciler.cloneOfDeployed(reflect.TypeOf(routev1.Route{}), targetServiceName+"-rte")
+	if obj != nil {
+		existing = obj.(*routev1.Route)
 	}
-	deploymentSize := getDeploymentSize(customResource)
-	for i := int32(0); i < deploymentSize; i++ {
-		ordinalString := strconv.Itoa(int(i))
-		var serviceRoutelabels = make(map[string]string)
-		for k, v := range originalLabels {
-			serviceRoutelabels[k] = v
-		}
-		serviceRoutelabels["statefulset.kubernetes.io/pod-name"] = namer.SsNameBuilder.Name() + "-" + ordinalString
+	desired := routes.NewRouteDefinitionForCR(existing, namespacedName, labels, targetServiceName, targetPortName, passthroughTLS, domain)
+	if routeTLS != nil {
+		desired.Spec.TLS = routeTLS
+	}
+	setExposeAnnotations(desired, exposeAnnotations)
+	return desired
+}
 
-		for _, acceptor := range customResource.Spec.Acceptors {
-			serviceDefinition := svc.NewServiceDefinitionForCR("", client, namespacedName, acceptor.Name+"-"+ordinalString, acceptor.Port, serviceRoutelabels, namer.LabelBuilder.Labels())
+// routeTLSConfig is nil when the termination is left to the ssl enabled setting, the certificates
+// are copied into the route as the router has no way to reference a secret
+func routeTLSConfig(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, routeTLS *brokerv1beta1.RouteTLSType) *routev1.TLSConfig {
+	if routeTLS == nil || routeTLS.Termination == "" || routeTLS.Termination == brokerv1beta1.RouteTerminationPassthrough {
+		return nil
+	}
 
-			reconciler.checkExistingService(customResource, serviceDefinition, client)
-			reconciler.trackDesired(serviceDefinition)
+	config := &routev1.TLSConfig{
+		Termination:                   routev1.TLSTerminationEdge,
+		InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyRedirect,
+	}
+	if routeTLS.Termination == brokerv1beta1.RouteTerminationReencrypt {
+		config.Termination = routev1.TLSTerminationReencrypt
+	}
 
-			if acceptor.Expose {
+	secret := &corev1.Secret{}
+	if routeTLS.CertificateSecret != "" {
+		if err := client.Get(context.TODO(), types.NamespacedName{Name: routeTLS.CertificateSecret, Namespace: customResource.Namespace}, secret); err == nil {
+			config.Certificate = string(secret.Data[corev1.TLSCertKey])
+			config.Key = string(secret.Data[corev1.TLSPrivateKeyKey])
+			config.CACertificate = string(secret.Data[routeCAKey])
+		} else {
+			clog.Error(err, "unable to read the route certificate", "secret", routeTLS.CertificateSecret)
+		}
+	}
+	if routeTLS.DestinationCASecret != "" {
+		if err := client.Get(context.TODO(), types.NamespacedName{Name: routeTLS.DestinationCASecret, Namespace: customResource.Namespace}, secret); err == nil {
+			config.DestinationCACertificate = string(secret.Data[routeCAKey])
+		} else {
+			clog.Error(err, "unable to read the route destination CA", "secret", routeTLS.DestinationCASecret)
+		}
+	}
+	return config
+}
 
-				targetPortName := acceptor.Name + "-" + ordinalString
-				targetServiceName := customResource.Name + "-" + targetPortName + "-svc"
+const hostOrdinal = "{ordinal}"
 
-				exposureDefinition := reconciler.ExposureDefinitionForCR(namespacedName, serviceRoutelabels, targetServiceName, targetPortName, acceptor.SSLEnabled, customResource.Spec.IngressDomain)
-				reconciler.trackDesired(exposureDefinition)
+func hostForOrdinal(host string, ordinal int32) string {
+	return strings.ReplaceAll(host, hostOrdinal, strconv.Itoa(int(ordinal)))
+}
+
+// setExposedHost replaces the generated host of a route or an ingress when host is set. A route keeps
+// the host it was last given, the router doesn't give it a new one once it has one
+func setExposedHost(exposure rtclient.Object, host string, path string) {
+	switch desired := exposure.(type) {
+	case *routev1.Route:
+		if host != "" {
+			desired.Spec.Host = host
+		}
+		desired.Spec.Path = path
+	case *netv1.Ingress:
+		if host != "" {
+			desired.Spec.Rules[0].Host = host
+			for i := range desired.Spec.TLS {
+				desired.Spec.TLS[i].Hosts = []string{host}
 			}
 		}
+		if path != "" {
+			desired.Spec.Rules[0].HTTP.Paths[0].Path = path
+		}
 	}
 }
 
-func (reconciler *ActiveMQArtemisReconcilerImpl) ExposureDefinitionForCR(namespacedName types.NamespacedName, labels map[string]string, targetServiceName string, targetPortName string, passthroughTLS bool, domain string) rtclient.Object {
+func (reconciler *ActiveMQArtemisReconcilerImpl) ingressDefinitionForCR(namespacedName types.NamespacedName, labels map[string]string, targetServiceName string, targetPortName string, passthroughTLS bool, domain string, ingressClassName string, exposeAnnotations map[string]string) rtclient.Object {
+	clog.Info("creating ingress for "+targetPortName, "service", targetServiceName)
 
-	if isOpenshift, err := environments.DetectOpenshift(); isOpenshift && err == nil {
-		clog.Info("creating route for "+targetPortName, "service", targetServiceName)
+	var existing *netv1.Ingress = nil
+	obj := reconciler.cloneOfDeployed(reflect.TypeOf(netv1.Ingress{}), targetServiceName+"-ing")
+	if obj != nil {
+		existing = obj.(*netv1.Ingress)
+		clearExposeAnnotations(existing)
+	}
+	desired := ingresses.NewIngressForCRWithSSL(existing, namespacedName, labels, targetServiceName, targetPortName, passthroughTLS, domain)
+	// an unset class is left alone, the api server may have applied the default class
+	if ingressClassName != "" {
+		desired.Spec.IngressClassName = &ingressClassName
+	}
+	setExposeAnnotations(desired, exposeAnnotations)
+	return desired
+}
 
-		var existing *routev1.Route = nil
-		obj := reconciler.cloneOfDeployed(reflect.TypeOf(routev1.Route{}), targetServiceName+"-rte")
-		if obj != nil {
-			existing = obj.(*routev1.Route)
-		}
-		return routes.NewRouteDefinitionForCR(existing, namespacedName, labels, targetServiceName, targetPortName, passthroughTLS, domain)
-	} else {
-		clog.Info("creating ingress for "+targetPortName, "service", targetServiceName)
+// the annotations that make common load balancers and ingress controllers send the PROXY protocol
+// header, the ones of other clouds are inert
+var proxyProtocolAnnotations = map[brokerv1beta1.ExposeMode]map[string]string{
+	brokerv1beta1.ExposeModeLoadBalancer: {
+		"service.beta.kubernetes.io/aws-load-balancer-proxy-protocol":      "*",
+		"service.beta.kubernetes.io/do-loadbalancer-enable-proxy-protocol": "true",
+		"load-balancer.hetzner.cloud/uses-proxyprotocol":                   "true",
+	},
+	brokerv1beta1.ExposeModeIngress: {
+		"haproxy.org/send-proxy-protocol": "proxy-v2",
+	},
+}
 
-		var existing *netv1.Ingress = nil
-		obj := reconciler.cloneOfDeployed(reflect.TypeOf(netv1.Ingress{}), targetServiceName+"-ing")
-		if obj != nil {
-			existing = obj.(*netv1.Ingress)
+// acceptorExposeAnnotations adds the PROXY protocol annotations of the expose mode, the CR can override them
+func acceptorExposeAnnotations(acceptor brokerv1beta1.AcceptorType) map[string]string {
+	if !acceptor.ProxyProtocol {
+		return acceptor.ExposeAnnotations
+	}
+	annotations := map[string]string{}
+	for key, value := range proxyProtocolAnnotations[platformExposeMode(acceptorExposeMode(acceptor))] {
+		annotations[key] = value
+	}
+	for key, value := range acceptor.ExposeAnnotations {
+		annotations[key] = value
+	}
+	return annotations
+}
+
+const (
+	externalDNSAnnotationPrefix   = "external-dns.alpha.kubernetes.io/"
+	externalDNSHostnameAnnotation = externalDNSAnnotationPrefix + "hostname"
+	externalDNSTTLAnnotation      = externalDNSAnnotationPrefix + "ttl"
+)
+
+// withExternalDNS adds the external-dns annotations for the broker with the ordinal, they are managed
+// like the expose annotations
+func withExternalDNS(exposeAnnotations map[string]string, externalDNS *brokerv1beta1.ExternalDNSType, ordinal int32) map[string]string {
+	if externalDNS == nil {
+		return exposeAnnotations
+	}
+	annotations := map[string]string{}
+	for key, value := range exposeAnnotations {
+		annotations[key] = value
+	}
+	for key, value := range externalDNS.Annotations {
+		annotations[key] = hostForOrdinal(value, ordinal)
+	}
+	if externalDNS.Hostname != "" {
+		annotations[externalDNSHostnameAnnotation] = hostForOrdinal(externalDNS.Hostname, ordinal)
+	}
+	if externalDNS.TTL > 0 {
+		annotations[externalDNSTTLAnnotation] = strconv.Itoa(int(externalDNS.TTL))
+	}
+	return annotations
+}
+
+// removes the annotations a previous reconcile copied from the CR, so keys dropped from the CR don't linger
+func clearExposeAnnotations(obj rtclient.Object) {
+	annotations := obj.GetAnnotations()
+	if managed, found := annotations[exposeAnnotationsKey]; found {
+		for _, key := range strings.Split(managed, ",") {
+			delete(annotations, key)
 		}
-		return ingresses.NewIngressForCRWithSSL(existing, namespacedName, labels, targetServiceName, targetPortName, passthroughTLS, domain)
+		delete(annotations, exposeAnnotationsKey)
+	}
+}
+
+func setExposeAnnotations(obj rtclient.Object, exposeAnnotations map[string]string) {
+	if len(exposeAnnotations) == 0 {
+		return
+	}
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
 	}
+	keys := []string{}
+	for key, value := range exposeAnnotations {
+		annotations[key] = value
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	annotations[exposeAnnotationsKey] = strings.Join(keys, ",")
+	obj.SetAnnotations(annotations)
 }
 
 func (reconciler *ActiveMQArtemisReconcilerImpl) trackDesired(desired rtclient.Object) {
@@ -802,6 +2123,8 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) configureConnectorsExposure(cus
 
 		for _, connector := range customResource.Spec.Connectors {
 			serviceDefinition := svc.NewServiceDefinitionForCR("", client, namespacedName, connector.Name+"-"+ordinalString, connector.Port, serviceRoutelabels, namer.LabelBuilder.Labels())
+			reconciler.configureIPFamilies(customResource, serviceDefinition)
+			nameMeshPorts(customResource, serviceDefinition, meshTransportProtocol(connector.SSLEnabled))
 			reconciler.checkExistingService(customResource, serviceDefinition, client)
 			reconciler.trackDesired(serviceDefinition)
 
@@ -809,7 +2132,7 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) configureConnectorsExposure(cus
 
 				targetPortName := connector.Name + "-" + ordinalString
 				targetServiceName := customResource.Name + "-" + targetPortName + "-svc"
-				exposureDefinition := reconciler.ExposureDefinitionForCR(namespacedName, serviceRoutelabels, targetServiceName, targetPortName, connector.SSLEnabled, customResource.Spec.IngressDomain)
+				exposureDefinition := reconciler.ExposureDefinitionForCR(namespacedName, serviceRoutelabels, targetServiceName, meshPortName(customResource, targetPortName, meshTransportProtocol(connector.SSLEnabled)), connector.SSLEnabled, customResource.Spec.IngressDomain, "", nil, nil)
 
 				reconciler.trackDesired(exposureDefinition)
 			}
@@ -826,8 +2149,14 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) configureConsoleExposure(custom
 		Namespace: customResource.Namespace,
 	}
 	commonPortName := "wconsj"
-	targetPort := int32(8161)
+	targetPort := consoleServiceTargetPort(customResource)
 	portNumber := int32(8162)
+	// the sign-on proxy serves plain http whether or not the broker console has SSL enabled
+	secured := console.SSLEnabled && !consoleSSOEnabled(customResource)
+	meshProtocol := meshProtocolHTTP
+	if secured {
+		meshProtocol = meshProtocolHTTPS
+	}
 	deploymentSize := getDeploymentSize(customResource)
 	for i := int32(0); i < deploymentSize; i++ {
 		ordinalString := strconv.Itoa(int(i))
@@ -841,6 +2170,8 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) configureConsoleExposure(custom
 		targetServiceName := customResource.Name + "-" + targetPortName + "-svc"
 
 		serviceDefinition := svc.NewServiceDefinitionForCR(targetServiceName, client, namespacedName, commonPortName, targetPort, serviceRoutelabels, namer.LabelBuilder.Labels())
+		applyServiceSettings(serviceDefinition, console.ServiceSettings)
+		reconciler.configureIPFamilies(customResource, serviceDefinition)
 
 		serviceDefinition.Spec.Ports = append(serviceDefinition.Spec.Ports, corev1.ServicePort{
 			Name:       targetPortName,
@@ -848,32 +2179,14 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) configureConsoleExposure(custom
 			Protocol:   "TCP",
 			TargetPort: intstr.FromInt(int(targetPort)),
 		})
+		nameMeshPorts(customResource, serviceDefinition, meshProtocol)
 		if console.Expose {
 			reconciler.checkExistingService(customResource, serviceDefinition, client)
 			reconciler.trackDesired(serviceDefinition)
 
-			isOpenshift := false
-			isOpenshift, _ = environments.DetectOpenshift()
-			if isOpenshift {
-				clog.V(2).Info("routeDefinition for " + targetPortName)
-				var existing *routev1.Route = nil
-				obj := reconciler.cloneOfDeployed(reflect.TypeOf(routev1.Route{}), targetServiceName+"-rte")
-				if obj != nil {
-					existing = obj.(*routev1.Route)
-				}
-				routeDefinition := routes.NewRouteDefinitionForCR(existing, namespacedName, serviceRoutelabels, targetServiceName, targetPortName, console.SSLEnabled, customResource.Spec.IngressDomain)
-				reconciler.trackDesired(routeDefinition)
-
-			} else {
-				clog.V(2).Info("ingress for " + targetPortName)
-				var existing *netv1.Ingress = nil
-				obj := reconciler.cloneOfDeployed(reflect.TypeOf(netv1.Ingress{}), targetServiceName+"-ing")
-				if obj != nil {
-					existing = obj.(*netv1.Ingress)
-				}
-				ingressDefinition := ingresses.NewIngressForCRWithSSL(existing, namespacedName, serviceRoutelabels, targetServiceName, targetPortName, console.SSLEnabled, customResource.Spec.IngressDomain)
-				reconciler.trackDesired(ingressDefinition)
-			}
+			exposureDefinition := reconciler.ExposureDefinitionForCR(namespacedName, serviceRoutelabels, targetServiceName, meshPortName(customResource, targetPortName, meshProtocol), secured, customResource.Spec.IngressDomain, console.IngressClassName, withExternalDNS(console.ExposeAnnotations, console.ExternalDNS, i), routeTLSConfig(customResource, client, console.RouteTLS))
+			setExposedHost(exposureDefinition, hostForOrdinal(console.Host, i), console.Path)
+			reconciler.trackDesired(exposureDefinition)
 		}
 	}
 }
@@ -910,6 +2223,9 @@ func generateConsoleSSLFlags(customResource *brokerv1beta1.ActiveMQArtemis, name
 			trustStorePath = string(userPasswordSecret.Data["trustStorePath"])
 		}
 	}
+	if keyStore, trustStore, sourced := credentialsSourceKeyStorePasswords(customResource, credentialsSourceConsoleName); sourced {
+		keyStorePassword, trustStorePassword = keyStore, trustStore
+	}
 
 	sslFlags = sslFlags + " " + "--ssl-key" + " " + keyStorePath
 	sslFlags = sslFlags + " " + "--ssl-key-password" + " " + keyStorePassword
@@ -922,7 +2238,7 @@ func generateConsoleSSLFlags(customResource *brokerv1beta1.ActiveMQArtemis, name
 	return sslFlags
 }
 
-func generateAcceptorConnectorSSLArguments(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, client rtclient.Client, secretName string) string {
+func generateAcceptorConnectorSSLArguments(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, client rtclient.Client, name string, secretName string) string {
 
 	sslArguments := "sslEnabled=true"
 	secretNamespacedName := types.NamespacedName{
@@ -958,6 +2274,9 @@ func generateAcceptorConnectorSSLArguments(customResource *brokerv1beta1.ActiveM
 			trustStorePath = strings.ReplaceAll(string(userPasswordSecret.Data["trustStorePath"]), "/", "\\/")
 		}
 	}
+	if keyStore, trustStore, sourced := credentialsSourceKeyStorePasswords(customResource, name); sourced {
+		keyStorePassword, trustStorePassword = keyStore, trustStore
+	}
 	sslArguments = sslArguments + ";" + "keyStorePath=" + keyStorePath
 	sslArguments = sslArguments + ";" + "keyStorePassword=" + keyStorePassword
 	sslArguments = sslArguments + ";" + "trustStorePath=" + trustStorePath
@@ -966,6 +2285,18 @@ func generateAcceptorConnectorSSLArguments(customResource *brokerv1beta1.ActiveM
 	return sslArguments
 }
 
+// cert-manager writes the pkcs12 stores under these keys, protected by the password from the referenced secret
+func generateIssuedCertificateSSLArguments(secretName string, password string) string {
+	path := "\\/etc\\/" + secretName + "-volume\\/"
+	return "sslEnabled=true" +
+		";keyStorePath=" + path + "keystore.p12" +
+		";keyStorePassword=" + password +
+		";keyStoreType=PKCS12" +
+		";trustStorePath=" + path + "truststore.p12" +
+		";trustStorePassword=" + password +
+		";trustStoreType=PKCS12"
+}
+
 func generateAcceptorSSLOptionalArguments(acceptor brokerv1beta1.AcceptorType) string {
 
 	sslOptionalArguments := ""
@@ -1004,7 +2335,8 @@ func generateAcceptorSSLOptionalArguments(acceptor brokerv1beta1.AcceptorType) s
 		sslOptionalArguments = sslOptionalArguments + ";" + "trustStoreProvider=" + acceptor.TrustStoreProvider
 	}
 
-	return sslOptionalArguments
+	// the first optional argument may not be the cipher suites
+	return strings.TrimPrefix(sslOptionalArguments, ";")
 }
 
 func generateConnectorSSLOptionalArguments(connector brokerv1beta1.ConnectorType) string {
@@ -1045,7 +2377,7 @@ func generateConnectorSSLOptionalArguments(connector brokerv1beta1.ConnectorType
 		sslOptionalArguments = sslOptionalArguments + ";" + "trustStoreProvider=" + connector.TrustStoreProvider
 	}
 
-	return sslOptionalArguments
+	return strings.TrimPrefix(sslOptionalArguments, ";")
 }
 
 func (reconciler *ActiveMQArtemisReconcilerImpl) CurrentDeployedResources(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client) {
@@ -1053,7 +2385,7 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) CurrentDeployedResources(custom
 	reqLogger.Info("currentDeployedResources")
 
 	var err error
-	if customResource.Spec.DeploymentPlan.PersistenceEnabled {
+	if requiresPersistentVolume(customResource) {
 		checkExistingPersistentVolumes(customResource, client)
 	}
 	reconciler.deployed, err = getDeployedResources(customResource, client)
@@ -1106,7 +2438,13 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) ProcessResources(customResource
 	})
 
 	comparator.Comparator.SetComparator(reflect.TypeOf(netv1.Ingress{}), func(deployed, requested rtclient.Object) bool {
-		return equality.Semantic.DeepEqual(deployed.(*netv1.Ingress).Spec, requested.(*netv1.Ingress).Spec)
+		return equality.Semantic.DeepEqual(deployed.(*netv1.Ingress).Spec, requested.(*netv1.Ingress).Spec) &&
+			equality.Semantic.DeepEqual(deployed.GetAnnotations(), requested.GetAnnotations())
+	})
+
+	// the api server defaults many deployment fields, only compare what is requested
+	comparator.Comparator.SetComparator(reflect.TypeOf(appsv1.Deployment{}), func(deployed, requested rtclient.Object) bool {
+		return equality.Semantic.DeepDerivative(requested.(*appsv1.Deployment).Spec, deployed.(*appsv1.Deployment).Spec)
 	})
 
 	deltas := comparator.Compare(reconciler.deployed, requested)
@@ -1280,7 +2618,7 @@ func genOrderedTypesLists() []reflect.Type {
 
 	if orderedTypes == nil {
 		isOpenshift, _ := environments.DetectOpenshift()
-		types := make([]reflect.Type, 6)
+		types := make([]reflect.Type, 7)
 
 		// we want to create/update in this order
 		types[0] = reflect.TypeOf(corev1.Secret{})
@@ -1294,6 +2632,7 @@ func genOrderedTypesLists() []reflect.Type {
 			types[4] = reflect.TypeOf(netv1.Ingress{})
 		}
 		types[5] = reflect.TypeOf(policyv1.PodDisruptionBudget{})
+		types[6] = reflect.TypeOf(appsv1.Deployment{})
 		orderedTypes = &types
 	}
 	return *orderedTypes
@@ -1313,6 +2652,7 @@ func getDeployedResources(instance *brokerv1beta1.ActiveMQArtemis, client rtclie
 			&routev1.RouteList{},
 			&corev1.SecretList{},
 			&corev1.ConfigMapList{},
+			&appsv1.DeploymentList{},
 		)
 	} else {
 		resourceMap, err = reader.ListAll(
@@ -1321,6 +2661,7 @@ func getDeployedResources(instance *brokerv1beta1.ActiveMQArtemis, client rtclie
 			&netv1.IngressList{},
 			&corev1.SecretList{},
 			&corev1.ConfigMapList{},
+			&appsv1.DeploymentList{},
 		)
 	}
 	if err != nil {
@@ -1342,9 +2683,15 @@ func addNewVolumes(existingNames map[string]string, existing *[]corev1.Volume, n
 func MakeVolumes(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers) []corev1.Volume {
 
 	volumeDefinitions := []corev1.Volume{}
-	if customResource.Spec.DeploymentPlan.PersistenceEnabled {
+	if isHASharedStore(customResource) {
+		sharedStoreVolume := volumes.MakePersistentVolume(customResource.Name)
+		sharedStoreVolume[0].PersistentVolumeClaim.ClaimName = haSharedStoreClaimName(customResource)
+		volumeDefinitions = append(volumeDefinitions, sharedStoreVolume...)
+	} else if requiresPersistentVolume(customResource) {
 		basicCRVolume := volumes.MakePersistentVolume(customResource.Name)
 		volumeDefinitions = append(volumeDefinitions, basicCRVolume...)
+	} else if isEphemeral(customResource) {
+		volumeDefinitions = append(volumeDefinitions, ephemeralVolume(customResource)...)
 	}
 
 	secretVolumes := make(map[string]string)
@@ -1372,6 +2719,11 @@ func MakeVolumes(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers) []
 		addNewVolumes(secretVolumes, &volumeDefinitions, &secretName)
 	}
 
+	if customResource.Spec.ClusterTLS != nil {
+		secretName := clusterTLSSecretName(customResource)
+		addNewVolumes(secretVolumes, &volumeDefinitions, &secretName)
+	}
+
 	if customResource.Spec.Console.SSLEnabled {
 		clog.V(1).Info("Make volumes for ssl console exposure on k8s")
 		secretName := namer.SecretsConsoleNameBuilder.Name()
@@ -1395,7 +2747,7 @@ func addNewVolumeMounts(existingNames map[string]string, existing *[]corev1.Volu
 func MakeVolumeMounts(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers) []corev1.VolumeMount {
 
 	volumeMounts := []corev1.VolumeMount{}
-	if customResource.Spec.DeploymentPlan.PersistenceEnabled {
+	if requiresPersistentVolume(customResource) || isEphemeral(customResource) {
 		persistentCRVlMnt := volumes.MakePersistentVolumeMount(customResource.Name, namer.GLOBAL_DATA_PATH)
 		volumeMounts = append(volumeMounts, persistentCRVlMnt...)
 	}
@@ -1425,6 +2777,11 @@ func MakeVolumeMounts(customResource *brokerv1beta1.ActiveMQArtemis, namer Namer
 		addNewVolumeMounts(secretVolumeMounts, &volumeMounts, &volumeMountName)
 	}
 
+	if customResource.Spec.ClusterTLS != nil {
+		volumeMountName := clusterTLSSecretName(customResource) + "-volume"
+		addNewVolumeMounts(secretVolumeMounts, &volumeMounts, &volumeMountName)
+	}
+
 	if customResource.Spec.Console.SSLEnabled {
 		clog.V(1).Info("Make volume mounts for ssl console exposure on k8s")
 		volumeMountName := namer.SecretsConsoleNameBuilder.Name() + "-volume"
@@ -1448,12 +2805,15 @@ func MakeContainerPorts(cr *brokerv1beta1.ActiveMQArtemis) []corev1.ContainerPor
 		}
 		containerPorts = append(containerPorts, jolokiaContainerPort)
 	}
-	consoleContainerPort := corev1.ContainerPort{
-		Name:          "wconsj",
-		ContainerPort: 8161,
-		Protocol:      "TCP",
+	// the sign-on proxy publishes the console port when the console only listens inside the pod
+	if !cr.Spec.Console.Disabled && !consoleSSOEnabled(cr) {
+		consoleContainerPort := corev1.ContainerPort{
+			Name:          "wconsj",
+			ContainerPort: getConsolePort(cr),
+			Protocol:      "TCP",
+		}
+		containerPorts = append(containerPorts, consoleContainerPort)
 	}
-	containerPorts = append(containerPorts, consoleContainerPort)
 
 	return containerPorts
 }
@@ -1502,7 +2862,7 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) NewPodTemplateSpecForCR(customR
 		})
 	}
 
-	pts := pods.MakePodTemplateSpec(current, namespacedName, labels, customResource.Spec.DeploymentPlan.Annotations)
+	pts := pods.MakePodTemplateSpec(current, namespacedName, labels, podAnnotations(customResource))
 	podSpec := &pts.Spec
 
 	// REVISIT: don't know when this is nil
@@ -1523,8 +2883,32 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) NewPodTemplateSpecForCR(customR
 	reqLogger.Info("Checking out extraMounts", "extra config", customResource.Spec.DeploymentPlan.ExtraMounts)
 
 	configMapsToCreate := customResource.Spec.DeploymentPlan.ExtraMounts.ConfigMaps
+	if brokerXml := customResource.Spec.BrokerXmlConfigMap; brokerXml != nil && !containsString(configMapsToCreate, brokerXml.Name) {
+		configMapsToCreate = append(configMapsToCreate, brokerXml.Name)
+	}
 	secretsToCreate := customResource.Spec.DeploymentPlan.ExtraMounts.Secrets
-	brokerPropertiesResourceName, isSecret, brokerPropertiesMapData := reconciler.addResourceForBrokerProperties(customResource, namer)
+	if loggingResourceName := reconciler.addResourceForLogging(customResource, namer, client); loggingResourceName != "" {
+		secretsToCreate = append(secretsToCreate, loggingResourceName)
+	}
+	if mesh := customResource.Spec.ClusterMesh; mesh != nil && mesh.TrustSecret != "" && !containsString(secretsToCreate, mesh.TrustSecret) {
+		secretsToCreate = append(secretsToCreate, mesh.TrustSecret)
+	}
+	saslJavaArgs := ""
+	if securityCR := getApplicableSecurityCR(customResource); securityCR != nil {
+		var saslSecrets, saslConfigMaps []string
+		saslSecrets, saslConfigMaps, saslJavaArgs = saslMountsAndArgs(securityCR)
+		for _, name := range saslSecrets {
+			if !containsString(secretsToCreate, name) {
+				secretsToCreate = append(secretsToCreate, name)
+			}
+		}
+		for _, name := range saslConfigMaps {
+			if !containsString(configMapsToCreate, name) {
+				configMapsToCreate = append(configMapsToCreate, name)
+			}
+		}
+	}
+	brokerPropertiesResourceName, isSecret, brokerPropertiesMapData := reconciler.addResourceForBrokerProperties(customResource, namer, client)
 	if isSecret {
 		secretsToCreate = append(secretsToCreate, brokerPropertiesResourceName)
 	} else {
@@ -1539,8 +2923,11 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) NewPodTemplateSpecForCR(customR
 		container.VolumeMounts = append(container.VolumeMounts, extraVolumeMounts...)
 	}
 
-	container.LivenessProbe = configureLivenessProbe(container, customResource.Spec.DeploymentPlan.LivenessProbe)
+	container.LivenessProbe = configureLivenessProbe(container, customResource.Spec.DeploymentPlan.LivenessProbe, defaultLivenessProbeHandler(customResource))
 	container.ReadinessProbe = configureReadinessProbe(container, customResource.Spec.DeploymentPlan.ReadinessProbe)
+	if isEphemeral(customResource) {
+		relaxEphemeralProbes(container, customResource)
+	}
 
 	if len(customResource.Spec.DeploymentPlan.NodeSelector) > 0 {
 		reqLogger.V(1).Info("Adding Node Selectors", "len", len(customResource.Spec.DeploymentPlan.NodeSelector))
@@ -1548,6 +2935,8 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) NewPodTemplateSpecForCR(customR
 	}
 
 	configureAffinity(podSpec, &customResource.Spec.DeploymentPlan.Affinity)
+	configureAntiAffinityPreset(podSpec, customResource)
+	configureZoneSpread(podSpec, customResource)
 
 	if len(customResource.Spec.DeploymentPlan.Tolerations) > 0 {
 		reqLogger.V(1).Info("Adding Tolerations", "len", len(customResource.Spec.DeploymentPlan.Tolerations))
@@ -1597,6 +2986,64 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) NewPodTemplateSpecForCR(customR
 		environments.CreateOrAppend(podSpec.Containers, &loggerOpts)
 	}
 
+	if saslJavaArgs != "" {
+		saslOpts := corev1.EnvVar{
+			Name:  "JAVA_ARGS_APPEND",
+			Value: saslJavaArgs,
+		}
+		environments.CreateOrAppend(podSpec.Containers, &saslOpts)
+	}
+
+	if timeout := customResource.Spec.Console.SessionTimeoutSeconds; timeout != nil {
+		sessionOpts := corev1.EnvVar{
+			Name:  "JAVA_ARGS_APPEND",
+			Value: fmt.Sprintf("-Dhawtio.sessionTimeout=%d", *timeout),
+		}
+		environments.CreateOrAppend(podSpec.Containers, &sessionOpts)
+	}
+
+	// appended after the image defaults so that the last occurrence of a flag wins
+	if jvmArgs := jvmJavaArgs(customResource.Spec.DeploymentPlan.JVM); jvmArgs != "" {
+		jvmOpts := corev1.EnvVar{
+			Name:  "JAVA_ARGS_APPEND",
+			Value: jvmArgs,
+		}
+		environments.CreateOrAppend(podSpec.Containers, &jvmOpts)
+	}
+
+	// the jvm ignores the proxy env vars so they need to be passed as system properties too
+	if proxyArgs := proxyJavaArgs(customResource.Spec.Proxy); proxyArgs != "" {
+		proxyOpts := corev1.EnvVar{
+			Name:  "JAVA_ARGS_APPEND",
+			Value: proxyArgs,
+		}
+		environments.CreateOrAppend(podSpec.Containers, &proxyOpts)
+	}
+
+	if jdbc := customResource.Spec.DeploymentPlan.Persistence.JDBC; jdbc != nil && jdbc.DriverImage != "" {
+		jdbcOpts := corev1.EnvVar{
+			Name:  "JAVA_ARGS_APPEND",
+			Value: "-Dartemis.extra.libs=" + jdbcDriverLibDir,
+		}
+		environments.CreateOrAppend(podSpec.Containers, &jdbcOpts)
+	}
+
+	if isIPv6Primary(customResource) {
+		ipv6Opts := corev1.EnvVar{
+			Name:  "JAVA_ARGS_APPEND",
+			Value: preferIPv6JavaArg,
+		}
+		environments.CreateOrAppend(podSpec.Containers, &ipv6Opts)
+	}
+
+	if len(customResource.Spec.HawtioRoles) > 0 {
+		hawtioRoles := corev1.EnvVar{
+			Name:  "JAVA_ARGS_APPEND",
+			Value: fmt.Sprintf("-Dhawtio.roles=%v", strings.Join(customResource.Spec.HawtioRoles, ",")),
+		}
+		environments.CreateOrAppend(podSpec.Containers, &hawtioRoles)
+	}
+
 	//add empty-dir volume and volumeMounts to main container
 	volumeForCfg := volumes.MakeVolumeForCfg(cfgVolumeName)
 	podSpec.Volumes = append(podSpec.Volumes, volumeForCfg)
@@ -1704,6 +3151,11 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) NewPodTemplateSpecForCR(customR
 	volumeMountForCfg = volumes.MakeRwVolumeMountForCfg("tool-dir", initCfgRootDir)
 	podSpec.InitContainers[0].VolumeMounts = append(podSpec.InitContainers[0].VolumeMounts, volumeMountForCfg)
 
+	if sourceVolume, sourceVolumeMount := credentialsSourceVolume(customResource); sourceVolume != nil {
+		podSpec.Volumes = append(podSpec.Volumes, *sourceVolume)
+		podSpec.InitContainers[0].VolumeMounts = append(podSpec.InitContainers[0].VolumeMounts, *sourceVolumeMount)
+	}
+
 	//add empty-dir volume
 	volumeForCfg = volumes.MakeVolumeForCfg("tool-dir")
 	podSpec.Volumes = append(podSpec.Volumes, volumeForCfg)
@@ -1728,23 +3180,76 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) NewPodTemplateSpecForCR(customR
 	var brokerHandlerCmds []string = []string{}
 	clog.Info("Checking if there are any config handlers", "main cr", namespacedName)
 	brokerConfigHandler := GetBrokerConfigHandler(namespacedName)
-	if brokerConfigHandler != nil {
+	if brokerConfigHandler == nil {
+		recordSecurityRender(customResource, nil, nil)
+	} else {
 		clog.Info("there is a config handler")
 		handlerCmds := brokerConfigHandler.Config(podSpec.InitContainers, initCfgRootDir+"/security", yacfgProfileVersion, yacfgProfileName)
 		clog.Info("Getting back some init commands", "handlerCmds", handlerCmds)
+		recordSecurityRender(customResource, brokerConfigHandler, handlerCmds)
 		if len(handlerCmds) > 0 {
 			clog.Info("appending to initCmd array...")
 			brokerHandlerCmds = append(brokerHandlerCmds, handlerCmds...)
+			if getSecurityCanary(customResource) != nil {
+				securityChecksum := corev1.EnvVar{
+					Name:  securityConfigChecksumEnvVarName,
+					Value: securityConfigChecksum(handlerCmds),
+				}
+				environments.Create(podSpec.InitContainers, &securityChecksum)
+			}
 		}
 	}
 
 	var strBuilder strings.Builder
 
 	isFirst := true
+	if exportCmd := credentialsSourceExportCmd(customResource); exportCmd != "" {
+		initCmds = append(initCmds, exportCmd)
+	}
 	initCmds = append(initCmds, configCmd)
+	if keyStoreCmd := credentialsSourceKeyStoreCmd(customResource); keyStoreCmd != "" {
+		initCmds = append(initCmds, keyStoreCmd)
+	}
+	if haCmd := haPolicyCmd(customResource, initCfgRootDir); haCmd != "" {
+		initCmds = append(initCmds, haCmd)
+		if connectString := haZooKeeperConnectString(customResource); connectString != nil {
+			environments.Create(podSpec.InitContainers, connectString)
+		}
+	}
+	if brokerXmlCmd := brokerXmlMergeCmd(customResource); brokerXmlCmd != "" {
+		initCmds = append(initCmds, brokerXmlCmd)
+		brokerXmlChecksum := corev1.EnvVar{
+			Name:  "BROKER_XML_CHECKSUM",
+			Value: brokerXmlConfigMapChecksum(customResource, client),
+		}
+		environments.Create(podSpec.InitContainers, &brokerXmlChecksum)
+	}
+	// after the user broker.xml, which may replace the cluster connections
+	if meshCmd := reconciler.clusterMeshCmd(customResource, client, podSpec); meshCmd != "" {
+		initCmds = append(initCmds, meshCmd)
+	}
+	if isJdbcPersistence(customResource) {
+		// the store configuration is only read on start, a rotated connection url needs to roll the pods
+		jdbcChecksum := corev1.EnvVar{
+			Name:  jdbcConnectionChecksumEnvVarName,
+			Value: jdbcConnectionChecksum(customResource, client),
+		}
+		environments.Create(podSpec.InitContainers, &jdbcChecksum)
+	}
+	if bindCmd := bindInterfacesCmd(customResource); bindCmd != "" {
+		initCmds = append(initCmds, bindCmd)
+	}
+	if jolokiaCmd := jolokiaAccessCmd(customResource); jolokiaCmd != "" {
+		initCmds = append(initCmds, jolokiaCmd)
+	}
+	if consoleCmd := consoleBootstrapCmd(customResource); consoleCmd != "" {
+		initCmds = append(initCmds, consoleCmd)
+	}
 	initCmds = append(initCmds, brokerHandlerCmds...)
 	initCmds = append(initCmds, initHelperScript)
 
+	reconciler.addResourceForInitScripts(customResource, namer, initCmds, podSpec)
+
 	for _, icmd := range initCmds {
 		if isFirst {
 			isFirst = false
@@ -1780,38 +3285,291 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) NewPodTemplateSpecForCR(customR
 	configPodSecurity(podSpec, &customResource.Spec.DeploymentPlan.PodSecurity)
 	configurePodSecurityContext(podSpec, customResource.Spec.DeploymentPlan.PodSecurityContext)
 
+	// the driver jars land in the instance lib dir once the instance is created
+	if jdbcDriverContainer := makeJdbcDriverInitContainer(customResource, cfgVolumeName); jdbcDriverContainer != nil {
+		podSpec.InitContainers = append(podSpec.InitContainers, *jdbcDriverContainer)
+	}
+
 	clog.V(3).Info("Final Init spec", "Detail", podSpec.InitContainers)
 
-	pts.Spec = *podSpec
+	pts.Spec = *podSpec
+
+	return pts, nil
+}
+
+func resolveImage(customResource *brokerv1beta1.ActiveMQArtemis, key string) string {
+	var imageName string
+
+	if key == InitImageKey && isLockedDown(customResource.Spec.DeploymentPlan.InitImage) {
+		imageName = customResource.Spec.DeploymentPlan.InitImage
+	} else if key == BrokerImageKey && isLockedDown(customResource.Spec.DeploymentPlan.Image) {
+		imageName = customResource.Spec.DeploymentPlan.Image
+	} else {
+		imageName = determineImageToUse(customResource, key)
+	}
+	return imageName
+}
+
+func isLockedDown(imageAttribute string) bool {
+	return imageAttribute != "placeholder" && imageAttribute != ""
+}
+
+func brokerPropertiesConfigSystemPropValue(mountPoint, resourceName string, brokerPropertiesData map[string]string) string {
+	if len(brokerPropertiesData) == 1 {
+		// single entry, no ordinal subpath - broker will log if arg is not found for the watcher so make conditional
+		return fmt.Sprintf("-Dbroker.properties=%s%s/%s", mountPoint, resourceName, BrokerPropertiesName)
+	} else {
+		// directory works on broker image versions >= 2.27.1
+		return fmt.Sprintf("-Dbroker.properties=%s%s/,%s%s/%s${STATEFUL_SET_ORDINAL}/", mountPoint, resourceName, mountPoint, resourceName, OrdinalPrefix)
+	}
+}
+
+func podAnnotations(customResource *brokerv1beta1.ActiveMQArtemis) map[string]string {
+	networks := customResource.Spec.DeploymentPlan.AdditionalNetworks
+	vault := customResource.Spec.CredentialsSource != nil && customResource.Spec.CredentialsSource.Vault != nil
+	if len(networks) == 0 && !isServiceMeshEnabled(customResource) && !vault {
+		return customResource.Spec.DeploymentPlan.Annotations
+	}
+	annotations := make(map[string]string, len(customResource.Spec.DeploymentPlan.Annotations)+3)
+	for key, value := range customResource.Spec.DeploymentPlan.Annotations {
+		annotations[key] = value
+	}
+	if len(networks) > 0 {
+		annotations[multusNetworksAnnotation] = strings.Join(networks, ",")
+	}
+	if isServiceMeshEnabled(customResource) {
+		addServiceMeshAnnotations(customResource, annotations)
+	}
+	if vault {
+		addVaultAnnotations(customResource, annotations)
+	}
+	return annotations
+}
+
+func bindInterfaces(customResource *brokerv1beta1.ActiveMQArtemis) []string {
+	var interfaces []string
+	for _, acceptor := range customResource.Spec.Acceptors {
+		if acceptor.BindInterface != "" && !containsString(interfaces, acceptor.BindInterface) {
+			interfaces = append(interfaces, acceptor.BindInterface)
+		}
+	}
+	return interfaces
+}
+
+// the interface addresses are only known inside the pod, they are resolved once the
+// instance is created and the user broker.xml is merged
+func bindInterfacesCmd(customResource *brokerv1beta1.ActiveMQArtemis) string {
+	interfaces := bindInterfaces(customResource)
+	if len(interfaces) == 0 {
+		return ""
+	}
+	args := brokerConfigRoot + "/etc/broker.xml " + strings.Join(interfaces, " ")
+	if isIPv6Primary(customResource) {
+		args = "-6 " + args
+	}
+	return "python3 " + initScriptPath(bindInterfacesScriptName) + " " + args
+}
+
+func getConsolePort(customResource *brokerv1beta1.ActiveMQArtemis) int32 {
+	if customResource.Spec.Console.Port != 0 {
+		return customResource.Spec.Console.Port
+	}
+	return TCPLivenessPort
+}
+
+// the web binding is written to bootstrap.xml when the instance is created, it is rewritten or removed
+func consoleBootstrapCmd(customResource *brokerv1beta1.ActiveMQArtemis) string {
+	console := customResource.Spec.Console
+	sso := consoleSSOEnabled(customResource)
+	if !console.Disabled && !sso && console.BindHost == "" && console.Port == 0 {
+		return ""
+	}
+	host := "-"
+	if sso {
+		host = consoleSSOBindHost(customResource)
+	} else if console.BindHost != "" {
+		host = console.BindHost
+		if strings.Contains(host, ":") {
+			host = "[" + host + "]"
+		}
+	}
+	port := "-"
+	if console.Port != 0 {
+		port = strconv.Itoa(int(console.Port))
+	}
+	return "python3 " + initScriptPath(consoleBootstrapScriptName) + " " +
+		brokerConfigRoot + "/etc/bootstrap.xml " + host + " " + port + " " + strconv.FormatBool(console.Disabled)
+}
+
+const defaultJolokiaAllowedOrigin = "*://localhost*"
+
+// replaces the jolokia-access.xml policy of the created instance
+func jolokiaAccessCmd(customResource *brokerv1beta1.ActiveMQArtemis) string {
+	if jolokiaAccessPolicy(customResource) == "" {
+		return ""
+	}
+	return "cp " + initScriptPath(jolokiaAccessFileName) + " " + brokerConfigRoot + "/etc/jolokia-access.xml"
+}
+
+// the operator client sends no Origin header so the cors policy only restricts browsers. The denied
+// entries of an applicable security cr go in the same policy, management.xml has no way to deny a
+// role what it grants
+func jolokiaAccessPolicy(customResource *brokerv1beta1.ActiveMQArtemis) string {
+	jolokia := customResource.Spec.Console.Jolokia
+	var denied []brokerv1beta1.DeniedListEntryType
+	if securityCR := getApplicableSecurityCR(customResource); securityCR != nil {
+		denied = securityCR.Spec.SecuritySettings.Management.Authorisation.DeniedList
+	}
+	if len(denied) == 0 && (jolokia == nil || (len(jolokia.AllowedOrigins) == 0 && jolokia.StrictChecking == nil)) {
+		return ""
+	}
+	var origins []string
+	if jolokia != nil {
+		origins = jolokia.AllowedOrigins
+	}
+	if len(origins) == 0 {
+		origins = []string{defaultJolokiaAllowedOrigin}
+	}
+	var policy strings.Builder
+	policy.WriteString("<restrict><cors>")
+	for _, origin := range origins {
+		policy.WriteString("<allow-origin>" + origin + "</allow-origin>")
+	}
+	if jolokia == nil || jolokia.StrictChecking == nil || *jolokia.StrictChecking {
+		policy.WriteString("<strict-checking/>")
+	}
+	policy.WriteString("</cors>")
+	if len(denied) > 0 {
+		policy.WriteString("<deny>")
+		for _, entry := range denied {
+			key := entry.Key
+			if key == "" {
+				key = "*"
+			}
+			policy.WriteString("<mbean><name>" + entry.Domain + ":" + key + "</name>")
+			for _, operation := range entry.Operations {
+				policy.WriteString("<operation>" + operation + "</operation>")
+			}
+			for _, attribute := range entry.Attributes {
+				policy.WriteString("<attribute>" + attribute + "</attribute>")
+			}
+			policy.WriteString("</mbean>")
+		}
+		policy.WriteString("</deny>")
+	}
+	policy.WriteString("</restrict>")
+	return policy.String()
+}
+
+// the user broker.xml is merged after the instance is created (and address settings applied)
+// but before any security config handler runs
+func brokerXmlMergeCmd(customResource *brokerv1beta1.ActiveMQArtemis) string {
+	brokerXml := customResource.Spec.BrokerXmlConfigMap
+	if brokerXml == nil || brokerXml.Name == "" {
+		return ""
+	}
+	return "python3 " + initScriptPath(brokerXmlMergeScriptName) + " " +
+		brokerConfigRoot + "/etc/broker.xml " + cfgMapPathBase + brokerXml.Name + "/" + brokerXml.Key
+}
+
+// a change to the referenced ConfigMap needs to roll the pods, track its content in the init container env
+func brokerXmlConfigMapChecksum(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client) string {
+	if client == nil {
+		return ""
+	}
+	configMap := &corev1.ConfigMap{}
+	if err := client.Get(context.TODO(), types.NamespacedName{Name: customResource.Spec.BrokerXmlConfigMap.Name, Namespace: customResource.Namespace}, configMap); err != nil {
+		clog.V(1).Info("unable to retrieve broker.xml configMap", "name", customResource.Spec.BrokerXmlConfigMap.Name, "error", err)
+		return ""
+	}
+	return hex.EncodeToString(alder32Of([]string{configMap.Data[customResource.Spec.BrokerXmlConfigMap.Key]}))
+}
+
+var garbageCollectorFlags = map[string]string{
+	"G1":         "-XX:+UseG1GC",
+	"Parallel":   "-XX:+UseParallelGC",
+	"Serial":     "-XX:+UseSerialGC",
+	"Shenandoah": "-XX:+UseShenandoahGC",
+	"Z":          "-XX:+UseZGC",
+}
 
-	return pts, nil
+// jvm sizes are passed in whole mebibytes, the jvm does not understand kubernetes quantities
+func jvmSizeArg(flag string, quantity *resource.Quantity) string {
+	return fmt.Sprintf("%v%dm", flag, quantity.Value()/(1024*1024))
 }
 
-func resolveImage(customResource *brokerv1beta1.ActiveMQArtemis, key string) string {
-	var imageName string
+func jvmJavaArgs(jvm *brokerv1beta1.JVMType) string {
+	if jvm == nil {
+		return ""
+	}
+	args := []string{}
+	if jvm.InitialHeapSize != nil {
+		args = append(args, jvmSizeArg("-Xms", jvm.InitialHeapSize))
+	}
+	if jvm.MaxHeapSize != nil {
+		args = append(args, jvmSizeArg("-Xmx", jvm.MaxHeapSize))
+	}
+	if jvm.MaxMetaspaceSize != nil {
+		args = append(args, jvmSizeArg("-XX:MaxMetaspaceSize=", jvm.MaxMetaspaceSize))
+	}
+	if flag, found := garbageCollectorFlags[jvm.GarbageCollector]; found {
+		args = append(args, flag)
+	}
+	args = append(args, jvm.ExtraArgs...)
+	return strings.Join(args, " ")
+}
 
-	if key == InitImageKey && isLockedDown(customResource.Spec.DeploymentPlan.InitImage) {
-		imageName = customResource.Spec.DeploymentPlan.InitImage
-	} else if key == BrokerImageKey && isLockedDown(customResource.Spec.DeploymentPlan.Image) {
-		imageName = customResource.Spec.DeploymentPlan.Image
-	} else {
-		imageName = determineImageToUse(customResource, key)
+func proxyJavaArgs(proxy *brokerv1beta1.ProxyType) string {
+	if proxy == nil {
+		return ""
 	}
-	return imageName
+	args := []string{}
+	for _, p := range []struct {
+		scheme   string
+		proxyUrl string
+	}{{"http", proxy.HttpProxy}, {"https", proxy.HttpsProxy}} {
+		if p.proxyUrl == "" {
+			continue
+		}
+		parsed, err := url.Parse(p.proxyUrl)
+		if err != nil || parsed.Hostname() == "" {
+			clog.Info("ignoring invalid proxy url", "url", p.proxyUrl)
+			continue
+		}
+		args = append(args, fmt.Sprintf("-D%s.proxyHost=%s", p.scheme, parsed.Hostname()))
+		if parsed.Port() != "" {
+			args = append(args, fmt.Sprintf("-D%s.proxyPort=%s", p.scheme, parsed.Port()))
+		}
+	}
+	if proxy.NoProxy != "" && len(args) > 0 {
+		nonProxyHosts := []string{}
+		for _, host := range strings.Split(proxy.NoProxy, ",") {
+			host = strings.TrimSpace(host)
+			if strings.HasPrefix(host, ".") {
+				host = "*" + host
+			}
+			if host != "" {
+				nonProxyHosts = append(nonProxyHosts, host)
+			}
+		}
+		// http.nonProxyHosts applies to https too
+		args = append(args, fmt.Sprintf("-Dhttp.nonProxyHosts=%s", strings.Join(nonProxyHosts, "|")))
+	}
+	return strings.Join(args, " ")
 }
 
-func isLockedDown(imageAttribute string) bool {
-	return imageAttribute != "placeholder" && imageAttribute != ""
+func getAdminRole(customResource *brokerv1beta1.ActiveMQArtemis) string {
+	if customResource.Spec.AdminRole != "" {
+		return customResource.Spec.AdminRole
+	}
+	return DefaultAdminRole
 }
 
-func brokerPropertiesConfigSystemPropValue(mountPoint, resourceName string, brokerPropertiesData map[string]string) string {
-	if len(brokerPropertiesData) == 1 {
-		// single entry, no ordinal subpath - broker will log if arg is not found for the watcher so make conditional
-		return fmt.Sprintf("-Dbroker.properties=%s%s/%s", mountPoint, resourceName, BrokerPropertiesName)
-	} else {
-		// directory works on broker image versions >= 2.27.1
-		return fmt.Sprintf("-Dbroker.properties=%s%s/,%s%s/%s${STATEFUL_SET_ORDINAL}/", mountPoint, resourceName, mountPoint, resourceName, OrdinalPrefix)
+func getHawtioRoles(customResource *brokerv1beta1.ActiveMQArtemis) []string {
+	if len(customResource.Spec.HawtioRoles) > 0 {
+		return customResource.Spec.HawtioRoles
 	}
+	return []string{getAdminRole(customResource)}
 }
 
 func getJaasConfigExtraMountPath(customResource *brokerv1beta1.ActiveMQArtemis) (string, bool) {
@@ -1822,6 +3580,9 @@ func getJaasConfigExtraMountPath(customResource *brokerv1beta1.ActiveMQArtemis)
 }
 
 func getLoggingConfigExtraMountPath(customResource *brokerv1beta1.ActiveMQArtemis) (string, bool) {
+	if customResource.Spec.Logging != nil {
+		return fmt.Sprintf("%v%v/%v", secretPathBase, getLoggingResourceName(customResource), LoggingConfigKey), true
+	}
 	if t, name, found := getConfigExtraMount(customResource, loggingConfigSuffix); found {
 		return fmt.Sprintf("/amq/extra/%v/%v/logging.properties", t, name), true
 	}
@@ -1842,7 +3603,24 @@ func getConfigExtraMount(customResource *brokerv1beta1.ActiveMQArtemis, suffix s
 	return "", "", false
 }
 
-func configureLivenessProbe(container *corev1.Container, probeFromCr *corev1.Probe) *corev1.Probe {
+// the default liveness check connects to the console, without a console or when it only listens
+// inside the pod the readiness check is used
+func defaultLivenessProbeHandler(customResource *brokerv1beta1.ActiveMQArtemis) corev1.ProbeHandler {
+	if customResource.Spec.Console.Disabled || consoleSSOEnabled(customResource) {
+		return corev1.ProbeHandler{
+			Exec: &corev1.ExecAction{
+				Command: betterCommand,
+			},
+		}
+	}
+	return corev1.ProbeHandler{
+		TCPSocket: &corev1.TCPSocketAction{
+			Port: intstr.FromInt(int(getConsolePort(customResource))),
+		},
+	}
+}
+
+func configureLivenessProbe(container *corev1.Container, probeFromCr *corev1.Probe, defaultHandler corev1.ProbeHandler) *corev1.Probe {
 	var livenessProbe *corev1.Probe = container.LivenessProbe
 	clog.V(1).Info("Configuring Liveness Probe", "existing", livenessProbe)
 
@@ -1855,12 +3633,8 @@ func configureLivenessProbe(container *corev1.Container, probeFromCr *corev1.Pro
 
 		// not complete in this case!
 		if probeFromCr.Exec == nil && probeFromCr.HTTPGet == nil && probeFromCr.TCPSocket == nil {
-			clog.V(1).Info("Adding default TCP check")
-			livenessProbe.ProbeHandler = corev1.ProbeHandler{
-				TCPSocket: &corev1.TCPSocketAction{
-					Port: intstr.FromInt(TCPLivenessPort),
-				},
-			}
+			clog.V(1).Info("Adding default check")
+			livenessProbe.ProbeHandler = defaultHandler
 		} else if probeFromCr.TCPSocket != nil {
 			clog.V(1).Info("Using user specified TCPSocket")
 			livenessProbe.ProbeHandler = corev1.ProbeHandler{
@@ -1875,11 +3649,7 @@ func configureLivenessProbe(container *corev1.Container, probeFromCr *corev1.Pro
 
 		livenessProbe.InitialDelaySeconds = defaultLivenessProbeInitialDelay
 		livenessProbe.TimeoutSeconds = 5
-		livenessProbe.ProbeHandler = corev1.ProbeHandler{
-			TCPSocket: &corev1.TCPSocketAction{
-				Port: intstr.FromInt(TCPLivenessPort),
-			},
-		}
+		livenessProbe.ProbeHandler = defaultHandler
 	}
 
 	return livenessProbe
@@ -1977,7 +3747,7 @@ func getConfigAppliedConfigMapName(artemis *brokerv1beta1.ActiveMQArtemis) types
 	}
 }
 
-func (reconciler *ActiveMQArtemisReconcilerImpl) addResourceForBrokerProperties(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers) (string, bool, map[string]string) {
+func (reconciler *ActiveMQArtemisReconcilerImpl) addResourceForBrokerProperties(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, client rtclient.Client) (string, bool, map[string]string) {
 
 	// fetch and do idempotent transform based on CR
 
@@ -2007,7 +3777,26 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) addResourceForBrokerProperties(
 		desired = obj.(*corev1.Secret)
 	}
 
-	data := brokerPropertiesData(customResource.Spec.BrokerProperties)
+	// store configuration goes first so that it can be overridden from Spec.BrokerProperties
+	props := append(jdbcStoreProperties(customResource, client), ephemeralProperties(customResource)...)
+	props = append(props, metricsProperties(customResource)...)
+	props = append(props, reservedAddressPrefixProperties(customResource)...)
+	props = append(props, retentionPolicyProperties(customResource)...)
+	props = append(props, redeliveryProperties(customResource)...)
+	props = append(props, addressSettingsProperties(customResource, client)...)
+	props = append(props, divertProperties(customResource, client)...)
+	props = append(props, bridgeProperties(customResource, client)...)
+	props = append(props, clusterConnectionProperties(customResource)...)
+	props = append(props, reconciler.externalConnectorProperties(customResource)...)
+	props = append(props, reconciler.clusterTLSProperties(customResource, namer)...)
+	props = append(props, federationProperties(customResource, client)...)
+	props = append(props, amqpConnectionProperties(customResource, client)...)
+	props = append(props, reconciler.clusterMeshProperties(customResource, client)...)
+	props = append(props, passwordMaskingProperties(customResource)...)
+	props = append(props, customResource.Spec.BrokerProperties...)
+	props = append(props, readOnlyProperties(customResource)...)
+	props = append(props, ordinalBrokerProperties(customResource)...)
+	data := brokerPropertiesData(props)
 	if desired == nil {
 		secret := secrets.MakeSecret(resourceName, resourceName.Name, data, namer.LabelBuilder.Labels())
 		desired = &secret
@@ -2022,6 +3811,146 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) addResourceForBrokerProperties(
 	return resourceName.Name, true, data
 }
 
+var log4j2Levels = []string{"OFF", "FATAL", "ERROR", "WARN", "INFO", "DEBUG", "TRACE", "ALL"}
+
+// log4j2 checks the file for changes at this interval, the mounted secret is
+// updated in place so level changes apply without a pod restart
+var loggingMonitorIntervalSeconds = 10
+
+const defaultLoggingPattern = "%d %-5level [%logger] %msg%n"
+
+func isValidLogLevel(level string) bool {
+	return containsString(log4j2Levels, strings.ToUpper(level))
+}
+
+func getLoggingResourceName(customResource *brokerv1beta1.ActiveMQArtemis) string {
+	return customResource.Name + loggingConfigSuffix
+}
+
+func (reconciler *ActiveMQArtemisReconcilerImpl) addResourceForLogging(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, client rtclient.Client) string {
+	if customResource.Spec.Logging == nil {
+		return ""
+	}
+
+	resourceName := types.NamespacedName{
+		Namespace: customResource.Namespace,
+		Name:      getLoggingResourceName(customResource),
+	}
+
+	var desired *corev1.Secret
+	if obj := reconciler.cloneOfDeployed(reflect.TypeOf(corev1.Secret{}), resourceName.Name); obj != nil {
+		desired = obj.(*corev1.Secret)
+	}
+
+	data := map[string]string{LoggingConfigKey: loggingPropertiesData(customResource, client)}
+	if desired == nil {
+		secret := secrets.MakeSecret(resourceName, resourceName.Name, data, namer.LabelBuilder.Labels())
+		desired = &secret
+	} else {
+		desired.StringData = data
+	}
+
+	clog.V(1).Info("Requesting secret for logging configuration", "name", resourceName.Name)
+	reconciler.trackDesired(desired)
+
+	return resourceName.Name
+}
+
+// loggingPropertiesData renders the log4j2 properties, the referenced base file
+// goes first so that the levels and pattern of the CR take precedence
+func loggingPropertiesData(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client) string {
+	logging := customResource.Spec.Logging
+	buf := &bytes.Buffer{}
+
+	base := ""
+	if logging.ConfigMap != nil && client != nil {
+		configMap := &corev1.ConfigMap{}
+		if err := client.Get(context.TODO(), types.NamespacedName{Name: logging.ConfigMap.Name, Namespace: customResource.Namespace}, configMap); err != nil {
+			clog.V(1).Info("unable to retrieve logging configMap", "name", logging.ConfigMap.Name, "error", err)
+		} else {
+			base = configMap.Data[logging.ConfigMap.Key]
+		}
+	}
+
+	if base != "" {
+		fmt.Fprintln(buf, base)
+	} else {
+		fmt.Fprintln(buf, "appender.console.type = Console")
+		fmt.Fprintln(buf, "appender.console.name = console")
+		fmt.Fprintln(buf, "appender.console.layout.type = PatternLayout")
+		fmt.Fprintf(buf, "appender.console.layout.pattern = %v\n", defaultLoggingPattern)
+		fmt.Fprintln(buf, "rootLogger.level = INFO")
+		fmt.Fprintln(buf, "rootLogger.appenderRef.console.ref = console")
+	}
+
+	fmt.Fprintf(buf, "monitorInterval = %d\n", loggingMonitorIntervalSeconds)
+	if logging.Pattern != "" {
+		fmt.Fprintf(buf, "appender.console.layout.pattern = %v\n", logging.Pattern)
+	}
+	if logging.RootLevel != "" {
+		fmt.Fprintf(buf, "rootLogger.level = %v\n", strings.ToUpper(logging.RootLevel))
+	}
+	for _, category := range sortedKeys(logging.Levels) {
+		key := loggerKey(category)
+		fmt.Fprintf(buf, "logger.%v.name = %v\n", key, category)
+		fmt.Fprintf(buf, "logger.%v.level = %v\n", key, strings.ToUpper(logging.Levels[category]))
+	}
+	return buf.String()
+}
+
+// a logger key must be a single property segment so the dots of the category go
+func loggerKey(category string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, category)
+}
+
+// a database store keeps the journal out of the pod so no volume claim is needed
+func requiresPersistentVolume(customResource *brokerv1beta1.ActiveMQArtemis) bool {
+	return customResource.Spec.DeploymentPlan.PersistenceEnabled && !isJdbcPersistence(customResource)
+}
+
+func isEphemeral(customResource *brokerv1beta1.ActiveMQArtemis) bool {
+	return customResource.Spec.DeploymentPlan.Ephemeral != nil
+}
+
+func ephemeralVolume(customResource *brokerv1beta1.ActiveMQArtemis) []corev1.Volume {
+	ephemeral := customResource.Spec.DeploymentPlan.Ephemeral
+	var medium corev1.StorageMedium
+	if ephemeral.InMemory {
+		medium = corev1.StorageMediumMemory
+	}
+	var sizeLimit *resource.Quantity
+	if ephemeral.SizeLimit != "" {
+		if quantity, err := resource.ParseQuantity(ephemeral.SizeLimit); err == nil {
+			sizeLimit = &quantity
+		}
+	}
+	return volumes.MakeEphemeralVolume(customResource.Name, medium, sizeLimit)
+}
+
+// with nothing to recover a restart costs every message, so a broker busy under load is given
+// longer before its liveness check fails
+func relaxEphemeralProbes(container *corev1.Container, customResource *brokerv1beta1.ActiveMQArtemis) {
+	if customResource.Spec.DeploymentPlan.LivenessProbe == nil {
+		container.LivenessProbe.PeriodSeconds = ephemeralProbePeriodSeconds
+		container.LivenessProbe.FailureThreshold = ephemeralLivenessFailureThreshold
+	}
+	if customResource.Spec.DeploymentPlan.ReadinessProbe == nil {
+		container.ReadinessProbe.PeriodSeconds = ephemeralProbePeriodSeconds
+	}
+}
+
+func ephemeralProperties(customResource *brokerv1beta1.ActiveMQArtemis) []string {
+	if !isEphemeral(customResource) {
+		return nil
+	}
+	return []string{"persistenceEnabled=false"}
+}
+
 func alder32StringValue(alder32Bytes []byte) string {
 	return fmt.Sprintf("%d", binary.BigEndian.Uint32(alder32Bytes))
 }
@@ -2036,6 +3965,17 @@ func alder32Of(props []string) []byte {
 	return digest.Sum(nil)
 }
 
+// the per pod lists are rendered with the broker-N. prefix that scopes a property to the pod with ordinal N
+func ordinalBrokerProperties(customResource *brokerv1beta1.ActiveMQArtemis) []string {
+	props := []string{}
+	for _, ordinal := range customResource.Spec.OrdinalBrokerProperties {
+		for _, property := range ordinal.BrokerProperties {
+			props = append(props, fmt.Sprintf("%s%d%s%s", OrdinalPrefix, ordinal.Ordinal, OrdinalPrefixSep, property))
+		}
+	}
+	return props
+}
+
 func brokerPropertiesData(props []string) map[string]string {
 	buf := &bytes.Buffer{}
 	fmt.Fprintln(buf, "# generated by crd")
@@ -2043,7 +3983,7 @@ func brokerPropertiesData(props []string) map[string]string {
 
 	hasOrdinalPrefix := false
 	for _, propertyKeyVal := range props {
-		if strings.HasPrefix(propertyKeyVal, OrdinalPrefix) && strings.Index(propertyKeyVal, OrdinalPrefixSep) > 0 {
+		if hasOrdinal, _ := extractOrdinalPrefixSeperatorIndex(propertyKeyVal); hasOrdinal {
 			hasOrdinalPrefix = true
 			continue
 		}
@@ -2054,18 +3994,16 @@ func brokerPropertiesData(props []string) map[string]string {
 
 	if hasOrdinalPrefix {
 		for _, propertyKeyVal := range props {
-			if strings.HasPrefix(propertyKeyVal, OrdinalPrefix) {
-				if i := strings.Index(propertyKeyVal, OrdinalPrefixSep); i > 0 {
-					// use a key that will match the volume projection and broker status
-					mapKey := propertyKeyVal[:i+len(OrdinalPrefixSep)] + BrokerPropertiesName
-					value := propertyKeyVal[i+len(OrdinalPrefixSep):]
-
-					existing, found := contents[mapKey]
-					if found {
-						contents[mapKey] = fmt.Sprintf("%s%s\n", existing, value)
-					} else {
-						contents[mapKey] = fmt.Sprintf("%s\n", value)
-					}
+			if hasOrdinal, i := extractOrdinalPrefixSeperatorIndex(propertyKeyVal); hasOrdinal {
+				// use a key that will match the volume projection and broker status
+				mapKey := propertyKeyVal[:i+len(OrdinalPrefixSep)] + BrokerPropertiesName
+				value := propertyKeyVal[i+len(OrdinalPrefixSep):]
+
+				existing, found := contents[mapKey]
+				if found {
+					contents[mapKey] = fmt.Sprintf("%s%s\n", existing, value)
+				} else {
+					contents[mapKey] = fmt.Sprintf("%s\n", value)
 				}
 			}
 		}
@@ -2092,6 +4030,44 @@ func configureAffinity(podSpec *corev1.PodSpec, affinity *brokerv1beta1.Affinity
 	}
 }
 
+// configureAntiAffinityPreset adds the terms of the preset to a copy of the anti affinity of the cr,
+// they select every broker pod of the cr
+func configureAntiAffinityPreset(podSpec *corev1.PodSpec, customResource *brokerv1beta1.ActiveMQArtemis) {
+	preset := customResource.Spec.DeploymentPlan.AntiAffinityPreset
+	if preset == "" && isHASharedStore(customResource) {
+		// a backup on the node of its primary doesn't survive the loss of that node. Replicated pairs
+		// deployed before shared stores keep their affinity, a default would roll them
+		preset = brokerv1beta1.AntiAffinityPresetPreferred
+	}
+	if preset == "" {
+		return
+	}
+	if podSpec.Affinity == nil {
+		podSpec.Affinity = &corev1.Affinity{}
+	}
+	antiAffinity := &corev1.PodAntiAffinity{}
+	if podSpec.Affinity.PodAntiAffinity != nil {
+		antiAffinity = podSpec.Affinity.PodAntiAffinity.DeepCopy()
+	}
+
+	term := func(topologyKey string) corev1.PodAffinityTerm {
+		return corev1.PodAffinityTerm{
+			LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{selectors.LabelResourceKey: customResource.Name}},
+			TopologyKey:   topologyKey,
+		}
+	}
+	switch preset {
+	case brokerv1beta1.AntiAffinityPresetRequired, brokerv1beta1.AntiAffinityPresetZone:
+		antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution, term(corev1.LabelHostname))
+	case brokerv1beta1.AntiAffinityPresetPreferred:
+		antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution, corev1.WeightedPodAffinityTerm{Weight: 100, PodAffinityTerm: term(corev1.LabelHostname)})
+	}
+	if preset == brokerv1beta1.AntiAffinityPresetZone {
+		antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution, corev1.WeightedPodAffinityTerm{Weight: 100, PodAffinityTerm: term(corev1.LabelTopologyZone)})
+	}
+	podSpec.Affinity.PodAntiAffinity = antiAffinity
+}
+
 func configurePodSecurityContext(podSpec *corev1.PodSpec, podSecurityContext *corev1.PodSecurityContext) {
 	clog.V(1).Info("Configuring PodSecurityContext")
 
@@ -2246,8 +4222,11 @@ func extractOrdinalPrefixSeperatorIndex(key string) (bool, int) {
 	prefixIndex := strings.Index(key, OrdinalPrefix)
 	separatorIndex := strings.Index(key, OrdinalPrefixSep)
 
+	// only a numeric ordinal scopes the entry to a pod, anything else is a regular property
 	if prefixIndex == 0 && separatorIndex > len(OrdinalPrefix) {
-		return true, separatorIndex
+		if _, err := strconv.ParseUint(key[len(OrdinalPrefix):separatorIndex], 10, 32); err == nil {
+			return true, separatorIndex
+		}
 	}
 	return false, -1
 }
@@ -2261,6 +4240,7 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) NewStatefulSetForCR(customResou
 		Namespace: customResource.Namespace,
 	}
 	replicas := getDeploymentSize(customResource)
+	restartedAt := restartedAtForCR(customResource, currentStateFullSet)
 	currentStateFullSet = ss.MakeStatefulSet(currentStateFullSet, namer.SsNameBuilder.Name(), namer.SvcHeadlessNameBuilder.Name(), namespacedName, customResource.Annotations, namer.LabelBuilder.Labels(), &replicas)
 
 	podTemplateSpec, err := reconciler.NewPodTemplateSpecForCR(customResource, namer, &currentStateFullSet.Spec.Template, client)
@@ -2269,14 +4249,60 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) NewStatefulSetForCR(customResou
 		return nil, err
 	}
 
-	if customResource.Spec.DeploymentPlan.PersistenceEnabled {
+	if requiresPersistentVolume(customResource) && !isHASharedStore(customResource) {
 		currentStateFullSet.Spec.VolumeClaimTemplates = *NewPersistentVolumeClaimArrayForCR(customResource, namer, 1)
+	} else {
+		currentStateFullSet.Spec.VolumeClaimTemplates = nil
+	}
+	if restartedAt != "" {
+		annotations := make(map[string]string, len(podTemplateSpec.Annotations)+1)
+		for key, value := range podTemplateSpec.Annotations {
+			annotations[key] = value
+		}
+		annotations[RestartedAtAnnotation] = restartedAt
+		podTemplateSpec.Annotations = annotations
 	}
 	currentStateFullSet.Spec.Template = *podTemplateSpec
 
 	return currentStateFullSet, nil
 }
 
+// a new restartedAt value goes into the pod template, rolling the pods in the order of the
+// statefulset, only once every pod is ready and updated so that restarts don't pile onto
+// an ongoing rollout or an unavailable pod
+func restartedAtForCR(customResource *brokerv1beta1.ActiveMQArtemis, deployed *appsv1.StatefulSet) string {
+	requested := customResource.Annotations[RestartedAtAnnotation]
+	if deployed == nil {
+		return requested
+	}
+	current := deployed.Spec.Template.Annotations[RestartedAtAnnotation]
+	if requested == current || isStatefulSetSettled(deployed) {
+		return requested
+	}
+	return current
+}
+
+func isStatefulSetSettled(deployed *appsv1.StatefulSet) bool {
+	replicas := int32(1)
+	if deployed.Spec.Replicas != nil {
+		replicas = *deployed.Spec.Replicas
+	}
+	return deployed.Status.ObservedGeneration == deployed.Generation &&
+		deployed.Status.ReadyReplicas == replicas &&
+		deployed.Status.UpdatedReplicas == replicas &&
+		deployed.Status.CurrentRevision == deployed.Status.UpdateRevision
+}
+
+func updateRestartStatus(cr *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, namer Namers) {
+	deployed := &appsv1.StatefulSet{}
+	if err := client.Get(context.TODO(), types.NamespacedName{Name: namer.SsNameBuilder.Name(), Namespace: cr.Namespace}, deployed); err != nil {
+		return
+	}
+	if restartedAt, found := deployed.Spec.Template.Annotations[RestartedAtAnnotation]; found && isStatefulSetSettled(deployed) {
+		cr.Status.RestartedAt = restartedAt
+	}
+}
+
 func NewPersistentVolumeClaimArrayForCR(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, arrayLength int) *[]corev1.PersistentVolumeClaim {
 
 	var pvc *corev1.PersistentVolumeClaim = nil
@@ -2311,6 +4337,8 @@ func UpdateStatus(cr *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, nam
 
 	updateVersionStatus(cr)
 
+	updateAPIVersionStatus(cr)
+
 	updateScaleStatus(cr, namer)
 
 	reqLogger.V(1).Info("Updating status for pods")
@@ -2326,6 +4354,33 @@ func UpdateStatus(cr *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, nam
 	meta.SetStatusCondition(&cr.Status.Conditions, ValidCondition)
 	meta.SetStatusCondition(&cr.Status.Conditions, getDeploymentCondition(cr, podStatus, ValidCondition.Status == metav1.ConditionTrue))
 
+	// only present while a broker pod can't be scheduled, a false condition would hold back Ready
+	if condition := getUnschedulableCondition(cr, client, namer); condition != nil {
+		meta.SetStatusCondition(&cr.Status.Conditions, *condition)
+	} else {
+		meta.RemoveStatusCondition(&cr.Status.Conditions, brokerv1beta1.UnschedulableConditionType)
+	}
+
+	updateExternalEndpointsStatus(cr, client, namer, endpoints.GetProber())
+
+	updateRestartStatus(cr, client, namer)
+
+	updateMessageMigrationStatus(cr, client)
+
+	updateHAStatus(cr, client, namer)
+
+	updateZoneStatus(cr, client, namer)
+
+	updateAMQPConnectionStatus(cr, namer, func() map[string]map[string]mirrorHealth { return readMirrorHealth(cr, client, namer) }, mirrorSamples, time.Now())
+
+	updateSecurityAppliedCondition(cr)
+
+	updateReadOnlyMode(cr)
+
+	updateLoggingLevels(cr, func() map[string]loggerLevelSetter { return loggerLevelSetters(cr, client, namer) })
+
+	updateCapacityForecast(cr, func() map[string]capacityUsage { return readCapacityUsage(cr, client, namer) }, forecast.GetTrends(), time.Now())
+
 	if !reflect.DeepEqual(podStatus, cr.Status.PodStatus) {
 		reqLogger.V(1).Info("Pods status updated")
 		cr.Status.PodStatus = podStatus
@@ -2335,25 +4390,17 @@ func UpdateStatus(cr *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, nam
 	}
 }
 
+// the selector of the scale subresource lets the HorizontalPodAutoscaler find the broker pods, the
+// labels are sorted so that the status doesn't change between reconciles
 func updateScaleStatus(cr *brokerv1beta1.ActiveMQArtemis, namer Namers) {
-	Selector := new(bytes.Buffer)
-
-	var needsSep bool = false
+	podLabels := labels.Set{}
 	for k, v := range namer.LabelBuilder.Labels() {
-		if needsSep {
-			fmt.Fprintf(Selector, ",")
-		}
-		fmt.Fprintf(Selector, "%s=%s", k, v)
-		needsSep = true
+		podLabels[k] = v
 	}
 	for k, v := range cr.Spec.DeploymentPlan.Labels {
-		if needsSep {
-			fmt.Fprintf(Selector, ",")
-		}
-		fmt.Fprintf(Selector, "%s=%s", k, v)
-		needsSep = true
+		podLabels[k] = v
 	}
-	cr.Status.ScaleLabelSelector = Selector.String()
+	cr.Status.ScaleLabelSelector = labels.SelectorFromSet(podLabels).String()
 }
 
 func updateVersionStatus(cr *brokerv1beta1.ActiveMQArtemis) {
@@ -2419,6 +4466,11 @@ func getDeploymentCondition(cr *brokerv1beta1.ActiveMQArtemis, podStatus olm.Dep
 	}
 
 	deploymentSize := getDeploymentSize(cr)
+	readySize := deploymentSize
+	if isHAEnabled(cr) {
+		// only the live broker of a pair is ready
+		readySize = deploymentSize / 2
+	}
 	if deploymentSize == 0 {
 		return metav1.Condition{
 			Type:    brokerv1beta1.DeployedConditionType,
@@ -2427,12 +4479,12 @@ func getDeploymentCondition(cr *brokerv1beta1.ActiveMQArtemis, podStatus olm.Dep
 			Message: common.DeployedConditionZeroSizeMessage,
 		}
 	}
-	if len(podStatus.Ready) != int(deploymentSize) {
+	if len(podStatus.Ready) != int(readySize) {
 		return metav1.Condition{
 			Type:    brokerv1beta1.DeployedConditionType,
 			Status:  metav1.ConditionFalse,
 			Reason:  brokerv1beta1.DeployedConditionNotReadyReason,
-			Message: fmt.Sprintf("%d/%d pods ready", len(podStatus.Ready), deploymentSize),
+			Message: fmt.Sprintf("%d/%d pods ready", len(podStatus.Ready), readySize),
 		}
 	}
 	return metav1.Condition{
@@ -2442,6 +4494,204 @@ func getDeploymentCondition(cr *brokerv1beta1.ActiveMQArtemis, podStatus olm.Dep
 	}
 }
 
+func getUnschedulableCondition(cr *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, namer Namers) *metav1.Condition {
+
+	for i := int32(0); i < getDeploymentSize(cr); i++ {
+		pod := &corev1.Pod{}
+		podName := namer.SsNameBuilder.Name() + "-" + strconv.Itoa(int(i))
+		if err := client.Get(context.TODO(), types.NamespacedName{Name: podName, Namespace: cr.Namespace}, pod); err != nil {
+			continue
+		}
+		for _, podCondition := range pod.Status.Conditions {
+			if podCondition.Type != corev1.PodScheduled || podCondition.Status != corev1.ConditionFalse || podCondition.Reason != corev1.PodReasonUnschedulable {
+				continue
+			}
+			message := fmt.Sprintf("pod %v with ordinal %d is unschedulable", podName, i)
+			if requests := podResourceRequests(pod); requests != "" {
+				message += ", it requests " + requests
+			}
+			if pvc := pendingClaims(cr, client, pod); len(pvc) > 0 {
+				message += ", pending persistent volume claims " + strings.Join(pvc, ", ")
+			}
+			return &metav1.Condition{
+				Type:    brokerv1beta1.UnschedulableConditionType,
+				Status:  metav1.ConditionTrue,
+				Reason:  brokerv1beta1.UnschedulableConditionPendingReason,
+				Message: message + ": " + podCondition.Message,
+			}
+		}
+	}
+	return nil
+}
+
+type exposedEndpoint struct {
+	name     string
+	endpoint endpoints.Endpoint
+}
+
+func getExposedEndpoints(cr *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, namer Namers) []exposedEndpoint {
+	exposed := []exposedEndpoint{}
+	opts := []rtclient.ListOption{rtclient.InNamespace(cr.Namespace), rtclient.MatchingLabels(namer.LabelBuilder.Labels())}
+
+	if isOpenshift, err := environments.DetectOpenshift(); isOpenshift && err == nil {
+		routeList := &routev1.RouteList{}
+		if err := client.List(context.TODO(), routeList, opts...); err != nil {
+			clog.V(1).Info("unable to list routes", "error", err)
+			return exposed
+		}
+		for _, route := range routeList.Items {
+			if route.Spec.Host == "" {
+				continue
+			}
+			endpoint := endpoints.Endpoint{Host: route.Spec.Host, Port: 80}
+			if route.Spec.TLS != nil {
+				endpoint.Port = 443
+				endpoint.TLS = true
+			}
+			exposed = append(exposed, exposedEndpoint{name: route.Name, endpoint: endpoint})
+		}
+	}
+
+	// ingresses are the default off OpenShift and can be asked for by an acceptor on it
+	ingressList := &netv1.IngressList{}
+	if err := client.List(context.TODO(), ingressList, opts...); err != nil {
+		clog.V(1).Info("unable to list ingresses", "error", err)
+		return exposed
+	}
+	for _, ingress := range ingressList.Items {
+		tlsHosts := map[string]bool{}
+		for _, tls := range ingress.Spec.TLS {
+			for _, host := range tls.Hosts {
+				tlsHosts[host] = true
+			}
+		}
+		for _, rule := range ingress.Spec.Rules {
+			if rule.Host == "" {
+				continue
+			}
+			endpoint := endpoints.Endpoint{Host: rule.Host, Port: 80}
+			if tlsHosts[rule.Host] {
+				endpoint.Port = 443
+				endpoint.TLS = true
+			}
+			exposed = append(exposed, exposedEndpoint{name: ingress.Name, endpoint: endpoint})
+		}
+	}
+
+	for _, acceptor := range cr.Spec.Acceptors {
+		if !acceptor.Expose || acceptorExposeMode(acceptor) != brokerv1beta1.ExposeModeLoadBalancer {
+			continue
+		}
+		serviceNames := []string{exposedServiceName(cr, acceptor.Name, brokerv1beta1.ExposeModeLoadBalancer)}
+		if acceptor.ExposePerPod {
+			serviceNames = nil
+			for i := int32(0); i < getDeploymentSize(cr); i++ {
+				serviceNames = append(serviceNames, exposedServiceName(cr, acceptor.Name+"-"+strconv.Itoa(int(i)), brokerv1beta1.ExposeModeLoadBalancer))
+			}
+		}
+		for _, name := range serviceNames {
+			service := &corev1.Service{}
+			if err := client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: cr.Namespace}, service); err != nil {
+				continue
+			}
+			// the cloud provider fills in the address once the load balancer is provisioned
+			for _, ingress := range service.Status.LoadBalancer.Ingress {
+				host := ingress.Hostname
+				if host == "" {
+					host = ingress.IP
+				}
+				if host != "" {
+					exposed = append(exposed, exposedEndpoint{name: name, endpoint: endpoints.Endpoint{Host: host, Port: acceptor.Port, TLS: acceptor.SSLEnabled}})
+				}
+			}
+		}
+	}
+
+	sort.Slice(exposed, func(i, j int) bool {
+		return exposed[i].name < exposed[j].name || exposed[i].name == exposed[j].name && exposed[i].endpoint.Host < exposed[j].endpoint.Host
+	})
+	return exposed
+}
+
+// only endpoints that resolve and accept a connection are advertised, NotReachable
+// stays true and the CR is requeued until the rest catch up
+func updateExternalEndpointsStatus(cr *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, namer Namers, prober *endpoints.Prober) {
+
+	reachable := []brokerv1beta1.ExternalEndpointStatus{}
+	pending := []string{}
+	failed := []string{}
+
+	for _, exposed := range getExposedEndpoints(cr, client, namer) {
+		result, known := prober.Check(exposed.endpoint)
+		if !known {
+			pending = append(pending, exposed.endpoint.Address())
+		} else if !result.Reachable {
+			failed = append(failed, exposed.endpoint.Address()+": "+result.Error)
+		} else {
+			reachable = append(reachable, brokerv1beta1.ExternalEndpointStatus{
+				Name: exposed.name,
+				Host: exposed.endpoint.Host,
+				Port: exposed.endpoint.Port,
+				TLS:  exposed.endpoint.TLS,
+			})
+		}
+	}
+
+	cr.Status.ExternalEndpoints = nil
+	if len(reachable) > 0 {
+		cr.Status.ExternalEndpoints = reachable
+	}
+
+	if len(failed) > 0 {
+		meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+			Type:    brokerv1beta1.NotReachableConditionType,
+			Status:  metav1.ConditionTrue,
+			Reason:  brokerv1beta1.NotReachableConditionFailedReason,
+			Message: strings.Join(failed, ", "),
+		})
+	} else if len(pending) > 0 {
+		meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+			Type:    brokerv1beta1.NotReachableConditionType,
+			Status:  metav1.ConditionTrue,
+			Reason:  brokerv1beta1.NotReachableConditionPendingReason,
+			Message: "checking " + strings.Join(pending, ", "),
+		})
+	} else {
+		meta.RemoveStatusCondition(&cr.Status.Conditions, brokerv1beta1.NotReachableConditionType)
+	}
+}
+
+func podResourceRequests(pod *corev1.Pod) string {
+	total := corev1.ResourceList{}
+	for _, container := range pod.Spec.Containers {
+		for name, quantity := range container.Resources.Requests {
+			sum := total[name]
+			sum.Add(quantity)
+			total[name] = sum
+		}
+	}
+	requests := []string{}
+	for name, quantity := range total {
+		requests = append(requests, fmt.Sprintf("%v=%v", name, quantity.String()))
+	}
+	sort.Strings(requests)
+	return strings.Join(requests, " ")
+}
+
+func pendingClaims(cr *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, pod *corev1.Pod) []string {
+	pending := []string{}
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim == nil {
+			continue
+		}
+		pvc := &corev1.PersistentVolumeClaim{}
+		if err := client.Get(context.TODO(), types.NamespacedName{Name: volume.PersistentVolumeClaim.ClaimName, Namespace: cr.Namespace}, pvc); err == nil && pvc.Status.Phase == corev1.ClaimPending {
+			pending = append(pending, pvc.Name)
+		}
+	}
+	return pending
+}
+
 func updatePodStatus(cr *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, namespacedName types.NamespacedName) olm.DeploymentStatus {
 
 	reqLogger := ctrl.Log.WithValues("ActiveMQArtemis Name", namespacedName.Name)
@@ -2486,6 +4736,7 @@ func getSingleStatefulSetStatus(ss *appsv1.StatefulSet, cr *brokerv1beta1.Active
 		requestedCount = *ss.Spec.Replicas
 	}
 	cr.Status.DeploymentPlanSize = requestedCount
+	cr.Status.Replicas = ss.Status.Replicas
 
 	targetCount := ss.Status.Replicas
 	readyCount := ss.Status.ReadyReplicas
@@ -2524,6 +4775,10 @@ func MakeEnvVarArrayForCR(customResource *brokerv1beta1.ActiveMQArtemis, namer N
 	} else {
 		journalType = "nio"
 	}
+	// libaio needs O_DIRECT, which tmpfs doesn't support
+	if isEphemeral(customResource) {
+		journalType = "nio"
+	}
 
 	jolokiaAgentEnabled := "false"
 	if customResource.Spec.DeploymentPlan.JolokiaAgentEnabled {
@@ -2539,16 +4794,19 @@ func MakeEnvVarArrayForCR(customResource *brokerv1beta1.ActiveMQArtemis, namer N
 		managementRBACEnabled = "false"
 	}
 
-	metricsPluginEnabled := "false"
-	if customResource.Spec.DeploymentPlan.EnableMetricsPlugin != nil {
-		metricsPluginEnabled = strconv.FormatBool(*customResource.Spec.DeploymentPlan.EnableMetricsPlugin)
-	}
+	metricsPluginEnabled := strconv.FormatBool(isMetricsPluginEnabled(customResource))
 
 	envVar := []corev1.EnvVar{}
-	envVarArrayForBasic := environments.AddEnvVarForBasic(requireLogin, journalType, namer.SvcPingNameBuilder.Name())
+	envVarArrayForBasic := environments.AddEnvVarForBasic(requireLogin, journalType, namer.SvcPingNameBuilder.Name(), getAdminRole(customResource))
 	envVar = append(envVar, envVarArrayForBasic...)
-	if customResource.Spec.DeploymentPlan.PersistenceEnabled {
+	if requiresPersistentVolume(customResource) || isEphemeral(customResource) {
 		envVarArrayForPresistent := environments.AddEnvVarForPersistent(customResource.Name)
+		for i := range envVarArrayForPresistent {
+			// the brokers of all the pairs mount the shared store, they must not log to the same files
+			if envVarArrayForPresistent[i].Name == "AMQ_DATA_DIR_LOGGING" && isHASharedStore(customResource) {
+				envVarArrayForPresistent[i].Value = "false"
+			}
+		}
 		envVar = append(envVar, envVarArrayForPresistent...)
 	}
 
@@ -2558,12 +4816,22 @@ func MakeEnvVarArrayForCR(customResource *brokerv1beta1.ActiveMQArtemis, namer N
 	envVarArrayForJolokia := environments.AddEnvVarForJolokia(jolokiaAgentEnabled)
 	envVar = append(envVar, envVarArrayForJolokia...)
 
+	if jolokia := customResource.Spec.Console.Jolokia; jolokia != nil && jolokia.CredentialsSecret != "" {
+		envVarArrayForJolokiaCredentials := environments.AddEnvVarForJolokiaCredentials(jolokia.CredentialsSecret)
+		envVar = append(envVar, envVarArrayForJolokiaCredentials...)
+	}
+
 	envVarArrayForManagement := environments.AddEnvVarForManagement(managementRBACEnabled)
 	envVar = append(envVar, envVarArrayForManagement...)
 
 	envVarArrayForMetricsPlugin := environments.AddEnvVarForMetricsPlugin(metricsPluginEnabled)
 	envVar = append(envVar, envVarArrayForMetricsPlugin...)
 
+	if proxy := customResource.Spec.Proxy; proxy != nil {
+		envVarArrayForProxy := environments.AddEnvVarForProxy(proxy.HttpProxy, proxy.HttpsProxy, proxy.NoProxy)
+		envVar = append(envVar, envVarArrayForProxy...)
+	}
+
 	// appending any Env from CR, to allow potential override
 	envVar = append(envVar, customResource.Spec.Env...)
 
@@ -2602,6 +4870,16 @@ func UpdateBrokerPropertiesStatus(cr *brokerv1beta1.ActiveMQArtemis, client rtcl
 	result := ctrl.Result{}
 	var condition metav1.Condition
 
+	if cr.Spec.Console.Disabled {
+		// without the web server there is no jolokia endpoint to read the status from
+		meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+			Type:   brokerv1beta1.ConfigAppliedConditionType,
+			Status: metav1.ConditionUnknown,
+			Reason: brokerv1beta1.ConfigAppliedConditionConsoleDisabledReason,
+		})
+		return result
+	}
+
 	err := AssertBrokersAvailable(cr, client, scheme)
 	if err != nil {
 		condition = trapErrorAsCondition(err, brokerv1beta1.ConfigAppliedConditionType)
@@ -1,20 +1,40 @@
 package controllers
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/RHsyseng/operator-utils/pkg/resource/compare"
 	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/draincontroller"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/resources/environments"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/resources/ingresses"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/common"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/endpoints"
+	routev1 "github.com/openshift/api/route/v1"
 	"github.com/stretchr/testify/assert"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 )
 
 func TestHexShaHashOfMap(t *testing.T) {
@@ -191,6 +211,66 @@ func semanticEquals(a client.Object, b client.Object) bool {
 	return equality.Semantic.DeepEqual(a, b)
 }
 
+// newTestScheme returns a scheme holding the core and the broker types
+func newTestScheme(t *testing.T) *runtime.Scheme {
+	testScheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(testScheme))
+	assert.NoError(t, brokerv1beta1.AddToScheme(testScheme))
+	return testScheme
+}
+
+// indexedClient applies the field selectors of its lists through the indexes registered on it, the
+// fake client of this controller-runtime version has no WithIndex and ignores field selectors
+type indexedClient struct {
+	client.Client
+	indexes map[reflect.Type]map[string]client.IndexerFunc
+}
+
+// withSecretNamesIndex registers the secret names indexes of the manager on the fake client
+func withSecretNamesIndex(t *testing.T, fakeClient client.Client) client.Client {
+	indexed := &indexedClient{Client: fakeClient, indexes: map[reflect.Type]map[string]client.IndexerFunc{}}
+	assert.NoError(t, indexSecretNames(indexed))
+	return indexed
+}
+
+func (c *indexedClient) IndexField(ctx context.Context, obj client.Object, field string, extract client.IndexerFunc) error {
+	objType := reflect.TypeOf(obj)
+	if c.indexes[objType] == nil {
+		c.indexes[objType] = map[string]client.IndexerFunc{}
+	}
+	c.indexes[objType][field] = extract
+	return nil
+}
+
+func (c *indexedClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	listOpts := &client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+	fieldSelector := listOpts.FieldSelector
+	listOpts.FieldSelector = nil
+	if err := c.Client.List(ctx, list, listOpts); err != nil || fieldSelector == nil {
+		return err
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return err
+	}
+	selected := []runtime.Object{}
+	for _, item := range items {
+		matches := true
+		for _, requirement := range fieldSelector.Requirements() {
+			extract, found := c.indexes[reflect.TypeOf(item)][requirement.Field]
+			if !found {
+				return fmt.Errorf("no index on field %v of %T", requirement.Field, item)
+			}
+			matches = matches && containsString(extract(item.(client.Object)), requirement.Value)
+		}
+		if matches {
+			selected = append(selected, item)
+		}
+	}
+	return meta.SetList(list, selected)
+}
+
 func TestGetSingleStatefulSetStatus(t *testing.T) {
 
 	var expected int32 = int32(1)
@@ -231,6 +311,31 @@ func TestGetSingleStatefulSetStatus(t *testing.T) {
 	if cr.Status.DeploymentPlanSize != 2 {
 		t.Errorf("not good!, status not updated")
 	}
+	if cr.Status.Replicas != 2 {
+		t.Errorf("not good!, replicas of the scale subresource not updated")
+	}
+}
+
+func TestUpdateScaleStatusSortsSelector(t *testing.T) {
+	cr := &brokerv1beta1.ActiveMQArtemis{ObjectMeta: metav1.ObjectMeta{Name: "scaled", Namespace: "scaled-ns"}}
+	cr.Spec.DeploymentPlan.Labels = map[string]string{"zone": "a", "tier": "messaging"}
+	namer := MakeNamers(cr)
+
+	updateScaleStatus(cr, *namer)
+	selector := cr.Status.ScaleLabelSelector
+	for i := 0; i < 10; i++ {
+		updateScaleStatus(cr, *namer)
+		assert.Equal(t, selector, cr.Status.ScaleLabelSelector)
+	}
+
+	parsed, err := labels.Parse(selector)
+	assert.NoError(t, err)
+	podLabels := labels.Set{"zone": "a", "tier": "messaging"}
+	for k, v := range namer.LabelBuilder.Labels() {
+		podLabels[k] = v
+	}
+	assert.True(t, parsed.Matches(podLabels))
+	assert.False(t, parsed.Matches(labels.Set(namer.LabelBuilder.Labels())))
 }
 
 func TestGetConfigAppliedConfigMapName(t *testing.T) {
@@ -663,3 +768,1991 @@ func TestLoginConfigSyntaxCheck(t *testing.T) {
 	}
 
 }
+
+func TestNewPodTemplateSpecForCR_ConfiguresAdminAndHawtioRoles(t *testing.T) {
+	reconciler := &ActiveMQArtemisReconcilerImpl{}
+
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			AdminRole:   "corp-admins",
+			HawtioRoles: []string{"corp-admins", "corp-viewers"},
+		},
+	}
+
+	newSpec, err := reconciler.NewPodTemplateSpecForCR(cr, Namers{}, &v1.PodTemplateSpec{}, k8sClient)
+
+	assert.NoError(t, err)
+	assert.Contains(t, newSpec.Spec.Containers[0].Env, v1.EnvVar{Name: "AMQ_ROLE", Value: "corp-admins"})
+	assert.Contains(t, newSpec.Spec.Containers[0].Env, v1.EnvVar{Name: "JAVA_ARGS_APPEND", Value: "-Dhawtio.roles=corp-admins,corp-viewers"})
+}
+
+func TestValidateRolesGrantedBySecurity(t *testing.T) {
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "roles",
+			Namespace: "roles-ns",
+		},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			AdminRole: "corp-admins",
+		},
+	}
+
+	assert.Nil(t, validateRolesGrantedBySecurity(cr))
+
+	securityName := types.NamespacedName{Name: "roles-sec", Namespace: "roles-ns"}
+	namespaceToConfigHandler[securityName] = &ActiveMQArtemisSecurityConfigHandler{
+		SecurityCR: &brokerv1beta1.ActiveMQArtemisSecurity{
+			ObjectMeta: metav1.ObjectMeta{Name: securityName.Name, Namespace: securityName.Namespace},
+			Spec: brokerv1beta1.ActiveMQArtemisSecuritySpec{
+				SecuritySettings: brokerv1beta1.SecuritySettingsType{
+					Management: brokerv1beta1.ManagementSecuritySettingsType{
+						HawtioRoles: []string{"admin"},
+					},
+				},
+			},
+		},
+		NamespacedName: securityName,
+	}
+	defer delete(namespaceToConfigHandler, securityName)
+
+	condition := validateRolesGrantedBySecurity(cr)
+	assert.NotNil(t, condition)
+	assert.Equal(t, brokerv1beta1.ValidConditionRoleNotGrantedReason, condition.Reason)
+
+	cr.Spec.AdminRole = "admin"
+	assert.Nil(t, validateRolesGrantedBySecurity(cr))
+}
+
+func TestNewPodTemplateSpecForCR_MergesBrokerXmlConfigMap(t *testing.T) {
+	reconciler := &ActiveMQArtemisReconcilerImpl{}
+
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			BrokerXmlConfigMap: &v1.ConfigMapKeySelector{
+				LocalObjectReference: v1.LocalObjectReference{Name: "my-broker-xml"},
+				Key:                  "broker.xml",
+			},
+		},
+	}
+
+	newSpec, err := reconciler.NewPodTemplateSpecForCR(cr, Namers{}, &v1.PodTemplateSpec{}, nil)
+
+	assert.NoError(t, err)
+	initArgs := newSpec.Spec.InitContainers[0].Args[1]
+	assert.Contains(t, initArgs, "python3 /amq/init/scripts/merge-broker-xml.py /amq/init/config/etc/broker.xml /amq/extra/configmaps/my-broker-xml/broker.xml")
+	// merged after the instance is created
+	assert.True(t, strings.Index(initArgs, configCmd) < strings.Index(initArgs, "merge-broker-xml.py"))
+
+	mounted := false
+	for _, mount := range newSpec.Spec.InitContainers[0].VolumeMounts {
+		if mount.MountPath == "/amq/extra/configmaps/my-broker-xml" {
+			mounted = true
+		}
+	}
+	assert.True(t, mounted)
+}
+
+func TestOrdinalBrokerProperties(t *testing.T) {
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			BrokerProperties: []string{"globalMaxSize=512m"},
+			OrdinalBrokerProperties: []brokerv1beta1.OrdinalBrokerPropertiesType{
+				{Ordinal: 0, BrokerProperties: []string{"name=primary"}},
+				{Ordinal: 1, BrokerProperties: []string{"name=secondary", "connectorConfigurations.c.params.weight=2"}},
+			},
+		},
+	}
+
+	props := ordinalBrokerProperties(cr)
+	assert.Equal(t, []string{"broker-0.name=primary", "broker-1.name=secondary", "broker-1.connectorConfigurations.c.params.weight=2"}, props)
+
+	data := brokerPropertiesData(append(cr.Spec.BrokerProperties, props...))
+	assert.Contains(t, data[BrokerPropertiesName], "globalMaxSize=512m")
+	assert.Equal(t, "name=primary\n", data["broker-0."+BrokerPropertiesName])
+	assert.Equal(t, "name=secondary\nconnectorConfigurations.c.params.weight=2\n", data["broker-1."+BrokerPropertiesName])
+}
+
+func TestBrokerPropertiesDataWithOrdinals(t *testing.T) {
+	data := brokerPropertiesData([]string{
+		"globalMaxSize=512m",
+		"broker-0.name=primary",
+		"broker-1.name=secondary",
+		"broker-1.connectorConfigurations.c.params.weight=2",
+		"broker-x.notAnOrdinal=true",
+	})
+
+	assert.Len(t, data, 3)
+	assert.Contains(t, data[BrokerPropertiesName], "globalMaxSize=512m")
+	assert.Contains(t, data[BrokerPropertiesName], "broker-x.notAnOrdinal=true")
+	assert.NotContains(t, data[BrokerPropertiesName], "primary")
+	assert.Equal(t, "name=primary\n", data["broker-0."+BrokerPropertiesName])
+	assert.Equal(t, "name=secondary\nconnectorConfigurations.c.params.weight=2\n", data["broker-1."+BrokerPropertiesName])
+}
+
+func TestNewPodTemplateSpecForCR_ConfiguresProxy(t *testing.T) {
+	reconciler := &ActiveMQArtemisReconcilerImpl{}
+
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			Proxy: &brokerv1beta1.ProxyType{
+				HttpProxy:  "http://proxy.example.com:3128",
+				HttpsProxy: "http://secure-proxy.example.com:3129",
+				NoProxy:    ".svc, localhost",
+			},
+		},
+	}
+
+	newSpec, err := reconciler.NewPodTemplateSpecForCR(cr, Namers{}, &v1.PodTemplateSpec{}, k8sClient)
+
+	assert.NoError(t, err)
+	env := newSpec.Spec.Containers[0].Env
+	assert.Contains(t, env, v1.EnvVar{Name: "HTTP_PROXY", Value: "http://proxy.example.com:3128"})
+	assert.Contains(t, env, v1.EnvVar{Name: "HTTPS_PROXY", Value: "http://secure-proxy.example.com:3129"})
+	assert.Contains(t, env, v1.EnvVar{Name: "NO_PROXY", Value: ".svc, localhost"})
+	assert.Contains(t, env, v1.EnvVar{
+		Name:  "JAVA_ARGS_APPEND",
+		Value: "-Dhttp.proxyHost=proxy.example.com -Dhttp.proxyPort=3128 -Dhttps.proxyHost=secure-proxy.example.com -Dhttps.proxyPort=3129 -Dhttp.nonProxyHosts=*.svc|localhost",
+	})
+}
+
+func TestLoggingPropertiesData(t *testing.T) {
+	baseConfigMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "base-logging", Namespace: "test"},
+		Data:       map[string]string{"log4j2.properties": "rootLogger.level = WARN"},
+	}
+	client := fake.NewClientBuilder().WithObjects(baseConfigMap).Build()
+
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: "test"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			Logging: &brokerv1beta1.LoggingType{
+				RootLevel: "debug",
+				Levels:    map[string]string{"org.apache.activemq.artemis.core.server": "TRACE"},
+				Pattern:   "%m%n",
+			},
+		},
+	}
+
+	data := loggingPropertiesData(cr, client)
+	assert.Contains(t, data, "appender.console.type = Console")
+	assert.Contains(t, data, "monitorInterval = 10")
+	assert.Contains(t, data, "rootLogger.level = DEBUG")
+	assert.Contains(t, data, "appender.console.layout.pattern = %m%n")
+	assert.Contains(t, data, "logger.org_apache_activemq_artemis_core_server.name = org.apache.activemq.artemis.core.server")
+	assert.Contains(t, data, "logger.org_apache_activemq_artemis_core_server.level = TRACE")
+
+	cr.Spec.Logging.RootLevel = ""
+	cr.Spec.Logging.ConfigMap = &v1.ConfigMapKeySelector{
+		LocalObjectReference: v1.LocalObjectReference{Name: "base-logging"},
+		Key:                  "log4j2.properties",
+	}
+	data = loggingPropertiesData(cr, client)
+	assert.True(t, strings.HasPrefix(data, "rootLogger.level = WARN\n"))
+	assert.NotContains(t, data, "appender.console.type")
+}
+
+func TestNewPodTemplateSpecForCR_MountsLoggingConfig(t *testing.T) {
+	reconciler := &ActiveMQArtemisReconcilerImpl{}
+
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: "test"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			Logging: &brokerv1beta1.LoggingType{RootLevel: "INFO"},
+		},
+	}
+
+	newSpec, err := reconciler.NewPodTemplateSpecForCR(cr, Namers{}, &v1.PodTemplateSpec{}, k8sClient)
+
+	assert.NoError(t, err)
+	assert.Contains(t, newSpec.Spec.Containers[0].Env, v1.EnvVar{
+		Name:  "JAVA_ARGS_APPEND",
+		Value: "-Dlog4j2.configurationFile=/amq/extra/secrets/broker-logging-config/logging.properties",
+	})
+	found := false
+	for _, mount := range newSpec.Spec.Containers[0].VolumeMounts {
+		if mount.MountPath == "/amq/extra/secrets/broker-logging-config" {
+			found = true
+			assert.Empty(t, mount.SubPath)
+		}
+	}
+	assert.True(t, found)
+}
+
+func TestNewPodTemplateSpecForCR_BindsAcceptorToInterface(t *testing.T) {
+	reconciler := &ActiveMQArtemisReconcilerImpl{}
+
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			DeploymentPlan: brokerv1beta1.DeploymentPlanType{
+				Annotations:        map[string]string{"some": "annotation"},
+				AdditionalNetworks: []string{"data-plane", "other/mgmt-plane"},
+			},
+			Acceptors: []brokerv1beta1.AcceptorType{
+				{Name: "data", Port: 61617, BindInterface: "net1"},
+				{Name: "mgmt", Port: 61618, BindInterface: "net2"},
+				{Name: "pod", Port: 61619},
+			},
+		},
+	}
+
+	acceptors := generateAcceptorsString(cr, Namers{}, k8sClient, nil)
+	assert.Contains(t, acceptors, "tcp:\\/\\/ACCEPTOR_IF_net1:61617?")
+	assert.Contains(t, acceptors, "tcp:\\/\\/ACCEPTOR_IF_net2:61618?")
+	assert.Contains(t, acceptors, "tcp:\\/\\/ACCEPTOR_IP:61619?")
+
+	newSpec, err := reconciler.NewPodTemplateSpecForCR(cr, Namers{}, &v1.PodTemplateSpec{}, k8sClient)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "data-plane,other/mgmt-plane", newSpec.Annotations["k8s.v1.cni.cncf.io/networks"])
+	assert.Equal(t, "annotation", newSpec.Annotations["some"])
+	assert.NotContains(t, cr.Spec.DeploymentPlan.Annotations, "k8s.v1.cni.cncf.io/networks")
+
+	initArgs := newSpec.Spec.InitContainers[0].Args[1]
+	assert.Contains(t, initArgs, "python3 /amq/init/scripts/bind-interfaces.py /amq/init/config/etc/broker.xml net1 net2")
+}
+
+func TestNewPodTemplateSpecForCR_ConfiguresJvm(t *testing.T) {
+	reconciler := &ActiveMQArtemisReconcilerImpl{}
+
+	initialHeap := resource.MustParse("512Mi")
+	maxHeap := resource.MustParse("2Gi")
+	metaspace := resource.MustParse("256Mi")
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			DeploymentPlan: brokerv1beta1.DeploymentPlanType{
+				JVM: &brokerv1beta1.JVMType{
+					InitialHeapSize:  &initialHeap,
+					MaxHeapSize:      &maxHeap,
+					MaxMetaspaceSize: &metaspace,
+					GarbageCollector: "Z",
+					ExtraArgs:        []string{"-XX:+HeapDumpOnOutOfMemoryError"},
+				},
+			},
+		},
+	}
+
+	newSpec, err := reconciler.NewPodTemplateSpecForCR(cr, Namers{}, &v1.PodTemplateSpec{}, k8sClient)
+
+	assert.NoError(t, err)
+	assert.Contains(t, newSpec.Spec.Containers[0].Env, v1.EnvVar{
+		Name:  "JAVA_ARGS_APPEND",
+		Value: "-Xms512m -Xmx2048m -XX:MaxMetaspaceSize=256m -XX:+UseZGC -XX:+HeapDumpOnOutOfMemoryError",
+	})
+}
+
+func TestValidateJvm(t *testing.T) {
+	maxHeap := resource.MustParse("2Gi")
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			DeploymentPlan: brokerv1beta1.DeploymentPlanType{
+				JVM: &brokerv1beta1.JVMType{MaxHeapSize: &maxHeap},
+				Resources: v1.ResourceRequirements{
+					Limits: v1.ResourceList{v1.ResourceMemory: resource.MustParse("4Gi")},
+				},
+			},
+		},
+	}
+	assert.Nil(t, validateJvm(cr))
+
+	cr.Spec.DeploymentPlan.Resources.Limits[v1.ResourceMemory] = resource.MustParse("2Gi")
+	condition := validateJvm(cr)
+	assert.NotNil(t, condition)
+	assert.Equal(t, brokerv1beta1.ValidConditionInvalidJvmReason, condition.Reason)
+	assert.Contains(t, condition.Message, "container memory limit")
+
+	cr.Spec.DeploymentPlan.Resources.Limits = nil
+	cr.Spec.DeploymentPlan.JVM.ExtraArgs = []string{"-Xmx1g"}
+	condition = validateJvm(cr)
+	assert.NotNil(t, condition)
+	assert.Contains(t, condition.Message, "conflicts")
+
+	cr.Spec.DeploymentPlan.JVM.ExtraArgs = nil
+	cr.Spec.DeploymentPlan.JVM.GarbageCollector = "CMS"
+	condition = validateJvm(cr)
+	assert.NotNil(t, condition)
+	assert.Contains(t, condition.Message, "garbageCollector")
+}
+
+func TestValidatePoliciesMatchValidate(t *testing.T) {
+	maxHeap := resource.MustParse("2Gi")
+	invalid := map[string]func(*brokerv1beta1.ActiveMQArtemis){
+		"jvm": func(cr *brokerv1beta1.ActiveMQArtemis) {
+			cr.Spec.DeploymentPlan.JVM = &brokerv1beta1.JVMType{MaxHeapSize: &maxHeap}
+			cr.Spec.DeploymentPlan.Resources.Limits = v1.ResourceList{v1.ResourceMemory: resource.MustParse("1Gi")}
+		},
+		"anti affinity preset": func(cr *brokerv1beta1.ActiveMQArtemis) {
+			cr.Spec.DeploymentPlan.AntiAffinityPreset = "Sometimes"
+		},
+		"tls renewal": func(cr *brokerv1beta1.ActiveMQArtemis) {
+			cr.Spec.TLSRenewal = "Sometimes"
+		},
+		"ip families": func(cr *brokerv1beta1.ActiveMQArtemis) {
+			cr.Spec.IPFamilies = []v1.IPFamily{v1.IPv4Protocol, v1.IPv4Protocol}
+		},
+		"session affinity": func(cr *brokerv1beta1.ActiveMQArtemis) {
+			cr.Spec.Acceptors = []brokerv1beta1.AcceptorType{{Name: "amqp", Port: 5672, ServiceSettings: &brokerv1beta1.ServiceSettingsType{SessionAffinity: "Sometimes"}}}
+		},
+		"first of two": func(cr *brokerv1beta1.ActiveMQArtemis) {
+			cr.Spec.TLSRenewal = "Sometimes"
+			cr.Spec.IPFamilies = []v1.IPFamily{"IPv5"}
+		},
+	}
+	for name, makeInvalid := range invalid {
+		cr := &brokerv1beta1.ActiveMQArtemis{ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: "test"}}
+		makeInvalid(cr)
+
+		policy := ValidatePolicies(cr)
+		if !assert.NotNil(t, policy, name) {
+			continue
+		}
+		valid, _ := validate(cr, fake.NewClientBuilder().WithScheme(newTestScheme(t)).Build(), newTestScheme(t), *MakeNamers(cr))
+		assert.False(t, valid, name)
+		condition := meta.FindStatusCondition(cr.Status.Conditions, brokerv1beta1.ValidConditionType)
+		if assert.NotNil(t, condition, name) {
+			assert.Equal(t, policy.Status, condition.Status, name)
+			assert.Equal(t, policy.Reason, condition.Reason, name)
+			assert.Equal(t, policy.Message, condition.Message, name)
+		}
+	}
+
+	assert.Nil(t, ValidatePolicies(&brokerv1beta1.ActiveMQArtemis{}))
+}
+
+func TestMetricsConfiguration(t *testing.T) {
+	jvmGc := true
+	jvmMemory := false
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			DeploymentPlan: brokerv1beta1.DeploymentPlanType{
+				Metrics: &brokerv1beta1.MetricsType{
+					PortName:  "prometheus",
+					JvmGc:     &jvmGc,
+					JvmMemory: &jvmMemory,
+				},
+			},
+		},
+	}
+
+	assert.True(t, isMetricsPluginEnabled(cr))
+	assert.Equal(t, []string{"metricsConfiguration.jvmMemory=false", "metricsConfiguration.jvmGc=true"}, metricsProperties(cr))
+
+	ports := *headlessServicePorts(cr)
+	assert.Contains(t, ports, v1.ServicePort{
+		Name:       "prometheus",
+		Protocol:   "TCP",
+		Port:       8162,
+		TargetPort: intstr.FromInt(8161),
+	})
+	assert.Nil(t, validateMetrics(cr))
+
+	cr.Spec.DeploymentPlan.Metrics.Port = 61616
+	condition := validateMetrics(cr)
+	assert.NotNil(t, condition)
+	assert.Equal(t, brokerv1beta1.ValidConditionInvalidMetricsReason, condition.Reason)
+
+	cr.Spec.DeploymentPlan.Metrics = nil
+	assert.False(t, isMetricsPluginEnabled(cr))
+	assert.Len(t, *headlessServicePorts(cr), 2)
+}
+
+func TestNewPodTemplateSpecForCR_WiresSaslLoginModules(t *testing.T) {
+	reconciler := &ActiveMQArtemisReconcilerImpl{}
+
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "sasl", Namespace: "sasl-ns"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			Acceptors: []brokerv1beta1.AcceptorType{
+				{Name: "amqp", Port: 5672, Protocols: "amqp", SASLMechanisms: "GSSAPI,SCRAM-SHA-512"},
+			},
+		},
+	}
+
+	securityName := types.NamespacedName{Name: "sasl-sec", Namespace: "sasl-ns"}
+	securityCR := &brokerv1beta1.ActiveMQArtemisSecurity{
+		ObjectMeta: metav1.ObjectMeta{Name: securityName.Name, Namespace: securityName.Namespace},
+		Spec: brokerv1beta1.ActiveMQArtemisSecuritySpec{
+			LoginModules: brokerv1beta1.LoginModulesType{
+				ScramLoginModules: []brokerv1beta1.ScramLoginModuleType{
+					{Name: "amqp-sasl-scram", UsersSecret: "scram-users"},
+				},
+				KerberosLoginModules: []brokerv1beta1.KerberosLoginModuleType{
+					{
+						Name:              "amqp-sasl-gssapi",
+						Principal:         "amqp/broker.example.com@EXAMPLE.COM",
+						KeytabSecret:      v1.SecretKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "broker-keytab"}, Key: "broker.keytab"},
+						Krb5ConfConfigMap: &v1.ConfigMapKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "krb5"}, Key: "krb5.conf"},
+					},
+				},
+			},
+		},
+	}
+	namespaceToConfigHandler[securityName] = &ActiveMQArtemisSecurityConfigHandler{
+		SecurityCR:     securityCR,
+		NamespacedName: securityName,
+	}
+	defer delete(namespaceToConfigHandler, securityName)
+
+	modules, entries := saslLoginConfig(securityCR)
+	assert.Equal(t, "    org.apache.activemq.artemis.spi.core.security.jaas.SCRAMLoginModule sufficient;\n"+
+		"    org.apache.activemq.artemis.spi.core.security.jaas.KerberosLoginModule sufficient;\n", modules)
+	assert.Contains(t, entries, "amqp-sasl-scram {\n    org.apache.activemq.artemis.spi.core.security.jaas.SCRAMPropertiesLoginModule required")
+	assert.Contains(t, entries, "baseDir=\"/amq/extra/secrets/scram-users\"")
+	assert.Contains(t, entries, "keyTab=\"/amq/extra/secrets/broker-keytab/broker.keytab\"")
+	assert.Contains(t, entries, "principal=\"amqp/broker.example.com@EXAMPLE.COM\"")
+
+	// required is an explicit choice
+	required := "required"
+	securityCR.Spec.LoginModules.KerberosLoginModules[0].Flag = &required
+	modules, _ = saslLoginConfig(securityCR)
+	assert.Contains(t, modules, "KerberosLoginModule required;\n")
+	securityCR.Spec.LoginModules.KerberosLoginModules[0].Flag = nil
+
+	acceptors := generateAcceptorsString(cr, Namers{}, k8sClient, nil)
+	assert.Contains(t, acceptors, ";saslMechanisms=GSSAPI,SCRAM-SHA-512;saslLoginConfigScope=amqp-sasl-gssapi;")
+
+	newSpec, err := reconciler.NewPodTemplateSpecForCR(cr, Namers{}, &v1.PodTemplateSpec{}, k8sClient)
+	assert.NoError(t, err)
+	assert.Contains(t, newSpec.Spec.Containers[0].Env, v1.EnvVar{
+		Name:  "JAVA_ARGS_APPEND",
+		Value: "-Djava.security.krb5.conf=/amq/extra/configmaps/krb5/krb5.conf",
+	})
+	mountPaths := []string{}
+	for _, mount := range newSpec.Spec.Containers[0].VolumeMounts {
+		mountPaths = append(mountPaths, mount.MountPath)
+	}
+	assert.Contains(t, mountPaths, "/amq/extra/secrets/scram-users")
+	assert.Contains(t, mountPaths, "/amq/extra/secrets/broker-keytab")
+	assert.Contains(t, mountPaths, "/amq/extra/configmaps/krb5")
+
+	assert.NoError(t, securityCR.ValidateCreate())
+	condition, _ := validateGssapiAcceptors(cr, k8sClient, nil)
+	assert.Nil(t, condition)
+	for _, invalid := range []func(module *brokerv1beta1.KerberosLoginModuleType){
+		func(module *brokerv1beta1.KerberosLoginModuleType) { module.Name = "amqp-sasl-scram" },
+		func(module *brokerv1beta1.KerberosLoginModuleType) { module.Name = "activemq" },
+		func(module *brokerv1beta1.KerberosLoginModuleType) { module.Name = "amqp gssapi" },
+		func(module *brokerv1beta1.KerberosLoginModuleType) { module.Principal = "" },
+		func(module *brokerv1beta1.KerberosLoginModuleType) { module.KeytabSecret.Key = "" },
+		func(module *brokerv1beta1.KerberosLoginModuleType) { module.Krb5ConfConfigMap.Name = "" },
+		func(module *brokerv1beta1.KerberosLoginModuleType) { module.Flag = &[]string{"always"}[0] },
+	} {
+		invalidCR := securityCR.DeepCopy()
+		invalid(&invalidCR.Spec.LoginModules.KerberosLoginModules[0])
+		assert.Error(t, invalidCR.ValidateCreate())
+	}
+
+	// without a kerberos module the acceptor has nothing to accept GSSAPI contexts with
+	delete(namespaceToConfigHandler, securityName)
+	condition, retry := validateGssapiAcceptors(cr, fake.NewClientBuilder().WithScheme(newTestScheme(t)).Build(), nil)
+	assert.NotNil(t, condition)
+	assert.False(t, retry)
+	assert.Equal(t, brokerv1beta1.ValidConditionGssapiWithoutKerberosReason, condition.Reason)
+
+	// after a restart the security cr has a kerberos module before its handler is registered again
+	registering := securityCR.DeepCopy()
+	registering.Name, registering.Namespace = securityName.Name, cr.Namespace
+	condition, retry = validateGssapiAcceptors(cr, fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithObjects(registering).Build(), nil)
+	assert.Nil(t, condition)
+	assert.True(t, retry)
+
+	cr.Spec.Acceptors[0].SASLLoginConfigScope = "custom-gssapi"
+	condition, _ = validateGssapiAcceptors(cr, k8sClient, nil)
+	assert.Nil(t, condition)
+}
+
+func TestReservedAddressPrefixes(t *testing.T) {
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "reserved", Namespace: "test"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			ReservedAddressPrefixes: &brokerv1beta1.ReservedAddressPrefixesType{
+				Prefixes: []string{"sys."},
+			},
+		},
+	}
+
+	assert.Nil(t, validateReservedAddressPrefixes(cr))
+	props := reservedAddressPrefixProperties(cr)
+	assert.Len(t, props, len(brokerv1beta1.BrokerPermissionTypes))
+	assert.Contains(t, props, `securityRoles."sys.#".admin.send=true`)
+	assert.Contains(t, props, `securityRoles."sys.#".admin.createAddress=true`)
+
+	cr.Spec.ReservedAddressPrefixes.Roles = []string{"ops"}
+	assert.Contains(t, reservedAddressPrefixProperties(cr), `securityRoles."sys.#".ops.createDurableQueue=true`)
+
+	// the webhook reads the prefixes from the stored brokers
+	brokerv1beta1.SetWebhookClient(fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithObjects(cr.DeepCopy()).Build())
+	defer brokerv1beta1.SetWebhookClient(nil)
+
+	address := &brokerv1beta1.ActiveMQArtemisAddress{
+		ObjectMeta: metav1.ObjectMeta{Name: "address", Namespace: "test"},
+		Spec:       brokerv1beta1.ActiveMQArtemisAddressSpec{AddressName: "sys.audit"},
+	}
+	assert.Error(t, address.ValidateCreate())
+
+	address.Spec.ApplyToCrNames = []string{"other"}
+	assert.NoError(t, address.ValidateCreate())
+
+	address.Spec.ApplyToCrNames = nil
+	address.Namespace = "other"
+	assert.NoError(t, address.ValidateCreate())
+
+	address.Namespace = "test"
+	address.Spec.AddressName = "orders"
+	assert.NoError(t, address.ValidateUpdate(address))
+
+	cr.Spec.ReservedAddressPrefixes.Prefixes = []string{"sys"}
+	condition := validateReservedAddressPrefixes(cr)
+	assert.NotNil(t, condition)
+	assert.Equal(t, brokerv1beta1.ValidConditionInvalidReservedPrefixReason, condition.Reason)
+}
+
+func TestNewPodTemplateSpecForCR_ConfiguresJolokia(t *testing.T) {
+	reconciler := &ActiveMQArtemisReconcilerImpl{}
+
+	strict := false
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: "test"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			Console: brokerv1beta1.ConsoleType{
+				Jolokia: &brokerv1beta1.JolokiaType{
+					ForceHttps:        true,
+					AllowedOrigins:    []string{"*://console.example.com*"},
+					StrictChecking:    &strict,
+					CredentialsSecret: "operator-mgmt",
+				},
+			},
+		},
+	}
+
+	newSpec, err := reconciler.NewPodTemplateSpecForCR(cr, Namers{}, &v1.PodTemplateSpec{}, k8sClient)
+	assert.NoError(t, err)
+
+	initArgs := newSpec.Spec.InitContainers[0].Args[1]
+	assert.Contains(t, initArgs, "cp /amq/init/scripts/jolokia-access.xml /amq/init/config/etc/jolokia-access.xml")
+	assert.Equal(t, "<restrict><cors><allow-origin>*://console.example.com*</allow-origin></cors></restrict>", jolokiaAccessPolicy(cr))
+
+	userEnv := environments.Retrieve(newSpec.Spec.Containers, "AMQ_JOLOKIA_USER")
+	assert.NotNil(t, userEnv)
+	assert.Equal(t, "operator-mgmt", userEnv.ValueFrom.SecretKeyRef.Name)
+	assert.Equal(t, "jolokiaUser", userEnv.ValueFrom.SecretKeyRef.Key)
+
+	condition, _ := validateJolokia(cr, fake.NewClientBuilder().Build(), nil)
+	assert.NotNil(t, condition)
+	assert.Equal(t, brokerv1beta1.ValidConditionInvalidJolokiaReason, condition.Reason)
+
+	cr.Spec.Console.SSLEnabled = true
+	condition, retry := validateJolokia(cr, fake.NewClientBuilder().Build(), nil)
+	assert.NotNil(t, condition)
+	assert.Equal(t, brokerv1beta1.ValidConditionMissingResourcesReason, condition.Reason)
+	assert.True(t, retry)
+
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "operator-mgmt", Namespace: "test"},
+		Data:       map[string][]byte{"jolokiaUser": []byte("operator"), "jolokiaPassword": []byte("secret")},
+	}
+	condition, _ = validateJolokia(cr, fake.NewClientBuilder().WithObjects(secret).Build(), nil)
+	assert.Nil(t, condition)
+
+	cr.Spec.Console.Jolokia.AllowedOrigins = []string{"\"$(id)"}
+	condition, _ = validateJolokia(cr, fake.NewClientBuilder().WithObjects(secret).Build(), nil)
+	assert.NotNil(t, condition)
+}
+
+func TestCapacityPlaceholders(t *testing.T) {
+	reconciler := &ActiveMQArtemisReconcilerImpl{}
+
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: "test"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			DeploymentPlan: brokerv1beta1.DeploymentPlanType{
+				NodeSelector: map[string]string{"pool": "brokers"},
+				CapacityPlaceholders: &brokerv1beta1.CapacityPlaceholdersType{
+					Replicas: 2,
+				},
+			},
+		},
+	}
+
+	condition := validateCapacityPlaceholders(cr)
+	assert.NotNil(t, condition)
+	assert.Equal(t, brokerv1beta1.ValidConditionInvalidPlaceholdersReason, condition.Reason)
+
+	cr.Spec.DeploymentPlan.CapacityPlaceholders.PriorityClassName = "overprovisioning"
+	assert.NotNil(t, validateCapacityPlaceholders(cr))
+
+	cr.Spec.DeploymentPlan.Resources.Requests = v1.ResourceList{v1.ResourceMemory: resource.MustParse("2Gi")}
+	assert.Nil(t, validateCapacityPlaceholders(cr))
+
+	reconciler.applyCapacityPlaceholders(cr)
+	assert.Len(t, reconciler.requestedResources, 1)
+	deployment := reconciler.requestedResources[0].(*appsv1.Deployment)
+	assert.Equal(t, "broker-capacity-placeholder", deployment.Name)
+	assert.Equal(t, int32(2), *deployment.Spec.Replicas)
+	assert.NotContains(t, deployment.Spec.Template.Labels, "ActiveMQArtemis")
+
+	podSpec := deployment.Spec.Template.Spec
+	assert.Equal(t, "overprovisioning", podSpec.PriorityClassName)
+	assert.Equal(t, "brokers", podSpec.NodeSelector["pool"])
+	assert.Equal(t, defaultPlaceholderImage, podSpec.Containers[0].Image)
+	assert.Equal(t, resource.MustParse("2Gi"), podSpec.Containers[0].Resources.Requests[v1.ResourceMemory])
+}
+
+func TestUnschedulableCondition(t *testing.T) {
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: "test"},
+	}
+	namer := MakeNamers(cr)
+
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: namer.SsNameBuilder.Name() + "-0", Namespace: "test"},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{
+				Name: "broker",
+				Resources: v1.ResourceRequirements{Requests: v1.ResourceList{
+					v1.ResourceCPU:    resource.MustParse("2"),
+					v1.ResourceMemory: resource.MustParse("4Gi"),
+				}},
+			}},
+		},
+	}
+
+	client := fake.NewClientBuilder().WithObjects(pod).Build()
+	assert.Nil(t, getUnschedulableCondition(cr, client, *namer))
+
+	pod.Status.Conditions = []v1.PodCondition{{
+		Type:    v1.PodScheduled,
+		Status:  v1.ConditionFalse,
+		Reason:  v1.PodReasonUnschedulable,
+		Message: "0/3 nodes are available: 3 Insufficient memory.",
+	}}
+	client = fake.NewClientBuilder().WithObjects(pod).Build()
+	condition := getUnschedulableCondition(cr, client, *namer)
+	assert.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, brokerv1beta1.UnschedulableConditionPendingReason, condition.Reason)
+	assert.Contains(t, condition.Message, "ordinal 0")
+	assert.Contains(t, condition.Message, "cpu=2 memory=4Gi")
+	assert.Contains(t, condition.Message, "3 Insufficient memory")
+}
+
+func TestNewPodTemplateSpecForCR_ConfiguresConsole(t *testing.T) {
+	reconciler := &ActiveMQArtemisReconcilerImpl{}
+
+	timeout := int32(900)
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			Console: brokerv1beta1.ConsoleType{
+				BindHost:              "0.0.0.0",
+				Port:                  8443,
+				SessionTimeoutSeconds: &timeout,
+			},
+		},
+	}
+	assert.Nil(t, validateConsole(cr))
+
+	newSpec, err := reconciler.NewPodTemplateSpecForCR(cr, Namers{}, &v1.PodTemplateSpec{}, k8sClient)
+	assert.NoError(t, err)
+
+	container := newSpec.Spec.Containers[0]
+	assert.Contains(t, container.Ports, v1.ContainerPort{Name: "wconsj", ContainerPort: 8443, Protocol: "TCP"})
+	assert.Equal(t, intstr.FromInt(8443), container.LivenessProbe.TCPSocket.Port)
+	assert.Contains(t, environments.Retrieve(newSpec.Spec.Containers, "JAVA_ARGS_APPEND").Value, "-Dhawtio.sessionTimeout=900")
+
+	initArgs := newSpec.Spec.InitContainers[0].Args[1]
+	assert.Contains(t, initArgs, "python3 /amq/init/scripts/console-bootstrap.py /amq/init/config/etc/bootstrap.xml 0.0.0.0 8443 false")
+
+	for _, port := range *headlessServicePorts(cr) {
+		if port.Name == "console-jolokia" {
+			assert.Equal(t, intstr.FromInt(8443), port.TargetPort)
+		}
+	}
+
+	cr.Spec.Console = brokerv1beta1.ConsoleType{Disabled: true}
+	newSpec, err = reconciler.NewPodTemplateSpecForCR(cr, Namers{}, &v1.PodTemplateSpec{}, k8sClient)
+	assert.NoError(t, err)
+	assert.Empty(t, newSpec.Spec.Containers[0].Ports)
+	assert.NotNil(t, newSpec.Spec.Containers[0].LivenessProbe.Exec)
+	assert.Contains(t, newSpec.Spec.InitContainers[0].Args[1], "/amq/init/config/etc/bootstrap.xml - - true")
+
+	cr.Spec.Console.Expose = true
+	condition := validateConsole(cr)
+	assert.NotNil(t, condition)
+	assert.Equal(t, brokerv1beta1.ValidConditionInvalidConsoleReason, condition.Reason)
+}
+
+func TestValidateImageCompatibility(t *testing.T) {
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			DeploymentPlan: brokerv1beta1.DeploymentPlanType{
+				Image:     "quay.io/artemiscloud/activemq-artemis-broker-kubernetes:artemis.2.28.0",
+				InitImage: "quay.io/artemiscloud/activemq-artemis-broker-init:artemis.2.28.0",
+			},
+		},
+	}
+	assert.Nil(t, validateBrokerVersion(cr))
+
+	// patch releases of a supported minor version are compatible, digests can't be checked
+	cr.Spec.DeploymentPlan.Image = "registry.example.com/broker:2.28.1-3"
+	cr.Spec.DeploymentPlan.InitImage = "registry.example.com/init@sha256:0123456789abcdef"
+	assert.Nil(t, validateBrokerVersion(cr))
+
+	cr.Spec.DeploymentPlan.Image = "quay.io/artemiscloud/activemq-artemis-broker-kubernetes:artemis.2.16.0"
+	condition := validateBrokerVersion(cr)
+	assert.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, brokerv1beta1.ValidConditionUnsupportedVersionReason, condition.Reason)
+	assert.Contains(t, condition.Message, "2.16.0")
+
+	common.SetVersionCompatibilityPolicy(common.VersionCompatibilityPolicyEnforce)
+	defer common.SetVersionCompatibilityPolicy(common.VersionCompatibilityPolicyWarn)
+
+	condition = validateBrokerVersion(cr)
+	assert.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+
+	cr.Spec.DeploymentPlan.Image = "quay.io/artemiscloud/activemq-artemis-broker-kubernetes:artemis.2.27.0"
+	cr.Spec.DeploymentPlan.InitImage = "quay.io/artemiscloud/activemq-artemis-broker-init:artemis.2.28.0"
+	condition = validateBrokerVersion(cr)
+	assert.NotNil(t, condition)
+	assert.Contains(t, condition.Message, "does not match")
+}
+
+func TestExposureDefinitionForCR_IngressPassthrough(t *testing.T) {
+	t.Setenv("OPERATOR_OPENSHIFT", "false")
+
+	namespacedName := types.NamespacedName{Name: "broker", Namespace: "test"}
+	reconciler := &ActiveMQArtemisReconcilerImpl{}
+
+	obj := reconciler.ExposureDefinitionForCR(namespacedName, nil, "broker-amqp-0-svc", "amqp-0", true, "example.com", "", nil, nil)
+	ingress, ok := obj.(*netv1.Ingress)
+	assert.True(t, ok)
+	assert.Equal(t, "broker-amqp-0-svc-ing", ingress.Name)
+	assert.Equal(t, "broker-amqp-0-svc-ing.example.com", ingress.Spec.Rules[0].Host)
+	assert.Equal(t, "amqp-0", ingress.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Port.Name)
+	assert.Equal(t, "true", ingress.Annotations[ingresses.SSLPassthroughAnnotation])
+	assert.Len(t, ingress.Spec.TLS, 1)
+
+	// user annotations on the deployed ingress survive, passthrough goes with ssl
+	ingress.Annotations["owner"] = "team-a"
+	reconciler.deployed = map[reflect.Type][]client.Object{reflect.TypeOf(netv1.Ingress{}): {ingress}}
+
+	ingress = reconciler.ExposureDefinitionForCR(namespacedName, nil, "broker-amqp-0-svc", "amqp-0", false, "example.com", "", nil, nil).(*netv1.Ingress)
+	assert.Equal(t, "team-a", ingress.Annotations["owner"])
+	assert.NotContains(t, ingress.Annotations, ingresses.SSLPassthroughAnnotation)
+	assert.Nil(t, ingress.Spec.TLS)
+}
+
+func TestUpdateExternalEndpointsStatus(t *testing.T) {
+	t.Setenv("OPERATOR_OPENSHIFT", "false")
+
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: "test"},
+	}
+	namer := MakeNamers(cr)
+
+	reconciler := &ActiveMQArtemisReconcilerImpl{}
+	namespacedName := types.NamespacedName{Name: "broker", Namespace: "test"}
+	ready := reconciler.ExposureDefinitionForCR(namespacedName, namer.LabelBuilder.Labels(), "broker-amqp-0-svc", "amqp-0", true, "example.com", "", nil, nil)
+	missing := reconciler.ExposureDefinitionForCR(namespacedName, namer.LabelBuilder.Labels(), "broker-amqp-1-svc", "amqp-1", true, "example.com", "", nil, nil)
+	client := fake.NewClientBuilder().WithObjects(ready, missing).Build()
+
+	prober := endpoints.NewProber(func(endpoint endpoints.Endpoint) endpoints.Result {
+		if strings.HasPrefix(endpoint.Host, "broker-amqp-0") {
+			return endpoints.Result{Reachable: true}
+		}
+		return endpoints.Result{Error: "no such host"}
+	})
+
+	updateExternalEndpointsStatus(cr, client, *namer, prober)
+	condition := meta.FindStatusCondition(cr.Status.Conditions, brokerv1beta1.NotReachableConditionType)
+	assert.NotNil(t, condition)
+	assert.Equal(t, brokerv1beta1.NotReachableConditionPendingReason, condition.Reason)
+	assert.Empty(t, cr.Status.ExternalEndpoints)
+
+	assert.Eventually(t, func() bool {
+		updateExternalEndpointsStatus(cr, client, *namer, prober)
+		return len(cr.Status.ExternalEndpoints) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, "broker-amqp-0-svc-ing.example.com", cr.Status.ExternalEndpoints[0].Host)
+	assert.Equal(t, int32(443), cr.Status.ExternalEndpoints[0].Port)
+	assert.True(t, cr.Status.ExternalEndpoints[0].TLS)
+
+	condition = meta.FindStatusCondition(cr.Status.Conditions, brokerv1beta1.NotReachableConditionType)
+	assert.NotNil(t, condition)
+	assert.Equal(t, brokerv1beta1.NotReachableConditionFailedReason, condition.Reason)
+	assert.Contains(t, condition.Message, "broker-amqp-1-svc-ing.example.com:443: no such host")
+
+	client = fake.NewClientBuilder().WithObjects(ready).Build()
+	updateExternalEndpointsStatus(cr, client, *namer, prober)
+	assert.Nil(t, meta.FindStatusCondition(cr.Status.Conditions, brokerv1beta1.NotReachableConditionType))
+	assert.Len(t, cr.Status.ExternalEndpoints, 1)
+}
+
+func TestExposureDefinitionForCR_IngressClassAndAnnotations(t *testing.T) {
+	t.Setenv("OPERATOR_OPENSHIFT", "false")
+
+	namespacedName := types.NamespacedName{Name: "broker", Namespace: "test"}
+	reconciler := &ActiveMQArtemisReconcilerImpl{}
+
+	annotations := map[string]string{"haproxy.org/timeout-tunnel": "1h", "team": "messaging"}
+	ingress := reconciler.ExposureDefinitionForCR(namespacedName, nil, "broker-amqp-0-svc", "amqp-0", true, "example.com", "haproxy", annotations, nil).(*netv1.Ingress)
+	assert.Equal(t, "haproxy", *ingress.Spec.IngressClassName)
+	assert.Equal(t, "1h", ingress.Annotations["haproxy.org/timeout-tunnel"])
+	assert.Equal(t, "true", ingress.Annotations[ingresses.SSLPassthroughAnnotation])
+	assert.Equal(t, "haproxy.org/timeout-tunnel,team", ingress.Annotations[exposeAnnotationsKey])
+
+	// keys dropped from the CR are removed, others on the ingress are left alone
+	ingress.Annotations["owner"] = "team-a"
+	reconciler.deployed = map[reflect.Type][]client.Object{reflect.TypeOf(netv1.Ingress{}): {ingress}}
+
+	ingress = reconciler.ExposureDefinitionForCR(namespacedName, nil, "broker-amqp-0-svc", "amqp-0", true, "example.com", "", map[string]string{"team": "platform"}, nil).(*netv1.Ingress)
+	assert.Equal(t, "haproxy", *ingress.Spec.IngressClassName)
+	assert.NotContains(t, ingress.Annotations, "haproxy.org/timeout-tunnel")
+	assert.Equal(t, "platform", ingress.Annotations["team"])
+	assert.Equal(t, "team-a", ingress.Annotations["owner"])
+	assert.Equal(t, "true", ingress.Annotations[ingresses.SSLPassthroughAnnotation])
+
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			Acceptors: []brokerv1beta1.AcceptorType{{Name: "amqp", IngressClassName: "Not_Valid"}},
+		},
+	}
+	condition := validateExposure(cr)
+	assert.NotNil(t, condition)
+	assert.Equal(t, brokerv1beta1.ValidConditionInvalidExposureReason, condition.Reason)
+
+	cr.Spec.Acceptors[0].IngressClassName = "nginx"
+	cr.Spec.Console.ExposeAnnotations = map[string]string{exposeAnnotationsKey: "x"}
+	assert.NotNil(t, validateExposure(cr))
+
+	cr.Spec.Console.ExposeAnnotations = map[string]string{"nginx.ingress.kubernetes.io/proxy-read-timeout": "3600"}
+	assert.Nil(t, validateExposure(cr))
+}
+
+func TestApplyAcceptorCertificates(t *testing.T) {
+	t.Setenv("OPERATOR_OPENSHIFT", "false")
+
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: "test"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			IngressDomain:     "example.com",
+			CertificateIssuer: &brokerv1beta1.CertificateIssuerType{Name: "ca-issuer"},
+			Acceptors: []brokerv1beta1.AcceptorType{
+				{Name: "amqps", Port: 5671, SSLEnabled: true, Expose: true},
+				{Name: "manual", Port: 5672, SSLEnabled: true, SSLSecret: "hand-made"},
+				{Name: "plain", Port: 5673},
+			},
+		},
+	}
+	namer := MakeNamers(cr)
+	fakeClient := fake.NewClientBuilder().Build()
+	reconciler := &ActiveMQArtemisReconcilerImpl{}
+
+	passwords := reconciler.applyAcceptorCertificates(cr, *namer, fakeClient, nil)
+	assert.Len(t, passwords, 1)
+	password := passwords["amqps"]
+	assert.NotEmpty(t, password)
+
+	assert.Len(t, reconciler.requestedResources, 1)
+	assert.Equal(t, "broker-amqps-keystore-password", reconciler.requestedResources[0].GetName())
+
+	certificate := &unstructured.Unstructured{}
+	certificate.SetAPIVersion(certificateAPIVersion)
+	certificate.SetKind("Certificate")
+	assert.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Name: "broker-amqps-cert", Namespace: "test"}, certificate))
+	secretName, _, _ := unstructured.NestedString(certificate.Object, "spec", "secretName")
+	assert.Equal(t, "broker-amqps-secret", secretName)
+	issuerKind, _, _ := unstructured.NestedString(certificate.Object, "spec", "issuerRef", "kind")
+	assert.Equal(t, "Issuer", issuerKind)
+	dnsNames, _, _ := unstructured.NestedStringSlice(certificate.Object, "spec", "dnsNames")
+	assert.Contains(t, dnsNames, "broker-amqps-0-svc-ing.example.com")
+
+	// the password is kept once deployed
+	deployedSecret := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "broker-amqps-keystore-password"}, Data: map[string][]byte{"password": []byte(password)}}
+	reconciler = &ActiveMQArtemisReconcilerImpl{deployed: map[reflect.Type][]client.Object{reflect.TypeOf(v1.Secret{}): {deployedSecret}}}
+	assert.Equal(t, password, reconciler.applyAcceptorCertificates(cr, *namer, fakeClient, nil)["amqps"])
+
+	acceptors := generateAcceptorsString(cr, *namer, fakeClient, passwords)
+	assert.Contains(t, acceptors, "keyStorePath=\\/etc\\/broker-amqps-secret-volume\\/keystore.p12;keyStorePassword="+password+";keyStoreType=PKCS12")
+
+	// the Certificate of an acceptor that no longer uses an issuer is deleted, the others are kept
+	cr.Spec.Acceptors = append(cr.Spec.Acceptors, brokerv1beta1.AcceptorType{Name: "mqtts", Port: 8883, SSLEnabled: true})
+	reconciler = &ActiveMQArtemisReconcilerImpl{}
+	reconciler.applyAcceptorCertificates(cr, *namer, fakeClient, nil)
+	cr.Spec.Acceptors = cr.Spec.Acceptors[:3]
+	reconciler = &ActiveMQArtemisReconcilerImpl{}
+	reconciler.applyAcceptorCertificates(cr, *namer, fakeClient, nil)
+	reconciler.deleteStaleCertificates(cr, *namer, fakeClient)
+	assert.True(t, apierrors.IsNotFound(fakeClient.Get(context.TODO(), types.NamespacedName{Name: "broker-mqtts-cert", Namespace: "test"}, certificate)))
+	assert.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Name: "broker-amqps-cert", Namespace: "test"}, certificate))
+
+	// a connector issued the certificate of an acceptor would replace it
+	cr.Spec.Connectors = []brokerv1beta1.ConnectorType{{Name: "amqps", Host: "remote", Port: 5671, SSLEnabled: true, CertificateIssuer: &brokerv1beta1.CertificateIssuerType{Name: "ca-issuer"}}}
+	condition := validateCertificateIssuers(cr)
+	assert.NotNil(t, condition)
+	assert.Contains(t, condition.Message, "certificate broker-amqps-cert")
+	cr.Spec.Connectors[0].Name = "remote"
+	assert.Nil(t, validateCertificateIssuers(cr))
+
+	cr.Spec.Acceptors[2].CertificateIssuer = &brokerv1beta1.CertificateIssuerType{Name: "ca-issuer"}
+	condition = validateCertificateIssuers(cr)
+	assert.NotNil(t, condition)
+	assert.Equal(t, brokerv1beta1.ValidConditionInvalidIssuerReason, condition.Reason)
+}
+
+func TestTLSSecretsCheckSum(t *testing.T) {
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: "test"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			Acceptors: []brokerv1beta1.AcceptorType{{Name: "amqps", SSLEnabled: true, SSLSecret: "amqps-tls"}},
+		},
+	}
+	namer := MakeNamers(cr)
+
+	tlsSecret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "amqps-tls", Namespace: "test"},
+		Data:       map[string][]byte{"broker.ks": []byte("one")},
+	}
+	checkSum := func(secret *v1.Secret) string {
+		containers := []v1.Container{{Name: "broker"}}
+		trackTLSSecretsCheckSumInEnvVar(cr, *namer, fake.NewClientBuilder().WithObjects(secret).Build(), containers)
+		if envVar := environments.Retrieve(containers, tlsSecretsCheckSumEnvVarName); envVar != nil {
+			return envVar.Value
+		}
+		return ""
+	}
+
+	// a renewal leaves the brokers alone unless asked otherwise
+	assert.Empty(t, checkSum(tlsSecret))
+
+	cr.Spec.TLSRenewal = TLSRenewalRollingRestart
+	first := checkSum(tlsSecret)
+	assert.NotEmpty(t, first)
+
+	renewed := tlsSecret.DeepCopy()
+	renewed.Data["broker.ks"] = []byte("two")
+	assert.NotEqual(t, first, checkSum(renewed))
+
+	// acceptors reload their keystores in place
+	cr.Spec.TLSRenewal = TLSRenewalReload
+	assert.Empty(t, checkSum(renewed))
+	assert.Contains(t, generateAcceptorsString(cr, *namer, fake.NewClientBuilder().Build(), nil), "sslAutoReload=true")
+
+	cr.Spec.TLSRenewal = "Sometimes"
+	assert.NotNil(t, validateTLSRenewal(cr))
+
+	cr.Spec.TLSRenewal = TLSRenewalRollingRestart
+	testScheme := newTestScheme(t)
+	other := &brokerv1beta1.ActiveMQArtemis{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "test"}}
+	r := &ActiveMQArtemisReconciler{Client: withSecretNamesIndex(t, fake.NewClientBuilder().WithScheme(testScheme).WithObjects(cr, other).Build())}
+
+	requests := r.brokersUsingSecret(tlsSecret)
+	assert.Len(t, requests, 1)
+	assert.Equal(t, "broker", requests[0].Name)
+
+	cr.Spec.TLSRenewal = ""
+	r = &ActiveMQArtemisReconciler{Client: withSecretNamesIndex(t, fake.NewClientBuilder().WithScheme(testScheme).WithObjects(cr, other).Build())}
+	assert.Empty(t, r.brokersUsingSecret(tlsSecret))
+}
+
+func TestTransportParams(t *testing.T) {
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			Acceptors: []brokerv1beta1.AcceptorType{
+				{Name: "amqp", Port: 61617, Params: map[string]string{
+					"tcpSendBufferSize": "65536",
+					"handshake-timeout": "20",
+					"webSocketPath":     "/ws",
+				}},
+			},
+			Connectors: []brokerv1beta1.ConnectorType{
+				{Name: "remote", Host: "remote", Port: 61616, Params: map[string]string{"reconnectAttempts": "-1"}},
+			},
+		},
+	}
+
+	acceptors := generateAcceptorsString(cr, Namers{}, k8sClient, nil)
+	assert.Contains(t, acceptors, ";handshake-timeout=20;tcpSendBufferSize=65536;webSocketPath=\\/ws;tcpReceiveBufferSize=1048576;")
+	assert.Contains(t, generateConnectorsString(cr, Namers{}, k8sClient, nil), "tcp:\\/\\/remote:61616?reconnectAttempts=-1<\\/connector>")
+
+	assert.Nil(t, validateTransportParams(cr))
+
+	cr.Spec.Acceptors[0].Params["bad;key"] = "1"
+	condition := validateTransportParams(cr)
+	assert.NotNil(t, condition)
+	assert.Equal(t, brokerv1beta1.ValidConditionInvalidParamsReason, condition.Reason)
+
+	delete(cr.Spec.Acceptors[0].Params, "bad;key")
+	cr.Spec.Connectors[0].Params["host"] = "a<\\/connector>"
+	assert.NotNil(t, validateTransportParams(cr))
+}
+
+func TestConnectorSSLParity(t *testing.T) {
+	t.Setenv("OPERATOR_OPENSHIFT", "false")
+
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: "test"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			Connectors: []brokerv1beta1.ConnectorType{
+				{Name: "remote", Host: "remote", Port: 61617, SSLEnabled: true, SSLSecret: "remote-tls", EnabledProtocols: "TLSv1.3", VerifyHost: true, Params: map[string]string{"reconnectAttempts": "-1"}},
+				{Name: "issued", Host: "other", Port: 61617, SSLEnabled: true, CertificateIssuer: &brokerv1beta1.CertificateIssuerType{Name: "ca-issuer", Kind: "ClusterIssuer"}},
+			},
+		},
+	}
+	namer := MakeNamers(cr)
+	fakeClient := fake.NewClientBuilder().Build()
+	reconciler := &ActiveMQArtemisReconcilerImpl{}
+
+	passwords := reconciler.applyConnectorCertificates(cr, *namer, fakeClient, nil)
+	assert.Len(t, passwords, 1)
+	assert.NotEmpty(t, passwords["issued"])
+
+	certificate := &unstructured.Unstructured{}
+	certificate.SetAPIVersion(certificateAPIVersion)
+	certificate.SetKind("Certificate")
+	assert.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Name: "broker-issued-cert", Namespace: "test"}, certificate))
+	issuerKind, _, _ := unstructured.NestedString(certificate.Object, "spec", "issuerRef", "kind")
+	assert.Equal(t, "ClusterIssuer", issuerKind)
+
+	connectors := generateConnectorsString(cr, *namer, fakeClient, passwords)
+	assert.Contains(t, connectors, "tcp:\\/\\/remote:61617?sslEnabled=true;")
+	assert.Contains(t, connectors, "\\/etc\\/remote-tls-volume\\/client.ts;trustStorePassword=password;enabledProtocols=TLSv1.3;verifyHost=true;reconnectAttempts=-1<\\/connector>")
+	assert.Contains(t, connectors, "tcp:\\/\\/other:61617?sslEnabled=true;keyStorePath=\\/etc\\/broker-issued-secret-volume\\/keystore.p12;keyStorePassword="+passwords["issued"])
+
+	cr.Spec.Connectors[0].SSLEnabled = false
+	cr.Spec.Connectors[0].CertificateIssuer = &brokerv1beta1.CertificateIssuerType{Name: "ca-issuer"}
+	condition := validateCertificateIssuers(cr)
+	assert.NotNil(t, condition)
+	assert.Equal(t, brokerv1beta1.ValidConditionInvalidIssuerReason, condition.Reason)
+}
+
+func TestLoadBalancerExposure(t *testing.T) {
+	loadBalancer := brokerv1beta1.ExposeModeLoadBalancer
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: "test"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			DeploymentPlan: brokerv1beta1.DeploymentPlanType{Size: common.Int32ToPtr(2)},
+			Acceptors: []brokerv1beta1.AcceptorType{{
+				Name:              "core",
+				Port:              61617,
+				Expose:            true,
+				ExposeMode:        &loadBalancer,
+				ExposeAnnotations: map[string]string{"service.beta.kubernetes.io/aws-load-balancer-type": "nlb"},
+			}},
+		},
+	}
+	namer := MakeNamers(cr)
+	fakeClient := fake.NewClientBuilder().Build()
+
+	loadBalancers := func(reconciler *ActiveMQArtemisReconcilerImpl) []*v1.Service {
+		found := []*v1.Service{}
+		for _, obj := range reconciler.requestedResources {
+			if service, ok := obj.(*v1.Service); ok && service.Spec.Type == v1.ServiceTypeLoadBalancer {
+				found = append(found, service)
+			}
+			_, isIngress := obj.(*netv1.Ingress)
+			assert.False(t, isIngress)
+		}
+		return found
+	}
+
+	// one service for the acceptor selects every pod
+	reconciler := &ActiveMQArtemisReconcilerImpl{}
+	reconciler.configureAcceptorsExposure(cr, *namer, fakeClient, nil)
+	services := loadBalancers(reconciler)
+	assert.Len(t, services, 1)
+	assert.Equal(t, "broker-core-lb-svc", services[0].Name)
+	assert.Equal(t, int32(61617), services[0].Spec.Ports[0].Port)
+	assert.NotContains(t, services[0].Spec.Selector, "statefulset.kubernetes.io/pod-name")
+	assert.Equal(t, "nlb", services[0].Annotations["service.beta.kubernetes.io/aws-load-balancer-type"])
+
+	// or one for each pod
+	cr.Spec.Acceptors[0].ExposePerPod = true
+	reconciler = &ActiveMQArtemisReconcilerImpl{}
+	reconciler.configureAcceptorsExposure(cr, *namer, fakeClient, nil)
+	services = loadBalancers(reconciler)
+	assert.Len(t, services, 2)
+	assert.Equal(t, "broker-core-1-lb-svc", services[1].Name)
+	assert.Equal(t, namer.SsNameBuilder.Name()+"-1", services[1].Spec.Selector["statefulset.kubernetes.io/pod-name"])
+
+	// provisioned addresses are exposed endpoints
+	provisioned := services[1].DeepCopy()
+	provisioned.Status.LoadBalancer.Ingress = []v1.LoadBalancerIngress{{IP: "203.0.113.7"}}
+	fakeClient = fake.NewClientBuilder().WithObjects(provisioned).Build()
+	exposed := getExposedEndpoints(cr, fakeClient, *namer)
+	assert.Len(t, exposed, 1)
+	assert.Equal(t, "203.0.113.7", exposed[0].endpoint.Host)
+	assert.Equal(t, int32(61617), exposed[0].endpoint.Port)
+
+	invalid := brokerv1beta1.ExposeMode("nodeport")
+	cr.Spec.Acceptors[0].ExposeMode = &invalid
+	assert.NotNil(t, validateExposure(cr))
+}
+
+func TestNodePortExposure(t *testing.T) {
+	nodePort := brokerv1beta1.ExposeModeNodePort
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: "test"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			DeploymentPlan: brokerv1beta1.DeploymentPlanType{Size: common.Int32ToPtr(2)},
+			Acceptors: []brokerv1beta1.AcceptorType{{
+				Name:         "core",
+				Port:         61617,
+				Expose:       true,
+				ExposeMode:   &nodePort,
+				ExposePerPod: true,
+				NodePort:     common.Int32ToPtr(30100),
+			}},
+		},
+	}
+	namer := MakeNamers(cr)
+	assert.Nil(t, validateExposure(cr))
+
+	reconciler := &ActiveMQArtemisReconcilerImpl{}
+	reconciler.configureAcceptorsExposure(cr, *namer, fake.NewClientBuilder().Build(), nil)
+	nodePorts := map[string]int32{}
+	for _, obj := range reconciler.requestedResources {
+		if service, ok := obj.(*v1.Service); ok && service.Spec.Type == v1.ServiceTypeNodePort {
+			nodePorts[service.Name] = service.Spec.Ports[0].NodePort
+		}
+	}
+	assert.Equal(t, map[string]int32{"broker-core-0-np-svc": 30100, "broker-core-1-np-svc": 30101}, nodePorts)
+
+	// an allocated port is kept when none is fixed
+	cr.Spec.Acceptors[0].NodePort = nil
+	allocated := &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "broker-core-0-np-svc", Namespace: "test"}}
+	allocated.Spec.Ports = []v1.ServicePort{{Name: "core-0", NodePort: 31234}}
+	reconciler = &ActiveMQArtemisReconcilerImpl{deployed: map[reflect.Type][]client.Object{reflect.TypeOf(v1.Service{}): {allocated}}}
+	reconciler.configureAcceptorsExposure(cr, *namer, fake.NewClientBuilder().Build(), nil)
+	for _, obj := range reconciler.requestedResources {
+		if service, ok := obj.(*v1.Service); ok && service.Name == "broker-core-0-np-svc" {
+			assert.Equal(t, int32(31234), service.Spec.Ports[0].NodePort)
+		}
+	}
+
+	cr.Spec.Acceptors[0].NodePort = common.Int32ToPtr(65535)
+	assert.NotNil(t, validateExposure(cr))
+	cr.Spec.Acceptors[0].ExposeMode = nil
+	cr.Spec.Acceptors[0].NodePort = common.Int32ToPtr(30100)
+	assert.NotNil(t, validateExposure(cr))
+}
+
+func TestRestartedAtAnnotation(t *testing.T) {
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "broker",
+			Namespace:   "test",
+			Annotations: map[string]string{RestartedAtAnnotation: "2022-01-01T10:00:00Z"},
+		},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			DeploymentPlan: brokerv1beta1.DeploymentPlanType{
+				Size:        common.Int32ToPtr(2),
+				Annotations: map[string]string{"team": "messaging"},
+			},
+		},
+	}
+
+	reconciler := &ActiveMQArtemisReconcilerImpl{}
+	namer := MakeNamers(cr)
+	ss, err := reconciler.NewStatefulSetForCR(cr, *namer, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "2022-01-01T10:00:00Z", ss.Spec.Template.Annotations[RestartedAtAnnotation])
+	assert.Equal(t, "messaging", ss.Spec.Template.Annotations["team"])
+	assert.NotContains(t, cr.Spec.DeploymentPlan.Annotations, RestartedAtAnnotation)
+
+	// a new restart waits for the statefulset to settle
+	ss.Status = appsv1.StatefulSetStatus{ReadyReplicas: 1, UpdatedReplicas: 2, CurrentRevision: "a", UpdateRevision: "a"}
+	cr.Annotations[RestartedAtAnnotation] = "2022-01-01T11:00:00Z"
+	assert.Equal(t, "2022-01-01T10:00:00Z", restartedAtForCR(cr, ss))
+
+	ss.Status.ReadyReplicas = 2
+	assert.Equal(t, "2022-01-01T11:00:00Z", restartedAtForCR(cr, ss))
+
+	// the status reports a restart once every pod has rolled
+	fakeClient := fake.NewClientBuilder().WithObjects(ss).Build()
+	updateRestartStatus(cr, fakeClient, *namer)
+	assert.Equal(t, "2022-01-01T10:00:00Z", cr.Status.RestartedAt)
+}
+
+func TestExternalConnectorProperties(t *testing.T) {
+	loadBalancer := brokerv1beta1.ExposeModeLoadBalancer
+	ingress := brokerv1beta1.ExposeModeIngress
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: "test"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			DeploymentPlan: brokerv1beta1.DeploymentPlanType{Size: common.Int32ToPtr(2)},
+			Acceptors: []brokerv1beta1.AcceptorType{{
+				Name:                      "core",
+				Port:                      61617,
+				Expose:                    true,
+				ExposeMode:                &loadBalancer,
+				ExposePerPod:              true,
+				PublishExternalConnectors: true,
+			}, {
+				Name:                      "tls",
+				Port:                      61618,
+				SSLEnabled:                true,
+				Expose:                    true,
+				ExposeMode:                &ingress,
+				PublishExternalConnectors: true,
+			}},
+		},
+	}
+	assert.Nil(t, validateExposure(cr))
+	assert.True(t, hasExternalConnectors(cr))
+
+	provisioned := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "broker-core-0-lb-svc", Namespace: "test"},
+		Spec:       v1.ServiceSpec{Ports: []v1.ServicePort{{Name: "core-0", Port: 61617}}},
+		Status:     v1.ServiceStatus{LoadBalancer: v1.LoadBalancerStatus{Ingress: []v1.LoadBalancerIngress{{Hostname: "lb0.example.com"}}}},
+	}
+	// the load balancer of pod 1 is still being provisioned
+	pending := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "broker-core-1-lb-svc", Namespace: "test"},
+		Spec:       v1.ServiceSpec{Ports: []v1.ServicePort{{Name: "core-1", Port: 61617}}},
+	}
+	ingresses := []client.Object{}
+	for _, ordinal := range []string{"0", "1"} {
+		ingresses = append(ingresses, &netv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: "broker-tls-" + ordinal + "-svc-ing", Namespace: "test"},
+			Spec: netv1.IngressSpec{
+				Rules: []netv1.IngressRule{{Host: "broker-tls-" + ordinal + "-svc-ing.apps.example.com"}},
+				TLS:   []netv1.IngressTLS{{Hosts: []string{"broker-tls-" + ordinal + "-svc-ing.apps.example.com"}}},
+			},
+		})
+	}
+	reconciler := &ActiveMQArtemisReconcilerImpl{
+		deployed: map[reflect.Type][]client.Object{
+			reflect.TypeOf(v1.Service{}):    {provisioned, pending},
+			reflect.TypeOf(netv1.Ingress{}): ingresses,
+		},
+	}
+
+	props := reconciler.externalConnectorProperties(cr)
+	assert.Contains(t, props, "connectorConfigurations.core-external-0.factoryClassName=org.apache.activemq.artemis.core.remoting.impl.netty.NettyConnectorFactory")
+	assert.Contains(t, props, "connectorConfigurations.core-external-0.params.host=lb0.example.com")
+	assert.Contains(t, props, "connectorConfigurations.core-external-0.params.port=61617")
+	assert.NotContains(t, strings.Join(props, "\n"), "core-external-1")
+	assert.NotContains(t, strings.Join(props, "\n"), "core-external-0.params.sniHost")
+
+	assert.Contains(t, props, "connectorConfigurations.tls-external-1.params.host=broker-tls-1-svc-ing.apps.example.com")
+	assert.Contains(t, props, "connectorConfigurations.tls-external-1.params.port=443")
+	assert.Contains(t, props, "connectorConfigurations.tls-external-1.params.sslEnabled=true")
+	assert.Contains(t, props, "connectorConfigurations.tls-external-1.params.sniHost=broker-tls-1-svc-ing.apps.example.com")
+
+	// a single load balancer has no per pod address
+	cr.Spec.Acceptors[0].ExposePerPod = false
+	assert.NotNil(t, validateExposure(cr))
+	cr.Spec.Acceptors[0].ExposePerPod = true
+	cr.Spec.Acceptors[0].Expose = false
+	assert.NotNil(t, validateExposure(cr))
+}
+
+func TestSNIExposure(t *testing.T) {
+	sni := brokerv1beta1.ExposeModeSNI
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "ex.aao", Namespace: "test"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			DeploymentPlan: brokerv1beta1.DeploymentPlanType{Size: common.Int32ToPtr(2)},
+			IngressDomain:  "apps.example.com",
+			Acceptors: []brokerv1beta1.AcceptorType{{
+				Name:       "amqps",
+				Port:       61617,
+				SSLEnabled: true,
+				Expose:     true,
+				ExposeMode: &sni,
+			}},
+		},
+	}
+	assert.Nil(t, validateExposure(cr))
+	namer := MakeNamers(cr)
+
+	// every pod gets a passthrough ingress on the shared ingress controller
+	reconciler := &ActiveMQArtemisReconcilerImpl{}
+	reconciler.configureAcceptorsExposure(cr, *namer, fake.NewClientBuilder().Build(), nil)
+	hosts := []string{}
+	for _, obj := range reconciler.requestedResources {
+		if ingress, ok := obj.(*netv1.Ingress); ok {
+			assert.Equal(t, "true", ingress.Annotations[ingresses.SSLPassthroughAnnotation])
+			hosts = append(hosts, ingress.Spec.Rules[0].Host)
+		}
+		service, isService := obj.(*v1.Service)
+		assert.False(t, isService && service.Spec.Type == v1.ServiceTypeLoadBalancer)
+	}
+	assert.Equal(t, []string{"ex.aao-amqps-0-svc-ing.apps.example.com", "ex.aao-amqps-1-svc-ing.apps.example.com"}, hosts)
+
+	// and the acceptor only takes connections for those hosts
+	pattern := sniHostPattern(cr, cr.Spec.Acceptors[0])
+	assert.Equal(t, "ex[.]aao-amqps-[0-9]+-svc-ing[.].+", pattern)
+	for _, host := range hosts {
+		assert.Regexp(t, "^"+pattern+"$", host)
+	}
+	assert.NotRegexp(t, "^"+pattern+"$", "other-amqps-0-svc-ing.apps.example.com")
+
+	acceptors := generateAcceptorsString(cr, *namer, nil, map[string]string{"amqps": "secret"})
+	assert.Contains(t, acceptors, ";sniHost="+pattern)
+
+	// an explicit sniHost is kept
+	cr.Spec.Acceptors[0].SNIHost = "brokers[.]example[.]com"
+	acceptors = generateAcceptorsString(cr, *namer, nil, map[string]string{"amqps": "secret"})
+	assert.Contains(t, acceptors, ";sniHost=brokers[.]example[.]com")
+	assert.NotContains(t, acceptors, pattern)
+
+	cr.Spec.Acceptors[0].SSLEnabled = false
+	assert.NotNil(t, validateExposure(cr))
+}
+
+func TestProxyProtocol(t *testing.T) {
+	loadBalancer := brokerv1beta1.ExposeModeLoadBalancer
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: "test"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			DeploymentPlan: brokerv1beta1.DeploymentPlanType{Size: common.Int32ToPtr(1)},
+			Acceptors: []brokerv1beta1.AcceptorType{{
+				Name:              "amqp",
+				Port:              5672,
+				Expose:            true,
+				ExposeMode:        &loadBalancer,
+				ProxyProtocol:     true,
+				ExposeAnnotations: map[string]string{"load-balancer.hetzner.cloud/uses-proxyprotocol": "false"},
+			}},
+		},
+	}
+	namer := MakeNamers(cr)
+	fakeClient := fake.NewClientBuilder().Build()
+
+	assert.Nil(t, validateExposure(cr))
+	assert.Contains(t, generateAcceptorsString(cr, *namer, fakeClient, nil), ";proxyProtocolEnabled=true")
+
+	reconciler := &ActiveMQArtemisReconcilerImpl{}
+	reconciler.configureAcceptorsExposure(cr, *namer, fakeClient, nil)
+	var service *v1.Service
+	for _, obj := range reconciler.requestedResources {
+		if candidate, ok := obj.(*v1.Service); ok && candidate.Name == "broker-amqp-lb-svc" {
+			service = candidate
+		}
+	}
+	assert.NotNil(t, service)
+	assert.Equal(t, "*", service.Annotations["service.beta.kubernetes.io/aws-load-balancer-proxy-protocol"])
+	// the CR wins
+	assert.Equal(t, "false", service.Annotations["load-balancer.hetzner.cloud/uses-proxyprotocol"])
+
+	// the header is dropped with it
+	cr.Spec.Acceptors[0].ProxyProtocol = false
+	assert.NotContains(t, generateAcceptorsString(cr, *namer, fakeClient, nil), "proxyProtocolEnabled")
+	reconciler = &ActiveMQArtemisReconcilerImpl{deployed: map[reflect.Type][]client.Object{reflect.TypeOf(v1.Service{}): {service}}}
+	reconciler.configureAcceptorsExposure(cr, *namer, fakeClient, nil)
+	for _, obj := range reconciler.requestedResources {
+		assert.NotContains(t, obj.GetAnnotations(), "service.beta.kubernetes.io/aws-load-balancer-proxy-protocol")
+	}
+
+	ingress := brokerv1beta1.ExposeModeIngress
+	cr.Spec.Acceptors[0].ProxyProtocol = true
+	cr.Spec.Acceptors[0].ExposeMode = &ingress
+	assert.Equal(t, "proxy-v2", acceptorExposeAnnotations(cr.Spec.Acceptors[0])["haproxy.org/send-proxy-protocol"])
+
+	nodePort := brokerv1beta1.ExposeModeNodePort
+	cr.Spec.Acceptors[0].ExposeMode = &nodePort
+	assert.NotNil(t, validateExposure(cr))
+	cr.Spec.Acceptors[0].ExposeMode = &loadBalancer
+	cr.Spec.Acceptors[0].Expose = false
+	assert.NotNil(t, validateExposure(cr))
+}
+
+func TestRouteTLS(t *testing.T) {
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: "test"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			Console: brokerv1beta1.ConsoleType{
+				Expose:   true,
+				RouteTLS: &brokerv1beta1.RouteTLSType{Termination: brokerv1beta1.RouteTerminationEdge, CertificateSecret: "wildcard"},
+			},
+			Acceptors: []brokerv1beta1.AcceptorType{{
+				Name:       "ws",
+				SSLEnabled: true,
+				RouteTLS:   &brokerv1beta1.RouteTLSType{Termination: brokerv1beta1.RouteTerminationReencrypt, DestinationCASecret: "broker-ca"},
+			}},
+		},
+	}
+	wildcard := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "wildcard", Namespace: "test"},
+		Data:       map[string][]byte{"tls.crt": []byte("cert"), "tls.key": []byte("key")},
+	}
+	brokerCA := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "broker-ca", Namespace: "test"},
+		Data:       map[string][]byte{"ca.crt": []byte("ca")},
+	}
+
+	// the secrets may yet be created
+	condition, retry := validateRouteTLS(cr, fake.NewClientBuilder().Build(), nil)
+	assert.NotNil(t, condition)
+	assert.Equal(t, brokerv1beta1.ValidConditionMissingResourcesReason, condition.Reason)
+	assert.True(t, retry)
+
+	fakeClient := fake.NewClientBuilder().WithObjects(wildcard, brokerCA).Build()
+	condition, _ = validateRouteTLS(cr, fakeClient, nil)
+	assert.Nil(t, condition)
+
+	// a renewed route certificate reaches the routes
+	r := &ActiveMQArtemisReconciler{Client: withSecretNamesIndex(t, fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithObjects(cr).Build())}
+	assert.Len(t, r.brokersUsingSecret(wildcard), 1)
+	assert.Len(t, r.brokersUsingSecret(brokerCA), 1)
+
+	edge := routeTLSConfig(cr, fakeClient, cr.Spec.Console.RouteTLS)
+	assert.Equal(t, routev1.TLSTerminationEdge, edge.Termination)
+	assert.Equal(t, routev1.InsecureEdgeTerminationPolicyRedirect, edge.InsecureEdgeTerminationPolicy)
+	assert.Equal(t, "cert", edge.Certificate)
+	assert.Equal(t, "key", edge.Key)
+
+	reconciler := &ActiveMQArtemisReconcilerImpl{}
+	namespacedName := types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace}
+	route := reconciler.routeDefinitionForCR(namespacedName, nil, "broker-ws-0-svc", "ws-0", true, "", nil, routeTLSConfig(cr, fakeClient, cr.Spec.Acceptors[0].RouteTLS)).(*routev1.Route)
+	assert.Equal(t, routev1.TLSTerminationReencrypt, route.Spec.TLS.Termination)
+	assert.Equal(t, "ca", route.Spec.TLS.DestinationCACertificate)
+	assert.Empty(t, route.Spec.TLS.Certificate)
+
+	// unset keeps the passthrough of sslEnabled
+	assert.Nil(t, routeTLSConfig(cr, fakeClient, nil))
+	route = reconciler.routeDefinitionForCR(namespacedName, nil, "broker-ws-0-svc", "ws-0", true, "", nil, nil).(*routev1.Route)
+	assert.Equal(t, routev1.TLSTerminationPassthrough, route.Spec.TLS.Termination)
+
+	cr.Spec.Console.SSLEnabled = true
+	condition, retry = validateRouteTLS(cr, fakeClient, nil)
+	assert.Equal(t, brokerv1beta1.ValidConditionInvalidRouteTLSReason, condition.Reason)
+	assert.False(t, retry)
+	cr.Spec.Console.SSLEnabled = false
+	cr.Spec.Acceptors[0].SSLEnabled = false
+	condition, _ = validateRouteTLS(cr, fakeClient, nil)
+	assert.NotNil(t, condition)
+	cr.Spec.Acceptors[0].RouteTLS = &brokerv1beta1.RouteTLSType{Termination: "reencrypted"}
+	condition, _ = validateRouteTLS(cr, fakeClient, nil)
+	assert.Contains(t, condition.Message, "must be one of")
+}
+
+func TestEphemeralProfile(t *testing.T) {
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "bus", Namespace: "test"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			DeploymentPlan: brokerv1beta1.DeploymentPlanType{
+				JournalType: "aio",
+				Ephemeral:   &brokerv1beta1.EphemeralType{SizeLimit: "1Gi", InMemory: true},
+			},
+		},
+	}
+	namer := MakeNamers(cr)
+	assert.Nil(t, validateEphemeral(cr))
+
+	volumes := MakeVolumes(cr, *namer)
+	assert.Equal(t, "bus", volumes[0].Name)
+	assert.Nil(t, volumes[0].PersistentVolumeClaim)
+	assert.Equal(t, v1.StorageMediumMemory, volumes[0].EmptyDir.Medium)
+	assert.Equal(t, "1Gi", volumes[0].EmptyDir.SizeLimit.String())
+	assert.Equal(t, namer.GLOBAL_DATA_PATH, MakeVolumeMounts(cr, *namer)[0].MountPath)
+	assert.False(t, requiresPersistentVolume(cr))
+
+	env := map[string]string{}
+	for _, envVar := range MakeEnvVarArrayForCR(cr, *namer) {
+		env[envVar.Name] = envVar.Value
+	}
+	assert.Equal(t, "nio", env["AMQ_JOURNAL_TYPE"])
+	assert.Equal(t, namer.GLOBAL_DATA_PATH, env["AMQ_DATA_DIR"])
+	assert.Equal(t, []string{"persistenceEnabled=false"}, ephemeralProperties(cr))
+
+	container := &v1.Container{}
+	container.LivenessProbe = configureLivenessProbe(container, nil, defaultLivenessProbeHandler(cr))
+	container.ReadinessProbe = configureReadinessProbe(container, nil)
+	relaxEphemeralProbes(container, cr)
+	assert.Equal(t, int32(ephemeralLivenessFailureThreshold), container.LivenessProbe.FailureThreshold)
+	assert.Equal(t, int32(ephemeralProbePeriodSeconds), container.ReadinessProbe.PeriodSeconds)
+
+	// probes from the CR are left alone
+	cr.Spec.DeploymentPlan.LivenessProbe = &v1.Probe{FailureThreshold: 2}
+	container.LivenessProbe = configureLivenessProbe(&v1.Container{}, cr.Spec.DeploymentPlan.LivenessProbe, defaultLivenessProbeHandler(cr))
+	relaxEphemeralProbes(container, cr)
+	assert.Equal(t, int32(2), container.LivenessProbe.FailureThreshold)
+
+	cr.Spec.DeploymentPlan.Ephemeral.SizeLimit = "lots"
+	assert.NotNil(t, validateEphemeral(cr))
+	cr.Spec.DeploymentPlan.Ephemeral.SizeLimit = ""
+	cr.Spec.DeploymentPlan.PersistenceEnabled = true
+	assert.NotNil(t, validateEphemeral(cr))
+
+	cr.Spec.DeploymentPlan.Ephemeral = nil
+	assert.Nil(t, ephemeralProperties(cr))
+}
+
+func TestServiceSettings(t *testing.T) {
+	loadBalancer := brokerv1beta1.ExposeModeLoadBalancer
+	local := v1.ServiceInternalTrafficPolicyLocal
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: "test"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			DeploymentPlan: brokerv1beta1.DeploymentPlanType{Size: common.Int32ToPtr(1)},
+			Acceptors: []brokerv1beta1.AcceptorType{{
+				Name:       "stomp",
+				Port:       61613,
+				Expose:     true,
+				ExposeMode: &loadBalancer,
+				ServiceSettings: &brokerv1beta1.ServiceSettingsType{
+					SessionAffinity:       v1.ServiceAffinityClientIP,
+					ExternalTrafficPolicy: v1.ServiceExternalTrafficPolicyTypeLocal,
+					InternalTrafficPolicy: &local,
+				},
+			}},
+		},
+	}
+	namer := MakeNamers(cr)
+	assert.Nil(t, validateExposure(cr))
+
+	services := func() map[string]*v1.Service {
+		reconciler := &ActiveMQArtemisReconcilerImpl{}
+		reconciler.configureAcceptorsExposure(cr, *namer, fake.NewClientBuilder().Build(), nil)
+		found := map[string]*v1.Service{}
+		for _, obj := range reconciler.requestedResources {
+			if service, ok := obj.(*v1.Service); ok {
+				found[service.Name] = service
+			}
+		}
+		return found
+	}
+
+	found := services()
+	assert.Equal(t, v1.ServiceAffinityClientIP, found["broker-stomp-lb-svc"].Spec.SessionAffinity)
+	assert.Equal(t, v1.ServiceExternalTrafficPolicyTypeLocal, found["broker-stomp-lb-svc"].Spec.ExternalTrafficPolicy)
+	assert.Equal(t, v1.ServiceInternalTrafficPolicyLocal, *found["broker-stomp-0-svc"].Spec.InternalTrafficPolicy)
+	// an in cluster service has no external policy
+	assert.Empty(t, found["broker-stomp-0-svc"].Spec.ExternalTrafficPolicy)
+
+	// removed settings revert to the defaults
+	deployed := found["broker-stomp-lb-svc"].DeepCopy()
+	deployed.Spec.SessionAffinityConfig = &v1.SessionAffinityConfig{ClientIP: &v1.ClientIPConfig{TimeoutSeconds: common.Int32ToPtr(10800)}}
+	applyServiceSettings(deployed, nil)
+	assert.Equal(t, v1.ServiceAffinityNone, deployed.Spec.SessionAffinity)
+	assert.Nil(t, deployed.Spec.SessionAffinityConfig)
+	assert.Equal(t, v1.ServiceExternalTrafficPolicyTypeCluster, deployed.Spec.ExternalTrafficPolicy)
+	assert.Equal(t, v1.ServiceInternalTrafficPolicyCluster, *deployed.Spec.InternalTrafficPolicy)
+
+	ingress := brokerv1beta1.ExposeModeIngress
+	cr.Spec.Acceptors[0].ExposeMode = &ingress
+	assert.NotNil(t, validateExposure(cr))
+	cr.Spec.Acceptors[0].ServiceSettings.ExternalTrafficPolicy = ""
+	assert.Nil(t, validateExposure(cr))
+	cr.Spec.Acceptors[0].ServiceSettings.SessionAffinity = "Sticky"
+	assert.NotNil(t, validateExposure(cr))
+}
+
+func TestConsoleHost(t *testing.T) {
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: "test"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			DeploymentPlan: brokerv1beta1.DeploymentPlanType{Size: common.Int32ToPtr(2)},
+			Console: brokerv1beta1.ConsoleType{
+				Expose: true,
+				Host:   "console-{ordinal}.example.com",
+				Path:   "/artemis",
+			},
+		},
+	}
+	namer := MakeNamers(cr)
+	assert.Nil(t, validateConsole(cr))
+
+	reconciler := &ActiveMQArtemisReconcilerImpl{}
+	reconciler.configureConsoleExposure(cr, *namer, fake.NewClientBuilder().Build(), nil)
+	var ingress *netv1.Ingress
+	for _, obj := range reconciler.requestedResources {
+		if candidate, ok := obj.(*netv1.Ingress); ok && candidate.Name == "broker-wconsj-1-svc-ing" {
+			ingress = candidate
+		}
+	}
+	assert.NotNil(t, ingress)
+	assert.Equal(t, "console-1.example.com", ingress.Spec.Rules[0].Host)
+	assert.Equal(t, "/artemis", ingress.Spec.Rules[0].HTTP.Paths[0].Path)
+
+	route := reconciler.routeDefinitionForCR(types.NamespacedName{Name: "broker", Namespace: "test"}, nil, "broker-wconsj-0-svc", "wconsj-0", false, "apps.example.com", nil, nil)
+	setExposedHost(route, hostForOrdinal(cr.Spec.Console.Host, 0), cr.Spec.Console.Path)
+	assert.Equal(t, "console-0.example.com", route.(*routev1.Route).Spec.Host)
+	assert.Equal(t, "/artemis", route.(*routev1.Route).Spec.Path)
+
+	// the brokers can't share a host
+	cr.Spec.Console.Host = "console.example.com"
+	assert.NotNil(t, validateConsole(cr))
+	cr.Spec.DeploymentPlan.Size = common.Int32ToPtr(1)
+	assert.Nil(t, validateConsole(cr))
+
+	cr.Spec.Console.Host = "Console_0"
+	assert.NotNil(t, validateConsole(cr))
+	cr.Spec.Console.Host = ""
+
+	cr.Spec.Console.Path = "artemis"
+	assert.NotNil(t, validateConsole(cr))
+	cr.Spec.Console.Path = "/artemis"
+	cr.Spec.Console.SSLEnabled = true
+	assert.NotNil(t, validateConsole(cr))
+	cr.Spec.Console.RouteTLS = &brokerv1beta1.RouteTLSType{Termination: brokerv1beta1.RouteTerminationReencrypt}
+	assert.Nil(t, validateConsole(cr))
+}
+
+func TestHeadlessServiceCustomization(t *testing.T) {
+	publishNotReady := false
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: "headless"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			HeadlessService: &brokerv1beta1.HeadlessServiceType{
+				PublishNotReadyAddresses: &publishNotReady,
+				Ports: []v1.ServicePort{
+					{Name: "jgroups", Port: 7800},
+					{Name: "jgroups-udp", Port: 7800, Protocol: v1.ProtocolUDP, TargetPort: intstr.FromString("jgroups")},
+				},
+				Annotations: map[string]string{"prometheus.io/scrape": "true"},
+			},
+		},
+	}
+	assert.Nil(t, validateHeadlessService(cr))
+
+	service := &v1.Service{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"other": "kept"}}}
+	service.Spec.PublishNotReadyAddresses = true
+	service.Spec.Ports = *headlessServicePorts(cr)
+	customizeHeadlessService(cr, service)
+
+	assert.False(t, service.Spec.PublishNotReadyAddresses)
+	assert.Len(t, service.Spec.Ports, 4)
+	assert.Equal(t, v1.ServicePort{Name: "jgroups", Protocol: v1.ProtocolTCP, Port: 7800, TargetPort: intstr.FromInt(7800)}, service.Spec.Ports[2])
+	assert.Equal(t, intstr.FromString("jgroups"), service.Spec.Ports[3].TargetPort)
+	assert.Equal(t, "true", service.Annotations["prometheus.io/scrape"])
+
+	// annotations dropped from the cr are removed, the ones of others stay
+	cr.Spec.HeadlessService = nil
+	customizeHeadlessService(cr, service)
+	assert.NotContains(t, service.Annotations, "prometheus.io/scrape")
+	assert.Equal(t, "kept", service.Annotations["other"])
+
+	cr.Spec.HeadlessService = &brokerv1beta1.HeadlessServiceType{Ports: []v1.ServicePort{{Name: "core", Port: 61616}}}
+	condition := validateHeadlessService(cr)
+	assert.NotNil(t, condition)
+	assert.Equal(t, brokerv1beta1.ValidConditionInvalidHeadlessServiceReason, condition.Reason)
+
+	cr.Spec.HeadlessService.Ports = []v1.ServicePort{{Name: "Not_Valid", Port: 9000}}
+	assert.NotNil(t, validateHeadlessService(cr))
+}
+
+func TestAntiAffinityPreset(t *testing.T) {
+	size := int32(3)
+	userTerm := v1.PodAffinityTerm{
+		LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "database"}},
+		TopologyKey:   v1.LabelHostname,
+	}
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: "spread"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			DeploymentPlan: brokerv1beta1.DeploymentPlanType{
+				Size:               &size,
+				AntiAffinityPreset: brokerv1beta1.AntiAffinityPresetZone,
+				Affinity: brokerv1beta1.AffinityConfig{
+					PodAntiAffinity: &v1.PodAntiAffinity{RequiredDuringSchedulingIgnoredDuringExecution: []v1.PodAffinityTerm{userTerm}},
+				},
+			},
+		},
+	}
+	assert.Nil(t, validateAntiAffinityPreset(cr))
+
+	podSpec := &v1.PodSpec{}
+	configureAffinity(podSpec, &cr.Spec.DeploymentPlan.Affinity)
+	configureAntiAffinityPreset(podSpec, cr)
+
+	required := podSpec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	assert.Len(t, required, 2)
+	assert.Equal(t, userTerm, required[0])
+	assert.Equal(t, v1.LabelHostname, required[1].TopologyKey)
+	assert.Equal(t, map[string]string{"ActiveMQArtemis": "broker"}, required[1].LabelSelector.MatchLabels)
+	preferred := podSpec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+	assert.Len(t, preferred, 1)
+	assert.Equal(t, v1.LabelTopologyZone, preferred[0].PodAffinityTerm.TopologyKey)
+	// the terms of the cr are left alone
+	assert.Len(t, cr.Spec.DeploymentPlan.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution, 1)
+
+	cr.Spec.DeploymentPlan.AntiAffinityPreset = brokerv1beta1.AntiAffinityPresetPreferred
+	cr.Spec.DeploymentPlan.Affinity.PodAntiAffinity = nil
+	podSpec = &v1.PodSpec{}
+	configureAffinity(podSpec, &cr.Spec.DeploymentPlan.Affinity)
+	configureAntiAffinityPreset(podSpec, cr)
+	assert.Empty(t, podSpec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution)
+	assert.Equal(t, int32(100), podSpec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution[0].Weight)
+
+	// pod affinity that keeps the brokers together on a node
+	cr.Spec.DeploymentPlan.Affinity.PodAffinity = &v1.PodAffinity{
+		PreferredDuringSchedulingIgnoredDuringExecution: []v1.WeightedPodAffinityTerm{{Weight: 10, PodAffinityTerm: v1.PodAffinityTerm{
+			LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"application": "broker-app"}},
+			TopologyKey:   v1.LabelHostname,
+		}}},
+	}
+	condition := validateAntiAffinityPreset(cr)
+	assert.NotNil(t, condition)
+	assert.Equal(t, brokerv1beta1.ValidConditionInvalidAffinityReason, condition.Reason)
+	assert.NotNil(t, cr.ValidateCreate())
+
+	// together in a zone only contradicts the zone preset
+	cr.Spec.DeploymentPlan.Affinity.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution[0].PodAffinityTerm.TopologyKey = v1.LabelTopologyZone
+	assert.Nil(t, validateAntiAffinityPreset(cr))
+	cr.Spec.DeploymentPlan.AntiAffinityPreset = brokerv1beta1.AntiAffinityPresetZone
+	assert.NotNil(t, validateAntiAffinityPreset(cr))
+	cr.Spec.DeploymentPlan.Affinity.PodAffinity = nil
+
+	// a single node can't hold three brokers apart
+	cr.Spec.DeploymentPlan.AntiAffinityPreset = brokerv1beta1.AntiAffinityPresetRequired
+	cr.Spec.DeploymentPlan.NodeSelector = map[string]string{v1.LabelHostname: "node-1"}
+	assert.NotNil(t, validateAntiAffinityPreset(cr))
+	cr.Spec.DeploymentPlan.NodeSelector = nil
+	cr.Spec.DeploymentPlan.Affinity.NodeAffinity = &v1.NodeAffinity{RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{
+		NodeSelectorTerms: []v1.NodeSelectorTerm{{MatchExpressions: []v1.NodeSelectorRequirement{{Key: v1.LabelTopologyZone, Operator: v1.NodeSelectorOpIn, Values: []string{"zone-a"}}}}},
+	}}
+	assert.Nil(t, validateAntiAffinityPreset(cr))
+	cr.Spec.DeploymentPlan.AntiAffinityPreset = brokerv1beta1.AntiAffinityPresetZone
+	assert.NotNil(t, validateAntiAffinityPreset(cr))
+
+	cr.Spec.DeploymentPlan.AntiAffinityPreset = "rack"
+	assert.NotNil(t, validateAntiAffinityPreset(cr))
+}
+
+func TestExternalDNSAnnotations(t *testing.T) {
+	size := int32(2)
+	loadBalancer := brokerv1beta1.ExposeModeLoadBalancer
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: "dns"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			DeploymentPlan: brokerv1beta1.DeploymentPlanType{Size: &size},
+			Acceptors: []brokerv1beta1.AcceptorType{{
+				Name:              "amqp",
+				Port:              5672,
+				Expose:            true,
+				ExposeAnnotations: map[string]string{"nginx.ingress.kubernetes.io/proxy-read-timeout": "3600"},
+				ExternalDNS: &brokerv1beta1.ExternalDNSType{
+					Hostname:    "amqp-{ordinal}.example.com",
+					TTL:         60,
+					Annotations: map[string]string{"external-dns.alpha.kubernetes.io/target": "lb-{ordinal}.example.com"},
+				},
+			}},
+			Console: brokerv1beta1.ConsoleType{
+				Expose:      true,
+				ExternalDNS: &brokerv1beta1.ExternalDNSType{Hostname: "console-{ordinal}.example.com"},
+			},
+		},
+	}
+	assert.Nil(t, validateExternalDNS(cr))
+
+	annotations := withExternalDNS(acceptorExposeAnnotations(cr.Spec.Acceptors[0]), cr.Spec.Acceptors[0].ExternalDNS, 1)
+	assert.Equal(t, map[string]string{
+		"nginx.ingress.kubernetes.io/proxy-read-timeout": "3600",
+		"external-dns.alpha.kubernetes.io/hostname":      "amqp-1.example.com",
+		"external-dns.alpha.kubernetes.io/ttl":           "60",
+		"external-dns.alpha.kubernetes.io/target":        "lb-1.example.com",
+	}, annotations)
+	assert.Len(t, cr.Spec.Acceptors[0].ExposeAnnotations, 1)
+
+	// the annotations reach the generated ingress and are removed with the records
+	reconciler := &ActiveMQArtemisReconcilerImpl{}
+	ingress := reconciler.ingressDefinitionForCR(types.NamespacedName{Name: "broker", Namespace: "dns"}, map[string]string{}, "broker-amqp-0-svc", "amqp-0", false, "", "", withExternalDNS(nil, cr.Spec.Console.ExternalDNS, 0))
+	assert.Equal(t, "console-0.example.com", ingress.GetAnnotations()[externalDNSHostnameAnnotation])
+	clearExposeAnnotations(ingress)
+	assert.NotContains(t, ingress.GetAnnotations(), externalDNSHostnameAnnotation)
+
+	// one load balancer for every broker publishes one record
+	cr.Spec.Acceptors[0].ExposeMode = &loadBalancer
+	condition := validateExternalDNS(cr)
+	assert.NotNil(t, condition)
+	assert.Equal(t, brokerv1beta1.ValidConditionInvalidExternalDNSReason, condition.Reason)
+	cr.Spec.Acceptors[0].ExternalDNS.Hostname = "amqp.example.com"
+	cr.Spec.Acceptors[0].ExternalDNS.Annotations = nil
+	assert.Nil(t, validateExternalDNS(cr))
+	cr.Spec.Acceptors[0].ExposePerPod = true
+	assert.NotNil(t, validateExternalDNS(cr))
+	cr.Spec.Acceptors[0].ExposePerPod = false
+
+	cr.Spec.Acceptors[0].ExternalDNS.Annotations = map[string]string{"example.com/ttl": "60"}
+	assert.NotNil(t, validateExternalDNS(cr))
+	cr.Spec.Acceptors[0].ExternalDNS.Annotations = nil
+
+	cr.Spec.Console.Expose = false
+	assert.NotNil(t, validateExternalDNS(cr))
+}
+
+func TestRetentionPolicies(t *testing.T) {
+	maxMessages := int64(10000)
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "retention", Namespace: "test"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			RetentionPolicies: []brokerv1beta1.RetentionPolicyType{
+				{Match: "orders.#", MaxAge: "24h", MaxMessages: &maxMessages},
+				{Match: "audit", MaxMessages: &maxMessages, Action: brokerv1beta1.RetentionActionPage},
+			},
+		},
+	}
+
+	assert.Nil(t, validateRetentionPolicies(cr))
+	assert.Equal(t, []string{
+		`addressSettings."orders.#".expiryDelay=86400000`,
+		`addressSettings."orders.#".maxExpiryDelay=86400000`,
+		`addressSettings."orders.#".maxSizeMessages=10000`,
+		`addressSettings."orders.#".addressFullMessagePolicy=DROP`,
+		`addressSettings."audit".maxSizeMessages=10000`,
+		`addressSettings."audit".addressFullMessagePolicy=PAGE`,
+	}, retentionPolicyProperties(cr))
+
+	// brokerProperties come last and override the policies
+	cr.Spec.BrokerProperties = []string{`addressSettings."audit".addressFullMessagePolicy=FAIL`}
+	_, _, data := (&ActiveMQArtemisReconcilerImpl{}).addResourceForBrokerProperties(cr, *MakeNamers(cr), fake.NewClientBuilder().Build())
+	assert.NotEmpty(t, data)
+	for _, value := range data {
+		assert.Less(t, strings.Index(value, "addressFullMessagePolicy=PAGE"), strings.Index(value, "addressFullMessagePolicy=FAIL"))
+	}
+
+	for _, invalid := range []brokerv1beta1.RetentionPolicyType{
+		{Match: "orders.#", MaxAge: "1h"},
+		{Match: "logs"},
+		{Match: "logs", MaxAge: "1d"},
+		{Match: "logs", MaxAge: "500ms"},
+		{Match: "logs", MaxAge: "1h", Action: brokerv1beta1.RetentionActionFail},
+		{Match: "logs", MaxMessages: &maxMessages, Action: "block"},
+		{Match: "a b", MaxAge: "1h"},
+	} {
+		policies := cr.Spec.RetentionPolicies
+		cr.Spec.RetentionPolicies = append(policies, invalid)
+		condition := validateRetentionPolicies(cr)
+		assert.NotNil(t, condition, invalid)
+		assert.Equal(t, brokerv1beta1.ValidConditionInvalidRetentionPolicyReason, condition.Reason)
+		cr.Spec.RetentionPolicies = policies
+	}
+}
+
+func TestRedelivery(t *testing.T) {
+	attempts := int32(5)
+	persist := true
+	autoCreate := true
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			Redelivery: &brokerv1beta1.RedeliveryDefaultsType{
+				RedeliveryPolicyType: brokerv1beta1.RedeliveryPolicyType{
+					MaxDeliveryAttempts:           &attempts,
+					RedeliveryDelay:               "2s",
+					RedeliveryMultiplier:          "2.5",
+					MaxRedeliveryDelay:            "1m",
+					DeadLetterAddress:             "DLQ",
+					AutoCreateDeadLetterResources: &autoCreate,
+				},
+				PersistDeliveryCount: &persist,
+			},
+		},
+	}
+	assert.Nil(t, validateRedelivery(cr))
+	assert.Equal(t, []string{
+		`addressSettings."#".autoCreateDeadLetterResources=true`,
+		`addressSettings."#".deadLetterAddress=DLQ`,
+		`addressSettings."#".maxDeliveryAttempts=5`,
+		`addressSettings."#".maxRedeliveryDelay=60000`,
+		`addressSettings."#".redeliveryDelay=2000`,
+		`addressSettings."#".redeliveryMultiplier=2.5`,
+		"persistDeliveryCountBeforeDelivery=true",
+	}, redeliveryProperties(cr))
+
+	// an Address CR sets only what it overrides
+	unlimited := int32(-1)
+	settings, err := redeliveryAddressSettings(&brokerv1beta1.RedeliveryPolicyType{MaxDeliveryAttempts: &unlimited, DeadLetterAddress: "orders.DLQ"})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"maxDeliveryAttempts":-1,"DLA":"orders.DLQ"}`, settings)
+
+	for _, invalid := range []brokerv1beta1.RedeliveryPolicyType{
+		{MaxDeliveryAttempts: new(int32)},
+		{RedeliveryDelay: "5"},
+		{RedeliveryDelay: "48h"},
+		{RedeliveryDelay: "1m", MaxRedeliveryDelay: "10s"},
+		{RedeliveryMultiplier: "2"},
+		{RedeliveryDelay: "1s", RedeliveryMultiplier: "0.5"},
+		{RedeliveryDelay: "1s", RedeliveryMultiplier: "twice"},
+		{DeadLetterAddress: "DLQ.#"},
+	} {
+		cr.Spec.Redelivery = &brokerv1beta1.RedeliveryDefaultsType{RedeliveryPolicyType: invalid}
+		condition := validateRedelivery(cr)
+		assert.NotNil(t, condition, invalid)
+		assert.Equal(t, brokerv1beta1.ValidConditionInvalidRedeliveryReason, condition.Reason)
+		assert.Error(t, invalid.Validate("redelivery"))
+	}
+}
+
+func TestClusterConnectionTuning(t *testing.T) {
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			ClusterConnection: &brokerv1beta1.ClusterConnectionType{
+				MessageLoadBalancing: brokerv1beta1.MessageLoadBalancingOffWithRedistribution,
+				MaxHops:              common.Int32ToPtr(0),
+				CallTimeout:          &[]int64{5000}[0],
+			},
+		},
+	}
+	assert.Nil(t, validateClusterConnection(cr))
+	assert.Equal(t, []string{
+		"clusterConfigurations.my-cluster.messageLoadBalancingType=OFF_WITH_REDISTRIBUTION",
+		"clusterConfigurations.my-cluster.maxHops=0",
+		"clusterConfigurations.my-cluster.callTimeout=5000",
+	}, clusterConnectionProperties(cr))
+
+	clustered := false
+	cr.Spec.DeploymentPlan.Clustered = &clustered
+	condition := validateClusterConnection(cr)
+	if assert.NotNil(t, condition) {
+		assert.Equal(t, brokerv1beta1.ValidConditionInvalidClusterConnectionReason, condition.Reason)
+	}
+	assert.Nil(t, clusterConnectionProperties(cr))
+}
+
+func TestMessageMigrationDrainer(t *testing.T) {
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		TypeMeta:   metav1.TypeMeta{Kind: "ActiveMQArtemis", APIVersion: brokerv1beta1.GroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{Name: "drained", Namespace: "drained-ns", UID: "drained-uid"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			DeploymentPlan: brokerv1beta1.DeploymentPlanType{
+				Size:               common.Int32ToPtr(2),
+				PersistenceEnabled: true,
+				Drainer:            &brokerv1beta1.DrainerType{Image: "drainer-image", ServiceAccountName: "drainer"},
+			},
+		},
+	}
+	testScheme := newTestScheme(t)
+	c := fake.NewClientBuilder().WithScheme(testScheme).Build()
+	namer := MakeNamers(cr)
+
+	// without its encryption keys the drainer is not set up, the reconcile fails and is retried
+	t.Setenv("OPERATOR_ENCRYPTION_KEY_SECRET", "missing-keys")
+	assert.ErrorContains(t, syncMessageMigration(cr, *namer, c, testScheme), "encryption keys")
+	scaledown := &brokerv1beta1.ActiveMQArtemisScaledown{}
+	assert.True(t, apierrors.IsNotFound(c.Get(context.TODO(), types.NamespacedName{Name: "drained", Namespace: "drained-ns"}, scaledown)))
+	ctx := &ReconcileStepContext{CustomResource: cr, Namer: *namer, Client: c, Scheme: testScheme, StatefulSet: &appsv1.StatefulSet{}, reconciler: &ActiveMQArtemisReconcilerImpl{}}
+	assert.Error(t, applyReconcileSteps(ctx, getReconcileSteps()[:1]))
+	assert.True(t, isReconcileStepFailed(cr))
+	t.Setenv("OPERATOR_ENCRYPTION_KEY_SECRET", "")
+
+	assert.NoError(t, syncMessageMigration(cr, *namer, c, testScheme))
+	assert.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "drained", Namespace: "drained-ns"}, scaledown))
+	assert.Equal(t, cr.Spec.DeploymentPlan.Drainer, scaledown.Spec.Drainer)
+
+	// the drain controller reads the overrides from the scaledown cr when it creates the next drainer
+	cr.Spec.DeploymentPlan.Drainer = &brokerv1beta1.DrainerType{PodSecurityContext: &v1.PodSecurityContext{RunAsNonRoot: &[]bool{true}[0]}}
+	syncMessageMigration(cr, *namer, c, testScheme)
+	assert.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "drained", Namespace: "drained-ns"}, scaledown))
+	assert.Equal(t, cr.Spec.DeploymentPlan.Drainer, scaledown.Spec.Drainer)
+
+	cr.Spec.DeploymentPlan.Drainer = nil
+	syncMessageMigration(cr, *namer, c, testScheme)
+	assert.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "drained", Namespace: "drained-ns"}, scaledown))
+	assert.Nil(t, scaledown.Spec.Drainer)
+
+	// a change of the ha mode doesn't hold back the changes of the spec made along with it
+	cr.Spec.DeploymentPlan.Drainer = &brokerv1beta1.DrainerType{Image: "other-drainer-image"}
+	cr.Spec.DeploymentPlan.Resources = v1.ResourceRequirements{Limits: v1.ResourceList{v1.ResourceMemory: resource.MustParse("1Gi")}}
+	scaledown.Annotations[draincontroller.AnnotationHAReplication] = "true"
+	assert.NoError(t, c.Update(context.TODO(), scaledown))
+	assert.NoError(t, syncMessageMigration(cr, *namer, c, testScheme))
+	assert.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "drained", Namespace: "drained-ns"}, scaledown))
+	assert.Equal(t, "false", scaledown.Annotations[draincontroller.AnnotationHAReplication])
+	assert.Equal(t, cr.Spec.DeploymentPlan.Drainer, scaledown.Spec.Drainer)
+	assert.Equal(t, cr.Spec.DeploymentPlan.Resources, scaledown.Spec.Resources)
+}
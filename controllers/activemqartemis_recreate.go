@@ -0,0 +1,375 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	ImmutableFieldsPolicyBlock    = "Block"
+	ImmutableFieldsPolicyRecreate = "Recreate"
+
+	recreateSnapshotSuffix = "-recreate"
+)
+
+var volumeSnapshotGVK = schema.GroupVersionKind{Group: "snapshot.storage.k8s.io", Version: "v1", Kind: "VolumeSnapshot"}
+
+func getImmutableFieldsPolicy(customResource *brokerv1beta1.ActiveMQArtemis) string {
+	if customResource.Spec.DeploymentPlan.ImmutableFieldsPolicy == "" {
+		return ImmutableFieldsPolicyBlock
+	}
+	return customResource.Spec.DeploymentPlan.ImmutableFieldsPolicy
+}
+
+func isRecreateInProgress(customResource *brokerv1beta1.ActiveMQArtemis) bool {
+	condition := meta.FindStatusCondition(customResource.Status.Conditions, brokerv1beta1.RecreatedConditionType)
+	if condition == nil || condition.Status != metav1.ConditionFalse {
+		return false
+	}
+	switch condition.Reason {
+	case brokerv1beta1.RecreatedConditionDeletingReason, brokerv1beta1.RecreatedConditionSnapshottingReason, brokerv1beta1.RecreatedConditionReplacingVolumesReason:
+		return true
+	}
+	return false
+}
+
+func setRecreatedCondition(customResource *brokerv1beta1.ActiveMQArtemis, status metav1.ConditionStatus, reason string, message string) {
+	meta.SetStatusCondition(&customResource.Status.Conditions, metav1.Condition{
+		Type:               brokerv1beta1.RecreatedConditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: customResource.Generation,
+	})
+}
+
+// processImmutableFields walks a change to fields the api server won't update through
+// delete, snapshot, replace claims and create, one step per reconcile with the step in
+// the Recreated condition. It returns false while the desired statefulset must not be
+// applied, process resources then deletes the deployed one as it is no longer requested.
+func (reconciler *ActiveMQArtemisReconcilerImpl) processImmutableFields(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, client rtclient.Client, desired *appsv1.StatefulSet) bool {
+
+	inProgress := isRecreateInProgress(customResource)
+
+	deployedObject := reconciler.getFromDeployed(reflect.TypeOf(appsv1.StatefulSet{}), desired.Name)
+	if deployedObject == nil {
+		if !inProgress {
+			return true
+		}
+		reason, message, err := replaceClaims(customResource, namer, client, desired)
+		if err != nil {
+			clog.Error(err, "failed to replace persistent volume claims", "statefulset", desired.Name)
+			setRecreatedCondition(customResource, metav1.ConditionFalse, brokerv1beta1.RecreatedConditionReplacingVolumesReason, err.Error())
+			return false
+		}
+		if reason != "" {
+			setRecreatedCondition(customResource, metav1.ConditionFalse, reason, message)
+			return false
+		}
+		setRecreatedCondition(customResource, metav1.ConditionFalse, brokerv1beta1.RecreatedConditionReplacingVolumesReason, "Creating the StatefulSet")
+		return true
+	}
+
+	deployed := deployedObject.(*appsv1.StatefulSet)
+	if inProgress && deployed.DeletionTimestamp != nil {
+		return false
+	}
+
+	changes := immutableFieldChanges(deployed, desired)
+	if len(changes) == 0 {
+		condition := meta.FindStatusCondition(customResource.Status.Conditions, brokerv1beta1.RecreatedConditionType)
+		if inProgress {
+			if pending, err := cleanUpRecreateSnapshots(customResource, namer, client); err != nil || pending != "" {
+				if err != nil {
+					clog.Error(err, "failed to clean up volume snapshots", "statefulset", desired.Name)
+				}
+				setRecreatedCondition(customResource, metav1.ConditionFalse, brokerv1beta1.RecreatedConditionReplacingVolumesReason, fmt.Sprintf("Waiting for restored claim %v to be bound", pending))
+				return true
+			}
+			setRecreatedCondition(customResource, metav1.ConditionTrue, brokerv1beta1.RecreatedConditionSuccessReason, "")
+		} else if condition != nil && condition.Status == metav1.ConditionFalse {
+			// the change was reverted
+			meta.RemoveStatusCondition(&customResource.Status.Conditions, brokerv1beta1.RecreatedConditionType)
+		}
+		return true
+	}
+
+	hold := func(reason string, message string) bool {
+		holdImmutableFields(deployed, desired)
+		setRecreatedCondition(customResource, metav1.ConditionFalse, reason, message)
+		return true
+	}
+
+	if getImmutableFieldsPolicy(customResource) != ImmutableFieldsPolicyRecreate && isHAEnabled(customResource) && deployed.Spec.PodManagementPolicy != appsv1.ParallelPodManagement {
+		// the backups never become ready, the pods after the first backup of an OrderedReady StatefulSet
+		// would never start. Nothing of ha applies until the StatefulSet is recreated
+		*desired = *deployed.DeepCopy()
+		setRecreatedCondition(customResource, metav1.ConditionFalse, brokerv1beta1.RecreatedConditionBlockedReason, fmt.Sprintf("The StatefulSet is held as deployed, .Spec.HA needs podManagementPolicy %v which can't be changed in place. Set deploymentPlan.immutableFieldsPolicy to %v to recreate it", appsv1.ParallelPodManagement, ImmutableFieldsPolicyRecreate))
+		return true
+	}
+
+	if getImmutableFieldsPolicy(customResource) != ImmutableFieldsPolicyRecreate {
+		return hold(brokerv1beta1.RecreatedConditionBlockedReason, fmt.Sprintf("The StatefulSet is held as deployed, %v can't be changed in place. Set deploymentPlan.immutableFieldsPolicy to %v to recreate it", strings.Join(changes, ", "), ImmutableFieldsPolicyRecreate))
+	}
+
+	if customResource.Spec.DeploymentPlan.Storage.SnapshotClassName == "" {
+		claims, err := mismatchedClaims(customResource, namer, client, desired)
+		if err != nil {
+			return hold(brokerv1beta1.RecreatedConditionSnapshotRequiredReason, err.Error())
+		}
+		if len(claims) > 0 {
+			return hold(brokerv1beta1.RecreatedConditionSnapshotRequiredReason, fmt.Sprintf("The StatefulSet is held as deployed, replacing the claims %v needs deploymentPlan.storage.snapshotClassName to keep their data", claimNames(claims)))
+		}
+	}
+
+	setRecreatedCondition(customResource, metav1.ConditionFalse, brokerv1beta1.RecreatedConditionDeletingReason, fmt.Sprintf("Deleting the StatefulSet to change %v", strings.Join(changes, ", ")))
+	return false
+}
+
+// holdImmutableFields keeps the fields the api server refuses to update as deployed, the rest of
+// the desired spec still applies. The pods keep the labels the deployed selector matches
+func holdImmutableFields(deployed *appsv1.StatefulSet, desired *appsv1.StatefulSet) {
+	desired.Spec.Selector = deployed.Spec.Selector.DeepCopy()
+	desired.Spec.ServiceName = deployed.Spec.ServiceName
+	if deployed.Spec.PodManagementPolicy != "" {
+		desired.Spec.PodManagementPolicy = deployed.Spec.PodManagementPolicy
+	}
+	desired.Spec.VolumeClaimTemplates = nil
+	for _, template := range deployed.Spec.VolumeClaimTemplates {
+		desired.Spec.VolumeClaimTemplates = append(desired.Spec.VolumeClaimTemplates, *template.DeepCopy())
+	}
+	if desired.Spec.Selector != nil && len(desired.Spec.Selector.MatchLabels) > 0 {
+		if desired.Spec.Template.Labels == nil {
+			desired.Spec.Template.Labels = map[string]string{}
+		}
+		for key, value := range desired.Spec.Selector.MatchLabels {
+			desired.Spec.Template.Labels[key] = value
+		}
+	}
+}
+
+// immutableFieldChanges names the fields of the statefulset spec the api server refuses to update
+func immutableFieldChanges(deployed *appsv1.StatefulSet, desired *appsv1.StatefulSet) []string {
+	changes := []string{}
+	if !equality.Semantic.DeepEqual(deployed.Spec.Selector, desired.Spec.Selector) {
+		changes = append(changes, "selector")
+	}
+	if deployed.Spec.ServiceName != desired.Spec.ServiceName {
+		changes = append(changes, "serviceName")
+	}
+	if deployed.Spec.PodManagementPolicy != "" && desired.Spec.PodManagementPolicy != "" && deployed.Spec.PodManagementPolicy != desired.Spec.PodManagementPolicy {
+		changes = append(changes, "podManagementPolicy")
+	}
+	if len(deployed.Spec.VolumeClaimTemplates) != len(desired.Spec.VolumeClaimTemplates) {
+		changes = append(changes, "volumeClaimTemplates")
+	} else {
+		for i := range desired.Spec.VolumeClaimTemplates {
+			if deployed.Spec.VolumeClaimTemplates[i].Name != desired.Spec.VolumeClaimTemplates[i].Name ||
+				!claimSpecMatches(deployed.Spec.VolumeClaimTemplates[i].Spec, desired.Spec.VolumeClaimTemplates[i].Spec) {
+				changes = append(changes, "volumeClaimTemplates")
+				break
+			}
+		}
+	}
+	return changes
+}
+
+// claimSpecMatches ignores the storage class when the template leaves it to the cluster default
+func claimSpecMatches(actual corev1.PersistentVolumeClaimSpec, template corev1.PersistentVolumeClaimSpec) bool {
+	if template.StorageClassName != nil && (actual.StorageClassName == nil || *actual.StorageClassName != *template.StorageClassName) {
+		return false
+	}
+	if !equality.Semantic.DeepEqual(actual.AccessModes, template.AccessModes) {
+		return false
+	}
+	actualSize := actual.Resources.Requests[corev1.ResourceStorage]
+	templateSize := template.Resources.Requests[corev1.ResourceStorage]
+	return actualSize.Cmp(templateSize) == 0
+}
+
+// mismatchedClaims lists the claims of the statefulset pods that don't match the new templates
+func mismatchedClaims(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, client rtclient.Client, desired *appsv1.StatefulSet) ([]corev1.PersistentVolumeClaim, error) {
+	claims := &corev1.PersistentVolumeClaimList{}
+	if err := client.List(context.TODO(), claims, rtclient.InNamespace(customResource.Namespace), rtclient.MatchingLabels(namer.LabelBuilder.Labels())); err != nil {
+		return nil, err
+	}
+	mismatched := []corev1.PersistentVolumeClaim{}
+	for _, claim := range claims.Items {
+		for _, template := range desired.Spec.VolumeClaimTemplates {
+			if strings.HasPrefix(claim.Name, template.Name+"-"+desired.Name+"-") && !claimSpecMatches(claim.Spec, template.Spec) {
+				mismatched = append(mismatched, claim)
+			}
+		}
+	}
+	return mismatched, nil
+}
+
+func claimNames(claims []corev1.PersistentVolumeClaim) string {
+	names := []string{}
+	for _, claim := range claims {
+		names = append(names, claim.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// replaceClaims runs once the old statefulset is gone. Mismatched claims are snapshot and
+// deleted, then recreated from their snapshot with the new template before the statefulset
+// is created again and adopts them by name. It returns the reason to wait for, if any.
+func replaceClaims(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, client rtclient.Client, desired *appsv1.StatefulSet) (string, string, error) {
+
+	pods := &corev1.PodList{}
+	if err := client.List(context.TODO(), pods, rtclient.InNamespace(customResource.Namespace), rtclient.MatchingLabels(namer.LabelBuilder.Labels())); err != nil {
+		return "", "", err
+	}
+	if len(pods.Items) > 0 {
+		// snapshots of a journal in use are not consistent
+		return brokerv1beta1.RecreatedConditionDeletingReason, "Waiting for the broker pods to terminate", nil
+	}
+
+	claims, err := mismatchedClaims(customResource, namer, client, desired)
+	if err != nil {
+		return "", "", err
+	}
+	for _, claim := range claims {
+		if claim.DeletionTimestamp != nil {
+			return brokerv1beta1.RecreatedConditionReplacingVolumesReason, fmt.Sprintf("Waiting for claim %v to be deleted", claim.Name), nil
+		}
+		if customResource.Spec.DeploymentPlan.Storage.SnapshotClassName == "" {
+			return "", "", fmt.Errorf("not deleting claim %v without deploymentPlan.storage.snapshotClassName", claim.Name)
+		}
+		ready, err := ensureRecreateSnapshot(customResource, namer, client, claim)
+		if err != nil {
+			return "", "", err
+		}
+		if !ready {
+			return brokerv1beta1.RecreatedConditionSnapshottingReason, fmt.Sprintf("Waiting for the snapshot of claim %v", claim.Name), nil
+		}
+	}
+	for i := range claims {
+		if err := client.Delete(context.TODO(), &claims[i]); err != nil && !k8serrors.IsNotFound(err) {
+			return "", "", err
+		}
+	}
+	if len(claims) > 0 {
+		return brokerv1beta1.RecreatedConditionReplacingVolumesReason, "Waiting for the claims to be deleted", nil
+	}
+
+	snapshots, err := listRecreateSnapshots(customResource, namer, client)
+	if err != nil {
+		return "", "", err
+	}
+	for _, snapshot := range snapshots {
+		claimName := strings.TrimSuffix(snapshot.GetName(), recreateSnapshotSuffix)
+		err := client.Get(context.TODO(), types.NamespacedName{Name: claimName, Namespace: customResource.Namespace}, &corev1.PersistentVolumeClaim{})
+		if err == nil {
+			continue
+		}
+		if !k8serrors.IsNotFound(err) {
+			return "", "", err
+		}
+		for _, template := range desired.Spec.VolumeClaimTemplates {
+			if strings.HasPrefix(claimName, template.Name+"-"+desired.Name+"-") {
+				if err := client.Create(context.TODO(), newRestoredClaim(customResource, namer, template, claimName, snapshot.GetName())); err != nil {
+					return "", "", err
+				}
+			}
+		}
+	}
+	return "", "", nil
+}
+
+func newRestoredClaim(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, template corev1.PersistentVolumeClaim, claimName string, snapshotName string) *corev1.PersistentVolumeClaim {
+	apiGroup := volumeSnapshotGVK.Group
+	claim := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      claimName,
+			Namespace: customResource.Namespace,
+			Labels:    namer.LabelBuilder.Labels(),
+		},
+		Spec: *template.Spec.DeepCopy(),
+	}
+	claim.Spec.DataSource = &corev1.TypedLocalObjectReference{
+		APIGroup: &apiGroup,
+		Kind:     volumeSnapshotGVK.Kind,
+		Name:     snapshotName,
+	}
+	return claim
+}
+
+// the snapshots are not owned by the cr, deleting the cr half way must not take the data with it
+func ensureRecreateSnapshot(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, client rtclient.Client, claim corev1.PersistentVolumeClaim) (bool, error) {
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(volumeSnapshotGVK)
+	err := client.Get(context.TODO(), types.NamespacedName{Name: claim.Name + recreateSnapshotSuffix, Namespace: customResource.Namespace}, snapshot)
+	if k8serrors.IsNotFound(err) {
+		snapshot.SetName(claim.Name + recreateSnapshotSuffix)
+		snapshot.SetNamespace(customResource.Namespace)
+		snapshot.SetLabels(namer.LabelBuilder.Labels())
+		snapshot.Object["spec"] = map[string]interface{}{
+			"volumeSnapshotClassName": customResource.Spec.DeploymentPlan.Storage.SnapshotClassName,
+			"source": map[string]interface{}{
+				"persistentVolumeClaimName": claim.Name,
+			},
+		}
+		return false, client.Create(context.TODO(), snapshot)
+	}
+	if err != nil {
+		return false, err
+	}
+	ready, _, _ := unstructured.NestedBool(snapshot.Object, "status", "readyToUse")
+	return ready, nil
+}
+
+func listRecreateSnapshots(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, client rtclient.Client) ([]unstructured.Unstructured, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(volumeSnapshotGVK.GroupVersion().WithKind(volumeSnapshotGVK.Kind + "List"))
+	if err := client.List(context.TODO(), list, rtclient.InNamespace(customResource.Namespace), rtclient.MatchingLabels(namer.LabelBuilder.Labels())); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	snapshots := []unstructured.Unstructured{}
+	for _, snapshot := range list.Items {
+		if strings.HasSuffix(snapshot.GetName(), recreateSnapshotSuffix) {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	return snapshots, nil
+}
+
+// a snapshot is kept until the claim restored from it is bound, provisioning may wait for the first pod
+func cleanUpRecreateSnapshots(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, client rtclient.Client) (string, error) {
+	snapshots, err := listRecreateSnapshots(customResource, namer, client)
+	if err != nil {
+		return "", err
+	}
+	for i := range snapshots {
+		claimName := strings.TrimSuffix(snapshots[i].GetName(), recreateSnapshotSuffix)
+		claim := &corev1.PersistentVolumeClaim{}
+		if err := client.Get(context.TODO(), types.NamespacedName{Name: claimName, Namespace: customResource.Namespace}, claim); err != nil {
+			return claimName, err
+		}
+		if claim.Status.Phase != corev1.ClaimBound {
+			return claimName, nil
+		}
+		if err := client.Delete(context.TODO(), &snapshots[i]); err != nil && !k8serrors.IsNotFound(err) {
+			return claimName, err
+		}
+	}
+	return "", nil
+}
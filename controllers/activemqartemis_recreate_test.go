@@ -0,0 +1,135 @@
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/common"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestProcessImmutableFields(t *testing.T) {
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: "test"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			DeploymentPlan: brokerv1beta1.DeploymentPlanType{
+				PersistenceEnabled: true,
+				Storage:            brokerv1beta1.StorageType{StorageClassName: "slow"},
+			},
+		},
+	}
+	namer := MakeNamers(cr)
+
+	deployed := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: namer.SsNameBuilder.Name(), Namespace: "test"}}
+	deployed.Spec.VolumeClaimTemplates = *NewPersistentVolumeClaimArrayForCR(cr, *namer, 1)
+	claim := deployed.Spec.VolumeClaimTemplates[0].DeepCopy()
+	claim.Name = "broker-" + deployed.Name + "-0"
+	claim.Namespace = "test"
+	fakeClient := fake.NewClientBuilder().WithObjects(claim).Build()
+
+	cr.Spec.DeploymentPlan.Storage.StorageClassName = "fast"
+	newDesired := func() *appsv1.StatefulSet {
+		desired := deployed.DeepCopy()
+		desired.Spec.VolumeClaimTemplates = *NewPersistentVolumeClaimArrayForCR(cr, *namer, 1)
+		return desired
+	}
+	reconcilerWith := func(statefulSets ...client.Object) *ActiveMQArtemisReconcilerImpl {
+		return &ActiveMQArtemisReconcilerImpl{deployed: map[reflect.Type][]client.Object{reflect.TypeOf(appsv1.StatefulSet{}): statefulSets}}
+	}
+	reason := func() string {
+		return meta.FindStatusCondition(cr.Status.Conditions, brokerv1beta1.RecreatedConditionType).Reason
+	}
+
+	// held as deployed by default, the mutable fields still apply
+	desired := newDesired()
+	desired.Spec.Replicas = common.Int32ToPtr(3)
+	desired.Spec.Template.Spec.Containers = []v1.Container{{Name: "broker", Image: "new-image"}}
+	assert.True(t, reconcilerWith(deployed).processImmutableFields(cr, *namer, fakeClient, desired))
+	assert.Equal(t, "slow", *desired.Spec.VolumeClaimTemplates[0].Spec.StorageClassName)
+	assert.Equal(t, int32(3), *desired.Spec.Replicas)
+	assert.Equal(t, "new-image", desired.Spec.Template.Spec.Containers[0].Image)
+	assert.Equal(t, brokerv1beta1.RecreatedConditionBlockedReason, reason())
+
+	// the data can't be kept without a snapshot class
+	cr.Spec.DeploymentPlan.ImmutableFieldsPolicy = ImmutableFieldsPolicyRecreate
+	assert.True(t, reconcilerWith(deployed).processImmutableFields(cr, *namer, fakeClient, newDesired()))
+	assert.Equal(t, brokerv1beta1.RecreatedConditionSnapshotRequiredReason, reason())
+
+	cr.Spec.DeploymentPlan.Storage.SnapshotClassName = "csi-snapshots"
+	assert.False(t, reconcilerWith(deployed).processImmutableFields(cr, *namer, fakeClient, newDesired()))
+	assert.Equal(t, brokerv1beta1.RecreatedConditionDeletingReason, reason())
+
+	// once the statefulset is gone the claim is snapshot
+	assert.False(t, reconcilerWith().processImmutableFields(cr, *namer, fakeClient, newDesired()))
+	assert.Equal(t, brokerv1beta1.RecreatedConditionSnapshottingReason, reason())
+
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(volumeSnapshotGVK)
+	assert.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Name: claim.Name + "-recreate", Namespace: "test"}, snapshot))
+	snapshotClass, _, _ := unstructured.NestedString(snapshot.Object, "spec", "volumeSnapshotClassName")
+	assert.Equal(t, "csi-snapshots", snapshotClass)
+	assert.NoError(t, unstructured.SetNestedField(snapshot.Object, true, "status", "readyToUse"))
+	assert.NoError(t, fakeClient.Update(context.TODO(), snapshot))
+
+	// then replaced and restored from the snapshot before the statefulset is created again
+	assert.False(t, reconcilerWith().processImmutableFields(cr, *namer, fakeClient, newDesired()))
+	assert.Equal(t, brokerv1beta1.RecreatedConditionReplacingVolumesReason, reason())
+	assert.True(t, reconcilerWith().processImmutableFields(cr, *namer, fakeClient, newDesired()))
+
+	restored := &v1.PersistentVolumeClaim{}
+	assert.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Name: claim.Name, Namespace: "test"}, restored))
+	assert.Equal(t, "fast", *restored.Spec.StorageClassName)
+	assert.Equal(t, claim.Name+"-recreate", restored.Spec.DataSource.Name)
+
+	// the snapshot goes once the restored claim is bound
+	assert.True(t, reconcilerWith(newDesired()).processImmutableFields(cr, *namer, fakeClient, newDesired()))
+	assert.Equal(t, brokerv1beta1.RecreatedConditionReplacingVolumesReason, reason())
+	restored.Status.Phase = v1.ClaimBound
+	assert.NoError(t, fakeClient.Status().Update(context.TODO(), restored))
+	assert.True(t, reconcilerWith(newDesired()).processImmutableFields(cr, *namer, fakeClient, newDesired()))
+	assert.True(t, meta.IsStatusConditionTrue(cr.Status.Conditions, brokerv1beta1.RecreatedConditionType))
+	assert.Error(t, fakeClient.Get(context.TODO(), types.NamespacedName{Name: claim.Name + "-recreate", Namespace: "test"}, snapshot))
+}
+
+func TestProcessImmutableFieldsHoldsHA(t *testing.T) {
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: "test"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			DeploymentPlan: brokerv1beta1.DeploymentPlanType{Size: common.Int32ToPtr(4), PersistenceEnabled: true},
+			HA:             &brokerv1beta1.HAType{Enabled: true},
+		},
+	}
+	namer := MakeNamers(cr)
+	deployed := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: namer.SsNameBuilder.Name(), Namespace: "test"}}
+	deployed.Spec.PodManagementPolicy = appsv1.OrderedReadyPodManagement
+	deployed.Spec.Template.Spec.Containers = []v1.Container{{Name: "broker", Image: "image"}}
+	reconciler := &ActiveMQArtemisReconcilerImpl{deployed: map[reflect.Type][]client.Object{reflect.TypeOf(appsv1.StatefulSet{}): {deployed}}}
+
+	// nothing of ha applies to an OrderedReady StatefulSet, the first backup would hold back the other pods
+	desired := deployed.DeepCopy()
+	desired.Spec.Template.Spec.Containers[0].Image = "new-image"
+	configureHAStatefulSet(cr, desired)
+	assert.True(t, reconciler.processImmutableFields(cr, *namer, fake.NewClientBuilder().Build(), desired))
+	assert.Equal(t, deployed.Spec, desired.Spec)
+	condition := meta.FindStatusCondition(cr.Status.Conditions, brokerv1beta1.RecreatedConditionType)
+	assert.Equal(t, brokerv1beta1.RecreatedConditionBlockedReason, condition.Reason)
+	assert.Contains(t, condition.Message, ".Spec.HA needs podManagementPolicy Parallel")
+	assert.Contains(t, condition.Message, "immutableFieldsPolicy to Recreate")
+
+	cr.Spec.DeploymentPlan.ImmutableFieldsPolicy = ImmutableFieldsPolicyRecreate
+	desired = deployed.DeepCopy()
+	configureHAStatefulSet(cr, desired)
+	assert.False(t, reconciler.processImmutableFields(cr, *namer, fake.NewClientBuilder().Build(), desired))
+	assert.Equal(t, brokerv1beta1.RecreatedConditionDeletingReason, meta.FindStatusCondition(cr.Status.Conditions, brokerv1beta1.RecreatedConditionType).Reason)
+}
@@ -0,0 +1,171 @@
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// a released resync that never reached Reconcile, for example because the work queue merged it,
+// stops holding the next ones back after this long
+const resyncReleaseTimeout = time.Minute
+
+// resyncLane keeps periodic resyncs and status refreshes out of the work queue of the controller
+// until no change made by a user is waiting. It releases them one at a time, at most one per
+// interval, so a user edit is never queued behind more than one of them
+type resyncLane struct {
+	mutex    sync.Mutex
+	interval time.Duration
+	due      map[types.NamespacedName]time.Time
+	pending  map[types.NamespacedName]bool
+	released map[types.NamespacedName]time.Time
+	release  func(types.NamespacedName)
+	now      func() time.Time
+}
+
+func newResyncLane(interval time.Duration, release func(types.NamespacedName)) *resyncLane {
+	return &resyncLane{
+		interval: interval,
+		due:      map[types.NamespacedName]time.Time{},
+		pending:  map[types.NamespacedName]bool{},
+		released: map[types.NamespacedName]time.Time{},
+		release:  release,
+		now:      time.Now,
+	}
+}
+
+// schedule keeps the earliest time when a resync of the broker is already due
+func (lane *resyncLane) schedule(name types.NamespacedName, after time.Duration) {
+	lane.mutex.Lock()
+	defer lane.mutex.Unlock()
+
+	at := lane.now().Add(after)
+	if current, found := lane.due[name]; !found || at.Before(current) {
+		lane.due[name] = at
+	}
+}
+
+// userChange is called for changes that go straight to the work queue, the reconcile they cause
+// makes a resync of the same broker redundant
+func (lane *resyncLane) userChange(name types.NamespacedName) {
+	lane.mutex.Lock()
+	defer lane.mutex.Unlock()
+
+	lane.pending[name] = true
+	delete(lane.due, name)
+}
+
+func (lane *resyncLane) forget(name types.NamespacedName) {
+	lane.mutex.Lock()
+	defer lane.mutex.Unlock()
+
+	delete(lane.due, name)
+	delete(lane.pending, name)
+}
+
+func (lane *resyncLane) reconciling(name types.NamespacedName) {
+	lane.mutex.Lock()
+	defer lane.mutex.Unlock()
+
+	delete(lane.pending, name)
+	delete(lane.released, name)
+}
+
+// next picks the resync that has been due the longest, if nothing is ahead of it
+func (lane *resyncLane) next() (types.NamespacedName, bool) {
+	lane.mutex.Lock()
+	defer lane.mutex.Unlock()
+
+	now := lane.now()
+	for name, at := range lane.released {
+		if now.Sub(at) > resyncReleaseTimeout {
+			delete(lane.released, name)
+		}
+	}
+	if len(lane.pending) > 0 || len(lane.released) > 0 {
+		return types.NamespacedName{}, false
+	}
+
+	var next types.NamespacedName
+	var nextAt time.Time
+	for name, at := range lane.due {
+		if !at.After(now) && (nextAt.IsZero() || at.Before(nextAt)) {
+			next, nextAt = name, at
+		}
+	}
+	if nextAt.IsZero() {
+		return next, false
+	}
+	delete(lane.due, next)
+	lane.released[next] = now
+	return next, true
+}
+
+// Start runs the lane as a runnable of the manager
+func (lane *resyncLane) Start(ctx context.Context) error {
+	ticker := time.NewTicker(lane.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if name, found := lane.next(); found {
+				lane.release(name)
+			}
+		}
+	}
+}
+
+// predicates sends the updates that only touch the status of a broker, its own status updates
+// included, to the lane and lets everything else through as a user change
+func (lane *resyncLane) predicates() predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			lane.userChange(types.NamespacedName{Name: e.Object.GetName(), Namespace: e.Object.GetNamespace()})
+			return true
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			name := types.NamespacedName{Name: e.ObjectNew.GetName(), Namespace: e.ObjectNew.GetNamespace()}
+			if isStatusOnlyUpdate(e.ObjectOld, e.ObjectNew) {
+				lane.schedule(name, 0)
+				return false
+			}
+			lane.userChange(name)
+			return true
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			lane.forget(types.NamespacedName{Name: e.Object.GetName(), Namespace: e.Object.GetNamespace()})
+			return true
+		},
+	}
+}
+
+func isStatusOnlyUpdate(old metav1.Object, new metav1.Object) bool {
+	return old.GetGeneration() == new.GetGeneration() &&
+		old.GetDeletionTimestamp().Equal(new.GetDeletionTimestamp()) &&
+		reflect.DeepEqual(old.GetLabels(), new.GetLabels()) &&
+		reflect.DeepEqual(old.GetAnnotations(), new.GetAnnotations()) &&
+		reflect.DeepEqual(old.GetFinalizers(), new.GetFinalizers())
+}
+
+// requeueForResync hands a periodic resync to the lane, without a lane it goes back to the work queue
+func (r *ActiveMQArtemisReconciler) requeueForResync(request ctrl.Request, after time.Duration) ctrl.Result {
+	if r.resync == nil {
+		return ctrl.Result{RequeueAfter: after}
+	}
+	r.resync.schedule(request.NamespacedName, after)
+	return ctrl.Result{}
+}
+
+func (r *ActiveMQArtemisReconciler) releaseResync(name types.NamespacedName) {
+	r.events <- event.GenericEvent{Object: &brokerv1beta1.ActiveMQArtemis{ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace}}}
+}
@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestResyncLane(t *testing.T) {
+	now := time.Now()
+	released := []types.NamespacedName{}
+	lane := newResyncLane(time.Millisecond, func(name types.NamespacedName) { released = append(released, name) })
+	lane.now = func() time.Time { return now }
+
+	first := types.NamespacedName{Name: "first", Namespace: "test"}
+	second := types.NamespacedName{Name: "second", Namespace: "test"}
+	edited := types.NamespacedName{Name: "edited", Namespace: "test"}
+
+	lane.schedule(second, 2*time.Second)
+	lane.schedule(first, time.Second)
+	_, found := lane.next()
+	assert.False(t, found, "nothing is due yet")
+
+	now = now.Add(5 * time.Second)
+	// a user edit goes first
+	lane.userChange(edited)
+	_, found = lane.next()
+	assert.False(t, found)
+	lane.reconciling(edited)
+
+	name, found := lane.next()
+	assert.True(t, found)
+	assert.Equal(t, first, name)
+	// one released resync at a time
+	_, found = lane.next()
+	assert.False(t, found)
+	lane.reconciling(first)
+
+	// a user edit replaces the resync of the same broker
+	lane.userChange(second)
+	lane.reconciling(second)
+	_, found = lane.next()
+	assert.False(t, found)
+
+	// a released resync that never shows up doesn't hold the lane forever
+	lane.schedule(first, 0)
+	lane.schedule(second, 0)
+	name, _ = lane.next()
+	_, found = lane.next()
+	assert.False(t, found)
+	now = now.Add(2 * resyncReleaseTimeout)
+	other, found := lane.next()
+	assert.True(t, found)
+	assert.NotEqual(t, name, other)
+
+	cr := &brokerv1beta1.ActiveMQArtemis{ObjectMeta: metav1.ObjectMeta{Name: "edited", Namespace: "test", Generation: 1}}
+	status := cr.DeepCopy()
+	status.Status.PodStatus.Ready = []string{"edited-ss-0"}
+	predicates := lane.predicates()
+	assert.False(t, predicates.Update(event.UpdateEvent{ObjectOld: cr, ObjectNew: status}))
+	assert.Contains(t, lane.due, edited)
+
+	spec := cr.DeepCopy()
+	spec.Generation = 2
+	assert.True(t, predicates.Update(event.UpdateEvent{ObjectOld: cr, ObjectNew: spec}))
+	assert.NotContains(t, lane.due, edited)
+	assert.True(t, lane.pending[edited])
+
+	r := &ActiveMQArtemisReconciler{}
+	assert.Equal(t, time.Second, r.requeueForResync(ctrl.Request{NamespacedName: edited}, time.Second).RequeueAfter)
+	r.resync = lane
+	result := r.requeueForResync(ctrl.Request{NamespacedName: edited}, time.Second)
+	assert.True(t, result.IsZero())
+	assert.Contains(t, lane.due, edited)
+}
@@ -0,0 +1,253 @@
+package controllers
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Azure/go-amqp"
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	securityConfigChecksumEnvVarName    = "SECURITY_CONFIG_CHECKSUM"
+	defaultSecurityCanaryTimeoutSeconds = 300
+	securityCanaryConnectTimeout        = 5 * time.Second
+)
+
+// the checksum of the security handler commands tells a change of the security configuration apart
+// from the other changes of the pod template
+func securityConfigChecksum(handlerCmds []string) string {
+	return hex.EncodeToString(alder32Of(handlerCmds))
+}
+
+func initContainerEnvValue(template *corev1.PodTemplateSpec, name string) string {
+	for _, container := range template.Spec.InitContainers {
+		for _, envVar := range container.Env {
+			if envVar.Name == name {
+				return envVar.Value
+			}
+		}
+	}
+	return ""
+}
+
+func getSecurityCanary(customResource *brokerv1beta1.ActiveMQArtemis) *brokerv1beta1.SecurityCanaryType {
+	if securityCR := getApplicableSecurityCR(customResource); securityCR != nil && securityCR.Spec.Canary != nil && securityCR.Spec.Canary.Enabled {
+		return securityCR.Spec.Canary
+	}
+	return nil
+}
+
+// securityCanaryLoginCheck logs in to a broker pod, an error means the pod doesn't let the login in
+type securityCanaryLoginCheck func(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, pod string, canary *brokerv1beta1.SecurityCanaryType) error
+
+// applySecurityCanary holds a change of the security configuration back from all but the broker pod
+// with the highest ordinal through the partition of the StatefulSet, until a login to that pod
+// succeeds. A rollout that doesn't pass in time is reported as failed and stays held, a fixed
+// configuration starts a new one
+func (reconciler *ActiveMQArtemisReconcilerImpl) applySecurityCanary(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, desired *appsv1.StatefulSet, loginCheck securityCanaryLoginCheck, now time.Time) {
+	// the desired StatefulSet starts out as a copy of the deployed one, a held rollout is released by default
+	if desired.Spec.UpdateStrategy.RollingUpdate != nil {
+		desired.Spec.UpdateStrategy.RollingUpdate.Partition = nil
+	}
+
+	canary := getSecurityCanary(customResource)
+	deployed, _ := reconciler.getFromDeployed(reflect.TypeOf(appsv1.StatefulSet{}), desired.Name).(*appsv1.StatefulSet)
+	if canary == nil || deployed == nil || desired.Spec.Replicas == nil || *desired.Spec.Replicas < 2 {
+		return
+	}
+	replicas := *desired.Spec.Replicas
+	checksum := initContainerEnvValue(&desired.Spec.Template, securityConfigChecksumEnvVarName)
+	deployedChecksum := initContainerEnvValue(&deployed.Spec.Template, securityConfigChecksumEnvVarName)
+	if checksum == "" || deployedChecksum == "" {
+		return
+	}
+
+	status := customResource.Status.SecurityCanary
+	if checksum != deployedChecksum {
+		status = &brokerv1beta1.SecurityCanaryStatus{
+			Phase:     brokerv1beta1.SecurityCanaryVerifying,
+			Pod:       desired.Name + "-" + strconv.Itoa(int(replicas-1)),
+			Checksum:  checksum,
+			StartedAt: &metav1.Time{Time: now},
+			Message:   "waiting for the canary pod to restart with the change",
+		}
+		customResource.Status.SecurityCanary = status
+		ctrl.Log.WithValues("ActiveMQArtemis Name", customResource.Name).Info("Rolling the security configuration out to the canary pod", "pod", status.Pod)
+		holdForSecurityCanary(desired, replicas-1)
+		return
+	}
+	if status == nil || status.Checksum != checksum || status.Phase == brokerv1beta1.SecurityCanaryPromoted {
+		return
+	}
+
+	if !securityCanaryPodUpdated(client, deployed, customResource.Namespace, status.Pod) {
+		status.Message = "waiting for the canary pod to restart with the change"
+	} else if err := loginCheck(customResource, client, status.Pod, canary); err != nil {
+		status.Message = "the login check failed, " + err.Error()
+	} else {
+		status.Phase = brokerv1beta1.SecurityCanaryPromoted
+		status.Message = ""
+		return
+	}
+	timeout := int32(defaultSecurityCanaryTimeoutSeconds)
+	if canary.TimeoutSeconds != nil {
+		timeout = *canary.TimeoutSeconds
+	}
+	if status.StartedAt != nil && now.Sub(status.StartedAt.Time) > time.Duration(timeout)*time.Second {
+		status.Phase = brokerv1beta1.SecurityCanaryFailed
+	}
+	holdForSecurityCanary(desired, replicas-1)
+}
+
+// startHASecurityCanary starts the verification of a change of the security configuration of ha
+// pairs, the operator restarts the pairs itself and applyHARollout holds the change back at the
+// highest pair
+func (reconciler *ActiveMQArtemisReconcilerImpl) startHASecurityCanary(customResource *brokerv1beta1.ActiveMQArtemis, desired *appsv1.StatefulSet, now time.Time) {
+	canary := getSecurityCanary(customResource)
+	deployed, _ := reconciler.getFromDeployed(reflect.TypeOf(appsv1.StatefulSet{}), desired.Name).(*appsv1.StatefulSet)
+	if canary == nil || deployed == nil || desired.Spec.Replicas == nil || *desired.Spec.Replicas < 4 {
+		return
+	}
+	checksum := initContainerEnvValue(&desired.Spec.Template, securityConfigChecksumEnvVarName)
+	deployedChecksum := initContainerEnvValue(&deployed.Spec.Template, securityConfigChecksumEnvVarName)
+	if checksum == "" || deployedChecksum == "" || checksum == deployedChecksum {
+		return
+	}
+	pair := *desired.Spec.Replicas/2 - 1
+	customResource.Status.SecurityCanary = &brokerv1beta1.SecurityCanaryStatus{
+		Phase:     brokerv1beta1.SecurityCanaryVerifying,
+		Pod:       desired.Name + "-" + strconv.Itoa(int(2*pair)),
+		Checksum:  checksum,
+		StartedAt: &metav1.Time{Time: now},
+		Message:   "waiting for the canary pair to restart with the change",
+	}
+	ctrl.Log.WithValues("ActiveMQArtemis Name", customResource.Name).Info("Rolling the security configuration out to the canary pair", "group", haGroupName(pair))
+}
+
+// haSecurityCanaryMessage checks the login to the live broker of the restarted canary pair, the
+// message holds the other pairs back
+func haSecurityCanaryMessage(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, deployed *appsv1.StatefulSet, live string, loginCheck securityCanaryLoginCheck, now time.Time) string {
+	canary := getSecurityCanary(customResource)
+	status := customResource.Status.SecurityCanary
+	if canary == nil || status == nil || status.Phase == brokerv1beta1.SecurityCanaryPromoted ||
+		status.Checksum != initContainerEnvValue(&deployed.Spec.Template, securityConfigChecksumEnvVarName) {
+		return ""
+	}
+	status.Pod = live
+	if err := loginCheck(customResource, client, live, canary); err != nil {
+		status.Message = "the login check failed, " + err.Error()
+	} else {
+		status.Phase = brokerv1beta1.SecurityCanaryPromoted
+		status.Message = ""
+		return ""
+	}
+	timeout := int32(defaultSecurityCanaryTimeoutSeconds)
+	if canary.TimeoutSeconds != nil {
+		timeout = *canary.TimeoutSeconds
+	}
+	if status.StartedAt != nil && now.Sub(status.StartedAt.Time) > time.Duration(timeout)*time.Second {
+		status.Phase = brokerv1beta1.SecurityCanaryFailed
+	}
+	return "the change is held back from the other pairs until the security canary " + live + " lets the login in"
+}
+
+func holdForSecurityCanary(desired *appsv1.StatefulSet, partition int32) {
+	desired.Spec.UpdateStrategy.Type = appsv1.RollingUpdateStatefulSetStrategyType
+	if desired.Spec.UpdateStrategy.RollingUpdate == nil {
+		desired.Spec.UpdateStrategy.RollingUpdate = &appsv1.RollingUpdateStatefulSetStrategy{}
+	}
+	desired.Spec.UpdateStrategy.RollingUpdate.Partition = &partition
+}
+
+// the canary pod is checked once it runs the revision of the deployed template and is ready
+func securityCanaryPodUpdated(client rtclient.Client, deployed *appsv1.StatefulSet, namespace string, name string) bool {
+	if deployed.Status.ObservedGeneration != deployed.Generation {
+		return false
+	}
+	pod := &corev1.Pod{}
+	if err := client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, pod); err != nil {
+		return false
+	}
+	if pod.Labels[appsv1.ControllerRevisionHashLabelKey] != deployed.Status.UpdateRevision {
+		return false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// securityCanaryAcceptor is the acceptor the login check connects to, one that offers AMQP with a
+// plain login and doesn't ask for a client certificate
+func securityCanaryAcceptor(customResource *brokerv1beta1.ActiveMQArtemis) *brokerv1beta1.AcceptorType {
+	for i := range customResource.Spec.Acceptors {
+		acceptor := applyAcceptorPreset(customResource.Spec.Acceptors[i])
+		protocols := strings.ToUpper(acceptor.Protocols)
+		if protocols != "" && protocols != "ALL" && !strings.Contains(protocols, "AMQP") {
+			continue
+		}
+		if acceptor.SSLEnabled && acceptor.NeedClientAuth {
+			continue
+		}
+		if acceptor.SASLMechanisms != "" && !strings.Contains(strings.ToUpper(acceptor.SASLMechanisms), "PLAIN") {
+			continue
+		}
+		return &acceptor
+	}
+	return nil
+}
+
+// checkSecurityCanaryLogin connects to an AMQP acceptor of the canary pod as the canary user, the
+// login goes through the broker domain like the one of the messaging clients
+func checkSecurityCanaryLogin(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, pod string, canary *brokerv1beta1.SecurityCanaryType) error {
+	acceptor := securityCanaryAcceptor(customResource)
+	if acceptor == nil {
+		return fmt.Errorf("no acceptor offers AMQP with a PLAIN login to check it with")
+	}
+	if canary.CredentialsSecret == nil {
+		return fmt.Errorf("the canary has no credentialsSecret to log in with")
+	}
+	secret := &corev1.Secret{}
+	if err := client.Get(context.TODO(), types.NamespacedName{Name: *canary.CredentialsSecret, Namespace: customResource.Namespace}, secret); err != nil {
+		return err
+	}
+	canaryPod := &corev1.Pod{}
+	if err := client.Get(context.TODO(), types.NamespacedName{Name: pod, Namespace: customResource.Namespace}, canaryPod); err != nil {
+		return err
+	}
+	if canaryPod.Status.PodIP == "" {
+		return fmt.Errorf("pod %v is not reachable", pod)
+	}
+
+	scheme := "amqp://"
+	options := []amqp.ConnOption{
+		amqp.ConnSASLPlain(secretValue(secret, "user"), secretValue(secret, "password")),
+		amqp.ConnConnectTimeout(securityCanaryConnectTimeout),
+	}
+	if acceptor.SSLEnabled {
+		// only the login matters here, trust is between the clients and the broker
+		scheme = "amqps://"
+		options = append(options, amqp.ConnTLSConfig(&tls.Config{InsecureSkipVerify: true}))
+	}
+	connection, err := amqp.Dial(scheme+net.JoinHostPort(canaryPod.Status.PodIP, strconv.Itoa(int(acceptor.Port))), options...)
+	if err != nil {
+		return err
+	}
+	return connection.Close()
+}
@@ -0,0 +1,131 @@
+package controllers
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+)
+
+func TestSecurityCanary(t *testing.T) {
+	cr := &brokerv1beta1.ActiveMQArtemis{ObjectMeta: metav1.ObjectMeta{Name: "canary", Namespace: "canary-ns"}}
+	securityName := types.NamespacedName{Name: "canary-sec", Namespace: "canary-ns"}
+	timeout := int32(60)
+	securityCR := &brokerv1beta1.ActiveMQArtemisSecurity{
+		ObjectMeta: metav1.ObjectMeta{Name: securityName.Name, Namespace: securityName.Namespace},
+		Spec: brokerv1beta1.ActiveMQArtemisSecuritySpec{
+			Canary: &brokerv1beta1.SecurityCanaryType{Enabled: true, TimeoutSeconds: &timeout},
+		},
+	}
+	assert.Error(t, securityCR.ValidateCreate())
+	canaryLogin := "canary-login"
+	securityCR.Spec.Canary.CredentialsSecret = &canaryLogin
+	assert.NoError(t, securityCR.ValidateCreate())
+	namespaceToConfigHandler[securityName] = &ActiveMQArtemisSecurityConfigHandler{SecurityCR: securityCR, NamespacedName: securityName}
+	defer delete(namespaceToConfigHandler, securityName)
+
+	replicas := int32(3)
+	statefulSet := func(checksum string) *appsv1.StatefulSet {
+		return &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "canary-ss", Namespace: cr.Namespace, Generation: 2},
+			Spec: appsv1.StatefulSetSpec{
+				Replicas: &replicas,
+				Template: v1.PodTemplateSpec{Spec: v1.PodSpec{InitContainers: []v1.Container{{
+					Name: "canary-container-init",
+					Env:  []v1.EnvVar{{Name: securityConfigChecksumEnvVarName, Value: checksum}},
+				}}}},
+			},
+			Status: appsv1.StatefulSetStatus{ObservedGeneration: 2, UpdateRevision: "canary-ss-2"},
+		}
+	}
+	canaryPod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "canary-ss-2", Namespace: cr.Namespace, Labels: map[string]string{appsv1.ControllerRevisionHashLabelKey: "canary-ss-2"}},
+		Status:     v1.PodStatus{Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}}},
+	}
+	testScheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(testScheme))
+	withoutPod := fake.NewClientBuilder().WithScheme(testScheme).Build()
+	withPod := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(canaryPod).Build()
+	loginErr := errors.New("401 Unauthorized")
+	checkLogin := func(customResource *brokerv1beta1.ActiveMQArtemis, c client.Client, pod string, canary *brokerv1beta1.SecurityCanaryType) error {
+		assert.Equal(t, "canary-ss-2", pod)
+		return loginErr
+	}
+	reconcile := func(deployed *appsv1.StatefulSet, desired *appsv1.StatefulSet, c client.Client, now time.Time) *appsv1.StatefulSet {
+		reconciler := &ActiveMQArtemisReconcilerImpl{deployed: map[reflect.Type][]client.Object{reflect.TypeOf(appsv1.StatefulSet{}): {deployed}}}
+		reconciler.applySecurityCanary(cr, c, desired, checkLogin, now)
+		return desired
+	}
+	partition := func(statefulSet *appsv1.StatefulSet) *int32 {
+		if statefulSet.Spec.UpdateStrategy.RollingUpdate == nil {
+			return nil
+		}
+		return statefulSet.Spec.UpdateStrategy.RollingUpdate.Partition
+	}
+	start := time.Now()
+
+	// a change of the security configuration only rolls out to the pod with the highest ordinal
+	desired := reconcile(statefulSet("previous"), statefulSet("changed"), withoutPod, start)
+	assert.Equal(t, int32(2), *partition(desired))
+	assert.Equal(t, brokerv1beta1.SecurityCanaryVerifying, cr.Status.SecurityCanary.Phase)
+	assert.Equal(t, "canary-ss-2", cr.Status.SecurityCanary.Pod)
+	assert.Equal(t, "changed", cr.Status.SecurityCanary.Checksum)
+
+	deployed := desired
+	desired = reconcile(deployed, deployed.DeepCopy(), withoutPod, start.Add(10*time.Second))
+	assert.Equal(t, int32(2), *partition(desired))
+	assert.Contains(t, cr.Status.SecurityCanary.Message, "waiting for the canary pod")
+
+	// the other pods stay on the previous configuration while the canary locks the login out
+	desired = reconcile(deployed, deployed.DeepCopy(), withPod, start.Add(20*time.Second))
+	assert.Equal(t, int32(2), *partition(desired))
+	assert.Equal(t, brokerv1beta1.SecurityCanaryVerifying, cr.Status.SecurityCanary.Phase)
+	assert.Contains(t, cr.Status.SecurityCanary.Message, "401 Unauthorized")
+	desired = reconcile(deployed, deployed.DeepCopy(), withPod, start.Add(61*time.Second))
+	assert.Equal(t, int32(2), *partition(desired))
+	assert.Equal(t, brokerv1beta1.SecurityCanaryFailed, cr.Status.SecurityCanary.Phase)
+
+	// once the login succeeds the partition is released
+	loginErr = nil
+	desired = reconcile(deployed, deployed.DeepCopy(), withPod, start.Add(70*time.Second))
+	assert.Nil(t, partition(desired))
+	assert.Equal(t, brokerv1beta1.SecurityCanaryPromoted, cr.Status.SecurityCanary.Phase)
+	assert.Empty(t, cr.Status.SecurityCanary.Message)
+
+	// a held StatefulSet is released when the canary is turned off
+	securityCR.Spec.Canary.Enabled = false
+	held := statefulSet("changed")
+	holdForSecurityCanary(held, 2)
+	assert.Nil(t, partition(reconcile(held, held.DeepCopy(), withPod, start)))
+
+	invalid := securityCR.DeepCopy()
+	invalid.Spec.Canary.TimeoutSeconds = &[]int32{0}[0]
+	assert.Error(t, invalid.ValidateCreate())
+
+	// the login goes through an acceptor that lets an amqp client in with a user and password
+	err := checkSecurityCanaryLogin(cr, withPod, "canary-ss-2", securityCR.Spec.Canary)
+	assert.ErrorContains(t, err, "no acceptor offers AMQP")
+	cr.Spec.Acceptors = []brokerv1beta1.AcceptorType{
+		{Name: "core", Protocols: "CORE", Port: 61617},
+		{Name: "mtls", Protocols: "AMQP", Port: 5671, SSLEnabled: true, NeedClientAuth: true},
+		{Name: "kerberos", Protocols: "AMQP", Port: 5673, SASLMechanisms: "GSSAPI"},
+		{Name: "amqp", Protocols: "AMQP,CORE", Port: 5672},
+	}
+	assert.Equal(t, "amqp", securityCanaryAcceptor(cr).Name)
+	cr.Spec.Acceptors[3].Protocols = ""
+	assert.Equal(t, "amqp", securityCanaryAcceptor(cr).Name)
+	cr.Spec.Acceptors = cr.Spec.Acceptors[:3]
+	assert.Nil(t, securityCanaryAcceptor(cr))
+}
@@ -0,0 +1,78 @@
+package controllers
+
+import (
+	"fmt"
+	"reflect"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/common"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// the security handlers are only registered once the security controller has seen their CR, which
+// can be after the broker is first reconciled on a fresh install or an operator restart
+func isSecurityGateOpen(customResource *brokerv1beta1.ActiveMQArtemis) bool {
+	return !customResource.Spec.WaitForSecurity || getApplicableSecurityCR(customResource) != nil
+}
+
+// holdStatefulSetForSecurity keeps a deployed StatefulSet as it is, rolling it now would restart the
+// brokers without their security configuration, and doesn't create one that isn't there yet
+func (reconciler *ActiveMQArtemisReconcilerImpl) holdStatefulSetForSecurity(desired *appsv1.StatefulSet) {
+	if deployed := reconciler.cloneOfDeployed(reflect.TypeOf(appsv1.StatefulSet{}), desired.Name); deployed != nil {
+		reconciler.trackDesired(deployed)
+	}
+}
+
+// recordSecurityRender keeps the generation of the security CR that went into the pod template on the
+// broker status. A generation that renders no commands holds the StatefulSet back, the pods keep the
+// previous generation rather than restart without any security configuration
+func recordSecurityRender(cr *brokerv1beta1.ActiveMQArtemis, handler common.ActiveMQArtemisConfigHandler, handlerCmds []string) {
+	securityHandler, ok := handler.(*ActiveMQArtemisSecurityConfigHandler)
+	if !ok || securityHandler == nil || securityHandler.SecurityCR == nil {
+		cr.Status.Security = nil
+		return
+	}
+	securityCR := securityHandler.SecurityCR
+	if len(handlerCmds) > 0 {
+		cr.Status.Security = &brokerv1beta1.SecurityRenderStatus{Name: securityCR.Name, Generation: securityCR.Generation}
+		return
+	}
+	status := &brokerv1beta1.SecurityRenderStatus{
+		Name:  securityCR.Name,
+		Error: fmt.Sprintf("generation %d of security cr %v could not be rendered, see the operator log", securityCR.Generation, securityCR.Name),
+	}
+	if previous := cr.Status.Security; previous != nil && previous.Name == securityCR.Name {
+		status.Generation = previous.Generation
+	}
+	cr.Status.Security = status
+}
+
+func securityRenderFailed(cr *brokerv1beta1.ActiveMQArtemis) bool {
+	return cr.Status.Security != nil && cr.Status.Security.Error != ""
+}
+
+func updateSecurityAppliedCondition(cr *brokerv1beta1.ActiveMQArtemis) {
+	if !cr.Spec.WaitForSecurity {
+		meta.RemoveStatusCondition(&cr.Status.Conditions, brokerv1beta1.SecurityAppliedConditionType)
+		return
+	}
+
+	if securityCR := getApplicableSecurityCR(cr); securityCR != nil {
+		meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+			Type:    brokerv1beta1.SecurityAppliedConditionType,
+			Status:  metav1.ConditionTrue,
+			Reason:  brokerv1beta1.SecurityAppliedConditionAppliedReason,
+			Message: fmt.Sprintf("security cr %v applied", securityCR.Name),
+		})
+		return
+	}
+
+	meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+		Type:    brokerv1beta1.SecurityAppliedConditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  brokerv1beta1.SecurityAppliedConditionWaitingReason,
+		Message: "no ActiveMQArtemisSecurity applies to the broker, the StatefulSet is held back until one does",
+	})
+}
@@ -0,0 +1,144 @@
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/common"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestSecurityBrokerStatus(t *testing.T) {
+	securityCR := &brokerv1beta1.ActiveMQArtemisSecurity{
+		ObjectMeta: metav1.ObjectMeta{Name: "applied-sec", Namespace: "applied-ns", Generation: 3},
+		Spec:       brokerv1beta1.ActiveMQArtemisSecuritySpec{ApplyToCrNames: []string{"rolling", "failed"}},
+	}
+	rolling := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "rolling", Namespace: "applied-ns"},
+		Status: brokerv1beta1.ActiveMQArtemisStatus{
+			Security: &brokerv1beta1.SecurityRenderStatus{Name: "applied-sec", Generation: 3},
+		},
+	}
+	failed := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "failed", Namespace: "applied-ns"},
+		Status: brokerv1beta1.ActiveMQArtemisStatus{
+			Security: &brokerv1beta1.SecurityRenderStatus{Name: "applied-sec", Generation: 2, Error: "generation 3 of security cr applied-sec could not be rendered, see the operator log"},
+		},
+	}
+	other := &brokerv1beta1.ActiveMQArtemis{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "applied-ns"}}
+	replicas := int32(2)
+	statefulSet := func(name string) *appsv1.StatefulSet {
+		return &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "applied-ns", Generation: 4},
+			Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+			Status:     appsv1.StatefulSetStatus{ObservedGeneration: 4, UpdateRevision: name + "-4"},
+		}
+	}
+	pod := func(name string, revision string) *v1.Pod {
+		return &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "applied-ns", Labels: map[string]string{appsv1.ControllerRevisionHashLabelKey: revision}}}
+	}
+	testScheme := newTestScheme(t)
+	fakeClient := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(securityCR, rolling, failed, other,
+		statefulSet("rolling-ss"), pod("rolling-ss-0", "rolling-ss-3"), pod("rolling-ss-1", "rolling-ss-4"),
+		statefulSet("failed-ss"), pod("failed-ss-0", "failed-ss-4"), pod("failed-ss-1", "failed-ss-4")).Build()
+	r := &ActiveMQArtemisSecurityReconciler{Client: fakeClient, Scheme: testScheme}
+
+	statuses := r.securityBrokerStatuses(context.TODO(), securityCR)
+	for i := range statuses {
+		assert.Equal(t, "#", statuses[i].EffectivePermissions[0].Match)
+		statuses[i].EffectivePermissions = nil
+	}
+	assert.Equal(t, []brokerv1beta1.SecurityBrokerStatus{
+		{
+			Name:        "failed",
+			Generation:  2,
+			PendingPods: []string{"failed-ss-0", "failed-ss-1"},
+			Error:       "generation 3 of security cr applied-sec could not be rendered, see the operator log",
+		},
+		{
+			Name:        "rolling",
+			Generation:  3,
+			UpdatedPods: []string{"rolling-ss-1"},
+			PendingPods: []string{"rolling-ss-0"},
+		},
+	}, statuses)
+
+	// a canary that locks the login out is reported on the broker it holds back
+	rolling.Status.SecurityCanary = &brokerv1beta1.SecurityCanaryStatus{Phase: brokerv1beta1.SecurityCanaryFailed, Pod: "rolling-ss-1", Message: "the login check failed, 401 Unauthorized"}
+	assert.Equal(t, "the canary rollout to pod rolling-ss-1 failed, the login check failed, 401 Unauthorized", r.securityBrokerStatus(context.TODO(), securityCR, rolling).Error)
+
+	// so is a missing secret of the security cr, the broker doesn't render it
+	meta.SetStatusCondition(&rolling.Status.Conditions, metav1.Condition{
+		Type:    brokerv1beta1.ValidConditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  brokerv1beta1.ValidConditionMissingResourcesReason,
+		Message: "security cr applied-sec login modules missing required secret ldap-bind",
+	})
+	assert.Equal(t, "security cr applied-sec login modules missing required secret ldap-bind", r.securityBrokerStatus(context.TODO(), securityCR, rolling).Error)
+
+	// a rendered generation is recorded on the broker, one that renders nothing holds the statefulset back
+	cr := &brokerv1beta1.ActiveMQArtemis{ObjectMeta: metav1.ObjectMeta{Name: "rolling", Namespace: "applied-ns"}}
+	configHandler := &ActiveMQArtemisSecurityConfigHandler{SecurityCR: securityCR, NamespacedName: types.NamespacedName{Name: "applied-sec", Namespace: "applied-ns"}}
+	recordSecurityRender(cr, configHandler, []string{"echo"})
+	assert.Equal(t, &brokerv1beta1.SecurityRenderStatus{Name: "applied-sec", Generation: 3}, cr.Status.Security)
+	assert.False(t, securityRenderFailed(cr))
+	securityCR.Generation = 4
+	recordSecurityRender(cr, configHandler, nil)
+	assert.Equal(t, int64(3), cr.Status.Security.Generation)
+	assert.Contains(t, cr.Status.Security.Error, "generation 4 of security cr applied-sec")
+	assert.True(t, securityRenderFailed(cr))
+	recordSecurityRender(cr, nil, nil)
+	assert.Nil(t, cr.Status.Security)
+}
+
+func TestWaitForSecurity(t *testing.T) {
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: "test"},
+		Spec:       brokerv1beta1.ActiveMQArtemisSpec{WaitForSecurity: true},
+	}
+	desired := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "broker-ss", Namespace: "test"}}
+
+	assert.False(t, isSecurityGateOpen(cr))
+	updateSecurityAppliedCondition(cr)
+	condition := meta.FindStatusCondition(cr.Status.Conditions, brokerv1beta1.SecurityAppliedConditionType)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, brokerv1beta1.SecurityAppliedConditionWaitingReason, condition.Reason)
+
+	// nothing is created on a fresh install
+	reconciler := &ActiveMQArtemisReconcilerImpl{}
+	reconciler.holdStatefulSetForSecurity(desired)
+	assert.Empty(t, reconciler.requestedResources)
+
+	// and what is deployed stays as it is
+	deployed := desired.DeepCopy()
+	deployed.Spec.Replicas = common.Int32ToPtr(3)
+	reconciler = &ActiveMQArtemisReconcilerImpl{deployed: map[reflect.Type][]client.Object{reflect.TypeOf(appsv1.StatefulSet{}): {deployed}}}
+	reconciler.holdStatefulSetForSecurity(desired)
+	assert.Len(t, reconciler.requestedResources, 1)
+	assert.Equal(t, int32(3), *reconciler.requestedResources[0].(*appsv1.StatefulSet).Spec.Replicas)
+
+	securityName := types.NamespacedName{Name: "security", Namespace: "test"}
+	namespaceToConfigHandler[securityName] = &ActiveMQArtemisSecurityConfigHandler{
+		SecurityCR:     &brokerv1beta1.ActiveMQArtemisSecurity{ObjectMeta: metav1.ObjectMeta{Name: "security", Namespace: "test"}},
+		NamespacedName: securityName,
+	}
+	defer delete(namespaceToConfigHandler, securityName)
+
+	assert.True(t, isSecurityGateOpen(cr))
+	updateSecurityAppliedCondition(cr)
+	assert.True(t, meta.IsStatusConditionTrue(cr.Status.Conditions, brokerv1beta1.SecurityAppliedConditionType))
+
+	cr.Spec.WaitForSecurity = false
+	updateSecurityAppliedCondition(cr)
+	assert.Nil(t, meta.FindStatusCondition(cr.Status.Conditions, brokerv1beta1.SecurityAppliedConditionType))
+}
@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"strings"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	istioInjectAnnotation      = "sidecar.istio.io/inject"
+	istioProxyConfigAnnotation = "proxy.istio.io/config"
+
+	meshProtocolTCP   = "tcp"
+	meshProtocolTLS   = "tls"
+	meshProtocolHTTP  = "http"
+	meshProtocolHTTPS = "https"
+)
+
+func isServiceMeshEnabled(customResource *brokerv1beta1.ActiveMQArtemis) bool {
+	return customResource.Spec.ServiceMesh != nil && customResource.Spec.ServiceMesh.Enabled
+}
+
+// the mesh takes a port it can't detect the protocol of for http, which breaks the messaging protocols
+func meshPortName(customResource *brokerv1beta1.ActiveMQArtemis, name string, protocol string) string {
+	if !isServiceMeshEnabled(customResource) || strings.HasPrefix(name, protocol+"-") {
+		return name
+	}
+	return protocol + "-" + name
+}
+
+func nameMeshPorts(customResource *brokerv1beta1.ActiveMQArtemis, service *corev1.Service, protocol string) {
+	for i := range service.Spec.Ports {
+		service.Spec.Ports[i].Name = meshPortName(customResource, service.Spec.Ports[i].Name, protocol)
+	}
+}
+
+// ssl enabled acceptors are passed through the sidecar as tls, the mesh can't look into them
+func meshTransportProtocol(sslEnabled bool) string {
+	if sslEnabled {
+		return meshProtocolTLS
+	}
+	return meshProtocolTCP
+}
+
+func meshConsoleProtocol(customResource *brokerv1beta1.ActiveMQArtemis) string {
+	if customResource.Spec.Console.SSLEnabled {
+		return meshProtocolHTTPS
+	}
+	return meshProtocolHTTP
+}
+
+// a proxy config set through the annotations of the deployment plan is left as it is
+func addServiceMeshAnnotations(customResource *brokerv1beta1.ActiveMQArtemis, annotations map[string]string) {
+	mesh := customResource.Spec.ServiceMesh
+	if _, found := annotations[istioInjectAnnotation]; !found {
+		annotations[istioInjectAnnotation] = "true"
+	}
+	if _, found := annotations[istioProxyConfigAnnotation]; !found && (mesh.HoldApplicationUntilProxyStarts == nil || *mesh.HoldApplicationUntilProxyStarts) {
+		annotations[istioProxyConfigAnnotation] = `{"holdApplicationUntilProxyStarts":true}`
+	}
+}
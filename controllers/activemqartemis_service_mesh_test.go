@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"testing"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestServiceMeshPortNames(t *testing.T) {
+	ingressMode := brokerv1beta1.ExposeModeIngress
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: "mesh"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			ServiceMesh: &brokerv1beta1.ServiceMeshType{Enabled: true},
+			DeploymentPlan: brokerv1beta1.DeploymentPlanType{
+				Annotations: map[string]string{"prometheus.io/scrape": "true"},
+				Metrics:     &brokerv1beta1.MetricsType{},
+			},
+			Acceptors: []brokerv1beta1.AcceptorType{
+				{Name: "amqp", Port: 5672},
+				{Name: "amqps", Port: 5671, SSLEnabled: true, Expose: true, ExposeMode: &ingressMode},
+			},
+			Console: brokerv1beta1.ConsoleType{Expose: true, SSLEnabled: true},
+		},
+	}
+
+	names := []string{}
+	for _, port := range *headlessServicePorts(cr) {
+		names = append(names, port.Name)
+	}
+	assert.Equal(t, []string{"https-console-jolokia", "tcp-all", "https-metrics"}, names)
+
+	annotations := podAnnotations(cr)
+	assert.Equal(t, "true", annotations["prometheus.io/scrape"])
+	assert.Equal(t, "true", annotations[istioInjectAnnotation])
+	assert.Equal(t, `{"holdApplicationUntilProxyStarts":true}`, annotations[istioProxyConfigAnnotation])
+	assert.Len(t, cr.Spec.DeploymentPlan.Annotations, 1)
+
+	reconciler := &ActiveMQArtemisReconcilerImpl{}
+	client := fake.NewClientBuilder().Build()
+	reconciler.configureAcceptorsExposure(cr, *MakeNamers(cr), client, nil)
+	reconciler.configureConsoleExposure(cr, *MakeNamers(cr), client, nil)
+	ports := map[string]string{}
+	backends := []string{}
+	for _, obj := range reconciler.requestedResources {
+		switch desired := obj.(type) {
+		case *v1.Service:
+			for _, port := range desired.Spec.Ports {
+				ports[desired.Name+"/"+port.Name] = port.Name
+			}
+		case *netv1.Ingress:
+			backends = append(backends, desired.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Port.Name)
+		}
+	}
+	assert.Contains(t, ports, "broker-amqp-0-svc/tcp-amqp-0")
+	assert.Contains(t, ports, "broker-amqps-0-svc/tls-amqps-0")
+	assert.Contains(t, ports, "broker-wconsj-0-svc/https-wconsj")
+	assert.Contains(t, ports, "broker-wconsj-0-svc/https-wconsj-0")
+	// the ingresses target the renamed ports
+	assert.ElementsMatch(t, []string{"tls-amqps-0", "https-wconsj-0"}, backends)
+
+	// a proxy config of the deployment plan wins, and the hold can be turned off
+	hold := false
+	cr.Spec.ServiceMesh.HoldApplicationUntilProxyStarts = &hold
+	assert.NotContains(t, podAnnotations(cr), istioProxyConfigAnnotation)
+	cr.Spec.DeploymentPlan.Annotations[istioProxyConfigAnnotation] = "{}"
+	assert.Equal(t, "{}", podAnnotations(cr)[istioProxyConfigAnnotation])
+
+	// outside the mesh the names stay as they were
+	cr.Spec.ServiceMesh.Enabled = false
+	assert.Equal(t, "console-jolokia", (*headlessServicePorts(cr))[0].Name)
+	assert.NotContains(t, podAnnotations(cr), istioInjectAnnotation)
+}
@@ -0,0 +1,140 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/resources/configmaps"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	topologyConfigMapSuffix = "-topology"
+	TopologyKey             = "topology.json"
+
+	topologyRoleLive = "live"
+)
+
+// Topology is the document clients watch, Version goes up whenever anything else changes
+type Topology struct {
+	Version int64            `json:"version"`
+	Brokers []TopologyBroker `json:"brokers"`
+}
+
+type TopologyBroker struct {
+	Name              string             `json:"name"`
+	Ordinal           int32              `json:"ordinal"`
+	Ready             bool               `json:"ready"`
+	Role              string             `json:"role"`
+	Host              string             `json:"host"`
+	Zone              string             `json:"zone,omitempty"`
+	Acceptors         []TopologyEndpoint `json:"acceptors,omitempty"`
+	ExternalEndpoints []TopologyEndpoint `json:"externalEndpoints,omitempty"`
+}
+
+type TopologyEndpoint struct {
+	Name string `json:"name"`
+	Host string `json:"host,omitempty"`
+	Port int32  `json:"port"`
+	TLS  bool   `json:"tls,omitempty"`
+}
+
+func topologyConfigMapName(customResource *brokerv1beta1.ActiveMQArtemis) string {
+	return customResource.Name + topologyConfigMapSuffix
+}
+
+// ProcessTopology tracks the topology ConfigMap, it is rewritten in one update so a watcher never
+// sees a partial change
+func (reconciler *ActiveMQArtemisReconcilerImpl) ProcessTopology(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, client rtclient.Client) {
+	if !customResource.Spec.PublishTopology {
+		return
+	}
+
+	topology := buildTopology(customResource, namer, client)
+
+	var desired *corev1.ConfigMap
+	if obj := reconciler.cloneOfDeployed(reflect.TypeOf(corev1.ConfigMap{}), topologyConfigMapName(customResource)); obj != nil {
+		desired = obj.(*corev1.ConfigMap)
+		deployed := Topology{}
+		if err := json.Unmarshal([]byte(desired.Data[TopologyKey]), &deployed); err == nil {
+			topology.Version = deployed.Version
+			if !reflect.DeepEqual(deployed, topology) {
+				topology.Version++
+			}
+		}
+	} else {
+		desired = configmaps.MakeConfigMap(customResource.Namespace, topologyConfigMapName(customResource), nil)
+		desired.Labels = namer.LabelBuilder.Labels()
+	}
+
+	document, err := json.Marshal(topology)
+	if err != nil {
+		clog.Error(err, "unable to marshal the topology", "name", desired.Name)
+		return
+	}
+	desired.Data = map[string]string{TopologyKey: string(document)}
+	reconciler.trackDesired(desired)
+}
+
+func buildTopology(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, client rtclient.Client) Topology {
+	topology := Topology{Version: 1, Brokers: []TopologyBroker{}}
+
+	var acceptors []TopologyEndpoint
+	for _, acceptor := range customResource.Spec.Acceptors {
+		acceptors = append(acceptors, TopologyEndpoint{Name: acceptor.Name, Port: acceptor.Port, TLS: acceptor.SSLEnabled})
+	}
+
+	for i := int32(0); i < getDeploymentSize(customResource); i++ {
+		name := namer.SsNameBuilder.Name() + "-" + strconv.Itoa(int(i))
+		broker := TopologyBroker{
+			Name:      name,
+			Ordinal:   i,
+			Role:      topologyRoleLive,
+			Host:      fmt.Sprintf("%s.%s.%s.svc.cluster.local", name, namer.SvcHeadlessNameBuilder.Name(), customResource.Namespace),
+			Acceptors: acceptors,
+		}
+
+		pod := &corev1.Pod{}
+		if err := client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: customResource.Namespace}, pod); err == nil {
+			broker.Ready = isPodReady(pod)
+			broker.Zone = pod.Labels[ZoneLabel]
+		}
+
+		names := podExposureNames(customResource, i)
+		for _, endpoint := range customResource.Status.ExternalEndpoints {
+			if names[endpoint.Name] {
+				broker.ExternalEndpoints = append(broker.ExternalEndpoints, TopologyEndpoint{Name: endpoint.Name, Host: endpoint.Host, Port: endpoint.Port, TLS: endpoint.TLS})
+			}
+		}
+		topology.Brokers = append(topology.Brokers, broker)
+	}
+	return topology
+}
+
+// the names of the routes, ingresses and services that expose a single pod
+func podExposureNames(customResource *brokerv1beta1.ActiveMQArtemis, ordinal int32) map[string]bool {
+	names := map[string]bool{}
+	for _, acceptor := range customResource.Spec.Acceptors {
+		portName := acceptor.Name + "-" + strconv.Itoa(int(ordinal))
+		targetServiceName := customResource.Name + "-" + portName + "-svc"
+		names[targetServiceName+"-rte"] = true
+		names[targetServiceName+"-ing"] = true
+		names[exposedServiceName(customResource, portName, brokerv1beta1.ExposeModeLoadBalancer)] = true
+	}
+	return names
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
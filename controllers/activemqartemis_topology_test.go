@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/common"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTopology(t *testing.T) {
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: "test"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			PublishTopology: true,
+			DeploymentPlan:  brokerv1beta1.DeploymentPlanType{Size: common.Int32ToPtr(2)},
+			Acceptors:       []brokerv1beta1.AcceptorType{{Name: "amqp", Port: 5672, Expose: true}},
+		},
+		Status: brokerv1beta1.ActiveMQArtemisStatus{
+			ExternalEndpoints: []brokerv1beta1.ExternalEndpointStatus{{Name: "broker-amqp-1-svc-ing", Host: "broker-amqp-1-svc-ing.example.com", Port: 80}},
+		},
+	}
+	namer := MakeNamers(cr)
+	readyPod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "broker-ss-0", Namespace: "test"},
+		Status:     v1.PodStatus{Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}}},
+	}
+	fakeClient := fake.NewClientBuilder().WithObjects(readyPod).Build()
+
+	publish := func(deployed *v1.ConfigMap) (*v1.ConfigMap, Topology) {
+		reconciler := &ActiveMQArtemisReconcilerImpl{deployed: map[reflect.Type][]client.Object{}}
+		if deployed != nil {
+			reconciler.deployed[reflect.TypeOf(v1.ConfigMap{})] = []client.Object{deployed}
+		}
+		reconciler.ProcessTopology(cr, *namer, fakeClient)
+		assert.Len(t, reconciler.requestedResources, 1)
+		configMap := reconciler.requestedResources[0].(*v1.ConfigMap)
+		assert.Equal(t, "broker-topology", configMap.Name)
+		topology := Topology{}
+		assert.NoError(t, json.Unmarshal([]byte(configMap.Data[TopologyKey]), &topology))
+		return configMap, topology
+	}
+
+	configMap, topology := publish(nil)
+	assert.Equal(t, int64(1), topology.Version)
+	assert.Len(t, topology.Brokers, 2)
+	assert.True(t, topology.Brokers[0].Ready)
+	assert.False(t, topology.Brokers[1].Ready)
+	assert.Equal(t, "broker-ss-1.broker-hdls-svc.test.svc.cluster.local", topology.Brokers[1].Host)
+	assert.Equal(t, []TopologyEndpoint{{Name: "amqp", Port: 5672}}, topology.Brokers[0].Acceptors)
+	assert.Empty(t, topology.Brokers[0].ExternalEndpoints)
+	assert.Equal(t, "broker-amqp-1-svc-ing.example.com", topology.Brokers[1].ExternalEndpoints[0].Host)
+
+	// unchanged, the version stays
+	configMap, topology = publish(configMap)
+	assert.Equal(t, int64(1), topology.Version)
+
+	// a failover bumps it
+	readyPod.Status.Conditions[0].Status = v1.ConditionFalse
+	assert.NoError(t, fakeClient.Status().Update(context.TODO(), readyPod))
+	_, topology = publish(configMap)
+	assert.Equal(t, int64(2), topology.Version)
+	assert.False(t, topology.Brokers[0].Ready)
+
+	cr.Spec.PublishTopology = false
+	reconciler := &ActiveMQArtemisReconcilerImpl{}
+	reconciler.ProcessTopology(cr, *namer, fakeClient)
+	assert.Empty(t, reconciler.requestedResources)
+}
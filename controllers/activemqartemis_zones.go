@@ -0,0 +1,205 @@
+package controllers
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	svc "github.com/artemiscloud/activemq-artemis-operator/pkg/resources/services"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/common"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/selectors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ZoneLabel carries the zone of the node a broker pod runs on
+const ZoneLabel = "broker.amq.io/zone"
+
+// the annotations that have kube-proxy prefer the endpoints of the zone of the client, the first one
+// up to kubernetes 1.26 and the second one from 1.27 on
+const (
+	topologyAwareHintsAnnotation = "service.kubernetes.io/topology-aware-hints"
+	topologyModeAnnotation       = "service.kubernetes.io/topology-mode"
+)
+
+var invalidServiceNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+func isZoneAware(customResource *brokerv1beta1.ActiveMQArtemis) bool {
+	return customResource.Spec.ZoneAwareness != nil && customResource.Spec.ZoneAwareness.Enabled
+}
+
+func zoneTopologyKey(customResource *brokerv1beta1.ActiveMQArtemis) string {
+	if key := customResource.Spec.ZoneAwareness.TopologyKey; key != "" {
+		return key
+	}
+	return corev1.LabelTopologyZone
+}
+
+// the zone is a label value, it may hold dots, underscores and capitals a service name can't
+func zoneServiceName(customResource *brokerv1beta1.ActiveMQArtemis, acceptor brokerv1beta1.AcceptorType, zone string) string {
+	return customResource.Name + "-" + acceptor.Name + "-zone-" + strings.Trim(invalidServiceNameChars.ReplaceAllString(strings.ToLower(zone), "-"), "-") + "-svc"
+}
+
+// the service of an acceptor that selects every broker and prefers the brokers of the zone of the client
+func nearestServiceName(customResource *brokerv1beta1.ActiveMQArtemis, acceptor brokerv1beta1.AcceptorType) string {
+	return customResource.Name + "-" + acceptor.Name + "-nearest-svc"
+}
+
+// withZoneRouting adds the topology aware routing annotations to the annotations of a service that
+// selects every broker. The endpoints get hints in proportion to the brokers of each zone, and every
+// zone falls back to all the brokers while one of them has none that are ready
+func withZoneRouting(customResource *brokerv1beta1.ActiveMQArtemis, annotations map[string]string) map[string]string {
+	if !isZoneAware(customResource) {
+		return annotations
+	}
+	routed := map[string]string{
+		topologyAwareHintsAnnotation: "auto",
+		topologyModeAnnotation:       "Auto",
+	}
+	for key, value := range annotations {
+		routed[key] = value
+	}
+	return routed
+}
+
+// configureZoneSpread keeps the number of brokers in any two zones at most one apart, the
+// StatefulSet creates the pods one ordinal after the other so the zones fill up in turn
+func configureZoneSpread(podSpec *corev1.PodSpec, customResource *brokerv1beta1.ActiveMQArtemis) {
+	if !isZoneAware(customResource) {
+		podSpec.TopologySpreadConstraints = nil
+		return
+	}
+	whenUnsatisfiable := customResource.Spec.ZoneAwareness.WhenUnsatisfiable
+	if whenUnsatisfiable == "" {
+		whenUnsatisfiable = corev1.ScheduleAnyway
+	}
+	podSpec.TopologySpreadConstraints = []corev1.TopologySpreadConstraint{{
+		MaxSkew:           1,
+		TopologyKey:       zoneTopologyKey(customResource),
+		WhenUnsatisfiable: whenUnsatisfiable,
+		LabelSelector:     &metav1.LabelSelector{MatchLabels: map[string]string{selectors.LabelResourceKey: customResource.Name}},
+	}}
+}
+
+// nodeReader gets the node of a broker pod without the cache, the operator only needs to get the
+// nodes its brokers run on rather than watch all the nodes of the cluster
+var nodeReader = func(client rtclient.Client) rtclient.Reader {
+	if mgr := common.GetManager(); mgr != nil {
+		return mgr.GetAPIReader()
+	}
+	return client
+}
+
+// ProcessZones labels the scheduled broker pods with the zone of their node and tracks a service per
+// acceptor and zone. The zone of a pod never changes, a pod is labeled once
+func (reconciler *ActiveMQArtemisReconcilerImpl) ProcessZones(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers, client rtclient.Client) {
+	if !isZoneAware(customResource) {
+		return
+	}
+
+	zones := map[string]bool{}
+	for i := int32(0); i < getDeploymentSize(customResource); i++ {
+		pod := &corev1.Pod{}
+		if err := client.Get(context.TODO(), types.NamespacedName{Name: namer.SsNameBuilder.Name() + "-" + strconv.Itoa(int(i)), Namespace: customResource.Namespace}, pod); err != nil {
+			continue
+		}
+		if zone, found := pod.Labels[ZoneLabel]; found {
+			zones[zone] = true
+			continue
+		}
+		if pod.Spec.NodeName == "" {
+			continue
+		}
+		node := &corev1.Node{}
+		if err := nodeReader(client).Get(context.TODO(), types.NamespacedName{Name: pod.Spec.NodeName}, node); err != nil {
+			clog.Error(err, "unable to read the zone of the node of broker pod", "pod", pod.Name, "node", pod.Spec.NodeName)
+			continue
+		}
+		zone := node.Labels[zoneTopologyKey(customResource)]
+		if zone == "" {
+			continue
+		}
+		patch := rtclient.MergeFrom(pod.DeepCopy())
+		if pod.Labels == nil {
+			pod.Labels = map[string]string{}
+		}
+		pod.Labels[ZoneLabel] = zone
+		if err := client.Patch(context.TODO(), pod, patch); err != nil {
+			clog.Error(err, "unable to label broker pod with its zone", "pod", pod.Name, "zone", zone)
+			continue
+		}
+		zones[zone] = true
+	}
+
+	namespacedName := types.NamespacedName{Name: customResource.Name, Namespace: customResource.Namespace}
+	for _, acceptor := range customResource.Spec.Acceptors {
+		serviceDefinition := svc.NewServiceDefinitionForCR(nearestServiceName(customResource, acceptor), client, namespacedName, acceptor.Name, acceptor.Port, namer.LabelBuilder.Labels(), namer.LabelBuilder.Labels())
+		serviceDefinition.Spec.PublishNotReadyAddresses = false
+		reconciler.configureIPFamilies(customResource, serviceDefinition)
+		nameMeshPorts(customResource, serviceDefinition, meshTransportProtocol(acceptor.SSLEnabled))
+		setExposeAnnotations(serviceDefinition, withZoneRouting(customResource, nil))
+		reconciler.checkExistingService(customResource, serviceDefinition, client)
+		reconciler.trackDesired(serviceDefinition)
+	}
+	for _, zone := range sortedZones(zones) {
+		selector := map[string]string{ZoneLabel: zone}
+		for key, value := range namer.LabelBuilder.Labels() {
+			selector[key] = value
+		}
+		for _, acceptor := range customResource.Spec.Acceptors {
+			serviceDefinition := svc.NewServiceDefinitionForCR(zoneServiceName(customResource, acceptor, zone), client, namespacedName, acceptor.Name, acceptor.Port, selector, namer.LabelBuilder.Labels())
+			// clients only get the brokers that can serve them, unlike the services of a single pod
+			serviceDefinition.Spec.PublishNotReadyAddresses = false
+			reconciler.configureIPFamilies(customResource, serviceDefinition)
+			nameMeshPorts(customResource, serviceDefinition, meshTransportProtocol(acceptor.SSLEnabled))
+			reconciler.checkExistingService(customResource, serviceDefinition, client)
+			reconciler.trackDesired(serviceDefinition)
+		}
+	}
+}
+
+func sortedZones(zones map[string]bool) []string {
+	sorted := make([]string, 0, len(zones))
+	for zone := range zones {
+		sorted = append(sorted, zone)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// updateZoneStatus groups the broker pods by the zone they are labeled with
+func updateZoneStatus(customResource *brokerv1beta1.ActiveMQArtemis, client rtclient.Client, namer Namers) {
+	if !isZoneAware(customResource) {
+		customResource.Status.Zones = nil
+		return
+	}
+	pods := map[string][]string{}
+	for i := int32(0); i < getDeploymentSize(customResource); i++ {
+		pod := &corev1.Pod{}
+		if err := client.Get(context.TODO(), types.NamespacedName{Name: namer.SsNameBuilder.Name() + "-" + strconv.Itoa(int(i)), Namespace: customResource.Namespace}, pod); err != nil {
+			continue
+		}
+		if zone, found := pod.Labels[ZoneLabel]; found {
+			pods[zone] = append(pods[zone], pod.Name)
+		}
+	}
+
+	names := map[string]bool{}
+	for zone := range pods {
+		names[zone] = true
+	}
+	var zones []brokerv1beta1.ZoneStatus
+	for _, zone := range sortedZones(names) {
+		status := brokerv1beta1.ZoneStatus{Zone: zone, Pods: pods[zone]}
+		for _, acceptor := range customResource.Spec.Acceptors {
+			status.Services = append(status.Services, zoneServiceName(customResource, acceptor, zone))
+		}
+		zones = append(zones, status)
+	}
+	customResource.Status.Zones = zones
+}
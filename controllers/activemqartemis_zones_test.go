@@ -0,0 +1,143 @@
+package controllers
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/common"
+	"github.com/stretchr/testify/assert"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+)
+
+func TestZoneAwareness(t *testing.T) {
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "zoned", Namespace: "zoned-ns"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			DeploymentPlan: brokerv1beta1.DeploymentPlanType{Size: common.Int32ToPtr(3)},
+			Acceptors:      []brokerv1beta1.AcceptorType{{Name: "amqp", Port: 5672}},
+			ZoneAwareness:  &brokerv1beta1.ZoneAwarenessType{Enabled: true},
+		},
+	}
+	namer := MakeNamers(cr)
+
+	podSpec := &v1.PodSpec{}
+	configureZoneSpread(podSpec, cr)
+	if assert.Len(t, podSpec.TopologySpreadConstraints, 1) {
+		spread := podSpec.TopologySpreadConstraints[0]
+		assert.Equal(t, v1.LabelTopologyZone, spread.TopologyKey)
+		assert.Equal(t, int32(1), spread.MaxSkew)
+		assert.Equal(t, v1.ScheduleAnyway, spread.WhenUnsatisfiable)
+		assert.Equal(t, "zoned", spread.LabelSelector.MatchLabels["ActiveMQArtemis"])
+	}
+
+	assert.Equal(t, "zoned-amqp-zone-eu-west-1a-svc", zoneServiceName(cr, cr.Spec.Acceptors[0], "eu-west-1a"))
+	assert.Equal(t, "zoned-amqp-zone-rack-b-2-svc", zoneServiceName(cr, cr.Spec.Acceptors[0], "Rack_B.2"))
+
+	node := func(name string, zone string) *v1.Node {
+		return &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{v1.LabelTopologyZone: zone}}}
+	}
+	pod := func(ordinal int, nodeName string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "zoned-ss-" + strconv.Itoa(ordinal), Namespace: cr.Namespace, Labels: namer.LabelBuilder.Labels()},
+			Spec:       v1.PodSpec{NodeName: nodeName},
+		}
+	}
+	testScheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(testScheme))
+	c := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(
+		node("node-a", "eu-west-1a"), node("node-b", "eu-west-1b"), pod(0, "node-a"), pod(1, "node-b"), pod(2, "")).Build()
+	defer func(reader func(client.Client) client.Reader) { nodeReader = reader }(nodeReader)
+	nodeReader = func(c client.Client) client.Reader { return c }
+
+	reconciler := &ActiveMQArtemisReconcilerImpl{}
+	reconciler.ProcessZones(cr, *namer, c)
+	zoneOf := func(name string) string {
+		labeled := &v1.Pod{}
+		assert.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: cr.Namespace}, labeled))
+		return labeled.Labels[ZoneLabel]
+	}
+	assert.Equal(t, "eu-west-1a", zoneOf("zoned-ss-0"))
+	assert.Equal(t, "eu-west-1b", zoneOf("zoned-ss-1"))
+	assert.Equal(t, "", zoneOf("zoned-ss-2"), "a pending pod has no zone yet")
+
+	// the clients of a zone get the ready brokers of their zone
+	services := map[string]*v1.Service{}
+	for _, obj := range reconciler.requestedResources {
+		services[obj.GetName()] = obj.(*v1.Service)
+	}
+	assert.Len(t, services, 3)
+	zoneA := services["zoned-amqp-zone-eu-west-1a-svc"]
+	if assert.NotNil(t, zoneA) {
+		assert.Equal(t, "eu-west-1a", zoneA.Spec.Selector[ZoneLabel])
+		assert.Equal(t, "zoned", zoneA.Spec.Selector["ActiveMQArtemis"])
+		assert.False(t, zoneA.Spec.PublishNotReadyAddresses)
+		assert.Equal(t, int32(5672), zoneA.Spec.Ports[0].Port)
+	}
+	// the clients of any zone get all the ready brokers, those of their own zone first
+	nearest := services["zoned-amqp-nearest-svc"]
+	if assert.NotNil(t, nearest) {
+		assert.NotContains(t, nearest.Spec.Selector, ZoneLabel)
+		assert.Equal(t, "zoned", nearest.Spec.Selector["ActiveMQArtemis"])
+		assert.Equal(t, "Auto", nearest.Annotations[topologyModeAnnotation])
+		assert.Equal(t, "auto", nearest.Annotations[topologyAwareHintsAnnotation])
+	}
+
+	updateZoneStatus(cr, c, *namer)
+	assert.Equal(t, []brokerv1beta1.ZoneStatus{
+		{Zone: "eu-west-1a", Pods: []string{"zoned-ss-0"}, Services: []string{"zoned-amqp-zone-eu-west-1a-svc"}},
+		{Zone: "eu-west-1b", Pods: []string{"zoned-ss-1"}, Services: []string{"zoned-amqp-zone-eu-west-1b-svc"}},
+	}, cr.Status.Zones)
+	assert.Equal(t, "eu-west-1b", buildTopology(cr, *namer, c).Brokers[1].Zone)
+
+	exposed := cr.DeepCopy()
+	exposed.Spec.Acceptors[0].Expose = true
+	exposed.Spec.Acceptors[0].ExposeMode = &[]brokerv1beta1.ExposeMode{brokerv1beta1.ExposeModeLoadBalancer}[0]
+	reconciler = &ActiveMQArtemisReconcilerImpl{}
+	reconciler.trackExposedService(exposed, *namer, c, exposed.Spec.Acceptors[0], "amqp", namer.LabelBuilder.Labels(), 0)
+	assert.Equal(t, "Auto", reconciler.requestedResources[0].GetAnnotations()[topologyModeAnnotation])
+
+	cr.Spec.ZoneAwareness.Enabled = false
+	configureZoneSpread(podSpec, cr)
+	assert.Nil(t, podSpec.TopologySpreadConstraints)
+	updateZoneStatus(cr, c, *namer)
+	assert.Nil(t, cr.Status.Zones)
+}
+
+func TestZoneAwareBrokerOfPod(t *testing.T) {
+	zoned := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "zoned", Namespace: "zoned-ns"},
+		Spec:       brokerv1beta1.ActiveMQArtemisSpec{ZoneAwareness: &brokerv1beta1.ZoneAwarenessType{Enabled: true}},
+	}
+	plain := &brokerv1beta1.ActiveMQArtemis{ObjectMeta: metav1.ObjectMeta{Name: "plain", Namespace: "zoned-ns"}}
+	r := &ActiveMQArtemisReconciler{Client: fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithObjects(zoned, plain).Build()}
+
+	pending := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "zoned-ss-0", Namespace: "zoned-ns", Labels: MakeNamers(zoned).LabelBuilder.Labels()}}
+	scheduled := pending.DeepCopy()
+	scheduled.Spec.NodeName = "node-a"
+	predicate := podScheduledPredicate()
+	assert.False(t, predicate.Create(event.CreateEvent{Object: pending}))
+	assert.True(t, predicate.Create(event.CreateEvent{Object: scheduled}))
+	assert.True(t, predicate.Update(event.UpdateEvent{ObjectOld: pending, ObjectNew: scheduled}), "scheduling a pod labels it")
+	assert.False(t, predicate.Update(event.UpdateEvent{ObjectOld: scheduled, ObjectNew: scheduled.DeepCopy()}))
+
+	assert.Equal(t, []ctrl.Request{{NamespacedName: types.NamespacedName{Name: "zoned", Namespace: "zoned-ns"}}}, r.zoneAwareBrokerOfPod(scheduled))
+
+	labeled := scheduled.DeepCopy()
+	labeled.Labels[ZoneLabel] = "eu-west-1a"
+	assert.Empty(t, r.zoneAwareBrokerOfPod(labeled))
+
+	other := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "plain-ss-0", Namespace: "zoned-ns", Labels: MakeNamers(plain).LabelBuilder.Labels()}}
+	assert.Empty(t, r.zoneAwareBrokerOfPod(other), "the pods of a broker without zone awareness are not labeled")
+	assert.Empty(t, r.zoneAwareBrokerOfPod(&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "zoned-ns"}}))
+}
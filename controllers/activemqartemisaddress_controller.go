@@ -18,18 +18,28 @@ package controllers
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
 	ss "github.com/artemiscloud/activemq-artemis-operator/pkg/resources/statefulsets"
 	mgmt "github.com/artemiscloud/activemq-artemis-operator/pkg/utils/artemis"
 	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/channels"
 	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/common"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/jolokia"
 	jc "github.com/artemiscloud/activemq-artemis-operator/pkg/utils/jolokia_client"
 	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/lsrcrs"
 	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/namer"
 	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/selectors"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
@@ -69,7 +79,7 @@ type ActiveMQArtemisAddressReconciler struct {
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.10.0/pkg/reconcile
 func (r *ActiveMQArtemisAddressReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
-	reqLogger := log.FromContext(ctx).WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name, "Reconciling", "ActiveMQArtemisAddress")
+	reqLogger := log.FromContext(ctx)
 
 	addressInstance, lookupSucceeded := namespacedNameToAddressName[request.NamespacedName]
 	// Fetch the ActiveMQArtemisAddress instance
@@ -79,14 +89,15 @@ func (r *ActiveMQArtemisAddressReconciler) Reconcile(ctx context.Context, reques
 		if errors.IsNotFound(err) {
 			// Delete action
 			if lookupSucceeded {
-				if addressInstance.AddressResource.Spec.RemoveFromBrokerOnDelete {
-					err = deleteQueue(&addressInstance, request, r.Client, r.Scheme)
-					return ctrl.Result{}, err
-				} else {
+				if addressRemovalPolicy(&addressInstance.AddressResource) == brokerv1beta1.AddressRemovalPolicyOrphan {
 					reqLogger.Info("Not to delete address", "address", addressInstance)
 				}
-				delete(namespacedNameToAddressName, request.NamespacedName)
-				lsrcrs.DeleteLastSuccessfulReconciledCR(request.NamespacedName, "address", getAddressLabels(&addressInstance.AddressResource), r.Client)
+				// other address CRs removed in the same teardown are handled
+				// in this pass so each pod is contacted once for all of them
+				if err = r.deletePendingAddresses(request); err != nil {
+					reqLogger.Error(err, "failed to delete address from brokers, request will be requeued")
+					return ctrl.Result{}, err
+				}
 			}
 			// Request object not found, could have been deleted after reconcile request.
 			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
@@ -97,12 +108,38 @@ func (r *ActiveMQArtemisAddressReconciler) Reconcile(ctx context.Context, reques
 		return ctrl.Result{}, err
 	}
 
+	// a CR admitted without the webhook is reported rather than left to fail on the brokers
+	if verr := instance.Spec.Validate(); verr != nil {
+		reqLogger.Info("Address CR is invalid, not applying it", "error", verr.Error())
+		return ctrl.Result{}, r.updateAddressValidity(ctx, instance, verr)
+	}
+
+	if target, requested := transferTarget(instance); requested {
+		transferred := &brokerv1beta1.ActiveMQArtemisAddress{
+			ObjectMeta: transferObjectMeta(instance, target),
+			Spec:       *instance.Spec.DeepCopy(),
+		}
+		transferred.Spec.ApplyToCrNames = transferApplyTo(instance, instance.Spec.ApplyToCrNames)
+		if err := transferCR(ctx, r.Client, r.Scheme, instance, transferred, nil); isTransferRefused(err) {
+			reqLogger.Info("not transferring address", "namespace", target, "reason", err.Error())
+		} else if err != nil {
+			reqLogger.Error(err, "failed to transfer address", "namespace", target)
+			return ctrl.Result{}, err
+		} else {
+			reqLogger.Info("transferred address", "namespace", target)
+		}
+	}
+
 	addressDeployment := AddressDeployment{
 		AddressResource:      *instance,
 		SsTargetNameBuilders: createNameBuilders(instance),
 	}
 
-	if !lookupSucceeded {
+	// the spec the address was last applied with, to tell what changed since
+	var previous *brokerv1beta1.ActiveMQArtemisAddress
+	if lookupSucceeded {
+		previous = &addressInstance.AddressResource
+	} else {
 		//check stored cr
 		if existingCr := lsrcrs.RetrieveLastSuccessfulReconciledCR(request.NamespacedName, "address", r.Client, getAddressLabels(instance)); existingCr != nil {
 			//compare resource version
@@ -112,26 +149,189 @@ func (r *ActiveMQArtemisAddressReconciler) Reconcile(ctx context.Context, reques
 				namespacedNameToAddressName[request.NamespacedName] = addressDeployment
 				return ctrl.Result{RequeueAfter: common.GetReconcileResyncPeriod()}, nil
 			}
+			stored := &brokerv1beta1.ActiveMQArtemisAddress{}
+			if merr := json.Unmarshal([]byte(existingCr.CR), stored); merr != nil {
+				reqLogger.Error(merr, "failed to unmarshal stored cr, applying as new")
+			} else {
+				previous = stored
+			}
 		}
 	}
 
-	err = createQueue(&addressDeployment, request, r.Client, r.Scheme)
+	if previous != nil && hasAddressSettings(previous) && !hasAddressSettings(instance) {
+		removeAddressSettingsFromBrokers(&AddressDeployment{AddressResource: *previous}, request, r.Client, r.Scheme)
+	}
+
+	getBrokers := func(deployment *AddressDeployment) []*jc.JkInfo {
+		return getPodBrokers(deployment, request, r.Client, r.Scheme)
+	}
+	changes, err := applyAddressUpdate(ctx, previous, &addressDeployment, planAddressUpdate(previous, instance), getBrokers)
+	var podErrors map[string]error
+	if err == nil {
+		podErrors = createQueue(ctx, &addressDeployment, request, r.Client, r.Scheme)
+		err = failedPodsError(podErrors)
+	}
 	if nil == err {
 		namespacedNameToAddressName[request.NamespacedName] = addressDeployment
-		crstr, merr := common.ToJson(instance)
-		if merr != nil {
-			reqLogger.Error(merr, "failed to marshal cr")
-		}
-		lsrcrs.StoreLastSuccessfulReconciledCR(instance, instance.Name, instance.Namespace, "address", crstr, "", instance.ResourceVersion, getAddressLabels(instance), r.Client, r.Scheme)
 	} else {
 		reqLogger.Error(err, "failed to create address resource, request will be requeued")
 	}
+
+	if serr := r.updateAddressStatus(ctx, instance, changes, podErrors, err); serr != nil {
+		reqLogger.Error(serr, "failed to update address status")
+		if err == nil {
+			err = serr
+		}
+	}
 	if err != nil {
 		return ctrl.Result{}, err
 	}
+	if len(podErrors) == 0 {
+		// the pods may still be starting, retry with backoff rather than wait for the resync
+		reqLogger.Info("no broker pod to create the address on, request will be requeued")
+		return ctrl.Result{Requeue: true}, nil
+	}
+	crstr, merr := common.ToJson(instance)
+	if merr != nil {
+		reqLogger.Error(merr, "failed to marshal cr")
+	}
+	lsrcrs.StoreLastSuccessfulReconciledCR(instance, instance.Name, instance.Namespace, "address", crstr, "", instance.ResourceVersion, getAddressLabels(instance), r.Client, r.Scheme)
 	return ctrl.Result{RequeueAfter: common.GetReconcileResyncPeriod()}, nil
 }
 
+// updateAddressStatus reports the outcome of applying the spec, the changes are those of the last
+// generation that was applied. The pods are those the address was created on, nil when it wasn't tried
+func (r *ActiveMQArtemisAddressReconciler) updateAddressStatus(ctx context.Context, instance *brokerv1beta1.ActiveMQArtemisAddress, changes []string, podErrors map[string]error, applyErr error) error {
+	status := instance.Status.DeepCopy()
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:   brokerv1beta1.ValidConditionType,
+		Status: metav1.ConditionTrue,
+		Reason: brokerv1beta1.ValidConditionSuccessReason,
+	})
+	if podErrors != nil {
+		status.Pods = addressPodStatuses(status.Pods, podErrors, instance.Generation, metav1.Now())
+		meta.SetStatusCondition(&status.Conditions, addressDeployedCondition(podErrors, instance.Generation))
+	}
+	condition := metav1.Condition{
+		Type:               brokerv1beta1.AddressAppliedConditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             brokerv1beta1.AddressAppliedSuccessReason,
+		ObservedGeneration: instance.Generation,
+	}
+	if applyErr != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = brokerv1beta1.AddressAppliedFailedReason
+		condition.Message = applyErr.Error()
+	} else if status.ObservedGeneration != instance.Generation {
+		status.ObservedGeneration = instance.Generation
+		status.Changes = changes
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+	if equality.Semantic.DeepEqual(&instance.Status, status) {
+		return nil
+	}
+	instance.Status = *status
+	return r.Client.Status().Update(ctx, instance)
+}
+
+// updateAddressValidity reports a spec that can't be applied
+func (r *ActiveMQArtemisAddressReconciler) updateAddressValidity(ctx context.Context, instance *brokerv1beta1.ActiveMQArtemisAddress, validationErr error) error {
+	status := instance.Status.DeepCopy()
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               brokerv1beta1.ValidConditionType,
+		Status:             metav1.ConditionFalse,
+		Reason:             brokerv1beta1.ValidConditionInvalidAddressReason,
+		Message:            validationErr.Error(),
+		ObservedGeneration: instance.Generation,
+	})
+	if equality.Semantic.DeepEqual(&instance.Status, status) {
+		return nil
+	}
+	instance.Status = *status
+	return r.Client.Status().Update(ctx, instance)
+}
+
+// addressPodStatuses records the outcome of each pod. A pod keeps its transition time while its
+// outcome stays the same, pods that are no longer selected are dropped
+func addressPodStatuses(existing []brokerv1beta1.AddressPodStatus, podErrors map[string]error, generation int64, now metav1.Time) []brokerv1beta1.AddressPodStatus {
+	previous := map[string]brokerv1beta1.AddressPodStatus{}
+	for _, pod := range existing {
+		previous[pod.PodName] = pod
+	}
+
+	pods := make([]brokerv1beta1.AddressPodStatus, 0, len(podErrors))
+	for podName, err := range podErrors {
+		last, found := previous[podName]
+		pod := brokerv1beta1.AddressPodStatus{
+			PodName:            podName,
+			State:              brokerv1beta1.AddressPodStateApplied,
+			ObservedGeneration: generation,
+			LastTransitionTime: now,
+		}
+		if err != nil {
+			pod.State = brokerv1beta1.AddressPodStateFailed
+			pod.Message = err.Error()
+			// a failed pod still has the generation it last applied
+			pod.ObservedGeneration = last.ObservedGeneration
+		}
+		if found && last.State == pod.State && last.Message == pod.Message && last.ObservedGeneration == pod.ObservedGeneration {
+			pod.LastTransitionTime = last.LastTransitionTime
+		}
+		pods = append(pods, pod)
+	}
+	sort.Slice(pods, func(i, j int) bool { return pods[i].PodName < pods[j].PodName })
+	return pods
+}
+
+// addressDeployedCondition tells whether the address is on every selected pod. When it isn't, the
+// reason says whether there was no pod, a pod couldn't be reached or refused the jolokia credentials
+func addressDeployedCondition(podErrors map[string]error, generation int64) metav1.Condition {
+	condition := metav1.Condition{
+		Type:               brokerv1beta1.AddressDeployedConditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             brokerv1beta1.AddressDeployedSuccessReason,
+		ObservedGeneration: generation,
+	}
+	if len(podErrors) == 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = brokerv1beta1.AddressDeployedNoBrokersReason
+		condition.Message = "no broker pod is selected, the address is created once one starts"
+		return condition
+	}
+	err := failedPodsError(podErrors)
+	if err == nil {
+		return condition
+	}
+	condition.Status = metav1.ConditionFalse
+	condition.Reason = brokerv1beta1.AddressDeployedApplyFailedReason
+	condition.Message = err.Error()
+	for _, podErr := range podErrors {
+		if jolokia.IsUnauthorized(podErr) {
+			condition.Reason = brokerv1beta1.AddressDeployedUnauthorizedReason
+			break
+		}
+		if jolokia.IsUnreachable(podErr) {
+			condition.Reason = brokerv1beta1.AddressDeployedUnreachableReason
+		}
+	}
+	return condition
+}
+
+// failedPodsError names the pods the address failed on, with the first of their errors
+func failedPodsError(podErrors map[string]error) error {
+	failed := []string{}
+	for podName, err := range podErrors {
+		if err != nil {
+			failed = append(failed, podName)
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	sort.Strings(failed)
+	return fmt.Errorf("failed on %v: %v", strings.Join(failed, ", "), podErrors[failed[0]])
+}
+
 func getAddressLabels(cr *brokerv1beta1.ActiveMQArtemisAddress) map[string]string {
 	labelBuilder := selectors.LabelerData{}
 	labelBuilder.Base(cr.Name).Suffix("addr").Generate()
@@ -177,16 +377,17 @@ func (r *ActiveMQArtemisAddressReconciler) SetupWithManager(mgr ctrl.Manager, ct
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&brokerv1beta1.ActiveMQArtemisAddress{}).
 		Owns(&corev1.Pod{}).
-		Complete(r)
+		Complete(withCorrelation("activemqartemisaddress", r))
 }
 
-// This method deals with creating queues and addresses.
-func createQueue(instance *AddressDeployment, request ctrl.Request, client client.Client, scheme *runtime.Scheme) error {
+// This method deals with creating queues and addresses. It returns the error of each pod, nil for
+// the pods that have the address
+func createQueue(ctx context.Context, instance *AddressDeployment, request ctrl.Request, client client.Client, scheme *runtime.Scheme) map[string]error {
 
-	reqLogger := ctrl.Log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger := log.FromContext(ctx)
 	reqLogger.Info("Creating ActiveMQArtemisAddress")
 
-	var err error = nil
+	podErrors := map[string]error{}
 	artemisArray := getPodBrokers(instance, request, client, scheme)
 	if nil != artemisArray {
 		for _, a := range artemisArray {
@@ -194,26 +395,84 @@ func createQueue(instance *AddressDeployment, request ctrl.Request, client clien
 				reqLogger.Info("Creating ActiveMQArtemisAddress artemisArray had a nil!")
 				continue
 			}
-			err = createAddressResource(a, &instance.AddressResource)
+			err := createAddressResource(a, &instance.AddressResource, appliedOnPod(&instance.AddressResource, a.PodName))
+			podErrors[a.PodName] = err
 			if err != nil {
-				reqLogger.V(1).Info("Failed to create address resource", "failed broker", a)
+				podLogger(ctx, a.PodName).V(1).Info("Failed to create address resource", "error", err.Error())
 				continue
 			}
 		}
 	}
 
-	if err == nil {
+	if failedPodsError(podErrors) == nil {
 		reqLogger.V(1).Info("Successfully created resources on all brokers", "size", len(artemisArray))
 	}
 
-	return err
+	return podErrors
+}
+
+// appliedOnPod tells whether the pod has the current generation of the CR already, the address is
+// created again on it after a restart or at a resync
+func appliedOnPod(addressRes *brokerv1beta1.ActiveMQArtemisAddress, podName string) bool {
+	for _, pod := range addressRes.Status.Pods {
+		if pod.PodName == podName {
+			return pod.State == brokerv1beta1.AddressPodStateApplied && pod.ObservedGeneration == addressRes.Generation
+		}
+	}
+	return false
+}
+
+// isAutoDeletedQueue tells whether the broker deletes the queue on its own once it is unused
+func isAutoDeletedQueue(addressRes *brokerv1beta1.ActiveMQArtemisAddress) bool {
+	config := addressRes.Spec.QueueConfiguration
+	return config != nil && config.AutoDelete != nil && *config.AutoDelete
 }
 
-func createAddressResource(a *jc.JkInfo, addressRes *brokerv1beta1.ActiveMQArtemisAddress) error {
+// createAddressResource creates the address and its queues on a pod. When the address is created again
+// on a pod that has it already, the queues the broker auto deletes are skipped, a queue the broker
+// deleted stays deleted until the CR changes
+func createAddressResource(a *jc.JkInfo, addressRes *brokerv1beta1.ActiveMQArtemisAddress, recreating bool) error {
+	// the defaults below are for the broker, the tracked spec is what the CR asked for
+	addressRes = addressRes.DeepCopy()
+	// the dead letter and expiry queues exist before the settings send messages to them
+	for _, queue := range deadLetterAndExpiryQueues(addressRes) {
+		if err := createAddressResource(a, queue, false); err != nil {
+			return err
+		}
+	}
+	// the settings are in place before the address takes messages
+	if hasAddressSettings(addressRes) {
+		settings, err := addressSettings(addressRes)
+		if err != nil {
+			glog.Error(err, "Failed to get address settings json string")
+		} else if respData, err := a.Artemis.AddAddressSettings(addressRes.Spec.AddressName, settings); err != nil {
+			glog.Error(err, "Error setting address settings of ActiveMQArtemisAddress", "address", addressRes.Spec.AddressName, "details", respData)
+			return err
+		}
+	}
+
+	// the address takes the routing types of all its queues before they are created
+	if len(addressRes.Spec.Queues) > 0 {
+		response, err := a.Artemis.CreateAddress(addressRes.Spec.AddressName, addressRoutingTypes(addressRes))
+		if nil != err && mgmt.GetCreationError(response) != mgmt.ADDRESS_ALREADY_EXISTS {
+			glog.Error(err, "Error creating ActiveMQArtemisAddress", "address", addressRes.Spec.AddressName)
+			return err
+		}
+		for _, queue := range addressQueues(addressRes) {
+			if recreating && isAutoDeletedQueue(queue) {
+				continue
+			}
+			if err := createAddressResource(a, queue, false); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
 	//Now checking if create queue or address
 	if addressRes.Spec.QueueName == nil || *addressRes.Spec.QueueName == "" {
 		//create address
-		response, err := a.Artemis.CreateAddress(addressRes.Spec.AddressName, *addressRes.Spec.RoutingType)
+		response, err := a.Artemis.CreateAddress(addressRes.Spec.AddressName, queueRoutingType(addressRes))
 		if nil != err {
 			if mgmt.GetCreationError(response) == mgmt.ADDRESS_ALREADY_EXISTS {
 				glog.Info("Address already exists, no retry", "address", addressRes.Spec.AddressName)
@@ -228,26 +487,17 @@ func createAddressResource(a *jc.JkInfo, addressRes *brokerv1beta1.ActiveMQArtem
 	} else {
 		glog.Info("Queue name is not empty so create queue", "name", *addressRes.Spec.QueueName, "broker", a.IP)
 		//first make sure address exists
-		response, err := a.Artemis.CreateAddress(addressRes.Spec.AddressName, *addressRes.Spec.RoutingType)
+		response, err := a.Artemis.CreateAddress(addressRes.Spec.AddressName, queueRoutingType(addressRes))
 		if nil != err && mgmt.GetCreationError(response) != mgmt.ADDRESS_ALREADY_EXISTS {
 			glog.Error(err, "Error creating ActiveMQArtemisAddress", "address", addressRes.Spec.AddressName)
 			return err
 		}
-
-		defaultConfigurationManaged := true
-		if addressRes.Spec.QueueConfiguration == nil {
-			routingType := "MULTICAST"
-			if addressRes.Spec.RoutingType != nil {
-				routingType = *addressRes.Spec.RoutingType
-			}
-
-			addressRes.Spec.QueueConfiguration = &brokerv1beta1.QueueConfigurationType{
-				RoutingType:          &routingType,
-				ConfigurationManaged: &defaultConfigurationManaged,
-			}
-		} else if addressRes.Spec.QueueConfiguration.ConfigurationManaged == nil {
-			addressRes.Spec.QueueConfiguration.ConfigurationManaged = &defaultConfigurationManaged
+		if recreating && isAutoDeletedQueue(addressRes) {
+			glog.V(1).Info("Not creating the auto deleted queue again", "name", *addressRes.Spec.QueueName, "broker", a.IP)
+			return nil
 		}
+
+		defaultQueueConfiguration(addressRes)
 		//create queue using queueconfig
 		queueCfg, ignoreIfExists, err := GetQueueConfig(addressRes)
 		if err != nil {
@@ -259,6 +509,10 @@ func createAddressResource(a *jc.JkInfo, addressRes *brokerv1beta1.ActiveMQArtem
 		if nil != err {
 			if mgmt.GetCreationError(respData) == mgmt.QUEUE_ALREADY_EXISTS {
 				glog.Info("The queue already exists, updating", "queue", queueCfg)
+				if queueCfg, err = getQueueUpdateConfig(addressRes); err != nil {
+					glog.Error(err, "Failed to get queue update config json string")
+					return nil
+				}
 				respData, err := a.Artemis.UpdateQueue(queueCfg)
 				if err != nil {
 					glog.Error(err, "Failed to update queue", "details", respData)
@@ -275,86 +529,448 @@ func createAddressResource(a *jc.JkInfo, addressRes *brokerv1beta1.ActiveMQArtem
 	return nil
 }
 
-type AddressRetry struct {
-	address string
-	artemis []*mgmt.Artemis
+// defaultQueueConfiguration gives a queue without a configuration the routing type of the address, the
+// configuration of the queue is managed by the broker unless it says otherwise. A temporary queue is
+// not durable, the broker makes queues durable by default
+func defaultQueueConfiguration(addressRes *brokerv1beta1.ActiveMQArtemisAddress) {
+	defaultConfigurationManaged := true
+	if addressRes.Spec.QueueConfiguration == nil {
+		routingType := queueRoutingType(addressRes)
+
+		addressRes.Spec.QueueConfiguration = &brokerv1beta1.QueueConfigurationType{
+			RoutingType:          &routingType,
+			ConfigurationManaged: &defaultConfigurationManaged,
+		}
+	} else if addressRes.Spec.QueueConfiguration.ConfigurationManaged == nil {
+		addressRes.Spec.QueueConfiguration.ConfigurationManaged = &defaultConfigurationManaged
+	}
+	if config := addressRes.Spec.QueueConfiguration; config.Temporary != nil && *config.Temporary && config.Durable == nil {
+		durable := false
+		config.Durable = &durable
+	}
 }
 
-func (ar *AddressRetry) addToDelete(a *mgmt.Artemis) {
-	ar.artemis = append(ar.artemis, a)
+// addressBulkDeleteTimeout bounds the time a single reconcile spends removing
+// addresses from the brokers; whatever is left over is retried on requeue
+var addressBulkDeleteTimeout = 60 * time.Second
+
+// the management operations removing addresses needs from a broker pod
+type addressDeleteBroker interface {
+	RemoveAddressSettingsOperation(addressMatch string) string
+	DeleteQueueOperation(queueName string) string
+	DeleteAddressOperation(addressName string) string
+	ExecBulk(operations []string) ([]*jolokia.ResponseData, error)
 }
 
-func (ar *AddressRetry) safeDelete() {
-	for _, a := range ar.artemis {
-		glog.Info("Checking parent address for bindings " + ar.address)
-		bindingsData, err := a.ListBindingsForAddress(ar.address)
-		if nil == err {
-			if bindingsData.Value == "" {
-				glog.Info("No bindings found, removing " + ar.address)
-				a.DeleteAddress(ar.address)
-			} else {
-				glog.Info("Bindings found, not removing", "address", ar.address, "bindings", bindingsData.Value)
+// addressDeleteBrokers are the broker pods the deployment applies to
+var addressDeleteBrokers = func(deployment *AddressDeployment, request ctrl.Request, client client.Client, scheme *runtime.Scheme) []addressDeleteBroker {
+	brokers := []addressDeleteBroker{}
+	for _, broker := range getPodBrokers(deployment, request, client, scheme) {
+		brokers = append(brokers, broker.Artemis)
+	}
+	return brokers
+}
+
+// deletePendingAddresses removes every tracked address CR of the request
+// namespace that no longer exists in the cluster. Brokers are resolved once per
+// target group and each pod gets the deletions of a group in one bulk request,
+// in parallel with the other pods. Only the error for the request's own CR is
+// returned, the other CRs are left tracked on failure and picked up by their
+// own reconcile.
+func (r *ActiveMQArtemisAddressReconciler) deletePendingAddresses(request ctrl.Request) error {
+	pending := r.pendingAddressDeletes(request)
+
+	groups := make(map[string][]types.NamespacedName)
+	for nn, deployment := range pending {
+		// a transferred address stays on the brokers for the copy to take over
+		if addressRemovalPolicy(&deployment.AddressResource) != brokerv1beta1.AddressRemovalPolicyOrphan && !isTransferred(&deployment.AddressResource) {
+			key := strings.Join(deployment.AddressResource.Spec.ApplyToCrNames, ",") + ";" + metav1.FormatLabelSelector(deployment.AddressResource.Spec.ApplyToCrSelector)
+			groups[key] = append(groups[key], nn)
+		}
+	}
+
+	deadline := time.Now().Add(addressBulkDeleteTimeout)
+	failures := make(map[types.NamespacedName]error)
+	for _, names := range groups {
+		sort.Slice(names, func(i, j int) bool { return names[i].Name < names[j].Name })
+		deployments := make([]*AddressDeployment, len(names))
+		for i, nn := range names {
+			deployment := pending[nn]
+			deployments[i] = &deployment
+		}
+		brokers := addressDeleteBrokers(deployments[0], request, r.Client, r.Scheme)
+		for i, err := range deleteFromBrokers(brokers, deployments, deadline) {
+			if err != nil {
+				failures[names[i]] = err
 			}
-		} else {
-			glog.Error(err, "failed to list bindings", "address", ar.address)
 		}
 	}
+
+	for nn, deployment := range pending {
+		if _, failed := failures[nn]; failed {
+			continue
+		}
+		delete(namespacedNameToAddressName, nn)
+		lsrcrs.DeleteLastSuccessfulReconciledCR(nn, "address", getAddressLabels(&deployment.AddressResource), r.Client)
+	}
+
+	return failures[request.NamespacedName]
 }
 
-// This method deals with deleting queues and addresses.
-func deleteQueue(instance *AddressDeployment, request ctrl.Request, client client.Client, scheme *runtime.Scheme) error {
+// pendingAddressDeletes collects the tracked deployments of the request
+// namespace whose CR has gone, always including the request itself
+func (r *ActiveMQArtemisAddressReconciler) pendingAddressDeletes(request ctrl.Request) map[types.NamespacedName]AddressDeployment {
+	pending := make(map[types.NamespacedName]AddressDeployment)
+	if deployment, found := namespacedNameToAddressName[request.NamespacedName]; found {
+		pending[request.NamespacedName] = deployment
+	}
 
-	reqLogger := ctrl.Log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	existing := &brokerv1beta1.ActiveMQArtemisAddressList{}
+	if err := r.List(context.TODO(), existing, client.InNamespace(request.Namespace)); err != nil {
+		glog.Error(err, "failed to list addresses, deleting only the requested one", "namespace", request.Namespace)
+		return pending
+	}
+	live := make(map[string]bool, len(existing.Items))
+	for _, item := range existing.Items {
+		live[item.Name] = true
+	}
+	for nn, deployment := range namespacedNameToAddressName {
+		if nn.Namespace == request.Namespace && !live[nn.Name] {
+			pending[nn] = deployment
+		}
+	}
+	return pending
+}
 
-	addressName := instance.AddressResource.Spec.AddressName
+// deleteFromBrokers runs the deletions for all deployments against each broker
+// concurrently. The returned slice holds, per deployment, the first error seen
+// on any broker. The deadline holds for the requests in flight too, when it
+// passes the deployments a pod has not answered for fail so they can be retried,
+// whatever the pod answers later is dropped.
+func deleteFromBrokers(brokers []addressDeleteBroker, deployments []*AddressDeployment, deadline time.Time) []error {
+	errs := make([]error, len(deployments))
+	timedOut := func() {
+		for i, deployment := range deployments {
+			if errs[i] == nil {
+				errs[i] = fmt.Errorf("timed out after %v deleting address %v", addressBulkDeleteTimeout, deployment.AddressResource.Spec.AddressName)
+			}
+		}
+	}
+	if !time.Now().Before(deadline) {
+		timedOut()
+		return errs
+	}
+
+	var mutex sync.Mutex
+	expired := false
+	var wg sync.WaitGroup
+	for _, broker := range brokers {
+		wg.Add(1)
+		go func(broker addressDeleteBroker) {
+			defer wg.Done()
+			brokerErrs := deleteAddressesFromBroker(broker, deployments)
+			mutex.Lock()
+			defer mutex.Unlock()
+			if expired {
+				return
+			}
+			for i, err := range brokerErrs {
+				if err != nil && errs[i] == nil {
+					errs[i] = err
+				}
+			}
+		}(broker)
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
 
-	queueName := ""
-	if instance.AddressResource.Spec.QueueName != nil {
-		queueName = *instance.AddressResource.Spec.QueueName
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+	select {
+	case <-done:
+	case <-timer.C:
+		mutex.Lock()
+		defer mutex.Unlock()
+		expired = true
+		timedOut()
 	}
+	return errs
+}
 
-	reqLogger.Info("Deleting ActiveMQArtemisAddress for queue " + addressName + "/" + queueName)
+// deleteAddressesFromBroker removes the deployments from one broker pod in two
+// bulk requests. The first removes the address settings, the queues and the
+// addresses without queues, the second the parent addresses of the removed
+// queues, which the broker keeps while other queues are bound to them. Anything
+// the broker reports as already gone counts as deleted.
+func deleteAddressesFromBroker(broker addressDeleteBroker, deployments []*AddressDeployment) []error {
+	errs := make([]error, len(deployments))
+	operations := []string{}
+	// the deployment each operation removes, the address settings are best effort
+	owners := []int{}
+	parents := []string{}
+	seen := map[string]bool{}
+	for i, deployment := range deployments {
+		addressRes := &deployment.AddressResource
+		policy := addressRemovalPolicy(addressRes)
+		if policy == brokerv1beta1.AddressRemovalPolicyOrphan {
+			continue
+		}
+		addressName := addressRes.Spec.AddressName
+		removeAddress := policy == brokerv1beta1.AddressRemovalPolicyRemoveQueueAndAddress
 
-	var err error = nil
-	artemisArray := getPodBrokers(instance, request, client, scheme)
-	if nil != artemisArray {
-		addressRetry := &AddressRetry{
-			address: addressName,
-			artemis: make([]*mgmt.Artemis, 0),
+		// the dead letter and expiry queues stay with the messages they hold
+		if removeAddress && hasAddressSettings(addressRes) {
+			operations = append(operations, broker.RemoveAddressSettingsOperation(addressName))
+			owners = append(owners, -1)
 		}
-		for _, a := range artemisArray {
-			if queueName == "" {
-				//delete address
-				_, err = a.Artemis.DeleteAddress(addressName)
-				if nil != err {
-					reqLogger.Error(err, "Deleting ActiveMQArtemisAddress error", "address", addressName)
-					break
-				}
-				reqLogger.Info("Deleted ActiveMQArtemisAddress for address " + addressName)
-			} else {
-				//delete queues
-				_, err = a.Artemis.DeleteQueue(queueName)
-				if nil != err {
-					reqLogger.Error(err, "Deleting ActiveMQArtemisAddress error for queue "+queueName)
-					break
-				} else {
-					addressRetry.addToDelete(a.Artemis)
-				}
+		queues := addressQueues(addressRes)
+		if len(queues) == 0 {
+			if removeAddress {
+				operations = append(operations, broker.DeleteAddressOperation(addressName))
+				owners = append(owners, i)
+			}
+			continue
+		}
+		for _, queue := range queues {
+			operations = append(operations, broker.DeleteQueueOperation(*queue.Spec.QueueName))
+			owners = append(owners, i)
+		}
+		if removeAddress && !seen[addressName] {
+			seen[addressName] = true
+			parents = append(parents, addressName)
+		}
+	}
+	if len(operations) == 0 {
+		return errs
+	}
+
+	responses, err := broker.ExecBulk(operations)
+	if err != nil {
+		for _, i := range owners {
+			if i >= 0 {
+				errs[i] = err
 			}
 		}
-		// we delete address after all queues are deleted
-		addressRetry.safeDelete()
-		reqLogger.Info("Deleted ActiveMQArtemisAddress for queue " + addressName + "/" + queueName)
+		return errs
+	}
+	for j, response := range responses {
+		i := owners[j]
+		if i < 0 || succeeded(response) || mgmt.IsNotFoundError(response) || errs[i] != nil {
+			continue
+		}
+		errs[i] = fmt.Errorf("unable to delete address %v: %v", deployments[i].AddressResource.Spec.AddressName, response.Error)
+	}
+
+	if len(parents) > 0 {
+		operations = []string{}
+		for _, name := range parents {
+			operations = append(operations, broker.DeleteAddressOperation(name))
+		}
+		if _, err := broker.ExecBulk(operations); err != nil {
+			glog.Error(err, "failed to remove the parent addresses", "addresses", parents)
+		}
 	}
+	return errs
+}
 
-	return err
+// addressRemovalPolicy is what deleting the CR removes from the brokers, removeFromBrokerOnDelete
+// decides when the CR has no removal policy
+func addressRemovalPolicy(addressRes *brokerv1beta1.ActiveMQArtemisAddress) string {
+	if addressRes.Spec.RemovalPolicy != "" {
+		return addressRes.Spec.RemovalPolicy
+	}
+	if addressRes.Spec.RemoveFromBrokerOnDelete {
+		return brokerv1beta1.AddressRemovalPolicyRemoveQueueAndAddress
+	}
+	return brokerv1beta1.AddressRemovalPolicyOrphan
+}
+
+// This method deals with deleting a queue, or a whole address when no queue is
+// given, from one broker. Anything the broker reports as already gone counts as
+// deleted. The parent address of a queue is removed once it has no bindings left,
+// unless the removal policy keeps the address.
+func deleteFromBroker(a *mgmt.Artemis, addressRes *brokerv1beta1.ActiveMQArtemisAddress) error {
+	addressName := addressRes.Spec.AddressName
+	policy := addressRemovalPolicy(addressRes)
+	if policy == brokerv1beta1.AddressRemovalPolicyOrphan {
+		return nil
+	}
+	removeAddress := policy == brokerv1beta1.AddressRemovalPolicyRemoveQueueAndAddress
+
+	// the dead letter and expiry queues stay with the messages they hold
+	if removeAddress && hasAddressSettings(addressRes) {
+		if respData, err := a.RemoveAddressSettings(addressName); err != nil {
+			glog.Error(err, "Failed to remove address settings", "address", addressName, "details", respData)
+		}
+	}
+
+	// each queue of the list goes on its own, the last one takes the address with it
+	if len(addressRes.Spec.Queues) > 0 {
+		for _, queue := range addressQueues(addressRes) {
+			if err := deleteFromBroker(a, queue); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if addressRes.Spec.QueueName == nil || *addressRes.Spec.QueueName == "" {
+		if !removeAddress {
+			glog.Info("Keeping address, the removal policy is "+policy, "address", addressName)
+			return nil
+		}
+		respData, err := a.DeleteAddress(addressName)
+		if err != nil && !mgmt.IsNotFoundError(respData) {
+			glog.Error(err, "Deleting ActiveMQArtemisAddress error", "address", addressName)
+			return err
+		}
+		glog.Info("Deleted ActiveMQArtemisAddress for address " + addressName)
+		return nil
+	}
+
+	queueName := *addressRes.Spec.QueueName
+	respData, err := a.DeleteQueue(queueName)
+	if err != nil && !mgmt.IsNotFoundError(respData) {
+		glog.Error(err, "Deleting ActiveMQArtemisAddress error for queue "+queueName)
+		return err
+	}
+
+	if !removeAddress {
+		glog.Info("Deleted ActiveMQArtemisAddress for queue " + addressName + "/" + queueName + ", keeping the address")
+		return nil
+	}
+
+	glog.Info("Checking parent address for bindings " + addressName)
+	bindingsData, err := a.ListBindingsForAddress(addressName)
+	if err != nil {
+		if !mgmt.IsNotFoundError(bindingsData) {
+			glog.Error(err, "failed to list bindings", "address", addressName)
+		}
+	} else if bindingsData.Value == "" {
+		glog.Info("No bindings found, removing " + addressName)
+		a.DeleteAddress(addressName)
+	} else {
+		glog.Info("Bindings found, not removing", "address", addressName, "bindings", bindingsData.Value)
+	}
+	glog.Info("Deleted ActiveMQArtemisAddress for queue " + addressName + "/" + queueName)
+	return nil
+}
+
+// deadLetterAddressName is the name of the dead letter address and queue created for the address,
+// empty without one
+func deadLetterAddressName(addressRes *brokerv1beta1.ActiveMQArtemisAddress) string {
+	provisioned := addressRes.Spec.DeadLetterAndExpiry
+	if provisioned == nil || !provisioned.DeadLetter {
+		return ""
+	}
+	if provisioned.DeadLetterSuffix == "" {
+		return addressRes.Spec.AddressName + brokerv1beta1.DefaultDeadLetterSuffix
+	}
+	return addressRes.Spec.AddressName + provisioned.DeadLetterSuffix
+}
+
+// expiryAddressName is the name of the expiry address and queue created for the address, empty
+// without one
+func expiryAddressName(addressRes *brokerv1beta1.ActiveMQArtemisAddress) string {
+	provisioned := addressRes.Spec.DeadLetterAndExpiry
+	if provisioned == nil || !provisioned.Expiry {
+		return ""
+	}
+	if provisioned.ExpirySuffix == "" {
+		return addressRes.Spec.AddressName + brokerv1beta1.DefaultExpirySuffix
+	}
+	return addressRes.Spec.AddressName + provisioned.ExpirySuffix
+}
+
+// deadLetterAndExpiryQueues are the queues created for the address, each an anycast queue on an
+// address of the same name. An existing queue is left as it is, it may hold messages
+func deadLetterAndExpiryQueues(addressRes *brokerv1beta1.ActiveMQArtemisAddress) []*brokerv1beta1.ActiveMQArtemisAddress {
+	queues := []*brokerv1beta1.ActiveMQArtemisAddress{}
+	for _, name := range []string{deadLetterAddressName(addressRes), expiryAddressName(addressRes)} {
+		if name == "" {
+			continue
+		}
+		queueName := name
+		routingType := "anycast"
+		ignoreIfExists := true
+		queue := &brokerv1beta1.ActiveMQArtemisAddress{}
+		queue.Spec.AddressName = name
+		queue.Spec.QueueName = &queueName
+		queue.Spec.RoutingType = &routingType
+		queue.Spec.QueueConfiguration = &brokerv1beta1.QueueConfigurationType{IgnoreIfExists: &ignoreIfExists}
+		queues = append(queues, queue)
+	}
+	return queues
+}
+
+func hasAddressSettings(addressRes *brokerv1beta1.ActiveMQArtemisAddress) bool {
+	return addressRes.Spec.Redelivery != nil || deadLetterAddressName(addressRes) != "" || expiryAddressName(addressRes) != ""
+}
+
+// addressSettings is the json of the address settings of an address CR, the redelivery policy with
+// the dead letter and expiry addresses that are created for it
+func addressSettings(addressRes *brokerv1beta1.ActiveMQArtemisAddress) (string, error) {
+	settings := map[string]interface{}{}
+	if addressRes.Spec.Redelivery != nil {
+		settings = redeliverySettings(addressRes.Spec.Redelivery)
+	}
+	if name := deadLetterAddressName(addressRes); name != "" {
+		settings["deadLetterAddress"] = name
+	}
+	if name := expiryAddressName(addressRes); name != "" {
+		settings["expiryAddress"] = name
+	}
+	return addressSettingsJson(settings)
+}
+
+// redeliveryAddressSettings is the json of the address settings of a policy
+func redeliveryAddressSettings(policy *brokerv1beta1.RedeliveryPolicyType) (string, error) {
+	return addressSettingsJson(redeliverySettings(policy))
+}
+
+// addressSettingsJson is the json of address settings for the management api, which knows the dead
+// letter address as DLA
+func addressSettingsJson(settings map[string]interface{}) (string, error) {
+	if address, found := settings["deadLetterAddress"]; found {
+		delete(settings, "deadLetterAddress")
+		settings["DLA"] = address
+	}
+	bytes, err := json.Marshal(settings)
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
+}
+
+// the settings of a policy removed from an Address CR would otherwise stay until the address is deleted
+func removeAddressSettingsFromBrokers(instance *AddressDeployment, request ctrl.Request, client client.Client, scheme *runtime.Scheme) {
+	addressName := instance.AddressResource.Spec.AddressName
+	for _, a := range getPodBrokers(instance, request, client, scheme) {
+		if a == nil {
+			continue
+		}
+		if respData, err := a.Artemis.RemoveAddressSettings(addressName); err != nil {
+			glog.Error(err, "Failed to remove address settings", "address", addressName, "broker", a.IP, "details", respData)
+		}
+	}
 }
 
 func getPodBrokers(instance *AddressDeployment, request ctrl.Request, client client.Client, scheme *runtime.Scheme) []*jc.JkInfo {
 	reqLogger := ctrl.Log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
 	reqLogger.Info("Getting Pod Brokers", "instance", instance)
-	targetCrNamespacedNames := createTargetCrNamespacedNames(request.Namespace, instance.AddressResource.Spec.ApplyToCrNames)
+	targetCrNamespacedNames, err := targetBrokerCrNames(client, request.Namespace, instance.AddressResource.Spec.ApplyToCrNames, instance.AddressResource.Spec.ApplyToCrSelector)
+	if err != nil {
+		reqLogger.Error(err, "failed to list the brokers of applyToCrSelector")
+		return nil
+	}
 	reqLogger.Info("target Cr names", "result", targetCrNamespacedNames)
+	if targetCrNamespacedNames != nil && len(targetCrNamespacedNames) == 0 {
+		// the selector selects no broker, an empty filter would select them all
+		return nil
+	}
 	ssInfos := ss.GetDeployedStatefulSetNames(client, targetCrNamespacedNames)
 
 	return jc.GetBrokers(request.NamespacedName, ssInfos, client)
@@ -21,14 +21,24 @@ package controllers
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
+	"testing"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -39,13 +49,19 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 
 	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	mgmt "github.com/artemiscloud/activemq-artemis-operator/pkg/utils/artemis"
 	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/common"
 	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/jolokia"
+	jc "github.com/artemiscloud/activemq-artemis-operator/pkg/utils/jolokia_client"
 	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/namer"
+	"github.com/stretchr/testify/assert"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/remotecommand"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 var _ = Describe("Address controller tests", func() {
@@ -1168,3 +1184,426 @@ func CheckQueueAttribute(brokerCrName string, podName string, namespace string,
 
 	}, existingClusterTimeout, existingClusterInterval).Should(Succeed())
 }
+
+func TestAddressRemovalPolicy(t *testing.T) {
+	address := &brokerv1beta1.ActiveMQArtemisAddress{}
+	assert.Equal(t, brokerv1beta1.AddressRemovalPolicyOrphan, addressRemovalPolicy(address))
+	address.Spec.RemoveFromBrokerOnDelete = true
+	assert.Equal(t, brokerv1beta1.AddressRemovalPolicyRemoveQueueAndAddress, addressRemovalPolicy(address))
+
+	// the policy wins over removeFromBrokerOnDelete
+	address.Spec.RemovalPolicy = brokerv1beta1.AddressRemovalPolicyRemoveQueue
+	assert.Equal(t, brokerv1beta1.AddressRemovalPolicyRemoveQueue, addressRemovalPolicy(address))
+	address.Spec.RemovalPolicy = brokerv1beta1.AddressRemovalPolicyOrphan
+	assert.Equal(t, brokerv1beta1.AddressRemovalPolicyOrphan, addressRemovalPolicy(address))
+	assert.NoError(t, deleteFromBroker(nil, address))
+}
+
+func TestAddressPodStatus(t *testing.T) {
+	applied := metav1.NewTime(time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC))
+	later := metav1.NewTime(applied.Add(time.Minute))
+
+	pods := addressPodStatuses(nil, map[string]error{"ex-aao-ss-1": nil, "ex-aao-ss-0": nil}, 1, applied)
+	assert.Equal(t, []brokerv1beta1.AddressPodStatus{
+		{PodName: "ex-aao-ss-0", State: brokerv1beta1.AddressPodStateApplied, ObservedGeneration: 1, LastTransitionTime: applied},
+		{PodName: "ex-aao-ss-1", State: brokerv1beta1.AddressPodStateApplied, ObservedGeneration: 1, LastTransitionTime: applied},
+	}, pods)
+
+	// a pod that fails keeps the generation it applied, one that is unchanged keeps its time
+	podErrors := map[string]error{"ex-aao-ss-0": nil, "ex-aao-ss-2": fmt.Errorf("AMQ229019 queue exists")}
+	pods = addressPodStatuses(pods, podErrors, 2, later)
+	assert.Equal(t, []brokerv1beta1.AddressPodStatus{
+		{PodName: "ex-aao-ss-0", State: brokerv1beta1.AddressPodStateApplied, ObservedGeneration: 2, LastTransitionTime: later},
+		{PodName: "ex-aao-ss-2", State: brokerv1beta1.AddressPodStateFailed, Message: "AMQ229019 queue exists", LastTransitionTime: later},
+	}, pods)
+	assert.Equal(t, pods, addressPodStatuses(pods, podErrors, 2, metav1.Now()))
+
+	err := failedPodsError(podErrors)
+	assert.EqualError(t, err, "failed on ex-aao-ss-2: AMQ229019 queue exists")
+	assert.NoError(t, failedPodsError(map[string]error{"ex-aao-ss-0": nil}))
+
+	testScheme := runtime.NewScheme()
+	assert.NoError(t, brokerv1beta1.AddToScheme(testScheme))
+	address := &brokerv1beta1.ActiveMQArtemisAddress{ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "test", Generation: 2}}
+	r := &ActiveMQArtemisAddressReconciler{Client: fake.NewClientBuilder().WithScheme(testScheme).WithObjects(address).Build(), Scheme: testScheme}
+	assert.NoError(t, r.updateAddressStatus(context.TODO(), address, nil, podErrors, err))
+	assert.Len(t, address.Status.Pods, 2)
+	condition := meta.FindStatusCondition(address.Status.Conditions, brokerv1beta1.AddressAppliedConditionType)
+	assert.Equal(t, "failed on ex-aao-ss-2: AMQ229019 queue exists", condition.Message)
+
+	// the pods stay as they were when the address wasn't tried
+	assert.NoError(t, r.updateAddressStatus(context.TODO(), address, nil, nil, fmt.Errorf("unable to remove the previous queue")))
+	assert.Len(t, address.Status.Pods, 2)
+}
+
+func TestAddressDeployedCondition(t *testing.T) {
+	condition := addressDeployedCondition(map[string]error{}, 1)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, brokerv1beta1.AddressDeployedNoBrokersReason, condition.Reason)
+
+	condition = addressDeployedCondition(map[string]error{"ex-aao-ss-0": nil, "ex-aao-ss-1": nil}, 1)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, brokerv1beta1.AddressDeployedSuccessReason, condition.Reason)
+
+	refused := &url.Error{Op: "Post", URL: "http://10.0.0.2:8161/console/jolokia", Err: &net.OpError{Op: "dial", Net: "tcp", Err: fmt.Errorf("connection refused")}}
+	condition = addressDeployedCondition(map[string]error{"ex-aao-ss-0": nil, "ex-aao-ss-1": refused}, 1)
+	assert.Equal(t, brokerv1beta1.AddressDeployedUnreachableReason, condition.Reason)
+	assert.Contains(t, condition.Message, "failed on ex-aao-ss-1")
+
+	unauthorized := &jolokia.JolokiaError{HttpCode: 401, Message: " Error: 401 Unauthorized"}
+	condition = addressDeployedCondition(map[string]error{"ex-aao-ss-0": unauthorized, "ex-aao-ss-1": refused}, 1)
+	assert.Equal(t, brokerv1beta1.AddressDeployedUnauthorizedReason, condition.Reason)
+
+	condition = addressDeployedCondition(map[string]error{"ex-aao-ss-0": fmt.Errorf("AMQ229209: Can't remove routing type")}, 1)
+	assert.Equal(t, brokerv1beta1.AddressDeployedApplyFailedReason, condition.Reason)
+}
+
+func TestDeadLetterAndExpiry(t *testing.T) {
+	address := &brokerv1beta1.ActiveMQArtemisAddress{}
+	address.Spec.AddressName = "orders"
+	assert.False(t, hasAddressSettings(address))
+	assert.Empty(t, deadLetterAndExpiryQueues(address))
+
+	address.Spec.DeadLetterAndExpiry = &brokerv1beta1.DeadLetterAndExpiryType{DeadLetter: true, Expiry: true, ExpirySuffix: ".expired"}
+	assert.True(t, hasAddressSettings(address))
+	queues := deadLetterAndExpiryQueues(address)
+	assert.Len(t, queues, 2)
+	assert.Equal(t, "orders.DLQ", queues[0].Spec.AddressName)
+	assert.Equal(t, "orders.DLQ", *queues[0].Spec.QueueName)
+	assert.Equal(t, "ANYCAST", queueRoutingType(queues[0]))
+	assert.True(t, *queues[0].Spec.QueueConfiguration.IgnoreIfExists)
+	assert.Equal(t, "orders.expired", queues[1].Spec.AddressName)
+
+	settings, err := addressSettings(address)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"DLA":"orders.DLQ","expiryAddress":"orders.expired"}`, settings)
+
+	// the redelivery policy goes with the addresses
+	attempts := int32(3)
+	address.Spec.Redelivery = &brokerv1beta1.RedeliveryPolicyType{MaxDeliveryAttempts: &attempts}
+	settings, err = addressSettings(address)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"DLA":"orders.DLQ","expiryAddress":"orders.expired","maxDeliveryAttempts":3}`, settings)
+	assert.NoError(t, address.Spec.DeadLetterAndExpiry.Validate("deadLetterAndExpiry", address.Spec.Redelivery))
+
+	for _, invalid := range []brokerv1beta1.DeadLetterAndExpiryType{
+		{DeadLetter: true, DeadLetterSuffix: ".#"},
+		{DeadLetter: true, Expiry: true, DeadLetterSuffix: ".ExpiryQueue"},
+	} {
+		assert.Error(t, invalid.Validate("deadLetterAndExpiry", nil), invalid)
+	}
+	address.Spec.Redelivery.DeadLetterAddress = "DLQ"
+	assert.Error(t, address.Spec.DeadLetterAndExpiry.Validate("deadLetterAndExpiry", address.Spec.Redelivery))
+}
+
+func TestAddressValidation(t *testing.T) {
+	queue := "orders.eu"
+	anycast := "anycast"
+	valid := brokerv1beta1.ActiveMQArtemisAddressSpec{AddressName: "orders", QueueName: &queue, RoutingType: &anycast}
+	assert.NoError(t, valid.Validate())
+
+	blank := " "
+	wildcard := "orders.*"
+	quoted := `orders"eu`
+	broadcast := "broadcast"
+	multicast := "multicast"
+	for _, invalid := range []brokerv1beta1.ActiveMQArtemisAddressSpec{
+		{},
+		{AddressName: " "},
+		{AddressName: "orders.#"},
+		{AddressName: "orders::eu"},
+		{AddressName: "orders", QueueName: &blank},
+		{AddressName: "orders", QueueName: &wildcard},
+		{AddressName: "orders", QueueName: &quoted},
+		{AddressName: "orders", RoutingType: &broadcast},
+		{AddressName: "orders", QueueConfiguration: &brokerv1beta1.QueueConfigurationType{}},
+		{AddressName: "orders", QueueName: &queue, RoutingType: &anycast, QueueConfiguration: &brokerv1beta1.QueueConfigurationType{RoutingType: &multicast}},
+	} {
+		assert.Error(t, invalid.Validate(), invalid)
+	}
+
+	// an update is only checked on the fields it changes, a stored value the webhook rejects now is kept
+	storedInvalid := &brokerv1beta1.ActiveMQArtemisAddress{ObjectMeta: metav1.ObjectMeta{Name: "legacy", Namespace: "test"}, Spec: brokerv1beta1.ActiveMQArtemisAddressSpec{AddressName: "orders.#"}}
+	updated := storedInvalid.DeepCopy()
+	updated.Spec.RemoveFromBrokerOnDelete = true
+	assert.NoError(t, updated.ValidateUpdate(storedInvalid))
+	updated.Spec.QueueName = &wildcard
+	assert.EqualError(t, updated.ValidateUpdate(storedInvalid), `queueName "orders.*" can't have wildcards, quotes, backslashes, spaces or ::`)
+
+	// another CR of the namespace that creates the same queue, or the address with another routing type,
+	// the CRs are read from the api server so a restarted operator sees them all
+	existing := &brokerv1beta1.ActiveMQArtemisAddress{ObjectMeta: metav1.ObjectMeta{Name: "orders-eu", Namespace: "test"}, Spec: valid}
+	webhookClient := fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithObjects(existing).Build()
+	brokerv1beta1.SetWebhookClient(webhookClient)
+	defer brokerv1beta1.SetWebhookClient(nil)
+
+	address := &brokerv1beta1.ActiveMQArtemisAddress{ObjectMeta: metav1.ObjectMeta{Name: "orders-eu", Namespace: "test"}, Spec: valid}
+	assert.NoError(t, address.ValidateUpdate(address))
+
+	address.Name = "copy"
+	assert.EqualError(t, address.ValidateCreate(), "queue orders.eu on address orders is already created by Address CR orders-eu")
+	address.Spec.AddressName = "orders.v2"
+	assert.EqualError(t, address.ValidateCreate(), "queue orders.eu is on address orders in Address CR orders-eu, a queue name is unique on a broker")
+
+	address.Spec = brokerv1beta1.ActiveMQArtemisAddressSpec{AddressName: "orders", RoutingType: &multicast}
+	assert.EqualError(t, address.ValidateCreate(), "address orders is ANYCAST in Address CR orders-eu, not MULTICAST")
+	address.Spec.RoutingType = &anycast
+	assert.NoError(t, address.ValidateCreate())
+
+	// the same queue on other brokers, or in another namespace
+	address.Spec = valid
+	address.Spec.ApplyToCrNames = []string{"other"}
+	existing.Spec = brokerv1beta1.ActiveMQArtemisAddressSpec{AddressName: "orders", QueueName: &queue, ApplyToCrNames: []string{"broker"}}
+	assert.NoError(t, webhookClient.Update(context.TODO(), existing))
+	assert.NoError(t, address.ValidateCreate())
+	address.Spec.ApplyToCrNames = nil
+	assert.Error(t, address.ValidateCreate())
+	address.Namespace = "other"
+	assert.NoError(t, address.ValidateCreate())
+
+	// the controller reports a CR that was admitted without the webhook
+	testScheme := runtime.NewScheme()
+	assert.NoError(t, brokerv1beta1.AddToScheme(testScheme))
+	address = &brokerv1beta1.ActiveMQArtemisAddress{ObjectMeta: metav1.ObjectMeta{Name: "unnamed", Namespace: "test"}}
+	r := &ActiveMQArtemisAddressReconciler{Client: fake.NewClientBuilder().WithScheme(testScheme).WithObjects(address).Build(), Scheme: testScheme}
+	_, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "unnamed", Namespace: "test"}})
+	assert.NoError(t, err)
+	stored := &brokerv1beta1.ActiveMQArtemisAddress{}
+	assert.NoError(t, r.Client.Get(context.TODO(), types.NamespacedName{Name: "unnamed", Namespace: "test"}, stored))
+	condition := meta.FindStatusCondition(stored.Status.Conditions, brokerv1beta1.ValidConditionType)
+	assert.Equal(t, brokerv1beta1.ValidConditionInvalidAddressReason, condition.Reason)
+	assert.Equal(t, "addressName can't be empty", condition.Message)
+}
+
+func TestTemporaryAndAutoDeleteQueues(t *testing.T) {
+	queue := "replies"
+	temporary := true
+	autoDelete := true
+	delay := int64(30000)
+	count := int64(0)
+	address := &brokerv1beta1.ActiveMQArtemisAddress{
+		Spec: brokerv1beta1.ActiveMQArtemisAddressSpec{AddressName: "replies", QueueName: &queue, QueueConfiguration: &brokerv1beta1.QueueConfigurationType{
+			Temporary: &temporary, AutoDelete: &autoDelete, AutoDeleteDelay: &delay, AutoDeleteMessageCount: &count,
+		}},
+	}
+	assert.NoError(t, address.Spec.Validate())
+
+	// a temporary queue isn't durable unless it says so, the broker would make it durable
+	defaultQueueConfiguration(address)
+	queueCfg, _, err := GetQueueConfig(address)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"name":"replies","address":"replies","routing-type":"MULTICAST","durable":false,"temporary":true,"auto-delete":true,"auto-delete-delay":30000,"auto-delete-message-count":0,"configuration-managed":true}`, queueCfg)
+
+	durable := true
+	notAutoDeleted := false
+	for _, invalid := range []brokerv1beta1.QueueConfigurationType{
+		{Temporary: &temporary, Durable: &durable},
+		{AutoDelete: &notAutoDeleted, AutoDeleteDelay: &delay},
+		{AutoDelete: &notAutoDeleted, AutoDeleteMessageCount: &count},
+	} {
+		assert.Error(t, invalid.Validate("queueConfiguration"), invalid)
+	}
+	assert.NoError(t, (&brokerv1beta1.QueueConfigurationType{AutoDeleteDelay: &delay}).Validate("queueConfiguration"))
+}
+
+func TestRecreatedAddressSkipsAutoDeletedQueues(t *testing.T) {
+	// the agent records the operations the address is created with
+	operations := []string{}
+	agent := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := map[string]interface{}{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		operations = append(operations, fmt.Sprintf("%v %v", request["operation"], request["arguments"]))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status":200,"value":""}`))
+	}))
+	defer agent.Close()
+	host, port, err := net.SplitHostPort(agent.Listener.Addr().String())
+	assert.NoError(t, err)
+	jk := &jc.JkInfo{Artemis: mgmt.NewArtemis(host, port, "amq-broker", "admin", "admin"), PodName: "broker-ss-0"}
+
+	autoDelete := true
+	address := &brokerv1beta1.ActiveMQArtemisAddress{
+		ObjectMeta: metav1.ObjectMeta{Name: "replies", Namespace: "test", Generation: 2},
+		Spec: brokerv1beta1.ActiveMQArtemisAddressSpec{AddressName: "replies", Queues: []brokerv1beta1.AddressQueueType{
+			{Name: "replies.web", QueueConfigurationType: brokerv1beta1.QueueConfigurationType{AutoDelete: &autoDelete}},
+			{Name: "replies.audit"},
+		}},
+	}
+	created := func() []string {
+		operations = []string{}
+		assert.NoError(t, createAddressResource(jk, address, appliedOnPod(address, jk.PodName)))
+		queues := []string{}
+		for _, operation := range operations {
+			if strings.HasPrefix(operation, "createQueue") {
+				queues = append(queues, operation)
+			}
+		}
+		return queues
+	}
+
+	// a pod that doesn't have the generation gets every queue
+	assert.Len(t, created(), 2)
+	address.Status.Pods = []brokerv1beta1.AddressPodStatus{{PodName: "broker-ss-0", State: brokerv1beta1.AddressPodStateApplied, ObservedGeneration: 1}}
+	assert.Len(t, created(), 2)
+
+	// a queue the broker deleted isn't brought back by a resync or a restart of the pod
+	address.Status.Pods[0].ObservedGeneration = 2
+	queues := created()
+	assert.Len(t, queues, 1)
+	assert.Contains(t, queues[0], "replies.audit")
+
+	queueName := "replies.web"
+	address.Spec = brokerv1beta1.ActiveMQArtemisAddressSpec{AddressName: "replies", QueueName: &queueName, QueueConfiguration: &brokerv1beta1.QueueConfigurationType{AutoDelete: &autoDelete}}
+	assert.Empty(t, created())
+	assert.Len(t, operations, 1)
+	assert.True(t, strings.HasPrefix(operations[0], "createAddress"), operations[0])
+}
+
+// fakeAddressDeleteBroker answers the bulk requests of a pod, the names it misses are not found and
+// the failing ones are refused. With a release channel a request waits for it to be closed
+type fakeAddressDeleteBroker struct {
+	mutex    sync.Mutex
+	requests [][]string
+	missing  map[string]bool
+	failing  map[string]bool
+	release  chan struct{}
+}
+
+func (b *fakeAddressDeleteBroker) RemoveAddressSettingsOperation(addressMatch string) string {
+	return "removeAddressSettings " + addressMatch
+}
+
+func (b *fakeAddressDeleteBroker) DeleteQueueOperation(queueName string) string {
+	return "deleteQueue " + queueName
+}
+
+func (b *fakeAddressDeleteBroker) DeleteAddressOperation(addressName string) string {
+	return "deleteAddress " + addressName
+}
+
+func (b *fakeAddressDeleteBroker) ExecBulk(operations []string) ([]*jolokia.ResponseData, error) {
+	if b.release != nil {
+		<-b.release
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.requests = append(b.requests, operations)
+	responses := []*jolokia.ResponseData{}
+	for _, operation := range operations {
+		name := operation[strings.Index(operation, " ")+1:]
+		switch {
+		case b.missing[name]:
+			responses = append(responses, &jolokia.ResponseData{Status: 500, Error: mgmt.QUEUE_DOES_NOT_EXIST + ": no queue " + name})
+		case b.failing[name]:
+			responses = append(responses, &jolokia.ResponseData{Status: 500, Error: "AMQ229000: refused"})
+		default:
+			responses = append(responses, &jolokia.ResponseData{Status: 200})
+		}
+	}
+	return responses, nil
+}
+
+func TestDeletePendingAddresses(t *testing.T) {
+	queue := func(name string) *string { return &name }
+	deployment := func(name string, applyTo string, address brokerv1beta1.ActiveMQArtemisAddressSpec) AddressDeployment {
+		address.ApplyToCrNames = []string{applyTo}
+		return AddressDeployment{AddressResource: brokerv1beta1.ActiveMQArtemisAddress{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"}, Spec: address}}
+	}
+	tracked := map[types.NamespacedName]AddressDeployment{
+		{Name: "orders", Namespace: "test"}:    deployment("orders", "east", brokerv1beta1.ActiveMQArtemisAddressSpec{AddressName: "orders", QueueName: queue("orders"), RemovalPolicy: brokerv1beta1.AddressRemovalPolicyRemoveQueue}),
+		{Name: "invoices", Namespace: "test"}:  deployment("invoices", "east", brokerv1beta1.ActiveMQArtemisAddressSpec{AddressName: "invoices", QueueName: queue("invoices"), RemovalPolicy: brokerv1beta1.AddressRemovalPolicyRemoveQueue}),
+		{Name: "payments", Namespace: "test"}:  deployment("payments", "east", brokerv1beta1.ActiveMQArtemisAddressSpec{AddressName: "payments", QueueName: queue("payments"), RemovalPolicy: brokerv1beta1.AddressRemovalPolicyRemoveQueue}),
+		{Name: "events", Namespace: "test"}:    deployment("events", "west", brokerv1beta1.ActiveMQArtemisAddressSpec{AddressName: "events", RemovalPolicy: brokerv1beta1.AddressRemovalPolicyRemoveQueueAndAddress}),
+		{Name: "audit", Namespace: "test"}:     deployment("audit", "west", brokerv1beta1.ActiveMQArtemisAddressSpec{AddressName: "audit"}),
+		{Name: "live", Namespace: "test"}:      deployment("live", "east", brokerv1beta1.ActiveMQArtemisAddressSpec{AddressName: "live", RemovalPolicy: brokerv1beta1.AddressRemovalPolicyRemoveQueueAndAddress}),
+		{Name: "orders", Namespace: "other"}:   deployment("orders", "east", brokerv1beta1.ActiveMQArtemisAddressSpec{AddressName: "orders", RemovalPolicy: brokerv1beta1.AddressRemovalPolicyRemoveQueueAndAddress}),
+		{Name: "archive", Namespace: "other"}:  deployment("archive", "west", brokerv1beta1.ActiveMQArtemisAddressSpec{AddressName: "archive", RemovalPolicy: brokerv1beta1.AddressRemovalPolicyRemoveQueueAndAddress}),
+		{Name: "shipments", Namespace: "test"}: deployment("shipments", "west", brokerv1beta1.ActiveMQArtemisAddressSpec{AddressName: "shipments", QueueName: queue("shipments"), RemovalPolicy: brokerv1beta1.AddressRemovalPolicyRemoveQueueAndAddress}),
+	}
+	previous := namespacedNameToAddressName
+	namespacedNameToAddressName = tracked
+	defer func() { namespacedNameToAddressName = previous }()
+
+	// the orders queue is gone already, the broker refuses to remove the payments queue
+	east := []*fakeAddressDeleteBroker{
+		{missing: map[string]bool{"orders": true}, failing: map[string]bool{"payments": true}},
+		{missing: map[string]bool{"orders": true}},
+	}
+	west := []*fakeAddressDeleteBroker{{}}
+	previousBrokers := addressDeleteBrokers
+	addressDeleteBrokers = func(deployment *AddressDeployment, request ctrl.Request, client client.Client, scheme *runtime.Scheme) []addressDeleteBroker {
+		brokers := []addressDeleteBroker{}
+		group := east
+		if deployment.AddressResource.Spec.ApplyToCrNames[0] == "west" {
+			group = west
+		}
+		for _, broker := range group {
+			brokers = append(brokers, broker)
+		}
+		return brokers
+	}
+	defer func() { addressDeleteBrokers = previousBrokers }()
+
+	live := tracked[types.NamespacedName{Name: "live", Namespace: "test"}].AddressResource
+	r := &ActiveMQArtemisAddressReconciler{Client: fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithObjects(&live).Build(), Scheme: newTestScheme(t)}
+
+	// the addresses of the request namespace that have gone, the request itself always
+	pending := r.pendingAddressDeletes(ctrl.Request{NamespacedName: types.NamespacedName{Name: "live", Namespace: "test"}})
+	names := []string{}
+	for nn := range pending {
+		names = append(names, nn.Name)
+	}
+	sort.Strings(names)
+	assert.Equal(t, []string{"audit", "events", "invoices", "live", "orders", "payments", "shipments"}, names)
+
+	assert.NoError(t, r.deletePendingAddresses(ctrl.Request{NamespacedName: types.NamespacedName{Name: "orders", Namespace: "test"}}))
+
+	// one bulk request per pod for each group of brokers, the orphaned address isn't sent
+	for _, broker := range east {
+		assert.Equal(t, [][]string{{"deleteQueue invoices", "deleteQueue orders", "deleteQueue payments"}}, broker.requests)
+	}
+	assert.Equal(t, [][]string{{"deleteAddress events", "deleteQueue shipments"}, {"deleteAddress shipments"}}, west[0].requests)
+
+	// the address that failed on a pod stays tracked for its own reconcile, the other namespace is untouched
+	remaining := []string{}
+	for nn := range namespacedNameToAddressName {
+		remaining = append(remaining, nn.String())
+	}
+	sort.Strings(remaining)
+	assert.Equal(t, []string{"other/archive", "other/orders", "test/live", "test/payments"}, remaining)
+
+	err := r.deletePendingAddresses(ctrl.Request{NamespacedName: types.NamespacedName{Name: "payments", Namespace: "test"}})
+	assert.ErrorContains(t, err, "unable to delete address payments")
+	assert.Contains(t, namespacedNameToAddressName, types.NamespacedName{Name: "payments", Namespace: "test"})
+}
+
+func TestDeleteFromBrokersDeadline(t *testing.T) {
+	queueName := "orders"
+	deployments := []*AddressDeployment{{AddressResource: brokerv1beta1.ActiveMQArtemisAddress{Spec: brokerv1beta1.ActiveMQArtemisAddressSpec{AddressName: "orders", QueueName: &queueName, RemovalPolicy: brokerv1beta1.AddressRemovalPolicyRemoveQueue}}}}
+
+	// nothing is sent once the deadline has passed
+	idle := &fakeAddressDeleteBroker{}
+	errs := deleteFromBrokers([]addressDeleteBroker{idle}, deployments, time.Now())
+	assert.ErrorContains(t, errs[0], "timed out")
+	assert.Empty(t, idle.requests)
+
+	// a pod that doesn't answer in time fails the deletion even though another one did
+	answering := &fakeAddressDeleteBroker{}
+	hanging := &fakeAddressDeleteBroker{release: make(chan struct{})}
+	defer close(hanging.release)
+	started := time.Now()
+	errs = deleteFromBrokers([]addressDeleteBroker{answering, hanging}, deployments, time.Now().Add(100*time.Millisecond))
+	assert.Less(t, time.Since(started), 5*time.Second)
+	assert.ErrorContains(t, errs[0], "timed out")
+	assert.Len(t, answering.requests, 1)
+
+	// the address settings and the address go with the last queue
+	deployments[0].AddressResource.Spec.RemovalPolicy = brokerv1beta1.AddressRemovalPolicyRemoveQueueAndAddress
+	deployments[0].AddressResource.Spec.QueueName = nil
+	deployments[0].AddressResource.Spec.Queues = []brokerv1beta1.AddressQueueType{{Name: "orders.eu"}, {Name: "orders.us"}}
+	deployments[0].AddressResource.Spec.DeadLetterAndExpiry = &brokerv1beta1.DeadLetterAndExpiryType{DeadLetter: true}
+	broker := &fakeAddressDeleteBroker{failing: map[string]bool{"orders": true}}
+	errs = deleteFromBrokers([]addressDeleteBroker{broker}, deployments, time.Now().Add(time.Minute))
+	assert.NoError(t, errs[0])
+	assert.Equal(t, [][]string{{"removeAddressSettings orders", "deleteQueue orders.eu", "deleteQueue orders.us"}, {"deleteAddress orders"}}, broker.requests)
+}
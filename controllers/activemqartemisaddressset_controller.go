@@ -0,0 +1,519 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	mgmt "github.com/artemiscloud/activemq-artemis-operator/pkg/utils/artemis"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/common"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/jolokia"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	"sigs.k8s.io/yaml"
+)
+
+var asetlog = ctrl.Log.WithName("controller_v1beta1activemqartemisaddressset")
+
+const addressSetFinalizer = "broker.amq.io/addressset"
+
+// the number of operations sent to a broker in one request when the CR doesn't say
+const defaultAddressSetBatchSize = 100
+
+// the management operations an address set needs from a broker pod
+type addressSetBroker interface {
+	ListAddresses() ([]string, error)
+	CreateAddressOperation(addressName string, routingType string) string
+	CreateQueueFromConfigOperation(queueConfig string, ignoreIfExists bool) string
+	UpdateQueueOperation(queueConfig string) string
+	DeleteQueueOperation(queueName string) string
+	DeleteAddressOperation(addressName string) string
+	ExecBulk(operations []string) ([]*jolokia.ResponseData, error)
+}
+
+// addressSetEntry is an address, or a queue on an address, of the ConfigMap of an address set
+type addressSetEntry struct {
+	AddressName        string                                `json:"addressName"`
+	QueueName          string                                `json:"queueName,omitempty"`
+	RoutingType        string                                `json:"routingType,omitempty"`
+	QueueConfiguration *brokerv1beta1.QueueConfigurationType `json:"queueConfiguration,omitempty"`
+}
+
+// key is how the status names the entry, the fully qualified name of a queue
+func (e *addressSetEntry) key() string {
+	if e.QueueName == "" {
+		return e.AddressName
+	}
+	return e.AddressName + "::" + e.QueueName
+}
+
+// address is the entry as an Address CR, for the helpers that configure the queues of Address CRs
+func (e *addressSetEntry) address() *brokerv1beta1.ActiveMQArtemisAddress {
+	address := &brokerv1beta1.ActiveMQArtemisAddress{}
+	address.Spec.AddressName = e.AddressName
+	if e.QueueName != "" {
+		queueName := e.QueueName
+		address.Spec.QueueName = &queueName
+	}
+	if e.RoutingType != "" {
+		routingType := e.RoutingType
+		address.Spec.RoutingType = &routingType
+	}
+	address.Spec.QueueConfiguration = e.QueueConfiguration.DeepCopy()
+	return address
+}
+
+func addressSetEntryFromKey(key string) addressSetEntry {
+	parts := strings.SplitN(key, "::", 2)
+	if len(parts) == 1 {
+		return addressSetEntry{AddressName: key}
+	}
+	return addressSetEntry{AddressName: parts[0], QueueName: parts[1]}
+}
+
+// ActiveMQArtemisAddressSetReconciler reconciles a ActiveMQArtemisAddressSet object
+type ActiveMQArtemisAddressSetReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=broker.amq.io,namespace=activemq-artemis-operator,resources=activemqartemisaddresssets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=broker.amq.io,namespace=activemq-artemis-operator,resources=activemqartemisaddresssets/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=broker.amq.io,namespace=activemq-artemis-operator,resources=activemqartemisaddresssets/finalizers,verbs=update
+
+// Reconcile creates the addresses and queues of the ConfigMap on every running pod of the selected
+// brokers, in batches of operations. After a change to the CR or to the ConfigMap every pod gets the
+// entries again, an existing queue is updated. Otherwise a pod gets them when it is new or has lost
+// some of the addresses. Entries dropped from the ConfigMap are removed as the removal policy says
+func (r *ActiveMQArtemisAddressSetReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	reqLogger := ctrl.LoggerFrom(ctx)
+
+	set := &brokerv1beta1.ActiveMQArtemisAddressSet{}
+	if err := r.Client.Get(ctx, request.NamespacedName, set); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if !set.DeletionTimestamp.IsZero() {
+		if controllerutil.ContainsFinalizer(set, addressSetFinalizer) {
+			for pod, broker := range r.brokers(set) {
+				if err := removeAddressSetEntries(broker, set.Status.Applied, set.Spec.RemovalPolicy, addressSetBatchSize(set), nil); err != nil {
+					podLogger(ctx, pod).Info("unable to remove the addresses of the set", "error", err.Error())
+				}
+			}
+			controllerutil.RemoveFinalizer(set, addressSetFinalizer)
+			return ctrl.Result{}, r.Client.Update(ctx, set)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	entries, configMapVersion, err := r.entries(ctx, set)
+	if err != nil {
+		recordEvent(ctx, r.Recorder, set, corev1.EventTypeWarning, brokerv1beta1.ValidConditionInvalidAddressSetReason, err.Error())
+		meta.SetStatusCondition(&set.Status.Conditions, metav1.Condition{
+			Type:    brokerv1beta1.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  brokerv1beta1.ValidConditionInvalidAddressSetReason,
+			Message: err.Error(),
+		})
+		// a missing ConfigMap may be created later
+		return ctrl.Result{RequeueAfter: common.GetReconcileResyncPeriod()}, r.Client.Status().Update(ctx, set)
+	}
+	meta.SetStatusCondition(&set.Status.Conditions, metav1.Condition{
+		Type:   brokerv1beta1.ValidConditionType,
+		Status: metav1.ConditionTrue,
+		Reason: brokerv1beta1.ValidConditionSuccessReason,
+	})
+
+	if !controllerutil.ContainsFinalizer(set, addressSetFinalizer) {
+		controllerutil.AddFinalizer(set, addressSetFinalizer)
+		if err := r.Client.Update(ctx, set); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if failed := applyAddressSet(set, entries, configMapVersion, r.brokers(set)); len(failed) > 0 {
+		reqLogger.Info("address set is not on every pod", "failed", failed)
+		condition := meta.FindStatusCondition(set.Status.Conditions, brokerv1beta1.AddressSetAppliedConditionType)
+		recordEvent(ctx, r.Recorder, set, corev1.EventTypeWarning, condition.Reason, condition.Message)
+	}
+	if err := r.Client.Status().Update(ctx, set); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: common.GetReconcileResyncPeriod()}, nil
+}
+
+func (r *ActiveMQArtemisAddressSetReconciler) brokers(set *brokerv1beta1.ActiveMQArtemisAddressSet) map[string]addressSetBroker {
+	brokers := map[string]addressSetBroker{}
+	pods, err := runningBrokerPods(r.Client, set.Namespace, set.Spec.ApplyToCrNames, set.Spec.ApplyToCrSelector)
+	if err != nil {
+		asetlog.Error(err, "unable to list the brokers", "namespace", set.Namespace)
+	}
+	for pod, artemis := range pods {
+		brokers[pod] = artemis
+	}
+	return brokers
+}
+
+// entries reads the entries of the ConfigMap, with the resource version of the ConfigMap so a change
+// to it can be detected
+func (r *ActiveMQArtemisAddressSetReconciler) entries(ctx context.Context, set *brokerv1beta1.ActiveMQArtemisAddressSet) ([]addressSetEntry, string, error) {
+	configMap := &corev1.ConfigMap{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: set.Namespace, Name: set.Spec.ConfigMapName}, configMap); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, "", fmt.Errorf("configMapName %v is not found", set.Spec.ConfigMapName)
+		}
+		return nil, "", err
+	}
+	entries, err := readAddressSetEntries(configMap, set.Spec.Keys)
+	if err != nil {
+		return nil, "", err
+	}
+	return entries, configMap.ResourceVersion, nil
+}
+
+// readAddressSetEntries parses the entries under the keys of the ConfigMap, every key in order when no
+// keys are given
+func readAddressSetEntries(configMap *corev1.ConfigMap, keys []string) ([]addressSetEntry, error) {
+	if len(keys) == 0 {
+		for key := range configMap.Data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+	}
+
+	entries := []addressSetEntry{}
+	seen := map[string]string{}
+	queues := map[string]string{}
+	for _, key := range keys {
+		data, found := configMap.Data[key]
+		if !found {
+			return nil, fmt.Errorf("configMap %v has no key %v", configMap.Name, key)
+		}
+		keyEntries := []addressSetEntry{}
+		if err := yaml.UnmarshalStrict([]byte(data), &keyEntries); err != nil {
+			return nil, fmt.Errorf("configMap %v key %v is not a list of addresses: %v", configMap.Name, key, err)
+		}
+		for i := range keyEntries {
+			entry := &keyEntries[i]
+			field := fmt.Sprintf("%v[%d]", key, i)
+			if err := validateAddressSetEntry(entry, field); err != nil {
+				return nil, err
+			}
+			if previous, duplicate := seen[entry.key()]; duplicate {
+				return nil, fmt.Errorf("%v repeats %v of %v", field, entry.key(), previous)
+			}
+			// the name of a queue is unique on a broker
+			if previous, duplicate := queues[entry.QueueName]; duplicate && entry.QueueName != "" {
+				return nil, fmt.Errorf("%v repeats queue %v of %v", field, entry.QueueName, previous)
+			}
+			seen[entry.key()] = field
+			queues[entry.QueueName] = field
+			entries = append(entries, *entry)
+		}
+	}
+	return entries, nil
+}
+
+func validateAddressSetEntry(entry *addressSetEntry, field string) error {
+	if strings.TrimSpace(entry.AddressName) == "" {
+		return fmt.Errorf("%v needs an addressName", field)
+	}
+	if strings.Contains(entry.AddressName, "::") {
+		return fmt.Errorf("%v addressName %v can't contain ::", field, entry.AddressName)
+	}
+	if entry.RoutingType != "" && !strings.EqualFold(entry.RoutingType, "anycast") && !strings.EqualFold(entry.RoutingType, "multicast") {
+		return fmt.Errorf("%v routingType %q must be anycast or multicast", field, entry.RoutingType)
+	}
+	return entry.QueueConfiguration.Validate(field + ".queueConfiguration")
+}
+
+func addressSetBatchSize(set *brokerv1beta1.ActiveMQArtemisAddressSet) int {
+	if set.Spec.BatchSize != nil && *set.Spec.BatchSize > 0 {
+		return int(*set.Spec.BatchSize)
+	}
+	return defaultAddressSetBatchSize
+}
+
+// applyAddressSet removes the entries dropped from the ConfigMap and creates the entries on each broker
+// that needs them. It returns the pods that failed
+func applyAddressSet(set *brokerv1beta1.ActiveMQArtemisAddressSet, entries []addressSetEntry, configMapVersion string, brokers map[string]addressSetBroker) []string {
+	status := &set.Status
+	changed := status.ConfigMapVersion != configMapVersion || status.ObservedGeneration != set.Generation
+
+	keys := make([]string, 0, len(entries))
+	current := map[string]bool{}
+	addresses := map[string]bool{}
+	for i := range entries {
+		keys = append(keys, entries[i].key())
+		current[entries[i].key()] = true
+		addresses[entries[i].AddressName] = true
+	}
+	dropped := []string{}
+	for _, key := range status.Applied {
+		if !current[key] {
+			dropped = append(dropped, key)
+		}
+	}
+
+	pods := make([]string, 0, len(brokers))
+	for pod := range brokers {
+		pods = append(pods, pod)
+	}
+	sort.Strings(pods)
+
+	batchSize := addressSetBatchSize(set)
+	failed := []string{}
+	applied := []string{}
+	var firstErr error
+	for _, pod := range pods {
+		broker := brokers[pod]
+		err := removeAddressSetEntries(broker, dropped, set.Spec.RemovalPolicy, batchSize, addresses)
+		if err == nil && (changed || !containsString(status.AppliedPods, pod) || missesAddresses(broker, addresses)) {
+			err = createAddressSetEntries(broker, entries, batchSize)
+		}
+		if err != nil {
+			asetlog.V(1).Info("unable to apply address set", "set", set.Name, "pod", pod, "error", err.Error())
+			failed = append(failed, pod)
+			if firstErr == nil {
+				firstErr = err
+			}
+		} else {
+			applied = append(applied, pod)
+		}
+	}
+	status.AppliedPods = applied
+
+	// the dropped entries are removed again on the next reconcile until every pod has the new set
+	if len(pods) > 0 && len(failed) == 0 {
+		status.ObservedGeneration = set.Generation
+		status.ConfigMapVersion = configMapVersion
+		status.Applied = keys
+		status.Entries = int32(len(entries))
+	}
+
+	condition := metav1.Condition{
+		Type:   brokerv1beta1.AddressSetAppliedConditionType,
+		Status: metav1.ConditionTrue,
+		Reason: brokerv1beta1.AddressSetAppliedSuccessReason,
+	}
+	if len(pods) == 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = brokerv1beta1.AddressSetAppliedNoBrokersReason
+		condition.Message = "no running broker pod is selected"
+	} else if len(failed) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = brokerv1beta1.AddressSetAppliedPendingReason
+		condition.Message = fmt.Sprintf("unable to apply the addresses on %v: %v", strings.Join(failed, ", "), firstErr)
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+	return failed
+}
+
+// missesAddresses tells whether a broker lost some of the addresses, in a restart without persistence
+func missesAddresses(broker addressSetBroker, addresses map[string]bool) bool {
+	existing, err := broker.ListAddresses()
+	if err != nil {
+		return true
+	}
+	found := 0
+	for _, name := range existing {
+		if addresses[name] {
+			found++
+		}
+	}
+	return found < len(addresses)
+}
+
+// execAddressSetBatches sends the operations in batches, it returns the response of each of them
+func execAddressSetBatches(broker addressSetBroker, operations []string, batchSize int) ([]*jolokia.ResponseData, error) {
+	responses := make([]*jolokia.ResponseData, 0, len(operations))
+	for start := 0; start < len(operations); start += batchSize {
+		end := start + batchSize
+		if end > len(operations) {
+			end = len(operations)
+		}
+		batch, err := broker.ExecBulk(operations[start:end])
+		if err != nil {
+			return responses, err
+		}
+		responses = append(responses, batch...)
+	}
+	return responses, nil
+}
+
+func succeeded(response *jolokia.ResponseData) bool {
+	return response != nil && response.Status == 200
+}
+
+// createAddressSetEntries creates the addresses, each with the routing types of its entries, then the
+// queues. A queue that exists already is updated unless its configuration ignores it
+func createAddressSetEntries(broker addressSetBroker, entries []addressSetEntry, batchSize int) error {
+	addressNames := []string{}
+	routingTypes := map[string]map[string]bool{}
+	for i := range entries {
+		name := entries[i].AddressName
+		if routingTypes[name] == nil {
+			addressNames = append(addressNames, name)
+			routingTypes[name] = map[string]bool{}
+		}
+		routingTypes[name][queueRoutingType(entries[i].address())] = true
+	}
+
+	operations := []string{}
+	for _, name := range addressNames {
+		names := []string{}
+		for routingType := range routingTypes[name] {
+			names = append(names, routingType)
+		}
+		sort.Strings(names)
+		operations = append(operations, broker.CreateAddressOperation(name, strings.Join(names, ",")))
+	}
+	queues := []*brokerv1beta1.ActiveMQArtemisAddress{}
+	for i := range entries {
+		if entries[i].QueueName == "" {
+			continue
+		}
+		queue := entries[i].address()
+		defaultQueueConfiguration(queue)
+		queueCfg, ignoreIfExists, err := GetQueueConfig(queue)
+		if err != nil {
+			return err
+		}
+		operations = append(operations, broker.CreateQueueFromConfigOperation(queueCfg, ignoreIfExists))
+		queues = append(queues, queue)
+	}
+
+	responses, err := execAddressSetBatches(broker, operations, batchSize)
+	if err != nil {
+		return err
+	}
+	updates := []string{}
+	for i, response := range responses {
+		if succeeded(response) {
+			continue
+		}
+		if i < len(addressNames) {
+			if mgmt.GetCreationError(response) != mgmt.ADDRESS_ALREADY_EXISTS {
+				return fmt.Errorf("unable to create address %v: %v", addressNames[i], response.Error)
+			}
+			continue
+		}
+		queue := queues[i-len(addressNames)]
+		if mgmt.GetCreationError(response) != mgmt.QUEUE_ALREADY_EXISTS {
+			return fmt.Errorf("unable to create queue %v: %v", *queue.Spec.QueueName, response.Error)
+		}
+		queueCfg, err := getQueueUpdateConfig(queue)
+		if err != nil {
+			return err
+		}
+		updates = append(updates, broker.UpdateQueueOperation(queueCfg))
+	}
+
+	responses, err = execAddressSetBatches(broker, updates, batchSize)
+	if err != nil {
+		return err
+	}
+	for _, response := range responses {
+		if !succeeded(response) {
+			return fmt.Errorf("unable to update queue: %v", response.Error)
+		}
+	}
+	return nil
+}
+
+// removeAddressSetEntries removes the queues of the entries, then with RemoveQueueAndAddress their
+// addresses once no queue is bound to them. The addresses to keep are still used by the set
+func removeAddressSetEntries(broker addressSetBroker, keys []string, removalPolicy string, batchSize int, keep map[string]bool) error {
+	if removalPolicy == "" || removalPolicy == brokerv1beta1.AddressRemovalPolicyOrphan || len(keys) == 0 {
+		return nil
+	}
+
+	operations := []string{}
+	addressNames := []string{}
+	seen := map[string]bool{}
+	for _, key := range keys {
+		entry := addressSetEntryFromKey(key)
+		if entry.QueueName != "" {
+			operations = append(operations, broker.DeleteQueueOperation(entry.QueueName))
+		}
+		if !seen[entry.AddressName] && !keep[entry.AddressName] {
+			seen[entry.AddressName] = true
+			addressNames = append(addressNames, entry.AddressName)
+		}
+	}
+	queueCount := len(operations)
+	if removalPolicy == brokerv1beta1.AddressRemovalPolicyRemoveQueueAndAddress {
+		for _, name := range addressNames {
+			operations = append(operations, broker.DeleteAddressOperation(name))
+		}
+	}
+
+	responses, err := execAddressSetBatches(broker, operations, batchSize)
+	if err != nil {
+		return err
+	}
+	for i, response := range responses {
+		// an address other queues are bound to stays
+		if i < queueCount && !succeeded(response) && !mgmt.IsNotFoundError(response) {
+			return fmt.Errorf("unable to delete queue: %v", response.Error)
+		}
+	}
+	return nil
+}
+
+// addressSetsUsingConfigMap are the address sets that read the ConfigMap
+func (r *ActiveMQArtemisAddressSetReconciler) addressSetsUsingConfigMap(configMap client.Object) []ctrl.Request {
+	requests := []ctrl.Request{}
+
+	sets := &brokerv1beta1.ActiveMQArtemisAddressSetList{}
+	if err := r.Client.List(context.TODO(), sets, client.InNamespace(configMap.GetNamespace())); err != nil {
+		asetlog.V(1).Info("unable to list address sets for config map", "configMap", configMap.GetName(), "error", err)
+		return requests
+	}
+	for i := range sets.Items {
+		if sets.Items[i].Spec.ConfigMapName == configMap.GetName() {
+			requests = append(requests, ctrl.Request{NamespacedName: types.NamespacedName{Name: sets.Items[i].Name, Namespace: sets.Items[i].Namespace}})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ActiveMQArtemisAddressSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&brokerv1beta1.ActiveMQArtemisAddressSet{}).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, handler.EnqueueRequestsFromMapFunc(r.addressSetsUsingConfigMap)).
+		Complete(withCorrelation("activemqartemisaddressset", r))
+}
@@ -0,0 +1,214 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	mgmt "github.com/artemiscloud/activemq-artemis-operator/pkg/utils/artemis"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/jolokia"
+	"github.com/stretchr/testify/assert"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fakeAddressSetBroker keeps the addresses and queues the operations of a bulk request create
+type fakeAddressSetBroker struct {
+	addresses map[string]string
+	queues    map[string]string
+	requests  [][]string
+	fail      bool
+}
+
+func (b *fakeAddressSetBroker) ListAddresses() ([]string, error) {
+	return settingMatches(b.addresses), nil
+}
+
+func (b *fakeAddressSetBroker) CreateAddressOperation(addressName string, routingType string) string {
+	return "createAddress " + addressName + " " + routingType
+}
+
+func (b *fakeAddressSetBroker) CreateQueueFromConfigOperation(queueConfig string, ignoreIfExists bool) string {
+	return "createQueue " + queueConfig
+}
+
+func (b *fakeAddressSetBroker) UpdateQueueOperation(queueConfig string) string {
+	return "updateQueue " + queueConfig
+}
+
+func (b *fakeAddressSetBroker) DeleteQueueOperation(queueName string) string {
+	return "deleteQueue " + queueName
+}
+
+func (b *fakeAddressSetBroker) DeleteAddressOperation(addressName string) string {
+	return "deleteAddress " + addressName
+}
+
+func (b *fakeAddressSetBroker) ExecBulk(operations []string) ([]*jolokia.ResponseData, error) {
+	if b.fail {
+		return nil, errors.New("unavailable")
+	}
+	b.requests = append(b.requests, operations)
+	responses := []*jolokia.ResponseData{}
+	for _, operation := range operations {
+		parts := strings.SplitN(operation, " ", 3)
+		response := &jolokia.ResponseData{Status: 200}
+		switch parts[0] {
+		case "createAddress":
+			if _, found := b.addresses[parts[1]]; found {
+				response = &jolokia.ResponseData{Status: 500, Error: mgmt.ADDRESS_ALREADY_EXISTS}
+			}
+			b.addresses[parts[1]] = parts[2]
+		case "createQueue", "updateQueue":
+			config := map[string]interface{}{}
+			json.Unmarshal([]byte(parts[1]), &config)
+			name := config["name"].(string)
+			if _, found := b.queues[name]; found && parts[0] == "createQueue" {
+				response = &jolokia.ResponseData{Status: 500, Error: mgmt.QUEUE_ALREADY_EXISTS}
+			} else {
+				b.queues[name] = config["address"].(string)
+				if maxConsumers, found := config["max-consumers"]; found {
+					b.queues[name] += " " + strconv.Itoa(int(maxConsumers.(float64)))
+				}
+			}
+		case "deleteQueue":
+			delete(b.queues, parts[1])
+		case "deleteAddress":
+			for _, queue := range b.queues {
+				if strings.SplitN(queue, " ", 2)[0] == parts[1] {
+					response = &jolokia.ResponseData{Status: 500, Error: "AMQ229205 address has bindings"}
+				}
+			}
+			if response.Status == 200 {
+				delete(b.addresses, parts[1])
+			}
+		}
+		responses = append(responses, response)
+	}
+	return responses, nil
+}
+
+func TestAddressSet(t *testing.T) {
+	configMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "addresses"},
+		Data: map[string]string{
+			"orders.yaml": `
+- addressName: orders
+  queueName: orders.eu
+  routingType: anycast
+- addressName: orders
+  queueName: orders.us
+  routingType: anycast
+  queueConfiguration:
+    maxConsumers: 2
+`,
+			"events.json": `[{"addressName": "events", "routingType": "multicast"}, {"addressName": "events", "queueName": "audit", "routingType": "multicast"}]`,
+		},
+	}
+	entries, err := readAddressSetEntries(configMap, nil)
+	assert.NoError(t, err)
+	keys := []string{}
+	for i := range entries {
+		keys = append(keys, entries[i].key())
+	}
+	assert.Equal(t, []string{"events", "events::audit", "orders::orders.eu", "orders::orders.us"}, keys)
+
+	only, err := readAddressSetEntries(configMap, []string{"orders.yaml"})
+	assert.NoError(t, err)
+	assert.Len(t, only, 2)
+
+	for _, invalid := range []string{
+		`[{"queueName": "nameless"}]`,
+		`[{"addressName": "a", "routingType": "broadcast"}]`,
+		`[{"addressName": "a", "queueName": "q"}, {"addressName": "b", "queueName": "q"}]`,
+		`[{"addressName": "a", "unknown": true}]`,
+		`{"addressName": "a"}`,
+	} {
+		_, err := readAddressSetEntries(&v1.ConfigMap{Data: map[string]string{"k": invalid}}, nil)
+		assert.Error(t, err, invalid)
+	}
+	_, err = readAddressSetEntries(configMap, []string{"missing"})
+	assert.Error(t, err)
+
+	// a batch size of 2 sends the 2 addresses and 3 queues in 3 requests
+	batchSize := int32(2)
+	set := &brokerv1beta1.ActiveMQArtemisAddressSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "bulk", Generation: 1},
+		Spec:       brokerv1beta1.ActiveMQArtemisAddressSetSpec{ConfigMapName: "addresses", BatchSize: &batchSize, RemovalPolicy: brokerv1beta1.AddressRemovalPolicyRemoveQueueAndAddress},
+	}
+	first := &fakeAddressSetBroker{addresses: map[string]string{}, queues: map[string]string{}}
+	second := &fakeAddressSetBroker{addresses: map[string]string{}, queues: map[string]string{}, fail: true}
+	brokers := map[string]addressSetBroker{"broker-ss-0": first, "broker-ss-1": second}
+	assert.Equal(t, []string{"broker-ss-1"}, applyAddressSet(set, entries, "1", brokers))
+	assert.Len(t, first.requests, 3)
+	assert.Equal(t, map[string]string{"events": "MULTICAST", "orders": "ANYCAST"}, first.addresses)
+	assert.Equal(t, map[string]string{"audit": "events", "orders.eu": "orders", "orders.us": "orders 2"}, first.queues)
+	assert.Empty(t, set.Status.Applied)
+	condition := meta.FindStatusCondition(set.Status.Conditions, brokerv1beta1.AddressSetAppliedConditionType)
+	assert.Equal(t, brokerv1beta1.AddressSetAppliedPendingReason, condition.Reason)
+
+	second.fail = false
+	assert.Empty(t, applyAddressSet(set, entries, "1", brokers))
+	assert.Equal(t, keys, set.Status.Applied)
+	assert.Equal(t, int32(4), set.Status.Entries)
+	assert.Equal(t, []string{"broker-ss-0", "broker-ss-1"}, set.Status.AppliedPods)
+
+	// nothing changed, a pod that has the addresses gets no request
+	first.requests = nil
+	assert.Empty(t, applyAddressSet(set, entries, "1", brokers))
+	assert.Empty(t, first.requests)
+
+	// a changed ConfigMap updates the existing queues and removes the dropped ones
+	configMap.Data["orders.yaml"] = `
+- addressName: orders
+  queueName: orders.us
+  routingType: anycast
+  queueConfiguration:
+    maxConsumers: 3
+`
+	delete(configMap.Data, "events.json")
+	entries, err = readAddressSetEntries(configMap, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, applyAddressSet(set, entries, "2", brokers))
+	assert.Equal(t, map[string]string{"orders": "ANYCAST"}, first.addresses)
+	assert.Equal(t, map[string]string{"orders.us": "orders 3"}, first.queues)
+	assert.Equal(t, []string{"orders::orders.us"}, set.Status.Applied)
+
+	// Orphan leaves the addresses on delete
+	assert.NoError(t, removeAddressSetEntries(first, set.Status.Applied, brokerv1beta1.AddressRemovalPolicyOrphan, 100, nil))
+	assert.Len(t, first.queues, 1)
+	assert.NoError(t, removeAddressSetEntries(first, set.Status.Applied, brokerv1beta1.AddressRemovalPolicyRemoveQueue, 100, nil))
+	assert.Empty(t, first.queues)
+	assert.Len(t, first.addresses, 1)
+
+	condition = meta.FindStatusCondition(set.Status.Conditions, brokerv1beta1.AddressSetAppliedConditionType)
+	assert.Equal(t, brokerv1beta1.AddressSetAppliedSuccessReason, condition.Reason)
+	applyAddressSet(set, entries, "2", map[string]addressSetBroker{})
+	condition = meta.FindStatusCondition(set.Status.Conditions, brokerv1beta1.AddressSetAppliedConditionType)
+	assert.Equal(t, brokerv1beta1.AddressSetAppliedNoBrokersReason, condition.Reason)
+}
+
+func TestMissesAddressesOfMultiAddressReply(t *testing.T) {
+	// the broker answers listAddresses with the names joined by the separator
+	agent := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.True(t, strings.HasSuffix(r.URL.Path, "/exec/org.apache.activemq.artemis:broker=\\\"amq-broker\\\""), r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status":200,"value":"orders,DLQ,ExpiryQueue,activemq.notifications"}`))
+	}))
+	defer agent.Close()
+	host, port, err := net.SplitHostPort(agent.Listener.Addr().String())
+	assert.NoError(t, err)
+	broker := mgmt.NewArtemis(host, port, "amq-broker", "admin", "admin")
+
+	assert.False(t, missesAddresses(broker, map[string]bool{"orders": true}))
+	assert.False(t, missesAddresses(broker, map[string]bool{"orders": true, "DLQ": true, "ExpiryQueue": true}))
+	assert.True(t, missesAddresses(broker, map[string]bool{"orders": true, "audit": true}))
+}
@@ -0,0 +1,360 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/common"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/jolokia"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+var aslog = ctrl.Log.WithName("controller_v1beta1activemqartemisaddresssettings")
+
+const addressSettingsFinalizer = "broker.amq.io/address-settings"
+
+// the management operations the address settings need from a broker pod
+type addressSettingsBroker interface {
+	AddAddressSettings(addressMatch string, settings string) (*jolokia.ResponseData, error)
+	RemoveAddressSettings(addressMatch string) (*jolokia.ResponseData, error)
+}
+
+// the management api names some settings differently from the address settings of the broker cr
+var managementAddressSettingNames = map[string]string{
+	"deadLetterAddress": "DLA",
+	"addressFullPolicy": "addressFullMessagePolicy",
+	"pageMaxCacheSize":  "pageCacheMaxSize",
+}
+
+// settings the management api takes as a number of bytes
+var byteAddressSettings = []string{"maxSizeBytes", "pageSizeBytes"}
+
+var byteNotation = regexp.MustCompile(`^(-?\d+)\s*([kKmMgG]?)[bB]?$`)
+
+// ActiveMQArtemisAddressSettingsReconciler reconciles a ActiveMQArtemisAddressSettings object
+type ActiveMQArtemisAddressSettingsReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=broker.amq.io,namespace=activemq-artemis-operator,resources=activemqartemisaddresssettings,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=broker.amq.io,namespace=activemq-artemis-operator,resources=activemqartemisaddresssettings/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=broker.amq.io,namespace=activemq-artemis-operator,resources=activemqartemisaddresssettings/finalizers,verbs=update
+
+// Reconcile sets the address settings on every running pod of the selected brokers and removes the
+// matches that are no longer in the spec. The settings are set again on each resync, which covers
+// pods that started or were scaled up since
+func (r *ActiveMQArtemisAddressSettingsReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	reqLogger := ctrl.LoggerFrom(ctx)
+
+	settings := &brokerv1beta1.ActiveMQArtemisAddressSettings{}
+	if err := r.Client.Get(ctx, request.NamespacedName, settings); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if !settings.DeletionTimestamp.IsZero() {
+		if controllerutil.ContainsFinalizer(settings, addressSettingsFinalizer) {
+			removeAddressSettings(settings, r.brokers(settings), settings.Status.Matches)
+			controllerutil.RemoveFinalizer(settings, addressSettingsFinalizer)
+			return ctrl.Result{}, r.Client.Update(ctx, settings)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if err := validateAddressSettings(settings); err != nil {
+		recordEvent(ctx, r.Recorder, settings, corev1.EventTypeWarning, brokerv1beta1.ValidConditionInvalidAddressSettingsReason, err.Error())
+		meta.SetStatusCondition(&settings.Status.Conditions, metav1.Condition{
+			Type:    brokerv1beta1.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  brokerv1beta1.ValidConditionInvalidAddressSettingsReason,
+			Message: err.Error(),
+		})
+		return ctrl.Result{}, r.Client.Status().Update(ctx, settings)
+	}
+	meta.SetStatusCondition(&settings.Status.Conditions, metav1.Condition{
+		Type:   brokerv1beta1.ValidConditionType,
+		Status: metav1.ConditionTrue,
+		Reason: brokerv1beta1.ValidConditionSuccessReason,
+	})
+
+	if !controllerutil.ContainsFinalizer(settings, addressSettingsFinalizer) {
+		controllerutil.AddFinalizer(settings, addressSettingsFinalizer)
+		if err := r.Client.Update(ctx, settings); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if failed := applyAddressSettings(settings, r.brokers(settings)); len(failed) > 0 {
+		reqLogger.Info("address settings are not set on every pod", "failed", failed)
+		condition := meta.FindStatusCondition(settings.Status.Conditions, brokerv1beta1.AddressSettingsAppliedConditionType)
+		recordEvent(ctx, r.Recorder, settings, corev1.EventTypeWarning, condition.Reason, condition.Message)
+	}
+	if err := r.Client.Status().Update(ctx, settings); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: common.GetReconcileResyncPeriod()}, nil
+}
+
+// brokers returns the management clients of the running pods of the selected brokers by pod name
+func (r *ActiveMQArtemisAddressSettingsReconciler) brokers(settings *brokerv1beta1.ActiveMQArtemisAddressSettings) map[string]addressSettingsBroker {
+	brokers := map[string]addressSettingsBroker{}
+	pods, err := runningBrokerPods(r.Client, settings.Namespace, settings.Spec.ApplyToCrNames, nil)
+	if err != nil {
+		aslog.Error(err, "unable to list the brokers", "namespace", settings.Namespace)
+	}
+	for pod, artemis := range pods {
+		brokers[pod] = artemis
+	}
+	return brokers
+}
+
+func validateAddressSettings(settings *brokerv1beta1.ActiveMQArtemisAddressSettings) error {
+	matches := map[string]bool{}
+	for i, setting := range settings.Spec.AddressSetting {
+		if setting.Match == "" {
+			return fmt.Errorf("addressSetting[%d] has no match", i)
+		}
+		if matches[setting.Match] {
+			return fmt.Errorf("addressSetting[%d] match %v is used more than once", i, setting.Match)
+		}
+		matches[setting.Match] = true
+		if _, err := managementAddressSettings(setting); err != nil {
+			return fmt.Errorf("addressSetting[%d] %v", i, err)
+		}
+	}
+	return nil
+}
+
+// applyAddressSettings sets the settings on each broker and removes the matches the spec no longer
+// has, it returns the pods that failed
+func applyAddressSettings(settings *brokerv1beta1.ActiveMQArtemisAddressSettings, brokers map[string]addressSettingsBroker) []string {
+	status := &settings.Status
+	matches := []string{}
+	for _, setting := range settings.Spec.AddressSetting {
+		matches = append(matches, setting.Match)
+	}
+	removed := []string{}
+	for _, match := range status.Matches {
+		if !containsString(matches, match) {
+			removed = append(removed, match)
+		}
+	}
+
+	pods := make([]string, 0, len(brokers))
+	for pod := range brokers {
+		pods = append(pods, pod)
+	}
+	sort.Strings(pods)
+
+	failed := removeAddressSettings(settings, brokers, removed)
+	status.AppliedPods = []string{}
+	for _, pod := range pods {
+		podFailed := containsString(failed, pod)
+		for _, setting := range settings.Spec.AddressSetting {
+			value, _ := managementAddressSettings(setting)
+			if data, err := brokers[pod].AddAddressSettings(setting.Match, value); err != nil {
+				aslog.V(1).Info("unable to set address settings", "pod", pod, "match", setting.Match, "error", err.Error(), "details", data)
+				podFailed = true
+			}
+		}
+		if podFailed {
+			if !containsString(failed, pod) {
+				failed = append(failed, pod)
+			}
+		} else {
+			status.AppliedPods = append(status.AppliedPods, pod)
+		}
+	}
+
+	// a removal that failed is tried again on the next reconcile
+	if len(failed) > 0 {
+		matches = append(matches, removed...)
+	}
+	status.Matches = matches
+
+	condition := metav1.Condition{
+		Type:   brokerv1beta1.AddressSettingsAppliedConditionType,
+		Status: metav1.ConditionTrue,
+		Reason: brokerv1beta1.AddressSettingsAppliedSuccessReason,
+	}
+	if len(pods) == 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = brokerv1beta1.AddressSettingsAppliedNoBrokersReason
+		condition.Message = "no running broker pod is selected"
+	} else if len(failed) > 0 {
+		sort.Strings(failed)
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = brokerv1beta1.AddressSettingsAppliedPendingReason
+		condition.Message = "unable to set the address settings on " + strings.Join(failed, ", ")
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+	return failed
+}
+
+func removeAddressSettings(settings *brokerv1beta1.ActiveMQArtemisAddressSettings, brokers map[string]addressSettingsBroker, matches []string) []string {
+	failed := []string{}
+	for pod, broker := range brokers {
+		for _, match := range matches {
+			if data, err := broker.RemoveAddressSettings(match); err != nil {
+				aslog.V(1).Info("unable to remove address settings", "settings", settings.Name, "pod", pod, "match", match, "error", err.Error(), "details", data)
+				if !containsString(failed, pod) {
+					failed = append(failed, pod)
+				}
+			}
+		}
+	}
+	return failed
+}
+
+// managementAddressSettings is the json the management api takes for a setting
+func managementAddressSettings(setting brokerv1beta1.AddressSettingType) (string, error) {
+	bytes, err := json.Marshal(setting)
+	if err != nil {
+		return "", err
+	}
+	values := map[string]interface{}{}
+	if err := json.Unmarshal(bytes, &values); err != nil {
+		return "", err
+	}
+	delete(values, "match")
+
+	for _, name := range byteAddressSettings {
+		if value, found := values[name]; found {
+			size, err := parseByteNotation(value.(string))
+			if err != nil {
+				return "", fmt.Errorf("%v %v", name, err)
+			}
+			values[name] = size
+		}
+	}
+	if value, found := values["lastValueQueue"]; found {
+		delete(values, "lastValueQueue")
+		if _, found := values["defaultLastValueQueue"]; !found {
+			values["defaultLastValueQueue"] = value
+		}
+	}
+	for name, managementName := range managementAddressSettingNames {
+		if value, found := values[name]; found {
+			delete(values, name)
+			values[managementName] = value
+		}
+	}
+
+	if bytes, err = json.Marshal(values); err != nil {
+		return "", err
+	}
+	return string(bytes), nil
+}
+
+// addressSettingsProperties renders the address settings CRs that select the broker into its broker
+// properties, so a broker that restarts has them before it takes traffic rather than on the next
+// resync. A CR that is invalid or being deleted is left out
+func addressSettingsProperties(customResource *brokerv1beta1.ActiveMQArtemis, c client.Client) []string {
+	props := []string{}
+	if c == nil {
+		return props
+	}
+	list := &brokerv1beta1.ActiveMQArtemisAddressSettingsList{}
+	if err := c.List(context.TODO(), list, client.InNamespace(customResource.Namespace)); err != nil {
+		clog.V(1).Info("unable to list address settings", "namespace", customResource.Namespace, "error", err)
+		return props
+	}
+	sort.Slice(list.Items, func(i, j int) bool { return list.Items[i].Name < list.Items[j].Name })
+	for i := range list.Items {
+		settings := &list.Items[i]
+		if !settings.DeletionTimestamp.IsZero() || !appliesToBroker(settings.Spec.ApplyToCrNames, customResource.Name) || validateAddressSettings(settings) != nil {
+			continue
+		}
+		for _, setting := range settings.Spec.AddressSetting {
+			props = append(props, addressSettingProperties(setting)...)
+		}
+	}
+	return props
+}
+
+// addressSettingProperties is a setting as broker properties, they take the names of the management
+// api except for the dead letter address
+func addressSettingProperties(setting brokerv1beta1.AddressSettingType) []string {
+	value, err := managementAddressSettings(setting)
+	if err != nil {
+		return nil
+	}
+	values := map[string]interface{}{}
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.UseNumber()
+	if err := decoder.Decode(&values); err != nil {
+		return nil
+	}
+	if dla, found := values["DLA"]; found {
+		delete(values, "DLA")
+		values["deadLetterAddress"] = dla
+	}
+	props := []string{}
+	for name, value := range values {
+		props = append(props, fmt.Sprintf("addressSettings.\"%v\".%v=%v", setting.Match, name, value))
+	}
+	sort.Strings(props)
+	return props
+}
+
+// parseByteNotation reads sizes like 10Mb or 512K the way the broker does, in powers of 1024
+func parseByteNotation(value string) (int64, error) {
+	parts := byteNotation.FindStringSubmatch(strings.TrimSpace(value))
+	if parts == nil {
+		return 0, fmt.Errorf("%q is not a size, for example 10Mb", value)
+	}
+	size, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a size, for example 10Mb", value)
+	}
+	switch strings.ToLower(parts[2]) {
+	case "k":
+		size *= 1024
+	case "m":
+		size *= 1024 * 1024
+	case "g":
+		size *= 1024 * 1024 * 1024
+	}
+	return size, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ActiveMQArtemisAddressSettingsReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&brokerv1beta1.ActiveMQArtemisAddressSettings{}).
+		Complete(withCorrelation("activemqartemisaddresssettings", r))
+}
@@ -0,0 +1,143 @@
+package controllers
+
+import (
+	"errors"
+	"testing"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/jolokia"
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type fakeAddressSettingsBroker struct {
+	settings map[string]string
+	fail     bool
+}
+
+func (b *fakeAddressSettingsBroker) AddAddressSettings(addressMatch string, settings string) (*jolokia.ResponseData, error) {
+	if b.fail {
+		return &jolokia.ResponseData{Status: 500}, errors.New("unavailable")
+	}
+	b.settings[addressMatch] = settings
+	return &jolokia.ResponseData{Status: 200}, nil
+}
+
+func (b *fakeAddressSettingsBroker) RemoveAddressSettings(addressMatch string) (*jolokia.ResponseData, error) {
+	if b.fail {
+		return &jolokia.ResponseData{Status: 500}, errors.New("unavailable")
+	}
+	delete(b.settings, addressMatch)
+	return &jolokia.ResponseData{Status: 200}, nil
+}
+
+func TestAddressSettings(t *testing.T) {
+	attempts := int32(3)
+	maxSize := "10Mb"
+	policy := "BLOCK"
+	dla := "DLQ"
+	lvq := true
+	settings := &brokerv1beta1.ActiveMQArtemisAddressSettings{
+		Spec: brokerv1beta1.ActiveMQArtemisAddressSettingsSpec{
+			AddressSetting: []brokerv1beta1.AddressSettingType{
+				{Match: "orders.#", MaxDeliveryAttempts: &attempts, DeadLetterAddress: &dla, MaxSizeBytes: &maxSize, AddressFullPolicy: &policy},
+				{Match: "prices", LastValueQueue: &lvq},
+			},
+		},
+	}
+	assert.NoError(t, validateAddressSettings(settings))
+
+	value, err := managementAddressSettings(settings.Spec.AddressSetting[0])
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"maxDeliveryAttempts":3,"DLA":"DLQ","maxSizeBytes":10485760,"addressFullMessagePolicy":"BLOCK"}`, value)
+	value, err = managementAddressSettings(settings.Spec.AddressSetting[1])
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"defaultLastValueQueue":true}`, value)
+
+	// the first pod is set, the second one is retried
+	settings.Status.Matches = []string{"stale"}
+	first := &fakeAddressSettingsBroker{settings: map[string]string{"stale": "{}"}}
+	second := &fakeAddressSettingsBroker{settings: map[string]string{"stale": "{}"}, fail: true}
+	brokers := map[string]addressSettingsBroker{"broker-ss-0": first, "broker-ss-1": second}
+	assert.Equal(t, []string{"broker-ss-1"}, applyAddressSettings(settings, brokers))
+	assert.Equal(t, []string{"broker-ss-0"}, settings.Status.AppliedPods)
+	assert.ElementsMatch(t, []string{"orders.#", "prices"}, settingMatches(first.settings))
+	assert.Equal(t, []string{"orders.#", "prices", "stale"}, settings.Status.Matches)
+	condition := meta.FindStatusCondition(settings.Status.Conditions, brokerv1beta1.AddressSettingsAppliedConditionType)
+	assert.Equal(t, brokerv1beta1.AddressSettingsAppliedPendingReason, condition.Reason)
+
+	second.fail = false
+	assert.Empty(t, applyAddressSettings(settings, brokers))
+	assert.Equal(t, []string{"broker-ss-0", "broker-ss-1"}, settings.Status.AppliedPods)
+	assert.ElementsMatch(t, []string{"orders.#", "prices"}, settingMatches(second.settings))
+	assert.Equal(t, []string{"orders.#", "prices"}, settings.Status.Matches)
+	assert.True(t, meta.IsStatusConditionTrue(settings.Status.Conditions, brokerv1beta1.AddressSettingsAppliedConditionType))
+
+	applyAddressSettings(settings, map[string]addressSettingsBroker{})
+	condition = meta.FindStatusCondition(settings.Status.Conditions, brokerv1beta1.AddressSettingsAppliedConditionType)
+	assert.Equal(t, brokerv1beta1.AddressSettingsAppliedNoBrokersReason, condition.Reason)
+
+	assert.True(t, appliesToBroker(settings.Spec.ApplyToCrNames, "any"))
+	settings.Spec.ApplyToCrNames = []string{"broker"}
+	assert.True(t, appliesToBroker(settings.Spec.ApplyToCrNames, "broker"))
+	assert.False(t, appliesToBroker(settings.Spec.ApplyToCrNames, "other"))
+
+	badSize := "ten"
+	for _, invalid := range [][]brokerv1beta1.AddressSettingType{
+		{{MaxDeliveryAttempts: &attempts}},
+		{{Match: "orders.#"}, {Match: "orders.#"}},
+		{{Match: "orders.#", MaxSizeBytes: &badSize}},
+	} {
+		settings.Spec.AddressSetting = invalid
+		assert.Error(t, validateAddressSettings(settings), invalid)
+	}
+}
+
+func TestAddressSettingsProperties(t *testing.T) {
+	attempts := int32(3)
+	maxSize := "10Mb"
+	dla := "DLQ"
+	broker := &brokerv1beta1.ActiveMQArtemis{ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: "test"}}
+	other := &brokerv1beta1.ActiveMQArtemis{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "test"}}
+	orders := &brokerv1beta1.ActiveMQArtemisAddressSettings{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "test"},
+		Spec: brokerv1beta1.ActiveMQArtemisAddressSettingsSpec{
+			ApplyToCrNames: []string{"broker"},
+			AddressSetting: []brokerv1beta1.AddressSettingType{
+				{Match: "orders.#", MaxDeliveryAttempts: &attempts, DeadLetterAddress: &dla, MaxSizeBytes: &maxSize},
+			},
+		},
+	}
+	invalid := &brokerv1beta1.ActiveMQArtemisAddressSettings{
+		ObjectMeta: metav1.ObjectMeta{Name: "invalid", Namespace: "test"},
+		Spec: brokerv1beta1.ActiveMQArtemisAddressSettingsSpec{
+			AddressSetting: []brokerv1beta1.AddressSettingType{{MaxDeliveryAttempts: &attempts}},
+		},
+	}
+	client := fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithObjects(broker, other, orders, invalid).Build()
+
+	assert.Equal(t, []string{
+		"addressSettings.\"orders.#\".deadLetterAddress=DLQ",
+		"addressSettings.\"orders.#\".maxDeliveryAttempts=3",
+		"addressSettings.\"orders.#\".maxSizeBytes=10485760",
+	}, addressSettingsProperties(broker, client))
+	assert.Empty(t, addressSettingsProperties(other, client))
+
+	r := &ActiveMQArtemisReconciler{Client: client}
+	requests := r.brokersRenderingCR(orders)
+	assert.Len(t, requests, 1)
+	assert.Equal(t, "broker", requests[0].Name)
+	assert.Len(t, r.brokersRenderingCR(invalid), 2)
+}
+
+func settingMatches(values map[string]string) []string {
+	names := []string{}
+	for name := range values {
+		names = append(names, name)
+	}
+	return names
+}
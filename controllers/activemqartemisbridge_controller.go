@@ -0,0 +1,412 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	mgmt "github.com/artemiscloud/activemq-artemis-operator/pkg/utils/artemis"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/common"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/jolokia"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+var brlog = ctrl.Log.WithName("controller_v1beta1activemqartemisbridge")
+
+const bridgeFinalizer = "broker.amq.io/bridge"
+
+// the management operations a bridge needs from a broker pod
+type bridgeBroker interface {
+	AddConnector(connectorName string, connectorUrl string) (*jolokia.ResponseData, error)
+	RemoveConnector(connectorName string) (*jolokia.ResponseData, error)
+	CreateBridgeFromConfiguration(config mgmt.BridgeConfiguration) (*jolokia.ResponseData, error)
+	DestroyBridge(bridgeName string) (*jolokia.ResponseData, error)
+	ListBridgeNames() ([]string, error)
+}
+
+// ActiveMQArtemisBridgeReconciler reconciles a ActiveMQArtemisBridge object
+type ActiveMQArtemisBridgeReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=broker.amq.io,namespace=activemq-artemis-operator,resources=activemqartemisbridges,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=broker.amq.io,namespace=activemq-artemis-operator,resources=activemqartemisbridges/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=broker.amq.io,namespace=activemq-artemis-operator,resources=activemqartemisbridges/finalizers,verbs=update
+
+// Reconcile adds the connector to the target and creates the bridge on every running pod of the
+// selected brokers. A pod that started since or lost the bridge in a restart gets it on the next
+// resync. A change to the spec or to the credentials secret replaces the bridge on every pod
+func (r *ActiveMQArtemisBridgeReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	reqLogger := ctrl.LoggerFrom(ctx)
+
+	bridge := &brokerv1beta1.ActiveMQArtemisBridge{}
+	if err := r.Client.Get(ctx, request.NamespacedName, bridge); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if !bridge.DeletionTimestamp.IsZero() {
+		if controllerutil.ContainsFinalizer(bridge, bridgeFinalizer) {
+			if bridge.Status.BridgeName != "" {
+				destroyBridge(bridge.Status.BridgeName, r.brokers(bridge))
+			}
+			controllerutil.RemoveFinalizer(bridge, bridgeFinalizer)
+			return ctrl.Result{}, r.Client.Update(ctx, bridge)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	err := validateBridge(bridge)
+	var user, password, credentialsVersion string
+	if err == nil {
+		user, password, credentialsVersion, err = r.credentials(ctx, bridge)
+	}
+	if err != nil {
+		recordEvent(ctx, r.Recorder, bridge, corev1.EventTypeWarning, brokerv1beta1.ValidConditionInvalidBridgeReason, err.Error())
+		meta.SetStatusCondition(&bridge.Status.Conditions, metav1.Condition{
+			Type:    brokerv1beta1.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  brokerv1beta1.ValidConditionInvalidBridgeReason,
+			Message: err.Error(),
+		})
+		// a missing secret may be created later
+		return ctrl.Result{RequeueAfter: common.GetReconcileResyncPeriod()}, r.Client.Status().Update(ctx, bridge)
+	}
+	meta.SetStatusCondition(&bridge.Status.Conditions, metav1.Condition{
+		Type:   brokerv1beta1.ValidConditionType,
+		Status: metav1.ConditionTrue,
+		Reason: brokerv1beta1.ValidConditionSuccessReason,
+	})
+
+	if !controllerutil.ContainsFinalizer(bridge, bridgeFinalizer) {
+		controllerutil.AddFinalizer(bridge, bridgeFinalizer)
+		if err := r.Client.Update(ctx, bridge); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	config := bridgeConfiguration(bridge, user, password)
+	if failed := applyBridge(bridge, config, credentialsVersion, r.brokers(bridge)); len(failed) > 0 {
+		reqLogger.Info("bridge is not on every pod", "failed", failed)
+		condition := meta.FindStatusCondition(bridge.Status.Conditions, brokerv1beta1.BridgeAppliedConditionType)
+		recordEvent(ctx, r.Recorder, bridge, corev1.EventTypeWarning, condition.Reason, condition.Message)
+	}
+	if err := r.Client.Status().Update(ctx, bridge); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: common.GetReconcileResyncPeriod()}, nil
+}
+
+func (r *ActiveMQArtemisBridgeReconciler) brokers(bridge *brokerv1beta1.ActiveMQArtemisBridge) map[string]bridgeBroker {
+	brokers := map[string]bridgeBroker{}
+	pods, err := runningBrokerPods(r.Client, bridge.Namespace, bridge.Spec.ApplyToCrNames, nil)
+	if err != nil {
+		brlog.Error(err, "unable to list the brokers", "namespace", bridge.Namespace)
+	}
+	for pod, artemis := range pods {
+		brokers[pod] = artemis
+	}
+	return brokers
+}
+
+// credentials reads the user and password of the target from the credentials secret, with the
+// resource version of the secret so a change to it can be detected
+func (r *ActiveMQArtemisBridgeReconciler) credentials(ctx context.Context, bridge *brokerv1beta1.ActiveMQArtemisBridge) (string, string, string, error) {
+	name := bridge.Spec.Target.CredentialsSecret
+	if name == "" {
+		return "", "", "", nil
+	}
+	secret := &corev1.Secret{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: bridge.Namespace, Name: name}, secret); err != nil {
+		if errors.IsNotFound(err) {
+			return "", "", "", fmt.Errorf("target.credentialsSecret %v is not found", name)
+		}
+		return "", "", "", err
+	}
+	user, password := string(secret.Data["user"]), string(secret.Data["password"])
+	if user == "" || password == "" {
+		return "", "", "", fmt.Errorf("target.credentialsSecret %v needs the user and password keys", name)
+	}
+	return user, password, secret.ResourceVersion, nil
+}
+
+func bridgeName(bridge *brokerv1beta1.ActiveMQArtemisBridge) string {
+	if bridge.Spec.BridgeName != "" {
+		return bridge.Spec.BridgeName
+	}
+	return bridge.Name
+}
+
+// the connector the bridge connects to the target with has the name of the bridge
+func bridgeConnectorName(bridgeName string) string {
+	return bridgeName + "-connector"
+}
+
+func validateBridge(bridge *brokerv1beta1.ActiveMQArtemisBridge) error {
+	spec := bridge.Spec
+	if strings.ContainsAny(bridgeName(bridge), " \t\n") {
+		return fmt.Errorf("bridgeName %q can't contain white space", bridgeName(bridge))
+	}
+	if spec.QueueName == "" {
+		return fmt.Errorf("a bridge needs a queueName")
+	}
+	if target, err := url.Parse(spec.Target.ConnectorUrl); err != nil || target.Scheme == "" || target.Host == "" {
+		return fmt.Errorf("target.connectorUrl %q must be a url like tcp://host:61616", spec.Target.ConnectorUrl)
+	}
+	if spec.ProducerWindowSize != nil && *spec.ProducerWindowSize < -1 {
+		return fmt.Errorf("producerWindowSize %v must be -1 or more", *spec.ProducerWindowSize)
+	}
+	if policy := spec.ReconnectPolicy; policy != nil {
+		if policy.RetryInterval != nil && *policy.RetryInterval <= 0 {
+			return fmt.Errorf("reconnectPolicy.retryInterval %v must be more than 0", *policy.RetryInterval)
+		}
+		if policy.RetryIntervalMultiplier != "" {
+			if multiplier, err := strconv.ParseFloat(policy.RetryIntervalMultiplier, 64); err != nil || multiplier < 1 {
+				return fmt.Errorf("reconnectPolicy.retryIntervalMultiplier %q must be a number of 1 or more", policy.RetryIntervalMultiplier)
+			}
+		}
+		if policy.InitialConnectAttempts != nil && *policy.InitialConnectAttempts < -1 {
+			return fmt.Errorf("reconnectPolicy.initialConnectAttempts %v must be -1 or more", *policy.InitialConnectAttempts)
+		}
+		if policy.ReconnectAttempts != nil && *policy.ReconnectAttempts < -1 {
+			return fmt.Errorf("reconnectPolicy.reconnectAttempts %v must be -1 or more", *policy.ReconnectAttempts)
+		}
+	}
+	return nil
+}
+
+// bridgeConfiguration is the bridge of a valid CR, with the broker defaults for the settings it leaves out
+func bridgeConfiguration(bridge *brokerv1beta1.ActiveMQArtemisBridge, user string, password string) mgmt.BridgeConfiguration {
+	spec := bridge.Spec
+	config := mgmt.BridgeConfiguration{
+		Name:                    bridgeName(bridge),
+		QueueName:               spec.QueueName,
+		ForwardingAddress:       spec.ForwardingAddress,
+		Filter:                  spec.Filter,
+		TransformerClassName:    spec.TransformerClassName,
+		RetryInterval:           2000,
+		RetryIntervalMultiplier: 1.0,
+		InitialConnectAttempts:  -1,
+		ReconnectAttempts:       -1,
+		UseDuplicateDetection:   true,
+		ProducerWindowSize:      1048576,
+		ConnectorName:           bridgeConnectorName(bridgeName(bridge)),
+		User:                    user,
+		Password:                password,
+	}
+	if spec.UseDuplicateDetection != nil {
+		config.UseDuplicateDetection = *spec.UseDuplicateDetection
+	}
+	if spec.ProducerWindowSize != nil {
+		config.ProducerWindowSize = *spec.ProducerWindowSize
+	}
+	if policy := spec.ReconnectPolicy; policy != nil {
+		if policy.RetryInterval != nil {
+			config.RetryInterval = *policy.RetryInterval
+		}
+		if multiplier, err := strconv.ParseFloat(policy.RetryIntervalMultiplier, 64); err == nil {
+			config.RetryIntervalMultiplier = multiplier
+		}
+		if policy.InitialConnectAttempts != nil {
+			config.InitialConnectAttempts = *policy.InitialConnectAttempts
+		}
+		if policy.ReconnectAttempts != nil {
+			config.ReconnectAttempts = *policy.ReconnectAttempts
+		}
+	}
+	return config
+}
+
+// applyBridge creates the bridge on each broker that doesn't have it, after a change to the spec or to
+// the credentials the bridge is replaced on every broker. It returns the pods that failed
+func applyBridge(bridge *brokerv1beta1.ActiveMQArtemisBridge, config mgmt.BridgeConfiguration, credentialsVersion string, brokers map[string]bridgeBroker) []string {
+	status := &bridge.Status
+	changed := status.BridgeName != "" &&
+		(status.BridgeName != config.Name || status.ObservedGeneration != bridge.Generation || status.CredentialsVersion != credentialsVersion)
+
+	pods := make([]string, 0, len(brokers))
+	for pod := range brokers {
+		pods = append(pods, pod)
+	}
+	sort.Strings(pods)
+
+	failed := []string{}
+	status.AppliedPods = []string{}
+	for _, pod := range pods {
+		if err := replaceBridge(bridge, config, brokers[pod], changed); err != nil {
+			brlog.V(1).Info("unable to create bridge", "bridge", bridge.Name, "pod", pod, "error", err.Error())
+			failed = append(failed, pod)
+		} else {
+			status.AppliedPods = append(status.AppliedPods, pod)
+		}
+	}
+
+	// the previous bridge is looked for again on the next reconcile until every pod has the new one
+	if len(pods) > 0 && len(failed) == 0 {
+		status.BridgeName = config.Name
+		status.ObservedGeneration = bridge.Generation
+		status.CredentialsVersion = credentialsVersion
+	}
+
+	condition := metav1.Condition{
+		Type:   brokerv1beta1.BridgeAppliedConditionType,
+		Status: metav1.ConditionTrue,
+		Reason: brokerv1beta1.BridgeAppliedSuccessReason,
+	}
+	if len(pods) == 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = brokerv1beta1.BridgeAppliedNoBrokersReason
+		condition.Message = "no running broker pod is selected"
+	} else if len(failed) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = brokerv1beta1.BridgeAppliedPendingReason
+		condition.Message = "unable to create the bridge on " + strings.Join(failed, ", ")
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+	return failed
+}
+
+func replaceBridge(bridge *brokerv1beta1.ActiveMQArtemisBridge, config mgmt.BridgeConfiguration, broker bridgeBroker, changed bool) error {
+	names, err := broker.ListBridgeNames()
+	if err != nil {
+		return err
+	}
+	exists := containsString(names, config.Name)
+	if changed && containsString(names, bridge.Status.BridgeName) {
+		if _, err := broker.DestroyBridge(bridge.Status.BridgeName); err != nil {
+			return err
+		}
+		if bridge.Status.BridgeName != config.Name {
+			if _, err := broker.RemoveConnector(bridgeConnectorName(bridge.Status.BridgeName)); err != nil {
+				return err
+			}
+		}
+		exists = exists && bridge.Status.BridgeName != config.Name
+	}
+	if exists {
+		return nil
+	}
+
+	// the connector is added again each time, a connector of the same name is replaced
+	if _, err := broker.AddConnector(config.ConnectorName, bridge.Spec.Target.ConnectorUrl); err != nil {
+		return err
+	}
+	_, err = broker.CreateBridgeFromConfiguration(config)
+	return err
+}
+
+// bridgeProperties renders the bridge CRs that select the broker and their connectors into its broker
+// properties, so a broker that restarts creates the bridges again. A CR that is invalid, being deleted
+// or whose credentials can't be read is left out
+func bridgeProperties(customResource *brokerv1beta1.ActiveMQArtemis, c client.Client) []string {
+	props := []string{}
+	if c == nil {
+		return props
+	}
+	list := &brokerv1beta1.ActiveMQArtemisBridgeList{}
+	if err := c.List(context.TODO(), list, client.InNamespace(customResource.Namespace)); err != nil {
+		clog.V(1).Info("unable to list bridges", "namespace", customResource.Namespace, "error", err)
+		return props
+	}
+	sort.Slice(list.Items, func(i, j int) bool { return list.Items[i].Name < list.Items[j].Name })
+	for i := range list.Items {
+		bridge := &list.Items[i]
+		if !bridge.DeletionTimestamp.IsZero() || !appliesToBroker(bridge.Spec.ApplyToCrNames, customResource.Name) || validateBridge(bridge) != nil {
+			continue
+		}
+		var user, password string
+		if name := bridge.Spec.Target.CredentialsSecret; name != "" {
+			var err error
+			if user, password, err = getConnectionCredentials(customResource, name, c); err != nil {
+				clog.V(1).Info("unable to read bridge credentials", "bridge", bridge.Name, "error", err)
+				continue
+			}
+		}
+		config := bridgeConfiguration(bridge, user, password)
+		connector, err := connectorUrlProperties(config.ConnectorName, bridge.Spec.Target.ConnectorUrl)
+		if err != nil {
+			continue
+		}
+		props = append(props, connector...)
+
+		prefix := fmt.Sprintf("bridgeConfigurations.\"%v\".", config.Name)
+		props = append(props,
+			prefix+"queueName="+config.QueueName,
+			prefix+"staticConnectors="+config.ConnectorName,
+			prefix+"retryInterval="+strconv.FormatInt(config.RetryInterval, 10),
+			prefix+"retryIntervalMultiplier="+strconv.FormatFloat(config.RetryIntervalMultiplier, 'f', -1, 64),
+			prefix+"initialConnectAttempts="+strconv.Itoa(int(config.InitialConnectAttempts)),
+			prefix+"reconnectAttempts="+strconv.Itoa(int(config.ReconnectAttempts)),
+			prefix+"useDuplicateDetection="+strconv.FormatBool(config.UseDuplicateDetection),
+			prefix+"producerWindowSize="+strconv.Itoa(int(config.ProducerWindowSize)))
+		if config.ForwardingAddress != "" {
+			props = append(props, prefix+"forwardingAddress="+config.ForwardingAddress)
+		}
+		if config.Filter != "" {
+			props = append(props, prefix+"filterString="+config.Filter)
+		}
+		if config.TransformerClassName != "" {
+			props = append(props, prefix+"transformerConfiguration="+config.TransformerClassName)
+		}
+		if user != "" {
+			props = append(props, prefix+"user="+user, prefix+"password="+password)
+		}
+	}
+	return props
+}
+
+func destroyBridge(name string, brokers map[string]bridgeBroker) {
+	for pod, broker := range brokers {
+		names, err := broker.ListBridgeNames()
+		if err == nil && !containsString(names, name) {
+			continue
+		}
+		if data, err := broker.DestroyBridge(name); err != nil {
+			brlog.V(1).Info("unable to destroy bridge", "bridge", name, "pod", pod, "error", err.Error(), "details", data)
+			continue
+		}
+		if data, err := broker.RemoveConnector(bridgeConnectorName(name)); err != nil {
+			brlog.V(1).Info("unable to remove connector", "bridge", name, "pod", pod, "error", err.Error(), "details", data)
+		}
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ActiveMQArtemisBridgeReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&brokerv1beta1.ActiveMQArtemisBridge{}).
+		Complete(withCorrelation("activemqartemisbridge", r))
+}
@@ -0,0 +1,177 @@
+package controllers
+
+import (
+	"errors"
+	"testing"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	mgmt "github.com/artemiscloud/activemq-artemis-operator/pkg/utils/artemis"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/jolokia"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type fakeBridgeBroker struct {
+	connectors map[string]string
+	bridges    map[string]string
+	fail       bool
+}
+
+func (b *fakeBridgeBroker) AddConnector(connectorName string, connectorUrl string) (*jolokia.ResponseData, error) {
+	b.connectors[connectorName] = connectorUrl
+	return &jolokia.ResponseData{Status: 200}, nil
+}
+
+func (b *fakeBridgeBroker) RemoveConnector(connectorName string) (*jolokia.ResponseData, error) {
+	delete(b.connectors, connectorName)
+	return &jolokia.ResponseData{Status: 200}, nil
+}
+
+func (b *fakeBridgeBroker) CreateBridgeFromConfiguration(config mgmt.BridgeConfiguration) (*jolokia.ResponseData, error) {
+	if b.fail {
+		return &jolokia.ResponseData{Status: 500}, errors.New("unavailable")
+	}
+	b.bridges[config.Name] = config.QueueName + "->" + b.connectors[config.ConnectorName] + " as " + config.User
+	return &jolokia.ResponseData{Status: 200}, nil
+}
+
+func (b *fakeBridgeBroker) DestroyBridge(bridgeName string) (*jolokia.ResponseData, error) {
+	delete(b.bridges, bridgeName)
+	return &jolokia.ResponseData{Status: 200}, nil
+}
+
+func (b *fakeBridgeBroker) ListBridgeNames() ([]string, error) {
+	if b.fail {
+		return nil, errors.New("unavailable")
+	}
+	return settingMatches(b.bridges), nil
+}
+
+func TestBridge(t *testing.T) {
+	attempts := int32(10)
+	bridge := &brokerv1beta1.ActiveMQArtemisBridge{
+		ObjectMeta: metav1.ObjectMeta{Name: "to-dr", Generation: 1},
+		Spec: brokerv1beta1.ActiveMQArtemisBridgeSpec{
+			QueueName:       "orders",
+			Target:          brokerv1beta1.BridgeTargetType{ConnectorUrl: "tcp://dr-hdls-svc:61616"},
+			ReconnectPolicy: &brokerv1beta1.BridgeReconnectPolicyType{RetryIntervalMultiplier: "1.5", ReconnectAttempts: &attempts},
+		},
+	}
+	assert.NoError(t, validateBridge(bridge))
+	config := bridgeConfiguration(bridge, "bridge", "secret")
+	assert.Equal(t, "to-dr-connector", config.ConnectorName)
+	assert.Equal(t, int64(2000), config.RetryInterval)
+	assert.Equal(t, 1.5, config.RetryIntervalMultiplier)
+	assert.Equal(t, int32(-1), config.InitialConnectAttempts)
+	assert.Equal(t, int32(10), config.ReconnectAttempts)
+	assert.True(t, config.UseDuplicateDetection)
+
+	// the second pod is retried until it has the bridge
+	first := &fakeBridgeBroker{connectors: map[string]string{}, bridges: map[string]string{}}
+	second := &fakeBridgeBroker{connectors: map[string]string{}, bridges: map[string]string{}, fail: true}
+	brokers := map[string]bridgeBroker{"broker-ss-0": first, "broker-ss-1": second}
+	assert.Equal(t, []string{"broker-ss-1"}, applyBridge(bridge, config, "1", brokers))
+	assert.Equal(t, map[string]string{"to-dr": "orders->tcp://dr-hdls-svc:61616 as bridge"}, first.bridges)
+	assert.Empty(t, bridge.Status.BridgeName)
+	condition := meta.FindStatusCondition(bridge.Status.Conditions, brokerv1beta1.BridgeAppliedConditionType)
+	assert.Equal(t, brokerv1beta1.BridgeAppliedPendingReason, condition.Reason)
+
+	second.fail = false
+	assert.Empty(t, applyBridge(bridge, config, "1", brokers))
+	assert.Equal(t, []string{"broker-ss-0", "broker-ss-1"}, bridge.Status.AppliedPods)
+	assert.Equal(t, "to-dr", bridge.Status.BridgeName)
+	assert.Equal(t, "1", bridge.Status.CredentialsVersion)
+
+	// a restarted pod gets the bridge back, new credentials replace it everywhere
+	delete(first.bridges, "to-dr")
+	assert.Empty(t, applyBridge(bridge, config, "1", brokers))
+	assert.Contains(t, first.bridges, "to-dr")
+
+	config = bridgeConfiguration(bridge, "rotated", "secret")
+	assert.Empty(t, applyBridge(bridge, config, "2", brokers))
+	assert.Equal(t, map[string]string{"to-dr": "orders->tcp://dr-hdls-svc:61616 as rotated"}, second.bridges)
+
+	// a new name replaces the bridge and its connector
+	bridge.Spec.BridgeName = "to-dr2"
+	bridge.Spec.Target.ConnectorUrl = "tcp://dr2-hdls-svc:61616"
+	bridge.Generation = 2
+	config = bridgeConfiguration(bridge, "rotated", "secret")
+	assert.Empty(t, applyBridge(bridge, config, "2", brokers))
+	assert.Equal(t, map[string]string{"to-dr2": "orders->tcp://dr2-hdls-svc:61616 as rotated"}, first.bridges)
+	assert.Equal(t, map[string]string{"to-dr2-connector": "tcp://dr2-hdls-svc:61616"}, first.connectors)
+
+	destroyBridge(bridge.Status.BridgeName, brokers)
+	assert.Empty(t, first.bridges)
+	assert.Empty(t, first.connectors)
+	assert.Empty(t, second.bridges)
+
+	negative := int32(-2)
+	for _, invalid := range []brokerv1beta1.ActiveMQArtemisBridgeSpec{
+		{Target: brokerv1beta1.BridgeTargetType{ConnectorUrl: "tcp://dr-hdls-svc:61616"}},
+		{QueueName: "orders"},
+		{QueueName: "orders", Target: brokerv1beta1.BridgeTargetType{ConnectorUrl: "dr-hdls-svc"}},
+		{BridgeName: "two words", QueueName: "orders", Target: brokerv1beta1.BridgeTargetType{ConnectorUrl: "tcp://dr-hdls-svc:61616"}},
+		{QueueName: "orders", Target: brokerv1beta1.BridgeTargetType{ConnectorUrl: "tcp://dr-hdls-svc:61616"}, ProducerWindowSize: &negative},
+		{QueueName: "orders", Target: brokerv1beta1.BridgeTargetType{ConnectorUrl: "tcp://dr-hdls-svc:61616"}, ReconnectPolicy: &brokerv1beta1.BridgeReconnectPolicyType{RetryIntervalMultiplier: "0.5"}},
+		{QueueName: "orders", Target: brokerv1beta1.BridgeTargetType{ConnectorUrl: "tcp://dr-hdls-svc:61616"}, ReconnectPolicy: &brokerv1beta1.BridgeReconnectPolicyType{ReconnectAttempts: &negative}},
+	} {
+		bridge.Spec = invalid
+		assert.Error(t, validateBridge(bridge), invalid)
+	}
+}
+
+func TestBridgeProperties(t *testing.T) {
+	broker := &brokerv1beta1.ActiveMQArtemis{ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: "test"}}
+	orders := &brokerv1beta1.ActiveMQArtemisBridge{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "test"},
+		Spec: brokerv1beta1.ActiveMQArtemisBridgeSpec{
+			QueueName:      "orders",
+			Filter:         "priority > 4",
+			Target:         brokerv1beta1.BridgeTargetType{ConnectorUrl: "tcp://remote:61617?sslEnabled=true", CredentialsSecret: "remote-credentials"},
+			ApplyToCrNames: []string{"broker"},
+		},
+	}
+	missing := &brokerv1beta1.ActiveMQArtemisBridge{
+		ObjectMeta: metav1.ObjectMeta{Name: "missing", Namespace: "test"},
+		Spec: brokerv1beta1.ActiveMQArtemisBridgeSpec{
+			QueueName: "orders",
+			Target:    brokerv1beta1.BridgeTargetType{ConnectorUrl: "tcp://remote:61616", CredentialsSecret: "absent"},
+		},
+	}
+	credentials := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "remote-credentials", Namespace: "test"},
+		Data:       map[string][]byte{"user": []byte("bridge"), "password": []byte("secret")},
+	}
+	client := fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithObjects(broker, orders, missing, credentials).Build()
+
+	// a bridge whose credentials can't be read is left out
+	assert.Equal(t, []string{
+		"connectorConfigurations.orders-connector.factoryClassName=org.apache.activemq.artemis.core.remoting.impl.netty.NettyConnectorFactory",
+		"connectorConfigurations.orders-connector.params.host=remote",
+		"connectorConfigurations.orders-connector.params.port=61617",
+		"connectorConfigurations.orders-connector.params.sslEnabled=true",
+		"bridgeConfigurations.\"orders\".queueName=orders",
+		"bridgeConfigurations.\"orders\".staticConnectors=orders-connector",
+		"bridgeConfigurations.\"orders\".retryInterval=2000",
+		"bridgeConfigurations.\"orders\".retryIntervalMultiplier=1",
+		"bridgeConfigurations.\"orders\".initialConnectAttempts=-1",
+		"bridgeConfigurations.\"orders\".reconnectAttempts=-1",
+		"bridgeConfigurations.\"orders\".useDuplicateDetection=true",
+		"bridgeConfigurations.\"orders\".producerWindowSize=1048576",
+		"bridgeConfigurations.\"orders\".filterString=priority > 4",
+		"bridgeConfigurations.\"orders\".user=bridge",
+		"bridgeConfigurations.\"orders\".password=secret",
+	}, bridgeProperties(broker, client))
+	assert.Empty(t, bridgeProperties(&brokerv1beta1.ActiveMQArtemis{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "test"}}, client))
+
+	// a rotated credentials secret renders the brokers of the bridges that read it again
+	r := &ActiveMQArtemisReconciler{Client: withSecretNamesIndex(t, client)}
+	assert.Len(t, r.brokersRenderingCR(orders), 1)
+	requests := r.brokersUsingSecret(credentials)
+	assert.Len(t, requests, 1)
+	assert.Equal(t, "broker", requests[0].Name)
+}
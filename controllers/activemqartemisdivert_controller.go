@@ -0,0 +1,303 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/common"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/jolokia"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+var dvlog = ctrl.Log.WithName("controller_v1beta1activemqartemisdivert")
+
+const divertFinalizer = "broker.amq.io/divert"
+
+var divertRoutingTypes = []string{"STRIP", "PASS", "ANYCAST", "MULTICAST"}
+
+// the management operations a divert needs from a broker pod
+type divertBroker interface {
+	CreateDivert(divertName string, routingName string, address string, forwardingAddress string, exclusive bool, filter string, transformerClassName string, transformerProperties map[string]string, routingType string) (*jolokia.ResponseData, error)
+	DestroyDivert(divertName string) (*jolokia.ResponseData, error)
+	ListDivertNames() ([]string, error)
+}
+
+// ActiveMQArtemisDivertReconciler reconciles a ActiveMQArtemisDivert object
+type ActiveMQArtemisDivertReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=broker.amq.io,namespace=activemq-artemis-operator,resources=activemqartemisdiverts,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=broker.amq.io,namespace=activemq-artemis-operator,resources=activemqartemisdiverts/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=broker.amq.io,namespace=activemq-artemis-operator,resources=activemqartemisdiverts/finalizers,verbs=update
+
+// Reconcile creates the divert on every running pod of the selected brokers. A pod that doesn't
+// have it, because it started since or lost it in a restart, gets it on the next resync. A change
+// to the spec replaces the divert on every pod
+func (r *ActiveMQArtemisDivertReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	reqLogger := ctrl.LoggerFrom(ctx)
+
+	divert := &brokerv1beta1.ActiveMQArtemisDivert{}
+	if err := r.Client.Get(ctx, request.NamespacedName, divert); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if !divert.DeletionTimestamp.IsZero() {
+		if controllerutil.ContainsFinalizer(divert, divertFinalizer) {
+			if divert.Status.DivertName != "" {
+				destroyDivert(divert.Status.DivertName, r.brokers(divert))
+			}
+			controllerutil.RemoveFinalizer(divert, divertFinalizer)
+			return ctrl.Result{}, r.Client.Update(ctx, divert)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if err := validateDivert(divert); err != nil {
+		recordEvent(ctx, r.Recorder, divert, corev1.EventTypeWarning, brokerv1beta1.ValidConditionInvalidDivertReason, err.Error())
+		meta.SetStatusCondition(&divert.Status.Conditions, metav1.Condition{
+			Type:    brokerv1beta1.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  brokerv1beta1.ValidConditionInvalidDivertReason,
+			Message: err.Error(),
+		})
+		return ctrl.Result{}, r.Client.Status().Update(ctx, divert)
+	}
+	meta.SetStatusCondition(&divert.Status.Conditions, metav1.Condition{
+		Type:   brokerv1beta1.ValidConditionType,
+		Status: metav1.ConditionTrue,
+		Reason: brokerv1beta1.ValidConditionSuccessReason,
+	})
+
+	if !controllerutil.ContainsFinalizer(divert, divertFinalizer) {
+		controllerutil.AddFinalizer(divert, divertFinalizer)
+		if err := r.Client.Update(ctx, divert); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if failed := applyDivert(divert, r.brokers(divert)); len(failed) > 0 {
+		reqLogger.Info("divert is not on every pod", "failed", failed)
+		condition := meta.FindStatusCondition(divert.Status.Conditions, brokerv1beta1.DivertAppliedConditionType)
+		recordEvent(ctx, r.Recorder, divert, corev1.EventTypeWarning, condition.Reason, condition.Message)
+	}
+	if err := r.Client.Status().Update(ctx, divert); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: common.GetReconcileResyncPeriod()}, nil
+}
+
+func (r *ActiveMQArtemisDivertReconciler) brokers(divert *brokerv1beta1.ActiveMQArtemisDivert) map[string]divertBroker {
+	brokers := map[string]divertBroker{}
+	pods, err := runningBrokerPods(r.Client, divert.Namespace, divert.Spec.ApplyToCrNames, nil)
+	if err != nil {
+		dvlog.Error(err, "unable to list the brokers", "namespace", divert.Namespace)
+	}
+	for pod, artemis := range pods {
+		brokers[pod] = artemis
+	}
+	return brokers
+}
+
+func divertName(divert *brokerv1beta1.ActiveMQArtemisDivert) string {
+	if divert.Spec.DivertName != "" {
+		return divert.Spec.DivertName
+	}
+	return divert.Name
+}
+
+func divertRoutingType(divert *brokerv1beta1.ActiveMQArtemisDivert) string {
+	if divert.Spec.RoutingType == "" {
+		return "STRIP"
+	}
+	return strings.ToUpper(divert.Spec.RoutingType)
+}
+
+func validateDivert(divert *brokerv1beta1.ActiveMQArtemisDivert) error {
+	spec := divert.Spec
+	if strings.ContainsAny(divertName(divert), " \t\n") {
+		return fmt.Errorf("divertName %q can't contain white space", divertName(divert))
+	}
+	if spec.Address == "" || spec.ForwardingAddress == "" {
+		return fmt.Errorf("a divert needs an address and a forwardingAddress")
+	}
+	if spec.Address == spec.ForwardingAddress {
+		return fmt.Errorf("forwardingAddress %v is the address the divert is on, the messages would loop", spec.ForwardingAddress)
+	}
+	if !containsString(divertRoutingTypes, divertRoutingType(divert)) {
+		return fmt.Errorf("routingType %q must be one of %v", spec.RoutingType, strings.Join(divertRoutingTypes, ", "))
+	}
+	if len(spec.TransformerProperties) > 0 && spec.TransformerClassName == "" {
+		return fmt.Errorf("transformerProperties are set without a transformerClassName")
+	}
+	return nil
+}
+
+// applyDivert creates the divert on each broker that doesn't have it, after a spec change the divert
+// is replaced on every broker. It returns the pods that failed
+func applyDivert(divert *brokerv1beta1.ActiveMQArtemisDivert, brokers map[string]divertBroker) []string {
+	status := &divert.Status
+	name := divertName(divert)
+	changed := status.DivertName != "" && (status.DivertName != name || status.ObservedGeneration != divert.Generation)
+
+	pods := make([]string, 0, len(brokers))
+	for pod := range brokers {
+		pods = append(pods, pod)
+	}
+	sort.Strings(pods)
+
+	failed := []string{}
+	status.AppliedPods = []string{}
+	for _, pod := range pods {
+		if err := replaceDivert(divert, brokers[pod], changed); err != nil {
+			dvlog.V(1).Info("unable to create divert", "divert", divert.Name, "pod", pod, "error", err.Error())
+			failed = append(failed, pod)
+		} else {
+			status.AppliedPods = append(status.AppliedPods, pod)
+		}
+	}
+
+	// the previous divert is looked for again on the next reconcile until every pod has the new one
+	if len(pods) > 0 && len(failed) == 0 {
+		status.DivertName = name
+		status.ObservedGeneration = divert.Generation
+	}
+
+	condition := metav1.Condition{
+		Type:   brokerv1beta1.DivertAppliedConditionType,
+		Status: metav1.ConditionTrue,
+		Reason: brokerv1beta1.DivertAppliedSuccessReason,
+	}
+	if len(pods) == 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = brokerv1beta1.DivertAppliedNoBrokersReason
+		condition.Message = "no running broker pod is selected"
+	} else if len(failed) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = brokerv1beta1.DivertAppliedPendingReason
+		condition.Message = "unable to create the divert on " + strings.Join(failed, ", ")
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+	return failed
+}
+
+func replaceDivert(divert *brokerv1beta1.ActiveMQArtemisDivert, broker divertBroker, changed bool) error {
+	names, err := broker.ListDivertNames()
+	if err != nil {
+		return err
+	}
+	exists := containsString(names, divertName(divert))
+	if changed && containsString(names, divert.Status.DivertName) {
+		if _, err := broker.DestroyDivert(divert.Status.DivertName); err != nil {
+			return err
+		}
+		exists = exists && divert.Status.DivertName != divertName(divert)
+	}
+	if exists {
+		return nil
+	}
+
+	spec := divert.Spec
+	routingName := spec.RoutingName
+	if routingName == "" {
+		routingName = divertName(divert)
+	}
+	_, err = broker.CreateDivert(divertName(divert), routingName, spec.Address, spec.ForwardingAddress, spec.Exclusive, spec.Filter, spec.TransformerClassName, spec.TransformerProperties, divertRoutingType(divert))
+	return err
+}
+
+// divertProperties renders the divert CRs that select the broker into its broker properties, so a
+// broker that restarts creates the diverts before it takes traffic. A CR that is invalid or being
+// deleted is left out
+func divertProperties(customResource *brokerv1beta1.ActiveMQArtemis, c client.Client) []string {
+	props := []string{}
+	if c == nil {
+		return props
+	}
+	list := &brokerv1beta1.ActiveMQArtemisDivertList{}
+	if err := c.List(context.TODO(), list, client.InNamespace(customResource.Namespace)); err != nil {
+		clog.V(1).Info("unable to list diverts", "namespace", customResource.Namespace, "error", err)
+		return props
+	}
+	sort.Slice(list.Items, func(i, j int) bool { return list.Items[i].Name < list.Items[j].Name })
+	for i := range list.Items {
+		divert := &list.Items[i]
+		if !divert.DeletionTimestamp.IsZero() || !appliesToBroker(divert.Spec.ApplyToCrNames, customResource.Name) || validateDivert(divert) != nil {
+			continue
+		}
+		spec := divert.Spec
+		routingName := spec.RoutingName
+		if routingName == "" {
+			routingName = divertName(divert)
+		}
+		prefix := fmt.Sprintf("divertConfigurations.\"%v\".", divertName(divert))
+		props = append(props,
+			prefix+"routingName="+routingName,
+			prefix+"address="+spec.Address,
+			prefix+"forwardingAddress="+spec.ForwardingAddress,
+			prefix+"exclusive="+strconv.FormatBool(spec.Exclusive),
+			prefix+"routingType="+divertRoutingType(divert))
+		if spec.Filter != "" {
+			props = append(props, prefix+"filterString="+spec.Filter)
+		}
+		if spec.TransformerClassName != "" {
+			props = append(props, prefix+"transformerConfiguration="+spec.TransformerClassName)
+			for _, key := range sortedKeys(spec.TransformerProperties) {
+				props = append(props, fmt.Sprintf("%vtransformerConfiguration.properties.\"%v\"=%v", prefix, key, spec.TransformerProperties[key]))
+			}
+		}
+	}
+	return props
+}
+
+func destroyDivert(name string, brokers map[string]divertBroker) {
+	for pod, broker := range brokers {
+		names, err := broker.ListDivertNames()
+		if err == nil && !containsString(names, name) {
+			continue
+		}
+		if data, err := broker.DestroyDivert(name); err != nil {
+			dvlog.V(1).Info("unable to destroy divert", "divert", name, "pod", pod, "error", err.Error(), "details", data)
+		}
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ActiveMQArtemisDivertReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&brokerv1beta1.ActiveMQArtemisDivert{}).
+		Complete(withCorrelation("activemqartemisdivert", r))
+}
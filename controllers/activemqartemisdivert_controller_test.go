@@ -0,0 +1,132 @@
+package controllers
+
+import (
+	"errors"
+	"testing"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/jolokia"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type fakeDivertBroker struct {
+	diverts map[string]string
+	fail    bool
+}
+
+func (b *fakeDivertBroker) CreateDivert(divertName string, routingName string, address string, forwardingAddress string, exclusive bool, filter string, transformerClassName string, transformerProperties map[string]string, routingType string) (*jolokia.ResponseData, error) {
+	if b.fail {
+		return &jolokia.ResponseData{Status: 500}, errors.New("unavailable")
+	}
+	b.diverts[divertName] = address + "->" + forwardingAddress
+	return &jolokia.ResponseData{Status: 200}, nil
+}
+
+func (b *fakeDivertBroker) DestroyDivert(divertName string) (*jolokia.ResponseData, error) {
+	delete(b.diverts, divertName)
+	return &jolokia.ResponseData{Status: 200}, nil
+}
+
+func (b *fakeDivertBroker) ListDivertNames() ([]string, error) {
+	if b.fail {
+		return nil, errors.New("unavailable")
+	}
+	return settingMatches(b.diverts), nil
+}
+
+func TestDivert(t *testing.T) {
+	divert := &brokerv1beta1.ActiveMQArtemisDivert{
+		ObjectMeta: metav1.ObjectMeta{Name: "priority", Generation: 1},
+		Spec: brokerv1beta1.ActiveMQArtemisDivertSpec{
+			Address:           "orders",
+			ForwardingAddress: "orders.priority",
+			Filter:            "priority > 4",
+		},
+	}
+	assert.NoError(t, validateDivert(divert))
+	assert.Equal(t, "STRIP", divertRoutingType(divert))
+
+	// the second pod is retried until it has the divert
+	first := &fakeDivertBroker{diverts: map[string]string{}}
+	second := &fakeDivertBroker{diverts: map[string]string{}, fail: true}
+	brokers := map[string]divertBroker{"broker-ss-0": first, "broker-ss-1": second}
+	assert.Equal(t, []string{"broker-ss-1"}, applyDivert(divert, brokers))
+	assert.Equal(t, map[string]string{"priority": "orders->orders.priority"}, first.diverts)
+	assert.Empty(t, divert.Status.DivertName)
+	condition := meta.FindStatusCondition(divert.Status.Conditions, brokerv1beta1.DivertAppliedConditionType)
+	assert.Equal(t, brokerv1beta1.DivertAppliedPendingReason, condition.Reason)
+
+	second.fail = false
+	assert.Empty(t, applyDivert(divert, brokers))
+	assert.Equal(t, []string{"broker-ss-0", "broker-ss-1"}, divert.Status.AppliedPods)
+	assert.Equal(t, "priority", divert.Status.DivertName)
+	assert.Equal(t, int64(1), divert.Status.ObservedGeneration)
+
+	// a pod that lost the divert gets it back, a spec change replaces it everywhere
+	delete(first.diverts, "priority")
+	assert.Empty(t, applyDivert(divert, brokers))
+	assert.Contains(t, first.diverts, "priority")
+
+	divert.Spec.DivertName = "urgent"
+	divert.Spec.ForwardingAddress = "orders.urgent"
+	divert.Generation = 2
+	assert.Empty(t, applyDivert(divert, brokers))
+	assert.Equal(t, map[string]string{"urgent": "orders->orders.urgent"}, first.diverts)
+	assert.Equal(t, map[string]string{"urgent": "orders->orders.urgent"}, second.diverts)
+	assert.Equal(t, "urgent", divert.Status.DivertName)
+
+	destroyDivert(divert.Status.DivertName, brokers)
+	assert.Empty(t, first.diverts)
+	assert.Empty(t, second.diverts)
+
+	for _, invalid := range []brokerv1beta1.ActiveMQArtemisDivertSpec{
+		{Address: "orders"},
+		{Address: "orders", ForwardingAddress: "orders"},
+		{Address: "orders", ForwardingAddress: "audit", RoutingType: "copy"},
+		{Address: "orders", ForwardingAddress: "audit", TransformerProperties: map[string]string{"key": "value"}},
+		{DivertName: "two words", Address: "orders", ForwardingAddress: "audit"},
+	} {
+		divert.Spec = invalid
+		assert.Error(t, validateDivert(divert), invalid)
+	}
+}
+
+func TestDivertProperties(t *testing.T) {
+	broker := &brokerv1beta1.ActiveMQArtemis{ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: "test"}}
+	audit := &brokerv1beta1.ActiveMQArtemisDivert{
+		ObjectMeta: metav1.ObjectMeta{Name: "audit", Namespace: "test"},
+		Spec: brokerv1beta1.ActiveMQArtemisDivertSpec{
+			Address:               "orders",
+			ForwardingAddress:     "audit",
+			Filter:                "priority > 4",
+			TransformerClassName:  "org.example.Redact",
+			TransformerProperties: map[string]string{"field": "card"},
+			ApplyToCrNames:        []string{"broker"},
+		},
+	}
+	looping := &brokerv1beta1.ActiveMQArtemisDivert{
+		ObjectMeta: metav1.ObjectMeta{Name: "looping", Namespace: "test"},
+		Spec:       brokerv1beta1.ActiveMQArtemisDivertSpec{Address: "orders", ForwardingAddress: "orders"},
+	}
+	client := fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithObjects(broker, audit, looping).Build()
+
+	// an invalid divert is left out
+	assert.Equal(t, []string{
+		"divertConfigurations.\"audit\".routingName=audit",
+		"divertConfigurations.\"audit\".address=orders",
+		"divertConfigurations.\"audit\".forwardingAddress=audit",
+		"divertConfigurations.\"audit\".exclusive=false",
+		"divertConfigurations.\"audit\".routingType=STRIP",
+		"divertConfigurations.\"audit\".filterString=priority > 4",
+		"divertConfigurations.\"audit\".transformerConfiguration=org.example.Redact",
+		"divertConfigurations.\"audit\".transformerConfiguration.properties.\"field\"=card",
+	}, divertProperties(broker, client))
+
+	r := &ActiveMQArtemisReconciler{Client: client}
+	assert.Len(t, r.brokersRenderingCR(audit), 1)
+	assert.Empty(t, divertProperties(&brokerv1beta1.ActiveMQArtemis{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "test"}}, client))
+}
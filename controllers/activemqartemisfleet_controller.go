@@ -0,0 +1,276 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/common"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+var flog = ctrl.Log.WithName("controller_v1beta1activemqartemisfleet")
+
+// ActiveMQArtemisFleetReconciler reconciles a ActiveMQArtemisFleet object
+type ActiveMQArtemisFleetReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+//+kubebuilder:rbac:groups=broker.amq.io,namespace=activemq-artemis-operator,resources=activemqartemisfleets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=broker.amq.io,namespace=activemq-artemis-operator,resources=activemqartemisfleets/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=broker.amq.io,namespace=activemq-artemis-operator,resources=activemqartemisfleets/finalizers,verbs=update
+
+// Reconcile creates, updates and removes the ActiveMQArtemis members of a fleet and
+// aggregates their readiness into the status of the fleet
+func (r *ActiveMQArtemisFleetReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	reqLogger := ctrl.LoggerFrom(ctx)
+
+	fleet := &brokerv1beta1.ActiveMQArtemisFleet{}
+	if err := r.Client.Get(ctx, request.NamespacedName, fleet); err != nil {
+		if errors.IsNotFound(err) {
+			// members are garbage collected through their owner reference
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	members, err := fleetMembers(fleet)
+	if err != nil {
+		reqLogger.Info("invalid fleet", "reason", err.Error())
+		meta.SetStatusCondition(&fleet.Status.Conditions, metav1.Condition{
+			Type:    brokerv1beta1.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  brokerv1beta1.ValidConditionInvalidOverrideReason,
+			Message: err.Error(),
+		})
+		common.SetReadyCondition(&fleet.Status.Conditions)
+		return ctrl.Result{}, r.Client.Status().Update(ctx, fleet)
+	}
+	meta.SetStatusCondition(&fleet.Status.Conditions, metav1.Condition{
+		Type:   brokerv1beta1.ValidConditionType,
+		Status: metav1.ConditionTrue,
+		Reason: brokerv1beta1.ValidConditionSuccessReason,
+	})
+
+	for _, desired := range members {
+		if err := r.applyMember(ctx, fleet, desired); err != nil {
+			reqLogger.Error(err, "failed to apply fleet member", "member", desired.Name)
+			return ctrl.Result{}, err
+		}
+	}
+
+	existing := &brokerv1beta1.ActiveMQArtemisList{}
+	if err := r.Client.List(ctx, existing, client.InNamespace(fleet.Namespace), client.MatchingLabels{brokerv1beta1.FleetLabel: fleet.Name}); err != nil {
+		return ctrl.Result{}, err
+	}
+	for i := range existing.Items {
+		member := &existing.Items[i]
+		if ordinal, found := fleetOrdinal(fleet, member); found && ordinal < fleet.Spec.Size {
+			continue
+		}
+		if !metav1.IsControlledBy(member, fleet) {
+			continue
+		}
+		reqLogger.Info("removing fleet member beyond size", "member", member.Name)
+		if err := r.Client.Delete(ctx, member); err != nil && !errors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if err := r.Client.List(ctx, existing, client.InNamespace(fleet.Namespace), client.MatchingLabels{brokerv1beta1.FleetLabel: fleet.Name}); err != nil {
+		return ctrl.Result{}, err
+	}
+	updateFleetStatus(fleet, existing.Items)
+	return ctrl.Result{}, r.Client.Status().Update(ctx, fleet)
+}
+
+func (r *ActiveMQArtemisFleetReconciler) applyMember(ctx context.Context, fleet *brokerv1beta1.ActiveMQArtemisFleet, desired *brokerv1beta1.ActiveMQArtemis) error {
+	current := &brokerv1beta1.ActiveMQArtemis{}
+	err := r.Client.Get(ctx, client.ObjectKeyFromObject(desired), current)
+	if errors.IsNotFound(err) {
+		if err := controllerutil.SetControllerReference(fleet, desired, r.Scheme); err != nil {
+			return err
+		}
+		return r.Client.Create(ctx, desired)
+	}
+	if err != nil {
+		return err
+	}
+	if !metav1.IsControlledBy(current, fleet) {
+		return fmt.Errorf("%v exists and is not a member of fleet %v", current.Name, fleet.Name)
+	}
+
+	changed := !equality.Semantic.DeepEqual(current.Spec, desired.Spec)
+	current.Spec = desired.Spec
+	if current.Labels == nil {
+		current.Labels = map[string]string{}
+	}
+	for k, v := range desired.Labels {
+		if current.Labels[k] != v {
+			current.Labels[k] = v
+			changed = true
+		}
+	}
+	if current.Annotations == nil {
+		current.Annotations = map[string]string{}
+	}
+	for k, v := range desired.Annotations {
+		if current.Annotations[k] != v {
+			current.Annotations[k] = v
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return r.Client.Update(ctx, current)
+}
+
+// fleetMembers renders the ActiveMQArtemis of every ordinal of the fleet
+func fleetMembers(fleet *brokerv1beta1.ActiveMQArtemisFleet) ([]*brokerv1beta1.ActiveMQArtemis, error) {
+	overrides := map[int32]*brokerv1beta1.ActiveMQArtemisFleetOverride{}
+	for i := range fleet.Spec.Overrides {
+		override := &fleet.Spec.Overrides[i]
+		if override.Ordinal < 0 || override.Ordinal >= fleet.Spec.Size {
+			return nil, fmt.Errorf("override ordinal %d is outside of the fleet of size %d", override.Ordinal, fleet.Spec.Size)
+		}
+		if _, duplicate := overrides[override.Ordinal]; duplicate {
+			return nil, fmt.Errorf("ordinal %d has more than one override", override.Ordinal)
+		}
+		overrides[override.Ordinal] = override
+	}
+
+	template, err := json.Marshal(fleet.Spec.Template.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	var members []*brokerv1beta1.ActiveMQArtemis
+	for ordinal := int32(0); ordinal < fleet.Spec.Size; ordinal++ {
+		member := &brokerv1beta1.ActiveMQArtemis{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        fleetMemberName(fleet, ordinal),
+				Namespace:   fleet.Namespace,
+				Labels:      map[string]string{},
+				Annotations: map[string]string{},
+			},
+		}
+		for k, v := range fleet.Spec.Template.Labels {
+			member.Labels[k] = v
+		}
+		for k, v := range fleet.Spec.Template.Annotations {
+			member.Annotations[k] = v
+		}
+
+		spec := template
+		if override, found := overrides[ordinal]; found {
+			for k, v := range override.Labels {
+				member.Labels[k] = v
+			}
+			if override.Spec != nil && len(override.Spec.Raw) > 0 {
+				spec, err = strategicpatch.StrategicMergePatch(template, override.Spec.Raw, brokerv1beta1.ActiveMQArtemisSpec{})
+				if err != nil {
+					return nil, fmt.Errorf("override of ordinal %d does not apply: %v", ordinal, err)
+				}
+			}
+		}
+		if err := json.Unmarshal(spec, &member.Spec); err != nil {
+			return nil, fmt.Errorf("override of ordinal %d does not apply: %v", ordinal, err)
+		}
+		member.Labels[brokerv1beta1.FleetLabel] = fleet.Name
+		members = append(members, member)
+	}
+	return members, nil
+}
+
+func fleetMemberName(fleet *brokerv1beta1.ActiveMQArtemisFleet, ordinal int32) string {
+	return fleet.Name + "-" + strconv.Itoa(int(ordinal))
+}
+
+func fleetOrdinal(fleet *brokerv1beta1.ActiveMQArtemisFleet, member *brokerv1beta1.ActiveMQArtemis) (int32, bool) {
+	if !strings.HasPrefix(member.Name, fleet.Name+"-") {
+		return 0, false
+	}
+	ordinal, err := strconv.Atoi(strings.TrimPrefix(member.Name, fleet.Name+"-"))
+	if err != nil {
+		return 0, false
+	}
+	return int32(ordinal), true
+}
+
+func updateFleetStatus(fleet *brokerv1beta1.ActiveMQArtemisFleet, members []brokerv1beta1.ActiveMQArtemis) {
+	fleet.Status.Brokers = nil
+	fleet.Status.ReadyBrokers = 0
+	for i := range members {
+		member := &members[i]
+		if !member.DeletionTimestamp.IsZero() {
+			continue
+		}
+		status := brokerv1beta1.ActiveMQArtemisFleetBrokerStatus{Name: member.Name}
+		ready := meta.FindStatusCondition(member.Status.Conditions, brokerv1beta1.ReadyConditionType)
+		if ready != nil && ready.Status == metav1.ConditionTrue {
+			status.Ready = true
+			fleet.Status.ReadyBrokers++
+		} else if ready != nil {
+			status.Message = ready.Message
+		} else {
+			status.Message = "waiting for the broker status"
+		}
+		fleet.Status.Brokers = append(fleet.Status.Brokers, status)
+	}
+	sort.Slice(fleet.Status.Brokers, func(i, j int) bool {
+		return fleet.Status.Brokers[i].Name < fleet.Status.Brokers[j].Name
+	})
+
+	deployed := metav1.Condition{
+		Type:    brokerv1beta1.DeployedConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  brokerv1beta1.DeployedConditionReadyReason,
+		Message: fmt.Sprintf("%d of %d brokers ready", fleet.Status.ReadyBrokers, fleet.Spec.Size),
+	}
+	if fleet.Spec.Size == 0 {
+		deployed.Reason = brokerv1beta1.DeployedConditionZeroSizeReason
+	} else if fleet.Status.ReadyBrokers < fleet.Spec.Size {
+		deployed.Status = metav1.ConditionFalse
+		deployed.Reason = brokerv1beta1.DeployedConditionNotReadyReason
+	}
+	meta.SetStatusCondition(&fleet.Status.Conditions, deployed)
+	common.SetReadyCondition(&fleet.Status.Conditions)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ActiveMQArtemisFleetReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&brokerv1beta1.ActiveMQArtemisFleet{}).
+		Owns(&brokerv1beta1.ActiveMQArtemis{}).
+		Complete(withCorrelation("activemqartemisfleet", r))
+}
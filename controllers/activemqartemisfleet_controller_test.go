@@ -0,0 +1,93 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/common"
+	"github.com/stretchr/testify/assert"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestFleetReconcile(t *testing.T) {
+	testScheme := newTestScheme(t)
+
+	fleet := &brokerv1beta1.ActiveMQArtemisFleet{
+		ObjectMeta: metav1.ObjectMeta{Name: "fleet", Namespace: "test", UID: "fleet-uid"},
+		Spec: brokerv1beta1.ActiveMQArtemisFleetSpec{
+			Size: 3,
+			Template: brokerv1beta1.ActiveMQArtemisFleetTemplate{
+				Labels: map[string]string{"team": "a"},
+				Spec: brokerv1beta1.ActiveMQArtemisSpec{
+					DeploymentPlan: brokerv1beta1.DeploymentPlanType{Size: common.Int32ToPtr(1)},
+					Acceptors:      []brokerv1beta1.AcceptorType{{Name: "amqp", Port: 5672}},
+				},
+			},
+			Overrides: []brokerv1beta1.ActiveMQArtemisFleetOverride{{
+				Ordinal: 1,
+				Labels:  map[string]string{"zone": "b"},
+				Spec:    &runtime.RawExtension{Raw: []byte(`{"deploymentPlan":{"size":2}}`)},
+			}},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(fleet).Build()
+	r := &ActiveMQArtemisFleetReconciler{Client: fakeClient, Scheme: testScheme}
+	request := ctrl.Request{NamespacedName: types.NamespacedName{Name: "fleet", Namespace: "test"}}
+
+	_, err := r.Reconcile(context.TODO(), request)
+	assert.NoError(t, err)
+
+	members := &brokerv1beta1.ActiveMQArtemisList{}
+	assert.NoError(t, fakeClient.List(context.TODO(), members))
+	assert.Len(t, members.Items, 3)
+	for _, member := range members.Items {
+		assert.True(t, metav1.IsControlledBy(&member, fleet))
+		assert.Equal(t, "fleet", member.Labels[brokerv1beta1.FleetLabel])
+		assert.Equal(t, "a", member.Labels["team"])
+		assert.Len(t, member.Spec.Acceptors, 1)
+		if member.Name == "fleet-1" {
+			assert.Equal(t, int32(2), *member.Spec.DeploymentPlan.Size)
+			assert.Equal(t, "b", member.Labels["zone"])
+		} else {
+			assert.Equal(t, int32(1), *member.Spec.DeploymentPlan.Size)
+		}
+	}
+
+	// readiness of the members is aggregated
+	ready := &brokerv1beta1.ActiveMQArtemis{}
+	assert.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Name: "fleet-0", Namespace: "test"}, ready))
+	meta.SetStatusCondition(&ready.Status.Conditions, metav1.Condition{Type: brokerv1beta1.ReadyConditionType, Status: metav1.ConditionTrue, Reason: brokerv1beta1.ReadyConditionReason})
+	assert.NoError(t, fakeClient.Status().Update(context.TODO(), ready))
+
+	// shrinking removes the highest ordinals
+	assert.NoError(t, fakeClient.Get(context.TODO(), request.NamespacedName, fleet))
+	fleet.Spec.Size = 2
+	assert.NoError(t, fakeClient.Update(context.TODO(), fleet))
+	_, err = r.Reconcile(context.TODO(), request)
+	assert.NoError(t, err)
+
+	assert.NoError(t, fakeClient.List(context.TODO(), members))
+	assert.Len(t, members.Items, 2)
+	assert.NoError(t, fakeClient.Get(context.TODO(), request.NamespacedName, fleet))
+	assert.Equal(t, int32(1), fleet.Status.ReadyBrokers)
+	assert.Len(t, fleet.Status.Brokers, 2)
+	assert.True(t, fleet.Status.Brokers[0].Ready)
+	assert.False(t, meta.IsStatusConditionTrue(fleet.Status.Conditions, brokerv1beta1.ReadyConditionType))
+
+	// an override beyond the size is rejected
+	fleet.Spec.Overrides[0].Ordinal = 2
+	assert.NoError(t, fakeClient.Update(context.TODO(), fleet))
+	_, err = r.Reconcile(context.TODO(), request)
+	assert.NoError(t, err)
+	assert.NoError(t, fakeClient.Get(context.TODO(), request.NamespacedName, fleet))
+	valid := meta.FindStatusCondition(fleet.Status.Conditions, brokerv1beta1.ValidConditionType)
+	assert.Equal(t, metav1.ConditionFalse, valid.Status)
+	assert.Equal(t, brokerv1beta1.ValidConditionInvalidOverrideReason, valid.Reason)
+}
@@ -0,0 +1,403 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	ss "github.com/artemiscloud/activemq-artemis-operator/pkg/resources/statefulsets"
+	mgmt "github.com/artemiscloud/activemq-artemis-operator/pkg/utils/artemis"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/common"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/jolokia"
+	jc "github.com/artemiscloud/activemq-artemis-operator/pkg/utils/jolokia_client"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+var qmlog = ctrl.Log.WithName("controller_v1beta1activemqartemisqueuemigration")
+
+const (
+	queueMigrationFinalizer = "broker.amq.io/queue-migration"
+
+	defaultMigrationProducerWindowSize int32 = 1048576
+)
+
+// the management operations a migration needs from a broker pod
+type queueMigrationBroker interface {
+	GetQueueMessageCount(addressName string, routingType string, queueName string) (int64, error)
+	GetBridgeMessagesAcknowledged(bridgeName string) (int64, error)
+	CreateQueue(addressName string, queueName string, routingType string) (*jolokia.ResponseData, error)
+	DeleteQueue(queueName string) (*jolokia.ResponseData, error)
+	AddConnector(connectorName string, connectorUrl string) (*jolokia.ResponseData, error)
+	RemoveConnector(connectorName string) (*jolokia.ResponseData, error)
+	CreateBridge(bridgeName string, queueName string, forwardingAddress string, connectorName string, producerWindowSize int32, user string, password string) (*jolokia.ResponseData, error)
+	DestroyBridge(bridgeName string) (*jolokia.ResponseData, error)
+	ListBridgeNames() ([]string, error)
+}
+
+// ActiveMQArtemisQueueMigrationReconciler reconciles a ActiveMQArtemisQueueMigration object
+type ActiveMQArtemisQueueMigrationReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+//+kubebuilder:rbac:groups=broker.amq.io,namespace=activemq-artemis-operator,resources=activemqartemisqueuemigrations,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=broker.amq.io,namespace=activemq-artemis-operator,resources=activemqartemisqueuemigrations/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=broker.amq.io,namespace=activemq-artemis-operator,resources=activemqartemisqueuemigrations/finalizers,verbs=update
+
+// Reconcile bridges the queue from each source pod to the target, tracks the
+// progress of the bridges and removes them once the source queue has drained
+func (r *ActiveMQArtemisQueueMigrationReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	reqLogger := ctrl.LoggerFrom(ctx)
+
+	migration := &brokerv1beta1.ActiveMQArtemisQueueMigration{}
+	if err := r.Client.Get(ctx, request.NamespacedName, migration); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if !migration.DeletionTimestamp.IsZero() {
+		if controllerutil.ContainsFinalizer(migration, queueMigrationFinalizer) {
+			removeMigrationBridges(migration, r.brokers(migration.Namespace, migration.Spec.Source.BrokerName))
+			controllerutil.RemoveFinalizer(migration, queueMigrationFinalizer)
+			return ctrl.Result{}, r.Client.Update(ctx, migration)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if migration.Status.Phase == brokerv1beta1.QueueMigrationPhaseCompleted {
+		return ctrl.Result{}, nil
+	}
+
+	if err := validateQueueMigration(migration); err != nil {
+		meta.SetStatusCondition(&migration.Status.Conditions, metav1.Condition{
+			Type:    brokerv1beta1.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  brokerv1beta1.ValidConditionInvalidMigrationReason,
+			Message: err.Error(),
+		})
+		return ctrl.Result{}, r.Client.Status().Update(ctx, migration)
+	}
+	meta.SetStatusCondition(&migration.Status.Conditions, metav1.Condition{
+		Type:   brokerv1beta1.ValidConditionType,
+		Status: metav1.ConditionTrue,
+		Reason: brokerv1beta1.ValidConditionSuccessReason,
+	})
+
+	if !controllerutil.ContainsFinalizer(migration, queueMigrationFinalizer) {
+		controllerutil.AddFinalizer(migration, queueMigrationFinalizer)
+		if err := r.Client.Update(ctx, migration); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	target := r.brokers(migration.Namespace, migration.Spec.Target.BrokerName)[migrationTargetPod(migration)]
+	sources := map[string]queueMigrationBroker{}
+	for pod, broker := range r.brokers(migration.Namespace, migration.Spec.Source.BrokerName) {
+		if migration.Spec.Source.Ordinal == nil || pod == migrationPod(migration.Spec.Source.BrokerName, *migration.Spec.Source.Ordinal) {
+			sources[pod] = broker
+		}
+	}
+	user, password := r.clusterCredentials(migration.Namespace, migration.Spec.Target.BrokerName)
+
+	targetBroker := &brokerv1beta1.ActiveMQArtemis{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: migration.Namespace, Name: migration.Spec.Target.BrokerName}, targetBroker); err != nil {
+		reqLogger.Info("queue migration is waiting", "reason", err.Error())
+	} else if targetUrl, err := migrationTargetUrl(migration, targetBroker); err != nil {
+		reqLogger.Info("queue migration is waiting", "reason", err.Error())
+	} else if err := advanceQueueMigration(migration, sources, target, targetUrl, user, password); err != nil {
+		reqLogger.Info("queue migration is waiting", "reason", err.Error())
+	}
+	if err := r.Client.Status().Update(ctx, migration); err != nil {
+		return ctrl.Result{}, err
+	}
+	if migration.Status.Phase == brokerv1beta1.QueueMigrationPhaseCompleted {
+		controllerutil.RemoveFinalizer(migration, queueMigrationFinalizer)
+		return ctrl.Result{}, r.Client.Update(ctx, migration)
+	}
+	return ctrl.Result{RequeueAfter: common.GetReconcileResyncPeriod()}, nil
+}
+
+// brokers returns the management clients of the running pods of a broker by pod name
+func (r *ActiveMQArtemisQueueMigrationReconciler) brokers(namespace string, brokerName string) map[string]queueMigrationBroker {
+	crName := types.NamespacedName{Namespace: namespace, Name: brokerName}
+	brokers := map[string]queueMigrationBroker{}
+	for _, info := range jc.GetBrokers(crName, ss.GetDeployedStatefulSetNames(r.Client, []types.NamespacedName{crName}), r.Client) {
+		brokers[MakeNamers(&brokerv1beta1.ActiveMQArtemis{ObjectMeta: metav1.ObjectMeta{Name: brokerName}}).SsNameBuilder.Name()+"-"+info.Ordinal] = info.Artemis
+	}
+	return brokers
+}
+
+// the bridges authenticate to the target with its cluster credentials
+func (r *ActiveMQArtemisQueueMigrationReconciler) clusterCredentials(namespace string, brokerName string) (string, string) {
+	secret := &corev1.Secret{}
+	name := MakeNamers(&brokerv1beta1.ActiveMQArtemis{ObjectMeta: metav1.ObjectMeta{Name: brokerName}}).SecretsCredentialsNameBuilder.Name()
+	if err := r.Client.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: name}, secret); err != nil {
+		qmlog.V(1).Info("unable to read cluster credentials of the target", "secret", name, "error", err.Error())
+		return "", ""
+	}
+	return string(secret.Data["AMQ_CLUSTER_USER"]), string(secret.Data["AMQ_CLUSTER_PASSWORD"])
+}
+
+func validateQueueMigration(migration *brokerv1beta1.ActiveMQArtemisQueueMigration) error {
+	spec := &migration.Spec
+	if spec.AddressName == "" || spec.QueueName == "" {
+		return fmt.Errorf("addressName and queueName are required")
+	}
+	if spec.Source.BrokerName == "" || spec.Target.BrokerName == "" {
+		return fmt.Errorf("source and target brokerName are required")
+	}
+	switch strings.ToLower(spec.RoutingType) {
+	case "", "anycast", "multicast":
+	default:
+		return fmt.Errorf("routingType %v must be anycast or multicast", spec.RoutingType)
+	}
+	if spec.ProducerWindowSize != nil && *spec.ProducerWindowSize == 0 {
+		return fmt.Errorf("producerWindowSize must not be 0")
+	}
+	if spec.Source.BrokerName == spec.Target.BrokerName {
+		if spec.Source.Ordinal == nil || *spec.Source.Ordinal == migrationTargetOrdinal(migration) {
+			return fmt.Errorf("the target pod must not be a source")
+		}
+	}
+	return nil
+}
+
+// advanceQueueMigration moves the migration one step forward, it bridges the
+// queue of every source pod to the target, records the progress of the bridges
+// and removes each bridge once its queue has drained
+func advanceQueueMigration(migration *brokerv1beta1.ActiveMQArtemisQueueMigration, sources map[string]queueMigrationBroker, target queueMigrationBroker, targetUrl string, user string, password string) error {
+	spec := &migration.Spec
+	status := &migration.Status
+	routingType := migrationRoutingType(migration)
+
+	if status.Phase == "" {
+		status.Phase = brokerv1beta1.QueueMigrationPhasePending
+	}
+	if status.Phase == brokerv1beta1.QueueMigrationPhasePending {
+		if target == nil {
+			return fmt.Errorf("target pod %v is not running", migrationTargetPod(migration))
+		}
+		if len(sources) == 0 {
+			return fmt.Errorf("no source pod of %v is running", spec.Source.BrokerName)
+		}
+		if data, err := target.CreateQueue(spec.AddressName, spec.QueueName, routingType); err != nil && mgmt.GetCreationError(data) != mgmt.QUEUE_ALREADY_EXISTS {
+			return fmt.Errorf("unable to create the queue on the target: %v", err)
+		}
+		status.Sources = nil
+		for pod := range sources {
+			status.Sources = append(status.Sources, brokerv1beta1.QueueMigrationSourceStatus{Pod: pod})
+		}
+		sort.Slice(status.Sources, func(i, j int) bool {
+			return status.Sources[i].Pod < status.Sources[j].Pod
+		})
+		status.Phase = brokerv1beta1.QueueMigrationPhaseMigrating
+	}
+
+	var waiting error
+	for i := range status.Sources {
+		source := &status.Sources[i]
+		if source.Drained {
+			continue
+		}
+		broker := sources[source.Pod]
+		if broker == nil {
+			waiting = fmt.Errorf("source pod %v is not running", source.Pod)
+			continue
+		}
+
+		count, err := broker.GetQueueMessageCount(spec.AddressName, routingType, spec.QueueName)
+		if err != nil {
+			waiting = fmt.Errorf("unable to read the queue on %v: %v", source.Pod, err)
+			continue
+		}
+		source.MessageCount = count
+
+		// the bridges are not in the broker configuration, a source pod that restarted comes back
+		// without its bridge and it is created again
+		if source.Bridged {
+			if names, err := broker.ListBridgeNames(); err == nil && !containsString(names, migrationBridgeName(migration)) {
+				source.PreviousMessagesAcknowledged = source.MessagesAcknowledged
+				source.Bridged = false
+				broker.RemoveConnector(migrationConnectorName(migration))
+			}
+		} else {
+			source.InitialMessageCount = count
+		}
+		if !source.Bridged {
+			if _, err := broker.AddConnector(migrationConnectorName(migration), targetUrl); err != nil {
+				waiting = fmt.Errorf("unable to add the connector to the target on %v: %v", source.Pod, err)
+				continue
+			}
+			if _, err := broker.CreateBridge(migrationBridgeName(migration), spec.QueueName, spec.AddressName, migrationConnectorName(migration), migrationProducerWindowSize(migration), user, password); err != nil {
+				waiting = fmt.Errorf("unable to create the bridge on %v: %v", source.Pod, err)
+				continue
+			}
+			source.Bridged = true
+		}
+
+		if acknowledged, err := broker.GetBridgeMessagesAcknowledged(migrationBridgeName(migration)); err == nil {
+			source.MessagesAcknowledged = source.PreviousMessagesAcknowledged + acknowledged
+		}
+
+		if count == 0 {
+			if _, err := broker.DestroyBridge(migrationBridgeName(migration)); err != nil {
+				waiting = fmt.Errorf("unable to remove the bridge on %v: %v", source.Pod, err)
+				continue
+			}
+			broker.RemoveConnector(migrationConnectorName(migration))
+			source.Drained = true
+		}
+	}
+
+	status.MessagesRemaining = 0
+	status.MessagesMoved = 0
+	drained := true
+	var initial int64
+	for _, source := range status.Sources {
+		status.MessagesRemaining += source.MessageCount
+		status.MessagesMoved += source.MessagesAcknowledged
+		initial += source.InitialMessageCount
+		drained = drained && source.Drained
+	}
+	if !drained {
+		return waiting
+	}
+
+	// messages consumed or expired on the source while the bridge ran are not
+	// forwarded, so a shortfall is reported rather than failing the migration
+	verified := metav1.Condition{
+		Type:    brokerv1beta1.VerifiedConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  brokerv1beta1.VerifiedConditionSuccessReason,
+		Message: fmt.Sprintf("%d of %d messages moved", status.MessagesMoved, initial),
+	}
+	if status.MessagesMoved < initial {
+		verified.Status = metav1.ConditionFalse
+		verified.Reason = brokerv1beta1.VerifiedConditionUnaccountedReason
+	}
+	meta.SetStatusCondition(&status.Conditions, verified)
+
+	if spec.RemoveSourceQueue && verified.Status == metav1.ConditionTrue {
+		for _, source := range status.Sources {
+			if broker := sources[source.Pod]; broker == nil {
+				return fmt.Errorf("source pod %v is not running to remove the queue", source.Pod)
+			} else if data, err := broker.DeleteQueue(spec.QueueName); err != nil && !mgmt.IsNotFoundError(data) {
+				return fmt.Errorf("unable to remove the queue on %v: %v", source.Pod, err)
+			}
+		}
+	}
+	status.Phase = brokerv1beta1.QueueMigrationPhaseCompleted
+	return nil
+}
+
+// removeMigrationBridges tears down the bridges of an unfinished migration
+func removeMigrationBridges(migration *brokerv1beta1.ActiveMQArtemisQueueMigration, sources map[string]queueMigrationBroker) {
+	for _, source := range migration.Status.Sources {
+		if !source.Bridged || source.Drained {
+			continue
+		}
+		if broker := sources[source.Pod]; broker != nil {
+			broker.DestroyBridge(migrationBridgeName(migration))
+			broker.RemoveConnector(migrationConnectorName(migration))
+		}
+	}
+}
+
+func migrationRoutingType(migration *brokerv1beta1.ActiveMQArtemisQueueMigration) string {
+	if migration.Spec.RoutingType == "" {
+		return "anycast"
+	}
+	return strings.ToLower(migration.Spec.RoutingType)
+}
+
+func migrationProducerWindowSize(migration *brokerv1beta1.ActiveMQArtemisQueueMigration) int32 {
+	if migration.Spec.ProducerWindowSize == nil {
+		return defaultMigrationProducerWindowSize
+	}
+	return *migration.Spec.ProducerWindowSize
+}
+
+func migrationBridgeName(migration *brokerv1beta1.ActiveMQArtemisQueueMigration) string {
+	return migration.Name + "-bridge"
+}
+
+func migrationConnectorName(migration *brokerv1beta1.ActiveMQArtemisQueueMigration) string {
+	return migration.Name + "-connector"
+}
+
+func migrationTargetOrdinal(migration *brokerv1beta1.ActiveMQArtemisQueueMigration) int32 {
+	if migration.Spec.Target.Ordinal == nil {
+		return 0
+	}
+	return *migration.Spec.Target.Ordinal
+}
+
+func migrationPod(brokerName string, ordinal int32) string {
+	return fmt.Sprintf("%s-%d", MakeNamers(&brokerv1beta1.ActiveMQArtemis{ObjectMeta: metav1.ObjectMeta{Name: brokerName}}).SsNameBuilder.Name(), ordinal)
+}
+
+func migrationTargetPod(migration *brokerv1beta1.ActiveMQArtemisQueueMigration) string {
+	return migrationPod(migration.Spec.Target.BrokerName, migrationTargetOrdinal(migration))
+}
+
+// migrationTargetUrl derives the url of the bridges from the acceptors of the target. The bridges
+// connect to the first CORE acceptor the source pods can reach without a store of their own, or
+// to the one the operator keeps on 61616 when the target has none
+func migrationTargetUrl(migration *brokerv1beta1.ActiveMQArtemisQueueMigration, target *brokerv1beta1.ActiveMQArtemis) (string, error) {
+	namer := MakeNamers(target)
+	host := fmt.Sprintf("%s.%s.%s.svc.cluster.local", migrationTargetPod(migration), namer.SvcHeadlessNameBuilder.Name(), migration.Namespace)
+
+	port61616InUse := false
+	for _, acceptor := range acceptorsWithPorts(target) {
+		if acceptor.Port == 61616 {
+			port61616InUse = true
+		}
+		protocols := strings.ToUpper(acceptor.Protocols)
+		if protocols != "" && protocols != "ALL" && !strings.Contains(protocols, "CORE") && acceptor.Port != 61616 {
+			continue
+		}
+		if acceptor.SSLEnabled || acceptor.SASLMechanisms != "" {
+			continue
+		}
+		return fmt.Sprintf("tcp://%s:%d", host, acceptor.Port), nil
+	}
+	if port61616InUse {
+		return "", fmt.Errorf("no CORE acceptor of %v can be reached without TLS or SASL", target.Name)
+	}
+	return fmt.Sprintf("tcp://%s:61616", host), nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ActiveMQArtemisQueueMigrationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&brokerv1beta1.ActiveMQArtemisQueueMigration{}).
+		Complete(withCorrelation("activemqartemisqueuemigration", r))
+}
@@ -0,0 +1,137 @@
+package controllers
+
+import (
+	"testing"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/common"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/jolokia"
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type fakeMigrationBroker struct {
+	messages     int64
+	acknowledged int64
+	queues       []string
+	bridges      []string
+	connectors   []string
+}
+
+func (b *fakeMigrationBroker) GetQueueMessageCount(addressName string, routingType string, queueName string) (int64, error) {
+	return b.messages, nil
+}
+
+func (b *fakeMigrationBroker) GetBridgeMessagesAcknowledged(bridgeName string) (int64, error) {
+	return b.acknowledged, nil
+}
+
+func (b *fakeMigrationBroker) CreateQueue(addressName string, queueName string, routingType string) (*jolokia.ResponseData, error) {
+	b.queues = append(b.queues, queueName)
+	return &jolokia.ResponseData{Status: 200}, nil
+}
+
+func (b *fakeMigrationBroker) DeleteQueue(queueName string) (*jolokia.ResponseData, error) {
+	b.queues = nil
+	return &jolokia.ResponseData{Status: 200}, nil
+}
+
+func (b *fakeMigrationBroker) AddConnector(connectorName string, connectorUrl string) (*jolokia.ResponseData, error) {
+	b.connectors = append(b.connectors, connectorUrl)
+	return &jolokia.ResponseData{Status: 200}, nil
+}
+
+func (b *fakeMigrationBroker) RemoveConnector(connectorName string) (*jolokia.ResponseData, error) {
+	b.connectors = nil
+	return &jolokia.ResponseData{Status: 200}, nil
+}
+
+func (b *fakeMigrationBroker) CreateBridge(bridgeName string, queueName string, forwardingAddress string, connectorName string, producerWindowSize int32, user string, password string) (*jolokia.ResponseData, error) {
+	b.bridges = append(b.bridges, bridgeName)
+	return &jolokia.ResponseData{Status: 200}, nil
+}
+
+func (b *fakeMigrationBroker) DestroyBridge(bridgeName string) (*jolokia.ResponseData, error) {
+	b.bridges = nil
+	return &jolokia.ResponseData{Status: 200}, nil
+}
+
+func (b *fakeMigrationBroker) ListBridgeNames() ([]string, error) {
+	return b.bridges, nil
+}
+
+func TestAdvanceQueueMigration(t *testing.T) {
+	migration := &brokerv1beta1.ActiveMQArtemisQueueMigration{
+		ObjectMeta: metav1.ObjectMeta{Name: "move", Namespace: "test"},
+		Spec: brokerv1beta1.ActiveMQArtemisQueueMigrationSpec{
+			AddressName:       "orders",
+			QueueName:         "orders",
+			Source:            brokerv1beta1.QueueMigrationEndpointType{BrokerName: "old"},
+			Target:            brokerv1beta1.QueueMigrationEndpointType{BrokerName: "new"},
+			RemoveSourceQueue: true,
+		},
+	}
+	assert.NoError(t, validateQueueMigration(migration))
+	newBroker := &brokerv1beta1.ActiveMQArtemis{ObjectMeta: metav1.ObjectMeta{Name: "new", Namespace: "test"}}
+	targetUrl, err := migrationTargetUrl(migration, newBroker)
+	assert.NoError(t, err)
+	assert.Equal(t, "tcp://new-ss-0.new-hdls-svc.test.svc.cluster.local:61616", targetUrl)
+
+	target := &fakeMigrationBroker{}
+	source := &fakeMigrationBroker{messages: 10, queues: []string{"orders"}}
+	sources := map[string]queueMigrationBroker{"old-ss-0": source}
+
+	// the queue is created on the target and bridged from the source
+	assert.NoError(t, advanceQueueMigration(migration, sources, target, targetUrl, "user", "pass"))
+	assert.Equal(t, brokerv1beta1.QueueMigrationPhaseMigrating, migration.Status.Phase)
+	assert.Equal(t, []string{"orders"}, target.queues)
+	assert.Equal(t, []string{"move-bridge"}, source.bridges)
+	assert.Equal(t, int64(10), migration.Status.Sources[0].InitialMessageCount)
+	assert.Equal(t, int64(10), migration.Status.MessagesRemaining)
+
+	// progress is tracked while the queue drains
+	source.messages, source.acknowledged = 4, 6
+	assert.NoError(t, advanceQueueMigration(migration, sources, target, targetUrl, "user", "pass"))
+	assert.Equal(t, int64(4), migration.Status.MessagesRemaining)
+	assert.Equal(t, int64(6), migration.Status.MessagesMoved)
+	assert.Len(t, source.bridges, 1)
+
+	// a restarted source pod gets its bridge back, the messages moved before are kept
+	source.bridges, source.connectors, source.acknowledged = nil, nil, 0
+	assert.NoError(t, advanceQueueMigration(migration, sources, target, targetUrl, "user", "pass"))
+	assert.Equal(t, []string{"move-bridge"}, source.bridges)
+	assert.Equal(t, []string{targetUrl}, source.connectors)
+	assert.Equal(t, int64(10), migration.Status.Sources[0].InitialMessageCount)
+	assert.Equal(t, int64(6), migration.Status.MessagesMoved)
+
+	// once drained the bridge is removed, the move verified and the source queue removed
+	source.messages, source.acknowledged = 0, 4
+	assert.NoError(t, advanceQueueMigration(migration, sources, target, targetUrl, "user", "pass"))
+	assert.Equal(t, brokerv1beta1.QueueMigrationPhaseCompleted, migration.Status.Phase)
+	assert.Empty(t, source.bridges)
+	assert.Empty(t, source.connectors)
+	assert.Empty(t, source.queues)
+	assert.True(t, meta.IsStatusConditionTrue(migration.Status.Conditions, brokerv1beta1.VerifiedConditionType))
+
+	// the bridges connect to a CORE acceptor of the target
+	newBroker.Spec.Acceptors = []brokerv1beta1.AcceptorType{
+		{Name: "amqp", Protocols: "AMQP"},
+		{Name: "tls", Protocols: "CORE", SSLEnabled: true},
+		{Name: "core", Protocols: "CORE"},
+		{Name: "all", Port: 61616, SASLMechanisms: "GSSAPI"},
+	}
+	targetUrl, err = migrationTargetUrl(migration, newBroker)
+	assert.NoError(t, err)
+	assert.Equal(t, "tcp://new-ss-0.new-hdls-svc.test.svc.cluster.local:61646", targetUrl)
+	newBroker.Spec.Acceptors = newBroker.Spec.Acceptors[3:]
+	_, err = migrationTargetUrl(migration, newBroker)
+	assert.Error(t, err)
+
+	// the target pod can't be one of the sources
+	migration.Spec.Target.BrokerName = "old"
+	assert.Error(t, validateQueueMigration(migration))
+	migration.Spec.Source.Ordinal = common.Int32ToPtr(1)
+	assert.NoError(t, validateQueueMigration(migration))
+}
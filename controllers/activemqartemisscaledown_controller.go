@@ -62,7 +62,7 @@ type ActiveMQArtemisScaledownReconciler struct {
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.10.0/pkg/reconcile
 func (r *ActiveMQArtemisScaledownReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
-	reqLogger := ctrl.Log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name, "Reconciling", "ActiveMQArtemisScaledown")
+	reqLogger := ctrl.LoggerFrom(ctx)
 
 	// Fetch the ActiveMQArtemisScaledown instance
 	instance := &brokerv1beta1.ActiveMQArtemisScaledown{}
@@ -155,5 +155,5 @@ func (r *ActiveMQArtemisScaledownReconciler) SetupWithManager(mgr ctrl.Manager)
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&brokerv1beta1.ActiveMQArtemisScaledown{}).
 		Owns(&corev1.Pod{}).
-		Complete(r)
+		Complete(withCorrelation("activemqartemisscaledown", r))
 }
@@ -18,7 +18,13 @@ package controllers
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
 	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 
 	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
 	"github.com/artemiscloud/activemq-artemis-operator/pkg/resources"
@@ -26,16 +32,21 @@ import (
 	"github.com/artemiscloud/activemq-artemis-operator/pkg/resources/secrets"
 	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/common"
 	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/lsrcrs"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/namer"
 	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/random"
 	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/selectors"
 	"gopkg.in/yaml.v2"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 // ActiveMQArtemisSecurityReconciler reconciles a ActiveMQArtemisSecurity object
@@ -60,7 +71,7 @@ type ActiveMQArtemisSecurityReconciler struct {
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.10.0/pkg/reconcile
 func (r *ActiveMQArtemisSecurityReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
 
-	reqLogger := ctrl.Log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name, "Reconciling", "ActiveMQArtemisSecurity")
+	reqLogger := ctrl.LoggerFrom(ctx)
 
 	instance := &brokerv1beta1.ActiveMQArtemisSecurity{}
 
@@ -82,6 +93,32 @@ func (r *ActiveMQArtemisSecurityReconciler) Reconcile(ctx context.Context, reque
 		return ctrl.Result{RequeueAfter: common.GetReconcileResyncPeriod()}, nil
 	}
 
+	brokers := r.securityBrokerStatuses(ctx, instance)
+	if instance.Status.ObservedGeneration != instance.Generation || !reflect.DeepEqual(instance.Status.Brokers, brokers) {
+		instance.Status.ObservedGeneration = instance.Generation
+		instance.Status.Brokers = brokers
+		if err := r.Client.Status().Update(ctx, instance); err != nil {
+			reqLogger.Error(err, "failed to update the security status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	if target, requested := transferTarget(instance); requested {
+		transferred := &brokerv1beta1.ActiveMQArtemisSecurity{
+			ObjectMeta: transferObjectMeta(instance, target),
+			Spec:       *instance.Spec.DeepCopy(),
+		}
+		transferred.Spec.ApplyToCrNames = transferApplyTo(instance, instance.Spec.ApplyToCrNames)
+		if err := transferCR(ctx, r.Client, r.Scheme, instance, transferred, securityPasswordSecretNames(instance)); isTransferRefused(err) {
+			reqLogger.Info("not transferring security", "namespace", target, "reason", err.Error())
+		} else if err != nil {
+			reqLogger.Error(err, "failed to transfer security", "namespace", target)
+			return ctrl.Result{}, err
+		} else {
+			reqLogger.Info("transferred security", "namespace", target)
+		}
+	}
+
 	toReconcile := true
 	newHandler := &ActiveMQArtemisSecurityConfigHandler{
 		instance,
@@ -127,12 +164,167 @@ type ActiveMQArtemisSecurityConfigHandler struct {
 	owner          *ActiveMQArtemisSecurityReconciler
 }
 
+type securityGrant struct {
+	match         string
+	operationType string
+	role          string
+}
+
+// effectiveSecurityPermissions tells what the addresses of a broker permit. The broker starts from the
+// catch-all match that grants its admin role everything, a match of the CR replaces it, and the reserved
+// address prefixes and the securityRoles of the broker properties add roles to their matches. The broker
+// gives an operation type the roles of all the entries of a match, an address only gets the permissions
+// of its most specific match and the less specific matches are listed as overridden
+func effectiveSecurityPermissions(cr *brokerv1beta1.ActiveMQArtemisSecurity, broker *brokerv1beta1.ActiveMQArtemis) []brokerv1beta1.EffectivePermissionType {
+	grants := []securityGrant{}
+	settings := cr.Spec.SecuritySettings.Broker
+	catchAll := false
+	for _, setting := range settings {
+		catchAll = catchAll || setting.Match == "#"
+	}
+	if !catchAll {
+		for _, operationType := range brokerv1beta1.BrokerPermissionTypes {
+			grants = append(grants, securityGrant{"#", operationType, getAdminRole(broker)})
+		}
+	}
+	for _, setting := range settings {
+		for _, permission := range setting.Permissions {
+			for _, role := range permission.Roles {
+				grants = append(grants, securityGrant{setting.Match, permission.OperationType, role})
+			}
+		}
+	}
+	for _, prop := range append(reservedAddressPrefixProperties(broker), broker.Spec.BrokerProperties...) {
+		if grant, ok := securityRolesGrant(prop); ok {
+			grants = append(grants, grant)
+		}
+	}
+
+	var effective []brokerv1beta1.EffectivePermissionType
+	for _, grant := range grants {
+		var merged *brokerv1beta1.EffectivePermissionType
+		for i := range effective {
+			if effective[i].Match == grant.match {
+				merged = &effective[i]
+			}
+		}
+		if merged == nil {
+			effective = append(effective, brokerv1beta1.EffectivePermissionType{Match: grant.match})
+			merged = &effective[len(effective)-1]
+		}
+		var permission *brokerv1beta1.PermissionType
+		for i := range merged.Permissions {
+			if merged.Permissions[i].OperationType == grant.operationType {
+				permission = &merged.Permissions[i]
+			}
+		}
+		if permission == nil {
+			merged.Permissions = append(merged.Permissions, brokerv1beta1.PermissionType{OperationType: grant.operationType})
+			permission = &merged.Permissions[len(merged.Permissions)-1]
+		}
+		if !containsString(permission.Roles, grant.role) {
+			permission.Roles = append(permission.Roles, grant.role)
+		}
+	}
+	for i := range effective {
+		for j, other := range effective {
+			if j != i && brokerv1beta1.SecurityMatchCovers(other.Match, effective[i].Match) && !brokerv1beta1.SecurityMatchCovers(effective[i].Match, other.Match) {
+				effective[i].Overrides = append(effective[i].Overrides, other.Match)
+			}
+		}
+	}
+	return effective
+}
+
+// securityRolesGrant reads a securityRoles."<match>".<role>.<operation type>=true broker property
+func securityRolesGrant(prop string) (securityGrant, bool) {
+	key, value, found := strings.Cut(strings.TrimSpace(prop), "=")
+	if !found || strings.TrimSpace(value) != "true" || !strings.HasPrefix(key, "securityRoles.") {
+		return securityGrant{}, false
+	}
+	key = strings.TrimSpace(strings.TrimPrefix(key, "securityRoles."))
+	var match string
+	if strings.HasPrefix(key, "\"") {
+		end := strings.Index(key[1:], "\"")
+		if end < 0 {
+			return securityGrant{}, false
+		}
+		match, key = key[1:end+1], strings.TrimPrefix(key[end+2:], ".")
+	} else {
+		match, key, _ = strings.Cut(key, ".")
+	}
+	separator := strings.LastIndex(key, ".")
+	if match == "" || separator <= 0 || !containsString(brokerv1beta1.BrokerPermissionTypes, key[separator+1:]) {
+		return securityGrant{}, false
+	}
+	return securityGrant{match, key[separator+1:], key[:separator]}, true
+}
+
 func getLabels(cr *brokerv1beta1.ActiveMQArtemisSecurity) map[string]string {
 	labelBuilder := selectors.LabelerData{}
 	labelBuilder.Base(cr.Name).Suffix("sec").Generate()
 	return labelBuilder.Labels()
 }
 
+// maskHashedPassword marks a one-way hash with ENC() for the properties login module to verify
+// passwords against it with the default codec, the hash is then all that reaches the broker
+func maskHashedPassword(user *brokerv1beta1.UserType) {
+	if user.PasswordAlgorithm == nil || *user.PasswordAlgorithm != brokerv1beta1.PasswordAlgorithmOneWay || user.Password == nil {
+		return
+	}
+	password := strings.TrimSpace(*user.Password)
+	if !strings.HasPrefix(password, "ENC(") {
+		password = "ENC(" + password + ")"
+	}
+	user.Password = &password
+}
+
+// securitySecretReferences are the secret keys the CR reads instead of holding their values, such as
+// passwords, client secrets and the ldap truststore. The brokers pick up a rotated value on their next reconcile
+func securitySecretReferences(cr *brokerv1beta1.ActiveMQArtemisSecurity) []corev1.SecretKeySelector {
+	references := []corev1.SecretKeySelector{}
+	for _, pm := range cr.Spec.LoginModules.PropertiesLoginModules {
+		for _, user := range pm.Users {
+			if user.PasswordSecret != nil {
+				references = append(references, *user.PasswordSecret)
+			}
+		}
+	}
+	for _, pm := range cr.Spec.LoginModules.KeycloakLoginModules {
+		if pm.Configuration.CredentialsSecret != nil {
+			references = append(references, *pm.Configuration.CredentialsSecret)
+		}
+	}
+	for _, pm := range cr.Spec.LoginModules.LdapLoginModules {
+		if pm.ConnectionPasswordSecret != nil {
+			references = append(references, *pm.ConnectionPasswordSecret)
+		}
+	}
+	if trustStore := ldapTrustStoreSecret(cr); trustStore != "" {
+		for _, key := range []string{"client.ts", "trustStorePassword"} {
+			references = append(references, corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: trustStore}, Key: key})
+		}
+	}
+	if canary := cr.Spec.Canary; canary != nil && canary.Enabled && canary.CredentialsSecret != nil {
+		for _, key := range []string{"user", "password"} {
+			references = append(references, corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: *canary.CredentialsSecret}, Key: key})
+		}
+	}
+	return references
+}
+
+// securityPasswordSecretNames are the secrets the passwords the CR leaves out are generated in
+func securityPasswordSecretNames(cr *brokerv1beta1.ActiveMQArtemisSecurity) []string {
+	names := []string{}
+	for _, pm := range cr.Spec.LoginModules.PropertiesLoginModules {
+		names = append(names, "security-properties-"+pm.Name)
+	}
+	for _, pm := range cr.Spec.LoginModules.KeycloakLoginModules {
+		names = append(names, "security-keycloak-"+pm.Name)
+	}
+	return names
+}
+
 func (r *ActiveMQArtemisSecurityConfigHandler) IsApplicableFor(brokerNamespacedName types.NamespacedName) bool {
 	reqLogger := ctrl.Log.WithValues("IsApplicableFor", brokerNamespacedName)
 
@@ -144,6 +336,14 @@ func (r *ActiveMQArtemisSecurityConfigHandler) IsApplicableFor(brokerNamespacedN
 		reqLogger.V(1).Info("this security cr is not applicable for broker because it's not in my namespace")
 		return false
 	}
+	if selector := r.SecurityCR.Spec.ApplyToCrSelector; selector != nil {
+		broker := &brokerv1beta1.ActiveMQArtemis{}
+		if err := r.owner.Client.Get(context.TODO(), brokerNamespacedName, broker); err != nil {
+			reqLogger.Error(err, "failed to get the broker cr to match applyToCrSelector")
+			return false
+		}
+		return appliesToBrokerCR(applyTo, selector, broker)
+	}
 	if len(applyTo) == 0 {
 		reqLogger.V(1).Info("this security cr is applicable for broker because no applyTo is configured")
 		return true
@@ -166,16 +366,22 @@ func (r *ActiveMQArtemisSecurityConfigHandler) processCrPasswords() *brokerv1bet
 		for i, pm := range result.Spec.LoginModules.PropertiesLoginModules {
 			if len(pm.Users) > 0 {
 				for j, user := range pm.Users {
-					if user.Password == nil {
+					if user.PasswordSecret != nil {
+						reference := securitySecretFileReference(user.PasswordSecret, user.PasswordAlgorithm != nil && *user.PasswordAlgorithm == brokerv1beta1.PasswordAlgorithmOneWay)
+						result.Spec.LoginModules.PropertiesLoginModules[i].Users[j].Password = &reference
+						continue
+					} else if user.Password == nil {
 						result.Spec.LoginModules.PropertiesLoginModules[i].Users[j].Password = r.getPassword("security-properties-"+pm.Name, user.Name)
 					}
+					maskHashedPassword(&result.Spec.LoginModules.PropertiesLoginModules[i].Users[j])
 				}
 			}
 		}
 	}
 
 	if len(result.Spec.LoginModules.KeycloakLoginModules) > 0 {
-		for _, pm := range result.Spec.LoginModules.KeycloakLoginModules {
+		for i := range result.Spec.LoginModules.KeycloakLoginModules {
+			pm := &result.Spec.LoginModules.KeycloakLoginModules[i]
 			keycloakSecretName := "security-keycloak-" + pm.Name
 			if pm.Configuration.ClientKeyStore != nil {
 				if pm.Configuration.ClientKeyPassword == nil {
@@ -190,6 +396,11 @@ func (r *ActiveMQArtemisSecurityConfigHandler) processCrPasswords() *brokerv1bet
 					pm.Configuration.TrustStorePassword = r.getPassword(keycloakSecretName, "trust-store-password")
 				}
 			}
+			if secret := pm.Configuration.CredentialsSecret; secret != nil {
+				if value := r.getSecretValue(secret); value != nil {
+					pm.Configuration.Credentials = append(pm.Configuration.Credentials, brokerv1beta1.KeyValueType{Key: "secret", Value: value})
+				}
+			}
 			//need to process pm.Configuration.Credentials too. later.
 			if len(pm.Configuration.Credentials) > 0 {
 				for i, kv := range pm.Configuration.Credentials {
@@ -199,6 +410,7 @@ func (r *ActiveMQArtemisSecurityConfigHandler) processCrPasswords() *brokerv1bet
 				}
 			}
 		}
+		applyKeycloakRoleMappings(result)
 	}
 	return result
 }
@@ -210,6 +422,128 @@ func (r *ActiveMQArtemisSecurityConfigHandler) GetDefaultLabels() map[string]str
 
 }
 
+// getSecretValue reads a key of a secret the CR references, nil when the secret or the key is missing
+// securitySecretFileReference points the init container at the key of a secret mounted into the pod,
+// security-secrets.py writes its value into the config so it never is in the pod template
+func securitySecretFileReference(selector *corev1.SecretKeySelector, oneWay bool) string {
+	scheme := "secret://"
+	if oneWay {
+		scheme = "secret+enc://"
+	}
+	return scheme + secretPathBase + selector.Name + "/" + selector.Key
+}
+
+// securityFileSecretNames are the secrets whose keys the init container reads from their mounts
+func securityFileSecretNames(cr *brokerv1beta1.ActiveMQArtemisSecurity) []string {
+	names := []string{}
+	for _, pm := range cr.Spec.LoginModules.PropertiesLoginModules {
+		for _, user := range pm.Users {
+			if user.PasswordSecret != nil && !containsString(names, user.PasswordSecret.Name) {
+				names = append(names, user.PasswordSecret.Name)
+			}
+		}
+	}
+	for _, name := range ldapConnectionPasswordSecretNames(cr) {
+		if !containsString(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// ldapConnectionPasswordSecretNames are the secrets of the ldap bind passwords, they are read from their
+// mounts into the login.config
+func ldapConnectionPasswordSecretNames(cr *brokerv1beta1.ActiveMQArtemisSecurity) []string {
+	names := []string{}
+	for _, ldap := range cr.Spec.LoginModules.LdapLoginModules {
+		if ldap.ConnectionUsername != nil && ldap.ConnectionPasswordSecret != nil {
+			names = append(names, ldap.ConnectionPasswordSecret.Name)
+		}
+	}
+	return names
+}
+
+// securitySecretsChecksum changes with the value of any secret key the CR reads, the mounted keys only
+// reach the broker config when the pods restart. A missing secret or key is returned instead
+func (r *ActiveMQArtemisSecurityConfigHandler) securitySecretsChecksum() (string, *corev1.SecretKeySelector) {
+	references := securitySecretReferences(r.SecurityCR)
+	if len(references) == 0 {
+		return "", nil
+	}
+	values := []string{}
+	for _, selector := range references {
+		value := r.getSecretValue(&selector)
+		if value == nil {
+			return "", &selector
+		}
+		values = append(values, selector.Name, selector.Key, *value)
+	}
+	return hex.EncodeToString(alder32Of(values)), nil
+}
+
+func (r *ActiveMQArtemisSecurityConfigHandler) getSecretValue(selector *corev1.SecretKeySelector) *string {
+	secret := &corev1.Secret{}
+	if err := r.owner.Client.Get(context.TODO(), types.NamespacedName{Name: selector.Name, Namespace: r.NamespacedName.Namespace}, secret); err != nil {
+		slog.Error(err, "failed to get secret", "secret", selector.Name)
+		return nil
+	}
+	elem, ok := secret.Data[selector.Key]
+	if !ok {
+		slog.Info("secret has no key", "secret", selector.Name, "key", selector.Key)
+		return nil
+	}
+	value := string(elem)
+	return &value
+}
+
+// applyKeycloakRoleMappings grants the Keycloak roles of the role mappings wherever the security
+// settings grant one of their broker roles, the broker only knows the roles of the token
+func applyKeycloakRoleMappings(cr *brokerv1beta1.ActiveMQArtemisSecurity) {
+	mapped := map[string][]string{}
+	for _, module := range cr.Spec.LoginModules.KeycloakLoginModules {
+		for _, mapping := range module.RoleMappings {
+			for _, brokerRole := range mapping.BrokerRoles {
+				if !containsString(mapped[brokerRole], mapping.KeycloakRole) {
+					mapped[brokerRole] = append(mapped[brokerRole], mapping.KeycloakRole)
+				}
+			}
+		}
+	}
+	if len(mapped) == 0 {
+		return
+	}
+	withMappedRoles := func(roles []string) []string {
+		if len(roles) == 0 {
+			return roles
+		}
+		result := append([]string{}, roles...)
+		for _, role := range roles {
+			for _, keycloakRole := range mapped[role] {
+				if !containsString(result, keycloakRole) {
+					result = append(result, keycloakRole)
+				}
+			}
+		}
+		return result
+	}
+	settings := &cr.Spec.SecuritySettings
+	for i := range settings.Broker {
+		for j := range settings.Broker[i].Permissions {
+			settings.Broker[i].Permissions[j].Roles = withMappedRoles(settings.Broker[i].Permissions[j].Roles)
+		}
+	}
+	settings.Management.HawtioRoles = withMappedRoles(settings.Management.HawtioRoles)
+	for i := range settings.Management.Authorisation.DefaultAccess {
+		settings.Management.Authorisation.DefaultAccess[i].Roles = withMappedRoles(settings.Management.Authorisation.DefaultAccess[i].Roles)
+	}
+	for i := range settings.Management.Authorisation.RoleAccess {
+		for j := range settings.Management.Authorisation.RoleAccess[i].AccessList {
+			access := &settings.Management.Authorisation.RoleAccess[i].AccessList[j]
+			access.Roles = withMappedRoles(access.Roles)
+		}
+	}
+}
+
 //retrive value from secret, generate value if not exist.
 func (r *ActiveMQArtemisSecurityConfigHandler) getPassword(secretName string, key string) *string {
 	//check if the secret exists.
@@ -252,6 +586,12 @@ func (r *ActiveMQArtemisSecurityConfigHandler) getPassword(secretName string, ke
 
 func (r *ActiveMQArtemisSecurityConfigHandler) Config(initContainers []corev1.Container, outputDirRoot string, yacfgProfileVersion string, yacfgProfileName string) (value []string) {
 	ctrl.Log.Info("Reconciling ActiveMQArtemisSecurity", "cr", r.SecurityCR)
+	// the previous config stays in the pods rather than one without the passwords of the missing secret
+	secretsChecksum, missing := r.securitySecretsChecksum()
+	if missing != nil {
+		slog.Info("Not rendering security CR, a secret key it reads is missing", "cr", r.SecurityCR.Name, "secret", missing.Name, "key", missing.Key)
+		return nil
+	}
 	result := r.processCrPasswords()
 	outputDir := outputDirRoot + "/security"
 	var configCmds = []string{"echo \"making dir " + outputDir + "\"", "mkdir -p " + outputDir}
@@ -263,7 +603,23 @@ func (r *ActiveMQArtemisSecurityConfigHandler) Config(initContainers []corev1.Co
 	}
 	slog.Info("get the command", "value", cmdPersistCRAsYaml)
 	configCmds = append(configCmds, cmdPersistCRAsYaml)
+	if len(securityFileSecretNames(r.SecurityCR)) > 0 {
+		configCmds = append(configCmds, "python3 "+initScriptPath(securitySecretsScriptName)+" "+filePath)
+	}
 	configCmds = append(configCmds, "/opt/amq-broker/script/cfg/config-security.sh")
+	if codecCmd := propertiesCodecCmd(result); codecCmd != "" {
+		configCmds = append(configCmds, codecCmd)
+	}
+	if saslCmd := r.saslLoginConfigCmd(result); saslCmd != "" {
+		configCmds = append(configCmds, saslCmd)
+		if len(ldapConnectionPasswordSecretNames(result)) > 0 {
+			configCmds = append(configCmds, "python3 "+initScriptPath(securitySecretsScriptName)+" "+brokerConfigRoot+"/etc/login.config")
+		}
+	}
+	configCmds = append(configCmds, certificateLoginFilesCmds(result)...)
+	if trustStoreCmd := ldapTrustStoreCmd(result); trustStoreCmd != "" {
+		configCmds = append(configCmds, trustStoreCmd)
+	}
 	envVarName := "SECURITY_CFG_YAML"
 	envVar := corev1.EnvVar{
 		Name:      envVarName,
@@ -272,6 +628,14 @@ func (r *ActiveMQArtemisSecurityConfigHandler) Config(initContainers []corev1.Co
 	}
 	environments.Create(initContainers, &envVar)
 
+	if secretsChecksum != "" {
+		envVar = corev1.EnvVar{
+			Name:  securitySecretsChecksumEnvVarName,
+			Value: secretsChecksum,
+		}
+		environments.Create(initContainers, &envVar)
+	}
+
 	envVarName = "YACFG_PROFILE_VERSION"
 	envVar = corev1.EnvVar{
 		Name:      envVarName,
@@ -297,6 +661,46 @@ func (r *ActiveMQArtemisSecurityConfigHandler) persistCR(filePath string, cr *br
 	// remove superfluous data that can trip up the shell
 	stripped := cr.DeepCopy()
 	stripped.ObjectMeta = metav1.ObjectMeta{}
+	// the status changes on its own, it must not roll the brokers
+	stripped.Status = brokerv1beta1.ActiveMQArtemisSecurityStatus{}
+	// the sasl, certificate and ldap modules are rendered by the operator, see saslLoginConfigCmd
+	stripped.Spec.LoginModules.ScramLoginModules = nil
+	stripped.Spec.LoginModules.KerberosLoginModules = nil
+	stripped.Spec.LoginModules.CertificateLoginModules = nil
+	stripped.Spec.LoginModules.LdapLoginModules = nil
+	rendered := operatorLoginModuleNames(cr)
+	stripped.Spec.SecurityDomains.BrokerDomain.LoginModules = withoutLoginModules(stripped.Spec.SecurityDomains.BrokerDomain.LoginModules, rendered)
+	stripped.Spec.SecurityDomains.ConsoleDomain.LoginModules = withoutLoginModules(stripped.Spec.SecurityDomains.ConsoleDomain.LoginModules, rendered)
+	// the denied entries go into the Jolokia policy, see jolokiaAccessCmd
+	stripped.Spec.SecuritySettings.Management.Authorisation.DeniedList = nil
+	// a disabled guest module lets nobody in, neither the module nor the references to it are rendered
+	stripped.Spec.LoginModules.GuestLoginModules = nil
+	disabled := []string{}
+	for _, guest := range cr.Spec.LoginModules.GuestLoginModules {
+		if guestLoginModuleEnabled(guest) {
+			// the role the webhook checks the security settings against, whatever the module defaults to
+			if guest.GuestRole == nil {
+				guestRole := brokerv1beta1.DefaultGuestRole
+				guest.GuestRole = &guestRole
+			}
+			stripped.Spec.LoginModules.GuestLoginModules = append(stripped.Spec.LoginModules.GuestLoginModules, guest)
+		} else {
+			disabled = append(disabled, guest.Name)
+		}
+	}
+	stripped.Spec.SecurityDomains.BrokerDomain.LoginModules = withoutLoginModules(stripped.Spec.SecurityDomains.BrokerDomain.LoginModules, disabled)
+	stripped.Spec.SecurityDomains.ConsoleDomain.LoginModules = withoutLoginModules(stripped.Spec.SecurityDomains.ConsoleDomain.LoginModules, disabled)
+	// the role mappings and the secret references are resolved by processCrPasswords
+	for i := range stripped.Spec.LoginModules.PropertiesLoginModules {
+		for j := range stripped.Spec.LoginModules.PropertiesLoginModules[i].Users {
+			stripped.Spec.LoginModules.PropertiesLoginModules[i].Users[j].PasswordSecret = nil
+			stripped.Spec.LoginModules.PropertiesLoginModules[i].Users[j].PasswordAlgorithm = nil
+		}
+	}
+	for i := range stripped.Spec.LoginModules.KeycloakLoginModules {
+		stripped.Spec.LoginModules.KeycloakLoginModules[i].RoleMappings = nil
+		stripped.Spec.LoginModules.KeycloakLoginModules[i].Configuration.CredentialsSecret = nil
+	}
 
 	data, err := yaml.Marshal(stripped)
 	if err != nil {
@@ -305,9 +709,501 @@ func (r *ActiveMQArtemisSecurityConfigHandler) persistCR(filePath string, cr *br
 	return "echo \"" + string(data) + "\" > " + filePath, nil
 }
 
+func guestLoginModuleEnabled(guest brokerv1beta1.GuestLoginModuleType) bool {
+	return guest.Enabled == nil || *guest.Enabled
+}
+
+func withoutLoginModules(references []brokerv1beta1.LoginModuleReferenceType, names []string) []brokerv1beta1.LoginModuleReferenceType {
+	if len(names) == 0 {
+		return references
+	}
+	kept := []brokerv1beta1.LoginModuleReferenceType{}
+	for _, reference := range references {
+		if reference.Name == nil || !containsString(names, *reference.Name) {
+			kept = append(kept, reference)
+		}
+	}
+	return kept
+}
+
+// inserts the operator rendered modules at the top of their domains of a login.config, adding a domain
+// yacfg left out, and appends the sasl entries,
+// usage: sasl-login-config.py <login.config> <base64 entries> [<domain> <base64 domain modules>]...
+var saslLoginConfigScript = `import base64, sys
+
+path = sys.argv[1]
+entries = base64.b64decode(sys.argv[2]).decode()
+domains = {}
+for i in range(3, len(sys.argv) - 1, 2):
+    domains[sys.argv[i]] = base64.b64decode(sys.argv[i + 1]).decode()
+
+with open(path) as f:
+    lines = f.read().splitlines(True)
+
+out = []
+for line in lines:
+    out.append(line)
+    domain = line.split('{')[0].strip() if '{' in line else None
+    if domain in domains:
+        out.append(domains.pop(domain))
+for domain, modules in domains.items():
+    out.append(chr(10) + domain + ' {' + chr(10) + modules + '};' + chr(10))
+out.append(entries)
+
+with open(path, 'w') as f:
+    f.write(''.join(out))
+`
+
+func getBrokerDomainName(cr *brokerv1beta1.ActiveMQArtemisSecurity) string {
+	if name := cr.Spec.SecurityDomains.BrokerDomain.Name; name != nil && *name != "" {
+		return *name
+	}
+	return brokerv1beta1.DefaultBrokerDomain
+}
+
+// getConsoleDomainName is the domain the console logs in with when it is not the broker domain
+func getConsoleDomainName(cr *brokerv1beta1.ActiveMQArtemisSecurity) string {
+	if name := cr.Spec.SecurityDomains.ConsoleDomain.Name; name != nil && *name != getBrokerDomainName(cr) {
+		return *name
+	}
+	return ""
+}
+
+// operatorLoginModuleNames are the modules rendered by the operator that security domains reference by name
+func operatorLoginModuleNames(cr *brokerv1beta1.ActiveMQArtemisSecurity) []string {
+	names := []string{}
+	for _, certificate := range cr.Spec.LoginModules.CertificateLoginModules {
+		names = append(names, certificate.Name)
+	}
+	for _, ldap := range cr.Spec.LoginModules.LdapLoginModules {
+		names = append(names, ldap.Name)
+	}
+	return names
+}
+
+func domainLoginModuleReference(domain brokerv1beta1.BrokerDomainType, name string) *brokerv1beta1.LoginModuleReferenceType {
+	for i, reference := range domain.LoginModules {
+		if reference.Name != nil && *reference.Name == name {
+			return &domain.LoginModules[i]
+		}
+	}
+	return nil
+}
+
+// domainLoginModules renders the certificate and ldap modules a domain references, with the flag of the
+// reference. A certificate module no domain references stays in the broker domain with its own flag
+func domainLoginModules(cr *brokerv1beta1.ActiveMQArtemisSecurity, domain brokerv1beta1.BrokerDomainType, brokerDomain bool) string {
+	modules := &strings.Builder{}
+	domains := cr.Spec.SecurityDomains
+	for _, certificate := range cr.Spec.LoginModules.CertificateLoginModules {
+		flag := "sufficient"
+		if certificate.Flag != nil {
+			flag = *certificate.Flag
+		}
+		if reference := domainLoginModuleReference(domain, certificate.Name); reference != nil {
+			if reference.Flag != nil {
+				flag = *reference.Flag
+			}
+		} else if !brokerDomain || domainLoginModuleReference(domains.BrokerDomain, certificate.Name) != nil ||
+			domainLoginModuleReference(domains.ConsoleDomain, certificate.Name) != nil {
+			continue
+		}
+		fmt.Fprintf(modules, "    org.apache.activemq.artemis.spi.core.security.jaas.TextFileCertificateLoginModule %v\n", flag)
+		fmt.Fprintln(modules, "        reload=true")
+		fmt.Fprintf(modules, "        baseDir=\"%v/etc\"\n", brokerConfigRoot)
+		fmt.Fprintf(modules, "        org.apache.activemq.jaas.textfiledn.user=\"%v\"\n", certificate.Name+certificateUsersSuffix)
+		fmt.Fprintf(modules, "        org.apache.activemq.jaas.textfiledn.role=\"%v\";\n", certificate.Name+certificateRolesSuffix)
+	}
+	for _, ldap := range cr.Spec.LoginModules.LdapLoginModules {
+		reference := domainLoginModuleReference(domain, ldap.Name)
+		if reference == nil {
+			continue
+		}
+		flag := "required"
+		if reference.Flag != nil {
+			flag = *reference.Flag
+		}
+		options := []string{
+			"initialContextFactory=" + jaasQuote("com.sun.jndi.ldap.LdapCtxFactory"),
+			"connectionURL=" + jaasQuote(ldap.ConnectionURL),
+		}
+		if ldap.ConnectionUsername != nil {
+			options = append(options, "connectionUsername="+jaasQuote(*ldap.ConnectionUsername))
+			// security-secrets.py replaces the reference with the quoted password
+			if ldap.ConnectionPasswordSecret != nil {
+				options = append(options, "connectionPassword="+securitySecretFileReference(ldap.ConnectionPasswordSecret, false))
+			} else if ldap.ConnectionPassword != nil {
+				options = append(options, "connectionPassword="+jaasQuote(*ldap.ConnectionPassword))
+			}
+			options = append(options, "authentication=simple")
+		} else {
+			options = append(options, "authentication=none")
+		}
+		if ldap.ConnectionPool != nil {
+			options = append(options, fmt.Sprintf("connectionPool=%v", *ldap.ConnectionPool))
+		}
+		if ldap.ConnectionTimeout != nil {
+			options = append(options, fmt.Sprintf("connectionTimeout=%d", *ldap.ConnectionTimeout))
+		}
+		if ldap.ReadTimeout != nil {
+			options = append(options, fmt.Sprintf("readTimeout=%d", *ldap.ReadTimeout))
+		}
+		if ldap.Referral != nil {
+			options = append(options, "referral="+*ldap.Referral)
+		}
+		options = append(options,
+			"userBase="+jaasQuote(ldap.UserBase),
+			"userSearchMatching="+jaasQuote(ldap.UserSearchMatching),
+			fmt.Sprintf("userSearchSubtree=%v", ldap.UserSearchSubtree))
+		if ldap.RoleBase != "" {
+			options = append(options,
+				"roleBase="+jaasQuote(ldap.RoleBase),
+				"roleName="+jaasQuote(ldap.RoleName),
+				"roleSearchMatching="+jaasQuote(ldap.RoleSearchMatching),
+				fmt.Sprintf("roleSearchSubtree=%v", ldap.RoleSearchSubtree))
+			if ldap.ExpandRoles {
+				options = append(options, "expandRoles=true", "expandRolesMatching="+jaasQuote(ldap.ExpandRolesMatching))
+			}
+		}
+		if reference.Debug != nil && *reference.Debug {
+			options = append(options, "debug=true")
+		}
+		fmt.Fprintf(modules, "    org.apache.activemq.artemis.spi.core.security.jaas.LDAPLoginModule %v\n", flag)
+		fmt.Fprintf(modules, "        %v;\n", strings.Join(options, "\n        "))
+	}
+	return modules.String()
+}
+
+// adds the codec option to the properties login modules yacfg rendered into a login.config,
+// usage: properties-codec.py <login.config> <base64 option>
+var propertiesCodecScript = `import base64, sys
+
+path = sys.argv[1]
+option = '        ' + base64.b64decode(sys.argv[2]).decode() + chr(10)
+
+with open(path) as f:
+    lines = f.read().splitlines(True)
+
+out = []
+for line in lines:
+    out.append(line)
+    if line.strip().startswith('org.apache.activemq.artemis.spi.core.security.jaas.PropertiesLoginModule '):
+        out.append(option)
+
+with open(path, 'w') as f:
+    f.write(''.join(out))
+`
+
+func passwordCodec(cr *brokerv1beta1.ActiveMQArtemisSecurity) string {
+	if masking := cr.Spec.PasswordMasking; masking != nil && masking.PasswordCodec != nil {
+		return *masking.PasswordCodec
+	}
+	return ""
+}
+
+// the properties login modules verify the ENC() passwords of their users with the codec of the brokers
+func propertiesCodecCmd(cr *brokerv1beta1.ActiveMQArtemisSecurity) string {
+	codec := passwordCodec(cr)
+	if codec == "" || len(cr.Spec.LoginModules.PropertiesLoginModules) == 0 {
+		return ""
+	}
+	option := "org.apache.activemq.jaas.properties.password.codec=" + jaasQuote(codec)
+	return "python3 " + initScriptPath(propertiesCodecScriptName) + " " + brokerConfigRoot + "/etc/login.config " +
+		base64.StdEncoding.EncodeToString([]byte(option))
+}
+
+// passwordMaskingProperties render the password masking of the applicable security cr, the broker
+// properties of the CR come after them
+func passwordMaskingProperties(customResource *brokerv1beta1.ActiveMQArtemis) []string {
+	securityCR := getApplicableSecurityCR(customResource)
+	if securityCR == nil || securityCR.Spec.PasswordMasking == nil {
+		return nil
+	}
+	props := []string{}
+	if mask := securityCR.Spec.PasswordMasking.MaskPassword; mask != nil {
+		props = append(props, fmt.Sprintf("maskPassword=%v", *mask))
+	}
+	if codec := passwordCodec(securityCR); codec != "" {
+		props = append(props, "passwordCodec="+codec)
+	}
+	return props
+}
+
+// jaasQuote quotes a JAAS option value, backslashes and quotes are escaped within it
+func jaasQuote(value string) string {
+	return "\"" + strings.NewReplacer("\\", "\\\\", "\"", "\\\"").Replace(value) + "\""
+}
+
+// consoleLoginModules renders the certificate and ldap modules of a console domain of its own
+func consoleLoginModules(cr *brokerv1beta1.ActiveMQArtemisSecurity) string {
+	if getConsoleDomainName(cr) == "" {
+		return ""
+	}
+	return domainLoginModules(cr, cr.Spec.SecurityDomains.ConsoleDomain, false)
+}
+
+// saslLoginConfig renders the broker domain modules that turn an authenticated sasl peer, a client
+// certificate or a directory user into a broker user and one JAAS entry per sasl module for the
+// acceptors to reference in saslLoginConfigScope
+func saslLoginConfig(cr *brokerv1beta1.ActiveMQArtemisSecurity) (string, string) {
+	modules := &strings.Builder{}
+	entries := &strings.Builder{}
+
+	if len(cr.Spec.LoginModules.ScramLoginModules) > 0 {
+		flag := "sufficient"
+		for _, scram := range cr.Spec.LoginModules.ScramLoginModules {
+			if scram.Flag != nil {
+				flag = *scram.Flag
+				break
+			}
+		}
+		fmt.Fprintf(modules, "    org.apache.activemq.artemis.spi.core.security.jaas.SCRAMLoginModule %v;\n", flag)
+	}
+	if len(cr.Spec.LoginModules.KerberosLoginModules) > 0 {
+		// a required module would fail every login that is not a kerberos one, like those of the
+		// properties users the broker domain already has
+		flag := "sufficient"
+		for _, kerberos := range cr.Spec.LoginModules.KerberosLoginModules {
+			if kerberos.Flag != nil {
+				flag = *kerberos.Flag
+				break
+			}
+		}
+		fmt.Fprintf(modules, "    org.apache.activemq.artemis.spi.core.security.jaas.KerberosLoginModule %v;\n", flag)
+	}
+	modules.WriteString(domainLoginModules(cr, cr.Spec.SecurityDomains.BrokerDomain, true))
+
+	for _, scram := range cr.Spec.LoginModules.ScramLoginModules {
+		fmt.Fprintf(entries, "\n%v {\n", scram.Name)
+		fmt.Fprintln(entries, "    org.apache.activemq.artemis.spi.core.security.jaas.SCRAMPropertiesLoginModule required")
+		fmt.Fprintln(entries, "        reload=true")
+		fmt.Fprintf(entries, "        baseDir=\"%v%v\"\n", secretPathBase, scram.UsersSecret)
+		fmt.Fprintln(entries, "        org.apache.activemq.jaas.properties.user=\"users.properties\"")
+		fmt.Fprintln(entries, "        org.apache.activemq.jaas.properties.role=\"roles.properties\";")
+		fmt.Fprintln(entries, "};")
+	}
+	for _, kerberos := range cr.Spec.LoginModules.KerberosLoginModules {
+		fmt.Fprintf(entries, "\n%v {\n", kerberos.Name)
+		fmt.Fprintln(entries, "    com.sun.security.auth.module.Krb5LoginModule required")
+		fmt.Fprintln(entries, "        isInitiator=false")
+		fmt.Fprintln(entries, "        storeKey=true")
+		fmt.Fprintln(entries, "        useKeyTab=true")
+		fmt.Fprintf(entries, "        keyTab=\"%v%v/%v\"\n", secretPathBase, kerberos.KeytabSecret.Name, kerberos.KeytabSecret.Key)
+		fmt.Fprintf(entries, "        principal=\"%v\"\n", kerberos.Principal)
+		fmt.Fprintf(entries, "        debug=%v;\n", kerberos.Debug)
+		fmt.Fprintln(entries, "};")
+	}
+	return modules.String(), entries.String()
+}
+
+// the rendered config goes through the shell, base64 keeps the quotes of the JAAS options intact and
+// single quotes keep an empty value as an argument
+func (r *ActiveMQArtemisSecurityConfigHandler) saslLoginConfigCmd(cr *brokerv1beta1.ActiveMQArtemisSecurity) string {
+	modules, entries := saslLoginConfig(cr)
+	consoleModules := consoleLoginModules(cr)
+	if modules == "" && consoleModules == "" {
+		return ""
+	}
+	args := " '" + base64.StdEncoding.EncodeToString([]byte(entries)) + "'"
+	if modules != "" {
+		args += " " + getBrokerDomainName(cr) + " " + base64.StdEncoding.EncodeToString([]byte(modules))
+	}
+	if consoleModules != "" {
+		args += " " + getConsoleDomainName(cr) + " " + base64.StdEncoding.EncodeToString([]byte(consoleModules))
+	}
+	return "python3 " + initScriptPath(saslLoginConfigScriptName) + " " +
+		brokerConfigRoot + "/etc/login.config" + args
+}
+
+const (
+	certificateUsersSuffix = "-cert-users.properties"
+	certificateRolesSuffix = "-cert-roles.properties"
+)
+
+// certificateLoginFiles renders the users and roles properties files of the certificate modules,
+// a user maps to the subject DN of its certificate and a role to the users that have it
+func certificateLoginFiles(cr *brokerv1beta1.ActiveMQArtemisSecurity) map[string]string {
+	files := map[string]string{}
+	for _, certificate := range cr.Spec.LoginModules.CertificateLoginModules {
+		users := &strings.Builder{}
+		roleUsers := map[string][]string{}
+		roles := []string{}
+		for _, user := range certificate.Users {
+			fmt.Fprintf(users, "%v=%v\n", user.Name, strings.ReplaceAll(user.SubjectDN, "\\", "\\\\"))
+			for _, role := range user.Roles {
+				if _, found := roleUsers[role]; !found {
+					roles = append(roles, role)
+				}
+				roleUsers[role] = append(roleUsers[role], user.Name)
+			}
+		}
+		sort.Strings(roles)
+		rolesFile := &strings.Builder{}
+		for _, role := range roles {
+			fmt.Fprintf(rolesFile, "%v=%v\n", role, strings.Join(roleUsers[role], ","))
+		}
+		files[certificate.Name+certificateUsersSuffix] = users.String()
+		files[certificate.Name+certificateRolesSuffix] = rolesFile.String()
+	}
+	return files
+}
+
+// the subject DNs go through the shell like the JAAS options, so the files are written from base64
+func certificateLoginFilesCmds(cr *brokerv1beta1.ActiveMQArtemisSecurity) []string {
+	files := certificateLoginFiles(cr)
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	cmds := []string{}
+	for _, name := range names {
+		cmds = append(cmds, "echo "+base64.StdEncoding.EncodeToString([]byte(files[name]))+" | base64 -d > "+brokerConfigRoot+"/etc/"+name)
+	}
+	return cmds
+}
+
+// saslMountsAndArgs lists the secrets and configMaps the sasl and ldap modules read at runtime, the
+// secrets of the passwords the init container reads, and the jvm arguments pointing at the krb5.conf
+// and the ldap truststore
+func saslMountsAndArgs(cr *brokerv1beta1.ActiveMQArtemisSecurity) ([]string, []string, string) {
+	var configMapNames []string
+	secretNames := securityFileSecretNames(cr)
+	krb5ConfArg, trustStoreArgs := "", ""
+	for _, scram := range cr.Spec.LoginModules.ScramLoginModules {
+		if scram.UsersSecret != "" && !containsString(secretNames, scram.UsersSecret) {
+			secretNames = append(secretNames, scram.UsersSecret)
+		}
+	}
+	for _, kerberos := range cr.Spec.LoginModules.KerberosLoginModules {
+		if kerberos.KeytabSecret.Name != "" && !containsString(secretNames, kerberos.KeytabSecret.Name) {
+			secretNames = append(secretNames, kerberos.KeytabSecret.Name)
+		}
+		if krb5 := kerberos.Krb5ConfConfigMap; krb5 != nil && krb5.Name != "" {
+			if !containsString(configMapNames, krb5.Name) {
+				configMapNames = append(configMapNames, krb5.Name)
+			}
+			if krb5ConfArg == "" {
+				krb5ConfArg = fmt.Sprintf("-Djava.security.krb5.conf=%v%v/%v", cfgMapPathBase, krb5.Name, krb5.Key)
+			}
+		}
+	}
+	if trustStore := ldapTrustStoreSecret(cr); trustStore != "" {
+		if !containsString(secretNames, trustStore) {
+			secretNames = append(secretNames, trustStore)
+		}
+		// a jks truststore loads without its password, which keeps it off the command line
+		trustStoreArgs = "-Djavax.net.ssl.trustStore=" + ldapTrustStorePath
+	}
+	return secretNames, configMapNames, strings.TrimSpace(krb5ConfArg + " " + trustStoreArgs)
+}
+
+// the copy of the jvm cacerts with the ldap certificates, the truststore of the jvm
+var ldapTrustStorePath = brokerConfigRoot + "/etc/ldap-truststore.jks"
+
+const securitySecretsChecksumEnvVarName = "SECURITY_SECRETS_CHECKSUM"
+
+// ldapTrustStoreSecret is the truststore secret of the ldap modules, the webhook keeps them to one
+// as it becomes the truststore of the jvm
+func ldapTrustStoreSecret(cr *brokerv1beta1.ActiveMQArtemisSecurity) string {
+	for _, ldap := range cr.Spec.LoginModules.LdapLoginModules {
+		if ldap.TrustStoreSecret != nil && *ldap.TrustStoreSecret != "" {
+			return *ldap.TrustStoreSecret
+		}
+	}
+	return ""
+}
+
+// ldapTrustStoreCmd imports the ldap truststore into a copy of the jvm cacerts so that the other tls
+// connections of the broker keep the public certificate authorities. keytool reads the password of the
+// secret from its mount, the copy gets the well known password of cacerts
+func ldapTrustStoreCmd(cr *brokerv1beta1.ActiveMQArtemisSecurity) string {
+	trustStore := ldapTrustStoreSecret(cr)
+	if trustStore == "" {
+		return ""
+	}
+	return "rm -f " + ldapTrustStorePath +
+		" && keytool -importkeystore -noprompt -srckeystore \"${JAVA_HOME}/lib/security/cacerts\" -srcstorepass changeit" +
+		" -destkeystore " + ldapTrustStorePath + " -deststoretype JKS -deststorepass changeit" +
+		" && keytool -importkeystore -noprompt -srckeystore " + secretPathBase + trustStore + "/client.ts" +
+		" -srcstorepass:file " + secretPathBase + trustStore + "/trustStorePassword" +
+		" -destkeystore " + ldapTrustStorePath + " -deststorepass changeit"
+}
+
+// securityBrokerStatuses tells for each broker the CR applies to which generation of the CR is in its
+// pod template and which of its pods restarted with it
+func (r *ActiveMQArtemisSecurityReconciler) securityBrokerStatuses(ctx context.Context, cr *brokerv1beta1.ActiveMQArtemisSecurity) []brokerv1beta1.SecurityBrokerStatus {
+	var statuses []brokerv1beta1.SecurityBrokerStatus
+	brokers := &brokerv1beta1.ActiveMQArtemisList{}
+	if err := r.Client.List(ctx, brokers, client.InNamespace(cr.Namespace)); err != nil {
+		ctrl.LoggerFrom(ctx).V(1).Info("unable to list the brokers of the security cr", "error", err)
+		return cr.Status.Brokers
+	}
+	configHandler := &ActiveMQArtemisSecurityConfigHandler{cr, types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace}, r}
+	for i := range brokers.Items {
+		broker := &brokers.Items[i]
+		if configHandler.IsApplicableFor(types.NamespacedName{Name: broker.Name, Namespace: broker.Namespace}) {
+			statuses = append(statuses, r.securityBrokerStatus(ctx, cr, broker))
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
+func (r *ActiveMQArtemisSecurityReconciler) securityBrokerStatus(ctx context.Context, cr *brokerv1beta1.ActiveMQArtemisSecurity, broker *brokerv1beta1.ActiveMQArtemis) brokerv1beta1.SecurityBrokerStatus {
+	status := brokerv1beta1.SecurityBrokerStatus{Name: broker.Name, EffectivePermissions: effectiveSecurityPermissions(cr, broker)}
+	if rendered := broker.Status.Security; rendered != nil {
+		if rendered.Name == cr.Name {
+			status.Generation = rendered.Generation
+			status.Error = rendered.Error
+		} else {
+			status.Error = fmt.Sprintf("the broker applies security cr %v", rendered.Name)
+		}
+	}
+	if status.Error == "" {
+		if valid := meta.FindStatusCondition(broker.Status.Conditions, brokerv1beta1.ValidConditionType); valid != nil && valid.Status == metav1.ConditionFalse && strings.HasPrefix(valid.Message, "security cr "+cr.Name+" ") {
+			status.Error = valid.Message
+		} else if canary := broker.Status.SecurityCanary; canary != nil && canary.Phase == brokerv1beta1.SecurityCanaryFailed {
+			status.Error = fmt.Sprintf("the canary rollout to pod %v failed, %v", canary.Pod, canary.Message)
+		}
+	}
+
+	statefulSet := &appsv1.StatefulSet{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: namer.CrToSS(broker.Name), Namespace: broker.Namespace}, statefulSet); err != nil || statefulSet.Spec.Replicas == nil {
+		return status
+	}
+	// the pods of the current revision run the rendered generation once the StatefulSet controller saw it
+	current := status.Generation == cr.Generation && statefulSet.Status.ObservedGeneration == statefulSet.Generation
+	for ordinal := 0; ordinal < int(*statefulSet.Spec.Replicas); ordinal++ {
+		podName := statefulSet.Name + "-" + strconv.Itoa(ordinal)
+		pod := &corev1.Pod{}
+		if current && r.Client.Get(ctx, types.NamespacedName{Name: podName, Namespace: broker.Namespace}, pod) == nil &&
+			pod.Labels[appsv1.ControllerRevisionHashLabelKey] == statefulSet.Status.UpdateRevision {
+			status.UpdatedPods = append(status.UpdatedPods, podName)
+		} else {
+			status.PendingPods = append(status.PendingPods, podName)
+		}
+	}
+	return status
+}
+
+// securityCRsOfBroker requeues the security crs that apply to a broker, its status tells how far it
+// picked up their configuration
+func (r *ActiveMQArtemisSecurityReconciler) securityCRsOfBroker(broker client.Object) []ctrl.Request {
+	requests := []ctrl.Request{}
+	brokerName := types.NamespacedName{Name: broker.GetName(), Namespace: broker.GetNamespace()}
+	for name, configHandler := range configHandlers() {
+		if _, ok := configHandler.(*ActiveMQArtemisSecurityConfigHandler); ok && configHandler.IsApplicableFor(brokerName) {
+			requests = append(requests, ctrl.Request{NamespacedName: name})
+		}
+	}
+	return requests
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *ActiveMQArtemisSecurityReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&brokerv1beta1.ActiveMQArtemisSecurity{}).
-		Complete(r)
+		Watches(&source.Kind{Type: &brokerv1beta1.ActiveMQArtemis{}}, handler.EnqueueRequestsFromMapFunc(r.securityCRsOfBroker)).
+		Complete(withCorrelation("activemqartemissecurity", r))
 }
@@ -21,10 +21,12 @@ package controllers
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"fmt"
 	"os"
 	"strconv"
 	"strings"
+	"testing"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -40,11 +42,14 @@ import (
 	"k8s.io/client-go/tools/remotecommand"
 
 	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/resources/environments"
 	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/common"
 	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/namer"
+	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 var boolFalse = false
@@ -1219,3 +1224,780 @@ func generateSecuritySpec(secName string, targetNamespace string) *brokerv1beta1
 
 	return &toCreate
 }
+
+func TestKeycloakLoginModule(t *testing.T) {
+	realm := "artemis"
+	authServerUrl := "https://keycloak.example.com/auth"
+	directAccess := "directAccess"
+	trustStore := "/amq/extra/secrets/keycloak-tls/truststore.jks"
+	securityName := types.NamespacedName{Name: "keycloak", Namespace: "test"}
+	securityCR := &brokerv1beta1.ActiveMQArtemisSecurity{
+		ObjectMeta: metav1.ObjectMeta{Name: securityName.Name, Namespace: securityName.Namespace},
+		Spec: brokerv1beta1.ActiveMQArtemisSecuritySpec{
+			LoginModules: brokerv1beta1.LoginModulesType{
+				KeycloakLoginModules: []brokerv1beta1.KeycloakLoginModuleType{
+					{
+						Name:       "keycloak-broker",
+						ModuleType: &directAccess,
+						Configuration: brokerv1beta1.KeycloakModuleConfigurationType{
+							Realm:             &realm,
+							AuthServerUrl:     &authServerUrl,
+							TrustStore:        &trustStore,
+							CredentialsSecret: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "keycloak-client"}, Key: "client-secret"},
+						},
+						RoleMappings: []brokerv1beta1.KeycloakRoleMappingType{
+							{KeycloakRole: "mq-producers", BrokerRoles: []string{"producer"}},
+							{KeycloakRole: "mq-admins", BrokerRoles: []string{"admin", "producer"}},
+						},
+					},
+				},
+			},
+			SecuritySettings: brokerv1beta1.SecuritySettingsType{
+				Broker: []brokerv1beta1.BrokerSecuritySettingType{
+					{Match: "orders.#", Permissions: []brokerv1beta1.PermissionType{
+						{OperationType: "send", Roles: []string{"producer"}},
+						{OperationType: "consume", Roles: []string{"consumer"}},
+					}},
+				},
+				Management: brokerv1beta1.ManagementSecuritySettingsType{
+					HawtioRoles: []string{"admin"},
+				},
+			},
+		},
+	}
+	assert.NoError(t, securityCR.ValidateCreate())
+
+	testScheme := newTestScheme(t)
+	clientSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "keycloak-client", Namespace: securityName.Namespace},
+		Data:       map[string][]byte{"client-secret": []byte("s3cret")},
+	}
+	handler := &ActiveMQArtemisSecurityConfigHandler{
+		SecurityCR:     securityCR,
+		NamespacedName: securityName,
+		owner:          &ActiveMQArtemisSecurityReconciler{Client: fake.NewClientBuilder().WithScheme(testScheme).WithObjects(clientSecret, securityCR).Build(), Scheme: testScheme},
+	}
+
+	// the client secret is read from the secret, the generated passwords stay with the module
+	result := handler.processCrPasswords()
+	config := result.Spec.LoginModules.KeycloakLoginModules[0].Configuration
+	assert.Equal(t, []brokerv1beta1.KeyValueType{{Key: "secret", Value: &[]string{"s3cret"}[0]}}, config.Credentials)
+	assert.NotNil(t, config.TrustStorePassword)
+	assert.Nil(t, securityCR.Spec.LoginModules.KeycloakLoginModules[0].Configuration.Credentials)
+
+	// the keycloak roles get the permissions of the broker roles they map to
+	permissions := result.Spec.SecuritySettings.Broker[0].Permissions
+	assert.Equal(t, []string{"producer", "mq-producers", "mq-admins"}, permissions[0].Roles)
+	assert.Equal(t, []string{"consumer"}, permissions[1].Roles)
+	assert.Equal(t, []string{"admin", "mq-admins"}, result.Spec.SecuritySettings.Management.HawtioRoles)
+
+	cmd, err := handler.persistCR("/tmp/security-config.yaml", result)
+	assert.NoError(t, err)
+	assert.Contains(t, cmd, "s3cret")
+	assert.NotContains(t, cmd, "keycloak-client")
+	assert.NotContains(t, cmd, "keycloakrole")
+
+	for _, invalid := range []brokerv1beta1.KeycloakLoginModuleType{
+		{Configuration: brokerv1beta1.KeycloakModuleConfigurationType{Realm: &realm, AuthServerUrl: &authServerUrl}},
+		{Name: "keycloak", ModuleType: &realm, Configuration: brokerv1beta1.KeycloakModuleConfigurationType{Realm: &realm, AuthServerUrl: &authServerUrl}},
+		{Name: "keycloak", Configuration: brokerv1beta1.KeycloakModuleConfigurationType{AuthServerUrl: &authServerUrl}},
+		{Name: "keycloak", Configuration: brokerv1beta1.KeycloakModuleConfigurationType{Realm: &realm}},
+		{Name: "keycloak", Configuration: brokerv1beta1.KeycloakModuleConfigurationType{Realm: &realm, AuthServerUrl: &authServerUrl, CredentialsSecret: &corev1.SecretKeySelector{Key: "client-secret"}}},
+		{Name: "keycloak", Configuration: brokerv1beta1.KeycloakModuleConfigurationType{Realm: &realm, AuthServerUrl: &authServerUrl, CredentialsSecret: clientSecretSelector(), Credentials: []brokerv1beta1.KeyValueType{{Key: "secret"}}}},
+		{Name: "keycloak", Configuration: brokerv1beta1.KeycloakModuleConfigurationType{Realm: &realm, AuthServerUrl: &authServerUrl}, RoleMappings: []brokerv1beta1.KeycloakRoleMappingType{{KeycloakRole: "mq-admins"}}},
+	} {
+		invalidCR := &brokerv1beta1.ActiveMQArtemisSecurity{Spec: brokerv1beta1.ActiveMQArtemisSecuritySpec{LoginModules: brokerv1beta1.LoginModulesType{KeycloakLoginModules: []brokerv1beta1.KeycloakLoginModuleType{invalid}}}}
+		assert.Error(t, invalidCR.ValidateCreate(), invalid)
+	}
+}
+
+func clientSecretSelector() *corev1.SecretKeySelector {
+	return &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "keycloak-client"}, Key: "client-secret"}
+}
+
+func TestCertificateLoginModule(t *testing.T) {
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "mtls", Namespace: "mtls-ns"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			Acceptors: []brokerv1beta1.AcceptorType{
+				{Name: "amqps", Port: 5671, Protocols: "amqp", SSLEnabled: true},
+			},
+		},
+	}
+
+	securityName := types.NamespacedName{Name: "mtls-sec", Namespace: "mtls-ns"}
+	securityCR := &brokerv1beta1.ActiveMQArtemisSecurity{
+		ObjectMeta: metav1.ObjectMeta{Name: securityName.Name, Namespace: securityName.Namespace},
+		Spec: brokerv1beta1.ActiveMQArtemisSecuritySpec{
+			LoginModules: brokerv1beta1.LoginModulesType{
+				CertificateLoginModules: []brokerv1beta1.CertificateLoginModuleType{
+					{
+						Name: "services",
+						Users: []brokerv1beta1.CertificateUserType{
+							{Name: "orders", SubjectDN: "CN=orders,OU=services,O=example", Roles: []string{"producer", "consumer"}},
+							{Name: "billing", SubjectDN: "/CN=billing-\\d+,OU=services,O=example/", Roles: []string{"consumer"}},
+						},
+					},
+				},
+			},
+		},
+	}
+	assert.NoError(t, securityCR.ValidateCreate())
+	namespaceToConfigHandler[securityName] = &ActiveMQArtemisSecurityConfigHandler{
+		SecurityCR:     securityCR,
+		NamespacedName: securityName,
+	}
+	defer delete(namespaceToConfigHandler, securityName)
+
+	modules, entries := saslLoginConfig(securityCR)
+	assert.Equal(t, "    org.apache.activemq.artemis.spi.core.security.jaas.TextFileCertificateLoginModule sufficient\n"+
+		"        reload=true\n"+
+		"        baseDir=\"/amq/init/config/etc\"\n"+
+		"        org.apache.activemq.jaas.textfiledn.user=\"services-cert-users.properties\"\n"+
+		"        org.apache.activemq.jaas.textfiledn.role=\"services-cert-roles.properties\";\n", modules)
+	assert.Empty(t, entries)
+
+	files := certificateLoginFiles(securityCR)
+	assert.Equal(t, "orders=CN=orders,OU=services,O=example\nbilling=/CN=billing-\\\\d+,OU=services,O=example/\n", files["services-cert-users.properties"])
+	assert.Equal(t, "consumer=orders,billing\nproducer=orders\n", files["services-cert-roles.properties"])
+	cmds := certificateLoginFilesCmds(securityCR)
+	assert.Len(t, cmds, 2)
+	assert.True(t, strings.HasSuffix(cmds[0], " | base64 -d > /amq/init/config/etc/services-cert-roles.properties"))
+
+	// the module is left out of the yaml yacfg renders
+	handler := &ActiveMQArtemisSecurityConfigHandler{SecurityCR: securityCR, NamespacedName: securityName}
+	cmd, err := handler.persistCR("/tmp/security-config.yaml", securityCR)
+	assert.NoError(t, err)
+	assert.NotContains(t, cmd, "CN=orders")
+
+	// no acceptor asks clients for a certificate
+	condition := validateCertificateClientAuth(cr)
+	assert.NotNil(t, condition)
+	assert.Equal(t, brokerv1beta1.ValidConditionClientAuthRequiredReason, condition.Reason)
+	cr.Spec.Acceptors[0].NeedClientAuth = true
+	assert.Nil(t, validateCertificateClientAuth(cr))
+
+	for _, invalid := range []brokerv1beta1.CertificateLoginModuleType{
+		{Users: []brokerv1beta1.CertificateUserType{{Name: "orders", SubjectDN: "CN=orders"}}},
+		{Name: "etc/services", Users: []brokerv1beta1.CertificateUserType{{Name: "orders", SubjectDN: "CN=orders"}}},
+		{Name: "services"},
+		{Name: "services", Users: []brokerv1beta1.CertificateUserType{{Name: "orders"}}},
+		{Name: "services", Users: []brokerv1beta1.CertificateUserType{{Name: "order service", SubjectDN: "CN=orders"}}},
+		{Name: "services", Users: []brokerv1beta1.CertificateUserType{{Name: "orders", SubjectDN: "CN=orders"}, {Name: "orders", SubjectDN: "CN=billing"}}},
+		{Name: "services", Users: []brokerv1beta1.CertificateUserType{{Name: "orders", SubjectDN: "CN=orders", Roles: []string{"producer,consumer"}}}},
+		{Name: "services", Flag: &[]string{"always"}[0], Users: []brokerv1beta1.CertificateUserType{{Name: "orders", SubjectDN: "CN=orders"}}},
+	} {
+		invalidCR := &brokerv1beta1.ActiveMQArtemisSecurity{Spec: brokerv1beta1.ActiveMQArtemisSecuritySpec{LoginModules: brokerv1beta1.LoginModulesType{CertificateLoginModules: []brokerv1beta1.CertificateLoginModuleType{invalid}}}}
+		assert.Error(t, invalidCR.ValidateCreate(), invalid)
+	}
+}
+
+func TestSeparateConsoleDomain(t *testing.T) {
+	securityName := types.NamespacedName{Name: "split", Namespace: "split-ns"}
+	brokerDomain, consoleDomain := "activemq", "console"
+	certificates, directory, props := "services", "directory", "prop-module"
+	sufficient := "sufficient"
+	bindUser := "cn=artemis,dc=example,dc=com"
+	securityCR := &brokerv1beta1.ActiveMQArtemisSecurity{
+		ObjectMeta: metav1.ObjectMeta{Name: securityName.Name, Namespace: securityName.Namespace},
+		Spec: brokerv1beta1.ActiveMQArtemisSecuritySpec{
+			LoginModules: brokerv1beta1.LoginModulesType{
+				PropertiesLoginModules: []brokerv1beta1.PropertiesLoginModuleType{
+					{Name: props, Users: []brokerv1beta1.UserType{{Name: "admin", Roles: []string{"admin"}}}},
+				},
+				CertificateLoginModules: []brokerv1beta1.CertificateLoginModuleType{
+					{Name: certificates, Users: []brokerv1beta1.CertificateUserType{{Name: "orders", SubjectDN: "CN=orders", Roles: []string{"producer"}}}},
+				},
+				LdapLoginModules: []brokerv1beta1.LdapLoginModuleType{
+					{
+						Name:                     directory,
+						ConnectionURL:            "ldaps://ldap.example.com:636",
+						ConnectionUsername:       &bindUser,
+						ConnectionPasswordSecret: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "ldap-bind"}, Key: "password"},
+						UserBase:                 "ou=users,dc=example,dc=com",
+						UserSearchMatching:       "(uid={0})",
+						RoleBase:                 "ou=groups,dc=example,dc=com",
+						RoleName:                 "cn",
+						RoleSearchMatching:       "(member={0})",
+					},
+				},
+			},
+			SecurityDomains: brokerv1beta1.SecurityDomainsType{
+				BrokerDomain: brokerv1beta1.BrokerDomainType{
+					Name: &brokerDomain,
+					LoginModules: []brokerv1beta1.LoginModuleReferenceType{
+						{Name: &certificates, Flag: &sufficient},
+						{Name: &props, Flag: &sufficient},
+					},
+				},
+				ConsoleDomain: brokerv1beta1.BrokerDomainType{
+					Name:         &consoleDomain,
+					LoginModules: []brokerv1beta1.LoginModuleReferenceType{{Name: &directory}},
+				},
+			},
+		},
+	}
+	assert.NoError(t, securityCR.ValidateCreate())
+
+	testScheme := newTestScheme(t)
+	bindSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ldap-bind", Namespace: securityName.Namespace},
+		Data:       map[string][]byte{"password": []byte("s3\"cret")},
+	}
+	client := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(bindSecret, securityCR).Build()
+	handler := &ActiveMQArtemisSecurityConfigHandler{
+		SecurityCR:     securityCR,
+		NamespacedName: securityName,
+		owner:          &ActiveMQArtemisSecurityReconciler{Client: client, Scheme: testScheme},
+	}
+	result := handler.processCrPasswords()
+	assert.Contains(t, securitySecretReferences(securityCR), *securityCR.Spec.LoginModules.LdapLoginModules[0].ConnectionPasswordSecret)
+
+	// messaging clients log in with their certificate, the console against the directory
+	modules, _ := saslLoginConfig(result)
+	assert.True(t, strings.HasPrefix(modules, "    org.apache.activemq.artemis.spi.core.security.jaas.TextFileCertificateLoginModule sufficient\n"))
+	assert.NotContains(t, modules, "LDAPLoginModule")
+	assert.Equal(t, "    org.apache.activemq.artemis.spi.core.security.jaas.LDAPLoginModule required\n"+
+		"        initialContextFactory=\"com.sun.jndi.ldap.LdapCtxFactory\"\n"+
+		"        connectionURL=\"ldaps://ldap.example.com:636\"\n"+
+		"        connectionUsername=\"cn=artemis,dc=example,dc=com\"\n"+
+		"        connectionPassword=secret:///amq/extra/secrets/ldap-bind/password\n"+
+		"        authentication=simple\n"+
+		"        userBase=\"ou=users,dc=example,dc=com\"\n"+
+		"        userSearchMatching=\"(uid={0})\"\n"+
+		"        userSearchSubtree=false\n"+
+		"        roleBase=\"ou=groups,dc=example,dc=com\"\n"+
+		"        roleName=\"cn\"\n"+
+		"        roleSearchMatching=\"(member={0})\"\n"+
+		"        roleSearchSubtree=false;\n", consoleLoginModules(result))
+
+	cmd := handler.saslLoginConfigCmd(result)
+	assert.Contains(t, cmd, " activemq "+base64.StdEncoding.EncodeToString([]byte(modules)))
+	assert.Contains(t, cmd, " console "+base64.StdEncoding.EncodeToString([]byte(consoleLoginModules(result))))
+
+	// the bind password is read from its mount, it is in neither the init command nor the pod template
+	secretNames, _, _ := saslMountsAndArgs(securityCR)
+	assert.Contains(t, secretNames, "ldap-bind")
+	configCmds := handler.Config([]corev1.Container{{Name: "init"}}, "/amq/init/config", "", "")
+	assert.Contains(t, configCmds, "python3 /amq/init/scripts/security-secrets.py /amq/init/config/etc/login.config")
+
+	// yacfg renders the properties module and neither the operator modules nor the references to them
+	persisted, err := handler.persistCR("/tmp/security-config.yaml", result)
+	assert.NoError(t, err)
+	assert.Contains(t, persisted, "name: prop-module")
+	assert.NotContains(t, persisted, "name: services")
+	assert.NotContains(t, persisted, "name: directory")
+	assert.NotContains(t, persisted, "s3")
+
+	// the console has to be a domain of its own to take an operator module
+	sameDomain := securityCR.DeepCopy()
+	sameDomain.Spec.SecurityDomains.ConsoleDomain.Name = &brokerDomain
+	assert.Error(t, sameDomain.ValidateCreate())
+	unreferenced := securityCR.DeepCopy()
+	unreferenced.Spec.SecurityDomains.ConsoleDomain.LoginModules = nil
+	assert.Error(t, unreferenced.ValidateCreate())
+
+	for _, invalid := range []brokerv1beta1.LdapLoginModuleType{
+		{ConnectionURL: "ldap://ldap", UserBase: "ou=users", UserSearchMatching: "(uid={0})"},
+		{Name: directory, ConnectionURL: "http://ldap", UserBase: "ou=users", UserSearchMatching: "(uid={0})"},
+		{Name: directory, ConnectionURL: "ldap://ldap", UserSearchMatching: "(uid={0})"},
+		{Name: directory, ConnectionURL: "ldap://ldap", UserBase: "ou=users", UserSearchMatching: "(uid={0})", ConnectionPassword: &sufficient},
+		{Name: directory, ConnectionURL: "ldap://ldap", UserBase: "ou=users", UserSearchMatching: "(uid={0})", ConnectionUsername: &bindUser, ConnectionPasswordSecret: &corev1.SecretKeySelector{Key: "password"}},
+		{Name: certificates, ConnectionURL: "ldap://ldap", UserBase: "ou=users", UserSearchMatching: "(uid={0})"},
+	} {
+		invalidCR := securityCR.DeepCopy()
+		invalidCR.Spec.LoginModules.LdapLoginModules = []brokerv1beta1.LdapLoginModuleType{invalid}
+		assert.Error(t, invalidCR.ValidateCreate(), invalid)
+	}
+}
+
+func TestLdapLoginModuleOptions(t *testing.T) {
+	reconciler := &ActiveMQArtemisReconcilerImpl{}
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "ad", Namespace: "ad-ns"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			Env: []corev1.EnvVar{{Name: "JAVA_ARGS_APPEND", Value: "-Dfoo=bar"}},
+		},
+	}
+
+	securityName := types.NamespacedName{Name: "ad-sec", Namespace: "ad-ns"}
+	directory, follow, trustStore := "forest", "follow", "ad-truststore"
+	pooled := true
+	connectionTimeout, readTimeout := int32(5000), int32(10000)
+	securityCR := &brokerv1beta1.ActiveMQArtemisSecurity{
+		ObjectMeta: metav1.ObjectMeta{Name: securityName.Name, Namespace: securityName.Namespace},
+		Spec: brokerv1beta1.ActiveMQArtemisSecuritySpec{
+			LoginModules: brokerv1beta1.LoginModulesType{
+				LdapLoginModules: []brokerv1beta1.LdapLoginModuleType{
+					{
+						Name:                directory,
+						ConnectionURL:       "ldaps://dc.example.com:636",
+						UserBase:            "dc=example,dc=com",
+						UserSearchMatching:  "(sAMAccountName={0})",
+						UserSearchSubtree:   true,
+						RoleBase:            "dc=example,dc=com",
+						RoleName:            "cn",
+						RoleSearchMatching:  "(member={0})",
+						RoleSearchSubtree:   true,
+						ExpandRoles:         true,
+						ExpandRolesMatching: "(member={0})",
+						Referral:            &follow,
+						ConnectionPool:      &pooled,
+						ConnectionTimeout:   &connectionTimeout,
+						ReadTimeout:         &readTimeout,
+						TrustStoreSecret:    &trustStore,
+					},
+				},
+			},
+			SecurityDomains: brokerv1beta1.SecurityDomainsType{
+				BrokerDomain: brokerv1beta1.BrokerDomainType{
+					LoginModules: []brokerv1beta1.LoginModuleReferenceType{{Name: &directory}},
+				},
+			},
+		},
+	}
+	assert.NoError(t, securityCR.ValidateCreate())
+	trustStoreSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: trustStore, Namespace: securityName.Namespace},
+		Data:       map[string][]byte{"client.ts": []byte("jks"), "trustStorePassword": []byte("changeit")},
+	}
+	namespaceToConfigHandler[securityName] = &ActiveMQArtemisSecurityConfigHandler{
+		SecurityCR:     securityCR,
+		NamespacedName: securityName,
+		owner:          &ActiveMQArtemisSecurityReconciler{Client: fake.NewClientBuilder().WithObjects(trustStoreSecret).Build()},
+	}
+	defer delete(namespaceToConfigHandler, securityName)
+
+	modules, _ := saslLoginConfig(securityCR)
+	assert.Contains(t, modules, "        authentication=none\n"+
+		"        connectionPool=true\n"+
+		"        connectionTimeout=5000\n"+
+		"        readTimeout=10000\n"+
+		"        referral=follow\n")
+	assert.Contains(t, modules, "        roleSearchSubtree=true\n"+
+		"        expandRoles=true\n"+
+		"        expandRolesMatching=\"(member={0})\";\n")
+
+	// the truststore secret is mounted and imported into a copy of the jvm cacerts, the truststore of the jvm
+	newSpec, err := reconciler.NewPodTemplateSpecForCR(cr, Namers{}, &corev1.PodTemplateSpec{}, k8sClient)
+	assert.NoError(t, err)
+	javaArgs := environments.Retrieve(newSpec.Spec.Containers, "JAVA_ARGS_APPEND")
+	assert.NotNil(t, javaArgs)
+	assert.Contains(t, javaArgs.Value, "-Djavax.net.ssl.trustStore=/amq/init/config/etc/ldap-truststore.jks")
+	assert.NotContains(t, javaArgs.Value, "trustStorePassword")
+	trustStoreCmd := ldapTrustStoreCmd(securityCR)
+	assert.Contains(t, trustStoreCmd, `-srckeystore "${JAVA_HOME}/lib/security/cacerts"`)
+	assert.Contains(t, trustStoreCmd, "-srckeystore /amq/extra/secrets/ad-truststore/client.ts -srcstorepass:file /amq/extra/secrets/ad-truststore/trustStorePassword")
+	assert.Contains(t, namespaceToConfigHandler[securityName].Config([]corev1.Container{{Name: "init"}}, "/amq/init/config", "", ""), trustStoreCmd)
+	mountPaths := []string{}
+	for _, mount := range newSpec.Spec.Containers[0].VolumeMounts {
+		mountPaths = append(mountPaths, mount.MountPath)
+	}
+	assert.Contains(t, mountPaths, "/amq/extra/secrets/ad-truststore")
+	assert.Contains(t, securitySecretReferences(securityCR), corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: trustStore}, Key: "client.ts"})
+
+	plain, other, invalidReferral, zero := "ldap://dc.example.com", "other-truststore", "chase", int32(0)
+	for _, invalid := range []func(module *brokerv1beta1.LdapLoginModuleType){
+		func(module *brokerv1beta1.LdapLoginModuleType) { module.Referral = &invalidReferral },
+		func(module *brokerv1beta1.LdapLoginModuleType) { module.ReadTimeout = &zero },
+		func(module *brokerv1beta1.LdapLoginModuleType) { module.ExpandRolesMatching = "" },
+		func(module *brokerv1beta1.LdapLoginModuleType) { module.ConnectionURL = plain },
+	} {
+		invalidCR := securityCR.DeepCopy()
+		invalid(&invalidCR.Spec.LoginModules.LdapLoginModules[0])
+		assert.Error(t, invalidCR.ValidateCreate())
+	}
+	second := securityCR.Spec.LoginModules.LdapLoginModules[0]
+	second.Name, second.TrustStoreSecret = "second", &other
+	twoTrustStores := securityCR.DeepCopy()
+	twoTrustStores.Spec.LoginModules.LdapLoginModules = append(twoTrustStores.Spec.LoginModules.LdapLoginModules, second)
+	twoTrustStores.Spec.SecurityDomains.BrokerDomain.LoginModules = append(twoTrustStores.Spec.SecurityDomains.BrokerDomain.LoginModules, brokerv1beta1.LoginModuleReferenceType{Name: &second.Name})
+	assert.ErrorContains(t, twoTrustStores.ValidateCreate(), "differs from the trustStoreSecret")
+}
+
+func TestPropertiesUserPasswordSecret(t *testing.T) {
+	securityName := types.NamespacedName{Name: "props", Namespace: "props-ns"}
+	adminPassword := "adm1n"
+	securityCR := &brokerv1beta1.ActiveMQArtemisSecurity{
+		ObjectMeta: metav1.ObjectMeta{Name: securityName.Name, Namespace: securityName.Namespace},
+		Spec: brokerv1beta1.ActiveMQArtemisSecuritySpec{
+			LoginModules: brokerv1beta1.LoginModulesType{
+				PropertiesLoginModules: []brokerv1beta1.PropertiesLoginModuleType{
+					{
+						Name: "prop-module",
+						Users: []brokerv1beta1.UserType{
+							{Name: "orders", PasswordSecret: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "orders-credentials"}, Key: "password"}, Roles: []string{"producer"}},
+							{Name: "admin", Password: &adminPassword, Roles: []string{"admin"}},
+						},
+					},
+				},
+			},
+		},
+	}
+	assert.NoError(t, securityCR.ValidateCreate())
+
+	testScheme := newTestScheme(t)
+	passwordSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders-credentials", Namespace: securityName.Namespace},
+		Data:       map[string][]byte{"password": []byte("first")},
+	}
+	broker := &brokerv1beta1.ActiveMQArtemis{ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: securityName.Namespace}}
+	client := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(passwordSecret, securityCR, broker).Build()
+	handler := &ActiveMQArtemisSecurityConfigHandler{
+		SecurityCR:     securityCR,
+		NamespacedName: securityName,
+		owner:          &ActiveMQArtemisSecurityReconciler{Client: client, Scheme: testScheme},
+	}
+	namespaceToConfigHandler[securityName] = handler
+	defer delete(namespaceToConfigHandler, securityName)
+
+	// the init container reads the password from the mounted secret
+	result := handler.processCrPasswords()
+	users := result.Spec.LoginModules.PropertiesLoginModules[0].Users
+	assert.Equal(t, "secret:///amq/extra/secrets/orders-credentials/password", *users[0].Password)
+	assert.Equal(t, adminPassword, *users[1].Password)
+	assert.Nil(t, securityCR.Spec.LoginModules.PropertiesLoginModules[0].Users[0].Password)
+	initContainers := []corev1.Container{{Name: "init"}}
+	cmds := handler.Config(initContainers, "/amq/init/config", "", "")
+	assert.NotEmpty(t, cmds)
+	assert.NotContains(t, strings.Join(cmds, " "), "first")
+	assert.Contains(t, strings.Join(cmds, " "), "python3 "+initScriptPath(securitySecretsScriptName)+" /amq/init/config/security/security-config.yaml")
+	secretNames, _, _ := saslMountsAndArgs(securityCR)
+	assert.Equal(t, []string{"orders-credentials"}, secretNames)
+	checksum := environments.Retrieve(initContainers, securitySecretsChecksumEnvVarName)
+	assert.NotNil(t, checksum)
+
+	condition, _ := validateSaslResources(broker, client, testScheme)
+	assert.Nil(t, condition)
+
+	// a rotated password changes the pod template of the brokers the security cr applies to
+	reconciler := &ActiveMQArtemisReconciler{Client: withSecretNamesIndex(t, client), Scheme: testScheme}
+	assert.Equal(t, []ctrl.Request{{NamespacedName: types.NamespacedName{Name: "broker", Namespace: securityName.Namespace}}}, reconciler.brokersUsingSecret(passwordSecret))
+	assert.Empty(t, reconciler.brokersUsingSecret(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: securityName.Namespace}}))
+	passwordSecret.Data["password"] = []byte("second")
+	assert.NoError(t, client.Update(context.TODO(), passwordSecret))
+	rotated := []corev1.Container{{Name: "init"}}
+	assert.NotEmpty(t, handler.Config(rotated, "/amq/init/config", "", ""))
+	assert.NotEqual(t, checksum.Value, environments.Retrieve(rotated, securitySecretsChecksumEnvVarName).Value)
+
+	// without the secret the pods keep the previous config
+	assert.NoError(t, client.Delete(context.TODO(), passwordSecret))
+	assert.Nil(t, handler.Config([]corev1.Container{{Name: "init"}}, "/amq/init/config", "", ""))
+	condition, retry := validateSaslResources(broker, client, testScheme)
+	assert.NotNil(t, condition)
+	assert.True(t, retry)
+	assert.Equal(t, brokerv1beta1.ValidConditionMissingResourcesReason, condition.Reason)
+
+	for _, invalid := range []brokerv1beta1.UserType{
+		{Name: "orders", Password: &adminPassword, PasswordSecret: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "orders-credentials"}, Key: "password"}},
+		{Name: "orders", PasswordSecret: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "orders-credentials"}}},
+	} {
+		invalidCR := &brokerv1beta1.ActiveMQArtemisSecurity{Spec: brokerv1beta1.ActiveMQArtemisSecuritySpec{LoginModules: brokerv1beta1.LoginModulesType{PropertiesLoginModules: []brokerv1beta1.PropertiesLoginModuleType{{Name: "prop-module", Users: []brokerv1beta1.UserType{invalid}}}}}}
+		assert.Error(t, invalidCR.ValidateCreate(), invalid)
+	}
+}
+
+func TestHashedUserPasswords(t *testing.T) {
+	securityName := types.NamespacedName{Name: "hashed", Namespace: "hashed-ns"}
+	oneWay := brokerv1beta1.PasswordAlgorithmOneWay
+	hash := "1024:6F1E0D96A3B4C2D1:9C3B7E5F1A2D4C6E8B0A9F7E5D3C1B2A"
+	securityCR := &brokerv1beta1.ActiveMQArtemisSecurity{
+		ObjectMeta: metav1.ObjectMeta{Name: securityName.Name, Namespace: securityName.Namespace},
+		Spec: brokerv1beta1.ActiveMQArtemisSecuritySpec{
+			LoginModules: brokerv1beta1.LoginModulesType{
+				PropertiesLoginModules: []brokerv1beta1.PropertiesLoginModuleType{
+					{
+						Name: "prop-module",
+						Users: []brokerv1beta1.UserType{
+							{Name: "orders", Password: &hash, PasswordAlgorithm: &oneWay},
+							{Name: "billing", PasswordSecret: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "billing-credentials"}, Key: "hash"}, PasswordAlgorithm: &oneWay},
+						},
+					},
+				},
+			},
+		},
+	}
+	assert.NoError(t, securityCR.ValidateCreate())
+
+	testScheme := newTestScheme(t)
+	hashSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "billing-credentials", Namespace: securityName.Namespace},
+		Data:       map[string][]byte{"hash": []byte("ENC(1024:AB12:CD34)\n")},
+	}
+	handler := &ActiveMQArtemisSecurityConfigHandler{
+		SecurityCR:     securityCR,
+		NamespacedName: securityName,
+		owner:          &ActiveMQArtemisSecurityReconciler{Client: fake.NewClientBuilder().WithScheme(testScheme).WithObjects(hashSecret, securityCR).Build(), Scheme: testScheme},
+	}
+
+	// the hashes reach the broker marked for the default codec
+	result := handler.processCrPasswords()
+	users := result.Spec.LoginModules.PropertiesLoginModules[0].Users
+	assert.Equal(t, "ENC("+hash+")", *users[0].Password)
+	assert.Equal(t, "secret+enc:///amq/extra/secrets/billing-credentials/hash", *users[1].Password)
+	assert.Equal(t, hash, *securityCR.Spec.LoginModules.PropertiesLoginModules[0].Users[0].Password)
+	cmd, err := handler.persistCR("/tmp/security-config.yaml", result)
+	assert.NoError(t, err)
+	assert.Contains(t, cmd, "ENC("+hash+")")
+	assert.NotContains(t, cmd, "one-way")
+
+	// a codec of its own verifies the hashes and decodes the masked passwords of the broker
+	assert.Empty(t, propertiesCodecCmd(securityCR))
+	codec, mask := "com.example.Codec;key=hello world", true
+	securityCR.Spec.PasswordMasking = &brokerv1beta1.PasswordMaskingType{MaskPassword: &mask, PasswordCodec: &codec}
+	option := base64.StdEncoding.EncodeToString([]byte(`org.apache.activemq.jaas.properties.password.codec="com.example.Codec;key=hello world"`))
+	assert.Equal(t, "python3 "+initScriptPath(propertiesCodecScriptName)+" /amq/init/config/etc/login.config "+option, propertiesCodecCmd(securityCR))
+	namespaceToConfigHandler[securityName] = handler
+	defer delete(namespaceToConfigHandler, securityName)
+	broker := &brokerv1beta1.ActiveMQArtemis{ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: securityName.Namespace}}
+	assert.Equal(t, []string{"maskPassword=true", "passwordCodec=com.example.Codec;key=hello world"}, passwordMaskingProperties(broker))
+
+	plain := "not-a-hash"
+	for _, invalid := range []brokerv1beta1.UserType{
+		{Name: "orders", PasswordAlgorithm: &oneWay},
+		{Name: "orders", Password: &plain, PasswordAlgorithm: &oneWay},
+	} {
+		invalidCR := &brokerv1beta1.ActiveMQArtemisSecurity{Spec: brokerv1beta1.ActiveMQArtemisSecuritySpec{LoginModules: brokerv1beta1.LoginModulesType{PropertiesLoginModules: []brokerv1beta1.PropertiesLoginModuleType{{Name: "prop-module", Users: []brokerv1beta1.UserType{invalid}}}}}}
+		assert.Error(t, invalidCR.ValidateCreate(), invalid)
+	}
+}
+
+func TestManagementDeniedList(t *testing.T) {
+	cr := &brokerv1beta1.ActiveMQArtemis{ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: "mgmt-ns"}}
+	assert.Empty(t, jolokiaAccessCmd(cr))
+
+	mgmtDomain := "org.apache.activemq.artemis"
+	listMethod := "list*"
+	securityName := types.NamespacedName{Name: "mgmt-sec", Namespace: "mgmt-ns"}
+	securityCR := &brokerv1beta1.ActiveMQArtemisSecurity{
+		ObjectMeta: metav1.ObjectMeta{Name: securityName.Name, Namespace: securityName.Namespace},
+		Spec: brokerv1beta1.ActiveMQArtemisSecuritySpec{
+			SecuritySettings: brokerv1beta1.SecuritySettingsType{
+				Management: brokerv1beta1.ManagementSecuritySettingsType{
+					HawtioRoles: []string{"admin"},
+					Authorisation: brokerv1beta1.AuthorisationConfigType{
+						RoleAccess: []brokerv1beta1.RoleAccessType{
+							{Domain: &mgmtDomain, AccessList: []brokerv1beta1.DefaultAccessType{{Method: &listMethod, Roles: []string{"viewer", "admin"}}}},
+						},
+						DeniedList: []brokerv1beta1.DeniedListEntryType{
+							{Domain: mgmtDomain, Key: "broker=*", Operations: []string{"forceFailover", "stop"}},
+							{Domain: "java.lang", Attributes: []string{"SystemProperties"}},
+						},
+					},
+				},
+			},
+		},
+	}
+	assert.NoError(t, securityCR.ValidateCreate())
+	namespaceToConfigHandler[securityName] = &ActiveMQArtemisSecurityConfigHandler{
+		SecurityCR:     securityCR,
+		NamespacedName: securityName,
+	}
+	defer delete(namespaceToConfigHandler, securityName)
+
+	// the default cors policy of the instance is kept alongside the denied entries
+	assert.Equal(t, `<restrict><cors><allow-origin>*://localhost*</allow-origin><strict-checking/></cors><deny>`+
+		`<mbean><name>org.apache.activemq.artemis:broker=*</name><operation>forceFailover</operation><operation>stop</operation></mbean>`+
+		`<mbean><name>java.lang:*</name><attribute>SystemProperties</attribute></mbean>`+
+		`</deny></restrict>`, jolokiaAccessPolicy(cr))
+	assert.Equal(t, "cp /amq/init/scripts/jolokia-access.xml /amq/init/config/etc/jolokia-access.xml", jolokiaAccessCmd(cr))
+
+	cr.Spec.Console.Jolokia = &brokerv1beta1.JolokiaType{AllowedOrigins: []string{"*://console.example.com*"}}
+	assert.Contains(t, jolokiaAccessPolicy(cr), "<cors><allow-origin>*://console.example.com*</allow-origin><strict-checking/></cors><deny>")
+
+	handler := &ActiveMQArtemisSecurityConfigHandler{SecurityCR: securityCR, NamespacedName: securityName}
+	cmd, err := handler.persistCR("/tmp/security-config.yaml", securityCR)
+	assert.NoError(t, err)
+	assert.NotContains(t, cmd, "forceFailover")
+	assert.Contains(t, cmd, "viewer")
+
+	empty := ""
+	for _, invalid := range []brokerv1beta1.ManagementSecuritySettingsType{
+		{HawtioRoles: []string{""}},
+		{Authorisation: brokerv1beta1.AuthorisationConfigType{AllowedList: []brokerv1beta1.AllowedListEntryType{{Domain: &empty}}}},
+		{Authorisation: brokerv1beta1.AuthorisationConfigType{DefaultAccess: []brokerv1beta1.DefaultAccessType{{Roles: []string{"admin"}}}}},
+		{Authorisation: brokerv1beta1.AuthorisationConfigType{DefaultAccess: []brokerv1beta1.DefaultAccessType{{Method: &listMethod}}}},
+		{Authorisation: brokerv1beta1.AuthorisationConfigType{RoleAccess: []brokerv1beta1.RoleAccessType{{AccessList: []brokerv1beta1.DefaultAccessType{{Method: &listMethod, Roles: []string{"admin"}}}}}}},
+		{Authorisation: brokerv1beta1.AuthorisationConfigType{DeniedList: []brokerv1beta1.DeniedListEntryType{{Operations: []string{"stop"}}}}},
+		{Authorisation: brokerv1beta1.AuthorisationConfigType{DeniedList: []brokerv1beta1.DeniedListEntryType{{Domain: mgmtDomain}}}},
+		{Authorisation: brokerv1beta1.AuthorisationConfigType{DeniedList: []brokerv1beta1.DeniedListEntryType{{Domain: "java.lang:type=Runtime"}}}},
+		{Authorisation: brokerv1beta1.AuthorisationConfigType{DeniedList: []brokerv1beta1.DeniedListEntryType{{Domain: mgmtDomain, Operations: []string{"stop\"; rm -rf /"}}}}},
+	} {
+		invalidCR := &brokerv1beta1.ActiveMQArtemisSecurity{Spec: brokerv1beta1.ActiveMQArtemisSecuritySpec{SecuritySettings: brokerv1beta1.SecuritySettingsType{Management: invalid}}}
+		assert.Error(t, invalidCR.ValidateCreate(), invalid)
+	}
+}
+
+func TestGuestLoginModule(t *testing.T) {
+	moduleName := "guest-module"
+	propertiesName := "prop-module"
+	sufficient := "sufficient"
+	disabled := false
+	securityCR := &brokerv1beta1.ActiveMQArtemisSecurity{
+		Spec: brokerv1beta1.ActiveMQArtemisSecuritySpec{
+			LoginModules: brokerv1beta1.LoginModulesType{
+				PropertiesLoginModules: []brokerv1beta1.PropertiesLoginModuleType{{
+					Name:  propertiesName,
+					Users: []brokerv1beta1.UserType{{Name: "producer", Roles: []string{"producers"}}},
+				}},
+				GuestLoginModules: []brokerv1beta1.GuestLoginModuleType{{Name: moduleName}},
+			},
+			SecurityDomains: brokerv1beta1.SecurityDomainsType{
+				BrokerDomain: brokerv1beta1.BrokerDomainType{LoginModules: []brokerv1beta1.LoginModuleReferenceType{
+					{Name: &propertiesName, Flag: &sufficient},
+					{Name: &moduleName, Flag: &sufficient},
+				}},
+			},
+			SecuritySettings: brokerv1beta1.SecuritySettingsType{
+				Broker: []brokerv1beta1.BrokerSecuritySettingType{
+					{Match: "#", Permissions: []brokerv1beta1.PermissionType{{OperationType: "send", Roles: []string{"producers", "guests"}}}},
+				},
+			},
+		},
+	}
+	// without a role of its own the guest user has the one of the broker module
+	assert.NoError(t, securityCR.ValidateCreate())
+
+	handler := &ActiveMQArtemisSecurityConfigHandler{SecurityCR: securityCR}
+	cmd, err := handler.persistCR("/tmp/security-config.yaml", securityCR)
+	assert.NoError(t, err)
+	assert.Contains(t, cmd, "guestloginmodules:\n    - name: guest-module")
+	// the role the settings were validated against is the one the module logs guests in with
+	assert.Contains(t, cmd, "guestrole: guests")
+	assert.Nil(t, securityCR.Spec.LoginModules.GuestLoginModules[0].GuestRole)
+	assert.Contains(t, cmd, "- name: guest-module\n        flag: sufficient")
+
+	// disabled, the module and its reference are gone while the settings stay valid
+	securityCR.Spec.LoginModules.GuestLoginModules[0].Enabled = &disabled
+	assert.NoError(t, securityCR.ValidateUpdate(securityCR))
+	cmd, err = handler.persistCR("/tmp/security-config.yaml", securityCR)
+	assert.NoError(t, err)
+	assert.NotContains(t, cmd, "guest-module")
+	assert.Contains(t, cmd, "name: prop-module")
+	assert.Len(t, securityCR.Spec.SecurityDomains.BrokerDomain.LoginModules, 2)
+}
+
+func TestSecuritySettingsEffectivePermissions(t *testing.T) {
+	guestRole := "guests"
+	securityCR := &brokerv1beta1.ActiveMQArtemisSecurity{
+		Spec: brokerv1beta1.ActiveMQArtemisSecuritySpec{
+			LoginModules: brokerv1beta1.LoginModulesType{
+				PropertiesLoginModules: []brokerv1beta1.PropertiesLoginModuleType{{
+					Name: "prop-module",
+					Users: []brokerv1beta1.UserType{
+						{Name: "producer", Roles: []string{"producers"}},
+						{Name: "consumer", Roles: []string{"consumers"}},
+					},
+				}},
+				GuestLoginModules: []brokerv1beta1.GuestLoginModuleType{{Name: "guest-module", GuestRole: &guestRole}},
+			},
+			SecuritySettings: brokerv1beta1.SecuritySettingsType{
+				Broker: []brokerv1beta1.BrokerSecuritySettingType{
+					{Match: "#", Permissions: []brokerv1beta1.PermissionType{
+						{OperationType: "browse", Roles: []string{"guests"}},
+					}},
+					{Match: "orders.#", Permissions: []brokerv1beta1.PermissionType{
+						{OperationType: "send", Roles: []string{"producers"}},
+						{OperationType: "consume", Roles: []string{"consumers"}},
+						{OperationType: "send", Roles: []string{"consumers", "producers"}},
+					}},
+					{Match: "orders.*.audit", Permissions: []brokerv1beta1.PermissionType{
+						{OperationType: "consume", Roles: []string{"consumers"}},
+					}},
+				},
+			},
+		},
+	}
+	assert.NoError(t, securityCR.ValidateCreate())
+
+	// the reserved prefixes and the broker properties add roles to their matches
+	broker := &brokerv1beta1.ActiveMQArtemis{
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			ReservedAddressPrefixes: &brokerv1beta1.ReservedAddressPrefixesType{Prefixes: []string{"sys."}, Roles: []string{"ops"}},
+			BrokerProperties: []string{
+				`securityRoles."orders.#".auditors.browse=true`,
+				`securityRoles."orders.#".auditors.send=false`,
+				"globalMaxSize=512m",
+			},
+		},
+	}
+	reservedPermissions := []brokerv1beta1.PermissionType{}
+	for _, operationType := range brokerv1beta1.BrokerPermissionTypes {
+		reservedPermissions = append(reservedPermissions, brokerv1beta1.PermissionType{OperationType: operationType, Roles: []string{"ops"}})
+	}
+	assert.Equal(t, []brokerv1beta1.EffectivePermissionType{
+		{Match: "#", Permissions: []brokerv1beta1.PermissionType{
+			{OperationType: "browse", Roles: []string{"guests"}},
+		}},
+		{Match: "orders.#", Permissions: []brokerv1beta1.PermissionType{
+			{OperationType: "send", Roles: []string{"producers", "consumers"}},
+			{OperationType: "consume", Roles: []string{"consumers"}},
+			{OperationType: "browse", Roles: []string{"auditors"}},
+		}, Overrides: []string{"#"}},
+		{Match: "orders.*.audit", Permissions: []brokerv1beta1.PermissionType{
+			{OperationType: "consume", Roles: []string{"consumers"}},
+		}, Overrides: []string{"#", "orders.#"}},
+		{Match: "sys.#", Permissions: reservedPermissions, Overrides: []string{"#"}},
+	}, effectiveSecurityPermissions(securityCR, broker))
+
+	// without a catch-all match of the CR the admin role keeps the default permissions
+	defaults := securityCR.DeepCopy()
+	defaults.Spec.SecuritySettings.Broker = defaults.Spec.SecuritySettings.Broker[1:2]
+	effective := effectiveSecurityPermissions(defaults, &brokerv1beta1.ActiveMQArtemis{})
+	assert.Equal(t, "#", effective[0].Match)
+	assert.Len(t, effective[0].Permissions, len(brokerv1beta1.BrokerPermissionTypes))
+	assert.Equal(t, []string{DefaultAdminRole}, effective[0].Permissions[0].Roles)
+
+	assert.True(t, brokerv1beta1.SecurityMatchCovers("a.#", "a.#.#"))
+	assert.True(t, brokerv1beta1.SecurityMatchCovers("a.*.c", "a.b.c"))
+	assert.False(t, brokerv1beta1.SecurityMatchCovers("a.*", "a.#"))
+	assert.False(t, brokerv1beta1.SecurityMatchCovers("a.b.#", "a.#"))
+
+	for _, invalid := range [][]brokerv1beta1.BrokerSecuritySettingType{
+		{{Match: ""}},
+		{{Match: "orders..new"}},
+		{{Match: "orders.new#"}},
+		{{Match: "orders*"}},
+		{{Match: "orders.#"}, {Match: "orders.#.#"}},
+		{{Match: "orders.#", Permissions: []brokerv1beta1.PermissionType{{OperationType: "publish", Roles: []string{"producers"}}}}},
+		{{Match: "orders.#", Permissions: []brokerv1beta1.PermissionType{{OperationType: "send", Roles: []string{"producers,consumers"}}}}},
+		{{Match: "orders.#", Permissions: []brokerv1beta1.PermissionType{{OperationType: "send", Roles: []string{"shippers"}}}}},
+	} {
+		invalidCR := securityCR.DeepCopy()
+		invalidCR.Spec.SecuritySettings.Broker = invalid
+		assert.Error(t, invalidCR.ValidateCreate(), invalid)
+	}
+
+	// an update only checks the settings it adds or changes
+	stored := securityCR.DeepCopy()
+	stored.Spec.SecuritySettings.Broker[1].Permissions[0].Roles = []string{"shippers"}
+	updated := stored.DeepCopy()
+	updated.Spec.SecuritySettings.Broker[2].Permissions[0].Roles = []string{"producers"}
+	assert.NoError(t, updated.ValidateUpdate(stored))
+	updated.Spec.SecuritySettings.Broker[2].Permissions[0].Roles = []string{"shippers"}
+	assert.Error(t, updated.ValidateUpdate(stored))
+	updated = stored.DeepCopy()
+	updated.Spec.SecuritySettings.Broker = append(updated.Spec.SecuritySettings.Broker, brokerv1beta1.BrokerSecuritySettingType{Match: "orders.#.#"})
+	assert.Error(t, updated.ValidateUpdate(stored))
+
+	// the roles of a keycloak token are not known up front
+	realm, authServerUrl := "artemis", "https://keycloak.example.com/auth"
+	securityCR.Spec.LoginModules.KeycloakLoginModules = []brokerv1beta1.KeycloakLoginModuleType{{
+		Name: "keycloak-module",
+		Configuration: brokerv1beta1.KeycloakModuleConfigurationType{
+			Realm:         &realm,
+			AuthServerUrl: &authServerUrl,
+		},
+	}}
+	securityCR.Spec.SecuritySettings.Broker[1].Permissions[0].Roles = []string{"shippers"}
+	assert.NoError(t, securityCR.ValidateCreate())
+
+	handler := &ActiveMQArtemisSecurityConfigHandler{SecurityCR: securityCR}
+	cmd, err := handler.persistCR("/tmp/security-config.yaml", securityCR)
+	assert.NoError(t, err)
+	securityCR.Status.ObservedGeneration = 2
+	securityCR.Status.Brokers = []brokerv1beta1.SecurityBrokerStatus{{Name: "broker", EffectivePermissions: effectiveSecurityPermissions(securityCR, broker)}}
+	withStatus, err := handler.persistCR("/tmp/security-config.yaml", securityCR)
+	assert.NoError(t, err)
+	assert.Equal(t, cmd, withStatus)
+}
@@ -120,7 +120,11 @@ func (c *AddressObserver) checkCRsForNewPod(newPod *corev1.Pod, labels map[strin
 	// go over each address instance for the new pod
 	for _, a := range addressInstances.Items {
 		//get the target namespaces
-		targetCrNamespacedNames := createTargetCrNamespacedNames(newPod.Namespace, a.Spec.ApplyToCrNames)
+		targetCrNamespacedNames, err := targetBrokerCrNames(c.opclient, newPod.Namespace, a.Spec.ApplyToCrNames, a.Spec.ApplyToCrSelector)
+		if err != nil {
+			olog.Error(err, "Can't list the brokers of applyToCrSelector", "address", a.Name)
+			continue
+		}
 		//e.g. ex-aao-ss
 		podSSName, _ := c.getSSNameForPod(newPod)
 		if podSSName == nil {
@@ -160,7 +164,7 @@ func (c *AddressObserver) checkCRsForNewPod(newPod *corev1.Pod, labels map[strin
 			jks := jc.GetBrokers(podNamespacedName, ssInfos, c.opclient)
 
 			for _, jk := range jks {
-				createAddressResource(jk, &a)
+				createAddressResource(jk, &a, appliedOnPod(&a, jk.PodName))
 			}
 		}
 	}
@@ -0,0 +1,239 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	mgmt "github.com/artemiscloud/activemq-artemis-operator/pkg/utils/artemis"
+	jc "github.com/artemiscloud/activemq-artemis-operator/pkg/utils/jolokia_client"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// addressUpdate is what changed between the spec an address was last applied with and the current one
+type addressUpdate struct {
+	created            bool
+	renamed            bool
+	routingTypeChanged bool
+	queueChanged       bool
+	applyToChanged     bool
+	// the queues of the queues list that were dropped, added or reconfigured
+	removedQueues []string
+	addedQueues   []string
+	changedQueues []string
+}
+
+func isQueueAddress(addressRes *brokerv1beta1.ActiveMQArtemisAddress) bool {
+	return addressRes.Spec.QueueName != nil && *addressRes.Spec.QueueName != ""
+}
+
+// hasQueues tells whether the CR creates queues on its address, the queue of queueName or those of the
+// queues list
+func hasQueues(addressRes *brokerv1beta1.ActiveMQArtemisAddress) bool {
+	return isQueueAddress(addressRes) || len(addressRes.Spec.Queues) > 0
+}
+
+// describeAddress names the queues of an address CR or, without a queue, the address
+func describeAddress(addressRes *brokerv1beta1.ActiveMQArtemisAddress) string {
+	if isQueueAddress(addressRes) {
+		return describeQueue(addressRes.Spec.AddressName, *addressRes.Spec.QueueName)
+	}
+	if len(addressRes.Spec.Queues) > 0 {
+		names := []string{}
+		for _, queue := range addressRes.Spec.Queues {
+			names = append(names, queue.Name)
+		}
+		return fmt.Sprintf("queues %v on address %v", strings.Join(names, ", "), addressRes.Spec.AddressName)
+	}
+	return "address " + addressRes.Spec.AddressName
+}
+
+func describeQueue(addressName string, queueName string) string {
+	return fmt.Sprintf("queue %v on address %v", queueName, addressName)
+}
+
+func planAddressUpdate(previous *brokerv1beta1.ActiveMQArtemisAddress, current *brokerv1beta1.ActiveMQArtemisAddress) addressUpdate {
+	if previous == nil {
+		return addressUpdate{created: true}
+	}
+	applyToChanged := !equality.Semantic.DeepEqual(previous.Spec.ApplyToCrNames, current.Spec.ApplyToCrNames) ||
+		!equality.Semantic.DeepEqual(previous.Spec.ApplyToCrSelector, current.Spec.ApplyToCrSelector)
+	if previous.Spec.AddressName == current.Spec.AddressName && len(previous.Spec.Queues) > 0 && len(current.Spec.Queues) > 0 {
+		update := planQueuesUpdate(previous, current)
+		update.applyToChanged = applyToChanged
+		return update
+	}
+	renamed := previous.Spec.AddressName != current.Spec.AddressName || isQueueAddress(previous) != isQueueAddress(current) ||
+		(isQueueAddress(current) && *previous.Spec.QueueName != *current.Spec.QueueName) ||
+		(len(previous.Spec.Queues) > 0) != (len(current.Spec.Queues) > 0)
+	return addressUpdate{
+		renamed:            renamed,
+		routingTypeChanged: !renamed && queueRoutingType(previous) != queueRoutingType(current),
+		queueChanged:       !renamed && isQueueAddress(current) && !equality.Semantic.DeepEqual(previous.Spec.QueueConfiguration, current.Spec.QueueConfiguration),
+		applyToChanged:     applyToChanged,
+	}
+}
+
+// planQueuesUpdate compares the queues lists of an address, by queue name. The address takes both
+// routing types as soon as a queue brings a routing type it didn't have
+func planQueuesUpdate(previous *brokerv1beta1.ActiveMQArtemisAddress, current *brokerv1beta1.ActiveMQArtemisAddress) addressUpdate {
+	update := addressUpdate{}
+	previousQueues := map[string]*brokerv1beta1.ActiveMQArtemisAddress{}
+	previousRoutingTypes := map[string]bool{}
+	for _, queue := range addressQueues(previous) {
+		previousQueues[*queue.Spec.QueueName] = queue
+		previousRoutingTypes[queueRoutingType(queue)] = true
+	}
+	currentQueues := map[string]bool{}
+	for _, queue := range addressQueues(current) {
+		name := *queue.Spec.QueueName
+		currentQueues[name] = true
+		previousQueue, found := previousQueues[name]
+		if !found {
+			update.addedQueues = append(update.addedQueues, name)
+			update.routingTypeChanged = update.routingTypeChanged || !previousRoutingTypes[queueRoutingType(queue)]
+			continue
+		}
+		if queueRoutingType(previousQueue) != queueRoutingType(queue) {
+			update.routingTypeChanged = true
+			update.changedQueues = append(update.changedQueues, name)
+		} else if !equality.Semantic.DeepEqual(previousQueue.Spec.QueueConfiguration, queue.Spec.QueueConfiguration) {
+			update.changedQueues = append(update.changedQueues, name)
+		}
+	}
+	for _, queue := range previous.Spec.Queues {
+		if !currentQueues[queue.Name] {
+			update.removedQueues = append(update.removedQueues, queue.Name)
+		}
+	}
+	return update
+}
+
+// applyAddressUpdate makes the changes to the brokers that creating the address doesn't. A renamed
+// address or queue and the brokers an address no longer applies to lose the previous one, as far as
+// its removal policy removes it on delete. A new routing type is added to the address so the queue can
+// take it. It returns what it changed
+func applyAddressUpdate(ctx context.Context, previous *brokerv1beta1.ActiveMQArtemisAddress, current *AddressDeployment, update addressUpdate, getBrokers func(*AddressDeployment) []*jc.JkInfo) ([]string, error) {
+	reqLogger := log.FromContext(ctx)
+	changes := []string{}
+	if update.created {
+		return append(changes, "created "+describeAddress(&current.AddressResource)), nil
+	}
+
+	currentBrokers := getBrokers(current)
+	if update.renamed || update.applyToChanged {
+		currentPods := map[string]bool{}
+		for _, broker := range currentBrokers {
+			currentPods[broker.PodName] = true
+		}
+		// a renamed queue goes from every pod, the queue names are unique on a broker
+		removeFrom := []*jc.JkInfo{}
+		for _, broker := range getBrokers(&AddressDeployment{AddressResource: *previous, SsTargetNameBuilders: createNameBuilders(previous)}) {
+			if update.renamed || !currentPods[broker.PodName] {
+				removeFrom = append(removeFrom, broker)
+			}
+		}
+		pods := jkPodNames(removeFrom)
+		policy := addressRemovalPolicy(previous)
+		if len(pods) > 0 && (policy == brokerv1beta1.AddressRemovalPolicyOrphan || (policy == brokerv1beta1.AddressRemovalPolicyRemoveQueue && !hasQueues(previous))) {
+			changes = append(changes, fmt.Sprintf("kept %v on %v, the removal policy is %v", describeAddress(previous), strings.Join(pods, ", "), policy))
+		} else if len(pods) > 0 {
+			for _, broker := range removeFrom {
+				if err := deleteFromBroker(broker.Artemis, previous); err != nil {
+					podLogger(ctx, broker.PodName).Info("failed to remove the previous address", "error", err.Error())
+					return changes, err
+				}
+			}
+			changes = append(changes, fmt.Sprintf("removed %v from %v", describeAddress(previous), strings.Join(pods, ", ")))
+		}
+	}
+
+	if update.renamed {
+		changes = append(changes, "created "+describeAddress(&current.AddressResource))
+	}
+
+	// a queue dropped from the queues list goes from the brokers the address is still on
+	if len(update.removedQueues) > 0 {
+		removed := map[string]bool{}
+		for _, name := range update.removedQueues {
+			removed[name] = true
+		}
+		pods := jkPodNames(currentBrokers)
+		policy := addressRemovalPolicy(previous)
+		for _, queue := range addressQueues(previous) {
+			if !removed[*queue.Spec.QueueName] || len(pods) == 0 {
+				continue
+			}
+			if policy == brokerv1beta1.AddressRemovalPolicyOrphan {
+				changes = append(changes, fmt.Sprintf("kept %v on %v, the removal policy is %v", describeAddress(queue), strings.Join(pods, ", "), policy))
+				continue
+			}
+			for _, broker := range currentBrokers {
+				if err := deleteFromBroker(broker.Artemis, queue); err != nil {
+					podLogger(ctx, broker.PodName).Info("failed to remove a queue of the address", "queue", *queue.Spec.QueueName, "error", err.Error())
+					return changes, err
+				}
+			}
+			changes = append(changes, fmt.Sprintf("removed %v from %v", describeAddress(queue), strings.Join(pods, ", ")))
+		}
+	}
+	for _, name := range update.addedQueues {
+		changes = append(changes, "created "+describeQueue(current.AddressResource.Spec.AddressName, name))
+	}
+
+	if update.routingTypeChanged {
+		routingType := queueRoutingType(&current.AddressResource)
+		// other queues of the address may still use the previous routing type
+		routingTypes := routingType
+		if hasQueues(&current.AddressResource) {
+			routingTypes = "ANYCAST,MULTICAST"
+		}
+		for _, broker := range currentBrokers {
+			if respData, err := broker.Artemis.UpdateAddress(current.AddressResource.Spec.AddressName, routingTypes); err != nil && !mgmt.IsNotFoundError(respData) {
+				podLogger(ctx, broker.PodName).Info("failed to update the address routing types", "error", err.Error())
+				return changes, err
+			}
+		}
+		if len(current.AddressResource.Spec.Queues) == 0 {
+			changes = append(changes, fmt.Sprintf("changed the routing type of %v to %v", describeAddress(&current.AddressResource), routingType))
+		}
+	}
+
+	if update.queueChanged {
+		changes = append(changes, "updated the configuration of "+describeAddress(&current.AddressResource))
+	}
+	if len(update.changedQueues) > 0 {
+		changed := map[string]bool{}
+		for _, name := range update.changedQueues {
+			changed[name] = true
+		}
+		previousRoutingTypes := map[string]string{}
+		for _, queue := range addressQueues(previous) {
+			previousRoutingTypes[*queue.Spec.QueueName] = queueRoutingType(queue)
+		}
+		for _, queue := range addressQueues(&current.AddressResource) {
+			if !changed[*queue.Spec.QueueName] {
+				continue
+			}
+			if routingType := queueRoutingType(queue); routingType != previousRoutingTypes[*queue.Spec.QueueName] {
+				changes = append(changes, fmt.Sprintf("changed the routing type of %v to %v", describeAddress(queue), routingType))
+			} else {
+				changes = append(changes, "updated the configuration of "+describeAddress(queue))
+			}
+		}
+	}
+	reqLogger.V(1).Info("updated address", "changes", changes)
+	return changes, nil
+}
+
+func jkPodNames(brokers []*jc.JkInfo) []string {
+	pods := []string{}
+	for _, broker := range brokers {
+		pods = append(pods, broker.PodName)
+	}
+	sort.Strings(pods)
+	return pods
+}
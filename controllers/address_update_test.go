@@ -0,0 +1,146 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	jc "github.com/artemiscloud/activemq-artemis-operator/pkg/utils/jolokia_client"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestAddressUpdate(t *testing.T) {
+	queue := "orders"
+	renamedQueue := "orders-v2"
+	anycast := "anycast"
+	multicast := "MULTICAST"
+	maxConsumers := int32(5)
+	previous := &brokerv1beta1.ActiveMQArtemisAddress{
+		Spec: brokerv1beta1.ActiveMQArtemisAddressSpec{AddressName: "orders", QueueName: &queue, RoutingType: &anycast, ApplyToCrNames: []string{"broker"}},
+	}
+
+	assert.Equal(t, addressUpdate{created: true}, planAddressUpdate(nil, previous))
+	assert.Equal(t, addressUpdate{}, planAddressUpdate(previous, previous.DeepCopy()))
+
+	current := previous.DeepCopy()
+	current.Spec.RoutingType = &multicast
+	current.Spec.QueueConfiguration = &brokerv1beta1.QueueConfigurationType{MaxConsumers: &maxConsumers}
+	current.Spec.ApplyToCrNames = []string{"broker", "other"}
+	assert.Equal(t, addressUpdate{routingTypeChanged: true, queueChanged: true, applyToChanged: true}, planAddressUpdate(previous, current))
+
+	// a renamed queue is created anew, its routing type and configuration come with it
+	current.Spec.QueueName = &renamedQueue
+	assert.Equal(t, addressUpdate{renamed: true, applyToChanged: true}, planAddressUpdate(previous, current))
+
+	noBrokers := func(*AddressDeployment) []*jc.JkInfo { return nil }
+	deployment := &AddressDeployment{AddressResource: *current}
+	changes, err := applyAddressUpdate(context.TODO(), previous, deployment, planAddressUpdate(previous, current), noBrokers)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"created queue orders-v2 on address orders"}, changes)
+
+	current.Spec.QueueName = &queue
+	current.Spec.ApplyToCrNames = previous.Spec.ApplyToCrNames
+	changes, err = applyAddressUpdate(context.TODO(), previous, &AddressDeployment{AddressResource: *current}, planAddressUpdate(previous, current), noBrokers)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"changed the routing type of queue orders on address orders to MULTICAST", "updated the configuration of queue orders on address orders"}, changes)
+
+	// the status reports the changes of the generation that was applied
+	testScheme := runtime.NewScheme()
+	assert.NoError(t, brokerv1beta1.AddToScheme(testScheme))
+	current.ObjectMeta = metav1.ObjectMeta{Name: "orders", Namespace: "test", Generation: 2}
+	fakeClient := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(current).Build()
+	r := &ActiveMQArtemisAddressReconciler{Client: fakeClient, Scheme: testScheme}
+	assert.NoError(t, r.updateAddressStatus(context.TODO(), current, changes, nil, nil))
+	assert.Equal(t, int64(2), current.Status.ObservedGeneration)
+	assert.Equal(t, changes, current.Status.Changes)
+	assert.True(t, meta.IsStatusConditionTrue(current.Status.Conditions, brokerv1beta1.AddressAppliedConditionType))
+
+	assert.NoError(t, r.updateAddressStatus(context.TODO(), current, nil, nil, errors.New("broker unavailable")))
+	applied := meta.FindStatusCondition(current.Status.Conditions, brokerv1beta1.AddressAppliedConditionType)
+	assert.Equal(t, brokerv1beta1.AddressAppliedFailedReason, applied.Reason)
+	assert.Equal(t, "broker unavailable", applied.Message)
+	assert.Equal(t, changes, current.Status.Changes)
+}
+
+func TestAddressQueues(t *testing.T) {
+	multicast := "multicast"
+	anycast := "ANYCAST"
+	maxConsumers := int32(1)
+	previous := &brokerv1beta1.ActiveMQArtemisAddress{
+		Spec: brokerv1beta1.ActiveMQArtemisAddressSpec{AddressName: "prices", RoutingType: &multicast, RemoveFromBrokerOnDelete: true, Queues: []brokerv1beta1.AddressQueueType{
+			{Name: "prices.web"},
+			{Name: "prices.mobile"},
+			{Name: "prices.audit", QueueConfigurationType: brokerv1beta1.QueueConfigurationType{MaxConsumers: &maxConsumers}},
+		}},
+	}
+	assert.NoError(t, previous.Spec.Validate())
+	assert.Equal(t, "queues prices.web, prices.mobile, prices.audit on address prices", describeAddress(previous))
+
+	// each queue takes the routing type of the address unless it has its own
+	queues := addressQueues(previous)
+	assert.Len(t, queues, 3)
+	assert.Equal(t, "prices", queues[2].Spec.AddressName)
+	assert.Equal(t, "prices.audit", *queues[2].Spec.QueueName)
+	assert.Equal(t, "MULTICAST", queueRoutingType(queues[2]))
+	assert.Equal(t, maxConsumers, *queues[2].Spec.QueueConfiguration.MaxConsumers)
+	assert.Equal(t, brokerv1beta1.AddressRemovalPolicyRemoveQueueAndAddress, queues[2].Spec.RemovalPolicy)
+	assert.Equal(t, "MULTICAST", addressRoutingTypes(previous))
+	queueCfg, _, err := GetQueueConfig(queues[1])
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"name":"prices.mobile","address":"prices","routing-type":"MULTICAST"}`, queueCfg)
+
+	current := previous.DeepCopy()
+	current.Spec.Queues[0].RoutingType = &anycast
+	current.Spec.Queues[2].MaxConsumers = nil
+	current.Spec.Queues = append(current.Spec.Queues[:1], current.Spec.Queues[2], brokerv1beta1.AddressQueueType{Name: "prices.archive"})
+	assert.Equal(t, "ANYCAST,MULTICAST", addressRoutingTypes(current))
+	assert.Equal(t, addressUpdate{routingTypeChanged: true, removedQueues: []string{"prices.mobile"}, addedQueues: []string{"prices.archive"}, changedQueues: []string{"prices.web", "prices.audit"}}, planAddressUpdate(previous, current))
+	assert.Equal(t, addressUpdate{}, planAddressUpdate(current, current.DeepCopy()))
+
+	noBrokers := func(*AddressDeployment) []*jc.JkInfo { return nil }
+	changes, err := applyAddressUpdate(context.TODO(), previous, &AddressDeployment{AddressResource: *current}, planAddressUpdate(previous, current), noBrokers)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"created queue prices.archive on address prices",
+		"changed the routing type of queue prices.web on address prices to ANYCAST",
+		"updated the configuration of queue prices.audit on address prices",
+	}, changes)
+
+	// moving between queueName and a queues list, or to another address, creates the queues anew
+	single := previous.DeepCopy()
+	single.Spec.Queues = nil
+	queueName := "prices.web"
+	single.Spec.QueueName = &queueName
+	assert.True(t, planAddressUpdate(single, current).renamed)
+	assert.True(t, planAddressUpdate(current, single).renamed)
+	moved := current.DeepCopy()
+	moved.Spec.AddressName = "quotes"
+	assert.True(t, planAddressUpdate(current, moved).renamed)
+
+	for _, invalid := range []brokerv1beta1.ActiveMQArtemisAddressSpec{
+		{AddressName: "prices", QueueName: &queueName, Queues: []brokerv1beta1.AddressQueueType{{Name: "prices.mobile"}}},
+		{AddressName: "prices", QueueConfiguration: &brokerv1beta1.QueueConfigurationType{}, Queues: []brokerv1beta1.AddressQueueType{{Name: "prices.mobile"}}},
+		{AddressName: "prices", Queues: []brokerv1beta1.AddressQueueType{{Name: "prices.mobile"}, {Name: "prices.mobile"}}},
+		{AddressName: "prices", Queues: []brokerv1beta1.AddressQueueType{{Name: "prices.*"}}},
+		{AddressName: "prices", Queues: []brokerv1beta1.AddressQueueType{{Name: "prices.mobile", QueueConfigurationType: brokerv1beta1.QueueConfigurationType{RoutingType: &queueName}}}},
+	} {
+		assert.Error(t, invalid.Validate(), invalid)
+	}
+
+	// the queues of the list are unique on the brokers too
+	existing := &brokerv1beta1.ActiveMQArtemisAddress{ObjectMeta: metav1.ObjectMeta{Name: "prices", Namespace: "test"}, Spec: current.Spec}
+	brokerv1beta1.SetWebhookClient(fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithObjects(existing).Build())
+	defer brokerv1beta1.SetWebhookClient(nil)
+	address := &brokerv1beta1.ActiveMQArtemisAddress{ObjectMeta: metav1.ObjectMeta{Name: "prices-audit", Namespace: "test"}, Spec: single.Spec}
+	address.Spec.QueueName = &current.Spec.Queues[1].Name
+	address.Spec.Queues = nil
+	assert.EqualError(t, address.ValidateCreate(), "queue prices.audit on address prices is already created by Address CR prices")
+	address.Spec = brokerv1beta1.ActiveMQArtemisAddressSpec{AddressName: "prices", RoutingType: &multicast}
+	assert.EqualError(t, address.ValidateCreate(), "address prices is ANYCAST in Address CR prices, not MULTICAST")
+}
@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"context"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	ss "github.com/artemiscloud/activemq-artemis-operator/pkg/resources/statefulsets"
+	mgmt "github.com/artemiscloud/activemq-artemis-operator/pkg/utils/artemis"
+	jc "github.com/artemiscloud/activemq-artemis-operator/pkg/utils/jolokia_client"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// appliesToBroker is how the resources with applyToCrNames select brokers, no names, * or an empty
+// name select every broker of the namespace
+func appliesToBroker(applyToCrNames []string, brokerName string) bool {
+	if len(applyToCrNames) == 0 {
+		return true
+	}
+	for _, name := range applyToCrNames {
+		if name == "" || name == "*" || name == brokerName {
+			return true
+		}
+	}
+	return false
+}
+
+// appliesToBrokerCR adds the selector of the Address and Security CRs to appliesToBroker, a broker
+// is selected by its name or its labels. With a selector, no names select no broker by name
+func appliesToBrokerCR(applyToCrNames []string, selector *metav1.LabelSelector, broker *brokerv1beta1.ActiveMQArtemis) bool {
+	if selector == nil {
+		return appliesToBroker(applyToCrNames, broker.Name)
+	}
+	if len(applyToCrNames) > 0 && appliesToBroker(applyToCrNames, broker.Name) {
+		return true
+	}
+	matcher, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		glog.Error(err, "invalid applyToCrSelector, it selects no broker")
+		return false
+	}
+	return matcher.Matches(labels.Set(broker.Labels))
+}
+
+// targetBrokerCrNames are the broker CRs of the namespace the names and the selector select. Without
+// a selector it is nil for every broker, like createTargetCrNamespacedNames, with a selector it lists
+// the selected brokers and is empty, not nil, when there are none
+func targetBrokerCrNames(c client.Client, namespace string, applyToCrNames []string, selector *metav1.LabelSelector) ([]types.NamespacedName, error) {
+	if selector == nil {
+		return createTargetCrNamespacedNames(namespace, applyToCrNames), nil
+	}
+	crs := &brokerv1beta1.ActiveMQArtemisList{}
+	if err := c.List(context.TODO(), crs, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	result := []types.NamespacedName{}
+	for i := range crs.Items {
+		if appliesToBrokerCR(applyToCrNames, selector, &crs.Items[i]) {
+			result = append(result, types.NamespacedName{Namespace: namespace, Name: crs.Items[i].Name})
+		}
+	}
+	return result, nil
+}
+
+// runningBrokerPods returns the management clients of the running pods of the selected brokers by
+// pod name
+func runningBrokerPods(c client.Client, namespace string, applyToCrNames []string, selector *metav1.LabelSelector) (map[string]*mgmt.Artemis, error) {
+	pods := map[string]*mgmt.Artemis{}
+	crs := &brokerv1beta1.ActiveMQArtemisList{}
+	if err := c.List(context.TODO(), crs, client.InNamespace(namespace)); err != nil {
+		return pods, err
+	}
+	for i := range crs.Items {
+		cr := &crs.Items[i]
+		if !appliesToBrokerCR(applyToCrNames, selector, cr) {
+			continue
+		}
+		crName := types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name}
+		for _, info := range jc.GetBrokers(crName, ss.GetDeployedStatefulSetNames(c, []types.NamespacedName{crName}), c) {
+			pods[info.PodName] = info.Artemis
+		}
+	}
+	return pods, nil
+}
@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"testing"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestApplyToCrSelector(t *testing.T) {
+	testScheme := runtime.NewScheme()
+	assert.NoError(t, brokerv1beta1.AddToScheme(testScheme))
+	gold := &brokerv1beta1.ActiveMQArtemis{ObjectMeta: metav1.ObjectMeta{Name: "gold", Namespace: "test", Labels: map[string]string{"tier": "gold"}}}
+	silver := &brokerv1beta1.ActiveMQArtemis{ObjectMeta: metav1.ObjectMeta{Name: "silver", Namespace: "test", Labels: map[string]string{"tier": "silver"}}}
+	elsewhere := &brokerv1beta1.ActiveMQArtemis{ObjectMeta: metav1.ObjectMeta{Name: "elsewhere", Namespace: "other", Labels: map[string]string{"tier": "gold"}}}
+	fakeClient := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(gold, silver, elsewhere).Build()
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "gold"}}
+
+	// without a selector no names are every broker
+	names, err := targetBrokerCrNames(fakeClient, "test", nil, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, names)
+
+	names, err = targetBrokerCrNames(fakeClient, "test", nil, selector)
+	assert.NoError(t, err)
+	assert.Equal(t, []types.NamespacedName{{Namespace: "test", Name: "gold"}}, names)
+
+	// the names add to the brokers of the selector
+	names, err = targetBrokerCrNames(fakeClient, "test", []string{"silver"}, selector)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []types.NamespacedName{{Namespace: "test", Name: "gold"}, {Namespace: "test", Name: "silver"}}, names)
+
+	names, err = targetBrokerCrNames(fakeClient, "test", nil, &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "bronze"}})
+	assert.NoError(t, err)
+	assert.NotNil(t, names)
+	assert.Empty(t, names)
+
+	invalid := &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "tier", Operator: "Near"}}}
+	assert.False(t, appliesToBrokerCR(nil, invalid, gold))
+
+	security := &brokerv1beta1.ActiveMQArtemisSecurity{
+		ObjectMeta: metav1.ObjectMeta{Name: "security", Namespace: "test"},
+		Spec:       brokerv1beta1.ActiveMQArtemisSecuritySpec{ApplyToCrSelector: selector},
+	}
+	handler := &ActiveMQArtemisSecurityConfigHandler{
+		SecurityCR:     security,
+		NamespacedName: types.NamespacedName{Namespace: "test", Name: "security"},
+		owner:          &ActiveMQArtemisSecurityReconciler{Client: fakeClient, Scheme: testScheme},
+	}
+	assert.True(t, handler.IsApplicableFor(types.NamespacedName{Namespace: "test", Name: "gold"}))
+	assert.False(t, handler.IsApplicableFor(types.NamespacedName{Namespace: "test", Name: "silver"}))
+	assert.False(t, handler.IsApplicableFor(types.NamespacedName{Namespace: "other", Name: "elsewhere"}))
+	assert.Error(t, (&brokerv1beta1.ActiveMQArtemisSecurity{Spec: brokerv1beta1.ActiveMQArtemisSecuritySpec{ApplyToCrSelector: invalid}}).ValidateCreate())
+
+	// the reserved prefixes of a broker apply to the addresses that select it by its labels
+	gold.Spec.ReservedAddressPrefixes = &brokerv1beta1.ReservedAddressPrefixesType{Prefixes: []string{"sys."}}
+	brokerv1beta1.SetWebhookClient(fake.NewClientBuilder().WithScheme(testScheme).WithObjects(gold, silver).Build())
+	defer brokerv1beta1.SetWebhookClient(nil)
+	address := &brokerv1beta1.ActiveMQArtemisAddress{
+		ObjectMeta: metav1.ObjectMeta{Name: "address", Namespace: "test"},
+		Spec:       brokerv1beta1.ActiveMQArtemisAddressSpec{AddressName: "sys.audit", ApplyToCrSelector: selector},
+	}
+	assert.Error(t, address.ValidateCreate())
+	address.Spec.ApplyToCrSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "silver"}}
+	assert.NoError(t, address.ValidateCreate())
+	address.Spec.ApplyToCrSelector = invalid
+	assert.Error(t, address.ValidateCreate())
+}
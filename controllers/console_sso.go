@@ -0,0 +1,150 @@
+package controllers
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/resources/secrets"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/random"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	defaultConsoleSSOProxyImage  = "quay.io/oauth2-proxy/oauth2-proxy:v7.4.0"
+	consoleSSOProxyPort          = 4180
+	consoleSSOProxyPortName      = "wconsj"
+	consoleSSOCookieSecretSuffix = "-console-sso-cookie"
+	consoleSSOCookieSecretKey    = "cookieSecret"
+)
+
+var defaultConsoleSSOScopes = []string{"openid", "profile", "email"}
+
+func consoleSSOEnabled(customResource *brokerv1beta1.ActiveMQArtemis) bool {
+	return !customResource.Spec.Console.Disabled && customResource.Spec.Console.SSO != nil
+}
+
+// the console only listens inside the pod when users sign in with the provider, the console services
+// and the operator reach it through the proxy
+func consoleServiceTargetPort(customResource *brokerv1beta1.ActiveMQArtemis) int32 {
+	if consoleSSOEnabled(customResource) {
+		return consoleSSOProxyPort
+	}
+	return getConsolePort(customResource)
+}
+
+func consoleSSOCookieSecretName(customResource *brokerv1beta1.ActiveMQArtemis) string {
+	return customResource.Name + consoleSSOCookieSecretSuffix
+}
+
+// oauth2-proxy signs its session cookies with this value, it is generated once and kept so that
+// sessions survive a rolling restart
+func (reconciler *ActiveMQArtemisReconcilerImpl) applyConsoleSSOCookieSecret(customResource *brokerv1beta1.ActiveMQArtemis, namer Namers) {
+	secretName := consoleSSOCookieSecretName(customResource)
+	cookieSecret := ""
+	if obj := reconciler.cloneOfDeployed(reflect.TypeOf(corev1.Secret{}), secretName); obj != nil {
+		cookieSecret = string(obj.(*corev1.Secret).Data[consoleSSOCookieSecretKey])
+	}
+	if cookieSecret == "" {
+		cookieSecret = random.GenerateRandomString(32)
+	}
+	namespacedName := types.NamespacedName{Name: customResource.Name, Namespace: customResource.Namespace}
+	reconciler.trackDesired(secrets.NewSecret(namespacedName, secretName, map[string]string{consoleSSOCookieSecretKey: cookieSecret}, namer.LabelBuilder.Labels()))
+}
+
+// the console is bound to the loopback address so that the proxy is the only way in
+func consoleSSOBindHost(customResource *brokerv1beta1.ActiveMQArtemis) string {
+	if isIPv6Primary(customResource) {
+		return "[::1]"
+	}
+	return "127.0.0.1"
+}
+
+func consoleSSOUpstream(customResource *brokerv1beta1.ActiveMQArtemis) string {
+	scheme := "http"
+	if customResource.Spec.Console.SSLEnabled {
+		scheme = "https"
+	}
+	return scheme + "://" + consoleSSOBindHost(customResource) + ":" + strconv.Itoa(int(getConsolePort(customResource))) + "/"
+}
+
+func makeConsoleSSOProxyContainer(customResource *brokerv1beta1.ActiveMQArtemis) *corev1.Container {
+	if !consoleSSOEnabled(customResource) {
+		return nil
+	}
+	console := customResource.Spec.Console
+	sso := console.SSO
+
+	image := sso.ProxyImage
+	if image == "" {
+		image = defaultConsoleSSOProxyImage
+	}
+	scopes := sso.Scopes
+	if len(scopes) == 0 {
+		scopes = defaultConsoleSSOScopes
+	}
+	httpAddress := "0.0.0.0:" + strconv.Itoa(consoleSSOProxyPort)
+	if isIPv6Primary(customResource) {
+		httpAddress = "[::]:" + strconv.Itoa(consoleSSOProxyPort)
+	}
+
+	args := []string{
+		"--provider=oidc",
+		"--oidc-issuer-url=" + sso.IssuerURL,
+		"--client-id=" + sso.ClientID,
+		"--scope=" + strings.Join(scopes, " "),
+		"--email-domain=*",
+		"--http-address=" + httpAddress,
+		"--upstream=" + consoleSSOUpstream(customResource),
+		"--reverse-proxy=true",
+		"--skip-provider-button=true",
+		// jolokia and the metrics are called by the operator and the scrapers, the broker checks
+		// their credentials as it did before
+		"--skip-auth-route=^/console/jolokia",
+		"--skip-auth-route=^/metrics",
+	}
+	for _, group := range sso.AllowedGroups {
+		args = append(args, "--allowed-group="+group)
+	}
+	if console.SSLEnabled {
+		// the upstream is the loopback address, the certificate is issued for the exposed hosts
+		args = append(args, "--ssl-upstream-insecure-skip-verify=true")
+	}
+
+	clientSecret := sso.ClientSecret
+	return &corev1.Container{
+		Name:            customResource.Name + "-console-sso",
+		Image:           image,
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Args:            args,
+		Ports: []corev1.ContainerPort{{
+			Name:          consoleSSOProxyPortName,
+			ContainerPort: consoleSSOProxyPort,
+			Protocol:      corev1.ProtocolTCP,
+		}},
+		Env: []corev1.EnvVar{
+			{
+				Name:      "OAUTH2_PROXY_CLIENT_SECRET",
+				ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &clientSecret},
+			},
+			{
+				Name: "OAUTH2_PROXY_COOKIE_SECRET",
+				ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: consoleSSOCookieSecretName(customResource)},
+					Key:                  consoleSSOCookieSecretKey,
+				}},
+			},
+		},
+		ReadinessProbe: &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				HTTPGet: &corev1.HTTPGetAction{
+					Path: "/ping",
+					Port: intstr.FromInt(consoleSSOProxyPort),
+				},
+			},
+		},
+	}
+}
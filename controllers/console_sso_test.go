@@ -0,0 +1,125 @@
+package controllers
+
+import (
+	"testing"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/resources/environments"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/common"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestConsoleSSO(t *testing.T) {
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: "test"},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			DeploymentPlan: brokerv1beta1.DeploymentPlanType{Size: common.Int32ToPtr(1)},
+			Console: brokerv1beta1.ConsoleType{
+				Expose:     true,
+				SSLEnabled: true,
+				RouteTLS:   &brokerv1beta1.RouteTLSType{Termination: brokerv1beta1.RouteTerminationEdge},
+				SSO: &brokerv1beta1.ConsoleSSOType{
+					IssuerURL: "https://keycloak.example.com/realms/artemis",
+					ClientID:  "artemis-console",
+					ClientSecret: v1.SecretKeySelector{
+						LocalObjectReference: v1.LocalObjectReference{Name: "console-oidc"},
+						Key:                  "clientSecret",
+					},
+					AllowedGroups: []string{"messaging-admins"},
+				},
+			},
+		},
+	}
+	namer := MakeNamers(cr)
+	assert.Nil(t, validateConsole(cr))
+
+	statefulSet := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "broker-ss", Namespace: "test"}}
+	statefulSet.Spec.Template.Spec.Containers = []v1.Container{{Name: "broker-container"}}
+	reconciler := &ActiveMQArtemisReconcilerImpl{}
+	reconciler.ProcessConsole(cr, *namer, fake.NewClientBuilder().Build(), nil, statefulSet)
+
+	containers := statefulSet.Spec.Template.Spec.Containers
+	assert.Len(t, containers, 2)
+	proxy := containers[1]
+	assert.Equal(t, "broker-console-sso", proxy.Name)
+	assert.Equal(t, defaultConsoleSSOProxyImage, proxy.Image)
+	assert.Contains(t, proxy.Args, "--oidc-issuer-url=https://keycloak.example.com/realms/artemis")
+	assert.Contains(t, proxy.Args, "--client-id=artemis-console")
+	assert.Contains(t, proxy.Args, "--scope=openid profile email")
+	assert.Contains(t, proxy.Args, "--allowed-group=messaging-admins")
+	assert.Contains(t, proxy.Args, "--upstream=https://127.0.0.1:8161/")
+	assert.Contains(t, proxy.Args, "--skip-auth-route=^/console/jolokia")
+	assert.Equal(t, "wconsj", proxy.Ports[0].Name)
+	assert.Contains(t, proxy.Args, "--ssl-upstream-insecure-skip-verify=true")
+	assert.Equal(t, "console-oidc", environments.Retrieve([]v1.Container{proxy}, "OAUTH2_PROXY_CLIENT_SECRET").ValueFrom.SecretKeyRef.Name)
+	assert.Equal(t, "broker-console-sso-cookie", environments.Retrieve([]v1.Container{proxy}, "OAUTH2_PROXY_COOKIE_SECRET").ValueFrom.SecretKeyRef.Name)
+	// the console args and credentials of the broker stay out of the proxy
+	assert.Nil(t, environments.Retrieve([]v1.Container{proxy}, "AMQ_CONSOLE_ARGS"))
+	assert.NotNil(t, environments.Retrieve(containers[:1], "AMQ_CONSOLE_ARGS"))
+
+	var service *v1.Service
+	var cookieSecret *v1.Secret
+	for _, obj := range reconciler.requestedResources {
+		switch candidate := obj.(type) {
+		case *v1.Service:
+			service = candidate
+		case *v1.Secret:
+			if candidate.Name == "broker-console-sso-cookie" {
+				cookieSecret = candidate
+			}
+		}
+	}
+	assert.NotNil(t, service)
+	assert.Equal(t, intstr.FromInt(consoleSSOProxyPort), service.Spec.Ports[0].TargetPort)
+	assert.NotNil(t, cookieSecret)
+	assert.Len(t, cookieSecret.StringData[consoleSSOCookieSecretKey], 32)
+
+	// the console only listens inside the pod, the proxy publishes the console port
+	assert.Contains(t, consoleBootstrapCmd(cr), " 127.0.0.1 - false")
+	for _, port := range MakeContainerPorts(cr) {
+		assert.NotEqual(t, "wconsj", port.Name)
+	}
+	assert.NotNil(t, defaultLivenessProbeHandler(cr).Exec)
+	cr.Spec.Console.SSLEnabled = false
+	cr.Spec.IPFamilies = []v1.IPFamily{v1.IPv6Protocol}
+	assert.Equal(t, "http://[::1]:8161/", consoleSSOUpstream(cr))
+	cr.Spec.IPFamilies = nil
+	cr.Spec.Console.BindHost = "0.0.0.0"
+	assert.NotNil(t, validateConsole(cr))
+	cr.Spec.Console.BindHost = ""
+
+	client := fake.NewClientBuilder().Build()
+	condition, retry := validateConsoleSSOResources(cr, client, nil)
+	assert.NotNil(t, condition)
+	assert.True(t, retry)
+	assert.Equal(t, brokerv1beta1.ValidConditionMissingResourcesReason, condition.Reason)
+	client = fake.NewClientBuilder().WithObjects(&v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "console-oidc", Namespace: "test"},
+		Data:       map[string][]byte{"clientSecret": []byte("s3cr3t")},
+	}).Build()
+	condition, _ = validateConsoleSSOResources(cr, client, nil)
+	assert.Nil(t, condition)
+
+	cr.Spec.Console.SSO.IssuerURL = "http://keycloak.example.com/realms/artemis"
+	assert.NotNil(t, validateConsole(cr))
+	cr.Spec.Console.SSO.IssuerURL = "https://keycloak.example.com/realms/artemis"
+	cr.Spec.Console.SSO.ClientSecret.Key = ""
+	assert.NotNil(t, validateConsole(cr))
+	cr.Spec.Console.SSO.ClientSecret.Key = "clientSecret"
+	cr.Spec.Console.SSO.Scopes = []string{"openid email"}
+	assert.NotNil(t, validateConsole(cr))
+	cr.Spec.Console.SSO.Scopes = nil
+	cr.Spec.Console.RouteTLS.Termination = brokerv1beta1.RouteTerminationPassthrough
+	assert.NotNil(t, validateConsole(cr))
+	cr.Spec.Console.RouteTLS = nil
+	assert.Nil(t, validateConsole(cr))
+	cr.Spec.Console.Disabled = true
+	cr.Spec.Console.Expose = false
+	assert.NotNil(t, validateConsole(cr))
+}
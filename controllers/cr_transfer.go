@@ -0,0 +1,174 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// TransferToAnnotation asks the operator to copy an Address or Security CR to the namespace it
+	// names, for a broker that moves there
+	TransferToAnnotation = "broker.amq.io/transfer-to"
+	// TransferApplyToAnnotation replaces the applyToCrNames of the copy, a comma separated list of
+	// the broker CR names in the target namespace
+	TransferApplyToAnnotation = "broker.amq.io/transfer-apply-to"
+	// TransferredToAnnotation marks a CR that has been copied, deleting it leaves its addresses on
+	// the brokers
+	TransferredToAnnotation = "broker.amq.io/transferred-to"
+	// TransferredFromAnnotation is set on the copy, with the namespaced name of the CR it came from
+	TransferredFromAnnotation = "broker.amq.io/transferred-from"
+	// TransferSecretsAnnotation set to true on the CR copies the secrets the operator generated its
+	// passwords in along with it, without it the copy gets new passwords
+	TransferSecretsAnnotation = "broker.amq.io/transfer-secrets"
+	// AcceptTransfersFromAnnotation is set on the target namespace, a comma separated list of the
+	// namespaces it accepts copies from or * for any. A namespace without it accepts none
+	AcceptTransfersFromAnnotation = "broker.amq.io/accept-transfers-from"
+)
+
+// transferRefusedError is returned for a transfer to a namespace that doesn't accept it, the transfer
+// is tried again on the next resync
+type transferRefusedError struct {
+	source string
+	target string
+}
+
+func (e *transferRefusedError) Error() string {
+	return fmt.Sprintf("namespace %v doesn't accept transfers from %v, set the %v annotation on it", e.target, e.source, AcceptTransfersFromAnnotation)
+}
+
+func isTransferRefused(err error) bool {
+	_, refused := err.(*transferRefusedError)
+	return refused
+}
+
+// transferAccepted tells whether the target namespace opted in to the copies of the namespace of the CR
+func transferAccepted(ctx context.Context, c client.Client, source metav1.Object, target string) (bool, error) {
+	namespace := &corev1.Namespace{}
+	if err := c.Get(ctx, types.NamespacedName{Name: target}, namespace); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, accepted := range strings.Split(namespace.Annotations[AcceptTransfersFromAnnotation], ",") {
+		accepted = strings.TrimSpace(accepted)
+		if accepted == "*" || accepted == source.GetNamespace() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// transferTarget returns the namespace a CR asks to be transferred to, a CR that was transferred
+// there already is not transferred again
+func transferTarget(source metav1.Object) (string, bool) {
+	target := source.GetAnnotations()[TransferToAnnotation]
+	if target == "" || target == source.GetNamespace() || source.GetAnnotations()[TransferredToAnnotation] == target {
+		return "", false
+	}
+	return target, true
+}
+
+func isTransferred(source metav1.Object) bool {
+	return source.GetAnnotations()[TransferredToAnnotation] != ""
+}
+
+// transferApplyTo is the applyToCrNames of the copy
+func transferApplyTo(source metav1.Object, applyToCrNames []string) []string {
+	names := source.GetAnnotations()[TransferApplyToAnnotation]
+	if names == "" {
+		return applyToCrNames
+	}
+	applyTo := []string{}
+	for _, name := range strings.Split(names, ",") {
+		applyTo = append(applyTo, strings.TrimSpace(name))
+	}
+	return applyTo
+}
+
+// transferObjectMeta is the metadata of the copy of a CR in the target namespace, the annotations that
+// asked for the transfer are left behind
+func transferObjectMeta(source metav1.Object, target string) metav1.ObjectMeta {
+	annotations := map[string]string{}
+	for key, value := range source.GetAnnotations() {
+		if key != TransferToAnnotation && key != TransferApplyToAnnotation && key != TransferredToAnnotation && key != TransferSecretsAnnotation {
+			annotations[key] = value
+		}
+	}
+	annotations[TransferredFromAnnotation] = source.GetNamespace() + "/" + source.GetName()
+	labels := map[string]string{}
+	for key, value := range source.GetLabels() {
+		labels[key] = value
+	}
+	return metav1.ObjectMeta{
+		Name:        source.GetName(),
+		Namespace:   target,
+		Labels:      labels,
+		Annotations: annotations,
+	}
+}
+
+// transferCR creates the copy of a CR, and of the secrets it keeps its generated passwords in when the
+// CR asks for them, then marks the source as transferred. The target namespace must accept the
+// transfer. A copy that exists already is kept when it came from the source, so a transfer that
+// failed part way can be run again
+func transferCR(ctx context.Context, c client.Client, scheme *runtime.Scheme, source client.Object, copy client.Object, secretNames []string) error {
+	target := copy.GetNamespace()
+	if accepted, err := transferAccepted(ctx, c, source, target); err != nil {
+		return err
+	} else if !accepted {
+		return &transferRefusedError{source: source.GetNamespace(), target: target}
+	}
+
+	if err := c.Create(ctx, copy); err != nil {
+		if !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(copy), copy); err != nil {
+			return err
+		}
+		if from := copy.GetAnnotations()[TransferredFromAnnotation]; from != source.GetNamespace()+"/"+source.GetName() {
+			return fmt.Errorf("%v already exists in namespace %v and was not transferred from %v", copy.GetName(), target, source.GetNamespace())
+		}
+	}
+
+	if source.GetAnnotations()[TransferSecretsAnnotation] != "true" {
+		secretNames = nil
+	}
+	for _, name := range secretNames {
+		secret := &corev1.Secret{}
+		if err := c.Get(ctx, types.NamespacedName{Namespace: source.GetNamespace(), Name: name}, secret); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		transferred := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: target, Labels: secret.Labels},
+			Type:       secret.Type,
+			Data:       secret.Data,
+		}
+		if err := controllerutil.SetControllerReference(copy, transferred, scheme); err != nil {
+			return err
+		}
+		if err := c.Create(ctx, transferred); err != nil && !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+		log.FromContext(ctx).Info("copied secret for transfer", "secret", name, "namespace", target)
+	}
+
+	annotations := source.GetAnnotations()
+	annotations[TransferredToAnnotation] = target
+	source.SetAnnotations(annotations)
+	return c.Update(ctx, source)
+}
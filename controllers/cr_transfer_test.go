@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestCRTransfer(t *testing.T) {
+	testScheme := newTestScheme(t)
+
+	security := &brokerv1beta1.ActiveMQArtemisSecurity{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "security",
+			Namespace:   "old",
+			Annotations: map[string]string{TransferToAnnotation: "new", TransferApplyToAnnotation: "broker, other", TransferSecretsAnnotation: "true"},
+		},
+		Spec: brokerv1beta1.ActiveMQArtemisSecuritySpec{
+			ApplyToCrNames: []string{"broker"},
+			LoginModules: brokerv1beta1.LoginModulesType{
+				PropertiesLoginModules: []brokerv1beta1.PropertiesLoginModuleType{{Name: "prop-module"}},
+			},
+		},
+	}
+	passwords := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "security-properties-prop-module", Namespace: "old"},
+		Data:       map[string][]byte{"user": []byte("generated")},
+	}
+	newNamespace := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "new"}}
+	fakeClient := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(security, passwords, newNamespace).Build()
+
+	target, requested := transferTarget(security)
+	assert.True(t, requested)
+	transferred := &brokerv1beta1.ActiveMQArtemisSecurity{ObjectMeta: transferObjectMeta(security, target), Spec: *security.Spec.DeepCopy()}
+	transferred.Spec.ApplyToCrNames = transferApplyTo(security, security.Spec.ApplyToCrNames)
+
+	// the target namespace has to accept the transfer
+	err := transferCR(context.TODO(), fakeClient, testScheme, security, transferred.DeepCopy(), securityPasswordSecretNames(security))
+	assert.True(t, isTransferRefused(err))
+	assert.Error(t, fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: "new", Name: "security"}, &brokerv1beta1.ActiveMQArtemisSecurity{}))
+
+	newNamespace.Annotations = map[string]string{AcceptTransfersFromAnnotation: "elsewhere, old"}
+	assert.NoError(t, fakeClient.Update(context.TODO(), newNamespace))
+	assert.NoError(t, transferCR(context.TODO(), fakeClient, testScheme, security, transferred, securityPasswordSecretNames(security)))
+
+	// the copy keeps the generated passwords and targets the brokers of the new namespace
+	copied := &brokerv1beta1.ActiveMQArtemisSecurity{}
+	assert.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: "new", Name: "security"}, copied))
+	assert.Equal(t, []string{"broker", "other"}, copied.Spec.ApplyToCrNames)
+	assert.Equal(t, map[string]string{TransferredFromAnnotation: "old/security"}, copied.Annotations)
+	copiedPasswords := &v1.Secret{}
+	assert.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: "new", Name: passwords.Name}, copiedPasswords))
+	assert.Equal(t, passwords.Data, copiedPasswords.Data)
+	assert.Equal(t, "security", copiedPasswords.OwnerReferences[0].Name)
+
+	source := &brokerv1beta1.ActiveMQArtemisSecurity{}
+	assert.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: "old", Name: "security"}, source))
+	assert.True(t, isTransferred(source))
+	_, requested = transferTarget(source)
+	assert.False(t, requested)
+
+	// a transfer is run again after a failure, a CR of the same name that came from elsewhere stops it
+	assert.NoError(t, transferCR(context.TODO(), fakeClient, testScheme, source, &brokerv1beta1.ActiveMQArtemisSecurity{ObjectMeta: transferObjectMeta(source, "new"), Spec: source.Spec}, nil))
+	address := &brokerv1beta1.ActiveMQArtemisAddress{ObjectMeta: metav1.ObjectMeta{Name: "security", Namespace: "other"}}
+	clash := &brokerv1beta1.ActiveMQArtemisAddress{ObjectMeta: metav1.ObjectMeta{Name: "security", Namespace: "new"}}
+	anyNamespace := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "new", Annotations: map[string]string{AcceptTransfersFromAnnotation: "*"}}}
+	fakeClient = fake.NewClientBuilder().WithScheme(testScheme).WithObjects(address, clash, anyNamespace).Build()
+	err = transferCR(context.TODO(), fakeClient, testScheme, address, &brokerv1beta1.ActiveMQArtemisAddress{ObjectMeta: transferObjectMeta(address, "new")}, nil)
+	assert.Error(t, err)
+	assert.False(t, isTransferRefused(err))
+
+	// the secrets are only copied when the CR asks for them
+	delete(security.Annotations, TransferSecretsAnnotation)
+	security.ResourceVersion = ""
+	fakeClient = fake.NewClientBuilder().WithScheme(testScheme).WithObjects(security, passwords, anyNamespace).Build()
+	assert.NoError(t, transferCR(context.TODO(), fakeClient, testScheme, security, &brokerv1beta1.ActiveMQArtemisSecurity{ObjectMeta: transferObjectMeta(security, "new"), Spec: security.Spec}, securityPasswordSecretNames(security)))
+	assert.Error(t, fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: "new", Name: passwords.Name}, &v1.Secret{}))
+}
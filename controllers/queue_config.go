@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"encoding/json"
+	"sort"
 	"strings"
 
 	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
@@ -59,12 +60,8 @@ func GetQueueConfig(addressRes *brokerv1beta1.ActiveMQArtemisAddress) (string, b
 
 	artemisQueueConfig.Name = addressSpec.QueueName
 	artemisQueueConfig.Address = &addressSpec.AddressName
-	if addressSpec.RoutingType == nil {
-		artemisQueueConfig.RoutingType = &defaultRoutingType
-	} else {
-		routingType := strings.ToUpper(*addressSpec.RoutingType)
-		artemisQueueConfig.RoutingType = &routingType
-	}
+	routingType := queueRoutingType(addressRes)
+	artemisQueueConfig.RoutingType = &routingType
 	artemisQueueConfig.FilterString = configSpec.FilterString
 	artemisQueueConfig.Durable = configSpec.Durable
 	artemisQueueConfig.User = configSpec.User
@@ -97,3 +94,73 @@ func GetQueueConfig(addressRes *brokerv1beta1.ActiveMQArtemisAddress) (string, b
 	}
 	return string(bytes), ignoreIfExists, nil
 }
+
+// addressQueues are the queues an address CR creates, each as the CR of a single queue on the address.
+// A queue of the queues list without a routing type takes the one of the address
+func addressQueues(addressRes *brokerv1beta1.ActiveMQArtemisAddress) []*brokerv1beta1.ActiveMQArtemisAddress {
+	if len(addressRes.Spec.Queues) == 0 {
+		if addressRes.Spec.QueueName == nil || *addressRes.Spec.QueueName == "" {
+			return nil
+		}
+		return []*brokerv1beta1.ActiveMQArtemisAddress{addressRes}
+	}
+	queues := []*brokerv1beta1.ActiveMQArtemisAddress{}
+	for i := range addressRes.Spec.Queues {
+		queueName := addressRes.Spec.Queues[i].Name
+		queue := &brokerv1beta1.ActiveMQArtemisAddress{}
+		queue.Spec.AddressName = addressRes.Spec.AddressName
+		queue.Spec.QueueName = &queueName
+		queue.Spec.RemovalPolicy = addressRemovalPolicy(addressRes)
+		queue.Spec.QueueConfiguration = addressRes.Spec.Queues[i].QueueConfigurationType.DeepCopy()
+		if config := queue.Spec.QueueConfiguration; config.RoutingType == nil || *config.RoutingType == "" {
+			routingType := defaultRoutingType
+			if addressRes.Spec.RoutingType != nil && *addressRes.Spec.RoutingType != "" {
+				routingType = strings.ToUpper(*addressRes.Spec.RoutingType)
+			}
+			config.RoutingType = &routingType
+		}
+		queues = append(queues, queue)
+	}
+	return queues
+}
+
+// addressRoutingTypes are the routing types an address takes for its queues, comma separated as the
+// management api wants them
+func addressRoutingTypes(addressRes *brokerv1beta1.ActiveMQArtemisAddress) string {
+	if len(addressRes.Spec.Queues) == 0 {
+		return queueRoutingType(addressRes)
+	}
+	taken := map[string]bool{}
+	for _, queue := range addressQueues(addressRes) {
+		taken[queueRoutingType(queue)] = true
+	}
+	routingTypes := []string{}
+	for routingType := range taken {
+		routingTypes = append(routingTypes, routingType)
+	}
+	sort.Strings(routingTypes)
+	return strings.Join(routingTypes, ",")
+}
+
+// the routing type of the address wins over the one of the queue configuration
+func queueRoutingType(addressRes *brokerv1beta1.ActiveMQArtemisAddress) string {
+	if addressRes.Spec.RoutingType != nil && *addressRes.Spec.RoutingType != "" {
+		return strings.ToUpper(*addressRes.Spec.RoutingType)
+	}
+	if config := addressRes.Spec.QueueConfiguration; config != nil && config.RoutingType != nil && *config.RoutingType != "" {
+		return strings.ToUpper(*config.RoutingType)
+	}
+	return defaultRoutingType
+}
+
+// getQueueUpdateConfig is the queue config for updating an existing queue, the broker keeps the filter
+// of a queue when the update has none so a filter that was dropped from the CR is cleared
+func getQueueUpdateConfig(addressRes *brokerv1beta1.ActiveMQArtemisAddress) (string, error) {
+	updated := addressRes.DeepCopy()
+	if updated.Spec.QueueConfiguration.FilterString == nil {
+		noFilter := ""
+		updated.Spec.QueueConfiguration.FilterString = &noFilter
+	}
+	queueCfg, _, err := GetQueueConfig(updated)
+	return queueCfg, err
+}
@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"testing"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueueConfiguration(t *testing.T) {
+	queueName := "orders"
+	anycast := "anycast"
+	filter := "priority > 4"
+	maxConsumers := int32(1)
+	groupBuckets := int32(16)
+	ringSize := int64(100)
+	delay := int64(5000)
+	lastValueKey := "orderId"
+	enabled := true
+	address := &brokerv1beta1.ActiveMQArtemisAddress{
+		Spec: brokerv1beta1.ActiveMQArtemisAddressSpec{
+			AddressName: "orders",
+			QueueName:   &queueName,
+			QueueConfiguration: &brokerv1beta1.QueueConfigurationType{
+				RoutingType:         &anycast,
+				FilterString:        &filter,
+				MaxConsumers:        &maxConsumers,
+				PurgeOnNoConsumers:  &enabled,
+				Exclusive:           &enabled,
+				LastValueKey:        &lastValueKey,
+				RingSize:            &ringSize,
+				GroupBuckets:        &groupBuckets,
+				DelayBeforeDispatch: &delay,
+				NonDestructive:      &enabled,
+			},
+		},
+	}
+	assert.NoError(t, address.Spec.QueueConfiguration.Validate("queueConfiguration"))
+
+	// the routing type of the queue configuration is used when the address has none
+	queueCfg, ignoreIfExists, err := GetQueueConfig(address)
+	assert.NoError(t, err)
+	assert.False(t, ignoreIfExists)
+	assert.JSONEq(t, `{"name":"orders","address":"orders","routing-type":"ANYCAST","filter-string":"priority > 4",
+		"max-consumers":1,"purge-on-no-consumers":true,"exclusive":true,"last-value-key":"orderId","ring-size":100,
+		"group-buckets":16,"delay-before-dispatch":5000,"non-destructive":true}`, queueCfg)
+
+	// an update clears a filter that was dropped
+	address.Spec.QueueConfiguration.FilterString = nil
+	queueCfg, err = getQueueUpdateConfig(address)
+	assert.NoError(t, err)
+	assert.Contains(t, queueCfg, `"filter-string":""`)
+	assert.Nil(t, address.Spec.QueueConfiguration.FilterString)
+
+	multicast := "multicast"
+	address.Spec.RoutingType = &multicast
+	assert.Equal(t, "MULTICAST", queueRoutingType(address))
+	address.Spec.RoutingType = nil
+	address.Spec.QueueConfiguration = nil
+	assert.Equal(t, "MULTICAST", queueRoutingType(address))
+
+	belowLimit := int32(-2)
+	negative := int64(-1)
+	notLastValue := false
+	broadcast := "broadcast"
+	for _, invalid := range []brokerv1beta1.QueueConfigurationType{
+		{RoutingType: &broadcast},
+		{MaxConsumers: &belowLimit},
+		{ConsumersBeforeDispatch: &maxConsumers, AutoDeleteDelay: &negative},
+		{LastValueKey: &lastValueKey, LastValue: &notLastValue},
+	} {
+		assert.Error(t, invalid.Validate("queueConfiguration"), invalid)
+	}
+}
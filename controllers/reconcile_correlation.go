@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"context"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// ReconcileIDAnnotation is set on the events the operator records, with the reconcileID of the
+// reconcile that recorded them, so an event can be found in the logs
+const ReconcileIDAnnotation = "broker.amq.io/reconcile-id"
+
+type reconcileIDKey struct{}
+
+// withCorrelation gives every reconcile an id and a logger with the controller and the id in the
+// context, next to the name and namespace controller-runtime adds
+func withCorrelation(controllerName string, reconciler reconcile.Reconciler) reconcile.Reconciler {
+	return reconcile.Func(func(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+		id := string(uuid.NewUUID())
+		logger := log.FromContext(ctx).WithValues("controller", controllerName, "reconcileID", id)
+		ctx = context.WithValue(log.IntoContext(ctx, logger), reconcileIDKey{}, id)
+		return reconciler.Reconcile(ctx, request)
+	})
+}
+
+// ReconcileID is the id of the reconcile the context belongs to, empty outside of a reconcile
+func ReconcileID(ctx context.Context) string {
+	if id, ok := ctx.Value(reconcileIDKey{}).(string); ok {
+		return id
+	}
+	return ""
+}
+
+// reconcileCorrelation holds the values of the reconcile in progress of a controller that reconciles
+// one request at a time, for the package logger its helpers log through without a context
+type reconcileCorrelation struct {
+	mu     sync.RWMutex
+	values []interface{}
+}
+
+// begin takes the controller and the id the context of the reconcile carries, the returned func ends
+// the reconcile
+func (c *reconcileCorrelation) begin(ctx context.Context, controllerName string, request reconcile.Request) func() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values = []interface{}{"controller", controllerName, "reconcileID", ReconcileID(ctx), "name", request.Name, "namespace", request.Namespace}
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.values = nil
+	}
+}
+
+func (c *reconcileCorrelation) current() []interface{} {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.values
+}
+
+// correlatedLogger adds the values of the reconcile in progress to every line of the logger
+func correlatedLogger(logger logr.Logger, correlation *reconcileCorrelation) logr.Logger {
+	return logr.New(correlatedSink{sink: logger.GetSink(), correlation: correlation})
+}
+
+type correlatedSink struct {
+	sink        logr.LogSink
+	correlation *reconcileCorrelation
+}
+
+func (s correlatedSink) Init(info logr.RuntimeInfo) {
+	s.sink.Init(logr.RuntimeInfo{CallDepth: info.CallDepth + 1})
+}
+
+func (s correlatedSink) Enabled(level int) bool {
+	return s.sink.Enabled(level)
+}
+
+func (s correlatedSink) Info(level int, msg string, keysAndValues ...interface{}) {
+	s.sink.Info(level, msg, append(s.correlation.current(), keysAndValues...)...)
+}
+
+func (s correlatedSink) Error(err error, msg string, keysAndValues ...interface{}) {
+	s.sink.Error(err, msg, append(s.correlation.current(), keysAndValues...)...)
+}
+
+func (s correlatedSink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	return correlatedSink{sink: s.sink.WithValues(keysAndValues...), correlation: s.correlation}
+}
+
+func (s correlatedSink) WithName(name string) logr.LogSink {
+	return correlatedSink{sink: s.sink.WithName(name), correlation: s.correlation}
+}
+
+// podLogger is the logger for the work a reconcile does against a single broker pod
+func podLogger(ctx context.Context, pod string) logr.Logger {
+	return log.FromContext(ctx).WithValues("pod", pod)
+}
+
+// recordEvent records an event annotated with the reconcileID of the context
+func recordEvent(ctx context.Context, recorder record.EventRecorder, object runtime.Object, eventType string, reason string, message string) {
+	if recorder == nil {
+		return
+	}
+	annotations := map[string]string{}
+	if id := ReconcileID(ctx); id != "" {
+		annotations[ReconcileIDAnnotation] = id
+	}
+	recorder.AnnotatedEventf(object, annotations, eventType, reason, "%s", message)
+}
@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/go-logr/logr/funcr"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+)
+
+type annotatedEventRecorder struct {
+	record.FakeRecorder
+	annotations []map[string]string
+}
+
+func (r *annotatedEventRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.annotations = append(r.annotations, annotations)
+}
+
+func TestReconcileCorrelation(t *testing.T) {
+	recorder := &annotatedEventRecorder{}
+	cr := &brokerv1beta1.ActiveMQArtemis{ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: "correlation"}}
+
+	lines := []string{}
+	correlation := &reconcileCorrelation{}
+	logger := correlatedLogger(funcr.New(func(prefix, args string) { lines = append(lines, args) }, funcr.Options{}), correlation)
+
+	ids := []string{}
+	reconciler := withCorrelation("activemqartemis", reconcile.Func(func(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+		defer correlation.begin(ctx, "activemqartemis", request)()
+		ids = append(ids, ReconcileID(ctx))
+		logger.WithName("helper").Info("without a context", "step", "validate")
+		recordEvent(ctx, recorder, cr, v1.EventTypeWarning, brokerv1beta1.ValidConditionMissingResourcesReason, "invalid")
+		return reconcile.Result{}, nil
+	}))
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace}}
+	for i := 0; i < 2; i++ {
+		_, err := reconciler.Reconcile(context.TODO(), request)
+		assert.NoError(t, err)
+	}
+
+	// each reconcile has its own id, and its events carry it
+	assert.Len(t, ids, 2)
+	assert.NotEmpty(t, ids[0])
+	assert.NotEqual(t, ids[0], ids[1])
+	assert.Equal(t, []map[string]string{{ReconcileIDAnnotation: ids[0]}, {ReconcileIDAnnotation: ids[1]}}, recorder.annotations)
+
+	// the package logger carries the id of the reconcile in progress, and nothing in between
+	assert.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `"reconcileID"="`+ids[0]+`"`)
+	assert.Contains(t, lines[0], `"controller"="activemqartemis"`)
+	assert.Contains(t, lines[0], `"name"="broker"`)
+	assert.Contains(t, lines[0], `"step"="validate"`)
+	assert.Contains(t, lines[1], `"reconcileID"="`+ids[1]+`"`)
+	logger.Info("outside of a reconcile")
+	assert.NotContains(t, lines[2], "reconcileID")
+
+	assert.Empty(t, ReconcileID(context.TODO()))
+	recordEvent(context.TODO(), nil, cr, v1.EventTypeNormal, "Ignored", "no recorder")
+}
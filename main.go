@@ -25,6 +25,7 @@ import (
 
 	"github.com/artemiscloud/activemq-artemis-operator/version"
 	"github.com/go-logr/logr"
+	"go.uber.org/zap/zapcore"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -50,6 +51,8 @@ import (
 
 	"github.com/artemiscloud/activemq-artemis-operator/pkg/sdkk8sutil"
 	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/common"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/configdiff"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/scaffold"
 
 	brokerv1alpha1 "github.com/artemiscloud/activemq-artemis-operator/api/v1alpha1"
 	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
@@ -97,6 +100,25 @@ func init() {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == scaffold.Command {
+		if err := scaffold.Run(os.Args[2:], os.Stdin, os.Stdout, controllers.ValidatePolicies); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	if len(os.Args) > 1 && os.Args[1] == configdiff.Command {
+		changed, err := configdiff.Run(os.Args[2:], os.Stdin, os.Stdout, controllers.AppliedBrokerConfiguration, controllers.PendingBrokerConfiguration)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		if changed {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
@@ -106,8 +128,10 @@ func main() {
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	// the deployed operator logs json with --zap-encoder=json, the timestamps stay readable in both
 	opts := zap.Options{
 		Development: true,
+		TimeEncoder: zapcore.ISO8601TimeEncoder,
 	}
 	opts.BindFlags(flag.CommandLine)
 
@@ -201,8 +225,9 @@ func main() {
 	}
 
 	brokerReconciler := &controllers.ActiveMQArtemisReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("activemq-artemis-operator"),
 	}
 	if err = brokerReconciler.SetupWithManager(mgr); err != nil {
 		log.Error(err, "unable to create controller", "controller", "ActiveMQArtemis")
@@ -232,6 +257,52 @@ func main() {
 		log.Error(err, "unable to create controller", "controller", "ActiveMQArtemisSecurity")
 		os.Exit(1)
 	}
+	if err = (&controllers.ActiveMQArtemisFleetReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		log.Error(err, "unable to create controller", "controller", "ActiveMQArtemisFleet")
+		os.Exit(1)
+	}
+	if err = (&controllers.ActiveMQArtemisQueueMigrationReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		log.Error(err, "unable to create controller", "controller", "ActiveMQArtemisQueueMigration")
+		os.Exit(1)
+	}
+	if err = (&controllers.ActiveMQArtemisAddressSettingsReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("activemq-artemis-operator"),
+	}).SetupWithManager(mgr); err != nil {
+		log.Error(err, "unable to create controller", "controller", "ActiveMQArtemisAddressSettings")
+		os.Exit(1)
+	}
+	if err = (&controllers.ActiveMQArtemisDivertReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("activemq-artemis-operator"),
+	}).SetupWithManager(mgr); err != nil {
+		log.Error(err, "unable to create controller", "controller", "ActiveMQArtemisDivert")
+		os.Exit(1)
+	}
+	if err = (&controllers.ActiveMQArtemisBridgeReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("activemq-artemis-operator"),
+	}).SetupWithManager(mgr); err != nil {
+		log.Error(err, "unable to create controller", "controller", "ActiveMQArtemisBridge")
+		os.Exit(1)
+	}
+	if err = (&controllers.ActiveMQArtemisAddressSetReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("activemq-artemis-operator"),
+	}).SetupWithManager(mgr); err != nil {
+		log.Error(err, "unable to create controller", "controller", "ActiveMQArtemisAddressSet")
+		os.Exit(1)
+	}
 
 	enableWebhooks := os.Getenv("ENABLE_WEBHOOKS")
 	if enableWebhooks != "false" {
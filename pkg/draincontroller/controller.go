@@ -22,6 +22,8 @@ import (
 
 	//	"github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
 	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/envelope"
+	"github.com/go-logr/logr"
 
 	//"github.com/artemiscloud/activemq-artemis-operator/pkg/client/clientset/versioned/typed/broker/v1beta1"
 	"os"
@@ -33,6 +35,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/apimachinery/pkg/util/wait"
 	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
@@ -66,6 +69,9 @@ const controllerAgentName = "statefulset-drain-controller"
 const AnnotationStatefulSet = "statefulsets.kubernetes.io/drainer-pod-owner" // TODO: can we replace this with an OwnerReference with the StatefulSet as the owner?
 const AnnotationDrainerPodTemplate = "statefulsets.kubernetes.io/drainer-pod-template"
 
+// the scaledown annotation that tells whether the odd ordinals are the backups of replicated pairs
+const AnnotationHAReplication = "HA_REPLICATION"
+
 const LabelDrainPod = "drain-pod"
 const DrainServiceAccountName = "drain-pod-service-account"
 const DrainRoleName = "drain-pod-role"
@@ -120,6 +126,12 @@ type Controller struct {
 	stopCh chan struct{}
 
 	client client.Client
+
+	// readProgress returns the messages left in a drainer and their size
+	readProgress func(pod *corev1.Pod) (int64, int64, error)
+
+	// drain pod uid --> size of the messages when the progress was first read
+	drainBaselines map[string]int64
 }
 
 func eventLog(format string, args ...interface{}) {
@@ -170,9 +182,11 @@ func NewController(
 		ssNamesMap:         make(map[types.NamespacedName]map[string]string),
 		ssToCrMap:          make(map[types.NamespacedName]*brokerv1beta1.ActiveMQArtemisScaledown),
 
-		ssLabels: instance.Labels,
-		stopCh:   make(chan struct{}),
-		client:   client,
+		ssLabels:       instance.Labels,
+		stopCh:         make(chan struct{}),
+		client:         client,
+		readProgress:   readDrainerProgress,
+		drainBaselines: make(map[string]int64),
 	}
 
 	dlog.Info("Setting up event handlers")
@@ -294,15 +308,18 @@ func (c *Controller) processNextWorkItem() bool {
 		}
 
 		// Run the syncHandler, passing it the namespace/name string of the
-		// Foo resource to be synced.
-		dlog.Info("calling syncHandler to process this one", "key", key)
-		if err := c.syncHandler(key); err != nil {
+		// Foo resource to be synced. Its logger carries an id for the sync,
+		// like the reconciles of the controllers
+		namespace, name, _ := cache.SplitMetaNamespaceKey(key)
+		log := dlog.WithValues("controller", controllerAgentName, "reconcileID", string(uuid.NewUUID()), "namespace", namespace, "name", name)
+		log.Info("calling syncHandler to process this one")
+		if err := c.syncHandler(log, key); err != nil {
 			return fmt.Errorf("error syncing '" + key + ": " + err.Error())
 		}
 		// Finally, if no error occurs we Forget this item so it does not
 		// get queued again until another change happens.
 		c.workqueue.Forget(obj)
-		dlog.V(4).Info("Successfully processed '" + key + "'")
+		log.V(4).Info("Successfully processed '" + key + "'")
 		return nil
 	}(obj)
 
@@ -317,10 +334,10 @@ func (c *Controller) processNextWorkItem() bool {
 // syncHandler compares the actual state with the desired, and attempts to
 // converge the two. It then updates the Status block of the StatefulSet resource
 // with the current status of the resource.
-func (c *Controller) syncHandler(key string) error {
+func (c *Controller) syncHandler(log logr.Logger, key string) error {
 
-	dlog.Info("--------------------------------------------------------------------")
-	dlog.Info("SyncHandler invoked for " + key)
+	log.Info("--------------------------------------------------------------------")
+	log.Info("SyncHandler invoked for " + key)
 
 	// Convert the namespace/name string into a distinct namespace and name
 	namespace, name, err := cache.SplitMetaNamespaceKey(key)
@@ -333,7 +350,7 @@ func (c *Controller) syncHandler(key string) error {
 	// Get the StatefulSet resource with this namespace/name
 	sts, err := c.statefulSetLister.StatefulSets(namespace).Get(name)
 
-	dlog.Info("got sts from lister", "namespace", namespace, "name", name, "error?", err)
+	log.Info("got sts from lister", "namespace", namespace, "name", name, "error?", err)
 	if err != nil {
 		// The StatefulSet may no longer exist, in which case we stop
 		// processing.
@@ -345,37 +362,37 @@ func (c *Controller) syncHandler(key string) error {
 		return err
 	}
 
-	return c.processStatefulSet(sts)
+	return c.processStatefulSet(log, sts)
 }
 
-func (c *Controller) processStatefulSet(sts *appsv1.StatefulSet) error {
+func (c *Controller) processStatefulSet(log logr.Logger, sts *appsv1.StatefulSet) error {
 	// TODO: think about scale-down during a rolling upgrade
-	dlog.Info("Processing statefulset", "sts", sts.Name)
+	log.Info("Processing statefulset", "sts", sts.Name)
 
 	if *sts.Spec.Replicas == 0 {
 		// Ensure data is not touched in the case of complete scaledown
-		dlog.Info("Ignoring StatefulSet " + sts.Name + " because replicas set to 0.")
+		log.Info("Ignoring StatefulSet " + sts.Name + " because replicas set to 0.")
 		return nil
 	}
 
-	dlog.Info("Statefulset " + sts.Name + " Spec.Replicas set to " + strconv.Itoa(int(*sts.Spec.Replicas)))
+	log.Info("Statefulset " + sts.Name + " Spec.Replicas set to " + strconv.Itoa(int(*sts.Spec.Replicas)))
 
 	if len(sts.Spec.VolumeClaimTemplates) == 0 {
 		// nothing to do, as the stateful pods don't use any PVCs
-		dlog.V(1).Info("Ignoring StatefulSet " + sts.Name + " because it does not use any PersistentVolumeClaims.")
+		log.V(1).Info("Ignoring StatefulSet " + sts.Name + " because it does not use any PersistentVolumeClaims.")
 		return nil
 	}
-	dlog.Info("Statefulset " + sts.Name + " Spec.VolumeClaimTemplates is " + strconv.Itoa((len(sts.Spec.VolumeClaimTemplates))))
+	log.Info("Statefulset " + sts.Name + " Spec.VolumeClaimTemplates is " + strconv.Itoa((len(sts.Spec.VolumeClaimTemplates))))
 
 	//if sts.Annotations[AnnotationDrainerPodTemplate] == "" {
 	//	log.Info("Ignoring StatefulSet '%s' because it does not define a drain pod template.", sts.Name)
 	//	return nil
 	//}
 
-	claimsGroupedByOrdinal, err := c.getClaims(sts)
+	claimsGroupedByOrdinal, err := c.getClaims(log, sts)
 	if err != nil {
 		err = fmt.Errorf("error while getting list of PVCs in namespace %s: %s", sts.Namespace, err)
-		dlog.Error(err, "Error while getting list of PVCs in namespace "+sts.Namespace)
+		log.Error(err, "Error while getting list of PVCs in namespace "+sts.Namespace)
 		return err
 	}
 
@@ -385,32 +402,32 @@ func (c *Controller) processStatefulSet(sts *appsv1.StatefulSet) error {
 	}
 	sort.Sort(sort.Reverse(sort.IntSlice(ordinals)))
 
-	dlog.Info("Looking through all the pods...")
+	log.Info("Looking through all the pods...")
 	for _, ordinal := range ordinals {
 
-		dlog.Info("looking ordinal", "ordinal", ordinal)
+		log.Info("looking ordinal", "ordinal", ordinal)
 		if ordinal == 0 {
 			// This assumes order on scale up and down is enforced, i.e. the system waits for n, n-1,... 2, 1 to scaledown before attempting 0
-			dlog.Info("Ignoring ordinal 0 as no other pod to drain to.")
+			log.Info("Ignoring ordinal 0 as no other pod to drain to.")
 			continue
 		}
 
 		// TODO check if the number of claims matches the number of StatefulSet's volumeClaimTemplates. What if it doesn't?
 
 		podName := getPodName(sts, ordinal)
-		dlog.Info("got pod name", "name", podName)
+		log.Info("got pod name", "name", podName)
 
 		pod, err := c.podLister.Pods(sts.Namespace).Get(podName)
 
 		if err != nil && !errors.IsNotFound(err) {
-			dlog.Error(err, "Error while getting Pod "+podName)
+			log.Error(err, "Error while getting Pod "+podName)
 			return err
 		}
 
 		// Is it a drain pod or a regular stateful pod?
 		if isDrainPod(pod) {
-			dlog.Info("This is a drain pod", "pod name", podName)
-			err = c.cleanUpDrainPodIfNeeded(sts, pod, ordinal)
+			log.Info("This is a drain pod", "pod name", podName)
+			err = c.cleanUpDrainPodIfNeeded(log, sts, pod, ordinal)
 			if err != nil {
 				return err
 			}
@@ -418,33 +435,45 @@ func (c *Controller) processStatefulSet(sts *appsv1.StatefulSet) error {
 			if sts.Spec.PodManagementPolicy == appsv1.OrderedReadyPodManagement {
 				// don't create additional drain pods; they will be created in one of the
 				// next invocations of this method, when the current drain pod finishes
-				dlog.Info("sts has orderReadyPodManagement policy, break")
+				log.Info("sts has orderReadyPodManagement policy, break")
 				break
 			}
 		}
 
 		// TODO: scale down to zero? should what happens on such events be configurable? there may or may not be anywhere to drain to
 		if int32(ordinal) >= *sts.Spec.Replicas {
-			dlog.Info("ordinal is greater then replicas", "ordinal", ordinal, "replicas", *sts.Spec.Replicas)
+			log.Info("ordinal is greater then replicas", "ordinal", ordinal, "replicas", *sts.Spec.Replicas)
 			// PVC exists, but its ordinal is higher than the current last stateful pod's ordinal;
 			// this means the PVC is an orphan and should be drained & deleted
 
 			// If the Pod doesn't exist, we'll create it
+			if pod == nil && c.isReplicatedBackup(sts, ordinal) {
+				// the primary of the pair holds the same messages and is drained on its own
+				log.Info("Found orphaned PVC(s) for backup ordinal " + strconv.Itoa(ordinal) + " of a replicated pair. Deleting them without draining")
+				if err := c.deleteClaims(log, sts, ordinal); err != nil {
+					return err
+				}
+				c.drainSkipped(log, sts, podName)
+				continue
+			}
+
 			if pod == nil { // TODO: what if the PVC doesn't exist here (or what if it's deleted just after we create the pod)
-				dlog.Info("Found orphaned PVC(s) for ordinal " + strconv.Itoa(ordinal) + ". Creating drain pod " + podName)
+				log.Info("Found orphaned PVC(s) for ordinal " + strconv.Itoa(ordinal) + ". Creating drain pod " + podName)
 
 				// Check to ensure we have a pod to drain to
 				ordinalZeroPodName := getPodName(sts, 0)
 				ordinalZeroPod, err := c.podLister.Pods(sts.Namespace).Get(ordinalZeroPodName)
 				if err != nil {
-					dlog.Error(err, "Error while getting ordinal zero pod "+podName+": "+err.Error())
+					log.Error(err, "Error while getting ordinal zero pod "+podName+": "+err.Error())
+					c.drainWaiting(log, sts, podName, ordinalZeroPodName, "waiting for target pod "+ordinalZeroPodName+" to exist")
 					return err
 				}
 
 				// Ensure that at least the ordinal zero pod is running
 				if corev1.PodRunning != ordinalZeroPod.Status.Phase {
 					//log.Info("Ordinal zero pod '%s' status phase '%s', waiting for it to be Running.", sts.Name, pod.Status.Phase)
-					dlog.Info("Ordinal zero pod " + sts.Name + " status phase not PodRunning, waiting for it to be Running.")
+					log.Info("Ordinal zero pod " + sts.Name + " status phase not PodRunning, waiting for it to be Running.")
+					c.drainWaiting(log, sts, podName, ordinalZeroPodName, "waiting for target pod "+ordinalZeroPodName+" to be running")
 					continue
 				}
 
@@ -456,26 +485,27 @@ func (c *Controller) processStatefulSet(sts *appsv1.StatefulSet) error {
 					//log.V(5).Info("Ordinal zero pod condition %s", podCondition)
 					if corev1.PodReady == podCondition.Type {
 						if corev1.ConditionTrue != podCondition.Status {
-							dlog.Info("Ordinal zero pod " + sts.Name + " podCondition Ready not True, waiting for it to True.")
+							log.Info("Ordinal zero pod " + sts.Name + " podCondition Ready not True, waiting for it to True.")
 						}
 						if corev1.ConditionTrue == podCondition.Status {
-							dlog.Info("Ordinal zero pod " + sts.Name + " podCondition Ready True, proceeding to create drainer pod.")
+							log.Info("Ordinal zero pod " + sts.Name + " podCondition Ready True, proceeding to create drainer pod.")
 							ordinalZeroPodReady = true
 						}
 					}
 				}
 
 				if !ordinalZeroPodReady {
+					c.drainWaiting(log, sts, podName, ordinalZeroPodName, "waiting for target pod "+ordinalZeroPodName+" to be ready")
 					continue
 				}
 
-				dlog.Info("Creating new drain pod...", "sts", sts)
-				pod, err := c.newPod(sts, ordinal)
+				log.Info("Creating new drain pod...", "sts", sts)
+				pod, err := c.newPod(log, sts, ordinal)
 				if err != nil {
-					dlog.Error(err, "error creating drain pod")
+					log.Error(err, "error creating drain pod")
 					return fmt.Errorf("can't create drain Pod object: %s", err)
 				}
-				dlog.Info("Now creating the drain pod in namespace "+sts.Namespace, "pod", pod)
+				log.Info("Now creating the drain pod in namespace "+sts.Namespace, "pod", pod)
 				// needs a proper account for the pod to be created/start.
 				_, err = c.kubeclientset.CoreV1().Pods(sts.Namespace).Create(context.TODO(), pod, metav1.CreateOptions{})
 
@@ -483,9 +513,12 @@ func (c *Controller) processStatefulSet(sts *appsv1.StatefulSet) error {
 				// attempt processing again later. This could have been caused by a
 				// temporary network failure, or any other transient reason.
 				if err != nil {
-					dlog.Error(err, "Error while creating drain Pod "+podName+": ")
+					log.Error(err, "Error while creating drain Pod "+podName+": ")
+					// admission policies reject a drainer the broker cr doesn't adapt, the status tells why nothing drains
+					c.drainWaiting(log, sts, podName, ordinalZeroPodName, "unable to create drainer pod, "+err.Error())
 					return err
 				}
+				c.drainStarted(log, sts, podName, ordinalZeroPodName)
 
 				if !c.localOnly {
 					c.recorder.Event(sts, corev1.EventTypeNormal, SuccessCreate, fmt.Sprintf(MessageDrainPodCreated, podName, sts.Name))
@@ -498,23 +531,22 @@ func (c *Controller) processStatefulSet(sts *appsv1.StatefulSet) error {
 		}
 	}
 
-	// TODO: add status annotation (what info?)
 	return nil
 }
 
-func (c *Controller) getClaims(sts *appsv1.StatefulSet) (claimsGroupedByOrdinal map[int][]*corev1.PersistentVolumeClaim, err error) {
+func (c *Controller) getClaims(log logr.Logger, sts *appsv1.StatefulSet) (claimsGroupedByOrdinal map[int][]*corev1.PersistentVolumeClaim, err error) {
 	// shouldn't use statefulset.Spec.Selector.MatchLabels, as they don't always match; sts controller looks up pvcs by name!
 	allClaims, err := c.pvcLister.PersistentVolumeClaims(sts.Namespace).List(labels.Everything())
 	if err != nil {
 		return nil, err
 	}
-	dlog.V(5).Info("getClaims allClaims", "len", len(allClaims))
+	log.V(5).Info("getClaims allClaims", "len", len(allClaims))
 
 	claimsMap := map[int][]*corev1.PersistentVolumeClaim{}
 	for _, pvc := range allClaims {
-		dlog.V(5).Info("getClaims allClaims pvc name is " + pvc.Name)
+		log.V(5).Info("getClaims allClaims pvc name is " + pvc.Name)
 		if pvc.DeletionTimestamp != nil {
-			dlog.Info("PVC " + pvc.Name + " is being deleted. Ignoring it.")
+			log.Info("PVC " + pvc.Name + " is being deleted. Ignoring it.")
 			continue
 		}
 
@@ -537,8 +569,8 @@ func (c *Controller) getClaims(sts *appsv1.StatefulSet) (claimsGroupedByOrdinal
 }
 
 // create service account, role and role binding for drain pod
-func (c *Controller) createDrainRBACResources(namespace string) {
-	dlog.Info("Creating drain pod rbac resources", "namespace", namespace)
+func (c *Controller) createDrainRBACResources(log logr.Logger, namespace string) {
+	log.Info("Creating drain pod rbac resources", "namespace", namespace)
 	rbacutil.CreateServiceAccount(DrainServiceAccountName, namespace, c.kubeclientset)
 	rules := []rbacv1.PolicyRule{
 		{
@@ -563,50 +595,43 @@ func (c *Controller) createDrainRBACResources(namespace string) {
 }
 
 // delete the service account, role, and role binding for drain pod
-func (c *Controller) cleanupDrainRBACResources(namespace string) {
+func (c *Controller) cleanupDrainRBACResources(log logr.Logger, namespace string) {
 	if !c.localOnly {
-		dlog.Info("Cleaning up drain pod rbac resources", "namespace", namespace)
+		log.Info("Cleaning up drain pod rbac resources", "namespace", namespace)
 		drainRoleBindingName := namespace + "-drain-rb"
 		rbacutil.DeleteRoleBinding(drainRoleBindingName, namespace, c.kubeclientset)
 		rbacutil.DeleteRole(DrainRoleName, namespace, c.kubeclientset)
 		rbacutil.DeleteServiceAccount(DrainServiceAccountName, namespace, c.kubeclientset)
 
-		dlog.Info("Drain service account cleaned up", "namespace", namespace)
+		log.Info("Drain service account cleaned up", "namespace", namespace)
 	}
 }
 
-func (c *Controller) cleanUpDrainPodIfNeeded(sts *appsv1.StatefulSet, pod *corev1.Pod, ordinal int) error {
+func (c *Controller) cleanUpDrainPodIfNeeded(log logr.Logger, sts *appsv1.StatefulSet, pod *corev1.Pod, ordinal int) error {
 	// Drain Pod already exists. Check if it's done draining.
 	podName := getPodName(sts, ordinal)
 
 	podPhase := pod.Status.Phase
 	if podPhase == corev1.PodSucceeded || podPhase == corev1.PodFailed {
-		defer c.cleanupDrainRBACResources(sts.Namespace)
+		defer c.cleanupDrainRBACResources(log, sts.Namespace)
 	}
 
 	switch podPhase {
 	case (corev1.PodSucceeded):
-		dlog.Info("Drain pod " + podName + " finished.")
+		log.Info("Drain pod " + podName + " finished.")
+		c.drainSucceeded(log, sts, pod)
 		if !c.localOnly {
 			c.recorder.Event(sts, corev1.EventTypeNormal, DrainSuccess, fmt.Sprintf(MessageDrainPodFinished, podName, sts.Name))
 		}
 
-		for _, pvcTemplate := range sts.Spec.VolumeClaimTemplates {
-			pvcName := getPVCName(sts, pvcTemplate.Name, int32(ordinal))
-			dlog.Info("Deleting PVC " + pvcName)
-			err := c.kubeclientset.CoreV1().PersistentVolumeClaims(sts.Namespace).Delete(context.TODO(), pvcName, metav1.DeleteOptions{})
-			if err != nil {
-				return err
-			}
-			if !c.localOnly {
-				c.recorder.Event(sts, corev1.EventTypeNormal, PVCDeleteSuccess, fmt.Sprintf(MessagePVCDeleted, pvcName, sts.Name))
-			}
+		if err := c.deleteClaims(log, sts, ordinal); err != nil {
+			return err
 		}
 
 		// TODO what if the user scales up the statefulset and the statefulset controller creates the new pod after we delete the pod but before we delete the PVC
 		// TODO what if we crash after we delete the PVC, but before we delete the pod?
 		//
-		dlog.Info("Deleting drain pod " + podName)
+		log.Info("Deleting drain pod " + podName)
 		err := c.kubeclientset.CoreV1().Pods(sts.Namespace).Delete(context.TODO(), podName, metav1.DeleteOptions{})
 		if err != nil {
 			return err
@@ -616,17 +641,50 @@ func (c *Controller) cleanUpDrainPodIfNeeded(sts *appsv1.StatefulSet, pod *corev
 		}
 
 	case (corev1.PodFailed):
-		dlog.Info("Drain pod " + podName + " failed.")
+		log.Info("Drain pod " + podName + " failed.")
+		nextAttemptAt := c.drainFailed(log, sts, pod)
+		if delay := time.Until(nextAttemptAt); delay > 0 {
+			log.Info("Retrying drain pod "+podName, "in", delay.Round(time.Second).String())
+			c.requeueAfter(sts, delay)
+			return nil
+		}
+
+		// the next sync starts a new drain pod once the target pod is ready
+		log.Info("Deleting failed drain pod " + podName)
+		err := c.kubeclientset.CoreV1().Pods(sts.Namespace).Delete(context.TODO(), podName, metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
 
 	default:
 		str := fmt.Sprintf("Drain pod Phase was %s", pod.Status.Phase)
-		dlog.Info(str)
-
+		log.Info(str)
+		c.drainRunning(log, sts, pod)
 	}
 
 	return nil
 }
 
+func (c *Controller) deleteClaims(log logr.Logger, sts *appsv1.StatefulSet, ordinal int) error {
+	for _, pvcTemplate := range sts.Spec.VolumeClaimTemplates {
+		pvcName := getPVCName(sts, pvcTemplate.Name, int32(ordinal))
+		log.Info("Deleting PVC " + pvcName)
+		err := c.kubeclientset.CoreV1().PersistentVolumeClaims(sts.Namespace).Delete(context.TODO(), pvcName, metav1.DeleteOptions{})
+		if err != nil {
+			return err
+		}
+		if !c.localOnly {
+			c.recorder.Event(sts, corev1.EventTypeNormal, PVCDeleteSuccess, fmt.Sprintf(MessagePVCDeleted, pvcName, sts.Name))
+		}
+	}
+	return nil
+}
+
+func (c *Controller) isReplicatedBackup(sts *appsv1.StatefulSet, ordinal int) bool {
+	ssNames := c.ssNamesMap[types.NamespacedName{Namespace: sts.Namespace, Name: sts.Name}]
+	return ordinal%2 == 1 && ssNames[AnnotationHAReplication] == "true"
+}
+
 func isDrainPod(pod *corev1.Pod) bool {
 	return pod != nil && pod.ObjectMeta.Annotations[AnnotationStatefulSet] != ""
 }
@@ -735,7 +793,7 @@ func (c *Controller) GetStopCh() *chan struct{} {
 	return &c.stopCh
 }
 
-func (c *Controller) getClusterCredentials(namespace string, ssNames map[string]string) (string, string) {
+func (c *Controller) getClusterCredentials(log logr.Logger, namespace string, ssNames map[string]string) (string, string) {
 
 	secretName := ssNames["AMQ_CREDENTIALS_SECRET_NAME"]
 
@@ -749,54 +807,87 @@ func (c *Controller) getClusterCredentials(namespace string, ssNames map[string]
 
 	secretDefinition := secrets.NewSecret(namespacedName, secretName, stringDataMap, c.ssLabels)
 
-	dlog.Info("Try retrieving cluster credentials from secret", "secret", namespacedName)
+	log.Info("Try retrieving cluster credentials from secret", "secret", namespacedName)
 	if err := resources.Retrieve(namespacedName, c.client, secretDefinition); err != nil {
-		dlog.Info("Failed to retrieve cluster credentials from secret, using defaults", "err", err)
-		return ssNames["CLUSTERUSER"], ssNames["CLUSTERPASS"]
+		log.Info("Failed to retrieve cluster credentials from secret, using defaults", "err", err)
+		return c.openCredentials(log, ssNames["CLUSTERUSER"], ssNames["CLUSTERPASS"])
 	} else {
-		dlog.Info("retrieved cluster credential from existing secret")
+		log.Info("retrieved cluster credential from existing secret")
 		return string(secretDefinition.Data["AMQ_CLUSTER_USER"]), string(secretDefinition.Data["AMQ_CLUSTER_PASSWORD"])
 	}
 }
 
-func (c *Controller) newPod(sts *appsv1.StatefulSet, ordinal int) (*corev1.Pod, error) {
+// the credentials on the scaledown cr are sealed when the operator has encryption keys
+func (c *Controller) openCredentials(log logr.Logger, user string, password string) (string, string) {
+	if !envelope.IsSealed(user) && !envelope.IsSealed(password) {
+		return user, password
+	}
+	keyRing, err := envelope.LoadKeyRing(c.client)
+	if err == nil && keyRing == nil {
+		err = fmt.Errorf("cluster credentials are sealed but no encryption keys are configured")
+	}
+	if err == nil {
+		if user, err = keyRing.Open("CLUSTERUSER", user); err == nil {
+			password, err = keyRing.Open("CLUSTERPASS", password)
+		}
+	}
+	if err != nil {
+		log.Error(err, "Failed to open sealed cluster credentials")
+		return "", ""
+	}
+	return user, password
+}
+
+func (c *Controller) newPod(log logr.Logger, sts *appsv1.StatefulSet, ordinal int) (*corev1.Pod, error) {
 
 	ssNamesKey := types.NamespacedName{
 		Namespace: sts.Namespace,
 		Name:      sts.Name,
 	}
-	dlog.Info("Creating newPod for ss", "ss", ssNamesKey)
+	log.Info("Creating newPod for ss", "ss", ssNamesKey)
 
 	if _, ok := c.ssNamesMap[ssNamesKey]; !ok {
-		dlog.Info("Cannot find drain pod data for statefule set", "namespace", ssNamesKey)
+		log.Info("Cannot find drain pod data for statefule set", "namespace", ssNamesKey)
 		return nil, fmt.Errorf("No drain pod data for statefulset " + sts.Name)
 	}
 
 	ssNames := c.ssNamesMap[ssNamesKey]
+	ownerCr := c.ssToCrMap[ssNamesKey]
+	drainer := &brokerv1beta1.DrainerType{}
+	if ownerCr.Spec.Drainer != nil {
+		drainer = ownerCr.Spec.Drainer
+	}
 
 	//podTemplateJson := sts.Annotations[AnnotationDrainerPodTemplate]
 	//TODO: Remove this blatant hack
 	podTemplateJson := globalPodTemplateJson
-	clusterUser, clusterPassword := c.getClusterCredentials(sts.Namespace, ssNames)
+	clusterUser, clusterPassword := c.getClusterCredentials(log, sts.Namespace, ssNames)
 	podTemplateJson = strings.Replace(podTemplateJson, "CRNAME", ssNames["CRNAME"], -1)
 	podTemplateJson = strings.Replace(podTemplateJson, "CLUSTERUSER", clusterUser, 1)
 	podTemplateJson = strings.Replace(podTemplateJson, "CLUSTERPASS", clusterPassword, 1)
 	podTemplateJson = strings.Replace(podTemplateJson, "HEADLESSSVCNAMEVALUE", ssNames["HEADLESSSVCNAMEVALUE"], 1)
 	podTemplateJson = strings.Replace(podTemplateJson, "PINGSVCNAMEVALUE", ssNames["PINGSVCNAMEVALUE"], 1)
 
-	if c.localOnly {
+	if drainer.ServiceAccountName != "" {
+		// the service account is managed with the broker cr, the operator doesn't grant it anything
+		podTemplateJson = strings.Replace(podTemplateJson, "SERVICE_ACCOUNT", drainer.ServiceAccountName, 1)
+		podTemplateJson = strings.Replace(podTemplateJson, "SERVICE_ACCOUNT_NAME", drainer.ServiceAccountName, 1)
+	} else if c.localOnly {
 		podTemplateJson = strings.Replace(podTemplateJson, "SERVICE_ACCOUNT", os.Getenv("SERVICE_ACCOUNT"), 1)
 		podTemplateJson = strings.Replace(podTemplateJson, "SERVICE_ACCOUNT_NAME", os.Getenv("SERVICE_ACCOUNT"), 1)
 	} else {
 		// the drain pod is in a different namespace, we need set up a service account with proper permission
 		// and should delete it after drain is done.
-		c.createDrainRBACResources(sts.Namespace)
+		c.createDrainRBACResources(log, sts.Namespace)
 
-		dlog.Info("Setting drain pod service account", "service account name", DrainServiceAccountName)
+		log.Info("Setting drain pod service account", "service account name", DrainServiceAccountName)
 		podTemplateJson = strings.Replace(podTemplateJson, "SERVICE_ACCOUNT", DrainServiceAccountName, 1)
 		podTemplateJson = strings.Replace(podTemplateJson, "SERVICE_ACCOUNT_NAME", DrainServiceAccountName, 1)
 	}
 	image := sts.Spec.Template.Spec.Containers[0].Image
+	if drainer.Image != "" {
+		image = drainer.Image
+	}
 	//sts.Spec.Template.Spec.Containers[0].Resources = c.resources
 	if image == "" {
 		return nil, fmt.Errorf("No drain pod image configured for StatefulSet " + sts.Name)
@@ -827,12 +918,20 @@ func (c *Controller) newPod(sts *appsv1.StatefulSet, ordinal int) (*corev1.Pod,
 	if pod.OwnerReferences == nil {
 		pod.OwnerReferences = []metav1.OwnerReference{}
 	}
-	ownerCr := c.ssToCrMap[ssNamesKey]
 	pod.OwnerReferences = append(pod.OwnerReferences, *metav1.NewControllerRef(ownerCr, ownerCr.GroupVersionKind()))
 
-	pod.Spec.RestartPolicy = corev1.RestartPolicyOnFailure
+	// a failed drainer is started again by the controller, with a backoff and a status of the attempts
+	pod.Spec.RestartPolicy = corev1.RestartPolicyNever
 	pod.Spec.Containers[0].Resources = c.resources
 	pod.Spec.Tolerations = sts.Spec.Template.Spec.Tolerations
+	if drainer.Resources != nil {
+		pod.Spec.Containers[0].Resources = *drainer.Resources
+	}
+	if drainer.Tolerations != nil {
+		pod.Spec.Tolerations = drainer.Tolerations
+	}
+	pod.Spec.Containers[0].SecurityContext = drainer.SecurityContext
+	pod.Spec.SecurityContext = drainer.PodSecurityContext
 
 	for _, pvcTemplate := range sts.Spec.VolumeClaimTemplates {
 		pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{ // TODO: override existing volumes with the same name
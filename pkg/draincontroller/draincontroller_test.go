@@ -16,12 +16,26 @@ limitations under the License.
 package draincontroller
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
+	"time"
 
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestDrainController(t *testing.T) {
@@ -46,4 +60,190 @@ var _ = Describe("Drain Controller Test", func() {
 			Expect(servicePort).To(Equal("7800"))
 		})
 	})
+
+	Context("Drain status test", func() {
+		var (
+			sts        *appsv1.StatefulSet
+			scaledown  *brokerv1beta1.ActiveMQArtemisScaledown
+			controller *Controller
+			kubeClient *kubefake.Clientset
+		)
+
+		drainStatus := func() brokerv1beta1.ScaledownDrainStatus {
+			current := &brokerv1beta1.ActiveMQArtemisScaledown{}
+			Expect(controller.client.Get(context.TODO(), types.NamespacedName{Namespace: "test", Name: "broker"}, current)).Should(Succeed())
+			Expect(current.Status.Drains).Should(HaveLen(1))
+			return current.Status.Drains[0]
+		}
+
+		drainPod := func(phase corev1.PodPhase) *corev1.Pod {
+			return &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "broker-ss-1", Namespace: "test", UID: "drainer-uid", Annotations: map[string]string{AnnotationStatefulSet: sts.Name}},
+				Status:     corev1.PodStatus{Phase: phase},
+			}
+		}
+
+		BeforeEach(func() {
+			replicas := int32(1)
+			sts = &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "broker-ss", Namespace: "test"},
+				Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+			}
+			scaledown = &brokerv1beta1.ActiveMQArtemisScaledown{
+				ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: "test"},
+			}
+
+			scheme := runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(scheme)).Should(Succeed())
+			Expect(brokerv1beta1.AddToScheme(scheme)).Should(Succeed())
+
+			kubeClient = kubefake.NewSimpleClientset()
+			controller = &Controller{
+				kubeclientset:  kubeClient,
+				workqueue:      workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+				recorder:       record.NewFakeRecorder(10),
+				localOnly:      true,
+				ssToCrMap:      map[types.NamespacedName]*brokerv1beta1.ActiveMQArtemisScaledown{{Namespace: "test", Name: "broker-ss"}: scaledown},
+				client:         fake.NewClientBuilder().WithScheme(scheme).WithObjects(scaledown).Build(),
+				drainBaselines: map[string]int64{},
+			}
+		})
+
+		It("testing drain retry backoff doubles up to the maximum", func() {
+			Expect(drainRetryBackoff(1)).To(Equal(10 * time.Second))
+			Expect(drainRetryBackoff(2)).To(Equal(20 * time.Second))
+			Expect(drainRetryBackoff(4)).To(Equal(80 * time.Second))
+			Expect(drainRetryBackoff(10)).To(Equal(drainRetryMaxDelay))
+		})
+
+		It("testing drain status follows the drainer from waiting to succeeded", func() {
+			controller.drainWaiting(dlog, sts, "broker-ss-1", "broker-ss-0", "waiting for target pod broker-ss-0 to be ready")
+			Expect(drainStatus().Phase).To(Equal(brokerv1beta1.ScaledownDrainWaiting))
+			Expect(drainStatus().TargetPod).To(Equal("broker-ss-0"))
+
+			controller.drainStarted(dlog, sts, "broker-ss-1", "broker-ss-0")
+			Expect(drainStatus().Phase).To(Equal(brokerv1beta1.ScaledownDrainDraining))
+			Expect(drainStatus().Attempts).To(Equal(int32(1)))
+			Expect(drainStatus().StartedAt).ShouldNot(BeNil())
+			Expect(drainStatus().Message).To(BeEmpty())
+
+			progress := []int64{100, 4096}
+			controller.readProgress = func(pod *corev1.Pod) (int64, int64, error) {
+				return progress[0], progress[1], nil
+			}
+			running := drainPod(corev1.PodRunning)
+			Expect(controller.cleanUpDrainPodIfNeeded(dlog, sts, running, 1)).Should(Succeed())
+			Expect(*drainStatus().MessagesRemaining).To(Equal(int64(100)))
+			Expect(*drainStatus().BytesMoved).To(Equal(int64(0)))
+
+			progress = []int64{40, 1024}
+			Expect(controller.cleanUpDrainPodIfNeeded(dlog, sts, running, 1)).Should(Succeed())
+			Expect(*drainStatus().MessagesRemaining).To(Equal(int64(40)))
+			Expect(*drainStatus().BytesMoved).To(Equal(int64(3072)))
+
+			succeeded := drainPod(corev1.PodSucceeded)
+			_, err := kubeClient.CoreV1().Pods("test").Create(context.TODO(), succeeded, metav1.CreateOptions{})
+			Expect(err).Should(Succeed())
+			Expect(controller.cleanUpDrainPodIfNeeded(dlog, sts, succeeded, 1)).Should(Succeed())
+			drain := drainStatus()
+			Expect(drain.Phase).To(Equal(brokerv1beta1.ScaledownDrainSucceeded))
+			Expect(*drain.MessagesRemaining).To(Equal(int64(0)))
+			Expect(drain.CompletedAt).ShouldNot(BeNil())
+			Expect(drain.Duration).ShouldNot(BeNil())
+			Expect(controller.drainBaselines).To(BeEmpty())
+
+			controller.drainStarted(dlog, sts, "broker-ss-1", "broker-ss-0")
+			Expect(drainStatus().Attempts).To(Equal(int32(1)), "a new scale down of the ordinal starts afresh")
+			Expect(drainStatus().CompletedAt).Should(BeNil())
+		})
+
+		It("testing backup of a replicated pair is not drained", func() {
+			controller.ssNamesMap = map[types.NamespacedName]map[string]string{{Namespace: "test", Name: "broker-ss"}: {AnnotationHAReplication: "true"}}
+			Expect(controller.isReplicatedBackup(sts, 1)).To(BeTrue())
+			Expect(controller.isReplicatedBackup(sts, 2)).To(BeFalse(), "the primary of a pair is drained")
+			controller.ssNamesMap[types.NamespacedName{Namespace: "test", Name: "broker-ss"}][AnnotationHAReplication] = "false"
+			Expect(controller.isReplicatedBackup(sts, 1)).To(BeFalse())
+
+			sts.Spec.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{{ObjectMeta: metav1.ObjectMeta{Name: "broker"}}}
+			claim := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "broker-broker-ss-1", Namespace: "test"}}
+			_, err := kubeClient.CoreV1().PersistentVolumeClaims("test").Create(context.TODO(), claim, metav1.CreateOptions{})
+			Expect(err).Should(Succeed())
+			Expect(controller.deleteClaims(dlog, sts, 1)).Should(Succeed())
+			_, err = kubeClient.CoreV1().PersistentVolumeClaims("test").Get(context.TODO(), claim.Name, metav1.GetOptions{})
+			Expect(err).ShouldNot(Succeed())
+
+			controller.drainSkipped(dlog, sts, "broker-ss-1")
+			drain := drainStatus()
+			Expect(drain.Phase).To(Equal(brokerv1beta1.ScaledownDrainSucceeded))
+			Expect(drain.Attempts).To(Equal(int32(0)))
+			Expect(drain.CompletedAt).ShouldNot(BeNil())
+		})
+
+		It("testing failed drainer is started again after the backoff", func() {
+			controller.drainStarted(dlog, sts, "broker-ss-1", "broker-ss-0")
+
+			failed := drainPod(corev1.PodFailed)
+			failed.Status.ContainerStatuses = []corev1.ContainerStatus{{
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+					ExitCode:   1,
+					Reason:     "Error",
+					FinishedAt: metav1.Now(),
+				}},
+			}}
+			_, err := kubeClient.CoreV1().Pods("test").Create(context.TODO(), failed, metav1.CreateOptions{})
+			Expect(err).Should(Succeed())
+
+			Expect(controller.cleanUpDrainPodIfNeeded(dlog, sts, failed, 1)).Should(Succeed())
+			drain := drainStatus()
+			Expect(drain.Phase).To(Equal(brokerv1beta1.ScaledownDrainRetrying))
+			Expect(drain.Message).To(Equal("drainer exited with code 1, Error"))
+			Expect(drain.NextAttemptAt.Time).To(BeTemporally("~", failed.Status.ContainerStatuses[0].State.Terminated.FinishedAt.Add(drainRetryBaseDelay), time.Second))
+			_, err = kubeClient.CoreV1().Pods("test").Get(context.TODO(), failed.Name, metav1.GetOptions{})
+			Expect(err).Should(Succeed(), "the failed drainer is kept until the backoff passed")
+
+			controller.drainWaiting(dlog, sts, "broker-ss-1", "broker-ss-0", "waiting for target pod broker-ss-0 to be ready")
+			Expect(drainStatus().Phase).To(Equal(brokerv1beta1.ScaledownDrainRetrying))
+
+			failed.Status.ContainerStatuses[0].State.Terminated.FinishedAt = metav1.NewTime(time.Now().Add(-time.Minute))
+			Expect(controller.cleanUpDrainPodIfNeeded(dlog, sts, failed, 1)).Should(Succeed())
+			_, err = kubeClient.CoreV1().Pods("test").Get(context.TODO(), failed.Name, metav1.GetOptions{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			controller.drainStarted(dlog, sts, "broker-ss-1", "broker-ss-0")
+			drain = drainStatus()
+			Expect(drain.Phase).To(Equal(brokerv1beta1.ScaledownDrainDraining))
+			Expect(drain.Attempts).To(Equal(int32(2)))
+			Expect(drain.NextAttemptAt).Should(BeNil())
+		})
+
+		It("testing drainer pod takes the overrides of the broker cr", func() {
+			controller.ssNamesMap = map[types.NamespacedName]map[string]string{{Namespace: "test", Name: "broker-ss"}: {"CRNAME": "broker"}}
+			sts.Spec.Template.Spec.Containers = []corev1.Container{{Name: "broker-container", Image: "broker-image"}}
+			sts.Spec.Template.Spec.Tolerations = []corev1.Toleration{{Key: "broker", Operator: corev1.TolerationOpExists}}
+
+			pod, err := controller.newPod(dlog, sts, 1)
+			Expect(err).Should(Succeed())
+			Expect(pod.Spec.Containers[0].Image).To(Equal("broker-image"))
+			Expect(pod.Spec.Tolerations).To(Equal(sts.Spec.Template.Spec.Tolerations))
+			Expect(pod.Spec.SecurityContext).Should(BeNil())
+
+			nonRoot := true
+			scaledown.Spec.Drainer = &brokerv1beta1.DrainerType{
+				Image:              "drainer-image",
+				Resources:          &corev1.ResourceRequirements{Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")}},
+				SecurityContext:    &corev1.SecurityContext{AllowPrivilegeEscalation: new(bool)},
+				PodSecurityContext: &corev1.PodSecurityContext{RunAsNonRoot: &nonRoot},
+				Tolerations:        []corev1.Toleration{{Key: "drainer", Operator: corev1.TolerationOpExists}},
+				ServiceAccountName: "drainer",
+			}
+			pod, err = controller.newPod(dlog, sts, 1)
+			Expect(err).Should(Succeed())
+			Expect(pod.Spec.Containers[0].Image).To(Equal("drainer-image"))
+			Expect(pod.Spec.Containers[0].Resources).To(Equal(*scaledown.Spec.Drainer.Resources))
+			Expect(pod.Spec.Containers[0].SecurityContext).To(Equal(scaledown.Spec.Drainer.SecurityContext))
+			Expect(pod.Spec.SecurityContext).To(Equal(scaledown.Spec.Drainer.PodSecurityContext))
+			Expect(pod.Spec.Tolerations).To(Equal(scaledown.Spec.Drainer.Tolerations))
+			Expect(pod.Spec.ServiceAccountName).To(Equal("drainer"))
+		})
+	})
 })
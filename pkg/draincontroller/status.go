@@ -0,0 +1,309 @@
+package draincontroller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	mgmt "github.com/artemiscloud/activemq-artemis-operator/pkg/utils/artemis"
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	drainRetryBaseDelay   = 10 * time.Second
+	drainRetryMaxDelay    = 5 * time.Minute
+	drainProgressInterval = 10 * time.Second
+
+	// the drainer runs a broker with the name and credentials of the pod template
+	drainerJolokiaPort = "8161"
+	drainerBrokerName  = "amq-broker"
+	drainerUser        = "admin"
+	drainerPassword    = "admin"
+)
+
+var (
+	drainAttempts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "activemq_artemis_drain_attempts_total",
+		Help: "The drainer pods started to migrate the messages of removed brokers",
+	}, []string{"namespace", "statefulset"})
+
+	drainFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "activemq_artemis_drain_failures_total",
+		Help: "The drainer pods that failed, each failure is retried with a backoff",
+	}, []string{"namespace", "statefulset"})
+
+	drainDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "activemq_artemis_drain_duration_seconds",
+		Help:    "The time from the first attempt to migrate the messages of a removed broker to the completion",
+		Buckets: prometheus.ExponentialBuckets(10, 2, 10),
+	}, []string{"namespace", "statefulset"})
+
+	drainMessagesRemaining = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "activemq_artemis_drain_messages_remaining",
+		Help: "The messages a drainer pod still has to migrate",
+	}, []string{"namespace", "pod"})
+
+	drainBytesMoved = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "activemq_artemis_drain_bytes_moved",
+		Help: "The decrease of the size of the messages in a drainer pod since the operator first read it",
+	}, []string{"namespace", "pod"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(drainAttempts, drainFailures, drainDuration, drainMessagesRemaining, drainBytesMoved)
+}
+
+// the delay before the drainer is started again, doubling with every attempt
+func drainRetryBackoff(attempts int32) time.Duration {
+	delay := drainRetryBaseDelay
+	for i := int32(1); i < attempts && delay < drainRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > drainRetryMaxDelay {
+		delay = drainRetryMaxDelay
+	}
+	return delay
+}
+
+// readDrainerProgress reads the messages left in the broker of the drainer pod and their size, the
+// address memory usage would miss the paged messages
+func readDrainerProgress(pod *corev1.Pod) (int64, int64, error) {
+	if pod.Status.PodIP == "" {
+		return 0, 0, fmt.Errorf("drain pod %v has no ip yet", pod.Name)
+	}
+	artemis := mgmt.GetArtemis(pod.Status.PodIP, drainerJolokiaPort, drainerBrokerName, drainerUser, drainerPassword, "http")
+	messages, err := artemis.GetTotalMessageCount()
+	if err != nil {
+		return 0, 0, err
+	}
+	bytes, err := artemis.GetTotalPersistentSize()
+	if err != nil {
+		return 0, 0, err
+	}
+	return messages, bytes, nil
+}
+
+func drainFailureMessage(pod *corev1.Pod) string {
+	for _, status := range pod.Status.ContainerStatuses {
+		if terminated := status.State.Terminated; terminated != nil {
+			message := fmt.Sprintf("drainer exited with code %d", terminated.ExitCode)
+			if terminated.Reason != "" {
+				message += ", " + terminated.Reason
+			}
+			if terminated.Message != "" {
+				message += ": " + terminated.Message
+			}
+			return message
+		}
+	}
+	if pod.Status.Message != "" {
+		return "drain pod failed: " + pod.Status.Message
+	}
+	return "drain pod failed"
+}
+
+func drainFailedAt(pod *corev1.Pod) time.Time {
+	for _, status := range pod.Status.ContainerStatuses {
+		if terminated := status.State.Terminated; terminated != nil && !terminated.FinishedAt.IsZero() {
+			return terminated.FinishedAt.Time
+		}
+	}
+	return time.Now()
+}
+
+// setDrainStatus applies change to the status of the drain of podName on the scaledown cr of sts and
+// returns the status as it was before the change
+func (c *Controller) setDrainStatus(sts *appsv1.StatefulSet, podName string, change func(drain *brokerv1beta1.ScaledownDrainStatus)) (brokerv1beta1.ScaledownDrainStatus, error) {
+	previous := brokerv1beta1.ScaledownDrainStatus{Pod: podName}
+
+	instance, found := c.ssToCrMap[types.NamespacedName{Namespace: sts.Namespace, Name: sts.Name}]
+	if !found {
+		return previous, nil
+	}
+	scaledown := &brokerv1beta1.ActiveMQArtemisScaledown{}
+	if err := c.client.Get(context.TODO(), types.NamespacedName{Namespace: instance.Namespace, Name: instance.Name}, scaledown); err != nil {
+		return previous, err
+	}
+
+	index := -1
+	for i := range scaledown.Status.Drains {
+		if scaledown.Status.Drains[i].Pod == podName {
+			index = i
+			previous = scaledown.Status.Drains[i]
+		}
+	}
+	if index == -1 {
+		scaledown.Status.Drains = append(scaledown.Status.Drains, brokerv1beta1.ScaledownDrainStatus{Pod: podName})
+		index = len(scaledown.Status.Drains) - 1
+	}
+
+	drain := &scaledown.Status.Drains[index]
+	change(drain)
+
+	if equality.Semantic.DeepEqual(*drain, previous) {
+		return previous, nil
+	}
+	return previous, c.client.Status().Update(context.TODO(), scaledown)
+}
+
+// the ordinal was scaled up and down again since its last drain completed
+func restartCompletedDrain(drain *brokerv1beta1.ScaledownDrainStatus) {
+	if drain.Phase == brokerv1beta1.ScaledownDrainSucceeded {
+		*drain = brokerv1beta1.ScaledownDrainStatus{Pod: drain.Pod}
+	}
+}
+
+func (c *Controller) drainWaiting(log logr.Logger, sts *appsv1.StatefulSet, podName string, targetPod string, message string) {
+	_, err := c.setDrainStatus(sts, podName, func(drain *brokerv1beta1.ScaledownDrainStatus) {
+		restartCompletedDrain(drain)
+		drain.TargetPod = targetPod
+		if drain.Phase != brokerv1beta1.ScaledownDrainRetrying {
+			drain.Phase = brokerv1beta1.ScaledownDrainWaiting
+		}
+		drain.Message = message
+	})
+	if err != nil {
+		log.Error(err, "unable to update the drain status", "pod", podName)
+	}
+}
+
+func (c *Controller) drainStarted(log logr.Logger, sts *appsv1.StatefulSet, podName string, targetPod string) {
+	_, err := c.setDrainStatus(sts, podName, func(drain *brokerv1beta1.ScaledownDrainStatus) {
+		restartCompletedDrain(drain)
+		drain.TargetPod = targetPod
+		drain.Phase = brokerv1beta1.ScaledownDrainDraining
+		drain.Attempts++
+		if drain.StartedAt == nil {
+			now := metav1.Now()
+			drain.StartedAt = &now
+		}
+		drain.NextAttemptAt = nil
+		drain.Message = ""
+	})
+	if err != nil {
+		log.Error(err, "unable to update the drain status", "pod", podName)
+	}
+	drainAttempts.WithLabelValues(sts.Namespace, sts.Name).Inc()
+}
+
+// drainRunning refreshes the progress of a running drainer, the StatefulSet is queued again to read
+// it until the drainer completes
+func (c *Controller) drainRunning(log logr.Logger, sts *appsv1.StatefulSet, pod *corev1.Pod) {
+	var messagesRemaining, bytesMoved *int64
+	if pod.Status.Phase == corev1.PodRunning {
+		messages, bytes, err := c.readProgress(pod)
+		if err != nil {
+			log.V(1).Info("unable to read the drainer progress", "pod", pod.Name, "error", err)
+		} else {
+			key := string(pod.UID)
+			if _, found := c.drainBaselines[key]; !found {
+				c.drainBaselines[key] = bytes
+			}
+			moved := c.drainBaselines[key] - bytes
+			if moved < 0 {
+				moved = 0
+			}
+			messagesRemaining, bytesMoved = &messages, &moved
+			drainMessagesRemaining.WithLabelValues(pod.Namespace, pod.Name).Set(float64(messages))
+			drainBytesMoved.WithLabelValues(pod.Namespace, pod.Name).Set(float64(moved))
+		}
+	}
+
+	_, err := c.setDrainStatus(sts, pod.Name, func(drain *brokerv1beta1.ScaledownDrainStatus) {
+		drain.Phase = brokerv1beta1.ScaledownDrainDraining
+		if messagesRemaining != nil {
+			drain.MessagesRemaining = messagesRemaining
+			drain.BytesMoved = bytesMoved
+		}
+		drain.Message = ""
+	})
+	if err != nil {
+		log.Error(err, "unable to update the drain status", "pod", pod.Name)
+	}
+	c.requeueAfter(sts, drainProgressInterval)
+}
+
+func (c *Controller) drainSkipped(log logr.Logger, sts *appsv1.StatefulSet, podName string) {
+	now := metav1.Now()
+	_, err := c.setDrainStatus(sts, podName, func(drain *brokerv1beta1.ScaledownDrainStatus) {
+		*drain = brokerv1beta1.ScaledownDrainStatus{
+			Pod:         podName,
+			Phase:       brokerv1beta1.ScaledownDrainSucceeded,
+			CompletedAt: &now,
+			Message:     "not drained, the backup of a replicated pair holds the same messages as its primary",
+		}
+	})
+	if err != nil {
+		log.Error(err, "unable to update the drain status", "pod", podName)
+	}
+}
+
+func (c *Controller) drainSucceeded(log logr.Logger, sts *appsv1.StatefulSet, pod *corev1.Pod) {
+	now := metav1.Now()
+	previous, err := c.setDrainStatus(sts, pod.Name, func(drain *brokerv1beta1.ScaledownDrainStatus) {
+		if drain.Phase == brokerv1beta1.ScaledownDrainSucceeded {
+			return
+		}
+		drain.Phase = brokerv1beta1.ScaledownDrainSucceeded
+		drain.CompletedAt = &now
+		if drain.StartedAt != nil {
+			drain.Duration = &metav1.Duration{Duration: now.Sub(drain.StartedAt.Time)}
+		}
+		remaining := int64(0)
+		drain.MessagesRemaining = &remaining
+		drain.NextAttemptAt = nil
+		drain.Message = ""
+	})
+	if err != nil {
+		log.Error(err, "unable to update the drain status", "pod", pod.Name)
+	}
+	if previous.Phase != brokerv1beta1.ScaledownDrainSucceeded && previous.StartedAt != nil {
+		drainDuration.WithLabelValues(sts.Namespace, sts.Name).Observe(now.Sub(previous.StartedAt.Time).Seconds())
+	}
+	c.forgetDrainProgress(pod)
+}
+
+// drainFailed records the failure of the drainer and returns when it is due to be started again
+func (c *Controller) drainFailed(log logr.Logger, sts *appsv1.StatefulSet, pod *corev1.Pod) time.Time {
+	failedAt := drainFailedAt(pod)
+	var nextAttemptAt time.Time
+	previous, err := c.setDrainStatus(sts, pod.Name, func(drain *brokerv1beta1.ScaledownDrainStatus) {
+		attempts := drain.Attempts
+		if attempts < 1 {
+			attempts = 1
+		}
+		nextAttemptAt = failedAt.Add(drainRetryBackoff(attempts))
+		drain.Phase = brokerv1beta1.ScaledownDrainRetrying
+		drain.NextAttemptAt = &metav1.Time{Time: nextAttemptAt}
+		drain.Message = drainFailureMessage(pod)
+	})
+	if err != nil {
+		log.Error(err, "unable to update the drain status", "pod", pod.Name)
+	}
+	if previous.Phase != brokerv1beta1.ScaledownDrainRetrying {
+		drainFailures.WithLabelValues(sts.Namespace, sts.Name).Inc()
+	}
+	c.forgetDrainProgress(pod)
+	return nextAttemptAt
+}
+
+func (c *Controller) forgetDrainProgress(pod *corev1.Pod) {
+	delete(c.drainBaselines, string(pod.UID))
+	drainMessagesRemaining.DeleteLabelValues(pod.Namespace, pod.Name)
+	drainBytesMoved.DeleteLabelValues(pod.Namespace, pod.Name)
+}
+
+func (c *Controller) requeueAfter(sts *appsv1.StatefulSet, delay time.Duration) {
+	if key, err := cache.MetaNamespaceKeyFunc(sts); err == nil {
+		c.workqueue.AddAfter(key, delay)
+	}
+}
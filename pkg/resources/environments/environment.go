@@ -71,12 +71,12 @@ func DetectOpenshift() (bool, error) {
 	return false, errors.New("environment not yet determined")
 }
 
-func AddEnvVarForBasic(requireLogin string, journalType string, svcPingName string) []corev1.EnvVar {
+func AddEnvVarForBasic(requireLogin string, journalType string, svcPingName string, adminRole string) []corev1.EnvVar {
 
 	envVarArray := []corev1.EnvVar{
 		{
 			Name:      "AMQ_ROLE",
-			Value:     "admin",
+			Value:     adminRole,
 			ValueFrom: nil,
 		},
 		{
@@ -198,6 +198,25 @@ func AddEnvVarForJolokia(jolokiaAgentEnabled string) []corev1.EnvVar {
 	return envVarArray
 }
 
+// the operator management client reads the user from the broker container
+func AddEnvVarForJolokiaCredentials(secretName string) []corev1.EnvVar {
+
+	envVarArray := []corev1.EnvVar{}
+	for _, env := range []struct{ name, key string }{{"AMQ_JOLOKIA_USER", "jolokiaUser"}, {"AMQ_JOLOKIA_PASSWORD", "jolokiaPassword"}} {
+		envVarArray = append(envVarArray, corev1.EnvVar{
+			Name: env.name,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+					Key:                  env.key,
+				},
+			},
+		})
+	}
+
+	return envVarArray
+}
+
 func AddEnvVarForManagement(managementRBACEnabled string) []corev1.EnvVar {
 
 	envVarArray := []corev1.EnvVar{
@@ -224,6 +243,34 @@ func AddEnvVarForMetricsPlugin(metricsPluginEnabled string) []corev1.EnvVar {
 	return envVarArray
 }
 
+func AddEnvVarForProxy(httpProxy string, httpsProxy string, noProxy string) []corev1.EnvVar {
+
+	envVarArray := []corev1.EnvVar{}
+	if httpProxy != "" {
+		envVarArray = append(envVarArray, corev1.EnvVar{
+			Name:      "HTTP_PROXY",
+			Value:     httpProxy,
+			ValueFrom: nil,
+		})
+	}
+	if httpsProxy != "" {
+		envVarArray = append(envVarArray, corev1.EnvVar{
+			Name:      "HTTPS_PROXY",
+			Value:     httpsProxy,
+			ValueFrom: nil,
+		})
+	}
+	if noProxy != "" {
+		envVarArray = append(envVarArray, corev1.EnvVar{
+			Name:      "NO_PROXY",
+			Value:     noProxy,
+			ValueFrom: nil,
+		})
+	}
+
+	return envVarArray
+}
+
 // https://stackoverflow.com/questions/37334119/how-to-delete-an-element-from-a-slice-in-golang
 func remove(s []corev1.EnvVar, i int) []corev1.EnvVar {
 	s[i] = s[len(s)-1]
@@ -9,7 +9,11 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
-const defaultIngressDomain string = "apps.artemiscloud.io"
+const DefaultIngressDomain string = "apps.artemiscloud.io"
+
+// SSLPassthroughAnnotation asks the ingress controller to hand the TLS stream to
+// the broker untouched so the acceptor can terminate it and route on SNI
+const SSLPassthroughAnnotation string = "nginx.ingress.kubernetes.io/ssl-passthrough"
 
 func NewIngressForCRWithSSL(existing *netv1.Ingress, namespacedName types.NamespacedName, labels map[string]string, targetServiceName string, targetPortName string, sslEnabled bool, domain string) *netv1.Ingress {
 
@@ -71,14 +75,21 @@ func NewIngressForCRWithSSL(existing *netv1.Ingress, namespacedName types.Namesp
 	}
 
 	if domain == "" {
-		domain = defaultIngressDomain
+		domain = DefaultIngressDomain
 	}
 
 	host := desired.GetObjectMeta().GetName() + "." + domain
 	desired.Spec.Rules[0].Host = host
 	if sslEnabled {
-		desired.Annotations = map[string]string{"nginx.ingress.kubernetes.io/ssl-passthrough": "true"}
+		if desired.Annotations == nil {
+			desired.Annotations = map[string]string{}
+		}
+		desired.Annotations[SSLPassthroughAnnotation] = "true"
 		desired.Spec.TLS = []netv1.IngressTLS{{Hosts: []string{host}}}
+	} else {
+		// an acceptor that drops ssl must not keep routing through passthrough
+		delete(desired.Annotations, SSLPassthroughAnnotation)
+		desired.Spec.TLS = nil
 	}
 	return desired
 }
@@ -2,6 +2,7 @@ package volumes
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	logf "sigs.k8s.io/controller-runtime"
 )
 
@@ -145,6 +146,24 @@ func MakePersistentVolume(customResourceName string) []corev1.Volume {
 	return volume
 }
 
+// MakeEphemeralVolume stands in for the persistent volume, under the same name so it mounts in the same place
+func MakeEphemeralVolume(customResourceName string, medium corev1.StorageMedium, sizeLimit *resource.Quantity) []corev1.Volume {
+
+	volume := []corev1.Volume{
+		{
+			Name: customResourceName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{
+					Medium:    medium,
+					SizeLimit: sizeLimit,
+				},
+			},
+		},
+	}
+
+	return volume
+}
+
 //func makePersistentVolumeMount(cr *brokerv2alpha1.ActiveMQArtemis) []corev1.VolumeMount {
 func MakePersistentVolumeMount(customResourceName string, mountPath string) []corev1.VolumeMount {
 
@@ -1,7 +1,9 @@
 package artemis
 
 import (
+	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/artemiscloud/activemq-artemis-operator/pkg/utils/jolokia"
@@ -10,6 +12,8 @@ import (
 const (
 	QUEUE_ALREADY_EXISTS   = "AMQ229019"
 	ADDRESS_ALREADY_EXISTS = "AMQ229204"
+	QUEUE_DOES_NOT_EXIST   = "AMQ229017"
+	ADDRESS_DOES_NOT_EXIST = "AMQ229203"
 	UNKNOWN_ERROR          = "AMQ_UNKNOWN"
 )
 
@@ -26,6 +30,25 @@ func GetCreationError(jdata *jolokia.ResponseData) string {
 	return UNKNOWN_ERROR
 }
 
+// IsNotFoundError reports whether the broker rejected an operation because
+// the queue or address it refers to does not exist
+func IsNotFoundError(jdata *jolokia.ResponseData) bool {
+	if jdata == nil {
+		return false
+	}
+	return strings.Contains(jdata.Error, QUEUE_DOES_NOT_EXIST) || strings.Contains(jdata.Error, ADDRESS_DOES_NOT_EXIST)
+}
+
+// IsUserExistsError reports whether the broker rejected adding a user it already has
+func IsUserExistsError(jdata *jolokia.ResponseData) bool {
+	return jdata != nil && strings.Contains(jdata.Error, "User ") && strings.Contains(jdata.Error, "already exists")
+}
+
+// IsUserNotFoundError reports whether the broker rejected removing a user it doesn't have
+func IsUserNotFoundError(jdata *jolokia.ResponseData) bool {
+	return jdata != nil && strings.Contains(jdata.Error, "User ") && strings.Contains(jdata.Error, "does not exist")
+}
+
 type IArtemis interface {
 	NewArtemis(_ip string, _jolokiaPort string, _name string, _userName string, _password string) *Artemis
 	Uptime() (*jolokia.ResponseData, error)
@@ -95,16 +118,28 @@ func (artemis *Artemis) CreateQueue(addressName string, queueName string, routin
 
 func (artemis *Artemis) UpdateQueue(queueConfig string) (*jolokia.ResponseData, error) {
 	url := "org.apache.activemq.artemis:broker=\\\"" + artemis.name + "\\\""
-	parameters := queueConfig
-	jsonStr := `{ "type":"EXEC","mbean":"` + url + `","operation":"updateQueue(java.lang.String)","arguments":[` + parameters + `]` + ` }`
-
-	data, err := artemis.jolokia.Exec(url, jsonStr)
+	data, err := artemis.jolokia.Exec(url, artemis.UpdateQueueOperation(queueConfig))
 
 	return data, err
 
 }
 
+// UpdateQueueOperation is the request of UpdateQueue, for ExecBulk
+func (artemis *Artemis) UpdateQueueOperation(queueConfig string) string {
+	url := "org.apache.activemq.artemis:broker=\\\"" + artemis.name + "\\\""
+	parameters := queueConfig
+	return `{ "type":"EXEC","mbean":"` + url + `","operation":"updateQueue(java.lang.String)","arguments":[` + parameters + `]` + ` }`
+}
+
 func (artemis *Artemis) CreateQueueFromConfig(queueConfig string, ignoreIfExists bool) (*jolokia.ResponseData, error) {
+	url := "org.apache.activemq.artemis:broker=\\\"" + artemis.name + "\\\""
+	data, err := artemis.jolokia.Exec(url, artemis.CreateQueueFromConfigOperation(queueConfig, ignoreIfExists))
+
+	return data, err
+}
+
+// CreateQueueFromConfigOperation is the request of CreateQueueFromConfig, for ExecBulk
+func (artemis *Artemis) CreateQueueFromConfigOperation(queueConfig string, ignoreIfExists bool) string {
 	var ignoreIfExistsValue string
 	if ignoreIfExists {
 		ignoreIfExistsValue = "true"
@@ -113,19 +148,33 @@ func (artemis *Artemis) CreateQueueFromConfig(queueConfig string, ignoreIfExists
 	}
 	url := "org.apache.activemq.artemis:broker=\\\"" + artemis.name + "\\\""
 	parameters := queueConfig + `,` + ignoreIfExistsValue
-	jsonStr := `{ "type":"EXEC","mbean":"` + url + `","operation":"createQueue(java.lang.String,boolean)","arguments":[` + parameters + `]` + ` }`
+	return `{ "type":"EXEC","mbean":"` + url + `","operation":"createQueue(java.lang.String,boolean)","arguments":[` + parameters + `]` + ` }`
+}
 
-	data, err := artemis.jolokia.Exec(url, jsonStr)
+func (artemis *Artemis) CreateAddress(addressName string, routingType string) (*jolokia.ResponseData, error) {
+
+	url := "org.apache.activemq.artemis:broker=\\\"" + artemis.name + "\\\""
+	data, err := artemis.jolokia.Exec(url, artemis.CreateAddressOperation(addressName, routingType))
 
 	return data, err
 }
 
-func (artemis *Artemis) CreateAddress(addressName string, routingType string) (*jolokia.ResponseData, error) {
-
+// CreateAddressOperation is the request of CreateAddress, for ExecBulk. The routing type can be a comma
+// separated list
+func (artemis *Artemis) CreateAddressOperation(addressName string, routingType string) string {
 	url := "org.apache.activemq.artemis:broker=\\\"" + artemis.name + "\\\""
 	routingType = strings.ToUpper(routingType)
 	parameters := `"` + addressName + `","` + routingType + `"`
-	jsonStr := `{ "type":"EXEC","mbean":"` + url + `","operation":"createAddress(java.lang.String,java.lang.String)","arguments":[` + parameters + `]` + ` }`
+	return `{ "type":"EXEC","mbean":"` + url + `","operation":"createAddress(java.lang.String,java.lang.String)","arguments":[` + parameters + `]` + ` }`
+}
+
+// UpdateAddress sets the routing types of an address, routingTypes is a comma separated list. The
+// broker refuses to drop a routing type that queues of the address still use
+func (artemis *Artemis) UpdateAddress(addressName string, routingTypes string) (*jolokia.ResponseData, error) {
+
+	url := "org.apache.activemq.artemis:broker=\\\"" + artemis.name + "\\\""
+	parameters := quoteArgument(addressName) + `,` + quoteArgument(strings.ToUpper(routingTypes))
+	jsonStr := `{ "type":"EXEC","mbean":"` + url + `","operation":"updateAddress(java.lang.String,java.lang.String)","arguments":[` + parameters + `]` + ` }`
 	data, err := artemis.jolokia.Exec(url, jsonStr)
 
 	return data, err
@@ -134,13 +183,18 @@ func (artemis *Artemis) CreateAddress(addressName string, routingType string) (*
 func (artemis *Artemis) DeleteQueue(queueName string) (*jolokia.ResponseData, error) {
 
 	url := "org.apache.activemq.artemis:broker=\\\"" + artemis.name + "\\\""
-	parameters := `"` + queueName + `"`
-	jsonStr := `{ "type":"EXEC","mbean":"` + url + `","operation":"destroyQueue(java.lang.String)","arguments":[` + parameters + `]` + ` }`
-	data, err := artemis.jolokia.Exec(url, jsonStr)
+	data, err := artemis.jolokia.Exec(url, artemis.DeleteQueueOperation(queueName))
 
 	return data, err
 }
 
+// DeleteQueueOperation is the request of DeleteQueue, for ExecBulk
+func (artemis *Artemis) DeleteQueueOperation(queueName string) string {
+	url := "org.apache.activemq.artemis:broker=\\\"" + artemis.name + "\\\""
+	parameters := `"` + queueName + `"`
+	return `{ "type":"EXEC","mbean":"` + url + `","operation":"destroyQueue(java.lang.String)","arguments":[` + parameters + `]` + ` }`
+}
+
 func (artemis *Artemis) ListBindingsForAddress(addressName string) (*jolokia.ResponseData, error) {
 
 	url := "org.apache.activemq.artemis:broker=\\\"" + artemis.name + "\\\""
@@ -153,10 +207,376 @@ func (artemis *Artemis) ListBindingsForAddress(addressName string) (*jolokia.Res
 
 func (artemis *Artemis) DeleteAddress(addressName string) (*jolokia.ResponseData, error) {
 
+	url := "org.apache.activemq.artemis:broker=\\\"" + artemis.name + "\\\""
+	data, err := artemis.jolokia.Exec(url, artemis.DeleteAddressOperation(addressName))
+
+	return data, err
+}
+
+// DeleteAddressOperation is the request of DeleteAddress, for ExecBulk. The broker refuses to delete
+// an address that has queues
+func (artemis *Artemis) DeleteAddressOperation(addressName string) string {
 	url := "org.apache.activemq.artemis:broker=\\\"" + artemis.name + "\\\""
 	parameters := `"` + addressName + `"`
-	jsonStr := `{ "type":"EXEC","mbean":"` + url + `","operation":"deleteAddress(java.lang.String)","arguments":[` + parameters + `]` + ` }`
+	return `{ "type":"EXEC","mbean":"` + url + `","operation":"deleteAddress(java.lang.String)","arguments":[` + parameters + `]` + ` }`
+}
+
+// ExecBulk sends operations to the broker in one request, it returns the response of each of them
+func (artemis *Artemis) ExecBulk(operations []string) ([]*jolokia.ResponseData, error) {
+	return artemis.jolokia.ExecBulk(operations)
+}
+
+// ListAddresses lists the names of every address on the broker
+func (artemis *Artemis) ListAddresses() ([]string, error) {
+
+	url := "org.apache.activemq.artemis:broker=\\\"" + artemis.name + "\\\""
+	jsonStr := `{ "type":"EXEC","mbean":"` + url + `","operation":"listAddresses(java.lang.String)","arguments":[","]` + ` }`
+	data, err := artemis.jolokia.Exec(url, jsonStr)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil || data.Value == "" {
+		return nil, nil
+	}
+	return strings.Split(data.Value, ","), nil
+}
+
+// GetQueueMessageCount reads the number of messages in a queue, including
+// the ones delivered to consumers and not yet acknowledged
+func (artemis *Artemis) GetQueueMessageCount(addressName string, routingType string, queueName string) (int64, error) {
+	url := "org.apache.activemq.artemis:broker=\"" + artemis.name + "\",component=addresses,address=\"" + addressName +
+		"\",subcomponent=queues,routing-type=\"" + strings.ToLower(routingType) + "\",queue=\"" + queueName + "\"/MessageCount"
+	return artemis.readCount(url)
+}
+
+// GetQueueConsumerCount reads the number of consumers attached to a queue
+func (artemis *Artemis) GetQueueConsumerCount(addressName string, routingType string, queueName string) (int64, error) {
+	url := "org.apache.activemq.artemis:broker=\"" + artemis.name + "\",component=addresses,address=\"" + addressName +
+		"\",subcomponent=queues,routing-type=\"" + strings.ToLower(routingType) + "\",queue=\"" + queueName + "\"/ConsumerCount"
+	return artemis.readCount(url)
+}
+
+// GetBridgeMessagesAcknowledged reads the number of messages a bridge has
+// forwarded and had acknowledged by its target
+func (artemis *Artemis) GetBridgeMessagesAcknowledged(bridgeName string) (int64, error) {
+	url := "org.apache.activemq.artemis:broker=\"" + artemis.name + "\",component=bridges,name=\"" + bridgeName + "\"/MessagesAcknowledged"
+	return artemis.readCount(url)
+}
+
+// GetDiskStoreUsage reads the fraction of the disk holding the journal that is in use
+func (artemis *Artemis) GetDiskStoreUsage() (float64, error) {
+	return artemis.readNumber("org.apache.activemq.artemis:broker=\"" + artemis.name + "\"/DiskStoreUsage")
+}
+
+// GetAddressMemoryUsagePercentage reads the memory used by addresses as a percentage of
+// the global max size, addresses start paging at 100
+func (artemis *Artemis) GetAddressMemoryUsagePercentage() (float64, error) {
+	return artemis.readNumber("org.apache.activemq.artemis:broker=\"" + artemis.name + "\"/AddressMemoryUsagePercentage")
+}
+
+// GetTotalMessageCount reads the number of messages in all the queues of the broker
+func (artemis *Artemis) GetTotalMessageCount() (int64, error) {
+	return artemis.readCount("org.apache.activemq.artemis:broker=\"" + artemis.name + "\"/TotalMessageCount")
+}
+
+// GetTotalPersistentSize reads the bytes of the messages in all the queues of the broker, the
+// paged ones included
+func (artemis *Artemis) GetTotalPersistentSize() (int64, error) {
+	url := "org.apache.activemq.artemis:broker=\"" + artemis.name + "\",component=addresses,address=*,subcomponent=queues,routing-type=*,queue=*/PersistentSize"
+	resp, err := artemis.jolokia.Read(url)
+	if err != nil {
+		return 0, err
+	}
+	if resp == nil || resp.Status != 200 {
+		return 0, fmt.Errorf("unable to read %v", url)
+	}
+	queues := map[string]map[string]float64{}
+	if err := json.Unmarshal([]byte(resp.Value), &queues); err != nil {
+		return 0, err
+	}
+	var total int64
+	for _, attributes := range queues {
+		total += int64(attributes["PersistentSize"])
+	}
+	return total, nil
+}
+
+// IsReplicaSync reads whether the backup of a live broker has completed the initial
+// synchronization of the journal
+func (artemis *Artemis) IsReplicaSync() (bool, error) {
+	url := "org.apache.activemq.artemis:broker=\"" + artemis.name + "\"/ReplicaSync"
+	resp, err := artemis.jolokia.Read(url)
+	if err != nil {
+		return false, err
+	}
+	if resp == nil || resp.Status != 200 {
+		return false, fmt.Errorf("unable to read %v", url)
+	}
+	return strconv.ParseBool(resp.Value)
+}
+
+func (artemis *Artemis) readCount(url string) (int64, error) {
+	count, err := artemis.readNumber(url)
+	return int64(count), err
+}
+
+func (artemis *Artemis) readNumber(url string) (float64, error) {
+	resp, err := artemis.jolokia.Read(url)
+	if err != nil {
+		return 0, err
+	}
+	if resp == nil || resp.Status != 200 {
+		return 0, fmt.Errorf("unable to read %v", url)
+	}
+	return strconv.ParseFloat(resp.Value, 64)
+}
+
+func (artemis *Artemis) AddConnector(connectorName string, connectorUrl string) (*jolokia.ResponseData, error) {
+
+	url := "org.apache.activemq.artemis:broker=\\\"" + artemis.name + "\\\""
+	parameters := quoteArgument(connectorName) + `,` + quoteArgument(connectorUrl)
+	jsonStr := `{ "type":"EXEC","mbean":"` + url + `","operation":"addConnector(java.lang.String,java.lang.String)","arguments":[` + parameters + `]` + ` }`
+	data, err := artemis.jolokia.Exec(url, jsonStr)
+
+	return data, err
+}
+
+func (artemis *Artemis) RemoveConnector(connectorName string) (*jolokia.ResponseData, error) {
+
+	url := "org.apache.activemq.artemis:broker=\\\"" + artemis.name + "\\\""
+	parameters := quoteArgument(connectorName)
+	jsonStr := `{ "type":"EXEC","mbean":"` + url + `","operation":"removeConnector(java.lang.String)","arguments":[` + parameters + `]` + ` }`
 	data, err := artemis.jolokia.Exec(url, jsonStr)
 
 	return data, err
 }
+
+// CreateBridge creates a core bridge that forwards the messages of a queue to
+// an address through a static connector, the producer window size limits the
+// bytes in flight to the target
+func (artemis *Artemis) CreateBridge(bridgeName string, queueName string, forwardingAddress string, connectorName string, producerWindowSize int32, user string, password string) (*jolokia.ResponseData, error) {
+
+	url := "org.apache.activemq.artemis:broker=\\\"" + artemis.name + "\\\""
+	parameters := quoteArgument(bridgeName) + `,` + quoteArgument(queueName) + `,` + quoteArgument(forwardingAddress) + `,null,null,` +
+		`2000,1.0,-1,-1,true,1048576,` + strconv.Itoa(int(producerWindowSize)) + `,30000,` + quoteArgument(connectorName) + `,false,false,` + quoteArgument(user) + `,` + quoteArgument(password)
+	jsonStr := `{ "type":"EXEC","mbean":"` + url + `","operation":"createBridge(java.lang.String,java.lang.String,java.lang.String,java.lang.String,java.lang.String,long,double,int,int,boolean,int,int,long,java.lang.String,boolean,boolean,java.lang.String,java.lang.String)","arguments":[` + parameters + `]` + ` }`
+	data, err := artemis.jolokia.Exec(url, jsonStr)
+
+	return data, err
+}
+
+func (artemis *Artemis) DestroyBridge(bridgeName string) (*jolokia.ResponseData, error) {
+
+	url := "org.apache.activemq.artemis:broker=\\\"" + artemis.name + "\\\""
+	parameters := quoteArgument(bridgeName)
+	jsonStr := `{ "type":"EXEC","mbean":"` + url + `","operation":"destroyBridge(java.lang.String)","arguments":[` + parameters + `]` + ` }`
+	data, err := artemis.jolokia.Exec(url, jsonStr)
+
+	return data, err
+}
+
+// BridgeConfiguration is a core bridge that consumes from QueueName and sends to the broker of
+// ConnectorName, which has to be added to the broker first
+type BridgeConfiguration struct {
+	Name                    string
+	QueueName               string
+	ForwardingAddress       string
+	Filter                  string
+	TransformerClassName    string
+	RetryInterval           int64
+	RetryIntervalMultiplier float64
+	InitialConnectAttempts  int32
+	ReconnectAttempts       int32
+	UseDuplicateDetection   bool
+	ProducerWindowSize      int32
+	ConnectorName           string
+	User                    string
+	Password                string
+}
+
+// CreateBridgeFromConfiguration creates a bridge with the retry settings of the configuration, unlike
+// CreateBridge that uses the defaults of the broker
+func (artemis *Artemis) CreateBridgeFromConfiguration(config BridgeConfiguration) (*jolokia.ResponseData, error) {
+
+	url := "org.apache.activemq.artemis:broker=\\\"" + artemis.name + "\\\""
+	parameters := quoteArgument(config.Name) + `,` + quoteArgument(config.QueueName) + `,` + quoteArgument(config.ForwardingAddress) + `,` +
+		quoteArgument(config.Filter) + `,` + quoteArgument(config.TransformerClassName) + `,` +
+		strconv.FormatInt(config.RetryInterval, 10) + `,` + strconv.FormatFloat(config.RetryIntervalMultiplier, 'f', -1, 64) + `,` +
+		strconv.Itoa(int(config.InitialConnectAttempts)) + `,` + strconv.Itoa(int(config.ReconnectAttempts)) + `,` +
+		strconv.FormatBool(config.UseDuplicateDetection) + `,1048576,` + strconv.Itoa(int(config.ProducerWindowSize)) + `,30000,` +
+		quoteArgument(config.ConnectorName) + `,false,false,` + quoteArgument(config.User) + `,` + quoteArgument(config.Password)
+	jsonStr := `{ "type":"EXEC","mbean":"` + url + `","operation":"createBridge(java.lang.String,java.lang.String,java.lang.String,java.lang.String,java.lang.String,long,double,int,int,boolean,int,int,long,java.lang.String,boolean,boolean,java.lang.String,java.lang.String)","arguments":[` + parameters + `]` + ` }`
+	data, err := artemis.jolokia.Exec(url, jsonStr)
+
+	return data, err
+}
+
+// ListBridgeNames lists the names of the bridges on the broker
+func (artemis *Artemis) ListBridgeNames() ([]string, error) {
+	url := "org.apache.activemq.artemis:broker=\"" + artemis.name + "\"/BridgeNames"
+	resp, err := artemis.jolokia.Read(url)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil || resp.Status != 200 {
+		return nil, fmt.Errorf("unable to read %v", url)
+	}
+	// the names come formatted as [name1 name2]
+	return strings.Fields(strings.Trim(resp.Value, "[]")), nil
+}
+
+// CreateDivert creates a divert that forwards the messages sent to address to forwardingAddress, an
+// exclusive divert takes them away from address. routingType is STRIP, PASS, ANYCAST or MULTICAST
+func (artemis *Artemis) CreateDivert(divertName string, routingName string, address string, forwardingAddress string, exclusive bool, filter string, transformerClassName string, transformerProperties map[string]string, routingType string) (*jolokia.ResponseData, error) {
+
+	url := "org.apache.activemq.artemis:broker=\\\"" + artemis.name + "\\\""
+	properties, err := json.Marshal(transformerProperties)
+	if err != nil {
+		return nil, err
+	}
+	parameters := quoteArgument(divertName) + `,` + quoteArgument(routingName) + `,` + quoteArgument(address) + `,` + quoteArgument(forwardingAddress) + `,` +
+		strconv.FormatBool(exclusive) + `,` + quoteArgument(filter) + `,` + quoteArgument(transformerClassName) + `,` + string(properties) + `,` + quoteArgument(routingType)
+	jsonStr := `{ "type":"EXEC","mbean":"` + url + `","operation":"createDivert(java.lang.String,java.lang.String,java.lang.String,java.lang.String,boolean,java.lang.String,java.lang.String,java.util.Map,java.lang.String)","arguments":[` + parameters + `]` + ` }`
+	data, err := artemis.jolokia.Exec(url, jsonStr)
+
+	return data, err
+}
+
+func (artemis *Artemis) DestroyDivert(divertName string) (*jolokia.ResponseData, error) {
+
+	url := "org.apache.activemq.artemis:broker=\\\"" + artemis.name + "\\\""
+	parameters := quoteArgument(divertName)
+	jsonStr := `{ "type":"EXEC","mbean":"` + url + `","operation":"destroyDivert(java.lang.String)","arguments":[` + parameters + `]` + ` }`
+	data, err := artemis.jolokia.Exec(url, jsonStr)
+
+	return data, err
+}
+
+// ListDivertNames lists the names of the diverts on the broker
+func (artemis *Artemis) ListDivertNames() ([]string, error) {
+	url := "org.apache.activemq.artemis:broker=\"" + artemis.name + "\"/DivertNames"
+	resp, err := artemis.jolokia.Read(url)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil || resp.Status != 200 {
+		return nil, fmt.Errorf("unable to read %v", url)
+	}
+	// the names come formatted as [name1 name2]
+	return strings.Fields(strings.Trim(resp.Value, "[]")), nil
+}
+
+// SetLoggerLevel changes the level of a log4j2 logger of the broker, it reports false when the
+// logger is not configured yet, log4j2 only registers the loggers of its configuration file
+func (artemis *Artemis) SetLoggerLevel(category string, level string) (bool, error) {
+	pattern := "org.apache.logging.log4j2:type=*,component=Loggers,name=" + category
+	jsonStr := `{ "type":"SEARCH","mbean":` + quoteArgument(pattern) + ` }`
+	data, err := artemis.jolokia.Exec(pattern, jsonStr)
+	if err != nil {
+		return false, err
+	}
+	if data == nil || data.Status != 200 {
+		return false, fmt.Errorf("unable to search %v", pattern)
+	}
+	// the names come formatted as [name1 name2]
+	names := strings.Fields(strings.Trim(data.Value, "[]"))
+	if len(names) == 0 {
+		return false, nil
+	}
+	writes := make([]string, len(names))
+	for i, name := range names {
+		writes[i] = `{ "type":"WRITE","mbean":` + quoteArgument(name) + `,"attribute":"Level","value":` + quoteArgument(level) + ` }`
+	}
+	responses, err := artemis.jolokia.ExecBulk(writes)
+	if err != nil {
+		return false, err
+	}
+	for _, response := range responses {
+		if response.Status != 200 {
+			return false, fmt.Errorf("unable to set the level of %v, %v", category, response.Error)
+		}
+	}
+	return true, nil
+}
+
+// quoteArgument is a string argument of an operation, empty is passed as null
+func quoteArgument(value string) string {
+	if value == "" {
+		return "null"
+	}
+	quoted, _ := json.Marshal(value)
+	return string(quoted)
+}
+
+// AddAddressSettings sets the address settings of the match, settings is a json object with the
+// settings to set, the ones it leaves out keep the value of the less specific matches
+func (artemis *Artemis) AddAddressSettings(addressMatch string, settings string) (*jolokia.ResponseData, error) {
+
+	url := "org.apache.activemq.artemis:broker=\\\"" + artemis.name + "\\\""
+	parameters := `"` + addressMatch + `",` + settings
+	jsonStr := `{ "type":"EXEC","mbean":"` + url + `","operation":"addAddressSettings(java.lang.String,java.lang.String)","arguments":[` + parameters + `]` + ` }`
+	data, err := artemis.jolokia.Exec(url, jsonStr)
+
+	return data, err
+}
+
+func (artemis *Artemis) RemoveAddressSettings(addressMatch string) (*jolokia.ResponseData, error) {
+
+	url := "org.apache.activemq.artemis:broker=\\\"" + artemis.name + "\\\""
+	data, err := artemis.jolokia.Exec(url, artemis.RemoveAddressSettingsOperation(addressMatch))
+
+	return data, err
+}
+
+// RemoveAddressSettingsOperation is the request of RemoveAddressSettings, for ExecBulk
+func (artemis *Artemis) RemoveAddressSettingsOperation(addressMatch string) string {
+	url := "org.apache.activemq.artemis:broker=\\\"" + artemis.name + "\\\""
+	parameters := `"` + addressMatch + `"`
+	return `{ "type":"EXEC","mbean":"` + url + `","operation":"removeAddressSettings(java.lang.String)","arguments":[` + parameters + `]` + ` }`
+}
+
+// AddUser adds a user to the properties login module of the broker, roles is a comma separated list
+func (artemis *Artemis) AddUser(userName string, password string, roles string) (*jolokia.ResponseData, error) {
+
+	url := "org.apache.activemq.artemis:broker=\\\"" + artemis.name + "\\\""
+	parameters := `"` + userName + `","` + password + `","` + roles + `",true`
+	jsonStr := `{ "type":"EXEC","mbean":"` + url + `","operation":"addUser(java.lang.String,java.lang.String,java.lang.String,boolean)","arguments":[` + parameters + `]` + ` }`
+	data, err := artemis.jolokia.Exec(url, jsonStr)
+
+	return data, err
+}
+
+func (artemis *Artemis) RemoveUser(userName string) (*jolokia.ResponseData, error) {
+
+	url := "org.apache.activemq.artemis:broker=\\\"" + artemis.name + "\\\""
+	parameters := `"` + userName + `"`
+	jsonStr := `{ "type":"EXEC","mbean":"` + url + `","operation":"removeUser(java.lang.String)","arguments":[` + parameters + `]` + ` }`
+	data, err := artemis.jolokia.Exec(url, jsonStr)
+
+	return data, err
+}
+
+// GetClusterTopologySize counts the live brokers of the cluster the broker knows of, itself included
+func (artemis *Artemis) GetClusterTopologySize() (int, error) {
+
+	url := "org.apache.activemq.artemis:broker=\\\"" + artemis.name + "\\\""
+	jsonStr := `{ "type":"EXEC","mbean":"` + url + `","operation":"listNetworkTopology()","arguments":[]` + ` }`
+	data, err := artemis.jolokia.Exec(url, jsonStr)
+	if err != nil {
+		return 0, err
+	}
+	if data == nil || data.Status != 200 {
+		return 0, fmt.Errorf("unable to list the network topology")
+	}
+	members := []map[string]interface{}{}
+	if err := json.Unmarshal([]byte(data.Value), &members); err != nil {
+		return 0, err
+	}
+	live := 0
+	for _, member := range members {
+		if _, found := member["live"]; found {
+			live++
+		}
+	}
+	return live, nil
+}
@@ -109,6 +109,38 @@ func TestGetStatusWithNilStatus(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestIsNotFoundError(t *testing.T) {
+	assert.False(t, IsNotFoundError(nil))
+	assert.False(t, IsNotFoundError(&jolokia.ResponseData{Error: "AMQ229019: Queue q1 already exists"}))
+	assert.True(t, IsNotFoundError(&jolokia.ResponseData{Error: "javax.management.MBeanException : AMQ229017: Queue q1 does not exist"}))
+	assert.True(t, IsNotFoundError(&jolokia.ResponseData{Error: "javax.management.MBeanException : AMQ229203: Address Does Not Exist: a1"}))
+}
+
+func TestDeleteQueueNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	j := jolokia.NewMockIJolokia(ctrl)
+
+	artemis := createMockArtemis(j)
+
+	j.
+		EXPECT().
+		Exec(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ string, _ string) (*jolokia.ResponseData, error) {
+			return &jolokia.ResponseData{
+				Status:    500,
+				ErrorType: "javax.management.MBeanException",
+				Error:     "javax.management.MBeanException : AMQ229017: Queue q1 does not exist",
+			}, fmt.Errorf("javax.management.MBeanException")
+		}).
+		Times(1)
+	data, err := artemis.DeleteQueue("q1")
+
+	assert.Error(t, err)
+	assert.True(t, IsNotFoundError(data))
+}
+
 func createMockArtemis(j jolokia.IJolokia) Artemis {
 	return Artemis{
 		ip:          "0.0.0.0",
@@ -117,3 +149,283 @@ func createMockArtemis(j jolokia.IJolokia) Artemis {
 		jolokia:     j,
 	}
 }
+
+func TestGetQueueMessageCount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	j := jolokia.NewMockIJolokia(ctrl)
+
+	artemis := createMockArtemis(j)
+
+	j.
+		EXPECT().
+		Read(gomock.Eq("org.apache.activemq.artemis:broker=\"someBroker\",component=addresses,address=\"a1\",subcomponent=queues,routing-type=\"anycast\",queue=\"q1\"/MessageCount")).
+		DoAndReturn(func(_ string) (*jolokia.ResponseData, error) {
+			return &jolokia.ResponseData{
+				Status: 200,
+				Value:  "42",
+			}, nil
+		}).
+		Times(1)
+	count, err := artemis.GetQueueMessageCount("a1", "ANYCAST", "q1")
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(42), count)
+}
+
+func TestGetClusterTopologySize(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	j := jolokia.NewMockIJolokia(ctrl)
+
+	artemis := createMockArtemis(j)
+
+	j.
+		EXPECT().
+		Exec(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ string, body string) (*jolokia.ResponseData, error) {
+			assert.Contains(t, body, `"operation":"listNetworkTopology()"`)
+			return &jolokia.ResponseData{
+				Status: 200,
+				Value:  `[{"nodeID":"a","live":"broker-ss-0:61616"},{"nodeID":"b","live":"broker-ss-1:61616","backup":"broker-ss-2:61616"}]`,
+			}, nil
+		}).
+		Times(1)
+	size, err := artemis.GetClusterTopologySize()
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, size)
+}
+
+func TestUpdateAddress(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	j := jolokia.NewMockIJolokia(ctrl)
+
+	artemis := createMockArtemis(j)
+
+	j.
+		EXPECT().
+		Exec(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ string, body string) (*jolokia.ResponseData, error) {
+			assert.Contains(t, body, `"operation":"updateAddress(java.lang.String,java.lang.String)"`)
+			assert.Contains(t, body, `"arguments":["orders","ANYCAST,MULTICAST"]`)
+			return &jolokia.ResponseData{Status: 200}, nil
+		}).
+		Times(1)
+	_, err := artemis.UpdateAddress("orders", "anycast,multicast")
+
+	assert.Nil(t, err)
+}
+
+func TestCreateDivert(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	j := jolokia.NewMockIJolokia(ctrl)
+
+	artemis := createMockArtemis(j)
+
+	j.
+		EXPECT().
+		Exec(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ string, body string) (*jolokia.ResponseData, error) {
+			assert.Contains(t, body, `"operation":"createDivert(java.lang.String,java.lang.String,java.lang.String,java.lang.String,boolean,java.lang.String,java.lang.String,java.util.Map,java.lang.String)"`)
+			assert.Contains(t, body, `"arguments":["priority","priority","orders","orders.priority",true,"color = \"red\"",null,null,"STRIP"]`)
+			return &jolokia.ResponseData{Status: 200}, nil
+		}).
+		Times(1)
+	_, err := artemis.CreateDivert("priority", "priority", "orders", "orders.priority", true, `color = "red"`, "", nil, "STRIP")
+
+	assert.Nil(t, err)
+}
+
+func TestCreateBridgeFromConfiguration(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	j := jolokia.NewMockIJolokia(ctrl)
+
+	artemis := createMockArtemis(j)
+
+	j.
+		EXPECT().
+		Exec(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ string, body string) (*jolokia.ResponseData, error) {
+			assert.Contains(t, body, `"operation":"createBridge(java.lang.String,java.lang.String,java.lang.String,java.lang.String,java.lang.String,long,double,int,int,boolean,int,int,long,java.lang.String,boolean,boolean,java.lang.String,java.lang.String)"`)
+			assert.Contains(t, body, `"arguments":["to-dc2","orders",null,null,null,5000,1.5,-1,10,true,1048576,-1,30000,"to-dc2-connector",false,false,"bridge","secret"]`)
+			return &jolokia.ResponseData{Status: 200}, nil
+		}).
+		Times(1)
+	_, err := artemis.CreateBridgeFromConfiguration(BridgeConfiguration{
+		Name:                    "to-dc2",
+		QueueName:               "orders",
+		RetryInterval:           5000,
+		RetryIntervalMultiplier: 1.5,
+		InitialConnectAttempts:  -1,
+		ReconnectAttempts:       10,
+		UseDuplicateDetection:   true,
+		ProducerWindowSize:      -1,
+		ConnectorName:           "to-dc2-connector",
+		User:                    "bridge",
+		Password:                "secret",
+	})
+
+	assert.Nil(t, err)
+}
+
+func TestCreateBridgeQuotesCredentials(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	j := jolokia.NewMockIJolokia(ctrl)
+
+	artemis := createMockArtemis(j)
+
+	j.
+		EXPECT().
+		Exec(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ string, body string) (*jolokia.ResponseData, error) {
+			assert.Contains(t, body, `30000,"move-connector",false,false,"cluster","p\"a\\ss"]`)
+			return &jolokia.ResponseData{Status: 200}, nil
+		}).
+		Times(1)
+	_, err := artemis.CreateBridge("move-bridge", "orders", "orders", "move-connector", 1048576, "cluster", `p"a\ss`)
+
+	assert.Nil(t, err)
+}
+
+func TestListAddresses(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	j := jolokia.NewMockIJolokia(ctrl)
+
+	artemis := createMockArtemis(j)
+
+	j.
+		EXPECT().
+		Exec(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ string, body string) (*jolokia.ResponseData, error) {
+			assert.Contains(t, body, `"operation":"listAddresses(java.lang.String)","arguments":[","]`)
+			return &jolokia.ResponseData{Status: 200, Value: "orders,DLQ,activemq.notifications"}, nil
+		}).
+		Times(1)
+	addresses, err := artemis.ListAddresses()
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"orders", "DLQ", "activemq.notifications"}, addresses)
+}
+
+func TestGetTotalPersistentSize(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	j := jolokia.NewMockIJolokia(ctrl)
+
+	artemis := createMockArtemis(j)
+
+	j.
+		EXPECT().
+		Read(gomock.Eq("org.apache.activemq.artemis:broker=\"someBroker\",component=addresses,address=*,subcomponent=queues,routing-type=*,queue=*/PersistentSize")).
+		Return(&jolokia.ResponseData{Status: 200, Value: `{"org.apache.activemq.artemis:address=\"orders\",broker=\"someBroker\",component=addresses,queue=\"orders\",routing-type=\"anycast\",subcomponent=queues":{"PersistentSize":4096},` +
+			`"org.apache.activemq.artemis:address=\"DLQ\",broker=\"someBroker\",component=addresses,queue=\"DLQ\",routing-type=\"anycast\",subcomponent=queues":{"PersistentSize":1024}}`}, nil).
+		Times(1)
+	size, err := artemis.GetTotalPersistentSize()
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(5120), size)
+}
+
+func TestListDivertNames(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	j := jolokia.NewMockIJolokia(ctrl)
+
+	artemis := createMockArtemis(j)
+
+	j.
+		EXPECT().
+		Read(gomock.Eq("org.apache.activemq.artemis:broker=\"someBroker\"/DivertNames")).
+		DoAndReturn(func(_ string) (*jolokia.ResponseData, error) {
+			return &jolokia.ResponseData{Status: 200, Value: "[priority audit]"}, nil
+		}).
+		Times(1)
+	names, err := artemis.ListDivertNames()
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"priority", "audit"}, names)
+}
+
+func TestExecBulk(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	j := jolokia.NewMockIJolokia(ctrl)
+
+	artemis := createMockArtemis(j)
+
+	operations := []string{
+		artemis.CreateAddressOperation("orders", "anycast,multicast"),
+		artemis.CreateQueueFromConfigOperation(`"{\"name\":\"orders\"}"`, false),
+		artemis.DeleteQueueOperation("returns"),
+	}
+	j.
+		EXPECT().
+		ExecBulk(gomock.Eq(operations)).
+		DoAndReturn(func(requests []string) ([]*jolokia.ResponseData, error) {
+			assert.Contains(t, requests[0], `"operation":"createAddress(java.lang.String,java.lang.String)","arguments":["orders","ANYCAST,MULTICAST"]`)
+			assert.Contains(t, requests[1], `"operation":"createQueue(java.lang.String,boolean)"`)
+			assert.Contains(t, requests[2], `"operation":"destroyQueue(java.lang.String)","arguments":["returns"]`)
+			return []*jolokia.ResponseData{{Status: 200}, {Status: 200}, {Status: 404}}, nil
+		}).
+		Times(1)
+	responses, err := artemis.ExecBulk(operations)
+
+	assert.Nil(t, err)
+	assert.Len(t, responses, 3)
+}
+
+func TestSetLoggerLevel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	j := jolokia.NewMockIJolokia(ctrl)
+
+	artemis := createMockArtemis(j)
+
+	j.
+		EXPECT().
+		Exec(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ string, body string) (*jolokia.ResponseData, error) {
+			assert.Contains(t, body, `"type":"SEARCH","mbean":"org.apache.logging.log4j2:type=*,component=Loggers,name=org.apache.activemq.artemis.core.server"`)
+			return &jolokia.ResponseData{Status: 200, Value: "[org.apache.logging.log4j2:type=5c647e05,component=Loggers,name=org.apache.activemq.artemis.core.server]"}, nil
+		}).
+		Times(1)
+	j.
+		EXPECT().
+		ExecBulk(gomock.Any()).
+		DoAndReturn(func(bodies []string) ([]*jolokia.ResponseData, error) {
+			assert.Equal(t, []string{`{ "type":"WRITE","mbean":"org.apache.logging.log4j2:type=5c647e05,component=Loggers,name=org.apache.activemq.artemis.core.server","attribute":"Level","value":"DEBUG" }`}, bodies)
+			return []*jolokia.ResponseData{{Status: 200}}, nil
+		}).
+		Times(1)
+	set, err := artemis.SetLoggerLevel("org.apache.activemq.artemis.core.server", "DEBUG")
+
+	assert.Nil(t, err)
+	assert.True(t, set)
+
+	j.
+		EXPECT().
+		Exec(gomock.Any(), gomock.Any()).
+		Return(&jolokia.ResponseData{Status: 200, Value: "[]"}, nil).
+		Times(1)
+	set, err = artemis.SetLoggerLevel("org.example.unknown", "DEBUG")
+
+	assert.Nil(t, err)
+	assert.False(t, set)
+}
@@ -18,8 +18,12 @@ const (
 	RouteKind              = "Route"
 	OpenShiftAPIServerKind = "OpenShiftAPIServer"
 	DEFAULT_RESYNC_PERIOD  = 30 * time.Second
+	// the time between two resyncs released to the work queue
+	DEFAULT_RESYNC_INTERVAL = 100 * time.Millisecond
 	// comments push this over the edge a little when dealing with white space
 	// as en env var it can be disabled by setting to "" or can be improved!
+	VersionCompatibilityPolicyEnforce = "enforce"
+	VersionCompatibilityPolicyWarn    = "warn"
 	JaasConfigSyntaxMatchRegExDefault = `^(?:(\s*|(?://.*)|(?s:/\*.*\*/))*\S+\s*{(?:(\s*|(?://.*)|(?s:/\*.*\*/))*\S+\s+(?i:required|optional|sufficient|requisite)+(?:\s*\S+=\S+\s*)*\s*;)+(\s*|(?://.*)|(?s:/\*.*\*/))*}\s*;)+\s*\z`
 )
 
@@ -27,8 +31,12 @@ var theManager manager.Manager
 
 var resyncPeriod time.Duration = DEFAULT_RESYNC_PERIOD
 
+var resyncInterval time.Duration = DEFAULT_RESYNC_INTERVAL
+
 var jaasConfigSyntaxMatchRegEx = JaasConfigSyntaxMatchRegExDefault
 
+var versionCompatibilityPolicy = VersionCompatibilityPolicyWarn
+
 func init() {
 	if period, defined := os.LookupEnv("RECONCILE_RESYNC_PERIOD"); defined {
 		var err error
@@ -39,11 +47,32 @@ func init() {
 		resyncPeriod = DEFAULT_RESYNC_PERIOD
 	}
 
+	if interval, defined := os.LookupEnv("RECONCILE_RESYNC_INTERVAL"); defined {
+		var err error
+		if resyncInterval, err = time.ParseDuration(interval); err != nil || resyncInterval <= 0 {
+			resyncInterval = DEFAULT_RESYNC_INTERVAL
+		}
+	}
+
 	if regEx, defined := os.LookupEnv("JAAS_CONFIG_SYNTAX_MATCH_REGEX"); defined {
 		jaasConfigSyntaxMatchRegEx = regEx
 	} else {
 		jaasConfigSyntaxMatchRegEx = JaasConfigSyntaxMatchRegExDefault
 	}
+
+	if policy, defined := os.LookupEnv("VERSION_COMPATIBILITY_POLICY"); defined && strings.EqualFold(policy, VersionCompatibilityPolicyEnforce) {
+		versionCompatibilityPolicy = VersionCompatibilityPolicyEnforce
+	}
+}
+
+// how brokers with images outside the supported versions are treated, enforce marks them invalid
+// and warn only reports them
+func GetVersionCompatibilityPolicy() string {
+	return versionCompatibilityPolicy
+}
+
+func SetVersionCompatibilityPolicy(policy string) {
+	versionCompatibilityPolicy = policy
 }
 
 func GetJaasConfigSyntaxMatchRegEx() string {
@@ -54,6 +83,10 @@ func GetReconcileResyncPeriod() time.Duration {
 	return resyncPeriod
 }
 
+func GetReconcileResyncInterval() time.Duration {
+	return resyncInterval
+}
+
 type ActiveMQArtemisConfigHandler interface {
 	IsApplicableFor(brokerNamespacedName types.NamespacedName) bool
 	Config(initContainers []corev1.Container, outputDirRoot string, yacfgProfileVersion string, yacfgProfileName string) (value []string)
@@ -0,0 +1,192 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package configdiff shows how the effective configuration of the brokers changes when a pending
+// ActiveMQArtemis CR is applied, as a unified diff of the rendered broker properties, acceptors and
+// connectors against the ones the brokers run with
+package configdiff
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	"github.com/pmezard/go-difflib/difflib"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	"sigs.k8s.io/yaml"
+)
+
+// Command is the first argument of the operator binary that runs the diff rather than the manager
+const Command = "config-diff"
+
+// Configuration reads one side of the diff, keyed like the data of the secrets the configuration
+// reaches the brokers with
+type Configuration func(*brokerv1beta1.ActiveMQArtemis, client.Client) (map[string]string, error)
+
+type Options struct {
+	Filename  string
+	Namespace string
+}
+
+func (options *Options) BindFlags(flags *flag.FlagSet) {
+	flags.StringVar(&options.Filename, "f", "", "The file with the pending custom resource, - reads it from the standard input.")
+	flags.StringVar(&options.Namespace, "namespace", "", "The namespace of the custom resource when the file has none, defaults to default.")
+}
+
+var passwordPattern = regexp.MustCompile(`(?i)(password=)[^;&\s<"]*`)
+
+// Run writes the diff between the applied and the pending configuration to out, it reports whether
+// anything changes
+func Run(args []string, in io.Reader, out io.Writer, applied Configuration, pending Configuration) (bool, error) {
+	options := &Options{}
+	flags := flag.NewFlagSet(Command, flag.ContinueOnError)
+	flags.SetOutput(out)
+	options.BindFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return false, err
+	}
+	if options.Filename == "" {
+		return false, errors.New("-f is required")
+	}
+
+	cr, err := readCustomResource(options, in)
+	if err != nil {
+		return false, err
+	}
+	kubeClient, err := newClient()
+	if err != nil {
+		return false, err
+	}
+
+	// the applied configuration is found through the owner references of the deployed cr
+	deployed := &brokerv1beta1.ActiveMQArtemis{}
+	err = kubeClient.Get(context.TODO(), types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name}, deployed)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return false, err
+	}
+	cr.UID = deployed.UID
+
+	before, err := applied(cr, kubeClient)
+	if err != nil {
+		return false, fmt.Errorf("unable to read the applied configuration: %v", err)
+	}
+	after, err := pending(cr, kubeClient)
+	if err != nil {
+		return false, fmt.Errorf("unable to render the pending configuration: %v", err)
+	}
+
+	diff, err := Diff(before, after)
+	if err != nil {
+		return false, err
+	}
+	_, err = io.WriteString(out, diff)
+	return diff != "", err
+}
+
+// Diff is a unified diff per key, with passwords masked and every acceptor and connector on its own line
+func Diff(before map[string]string, after map[string]string) (string, error) {
+	keys := []string{}
+	for key := range before {
+		keys = append(keys, key)
+	}
+	for key := range after {
+		if _, found := before[key]; !found {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	diff := &strings.Builder{}
+	for _, key := range keys {
+		text, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        lines(before[key]),
+			B:        lines(after[key]),
+			FromFile: "applied/" + key,
+			ToFile:   "pending/" + key,
+			Context:  3,
+		})
+		if err != nil {
+			return "", err
+		}
+		diff.WriteString(text)
+	}
+	return diff.String(), nil
+}
+
+func lines(value string) []string {
+	if value == "" {
+		return nil
+	}
+	value = passwordPattern.ReplaceAllString(value, "${1}*****")
+	value = strings.ReplaceAll(value, "</acceptor><", "</acceptor>\n<")
+	value = strings.ReplaceAll(value, "</connector><", "</connector>\n<")
+	return difflib.SplitLines(strings.TrimSuffix(value, "\n"))
+}
+
+func readCustomResource(options *Options, in io.Reader) (*brokerv1beta1.ActiveMQArtemis, error) {
+	var document []byte
+	var err error
+	if options.Filename == "-" {
+		document, err = io.ReadAll(in)
+	} else {
+		document, err = os.ReadFile(options.Filename)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cr := &brokerv1beta1.ActiveMQArtemis{}
+	if err := yaml.UnmarshalStrict(document, cr); err != nil {
+		return nil, fmt.Errorf("unable to read the custom resource: %v", err)
+	}
+	if cr.Kind != "ActiveMQArtemis" || cr.Name == "" {
+		return nil, errors.New("the file must hold a single named ActiveMQArtemis")
+	}
+	if cr.Namespace == "" {
+		cr.Namespace = options.Namespace
+	}
+	if cr.Namespace == "" {
+		cr.Namespace = "default"
+	}
+	return cr, nil
+}
+
+func newClient() (client.Client, error) {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the kube config: %v", err)
+	}
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	if err := brokerv1beta1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	return client.New(cfg, client.Options{Scheme: scheme})
+}
@@ -0,0 +1,53 @@
+package configdiff
+
+import (
+	"io"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestConfigDiff(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Config Diff Suite")
+}
+
+var _ = Describe("Config diff", func() {
+
+	It("is empty when nothing changes", func() {
+		configuration := map[string]string{"broker.properties": "a=1\nb=2\n"}
+		diff, err := Diff(configuration, configuration)
+		Expect(err).To(BeNil())
+		Expect(diff).To(BeEmpty())
+	})
+
+	It("shows changed, added and removed keys", func() {
+		diff, err := Diff(
+			map[string]string{"broker.properties": "a=1\nb=2\n", "broker-0.broker.properties": "c=3\n"},
+			map[string]string{"broker.properties": "a=1\nb=3\n", "AMQ_ACCEPTORS": "<acceptor name=\"amqp\">tcp:\\/\\/ACCEPTOR_IP:5672</acceptor>"},
+		)
+		Expect(err).To(BeNil())
+		Expect(diff).To(ContainSubstring("--- applied/broker.properties\n+++ pending/broker.properties\n"))
+		Expect(diff).To(ContainSubstring("-b=2\n+b=3\n"))
+		Expect(diff).To(ContainSubstring("+++ pending/AMQ_ACCEPTORS\n"))
+		Expect(diff).To(ContainSubstring("-c=3\n"))
+	})
+
+	It("masks passwords and puts every acceptor on its own line", func() {
+		diff, err := Diff(
+			map[string]string{"AMQ_ACCEPTORS": "<acceptor name=\"a\">tcp:\\/\\/ACCEPTOR_IP:5671?sslEnabled=true;keyStorePassword=secret;port=5671</acceptor><acceptor name=\"b\">tcp:\\/\\/ACCEPTOR_IP:61616</acceptor>"},
+			map[string]string{"AMQ_ACCEPTORS": "<acceptor name=\"a\">tcp:\\/\\/ACCEPTOR_IP:5671?sslEnabled=true;keyStorePassword=other;port=5671</acceptor><acceptor name=\"b\">tcp:\\/\\/ACCEPTOR_IP:61617</acceptor>"},
+		)
+		Expect(err).To(BeNil())
+		Expect(diff).NotTo(ContainSubstring("secret"))
+		Expect(diff).NotTo(ContainSubstring("other"))
+		Expect(diff).To(ContainSubstring(" <acceptor name=\"a\">tcp:\\/\\/ACCEPTOR_IP:5671?sslEnabled=true;keyStorePassword=*****;port=5671</acceptor>\n"))
+		Expect(diff).To(ContainSubstring("-<acceptor name=\"b\">tcp:\\/\\/ACCEPTOR_IP:61616</acceptor>"))
+	})
+
+	It("needs a file", func() {
+		_, err := Run([]string{}, nil, io.Discard, nil, nil)
+		Expect(err).NotTo(BeNil())
+	})
+})
@@ -0,0 +1,147 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package endpoints
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	dialTimeout   = 5 * time.Second
+	recheckPeriod = 30 * time.Second
+	// an endpoint no CR asked for in this long is forgotten, it was removed or changed
+	evictPeriod = 10 * time.Minute
+)
+
+// Endpoint is a host and port that clients outside the cluster connect to
+type Endpoint struct {
+	Host string
+	Port int32
+	TLS  bool
+}
+
+func (e Endpoint) Address() string {
+	return net.JoinHostPort(e.Host, strconv.Itoa(int(e.Port)))
+}
+
+type Result struct {
+	Reachable bool
+	Error     string
+}
+
+type entry struct {
+	result    Result
+	known     bool
+	probing   bool
+	checkedAt time.Time
+	askedAt   time.Time
+}
+
+// Prober checks endpoints in the background so a reconcile never waits on DNS or a handshake
+type Prober struct {
+	sync.Mutex
+	entries map[Endpoint]*entry
+	probe   func(Endpoint) Result
+}
+
+var singleton *Prober
+var once sync.Once
+
+func GetProber() *Prober {
+	once.Do(func() {
+		singleton = NewProber(dial)
+	})
+	return singleton
+}
+
+func NewProber(probe func(Endpoint) Result) *Prober {
+	return &Prober{entries: map[Endpoint]*entry{}, probe: probe}
+}
+
+// Check returns the last result for the endpoint, known is false until the first probe completes.
+// A probe is started when there is no result yet or the last one is stale.
+func (p *Prober) Check(endpoint Endpoint) (result Result, known bool) {
+	p.Lock()
+	defer p.Unlock()
+
+	p.evict()
+	e, ok := p.entries[endpoint]
+	if !ok {
+		e = &entry{}
+		p.entries[endpoint] = e
+	}
+	e.askedAt = time.Now()
+	if !e.probing && (!e.known || time.Since(e.checkedAt) > recheckPeriod) {
+		e.probing = true
+		go p.run(endpoint, e)
+	}
+	return e.result, e.known
+}
+
+// evict drops the entries nobody checks anymore, a running probe keeps its entry until it completes
+func (p *Prober) evict() {
+	for endpoint, e := range p.entries {
+		if !e.probing && time.Since(e.askedAt) > evictPeriod {
+			delete(p.entries, endpoint)
+		}
+	}
+}
+
+func (p *Prober) run(endpoint Endpoint, e *entry) {
+	result := p.probe(endpoint)
+
+	p.Lock()
+	defer p.Unlock()
+	e.result = result
+	e.known = true
+	e.probing = false
+	e.checkedAt = time.Now()
+}
+
+func dial(endpoint Endpoint) Result {
+	ctx, cancel := context.WithTimeout(context.Background(), dialTimeout)
+	defer cancel()
+
+	if _, err := net.DefaultResolver.LookupHost(ctx, endpoint.Host); err != nil {
+		return Result{Error: err.Error()}
+	}
+
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	if !endpoint.TLS {
+		conn, err := dialer.DialContext(ctx, "tcp", endpoint.Address())
+		if err != nil {
+			return Result{Error: err.Error()}
+		}
+		conn.Close()
+		return Result{Reachable: true}
+	}
+
+	// only the handshake matters here, trust is between the client and the broker
+	conn, err := tls.DialWithDialer(dialer, "tcp", endpoint.Address(), &tls.Config{
+		ServerName:         endpoint.Host,
+		InsecureSkipVerify: true,
+	})
+	if err != nil {
+		return Result{Error: err.Error()}
+	}
+	conn.Close()
+	return Result{Reachable: true}
+}
@@ -0,0 +1,43 @@
+package endpoints
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProberEvictsUncheckedEndpoints(t *testing.T) {
+	probed := make(chan Endpoint, 2)
+	prober := NewProber(func(endpoint Endpoint) Result {
+		probed <- endpoint
+		return Result{Reachable: true}
+	})
+
+	removed := Endpoint{Host: "removed.example.com", Port: 61616}
+	kept := Endpoint{Host: "kept.example.com", Port: 61616}
+	prober.Check(removed)
+	prober.Check(kept)
+	<-probed
+	<-probed
+
+	// the probes complete before the entries are aged
+	assert.Eventually(t, func() bool {
+		prober.Lock()
+		defer prober.Unlock()
+		return !prober.entries[removed].probing && !prober.entries[kept].probing
+	}, time.Second, time.Millisecond)
+
+	prober.Lock()
+	prober.entries[removed].askedAt = time.Now().Add(-evictPeriod - time.Second)
+	prober.Unlock()
+
+	result, known := prober.Check(kept)
+	assert.True(t, known)
+	assert.True(t, result.Reachable)
+
+	prober.Lock()
+	defer prober.Unlock()
+	assert.NotContains(t, prober.entries, removed)
+	assert.Contains(t, prober.entries, kept)
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package envelope seals credentials the operator keeps for itself outside of secrets,
+// such as the cluster credentials on the ActiveMQArtemisScaledown it creates for message
+// migration.
+//
+// Threat model: the sealed values live in objects that are readable more widely than
+// secrets, and end up in backups and etcd dumps of custom resources. Anyone who can read
+// those but not the key secret learns nothing from them, and any change to a sealed
+// value makes it fail to open. The id of the key and the name of the annotation a value
+// is stored under are authenticated along with it, so a value moved to another annotation,
+// such as the user into the password, or relabelled with another key id fails to open too.
+// Anyone who can read the key secret, or the memory of the operator, can open every value.
+// Values are not protected against being replaced by an older value of the same annotation
+// sealed with a key that is still in the ring, so retired keys should be removed once values
+// have been re-sealed.
+//
+// Each value is sealed with its own random data key and the data key is sealed with the
+// active key of the ring, so rotating the active key only re-seals the small data keys.
+package envelope
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	prefix = "enc:v1:"
+
+	// ActiveKeyName is the entry of the key secret naming the key new values are sealed with
+	ActiveKeyName = "active"
+
+	keySize = 32
+)
+
+type KeyRing struct {
+	active string
+	keys   map[string][]byte
+}
+
+// NewKeyRing takes 32 byte keys by id, every entry other than active is a key
+func NewKeyRing(data map[string][]byte) (*KeyRing, error) {
+	active := string(data[ActiveKeyName])
+	if active == "" {
+		return nil, fmt.Errorf("the %v entry naming the active key is missing", ActiveKeyName)
+	}
+	keys := map[string][]byte{}
+	for id, key := range data {
+		if id == ActiveKeyName {
+			continue
+		}
+		if strings.Contains(id, ":") {
+			return nil, fmt.Errorf("key id %v must not contain ':'", id)
+		}
+		if len(key) != keySize {
+			return nil, fmt.Errorf("key %v must be %d bytes, it is %d", id, keySize, len(key))
+		}
+		keys[id] = key
+	}
+	if _, found := keys[active]; !found {
+		return nil, fmt.Errorf("the active key %v is not in the ring", active)
+	}
+	return &KeyRing{active: active, keys: keys}, nil
+}
+
+// LoadKeyRing reads the ring from the secret named by OPERATOR_ENCRYPTION_KEY_SECRET in the
+// operator namespace, a nil ring without error means sealing is not configured
+func LoadKeyRing(c client.Client) (*KeyRing, error) {
+	name := os.Getenv("OPERATOR_ENCRYPTION_KEY_SECRET")
+	if name == "" {
+		return nil, nil
+	}
+	secret := &corev1.Secret{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: os.Getenv("OPERATOR_NAMESPACE")}, secret); err != nil {
+		return nil, err
+	}
+	return NewKeyRing(secret.Data)
+}
+
+func IsSealed(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+// NeedsSealing is true for values in plain text and for values sealed with a key other than the active one
+func (r *KeyRing) NeedsSealing(value string) bool {
+	if !IsSealed(value) {
+		return true
+	}
+	return !strings.HasPrefix(value, prefix+r.active+":")
+}
+
+// Seal seals the value of the annotation with the name, it only opens for that name
+func (r *KeyRing) Seal(name string, plaintext string) (string, error) {
+	dataKey := make([]byte, keySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", err
+	}
+	additionalData := associatedData(r.active, name)
+	sealedKey, err := seal(r.keys[r.active], dataKey, additionalData)
+	if err != nil {
+		return "", err
+	}
+	sealedValue, err := seal(dataKey, []byte(plaintext), additionalData)
+	if err != nil {
+		return "", err
+	}
+	return prefix + r.active + ":" + base64.StdEncoding.EncodeToString(sealedKey) + ":" + base64.StdEncoding.EncodeToString(sealedValue), nil
+}
+
+// Open returns plain text values as they are so values stored before sealing was configured keep working
+func (r *KeyRing) Open(name string, value string) (string, error) {
+	if !IsSealed(value) {
+		return value, nil
+	}
+	parts := strings.Split(strings.TrimPrefix(value, prefix), ":")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("malformed sealed value")
+	}
+	key, found := r.keys[parts[0]]
+	if !found {
+		return "", fmt.Errorf("key %v is not in the ring", parts[0])
+	}
+	sealedKey, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", err
+	}
+	sealedValue, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", err
+	}
+	additionalData := associatedData(parts[0], name)
+	dataKey, err := open(key, sealedKey, additionalData)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(dataKey, sealedValue, additionalData)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// Reseal opens a value and seals it again with the active key when needed
+func (r *KeyRing) Reseal(name string, value string) (string, error) {
+	if !r.NeedsSealing(value) {
+		return value, nil
+	}
+	plaintext, err := r.Open(name, value)
+	if err != nil {
+		return "", err
+	}
+	return r.Seal(name, plaintext)
+}
+
+// associatedData binds a sealed value to the key id in front of it and to the annotation it is
+// stored under, a key id holds no ':' so the two can't be shifted into each other
+func associatedData(keyID string, name string) []byte {
+	return []byte(keyID + ":" + name)
+}
+
+func seal(key []byte, plaintext []byte, additionalData []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(key []byte, sealed []byte, additionalData []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, fmt.Errorf("sealed value is too short")
+	}
+	return aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], additionalData)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,134 @@
+package envelope
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestEnvelope(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Envelope Suite")
+}
+
+func key(b byte) []byte {
+	return bytes.Repeat([]byte{b}, keySize)
+}
+
+var _ = Describe("Envelope", func() {
+	Describe("NewKeyRing", func() {
+		It("requires the active entry", func() {
+			_, err := NewKeyRing(map[string][]byte{"one": key(1)})
+			Expect(err).To(HaveOccurred())
+		})
+		It("requires the active key to be in the ring", func() {
+			_, err := NewKeyRing(map[string][]byte{ActiveKeyName: []byte("two"), "one": key(1)})
+			Expect(err).To(HaveOccurred())
+		})
+		It("rejects keys of the wrong size", func() {
+			_, err := NewKeyRing(map[string][]byte{ActiveKeyName: []byte("one"), "one": []byte("short")})
+			Expect(err).To(HaveOccurred())
+		})
+		It("rejects key ids with a separator", func() {
+			_, err := NewKeyRing(map[string][]byte{ActiveKeyName: []byte("o:ne"), "o:ne": key(1)})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("sealing", func() {
+		var ring *KeyRing
+		BeforeEach(func() {
+			var err error
+			ring, err = NewKeyRing(map[string][]byte{ActiveKeyName: []byte("one"), "one": key(1)})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("opens what it sealed", func() {
+			sealed, err := ring.Seal("CLUSTERPASS", "s3cret")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(IsSealed(sealed)).To(BeTrue())
+			Expect(sealed).NotTo(ContainSubstring("s3cret"))
+			Expect(ring.NeedsSealing(sealed)).To(BeFalse())
+
+			opened, err := ring.Open("CLUSTERPASS", sealed)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(opened).To(Equal("s3cret"))
+		})
+
+		It("passes plain text through", func() {
+			opened, err := ring.Open("CLUSTERPASS", "plain")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(opened).To(Equal("plain"))
+			Expect(ring.NeedsSealing("plain")).To(BeTrue())
+		})
+
+		It("detects tampering", func() {
+			sealed, err := ring.Seal("CLUSTERPASS", "s3cret")
+			Expect(err).NotTo(HaveOccurred())
+			parts := strings.Split(sealed, ":")
+			value, _ := base64.StdEncoding.DecodeString(parts[len(parts)-1])
+			value[len(value)-1] ^= 1
+			parts[len(parts)-1] = base64.StdEncoding.EncodeToString(value)
+
+			_, err = ring.Open("CLUSTERPASS", strings.Join(parts, ":"))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("rejects a value moved to another annotation", func() {
+			user, err := ring.Seal("CLUSTERUSER", "admin")
+			Expect(err).NotTo(HaveOccurred())
+			password, err := ring.Seal("CLUSTERPASS", "s3cret")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = ring.Open("CLUSTERUSER", password)
+			Expect(err).To(HaveOccurred())
+			_, err = ring.Open("CLUSTERPASS", user)
+			Expect(err).To(HaveOccurred())
+
+			// nor does the ciphertext of one value open under the sealed data key of another
+			userParts := strings.Split(user, ":")
+			passwordParts := strings.Split(password, ":")
+			userParts[len(userParts)-1] = passwordParts[len(passwordParts)-1]
+			_, err = ring.Open("CLUSTERUSER", strings.Join(userParts, ":"))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("rejects a value relabelled with another key id", func() {
+			ring, err := NewKeyRing(map[string][]byte{ActiveKeyName: []byte("one"), "one": key(1), "two": key(1)})
+			Expect(err).NotTo(HaveOccurred())
+			sealed, err := ring.Seal("CLUSTERPASS", "s3cret")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = ring.Open("CLUSTERPASS", strings.Replace(sealed, prefix+"one:", prefix+"two:", 1))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("re-seals with the rotated active key", func() {
+			sealed, err := ring.Seal("CLUSTERPASS", "s3cret")
+			Expect(err).NotTo(HaveOccurred())
+
+			rotated, err := NewKeyRing(map[string][]byte{ActiveKeyName: []byte("two"), "one": key(1), "two": key(2)})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rotated.NeedsSealing(sealed)).To(BeTrue())
+
+			resealed, err := rotated.Reseal("CLUSTERPASS", sealed)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resealed).To(HavePrefix(prefix + "two:"))
+			Expect(rotated.NeedsSealing(resealed)).To(BeFalse())
+
+			// once re-sealed the old key can be retired
+			retired, err := NewKeyRing(map[string][]byte{ActiveKeyName: []byte("two"), "two": key(2)})
+			Expect(err).NotTo(HaveOccurred())
+			opened, err := retired.Open("CLUSTERPASS", resealed)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(opened).To(Equal("s3cret"))
+
+			_, err = retired.Open("CLUSTERPASS", sealed)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,151 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package forecast estimates when a steadily growing quantity, such as the disk usage of a
+// broker, reaches its limit from a linear fit of recent samples
+package forecast
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// fewer samples than this give too noisy a trend to warn on
+	minSamples = 5
+)
+
+type sample struct {
+	at    time.Time
+	value float64
+}
+
+// Trends keeps the samples of each quantity for a sliding window
+type Trends struct {
+	sync.Mutex
+	samples map[string][]sample
+	sampled map[string]time.Time
+}
+
+var singleton *Trends
+var once sync.Once
+
+func GetTrends() *Trends {
+	once.Do(func() {
+		singleton = NewTrends()
+	})
+	return singleton
+}
+
+func NewTrends() *Trends {
+	return &Trends{samples: map[string][]sample{}, sampled: map[string]time.Time{}}
+}
+
+// Record adds a sample and drops the ones older than the window
+func (t *Trends) Record(key string, at time.Time, value float64, window time.Duration) {
+	t.Lock()
+	defer t.Unlock()
+
+	kept := []sample{}
+	for _, s := range t.samples[key] {
+		if at.Sub(s.at) <= window {
+			kept = append(kept, s)
+		}
+	}
+	t.samples[key] = append(kept, sample{at: at, value: value})
+}
+
+// TimeToReach estimates how long the quantity takes to reach the limit at its current rate,
+// rising is false when there are too few samples or the quantity isn't growing
+func (t *Trends) TimeToReach(key string, limit float64) (estimate time.Duration, rising bool) {
+	t.Lock()
+	defer t.Unlock()
+
+	samples := t.samples[key]
+	if len(samples) < minSamples {
+		return 0, false
+	}
+
+	// least squares slope, in units per second
+	origin := samples[0].at
+	var sumX, sumY, sumXY, sumXX float64
+	for _, s := range samples {
+		x := s.at.Sub(origin).Seconds()
+		sumX += x
+		sumY += s.value
+		sumXY += x * s.value
+		sumXX += x * x
+	}
+	n := float64(len(samples))
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0, false
+	}
+	slope := (n*sumXY - sumX*sumY) / denominator
+	if slope <= 0 {
+		return 0, false
+	}
+
+	last := samples[len(samples)-1].value
+	if last >= limit {
+		return 0, true
+	}
+	return time.Duration((limit - last) / slope * float64(time.Second)), true
+}
+
+// Due is true when the prefix was last sampled at least the interval before now, it then
+// counts as sampled at now
+func (t *Trends) Due(prefix string, now time.Time, interval time.Duration) bool {
+	t.Lock()
+	defer t.Unlock()
+
+	if last, found := t.sampled[prefix]; found && now.Sub(last) < interval {
+		return false
+	}
+	t.sampled[prefix] = now
+	return true
+}
+
+// Expire drops the keys with the prefix that have no sample within the window before now,
+// like the ones of a pod that is gone
+func (t *Trends) Expire(prefix string, now time.Time, window time.Duration) {
+	t.Lock()
+	defer t.Unlock()
+
+	for key, samples := range t.samples {
+		if strings.HasPrefix(key, prefix) && (len(samples) == 0 || now.Sub(samples[len(samples)-1].at) > window) {
+			delete(t.samples, key)
+		}
+	}
+}
+
+// Forget drops the samples of every key with the prefix
+func (t *Trends) Forget(prefix string) {
+	t.Lock()
+	defer t.Unlock()
+
+	for key := range t.samples {
+		if strings.HasPrefix(key, prefix) {
+			delete(t.samples, key)
+		}
+	}
+	for key := range t.sampled {
+		if strings.HasPrefix(key, prefix) {
+			delete(t.sampled, key)
+		}
+	}
+}
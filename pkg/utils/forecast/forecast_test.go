@@ -0,0 +1,93 @@
+package forecast
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestForecast(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Forecast Suite")
+}
+
+var _ = Describe("Trends", func() {
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	window := time.Hour
+
+	It("needs enough samples", func() {
+		trends := NewTrends()
+		for i := 0; i < minSamples-1; i++ {
+			trends.Record("disk", start.Add(time.Duration(i)*time.Minute), float64(i), window)
+		}
+		_, rising := trends.TimeToReach("disk", 100)
+		Expect(rising).To(BeFalse())
+	})
+
+	It("estimates the time to reach the limit", func() {
+		trends := NewTrends()
+		// one percent a minute, at 50 after the last sample
+		for i := 0; i <= 50; i += 10 {
+			trends.Record("disk", start.Add(time.Duration(i)*time.Minute), float64(i), window)
+		}
+		estimate, rising := trends.TimeToReach("disk", 90)
+		Expect(rising).To(BeTrue())
+		Expect(estimate).To(BeNumerically("~", 40*time.Minute, time.Second))
+	})
+
+	It("ignores a flat or shrinking quantity", func() {
+		trends := NewTrends()
+		for i := 0; i < 10; i++ {
+			trends.Record("disk", start.Add(time.Duration(i)*time.Minute), float64(50-i), window)
+		}
+		_, rising := trends.TimeToReach("disk", 90)
+		Expect(rising).To(BeFalse())
+	})
+
+	It("drops samples outside the window", func() {
+		trends := NewTrends()
+		// a burst an hour ago doesn't count once it leaves the window
+		for i := 0; i < 10; i++ {
+			trends.Record("disk", start.Add(time.Duration(i)*time.Minute), float64(i*5), window)
+		}
+		for i := 0; i < 10; i++ {
+			trends.Record("disk", start.Add(2*time.Hour+time.Duration(i)*time.Minute), 45, window)
+		}
+		_, rising := trends.TimeToReach("disk", 90)
+		Expect(rising).To(BeFalse())
+	})
+
+	It("expires the keys no longer sampled", func() {
+		trends := NewTrends()
+		for i := 0; i < 10; i++ {
+			trends.Record("ns/a/pod-0/disk", start.Add(time.Duration(i)*time.Minute), float64(i), window)
+			trends.Record("ns/a/pod-1/disk", start.Add(2*time.Hour+time.Duration(i)*time.Minute), float64(i), window)
+		}
+		trends.Expire("ns/a/", start.Add(2*time.Hour+10*time.Minute), window)
+		Expect(trends.samples).NotTo(HaveKey("ns/a/pod-0/disk"))
+		Expect(trends.samples).To(HaveKey("ns/a/pod-1/disk"))
+	})
+
+	It("samples once per interval", func() {
+		trends := NewTrends()
+		Expect(trends.Due("ns/a/", start, time.Minute)).To(BeTrue())
+		Expect(trends.Due("ns/a/", start.Add(30*time.Second), time.Minute)).To(BeFalse())
+		Expect(trends.Due("ns/b/", start.Add(30*time.Second), time.Minute)).To(BeTrue())
+		Expect(trends.Due("ns/a/", start.Add(time.Minute), time.Minute)).To(BeTrue())
+	})
+
+	It("forgets by prefix", func() {
+		trends := NewTrends()
+		for i := 0; i < 10; i++ {
+			trends.Record("ns/a/disk", start.Add(time.Duration(i)*time.Minute), float64(i), window)
+			trends.Record("ns/b/disk", start.Add(time.Duration(i)*time.Minute), float64(i), window)
+		}
+		trends.Forget("ns/a/")
+		_, rising := trends.TimeToReach("ns/a/disk", 90)
+		Expect(rising).To(BeFalse())
+		_, rising = trends.TimeToReach("ns/b/disk", 90)
+		Expect(rising).To(BeTrue())
+	})
+})
@@ -4,9 +4,12 @@ import (
 	"bytes"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 )
 
@@ -36,9 +39,23 @@ func (j *JolokiaError) Error() string {
 	return fmt.Sprintf("HTTP STATUS %v. Message: %v", j.HttpCode, j.Message)
 }
 
+// IsUnauthorized reports whether the agent refused the credentials of a request
+func IsUnauthorized(err error) bool {
+	var jolokiaErr *JolokiaError
+	return errors.As(err, &jolokiaErr) && (jolokiaErr.HttpCode == http.StatusUnauthorized || jolokiaErr.HttpCode == http.StatusForbidden)
+}
+
+// IsUnreachable reports whether a request failed to reach the agent, as it does while the broker
+// pod is starting
+func IsUnreachable(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
 type IJolokia interface {
 	Read(path string) (*ResponseData, error)
 	Exec(path, postJsonString string) (*ResponseData, error)
+	ExecBulk(postJsonStrings []string) ([]*ResponseData, error)
 }
 
 type Jolokia struct {
@@ -81,6 +98,8 @@ func (j *Jolokia) getClient() *http.Client {
 	if j.protocol == "https" {
 		return &http.Client{
 			Transport: &http.Transport{
+				// honour the operator proxy settings, as the default transport does for http
+				Proxy: http.ProxyFromEnvironment,
 				TLSClientConfig: &tls.Config{
 					InsecureSkipVerify: true,
 				},
@@ -168,6 +187,13 @@ func (j *Jolokia) Exec(_path string, _postJsonString string) (*ResponseData, err
 		//decoding
 		result, _, err := decodeResponseData(res)
 		if err != nil {
+			if !isResponseSuccessful(res.StatusCode) {
+				// an agent that refuses the credentials answers with a page rather than json
+				return result, &JolokiaError{
+					HttpCode: res.StatusCode,
+					Message:  " Error: " + res.Status,
+				}
+			}
 			return result, err
 		}
 
@@ -184,6 +210,47 @@ func (j *Jolokia) Exec(_path string, _postJsonString string) (*ResponseData, err
 	return jdata, execErr
 }
 
+// ExecBulk sends the requests to the agent in one post, the agent runs them in order and answers
+// each of them. The error is for the post, the response of a request that failed has its error
+func (j *Jolokia) ExecBulk(_postJsonStrings []string) ([]*ResponseData, error) {
+
+	url := j.protocol + "://" + j.user + ":" + j.password + "@" + j.jolokiaURL
+
+	jolokiaClient := j.getClient()
+
+	body := "[" + strings.Join(_postJsonStrings, ",") + "]"
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer([]byte(body)))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "activemq-artemis-management")
+	req.Header.Set("Content-Type", "application/json")
+	res, err := jolokiaClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if !isResponseSuccessful(res.StatusCode) {
+		return nil, &JolokiaError{
+			HttpCode: res.StatusCode,
+			Message:  " Error: " + res.Status,
+		}
+	}
+	rawData := []map[string]interface{}{}
+	if err := json.NewDecoder(res.Body).Decode(&rawData); err != nil {
+		return nil, err
+	}
+	if len(rawData) != len(_postJsonStrings) {
+		return nil, fmt.Errorf("%v responses to %v requests", len(rawData), len(_postJsonStrings))
+	}
+	result := make([]*ResponseData, len(rawData))
+	for i, data := range rawData {
+		result[i] = responseDataFrom(data)
+	}
+	return result, nil
+}
+
 func CheckResponse(resp *http.Response, jdata *ResponseData) error {
 
 	if isResponseSuccessful(resp.StatusCode) {
@@ -209,13 +276,17 @@ func isResponseSuccessful(httpCode int) bool {
 }
 
 func decodeResponseData(resp *http.Response) (*ResponseData, map[string]interface{}, error) {
-	result := &ResponseData{}
 	rawData := make(map[string]interface{})
 	if err := json.NewDecoder(resp.Body).Decode(&rawData); err != nil {
 		return nil, rawData, err
 	}
+	return responseDataFrom(rawData), rawData, nil
 
-	//fill in response data
+}
+
+// responseDataFrom fills in the response data of a decoded response
+func responseDataFrom(rawData map[string]interface{}) *ResponseData {
+	result := &ResponseData{}
 	if v, ok := rawData["error"]; ok {
 		if v != nil {
 			result.Error = fmt.Sprintf("%v", v)
@@ -235,8 +306,12 @@ func decodeResponseData(resp *http.Response) (*ResponseData, map[string]interfac
 		if v != nil {
 			result.Value = fmt.Sprintf("%v", v)
 		}
+		// the read of an mbean pattern returns the attributes of each matching mbean
+		if values, isMap := v.(map[string]interface{}); isMap {
+			if data, err := json.Marshal(values); err == nil {
+				result.Value = string(data)
+			}
+		}
 	}
-
-	return result, rawData, nil
-
+	return result
 }
@@ -83,6 +83,21 @@ func (mr *MockIJolokiaMockRecorder) Exec(path, postJsonString interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exec", reflect.TypeOf((*MockIJolokia)(nil).Exec), path, postJsonString)
 }
 
+// ExecBulk mocks base method.
+func (m *MockIJolokia) ExecBulk(postJsonStrings []string) ([]*ResponseData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecBulk", postJsonStrings)
+	ret0, _ := ret[0].([]*ResponseData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExecBulk indicates an expected call of ExecBulk.
+func (mr *MockIJolokiaMockRecorder) ExecBulk(postJsonStrings interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecBulk", reflect.TypeOf((*MockIJolokia)(nil).ExecBulk), postJsonStrings)
+}
+
 // Read mocks base method.
 func (m *MockIJolokia) Read(path string) (*ResponseData, error) {
 	m.ctrl.T.Helper()
@@ -34,9 +34,17 @@ import (
 )
 
 type JkInfo struct {
-	Artemis *mgmt.Artemis
-	IP      string
-	Ordinal string
+	Artemis  *mgmt.Artemis
+	IP       string
+	Port     string
+	Protocol string
+	Ordinal  string
+	PodName  string
+}
+
+// As is a client of the same broker that logs in with other credentials
+func (jk *JkInfo) As(user string, password string) *mgmt.Artemis {
+	return mgmt.GetArtemis(jk.IP, jk.Port, "amq-broker", user, password, jk.Protocol)
 }
 
 func GetBrokers(resource types.NamespacedName, ssInfos []ss.StatefulSetInfo, client rtclient.Client) []*JkInfo {
@@ -80,11 +88,15 @@ func GetBrokers(resource types.NamespacedName, ssInfos []ss.StatefulSetInfo, cli
 					jolokiaUser, jolokiaPassword, jolokiaProtocol := resolveJolokiaRequestParams(resource.Namespace, client, client.Scheme(), jolokiaSecretName, &containers, podNamespacedName, statefulset, info.Labels)
 
 					reqLogger.Info("New Jolokia with ", "User: ", jolokiaUser, "Protocol: ", jolokiaProtocol, "broker ip", pod.Status.PodIP)
-					artemis := mgmt.GetArtemis(pod.Status.PodIP, "8161", "amq-broker", jolokiaUser, jolokiaPassword, jolokiaProtocol)
+					consolePort := resolveConsolePort(&containers)
+					artemis := mgmt.GetArtemis(pod.Status.PodIP, consolePort, "amq-broker", jolokiaUser, jolokiaPassword, jolokiaProtocol)
 					jkInfo := JkInfo{
-						Artemis: artemis,
-						IP:      pod.Status.PodIP,
-						Ordinal: strconv.Itoa(i),
+						Artemis:  artemis,
+						IP:       pod.Status.PodIP,
+						Port:     consolePort,
+						Protocol: jolokiaProtocol,
+						Ordinal:  strconv.Itoa(i),
+						PodName:  s,
 					}
 					artemisArray = append(artemisArray, &jkInfo)
 				}
@@ -96,6 +108,27 @@ func GetBrokers(resource types.NamespacedName, ssInfos []ss.StatefulSetInfo, cli
 	return artemisArray
 }
 
+// the console port can be configured, it is published as the wconsj container port
+func resolveConsolePort(containers *[]corev1.Container) string {
+	if _, port := consoleContainer(containers); port != nil {
+		return strconv.Itoa(int(port.ContainerPort))
+	}
+	return "8161"
+}
+
+// the broker is the first container, the console sign-on proxy publishes the console port when
+// the console only listens inside the pod
+func consoleContainer(containers *[]corev1.Container) (int, *corev1.ContainerPort) {
+	for i := range *containers {
+		for j, port := range (*containers)[i].Ports {
+			if port.Name == "wconsj" {
+				return i, &(*containers)[i].Ports[j]
+			}
+		}
+	}
+	return -1, nil
+}
+
 func resolveJolokiaRequestParams(namespace string,
 	client rtclient.Client,
 	scheme *runtime.Scheme,
@@ -121,8 +154,9 @@ func resolveJolokiaRequestParams(namespace string,
 			jolokiaPassword = *jolokiaPasswordFromSecret
 		}
 	}
-	if len(*containers) == 1 {
+	if len(*containers) > 0 {
 		envVars := (*containers)[0].Env
+		var managementUser, managementPassword string
 		for _, oneVar := range envVars {
 			if !userDefined && oneVar.Name == "AMQ_USER" {
 				jolokiaUser = getEnvVarValue(&oneVar, &podNamespacedName, statefulset, client, labels)
@@ -130,16 +164,25 @@ func resolveJolokiaRequestParams(namespace string,
 			if !userDefined && oneVar.Name == "AMQ_PASSWORD" {
 				jolokiaPassword = getEnvVarValue(&oneVar, &podNamespacedName, statefulset, client, labels)
 			}
+			if !userDefined && oneVar.Name == "AMQ_JOLOKIA_USER" {
+				managementUser = getEnvVarValue(&oneVar, &podNamespacedName, statefulset, client, labels)
+			}
+			if !userDefined && oneVar.Name == "AMQ_JOLOKIA_PASSWORD" {
+				managementPassword = getEnvVarValue(&oneVar, &podNamespacedName, statefulset, client, labels)
+			}
 			if oneVar.Name == "AMQ_CONSOLE_ARGS" {
 				jolokiaProtocol = getEnvVarValue(&oneVar, &podNamespacedName, statefulset, client, labels)
 			}
-			if jolokiaUser != "" && jolokiaPassword != "" && jolokiaProtocol != "" {
-				break
-			}
+		}
+		// a dedicated management user takes precedence over the admin user
+		if managementUser != "" {
+			jolokiaUser = managementUser
+			jolokiaPassword = managementPassword
 		}
 	}
 
-	if jolokiaProtocol == "" {
+	// the sign-on proxy serves plain http whether or not the console has SSL enabled
+	if index, _ := consoleContainer(containers); jolokiaProtocol == "" || index > 0 {
 		jolokiaProtocol = "http"
 	} else {
 		jolokiaProtocol = "https"
@@ -182,7 +225,7 @@ func getEnvVarValueFromSecret(envName string, varSource *corev1.EnvVarSource, na
 			reqLogger.Info("Secret IsNotFound.", "Secret Name", secretName, "Key", secretKey)
 		}
 	} else {
-		elem, ok := theSecret.Data[envName]
+		elem, ok := theSecret.Data[secretKey]
 		if ok {
 			result = string(elem)
 		}
@@ -128,6 +128,55 @@ var _ = Describe("JolokiaClient", func() {
 				Expect(infos[0].IP).To(Equal("1.2.3.4"))
 				Expect(infos[0].Artemis).NotTo(BeNil())
 			})
+			It("should reach the console through the sign-on proxy", func() {
+				replicas := int32(1)
+				objs := []client.Object{
+					&appsv1.StatefulSet{
+						ObjectMeta: v1.ObjectMeta{
+							Name:      "broker",
+							Namespace: "some-ns",
+						},
+						Spec: appsv1.StatefulSetSpec{
+							Replicas: &replicas,
+						},
+					},
+					&corev1.Pod{
+						ObjectMeta: v1.ObjectMeta{
+							Name:      "broker-0",
+							Namespace: "some-ns",
+						},
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{
+								{
+									Name: "broker-container",
+									Env: []corev1.EnvVar{
+										{Name: "AMQ_USER", Value: "admin"},
+										{Name: "AMQ_PASSWORD", Value: "secret"},
+										{Name: "AMQ_CONSOLE_ARGS", Value: "--ssl-key /etc/broker.ks"},
+									},
+								},
+								{
+									Name:  "broker-console-sso",
+									Ports: []corev1.ContainerPort{{Name: "wconsj", ContainerPort: 4180}},
+								},
+							},
+						},
+						Status: corev1.PodStatus{
+							PodIP: "1.2.3.4",
+						},
+					},
+				}
+				client := fake.NewClientBuilder().WithObjects(objs...).Build()
+				brokerRef := types.NamespacedName{
+					Name:      "broker",
+					Namespace: "some-ns",
+				}
+				ssInfos := ss.GetDeployedStatefulSetNames(client, []types.NamespacedName{brokerRef})
+				infos := jolokia_client.GetBrokers(brokerRef, ssInfos, client)
+				Expect(infos).Should(HaveLen(1))
+				Expect(infos[0].Port).To(Equal("4180"))
+				Expect(infos[0].Protocol).To(Equal("http"))
+			})
 		})
 	})
 
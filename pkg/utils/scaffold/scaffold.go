@@ -0,0 +1,283 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package scaffold writes a starter ActiveMQArtemis CR from flags or answers to prompts, checked
+// against the operator policies and optionally against the CRD schema of a live cluster
+package scaffold
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	"sigs.k8s.io/yaml"
+)
+
+// Command is the first argument of the operator binary that runs the scaffolding rather than the manager
+const Command = "scaffold"
+
+type Options struct {
+	Name           string
+	Namespace      string
+	Size           int
+	Persistence    bool
+	Ephemeral      bool
+	Acceptors      acceptorList
+	Expose         bool
+	ExposeMode     string
+	ConsoleExpose  bool
+	Interactive    bool
+	ValidateServer bool
+}
+
+// acceptorList collects repeated --acceptor flags, each name[:port[:protocols]]
+type acceptorList []string
+
+func (list *acceptorList) String() string {
+	return strings.Join(*list, " ")
+}
+
+func (list *acceptorList) Set(value string) error {
+	*list = append(*list, value)
+	return nil
+}
+
+func (options *Options) BindFlags(flags *flag.FlagSet) {
+	flags.StringVar(&options.Name, "name", "broker", "The name of the custom resource.")
+	flags.StringVar(&options.Namespace, "namespace", "", "The namespace of the custom resource.")
+	flags.IntVar(&options.Size, "size", 1, "The number of broker pods.")
+	flags.BoolVar(&options.Persistence, "persistence", false, "Store the journal on a persistent volume claim.")
+	flags.BoolVar(&options.Ephemeral, "ephemeral", false, "Run the brokers without persistence for throughput.")
+	flags.Var(&options.Acceptors, "acceptor", "An acceptor as name[:port[:protocols]], for example amqp:5672:AMQP, can be repeated.")
+	flags.BoolVar(&options.Expose, "expose", false, "Expose the acceptors outside the cluster.")
+	flags.StringVar(&options.ExposeMode, "expose-mode", "", "How the acceptors are exposed, route, ingress, loadBalancer, nodePort or sni.")
+	flags.BoolVar(&options.ConsoleExpose, "console-expose", false, "Expose the web console outside the cluster.")
+	flags.BoolVar(&options.Interactive, "interactive", false, "Prompt for the options rather than reading them from flags.")
+	flags.BoolVar(&options.ValidateServer, "validate-server", false, "Check the custom resource against the CRD schema of the cluster in the current kube config with a dry run create.")
+}
+
+// Run writes the yaml of the custom resource to out, checkPolicies is the validation the operator applies
+// on reconcile that needs nothing from the cluster
+func Run(args []string, in io.Reader, out io.Writer, checkPolicies func(*brokerv1beta1.ActiveMQArtemis) *metav1.Condition) error {
+	options := &Options{}
+	flags := flag.NewFlagSet(Command, flag.ContinueOnError)
+	flags.SetOutput(out)
+	options.BindFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if options.Interactive {
+		if err := Prompt(in, out, options); err != nil {
+			return err
+		}
+	}
+
+	cr, err := NewActiveMQArtemis(options)
+	if err != nil {
+		return err
+	}
+	if condition := checkPolicies(cr); condition != nil && condition.Status == metav1.ConditionFalse {
+		return fmt.Errorf("%v: %v", condition.Reason, condition.Message)
+	}
+	if options.ValidateServer {
+		if err := validateOnServer(cr); err != nil {
+			return err
+		}
+	}
+
+	document, err := ToYaml(cr)
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(document)
+	return err
+}
+
+// Prompt asks for each option, showing the value from the flags as the default
+func Prompt(in io.Reader, out io.Writer, options *Options) error {
+	scanner := bufio.NewScanner(in)
+	ask := func(question string, current string) string {
+		fmt.Fprintf(out, "%v [%v]: ", question, current)
+		if !scanner.Scan() {
+			return current
+		}
+		if answer := strings.TrimSpace(scanner.Text()); answer != "" {
+			return answer
+		}
+		return current
+	}
+	askBool := func(question string, current bool) (bool, error) {
+		answer := ask(question+" (true/false)", strconv.FormatBool(current))
+		value, err := strconv.ParseBool(answer)
+		if err != nil {
+			return false, fmt.Errorf("%v: %q is not true or false", question, answer)
+		}
+		return value, nil
+	}
+
+	var err error
+	options.Name = ask("Name", options.Name)
+	options.Namespace = ask("Namespace", options.Namespace)
+	size := ask("Number of brokers", strconv.Itoa(options.Size))
+	if options.Size, err = strconv.Atoi(size); err != nil {
+		return fmt.Errorf("number of brokers: %q is not a number", size)
+	}
+	if options.Persistence, err = askBool("Persistent volumes", options.Persistence); err != nil {
+		return err
+	}
+	if !options.Persistence {
+		if options.Ephemeral, err = askBool("Ephemeral profile", options.Ephemeral); err != nil {
+			return err
+		}
+	}
+	acceptors := ask("Acceptors, space separated name[:port[:protocols]]", options.Acceptors.String())
+	options.Acceptors = strings.Fields(acceptors)
+	if len(options.Acceptors) > 0 {
+		if options.Expose, err = askBool("Expose the acceptors", options.Expose); err != nil {
+			return err
+		}
+		if options.Expose {
+			options.ExposeMode = ask("Expose mode, route, ingress, loadBalancer, nodePort or sni, empty for the platform default", options.ExposeMode)
+		}
+	}
+	if options.ConsoleExpose, err = askBool("Expose the console", options.ConsoleExpose); err != nil {
+		return err
+	}
+	return nil
+}
+
+func NewActiveMQArtemis(options *Options) (*brokerv1beta1.ActiveMQArtemis, error) {
+	if options.Name == "" {
+		return nil, errors.New("a name is required")
+	}
+	if options.Size < 1 {
+		return nil, fmt.Errorf("size %d must be at least 1", options.Size)
+	}
+	if options.Persistence && options.Ephemeral {
+		return nil, errors.New("persistence and ephemeral can't be combined")
+	}
+
+	size := int32(options.Size)
+	cr := &brokerv1beta1.ActiveMQArtemis{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: brokerv1beta1.GroupVersion.String(),
+			Kind:       "ActiveMQArtemis",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      options.Name,
+			Namespace: options.Namespace,
+		},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			DeploymentPlan: brokerv1beta1.DeploymentPlanType{
+				Size:               &size,
+				PersistenceEnabled: options.Persistence,
+			},
+			Console: brokerv1beta1.ConsoleType{
+				Expose: options.ConsoleExpose,
+			},
+		},
+	}
+	if options.Ephemeral {
+		cr.Spec.DeploymentPlan.Ephemeral = &brokerv1beta1.EphemeralType{}
+	}
+
+	for _, value := range options.Acceptors {
+		parts := strings.SplitN(value, ":", 3)
+		acceptor := brokerv1beta1.AcceptorType{Name: parts[0], Expose: options.Expose}
+		if acceptor.Name == "" {
+			return nil, fmt.Errorf("acceptor %q has no name", value)
+		}
+		if len(parts) > 1 && parts[1] != "" {
+			port, err := strconv.Atoi(parts[1])
+			if err != nil || port < 1 || port > 65535 {
+				return nil, fmt.Errorf("acceptor %q has an invalid port", value)
+			}
+			acceptor.Port = int32(port)
+		}
+		if len(parts) > 2 {
+			acceptor.Protocols = parts[2]
+		}
+		if options.Expose && options.ExposeMode != "" {
+			mode := brokerv1beta1.ExposeMode(options.ExposeMode)
+			acceptor.ExposeMode = &mode
+		}
+		cr.Spec.Acceptors = append(cr.Spec.Acceptors, acceptor)
+	}
+	return cr, nil
+}
+
+// ToYaml leaves out the status, the fields the api server sets and the empty structs
+func ToYaml(cr *brokerv1beta1.ActiveMQArtemis) ([]byte, error) {
+	document, err := yaml.Marshal(cr)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]interface{}{}
+	if err := yaml.Unmarshal(document, &fields); err != nil {
+		return nil, err
+	}
+	delete(fields, "status")
+	if metadata, ok := fields["metadata"].(map[string]interface{}); ok {
+		delete(metadata, "creationTimestamp")
+	}
+	pruneEmpty(fields)
+	return yaml.Marshal(fields)
+}
+
+// the empty structs that turn a feature on, the rest are value fields without omitempty
+var keepEmpty = map[string]bool{
+	"ephemeral": true,
+}
+
+func pruneEmpty(fields map[string]interface{}) {
+	for key, value := range fields {
+		if nested, ok := value.(map[string]interface{}); ok {
+			pruneEmpty(nested)
+			if len(nested) == 0 && !keepEmpty[key] {
+				delete(fields, key)
+			}
+		}
+	}
+}
+
+func validateOnServer(cr *brokerv1beta1.ActiveMQArtemis) error {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return fmt.Errorf("unable to read the kube config: %v", err)
+	}
+	scheme := runtime.NewScheme()
+	if err := brokerv1beta1.AddToScheme(scheme); err != nil {
+		return err
+	}
+	kubeClient, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		return err
+	}
+	if err := kubeClient.Create(context.TODO(), cr.DeepCopy(), client.DryRunAll); err != nil {
+		return fmt.Errorf("rejected by the cluster: %v", err)
+	}
+	return nil
+}
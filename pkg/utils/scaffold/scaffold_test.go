@@ -0,0 +1,107 @@
+package scaffold
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	brokerv1beta1 "github.com/artemiscloud/activemq-artemis-operator/api/v1beta1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+func TestScaffold(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Scaffold Suite")
+}
+
+var noPolicies = func(*brokerv1beta1.ActiveMQArtemis) *metav1.Condition { return nil }
+
+func scaffoldCR(args []string, input string) (*brokerv1beta1.ActiveMQArtemis, string, error) {
+	out := &bytes.Buffer{}
+	err := Run(args, strings.NewReader(input), out, noPolicies)
+	if err != nil {
+		return nil, out.String(), err
+	}
+	cr := &brokerv1beta1.ActiveMQArtemis{}
+	text := out.String()
+	if index := strings.Index(text, "apiVersion:"); index > 0 {
+		text = text[index:]
+	}
+	Expect(yaml.Unmarshal([]byte(text), cr)).To(Succeed())
+	return cr, out.String(), nil
+}
+
+var _ = Describe("Scaffold", func() {
+
+	It("writes a CR from flags", func() {
+		cr, document, err := scaffoldCR([]string{"--name", "ex", "--namespace", "brokers", "--size", "2", "--persistence",
+			"--acceptor", "amqp:5672:AMQP", "--acceptor", "core", "--expose", "--expose-mode", "ingress"}, "")
+		Expect(err).To(BeNil())
+
+		Expect(cr.APIVersion).To(Equal("broker.amq.io/v1beta1"))
+		Expect(cr.Kind).To(Equal("ActiveMQArtemis"))
+		Expect(cr.Name).To(Equal("ex"))
+		Expect(cr.Namespace).To(Equal("brokers"))
+		Expect(*cr.Spec.DeploymentPlan.Size).To(Equal(int32(2)))
+		Expect(cr.Spec.DeploymentPlan.PersistenceEnabled).To(BeTrue())
+		Expect(cr.Spec.Acceptors).To(HaveLen(2))
+		Expect(cr.Spec.Acceptors[0].Port).To(Equal(int32(5672)))
+		Expect(cr.Spec.Acceptors[0].Protocols).To(Equal("AMQP"))
+		Expect(cr.Spec.Acceptors[1].Name).To(Equal("core"))
+		Expect(cr.Spec.Acceptors[1].Expose).To(BeTrue())
+		Expect(*cr.Spec.Acceptors[1].ExposeMode).To(Equal(brokerv1beta1.ExposeModeIngress))
+
+		Expect(document).NotTo(ContainSubstring("status"))
+		Expect(document).NotTo(ContainSubstring("creationTimestamp"))
+		Expect(document).NotTo(ContainSubstring("affinity"))
+	})
+
+	It("rejects invalid flags", func() {
+		_, _, err := scaffoldCR([]string{"--size", "0"}, "")
+		Expect(err).NotTo(BeNil())
+
+		_, _, err = scaffoldCR([]string{"--persistence", "--ephemeral"}, "")
+		Expect(err).NotTo(BeNil())
+
+		_, _, err = scaffoldCR([]string{"--acceptor", "amqp:http"}, "")
+		Expect(err).NotTo(BeNil())
+
+		_, _, err = scaffoldCR([]string{"--acceptor", ":5672"}, "")
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("reports the policy the CR breaks", func() {
+		out := &bytes.Buffer{}
+		err := Run([]string{}, strings.NewReader(""), out, func(*brokerv1beta1.ActiveMQArtemis) *metav1.Condition {
+			return &metav1.Condition{Status: metav1.ConditionFalse, Reason: "InvalidExposure", Message: "bad"}
+		})
+		Expect(err).To(MatchError("InvalidExposure: bad"))
+		Expect(out.String()).To(BeEmpty())
+	})
+
+	It("prompts for the options", func() {
+		input := strings.Join([]string{"ex", "", "3", "false", "true", "amqp:5672 mqtt:1883:MQTT", "true", "loadBalancer", "true"}, "\n")
+		cr, document, err := scaffoldCR([]string{"--interactive"}, input)
+		Expect(err).To(BeNil())
+
+		Expect(document).To(ContainSubstring("Name [broker]: "))
+		Expect(cr.Name).To(Equal("ex"))
+		Expect(*cr.Spec.DeploymentPlan.Size).To(Equal(int32(3)))
+		Expect(cr.Spec.DeploymentPlan.PersistenceEnabled).To(BeFalse())
+		Expect(cr.Spec.DeploymentPlan.Ephemeral).NotTo(BeNil())
+		Expect(cr.Spec.Acceptors).To(HaveLen(2))
+		Expect(cr.Spec.Acceptors[1].Protocols).To(Equal("MQTT"))
+		Expect(*cr.Spec.Acceptors[1].ExposeMode).To(Equal(brokerv1beta1.ExposeModeLoadBalancer))
+		Expect(cr.Spec.Console.Expose).To(BeTrue())
+	})
+
+	It("keeps the flag values when the answers are empty", func() {
+		cr, _, err := scaffoldCR([]string{"--interactive", "--name", "ex", "--size", "2"}, "")
+		Expect(err).To(BeNil())
+		Expect(cr.Name).To(Equal("ex"))
+		Expect(*cr.Spec.DeploymentPlan.Size).To(Equal(int32(2)))
+	})
+})
@@ -1,6 +1,7 @@
 package version
 
 import (
+	"regexp"
 	"strings"
 
 	"github.com/blang/semver/v4"
@@ -83,3 +84,30 @@ func SupportedActiveMQArtemisSemanticVersions() []semver.Version {
 
 	return supportedActiveMQArtemisSemanticVersions
 }
+
+// a broker version is released as image tag artemis.<version>, optionally with a build suffix
+var imageTagVersionRegex = regexp.MustCompile(`:(?:artemis\.)?(\d+\.\d+\.\d+)(?:[-.][\w.-]*)?$`)
+
+// ImageVersion returns the broker version of an image from its tag, an image referenced by digest has no version
+func ImageVersion(image string) (semver.Version, bool) {
+	if strings.Contains(image, "@") {
+		return semver.Version{}, false
+	}
+	match := imageTagVersionRegex.FindStringSubmatch(image)
+	if match == nil {
+		return semver.Version{}, false
+	}
+	imageVersion, err := semver.Parse(match[1])
+	return imageVersion, err == nil
+}
+
+// IsCompatibleVersion is true when a supported version has the same major and minor version, the operator
+// supports patch releases of a supported minor version
+func IsCompatibleVersion(brokerVersion semver.Version) bool {
+	for _, supported := range SupportedActiveMQArtemisSemanticVersions() {
+		if supported.Major == brokerVersion.Major && supported.Minor == brokerVersion.Minor {
+			return true
+		}
+	}
+	return false
+}